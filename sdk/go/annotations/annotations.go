@@ -0,0 +1,116 @@
+// Package annotations posts an annotation to Grafana, or to a generic
+// annotation webhook, whenever a Matrix Flag feature flag changes, so that
+// dashboards show exactly when a toggle happened.
+package annotations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Annotation represents a point-in-time event to display on graphs
+type Annotation struct {
+	Time time.Time
+	Tags []string
+	Text string
+}
+
+// Sink receives annotations
+type Sink interface {
+	Annotate(ctx context.Context, a Annotation) error
+}
+
+// GrafanaSink posts annotations to the Grafana HTTP API
+type GrafanaSink struct {
+	// URL is the base URL of the Grafana instance
+	URL string
+	// Token is a Grafana service account token
+	Token string
+	// DashboardUID optionally scopes annotations to a dashboard; they are global otherwise
+	DashboardUID string
+	// HTTPClient is used to send requests, http.DefaultClient by default
+	HTTPClient *http.Client
+}
+
+// grafanaAnnotation is the request body of the Grafana annotations API
+type grafanaAnnotation struct {
+	DashboardUID string   `json:"dashboardUID,omitempty"`
+	Time         int64    `json:"time"`
+	Tags         []string `json:"tags"`
+	Text         string   `json:"text"`
+}
+
+// Annotate implements Sink
+func (s *GrafanaSink) Annotate(ctx context.Context, a Annotation) error {
+	headers := map[string]string{}
+	if s.Token != "" {
+		headers["Authorization"] = "Bearer " + s.Token
+	}
+	return postJSON(ctx, s.HTTPClient, strings.TrimRight(s.URL, "/")+"/api/annotations", headers, grafanaAnnotation{
+		DashboardUID: s.DashboardUID,
+		Time:         a.Time.UnixMilli(),
+		Tags:         a.Tags,
+		Text:         a.Text,
+	})
+}
+
+// WebhookSink posts annotations as JSON to an arbitrary URL
+type WebhookSink struct {
+	URL        string
+	Headers    map[string]string
+	HTTPClient *http.Client
+}
+
+// webhookAnnotation is the request body sent by WebhookSink
+type webhookAnnotation struct {
+	Time time.Time `json:"time"`
+	Tags []string  `json:"tags"`
+	Text string    `json:"text"`
+}
+
+// Annotate implements Sink
+func (s *WebhookSink) Annotate(ctx context.Context, a Annotation) error {
+	return postJSON(ctx, s.HTTPClient, s.URL, s.Headers, webhookAnnotation{
+		Time: a.Time,
+		Tags: a.Tags,
+		Text: a.Text,
+	})
+}
+
+// postJSON posts body as JSON and checks the response status
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body any) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal annotation: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post annotation: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("annotation rejected (status %d): %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package annotations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/matrixflag/sdk/matrixflagtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink keeps the annotations it receives
+type recordingSink struct {
+	annotations []Annotation
+	err         error
+}
+
+func (s *recordingSink) Annotate(_ context.Context, a Annotation) error {
+	s.annotations = append(s.annotations, a)
+	return s.err
+}
+
+func TestAnnotatorPoll(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	td := matrixflagtest.NewTestDataSource()
+	td.Set("checkout", false)
+	td.Set("search", true)
+	td.SetFlag(matrixflag.FeatureFlag{Name: "banner", UpdatedAt: now})
+	client := td.Client(matrixflag.WithClock(matrixflagtest.NewFakeClock(now)))
+	sink := &recordingSink{}
+	annotator := New(client, sink, Options{Tags: []string{"team:web"}})
+	ctx := context.Background()
+
+	require.NoError(t, annotator.Poll(ctx))
+	assert.Empty(t, sink.annotations, "the first poll records the current state")
+
+	td.Set("checkout", true)
+	td.Delete("search")
+	td.SetFlag(matrixflag.FeatureFlag{Name: "banner", UpdatedAt: now.Add(time.Minute)})
+	td.Set("pricing", true)
+	require.NoError(t, annotator.Poll(ctx))
+
+	var texts []string
+	for _, a := range sink.annotations {
+		texts = append(texts, a.Text)
+		assert.Equal(t, now, a.Time)
+	}
+	assert.Equal(t, []string{
+		"Flag banner updated in test",
+		"Flag checkout enabled in test",
+		"Flag pricing created in test",
+		"Flag search deleted in test",
+	}, texts)
+	assert.Equal(t, []string{"matrixflag", "flag:checkout", "environment:test", "change:enabled", "team:web"}, sink.annotations[1].Tags)
+
+	sink.annotations = nil
+	require.NoError(t, annotator.Poll(ctx))
+	assert.Empty(t, sink.annotations, "unchanged flags are not annotated")
+}
+
+func TestAnnotatorPollSinkError(t *testing.T) {
+	td := matrixflagtest.NewTestDataSource()
+	td.Set("checkout", false)
+	sink := &recordingSink{err: errors.New("grafana unavailable")}
+	annotator := New(td.Client(), sink, Options{Environments: []string{matrixflagtest.TestEnvironment}})
+	ctx := context.Background()
+
+	require.NoError(t, annotator.Poll(ctx))
+	td.Set("checkout", true)
+	assert.EqualError(t, annotator.Poll(ctx), "grafana unavailable")
+}
+
+func TestGrafanaSink(t *testing.T) {
+	var auth string
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/annotations", r.URL.Path)
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&body)
+	}))
+	defer srv.Close()
+
+	sink := &GrafanaSink{URL: srv.URL + "/", Token: "glsa_token", DashboardUID: "checkout"}
+	at := time.UnixMilli(1714564800000)
+	require.NoError(t, sink.Annotate(context.Background(), Annotation{Time: at, Tags: []string{"matrixflag"}, Text: "Flag checkout enabled in production"}))
+	assert.Equal(t, "Bearer glsa_token", auth)
+	assert.Equal(t, map[string]any{
+		"dashboardUID": "checkout",
+		"time":         float64(1714564800000),
+		"tags":         []any{"matrixflag"},
+		"text":         "Flag checkout enabled in production",
+	}, body)
+}
+
+func TestWebhookSinkRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("X-Token"))
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	sink := &WebhookSink{URL: srv.URL, Headers: map[string]string{"X-Token": "secret"}}
+	err := sink.Annotate(context.Background(), Annotation{Time: time.Now(), Text: "Flag checkout enabled"})
+	assert.ErrorContains(t, err, "annotation rejected (status 403): forbidden")
+}
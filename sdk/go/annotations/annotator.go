@@ -0,0 +1,129 @@
+package annotations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+// DefaultInterval is the default interval between flag polls
+const DefaultInterval = 15 * time.Second
+
+// Options configures an Annotator
+type Options struct {
+	// Environments limits the watched flags to the given environments; all flags are watched when empty
+	Environments []string
+	// Interval is the time between flag polls
+	Interval time.Duration
+	// Tags are added to every annotation
+	Tags []string
+	// OnError is called when polling or posting an annotation fails
+	OnError func(error)
+}
+
+// Annotator polls flags and posts an annotation for every change it observes
+type Annotator struct {
+	client *matrixflag.Client
+	sink   Sink
+	opts   Options
+
+	known  []matrixflag.FeatureFlag
+	polled bool
+}
+
+// New creates a new Annotator
+func New(client *matrixflag.Client, sink Sink, opts Options) *Annotator {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	return &Annotator{
+		client: client,
+		sink:   sink,
+		opts:   opts,
+	}
+}
+
+// Run polls flags every interval until ctx is canceled.
+// The first poll records the current state without posting annotations.
+func (a *Annotator) Run(ctx context.Context) {
+	for {
+		if err := a.Poll(ctx); err != nil && a.opts.OnError != nil {
+			a.opts.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-a.client.Clock().After(a.opts.Interval):
+		}
+	}
+}
+
+// Poll fetches the flags once and annotates any change since the previous poll
+func (a *Annotator) Poll(ctx context.Context) error {
+	flags, err := a.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !a.polled {
+		a.known, a.polled = flags, true
+		return nil
+	}
+
+	var annotations []Annotation
+	for _, change := range matrixflag.DiffFlags(a.known, flags, a.client.Clock().Now()) {
+		flag := change.Flag()
+		switch {
+		case change.Type == matrixflag.FlagCreated:
+			annotations = append(annotations, a.annotation(change.Time, flag, "created", "Flag %s created in %s"))
+		case change.Type == matrixflag.FlagDeleted:
+			annotations = append(annotations, a.annotation(change.Time, flag, "deleted", "Flag %s deleted in %s"))
+		case change.Toggled() && flag.IsActive:
+			annotations = append(annotations, a.annotation(change.Time, flag, "enabled", "Flag %s enabled in %s"))
+		case change.Toggled():
+			annotations = append(annotations, a.annotation(change.Time, flag, "disabled", "Flag %s disabled in %s"))
+		case !change.Old.UpdatedAt.Equal(flag.UpdatedAt):
+			annotations = append(annotations, a.annotation(change.Time, flag, "updated", "Flag %s updated in %s"))
+		}
+	}
+	a.known = flags
+
+	sort.Slice(annotations, func(i, j int) bool {
+		return annotations[i].Text < annotations[j].Text
+	})
+	for _, annotation := range annotations {
+		if err := a.sink.Annotate(ctx, annotation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetch lists the watched flags
+func (a *Annotator) fetch(ctx context.Context) ([]matrixflag.FeatureFlag, error) {
+	if len(a.opts.Environments) == 0 {
+		return a.client.ListFeatureFlags(ctx, matrixflag.FlagFilter{})
+	}
+	var flags []matrixflag.FeatureFlag
+	for _, env := range a.opts.Environments {
+		page, err := a.client.ListFeatureFlags(ctx, matrixflag.FlagFilter{Environment: env})
+		if err != nil {
+			return nil, err
+		}
+		flags = append(flags, page...)
+	}
+	return flags, nil
+}
+
+// annotation builds the annotation for a flag change
+func (a *Annotator) annotation(t time.Time, flag matrixflag.FeatureFlag, change, format string) Annotation {
+	tags := append([]string{"matrixflag", "flag:" + flag.Name, "environment:" + flag.Environment, "change:" + change}, a.opts.Tags...)
+	return Annotation{
+		Time: t,
+		Tags: tags,
+		Text: fmt.Sprintf(format, flag.Name, flag.Environment),
+	}
+}
@@ -0,0 +1,191 @@
+package matrixflag
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// FlagEvaluator evaluates flags on the server, see Client.Evaluate. Code that only
+// evaluates flags can depend on it rather than on API.
+type FlagEvaluator interface {
+	Evaluate(ctx context.Context, flagKey string, evalCtx EvaluationContext, defaultValue any, reqOpts ...RequestOption) EvaluationDetail[any]
+	EvaluateBool(ctx context.Context, flagKey string, evalCtx EvaluationContext, defaultValue bool, reqOpts ...RequestOption) EvaluationDetail[bool]
+	EvaluateString(ctx context.Context, flagKey string, evalCtx EvaluationContext, defaultValue string, reqOpts ...RequestOption) EvaluationDetail[string]
+	EvaluateInt(ctx context.Context, flagKey string, evalCtx EvaluationContext, defaultValue int, reqOpts ...RequestOption) EvaluationDetail[int]
+	EvaluateFloat(ctx context.Context, flagKey string, evalCtx EvaluationContext, defaultValue float64, reqOpts ...RequestOption) EvaluationDetail[float64]
+	EvaluateJSON(ctx context.Context, flagKey string, evalCtx EvaluationContext, defaultValue any, reqOpts ...RequestOption) EvaluationDetail[any]
+	GetVariation(ctx context.Context, flagKey string, evalCtx EvaluationContext, reqOpts ...RequestOption) (*Variation, error)
+	GetJSONVariation(ctx context.Context, flagKey string, evalCtx EvaluationContext, target any, reqOpts ...RequestOption) error
+	Track(ctx context.Context, eventName string, evalCtx EvaluationContext, value float64, properties map[string]any, reqOpts ...RequestOption) error
+}
+
+// FlagManager manages feature flags, see Client.CreateFeatureFlag
+type FlagManager interface {
+	ListFeatureFlags(ctx context.Context, filter FlagFilter, reqOpts ...RequestOption) ([]FeatureFlag, error)
+	IterateFeatureFlags(opts ListOptions) *FlagIterator
+	ListAll(ctx context.Context, opts ListOptions, reqOpts ...RequestOption) ([]FeatureFlag, error)
+	GetFeatureFlag(ctx context.Context, id int, reqOpts ...RequestOption) (*FeatureFlag, error)
+	GetFeatureFlagByName(ctx context.Context, name, environment string, reqOpts ...RequestOption) (*FeatureFlag, error)
+	CreateFeatureFlag(ctx context.Context, flag FeatureFlagCreate, reqOpts ...RequestOption) (*FeatureFlag, error)
+	UpdateFeatureFlag(ctx context.Context, id int, flag FeatureFlagUpdate, reqOpts ...RequestOption) (*FeatureFlag, error)
+	PatchFeatureFlag(ctx context.Context, id int, update FeatureFlagUpdate, mask FieldMask, reqOpts ...RequestOption) (*FeatureFlag, error)
+	DeleteFeatureFlag(ctx context.Context, id int, reqOpts ...RequestOption) (*FeatureFlag, error)
+	ToggleFeatureFlag(ctx context.Context, id int, reqOpts ...RequestOption) (*FeatureFlag, error)
+	BulkCreateFlags(ctx context.Context, flags []FeatureFlagCreate, reqOpts ...RequestOption) ([]FeatureFlag, error)
+	BulkUpdateFlags(ctx context.Context, updates []BulkUpdate, reqOpts ...RequestOption) ([]FeatureFlag, error)
+	BulkToggle(ctx context.Context, ids []int, active bool, reqOpts ...RequestOption) ([]FeatureFlag, error)
+	AddTag(ctx context.Context, id int, tag string, reqOpts ...RequestOption) (*FeatureFlag, error)
+	RemoveTag(ctx context.Context, id int, tag string, reqOpts ...RequestOption) (*FeatureFlag, error)
+	ArchiveFeatureFlag(ctx context.Context, id int, reqOpts ...RequestOption) (*FeatureFlag, error)
+	UnarchiveFeatureFlag(ctx context.Context, id int, reqOpts ...RequestOption) (*FeatureFlag, error)
+}
+
+// API is the interface of Client, so code can depend on it and tests can inject
+// a fake, such as the gomock double of the matrixflagmock module. Methods added
+// to Client in later versions are added to API too, so fakes written by hand
+// should embed API to keep compiling.
+type API interface {
+	FlagEvaluator
+	FlagManager
+
+	// Rulesets, streams and watches
+	FetchRuleset(ctx context.Context, environment string, reqOpts ...RequestOption) (*Ruleset, error)
+	StreamFlags(ctx context.Context, reqOpts ...RequestOption) (<-chan FlagEvent, error)
+	Watch(ctx context.Context, opts WatchOptions, reqOpts ...RequestOption) (<-chan FlagChange, error)
+	Subscribe(key string, fn FlagChangeFunc) *Subscription
+	OnFlagChange(key string, fn FlagChangeFunc) *Subscription
+	OnAnyChange(fn FlagChangeFunc) *Subscription
+
+	// Events and debugging
+	SendEvents(ctx context.Context, events []Event, reqOpts ...RequestOption) error
+	FlushEvents(ctx context.Context, reqOpts ...RequestOption) error
+	RunEvents(ctx context.Context, reqOpts ...RequestOption)
+	EnableDebug(ctx context.Context, flagID int, duration time.Duration, reqOpts ...RequestOption) (*FeatureFlag, error)
+	DisableDebug(ctx context.Context, flagID int, reqOpts ...RequestOption) (*FeatureFlag, error)
+	SendDebugEvents(ctx context.Context, events []DebugEvent, reqOpts ...RequestOption) error
+	ListDebugEvents(ctx context.Context, flagID int, since time.Time, reqOpts ...RequestOption) ([]DebugEvent, error)
+	DebugEvaluate(ctx context.Context, flagKey string, evalCtx EvaluationContext, reqOpts ...RequestOption) (*EvaluationTrace, error)
+	AllFlagsState(ctx context.Context, evalCtx EvaluationContext, opts FlagsStateOptions, reqOpts ...RequestOption) (FlagsState, error)
+	ExpiredFlags(ctx context.Context, reqOpts ...RequestOption) ([]ExpiredFlag, error)
+
+	// Flag groups and atomic operations
+	AtomicFlagOperation(ctx context.Context, ops []FlagOperation, reqOpts ...RequestOption) ([]FeatureFlag, error)
+	ListFlagGroups(ctx context.Context, reqOpts ...RequestOption) ([]FlagGroup, error)
+	CreateFlagGroup(ctx context.Context, group FlagGroupCreate, reqOpts ...RequestOption) (*FlagGroup, error)
+	GetFlagGroup(ctx context.Context, id int, reqOpts ...RequestOption) (*FlagGroup, error)
+	UpdateFlagGroup(ctx context.Context, id int, group FlagGroupUpdate, reqOpts ...RequestOption) (*FlagGroup, error)
+	DeleteFlagGroup(ctx context.Context, id int, reqOpts ...RequestOption) error
+	SetFlagGroupActive(ctx context.Context, id int, active bool, reqOpts ...RequestOption) ([]FeatureFlag, error)
+	UpdateFlagGroupFlags(ctx context.Context, id int, update FeatureFlagUpdate, reqOpts ...RequestOption) ([]FeatureFlag, error)
+
+	// Rollouts, traffic and triggers
+	CreateRollout(ctx context.Context, flagID int, rollout RolloutCreate, reqOpts ...RequestOption) (*Rollout, error)
+	GetRollout(ctx context.Context, flagID int, reqOpts ...RequestOption) (*Rollout, error)
+	PauseRollout(ctx context.Context, flagID int, reason string, reqOpts ...RequestOption) (*Rollout, error)
+	ResumeRollout(ctx context.Context, flagID int, reqOpts ...RequestOption) (*Rollout, error)
+	RollbackRollout(ctx context.Context, flagID int, reason string, reqOpts ...RequestOption) (*Rollout, error)
+	CancelRollout(ctx context.Context, flagID int, reqOpts ...RequestOption) error
+	GetTrafficAllocation(ctx context.Context, flagID int, reqOpts ...RequestOption) (*TrafficAllocation, error)
+	UpdateTrafficAllocation(ctx context.Context, flagID int, allocation TrafficAllocation, reqOpts ...RequestOption) (*TrafficAllocation, error)
+	ListTriggers(ctx context.Context, flagID int, reqOpts ...RequestOption) ([]Trigger, error)
+	CreateTrigger(ctx context.Context, flagID int, trigger TriggerCreate, reqOpts ...RequestOption) (*Trigger, error)
+	GetTrigger(ctx context.Context, flagID, triggerID int, reqOpts ...RequestOption) (*Trigger, error)
+	UpdateTrigger(ctx context.Context, flagID, triggerID int, trigger TriggerUpdate, reqOpts ...RequestOption) (*Trigger, error)
+	ResetTriggerURL(ctx context.Context, flagID, triggerID int, reqOpts ...RequestOption) (*Trigger, error)
+	DeleteTrigger(ctx context.Context, flagID, triggerID int, reqOpts ...RequestOption) error
+
+	// Experiments
+	ListLayers(ctx context.Context, environment string, reqOpts ...RequestOption) ([]Layer, error)
+	CreateLayer(ctx context.Context, layer LayerCreate, reqOpts ...RequestOption) (*Layer, error)
+	GetLayer(ctx context.Context, id int, reqOpts ...RequestOption) (*Layer, error)
+	UpdateLayer(ctx context.Context, id int, layer LayerUpdate, reqOpts ...RequestOption) (*Layer, error)
+	DeleteLayer(ctx context.Context, id int, reqOpts ...RequestOption) error
+	ListHoldouts(ctx context.Context, environment string, reqOpts ...RequestOption) ([]Holdout, error)
+	CreateHoldout(ctx context.Context, holdout HoldoutCreate, reqOpts ...RequestOption) (*Holdout, error)
+	GetHoldout(ctx context.Context, id int, reqOpts ...RequestOption) (*Holdout, error)
+	UpdateHoldout(ctx context.Context, id int, holdout HoldoutUpdate, reqOpts ...RequestOption) (*Holdout, error)
+	DeleteHoldout(ctx context.Context, id int, reqOpts ...RequestOption) error
+	GetExperimentResults(ctx context.Context, name string, reqOpts ...RequestOption) ([]ExperimentResult, error)
+	Simulate(ctx context.Context, draft FeatureFlag, contexts []EvaluationContext, reqOpts ...RequestOption) (*SimulationResult, error)
+
+	// Segments
+	ListSegments(ctx context.Context, reqOpts ...RequestOption) ([]Segment, error)
+	GetSegment(ctx context.Context, name string, reqOpts ...RequestOption) (*Segment, error)
+	CreateSegment(ctx context.Context, segment SegmentCreate, reqOpts ...RequestOption) (*Segment, error)
+	UpdateSegment(ctx context.Context, name string, segment SegmentUpdate, reqOpts ...RequestOption) (*Segment, error)
+	DeleteSegment(ctx context.Context, name string, reqOpts ...RequestOption) error
+
+	// Projects, environments and defaults
+	ListProjects(ctx context.Context, reqOpts ...RequestOption) ([]Project, error)
+	GetProject(ctx context.Context, id int, reqOpts ...RequestOption) (*Project, error)
+	CreateProject(ctx context.Context, project ProjectCreate, reqOpts ...RequestOption) (*Project, error)
+	UpdateProject(ctx context.Context, id int, project ProjectUpdate, reqOpts ...RequestOption) (*Project, error)
+	DeleteProject(ctx context.Context, id int, reqOpts ...RequestOption) error
+	ListEnvironments(ctx context.Context, reqOpts ...RequestOption) ([]Environment, error)
+	GetEnvironment(ctx context.Context, key string, reqOpts ...RequestOption) (*Environment, error)
+	CreateEnvironment(ctx context.Context, environment EnvironmentCreate, reqOpts ...RequestOption) (*Environment, error)
+	UpdateEnvironment(ctx context.Context, key string, environment EnvironmentUpdate, reqOpts ...RequestOption) (*Environment, error)
+	DeleteEnvironment(ctx context.Context, key string, reqOpts ...RequestOption) error
+	ValidateEnvironment(ctx context.Context, key string, reqOpts ...RequestOption) error
+	GetProjectDefaults(ctx context.Context, projectID int, reqOpts ...RequestOption) (*FlagDefaults, error)
+	UpdateProjectDefaults(ctx context.Context, projectID int, defaults FlagDefaults, reqOpts ...RequestOption) (*FlagDefaults, error)
+	GetEnvironmentDefaults(ctx context.Context, projectID int, environment string, reqOpts ...RequestOption) (*FlagDefaults, error)
+	UpdateEnvironmentDefaults(ctx context.Context, projectID int, environment string, defaults FlagDefaults, reqOpts ...RequestOption) (*FlagDefaults, error)
+	ResolveFeatureFlag(ctx context.Context, id int, reqOpts ...RequestOption) (*ResolvedFlag, error)
+
+	// Change requests and release pipelines
+	CreateChangeRequest(ctx context.Context, change ChangeRequestCreate, reqOpts ...RequestOption) (*ChangeRequest, error)
+	ListChangeRequests(ctx context.Context, query ChangeRequestQuery, reqOpts ...RequestOption) ([]ChangeRequest, error)
+	GetChangeRequest(ctx context.Context, id int, reqOpts ...RequestOption) (*ChangeRequest, error)
+	ApproveChangeRequest(ctx context.Context, id int, comment string, reqOpts ...RequestOption) (*ChangeRequest, error)
+	RejectChangeRequest(ctx context.Context, id int, comment string, reqOpts ...RequestOption) (*ChangeRequest, error)
+	ApplyChangeRequest(ctx context.Context, id int, reqOpts ...RequestOption) (*FeatureFlag, error)
+	ListReleasePipelines(ctx context.Context, reqOpts ...RequestOption) ([]ReleasePipeline, error)
+	CreateReleasePipeline(ctx context.Context, pipeline ReleasePipelineCreate, reqOpts ...RequestOption) (*ReleasePipeline, error)
+	GetReleasePipeline(ctx context.Context, id int, reqOpts ...RequestOption) (*ReleasePipeline, error)
+	UpdateReleasePipeline(ctx context.Context, id int, pipeline ReleasePipelineUpdate, reqOpts ...RequestOption) (*ReleasePipeline, error)
+	DeleteReleasePipeline(ctx context.Context, id int, reqOpts ...RequestOption) error
+	GetFlagRelease(ctx context.Context, pipelineID int, flag string, reqOpts ...RequestOption) (*FlagRelease, error)
+	ReportReleaseCheck(ctx context.Context, pipelineID int, flag, environment, check string, passed bool, reqOpts ...RequestOption) (*FlagRelease, error)
+	ApproveRelease(ctx context.Context, pipelineID int, flag, environment, comment string, reqOpts ...RequestOption) (*FlagRelease, error)
+	AdvanceRelease(ctx context.Context, pipelineID int, flag string, reqOpts ...RequestOption) (*FlagRelease, error)
+
+	// Webhooks, API keys and audit
+	ListWebhooks(ctx context.Context, reqOpts ...RequestOption) ([]Webhook, error)
+	GetWebhook(ctx context.Context, id int, reqOpts ...RequestOption) (*Webhook, error)
+	CreateWebhook(ctx context.Context, webhook WebhookCreate, reqOpts ...RequestOption) (*Webhook, error)
+	UpdateWebhook(ctx context.Context, id int, webhook WebhookUpdate, reqOpts ...RequestOption) (*Webhook, error)
+	DeleteWebhook(ctx context.Context, id int, reqOpts ...RequestOption) error
+	AddWebhook(ctx context.Context, url string, reqOpts ...RequestOption) error
+	RemoveWebhook(ctx context.Context, url string, reqOpts ...RequestOption) error
+	ListAPIKeys(ctx context.Context, params map[string]string, reqOpts ...RequestOption) ([]APIKey, error)
+	GetAPIKey(ctx context.Context, id int, reqOpts ...RequestOption) (*APIKey, error)
+	CreateAPIKey(ctx context.Context, key APIKeyCreate, reqOpts ...RequestOption) (*APIKey, error)
+	RotateAPIKey(ctx context.Context, id int, gracePeriod time.Duration, reqOpts ...RequestOption) (*APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int, reqOpts ...RequestOption) error
+	ListAuditEvents(ctx context.Context, query AuditQuery, reqOpts ...RequestOption) (*AuditPage, error)
+	ListAuditLog(ctx context.Context, query AuditLogQuery, reqOpts ...RequestOption) ([]AuditLogEntry, error)
+
+	// Import, export and apply
+	ExportFlags(ctx context.Context, environment string, reqOpts ...RequestOption) (*FlagExport, error)
+	ImportFlags(ctx context.Context, doc *FlagExport, opts ImportOptions, reqOpts ...RequestOption) (*ImportResult, error)
+	ExportOpenFeature(ctx context.Context, environment string, reqOpts ...RequestOption) (*OpenFeatureDocument, error)
+	ExportCatalog(ctx context.Context, environment string, opts CatalogOptions, reqOpts ...RequestOption) ([]CatalogEntity, error)
+	Apply(ctx context.Context, desired DesiredState, opts ApplyOptions, reqOpts ...RequestOption) (*ApplyResult, error)
+	Plan(ctx context.Context, desired []FlagSpec, reqOpts ...RequestOption) (*FlagPlan, error)
+
+	// Client state
+	Clock() Clock
+	Logger() *slog.Logger
+	Metrics() Metrics
+	Tracer() Tracer
+	RateLimit() (RateLimit, bool)
+	BreakerState() BreakerState
+	Start(ctx context.Context, reqOpts ...RequestOption) error
+	WaitForReady(ctx context.Context, timeout time.Duration, reqOpts ...RequestOption) error
+	DataSourceStatus() DataSourceStatus
+	Close() error
+}
+
+var _ API = (*Client)(nil)
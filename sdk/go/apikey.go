@@ -0,0 +1,133 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// APIKey is a key accessing the API, scoped to a project, an environment or
+// both. The secret Key is only returned when the key is created or rotated.
+type APIKey struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Key is the secret, empty when listing keys; Prefix identifies the key in listings and logs
+	Key    string `json:"key,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+	// ProjectID and Environment scope the key, which accesses every project or environment when they are empty
+	ProjectID   int        `json:"project_id,omitempty"`
+	Environment string     `json:"environment,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// APIKeyCreate represents the data needed to create an API key
+type APIKeyCreate struct {
+	Name        string     `json:"name"`
+	ProjectID   int        `json:"project_id,omitempty"`
+	Environment string     `json:"environment,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+// apiKeyRotate is the body of a key rotation
+type apiKeyRotate struct {
+	GracePeriodSeconds int `json:"grace_period_seconds,omitempty"`
+}
+
+// Active reports whether the key is neither revoked nor expired at now
+func (k *APIKey) Active(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// ListAPIKeys retrieves the API keys, optionally filtered by parameters such
+// as project_id and environment. Their secrets are not returned.
+func (c *Client) ListAPIKeys(ctx context.Context, params map[string]string, reqOpts ...RequestOption) ([]APIKey, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	respBody, err := c.doRequest(ctx, request{
+		method: "GET",
+		path:   "/api/v1/api-keys/",
+		query:  params,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []APIKey
+	if err := json.Unmarshal(respBody, &keys); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return keys, nil
+}
+
+// GetAPIKey retrieves an API key by ID, without its secret
+func (c *Client) GetAPIKey(ctx context.Context, id int, reqOpts ...RequestOption) (*APIKey, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.apiKeyRequest(ctx, request{
+		method: "GET",
+		path:   fmt.Sprintf("/api/v1/api-keys/%d", id),
+	})
+}
+
+// CreateAPIKey creates a new API key, returning it with its secret
+func (c *Client) CreateAPIKey(ctx context.Context, key APIKeyCreate, reqOpts ...RequestOption) (*APIKey, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if key.Name == "" {
+		return nil, fmt.Errorf("API key name is required")
+	}
+	if key.Environment != "" {
+		if err := ValidateEnvironmentKey(key.Environment); err != nil {
+			return nil, err
+		}
+	}
+	return c.apiKeyRequest(ctx, request{
+		method: "POST",
+		path:   "/api/v1/api-keys/",
+		body:   key,
+	})
+}
+
+// RotateAPIKey replaces the secret of an API key, returning the key with its
+// new secret. The old secret keeps working for gracePeriod, so deployments can
+// switch to the new one without failed requests; zero revokes it immediately.
+func (c *Client) RotateAPIKey(ctx context.Context, id int, gracePeriod time.Duration, reqOpts ...RequestOption) (*APIKey, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if gracePeriod < 0 {
+		return nil, fmt.Errorf("grace period must not be negative")
+	}
+	return c.apiKeyRequest(ctx, request{
+		method: "POST",
+		path:   fmt.Sprintf("/api/v1/api-keys/%d/rotate", id),
+		body:   apiKeyRotate{GracePeriodSeconds: int(gracePeriod.Round(time.Second) / time.Second)},
+	})
+}
+
+// RevokeAPIKey revokes an API key, which fails every request from then on.
+// Revoked keys stay listed with their RevokedAt time.
+func (c *Client) RevokeAPIKey(ctx context.Context, id int, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	_, err := c.doRequest(ctx, request{
+		method: "POST",
+		path:   fmt.Sprintf("/api/v1/api-keys/%d/revoke", id),
+	})
+	return err
+}
+
+// apiKeyRequest performs a request returning an API key
+func (c *Client) apiKeyRequest(ctx context.Context, req request) (*APIKey, error) {
+	respBody, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var key APIKey
+	if err := json.Unmarshal(respBody, &key); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return &key, nil
+}
@@ -0,0 +1,74 @@
+package matrixflag
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeys(t *testing.T) {
+	var requests, bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		bodies = append(bodies, string(body))
+		switch r.URL.Path {
+		case "/api/v1/api-keys/":
+			if r.Method == http.MethodGet {
+				writeJSON(w, []APIKey{{ID: 4, Name: "ci", Prefix: "mf_1a2b"}})
+				return
+			}
+			writeJSON(w, APIKey{ID: 4, Name: "ci", Key: "mf_1a2b-secret"})
+		case "/api/v1/api-keys/4/rotate":
+			writeJSON(w, APIKey{ID: 4, Name: "ci", Key: "mf_3c4d-secret"})
+		default:
+			writeJSON(w, APIKey{ID: 4, Name: "ci"})
+		}
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+
+	key, err := client.CreateAPIKey(ctx, APIKeyCreate{Name: "ci", ProjectID: 3, Environment: "staging"})
+	require.NoError(t, err)
+	assert.Equal(t, "mf_1a2b-secret", key.Key)
+	keys, err := client.ListAPIKeys(ctx, map[string]string{"environment": "staging"})
+	require.NoError(t, err)
+	assert.Empty(t, keys[0].Key)
+	_, err = client.GetAPIKey(ctx, 4)
+	require.NoError(t, err)
+	key, err = client.RotateAPIKey(ctx, 4, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "mf_3c4d-secret", key.Key)
+	require.NoError(t, client.RevokeAPIKey(ctx, 4))
+
+	assert.Equal(t, []string{
+		"POST /api/v1/api-keys/",
+		"GET /api/v1/api-keys/?environment=staging",
+		"GET /api/v1/api-keys/4",
+		"POST /api/v1/api-keys/4/rotate",
+		"POST /api/v1/api-keys/4/revoke",
+	}, requests)
+	assert.JSONEq(t, `{"name":"ci","project_id":3,"environment":"staging"}`, bodies[0])
+	assert.JSONEq(t, `{"grace_period_seconds":3600}`, bodies[3])
+
+	_, err = client.CreateAPIKey(ctx, APIKeyCreate{})
+	assert.Error(t, err)
+	_, err = client.RotateAPIKey(ctx, 4, -time.Second)
+	assert.Error(t, err)
+}
+
+func TestAPIKeyActive(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	assert.True(t, (&APIKey{}).Active(now))
+	assert.True(t, (&APIKey{ExpiresAt: &future}).Active(now))
+	assert.False(t, (&APIKey{ExpiresAt: &past}).Active(now))
+	assert.False(t, (&APIKey{RevokedAt: &past}).Active(now))
+}
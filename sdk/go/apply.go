@@ -0,0 +1,525 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// FlagSpec represents the desired configuration of a feature flag.
+// Flags are identified by their environment and name.
+type FlagSpec struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	IsActive    bool   `json:"is_active" yaml:"is_active"`
+	Environment string `json:"environment,omitempty" yaml:"environment,omitempty"`
+	ProjectID   int    `json:"project_id,omitempty" yaml:"project_id,omitempty"`
+}
+
+// SegmentSpec represents the desired configuration of a segment, identified by its name
+type SegmentSpec struct {
+	Name        string        `json:"name" yaml:"name"`
+	Description string        `json:"description,omitempty" yaml:"description,omitempty"`
+	Included    []string      `json:"included,omitempty" yaml:"included,omitempty"`
+	Excluded    []string      `json:"excluded,omitempty" yaml:"excluded,omitempty"`
+	Rules       []SegmentRule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// WebhookSpec represents the desired configuration of a webhook, identified by its URL
+type WebhookSpec struct {
+	URL    string             `json:"url" yaml:"url"`
+	Events []WebhookEventType `json:"events,omitempty" yaml:"events,omitempty"`
+	// Paused stops deliveries while keeping the webhook
+	Paused bool `json:"paused,omitempty" yaml:"paused,omitempty"`
+	// Secret signs the deliveries. It is never read back, so only adding or
+	// removing a secret is a change; a new secret is sent with other changes.
+	Secret string `json:"-" yaml:"-"`
+}
+
+// DesiredState represents the desired set of objects to converge to
+type DesiredState struct {
+	Flags []FlagSpec
+	// Segments are shared by all environments. They are only pruned when
+	// Segments is not nil, so an empty list prunes every segment.
+	Segments []SegmentSpec
+	// Webhooks are pruned like segments, only when Webhooks is not nil
+	Webhooks []WebhookSpec
+}
+
+// ApplyOptions controls how a desired state is applied
+type ApplyOptions struct {
+	// Prune deletes flags, segments and webhooks that are not part of the desired state.
+	// Only environments referenced by the desired state are pruned.
+	Prune bool
+	// DryRun computes the change set without executing it
+	DryRun bool
+}
+
+// ChangeAction represents the kind of change made to an object
+type ChangeAction string
+
+// Change actions
+const (
+	ChangeCreate ChangeAction = "create"
+	ChangeUpdate ChangeAction = "update"
+	ChangeDelete ChangeAction = "delete"
+	// ChangeNoop marks a flag already matching its spec, only listed by Plan
+	ChangeNoop ChangeAction = "no-op"
+)
+
+// ObjectKind is the kind of object changed by Apply
+type ObjectKind string
+
+// Object kinds
+const (
+	KindFlag    ObjectKind = "flag"
+	KindSegment ObjectKind = "segment"
+	KindWebhook ObjectKind = "webhook"
+)
+
+// FieldChange describes a change to a single field
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// Change represents a single change to a flag, segment or webhook
+type Change struct {
+	Action ChangeAction `json:"action"`
+	Kind   ObjectKind   `json:"kind"`
+	// Name is the name of a flag or segment, or the URL of a webhook
+	Name string `json:"name"`
+	// Environment is the environment of a flag, empty for segments
+	Environment string        `json:"environment,omitempty"`
+	ID          int           `json:"id,omitempty"`
+	Fields      []FieldChange `json:"fields,omitempty"`
+}
+
+// ApplyResult represents the outcome of an apply
+type ApplyResult struct {
+	Changes []Change `json:"changes"`
+	DryRun  bool     `json:"dry_run"`
+}
+
+// flagKey identifies a flag within a project
+type flagKey struct {
+	environment string
+	name        string
+}
+
+// Apply computes and executes the minimal change set converging the server to the desired state.
+// Segments are created and updated before flags and deleted after them, so flag rules can target them.
+// Webhooks are changed last.
+// On error the result holds the changes that were executed before the failure.
+func (c *Client) Apply(ctx context.Context, desired DesiredState, opts ApplyOptions, reqOpts ...RequestOption) (*ApplyResult, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	flagChanges, err := c.planFlags(ctx, desired.Flags, opts.Prune)
+	if err != nil {
+		return nil, err
+	}
+	segmentChanges, err := c.planSegments(ctx, desired.Segments, opts.Prune && desired.Segments != nil)
+	if err != nil {
+		return nil, err
+	}
+	webhookChanges, err := c.planWebhooks(ctx, desired.Webhooks, opts.Prune && desired.Webhooks != nil)
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	for _, change := range segmentChanges {
+		if change.Action != ChangeDelete {
+			changes = append(changes, change)
+		}
+	}
+	changes = append(changes, flagChanges...)
+	for _, change := range segmentChanges {
+		if change.Action == ChangeDelete {
+			changes = append(changes, change)
+		}
+	}
+	changes = append(changes, webhookChanges...)
+
+	result := &ApplyResult{DryRun: opts.DryRun}
+	if opts.DryRun {
+		result.Changes = changes
+		return result, nil
+	}
+
+	flags := make(map[flagKey]FlagSpec, len(desired.Flags))
+	for _, spec := range desired.Flags {
+		flags[flagKey{spec.Environment, spec.Name}] = spec
+	}
+	segments := make(map[string]SegmentSpec, len(desired.Segments))
+	for _, spec := range desired.Segments {
+		segments[spec.Name] = spec
+	}
+	webhooks := make(map[string]WebhookSpec, len(desired.Webhooks))
+	for _, spec := range desired.Webhooks {
+		webhooks[spec.URL] = spec
+	}
+	for _, change := range changes {
+		switch change.Kind {
+		case KindSegment:
+			if err := c.applySegmentChange(ctx, change, segments[change.Name]); err != nil {
+				return result, fmt.Errorf("failed to %s segment %s: %w", change.Action, change.Name, err)
+			}
+		case KindWebhook:
+			if err := c.applyWebhookChange(ctx, change, webhooks[change.Name]); err != nil {
+				return result, fmt.Errorf("failed to %s webhook %s: %w", change.Action, change.Name, err)
+			}
+		default:
+			if err := c.applyChange(ctx, change, flags[flagKey{change.Environment, change.Name}]); err != nil {
+				return result, fmt.Errorf("failed to %s flag %s/%s: %w", change.Action, change.Environment, change.Name, err)
+			}
+		}
+		result.Changes = append(result.Changes, change)
+	}
+	return result, nil
+}
+
+// FlagPlan is the diff between the flags on the server and their desired specs
+type FlagPlan struct {
+	// Creates are the specs missing on the server, in the order of the specs
+	Creates []Change `json:"creates"`
+	// Updates are the flags differing from their spec
+	Updates []Change `json:"updates"`
+	// Deletes are the flags missing from the specs in the environments they reference
+	Deletes []Change `json:"deletes"`
+	// NoOps are the flags matching their spec
+	NoOps []Change `json:"no_ops"`
+}
+
+// HasDrift reports whether the flags on the server differ from their specs
+func (p *FlagPlan) HasDrift() bool {
+	return len(p.Creates)+len(p.Updates)+len(p.Deletes) > 0
+}
+
+// Changes returns the changes converging the server to the specs, in the order Apply executes them
+func (p *FlagPlan) Changes() []Change {
+	changes := make([]Change, 0, len(p.Creates)+len(p.Updates)+len(p.Deletes))
+	changes = append(changes, p.Creates...)
+	changes = append(changes, p.Updates...)
+	return append(changes, p.Deletes...)
+}
+
+// Plan compares the flags on the server with their desired specs without
+// changing anything, so CI can detect drift from committed definitions. Flags
+// of the environments the specs reference that have no spec are deletes, as
+// Apply with Prune would delete them.
+func (c *Client) Plan(ctx context.Context, desired []FlagSpec, reqOpts ...RequestOption) (*FlagPlan, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.flagPlan(ctx, desired, true)
+}
+
+// planFlags computes the changes needed to converge flags to the desired specs
+func (c *Client) planFlags(ctx context.Context, specs []FlagSpec, prune bool) ([]Change, error) {
+	plan, err := c.flagPlan(ctx, specs, prune)
+	if err != nil {
+		return nil, err
+	}
+	return plan.Changes(), nil
+}
+
+// flagPlan diffs the flags of the environments of specs with them, listing
+// the flags without a spec as deletes when pruning
+func (c *Client) flagPlan(ctx context.Context, specs []FlagSpec, prune bool) (*FlagPlan, error) {
+	desired := make(map[flagKey]FlagSpec, len(specs))
+	envSeen := make(map[string]bool)
+	var environments []string
+	for _, spec := range specs {
+		if spec.Name == "" || spec.Environment == "" {
+			return nil, fmt.Errorf("invalid flag spec: name and environment are required")
+		}
+		key := flagKey{spec.Environment, spec.Name}
+		if _, ok := desired[key]; ok {
+			return nil, fmt.Errorf("duplicate flag spec %s/%s", spec.Environment, spec.Name)
+		}
+		if !envSeen[spec.Environment] {
+			envSeen[spec.Environment] = true
+			environments = append(environments, spec.Environment)
+		}
+		desired[key] = spec
+	}
+
+	plan := &FlagPlan{Creates: []Change{}, Updates: []Change{}, Deletes: []Change{}, NoOps: []Change{}}
+	seen := make(map[flagKey]bool, len(specs))
+	for _, env := range environments {
+		flags, err := c.ListFeatureFlags(ctx, FlagFilter{Environment: env})
+		if err != nil {
+			return nil, err
+		}
+		for _, flag := range flags {
+			key := flagKey{flag.Environment, flag.Name}
+			spec, ok := desired[key]
+			if !ok {
+				if prune {
+					plan.Deletes = append(plan.Deletes, Change{Action: ChangeDelete, Kind: KindFlag, Name: flag.Name, Environment: flag.Environment, ID: flag.ID})
+				}
+				continue
+			}
+			seen[key] = true
+			if fields := diffFlag(flag, spec); len(fields) > 0 {
+				plan.Updates = append(plan.Updates, Change{Action: ChangeUpdate, Kind: KindFlag, Name: flag.Name, Environment: flag.Environment, ID: flag.ID, Fields: fields})
+			} else {
+				plan.NoOps = append(plan.NoOps, Change{Action: ChangeNoop, Kind: KindFlag, Name: flag.Name, Environment: flag.Environment, ID: flag.ID})
+			}
+		}
+	}
+	for _, spec := range specs {
+		if !seen[flagKey{spec.Environment, spec.Name}] {
+			plan.Creates = append(plan.Creates, Change{Action: ChangeCreate, Kind: KindFlag, Name: spec.Name, Environment: spec.Environment, Fields: diffFlag(FeatureFlag{}, spec)})
+		}
+	}
+
+	sort.SliceStable(plan.Deletes, func(i, j int) bool {
+		a, b := plan.Deletes[i], plan.Deletes[j]
+		return a.Environment+"/"+a.Name < b.Environment+"/"+b.Name
+	})
+	return plan, nil
+}
+
+// planSegments computes the changes needed to converge segments to the desired specs
+func (c *Client) planSegments(ctx context.Context, specs []SegmentSpec, prune bool) ([]Change, error) {
+	desired := make(map[string]SegmentSpec, len(specs))
+	for _, spec := range specs {
+		if spec.Name == "" {
+			return nil, fmt.Errorf("invalid segment spec: a name is required")
+		}
+		if _, ok := desired[spec.Name]; ok {
+			return nil, fmt.Errorf("duplicate segment spec %s", spec.Name)
+		}
+		if err := ValidateSegmentRules(spec.Rules); err != nil {
+			return nil, fmt.Errorf("invalid segment spec %s: %w", spec.Name, err)
+		}
+		desired[spec.Name] = spec
+	}
+	if len(specs) == 0 && !prune {
+		return nil, nil
+	}
+
+	segments, err := c.ListSegments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var creates, updates, deletes []Change
+	seen := make(map[string]bool, len(specs))
+	for _, segment := range segments {
+		spec, ok := desired[segment.Name]
+		if !ok {
+			if prune {
+				deletes = append(deletes, Change{Action: ChangeDelete, Kind: KindSegment, Name: segment.Name})
+			}
+			continue
+		}
+		seen[segment.Name] = true
+		if fields := diffSegment(segment, spec); len(fields) > 0 {
+			updates = append(updates, Change{Action: ChangeUpdate, Kind: KindSegment, Name: segment.Name, Fields: fields})
+		}
+	}
+	for _, spec := range specs {
+		if !seen[spec.Name] {
+			creates = append(creates, Change{Action: ChangeCreate, Kind: KindSegment, Name: spec.Name, Fields: diffSegment(Segment{}, spec)})
+		}
+	}
+
+	sort.SliceStable(deletes, func(i, j int) bool { return deletes[i].Name < deletes[j].Name })
+	changes := append(creates, updates...)
+	return append(changes, deletes...), nil
+}
+
+// planWebhooks computes the changes needed to converge webhooks to the desired
+// specs. Of several webhooks delivering to one URL, the first is kept.
+func (c *Client) planWebhooks(ctx context.Context, specs []WebhookSpec, prune bool) ([]Change, error) {
+	desired := make(map[string]WebhookSpec, len(specs))
+	for _, spec := range specs {
+		if err := ValidateWebhook(spec.URL, spec.Events); err != nil {
+			return nil, fmt.Errorf("invalid webhook spec: %w", err)
+		}
+		if _, ok := desired[spec.URL]; ok {
+			return nil, fmt.Errorf("duplicate webhook spec %s", spec.URL)
+		}
+		desired[spec.URL] = spec
+	}
+	if len(specs) == 0 && !prune {
+		return nil, nil
+	}
+
+	webhooks, err := c.ListWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var creates, updates, deletes []Change
+	seen := make(map[string]bool, len(specs))
+	for _, webhook := range webhooks {
+		spec, ok := desired[webhook.URL]
+		if !ok || seen[webhook.URL] {
+			if prune {
+				deletes = append(deletes, Change{Action: ChangeDelete, Kind: KindWebhook, Name: webhook.URL, ID: webhook.ID})
+			}
+			continue
+		}
+		seen[webhook.URL] = true
+		if fields := diffWebhook(webhook, spec); len(fields) > 0 {
+			updates = append(updates, Change{Action: ChangeUpdate, Kind: KindWebhook, Name: webhook.URL, ID: webhook.ID, Fields: fields})
+		}
+	}
+	for _, spec := range specs {
+		if !seen[spec.URL] {
+			creates = append(creates, Change{Action: ChangeCreate, Kind: KindWebhook, Name: spec.URL, Fields: diffWebhook(Webhook{}, spec)})
+		}
+	}
+
+	sort.SliceStable(deletes, func(i, j int) bool { return deletes[i].Name < deletes[j].Name })
+	changes := append(creates, updates...)
+	return append(changes, deletes...), nil
+}
+
+// diffWebhook returns the fields that differ between a webhook and its spec.
+// The secret is reported as the signed field, so plans do not reveal it.
+func diffWebhook(webhook Webhook, spec WebhookSpec) []FieldChange {
+	var fields []FieldChange
+	add := func(field string, old, new any) {
+		if !sameJSON(old, new) {
+			fields = append(fields, FieldChange{Field: field, Old: old, New: new})
+		}
+	}
+	add(FieldEvents, webhook.Events, spec.Events)
+	add(FieldIsActive, webhook.IsActive, !spec.Paused)
+	add("signed", webhook.Signed, spec.Secret != "")
+	return fields
+}
+
+// diffSegment returns the fields that differ between a segment and its spec
+func diffSegment(segment Segment, spec SegmentSpec) []FieldChange {
+	var fields []FieldChange
+	add := func(field string, old, new any) {
+		if !sameJSON(old, new) {
+			fields = append(fields, FieldChange{Field: field, Old: old, New: new})
+		}
+	}
+	add(FieldDescription, segment.Description, spec.Description)
+	add(FieldIncluded, segment.Included, spec.Included)
+	add(FieldExcluded, segment.Excluded, spec.Excluded)
+	add(FieldRules, segment.Rules, spec.Rules)
+	return fields
+}
+
+// sameJSON reports whether two values encode to the same JSON, so that values
+// decoded from the server compare equal to the Go values they were created
+// from. Empty lists and maps equal nil ones.
+func sameJSON(a, b any) bool {
+	normalize := func(v any) string {
+		rv := reflect.ValueOf(v)
+		if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Map) && rv.Len() == 0 {
+			return "null"
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+	return normalize(a) == normalize(b)
+}
+
+// diffFlag returns the fields that differ between a flag and its spec
+func diffFlag(flag FeatureFlag, spec FlagSpec) []FieldChange {
+	var fields []FieldChange
+	add := func(field string, old, new any) {
+		if !reflect.DeepEqual(old, new) {
+			fields = append(fields, FieldChange{Field: field, Old: old, New: new})
+		}
+	}
+	add(FieldName, flag.Name, spec.Name)
+	add(FieldDescription, flag.Description, spec.Description)
+	add(FieldIsActive, flag.IsActive, spec.IsActive)
+	add(FieldEnvironment, flag.Environment, spec.Environment)
+	add(FieldProjectID, flag.ProjectID, spec.ProjectID)
+	return fields
+}
+
+// applyChange executes a single planned change
+func (c *Client) applyChange(ctx context.Context, change Change, spec FlagSpec) error {
+	switch change.Action {
+	case ChangeCreate:
+		_, err := c.CreateFeatureFlag(ctx, FeatureFlagCreate{
+			Name:        spec.Name,
+			Description: spec.Description,
+			IsActive:    spec.IsActive,
+			Environment: spec.Environment,
+			ProjectID:   spec.ProjectID,
+		})
+		return err
+	case ChangeUpdate:
+		// Managed fields are sent even when empty, so clearing them converges
+		_, err := c.UpdateFeatureFlag(ctx, change.ID, FeatureFlagUpdate{
+			Description: spec.Description,
+			IsActive:    spec.IsActive,
+			ProjectID:   spec.ProjectID,
+			Fields:      []string{FieldDescription, FieldIsActive, FieldProjectID},
+		})
+		return err
+	case ChangeDelete:
+		_, err := c.DeleteFeatureFlag(ctx, change.ID)
+		return err
+	default:
+		return fmt.Errorf("unknown change action %q", change.Action)
+	}
+}
+
+// applySegmentChange executes a single planned segment change
+func (c *Client) applySegmentChange(ctx context.Context, change Change, spec SegmentSpec) error {
+	switch change.Action {
+	case ChangeCreate:
+		_, err := c.CreateSegment(ctx, SegmentCreate{
+			Name:        spec.Name,
+			Description: spec.Description,
+			Included:    spec.Included,
+			Excluded:    spec.Excluded,
+			Rules:       spec.Rules,
+		})
+		return err
+	case ChangeUpdate:
+		_, err := c.UpdateSegment(ctx, change.Name, SegmentUpdate{
+			Description: spec.Description,
+			Included:    spec.Included,
+			Excluded:    spec.Excluded,
+			Rules:       spec.Rules,
+			Fields:      []string{FieldDescription, FieldIncluded, FieldExcluded, FieldRules},
+		})
+		return err
+	case ChangeDelete:
+		return c.DeleteSegment(ctx, change.Name)
+	default:
+		return fmt.Errorf("unknown change action %q", change.Action)
+	}
+}
+
+// applyWebhookChange executes a single planned webhook change
+func (c *Client) applyWebhookChange(ctx context.Context, change Change, spec WebhookSpec) error {
+	switch change.Action {
+	case ChangeCreate:
+		webhook, err := c.CreateWebhook(ctx, WebhookCreate{URL: spec.URL, Events: spec.Events, Secret: spec.Secret})
+		if err != nil || !spec.Paused {
+			return err
+		}
+		// Webhooks are created active
+		_, err = c.UpdateWebhook(ctx, webhook.ID, WebhookUpdate{Fields: FieldMask{FieldIsActive}})
+		return err
+	case ChangeUpdate:
+		_, err := c.UpdateWebhook(ctx, change.ID, WebhookUpdate{
+			Events:   spec.Events,
+			IsActive: !spec.Paused,
+			Secret:   spec.Secret,
+			Fields:   []string{FieldEvents, FieldIsActive, FieldSecret},
+		})
+		return err
+	case ChangeDelete:
+		return c.DeleteWebhook(ctx, change.ID)
+	default:
+		return fmt.Errorf("unknown change action %q", change.Action)
+	}
+}
@@ -0,0 +1,198 @@
+package matrixflag
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyConverges(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.addFlag(FeatureFlag{Name: "checkout", Environment: "production", IsActive: true, Description: "old", ProjectID: 4})
+	srv.addFlag(FeatureFlag{Name: "stale", Environment: "production"})
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+
+	desired := DesiredState{
+		Flags: []FlagSpec{
+			// Clears the description and project, and deactivates the flag
+			{Name: "checkout", Environment: "production"},
+			{Name: "dark-mode", Environment: "production", IsActive: true},
+		},
+		Segments: []SegmentSpec{{
+			Name:     "beta",
+			Included: []string{"user-1"},
+			Rules: []SegmentRule{{Conditions: []TargetingCondition{
+				{Attribute: "plan", Operator: OpIn, Value: []string{"enterprise"}},
+			}}},
+		}},
+	}
+	opts := ApplyOptions{Prune: true}
+
+	result, err := client.Apply(ctx, desired, opts)
+	require.NoError(t, err)
+	var actions []string
+	for _, change := range result.Changes {
+		actions = append(actions, string(change.Action)+" "+string(change.Kind)+" "+change.Name)
+	}
+	assert.Equal(t, []string{
+		"create segment beta",
+		"create flag dark-mode",
+		"update flag checkout",
+		"delete flag stale",
+	}, actions)
+
+	flags, err := client.ListFeatureFlags(ctx, FlagFilter{})
+	require.NoError(t, err)
+	require.Len(t, flags, 2)
+	assert.Equal(t, "", flags[0].Description)
+	assert.Equal(t, 0, flags[0].ProjectID)
+	assert.False(t, flags[0].IsActive)
+
+	plan, err := client.Apply(ctx, desired, ApplyOptions{Prune: true, DryRun: true})
+	require.NoError(t, err)
+	assert.Empty(t, plan.Changes, "planning after an apply shows no changes")
+}
+
+func TestApplyClearsSegmentFields(t *testing.T) {
+	srv := newFakeServer(t)
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+	_, err := client.CreateSegment(ctx, SegmentCreate{Name: "beta", Description: "testers", Included: []string{"a"}})
+	require.NoError(t, err)
+
+	desired := DesiredState{Segments: []SegmentSpec{{Name: "beta"}}}
+	result, err := client.Apply(ctx, desired, ApplyOptions{})
+	require.NoError(t, err)
+	require.Len(t, result.Changes, 1)
+
+	segment, err := client.GetSegment(ctx, "beta")
+	require.NoError(t, err)
+	assert.Empty(t, segment.Description)
+	assert.Empty(t, segment.Included)
+
+	plan, err := client.Apply(ctx, desired, ApplyOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Empty(t, plan.Changes)
+}
+
+func TestApplyPrunesSegmentsOnlyWhenListed(t *testing.T) {
+	srv := newFakeServer(t)
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+	_, err := client.CreateSegment(ctx, SegmentCreate{Name: "beta"})
+	require.NoError(t, err)
+
+	plan, err := client.Apply(ctx, DesiredState{}, ApplyOptions{Prune: true, DryRun: true})
+	require.NoError(t, err)
+	assert.Empty(t, plan.Changes)
+
+	plan, err = client.Apply(ctx, DesiredState{Segments: []SegmentSpec{}}, ApplyOptions{Prune: true, DryRun: true})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	assert.Equal(t, Change{Action: ChangeDelete, Kind: KindSegment, Name: "beta"}, plan.Changes[0])
+}
+
+func TestPlanDetectsDrift(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.addFlag(FeatureFlag{Name: "checkout", Environment: "production", IsActive: true})
+	srv.addFlag(FeatureFlag{Name: "search", Environment: "production", Description: "old"})
+	srv.addFlag(FeatureFlag{Name: "stale", Environment: "production"})
+	srv.addFlag(FeatureFlag{Name: "stale", Environment: "staging"})
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+
+	desired := []FlagSpec{
+		{Name: "checkout", Environment: "production", IsActive: true},
+		{Name: "search", Environment: "production"},
+		{Name: "dark-mode", Environment: "production"},
+	}
+	plan, err := client.Plan(ctx, desired)
+	require.NoError(t, err)
+	assert.True(t, plan.HasDrift())
+	assert.Equal(t, []Change{{Action: ChangeCreate, Kind: KindFlag, Name: "dark-mode", Environment: "production", Fields: []FieldChange{
+		{Field: "name", Old: "", New: "dark-mode"}, {Field: "environment", Old: "", New: "production"},
+	}}}, plan.Creates)
+	assert.Equal(t, []Change{{Action: ChangeUpdate, Kind: KindFlag, Name: "search", Environment: "production", ID: 2, Fields: []FieldChange{
+		{Field: "description", Old: "old", New: ""},
+	}}}, plan.Updates)
+	assert.Equal(t, []Change{{Action: ChangeDelete, Kind: KindFlag, Name: "stale", Environment: "production", ID: 3}}, plan.Deletes,
+		"only environments of the specs are compared")
+	assert.Equal(t, []Change{{Action: ChangeNoop, Kind: KindFlag, Name: "checkout", Environment: "production", ID: 1}}, plan.NoOps)
+	assert.Equal(t, []string{"dark-mode", "search", "stale"}, changeNames(plan.Changes()))
+
+	flags, err := client.ListFeatureFlags(ctx, FlagFilter{})
+	require.NoError(t, err)
+	assert.Len(t, flags, 4, "planning changes nothing")
+
+	_, err = client.Apply(ctx, DesiredState{Flags: desired}, ApplyOptions{Prune: true})
+	require.NoError(t, err)
+	plan, err = client.Plan(ctx, desired)
+	require.NoError(t, err)
+	assert.False(t, plan.HasDrift())
+	assert.Len(t, plan.NoOps, 3)
+
+	_, err = client.Plan(ctx, []FlagSpec{{Name: "checkout"}})
+	assert.ErrorContains(t, err, "name and environment are required")
+}
+
+// changeNames returns the names of changes
+func changeNames(changes []Change) []string {
+	names := make([]string, len(changes))
+	for i, change := range changes {
+		names[i] = change.Name
+	}
+	return names
+}
+
+func TestFeatureFlagUpdateFields(t *testing.T) {
+	data, err := FeatureFlagUpdate{Name: "f", Fields: []string{FieldDescription, FieldIsActive, FieldRules, FieldRollout}}.MarshalJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"f","description":"","is_active":false,"rules":[],"rollout":null}`, string(data))
+
+	_, err = FeatureFlagUpdate{Fields: []string{"nope"}}.MarshalJSON()
+	assert.Error(t, err)
+}
+
+func TestApplyWebhooks(t *testing.T) {
+	srv := newFakeServer(t)
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+	_, err := client.CreateWebhook(ctx, WebhookCreate{URL: "https://example.com/old"})
+	require.NoError(t, err)
+	hook, err := client.CreateWebhook(ctx, WebhookCreate{URL: "https://example.com/hook", Secret: "s3cret"})
+	require.NoError(t, err)
+
+	desired := DesiredState{Webhooks: []WebhookSpec{
+		// Filters the events and stops signing
+		{URL: "https://example.com/hook", Events: []WebhookEventType{WebhookFlagToggled}},
+		{URL: "https://example.com/new", Secret: "s3cret", Paused: true},
+	}}
+	result, err := client.Apply(ctx, desired, ApplyOptions{Prune: true})
+	require.NoError(t, err)
+	var actions []string
+	for _, change := range result.Changes {
+		actions = append(actions, string(change.Action)+" "+string(change.Kind)+" "+change.Name)
+	}
+	assert.Equal(t, []string{
+		"create webhook https://example.com/new",
+		"update webhook https://example.com/hook",
+		"delete webhook https://example.com/old",
+	}, actions)
+
+	webhooks, err := client.ListWebhooks(ctx)
+	require.NoError(t, err)
+	require.Len(t, webhooks, 2)
+	assert.Equal(t, Webhook{ID: hook.ID, URL: "https://example.com/hook", Events: []WebhookEventType{WebhookFlagToggled}, IsActive: true}, webhooks[0])
+	assert.False(t, webhooks[1].IsActive, "paused webhooks are paused after creation")
+	assert.True(t, webhooks[1].Signed)
+
+	plan, err := client.Apply(ctx, desired, ApplyOptions{Prune: true, DryRun: true})
+	require.NoError(t, err)
+	assert.Empty(t, plan.Changes, "planning after an apply shows no changes")
+
+	_, err = client.Apply(ctx, DesiredState{Webhooks: []WebhookSpec{{URL: "hook"}}}, ApplyOptions{DryRun: true})
+	assert.ErrorContains(t, err, "invalid webhook spec")
+}
@@ -0,0 +1,142 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// AuditLogEntry represents a change recorded in the audit log
+type AuditLogEntry struct {
+	ID           string          `json:"id"`
+	Time         time.Time       `json:"time"`
+	Actor        string          `json:"actor"`
+	Action       string          `json:"action"`
+	ResourceType string          `json:"resource_type"`
+	ResourceID   string          `json:"resource_id"`
+	ResourceName string          `json:"resource_name,omitempty"`
+	Environment  string          `json:"environment,omitempty"`
+	SourceIP     string          `json:"source_ip,omitempty"`
+	Before       json.RawMessage `json:"before,omitempty"`
+	After        json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditLogQuery selects audit log entries
+type AuditLogQuery struct {
+	// After only returns entries recorded after the entry with this ID
+	After string
+	// Limit caps the number of entries returned; the server default is used when zero
+	Limit int
+}
+
+// defaultAuditPageSize is the page size of ListAuditEvents when the query sets none
+const defaultAuditPageSize = 100
+
+// AuditQuery selects a page of audit events
+type AuditQuery struct {
+	// FlagID only returns the changes of this flag
+	FlagID int
+	// Actor only returns the changes made by this user or API key
+	Actor string
+	// Since and Until bound the time of the changes, inclusive and exclusive
+	Since time.Time
+	Until time.Time
+	// Page is the NextPage of the previous page, empty for the first one
+	Page string
+	// PageSize caps the number of events of a page, 100 when zero
+	PageSize int
+}
+
+// AuditPage is a page of audit events
+type AuditPage struct {
+	Events []AuditLogEntry
+	// NextPage is the Page of the query returning the next page, empty on the last page
+	NextPage string
+}
+
+// ListAuditEvents retrieves a page of the audit events matching query, oldest first:
+//
+//	query := matrixflag.AuditQuery{FlagID: 42, Since: time.Now().AddDate(0, -1, 0)}
+//	for {
+//		page, err := client.ListAuditEvents(ctx, query)
+//		if err != nil {
+//			return err
+//		}
+//		// use page.Events
+//		if page.NextPage == "" {
+//			break
+//		}
+//		query.Page = page.NextPage
+//	}
+func (c *Client) ListAuditEvents(ctx context.Context, query AuditQuery, reqOpts ...RequestOption) (*AuditPage, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if !query.Since.IsZero() && !query.Until.IsZero() && !query.Since.Before(query.Until) {
+		return nil, fmt.Errorf("invalid audit query: since must be before until")
+	}
+	pageSize := query.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultAuditPageSize
+	}
+	params := map[string]string{"limit": strconv.Itoa(pageSize)}
+	if query.FlagID != 0 {
+		params["resource_type"] = "flag"
+		params["resource_id"] = strconv.Itoa(query.FlagID)
+	}
+	if query.Actor != "" {
+		params["actor"] = query.Actor
+	}
+	if !query.Since.IsZero() {
+		params["since"] = query.Since.UTC().Format(time.RFC3339)
+	}
+	if !query.Until.IsZero() {
+		params["until"] = query.Until.UTC().Format(time.RFC3339)
+	}
+	if query.Page != "" {
+		params["after"] = query.Page
+	}
+	entries, err := c.listAuditLog(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// A full page may be followed by more, which start after its last entry
+	page := &AuditPage{Events: entries}
+	if len(entries) == pageSize {
+		page.NextPage = entries[len(entries)-1].ID
+	}
+	return page, nil
+}
+
+// ListAuditLog retrieves audit log entries in the order they were recorded
+func (c *Client) ListAuditLog(ctx context.Context, query AuditLogQuery, reqOpts ...RequestOption) ([]AuditLogEntry, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	params := map[string]string{}
+	if query.After != "" {
+		params["after"] = query.After
+	}
+	if query.Limit > 0 {
+		params["limit"] = strconv.Itoa(query.Limit)
+	}
+	return c.listAuditLog(ctx, params)
+}
+
+// listAuditLog retrieves the audit log entries matching params
+func (c *Client) listAuditLog(ctx context.Context, params map[string]string) ([]AuditLogEntry, error) {
+	respBody, err := c.doRequest(ctx, request{
+		method: "GET",
+		path:   "/api/v1/audit-log/",
+		query:  params,
+		class:  EndpointExport,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []AuditLogEntry
+	if err := json.Unmarshal(respBody, &entries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,52 @@
+package matrixflag
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAuditEventsPages(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		after, _ := strconv.Atoi(r.URL.Query().Get("after"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		entries := []AuditLogEntry{}
+		for id := after + 1; id <= 5 && len(entries) < limit; id++ {
+			entries = append(entries, AuditLogEntry{ID: strconv.Itoa(id), Actor: "jane"})
+		}
+		writeJSON(w, entries)
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+
+	since := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	query := AuditQuery{FlagID: 42, Actor: "jane", Since: since, Until: since.AddDate(0, 1, 0), PageSize: 2}
+	var ids []string
+	for {
+		page, err := client.ListAuditEvents(ctx, query)
+		require.NoError(t, err)
+		for _, event := range page.Events {
+			ids = append(ids, event.ID)
+		}
+		if page.NextPage == "" {
+			break
+		}
+		query.Page = page.NextPage
+	}
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, ids)
+	require.Len(t, queries, 3)
+	assert.Equal(t, "actor=jane&limit=2&resource_id=42&resource_type=flag&since=2024-05-01T00%3A00%3A00Z&until=2024-06-01T00%3A00%3A00Z", queries[0])
+	assert.Contains(t, queries[2], "after=4")
+
+	_, err := client.ListAuditEvents(ctx, AuditQuery{Since: since, Until: since})
+	assert.Error(t, err)
+}
@@ -0,0 +1,52 @@
+package matrixflag
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+)
+
+// DefaultBucketBy is the context attribute bucketed on when a rollout does not configure one
+const DefaultBucketBy = "key"
+
+// bucketScale is the largest value of the 15 hex digit hash prefix used for bucketing
+const bucketScale = float64(0xFFFFFFFFFFFFFFF)
+
+// Bucket deterministically maps a key to a value in [0, 100) using salt. The
+// value is the first 15 hex digits of the SHA-1 hash of salt + "." + key,
+// scaled to [0, 100), so the same key and salt always land in the same bucket,
+// in every process and in every SDK implementing the algorithm. A key is inside
+// a percentage p when its bucket is below p, so raising p only adds keys.
+func Bucket(key, salt string) float64 {
+	sum := sha1.Sum([]byte(salt + "." + key))
+	prefix := hex.EncodeToString(sum[:])[:15]
+	n, _ := strconv.ParseUint(prefix, 16, 64)
+	return float64(n) / bucketScale * 100
+}
+
+// BucketingKey returns the value of the attribute bucketBy, DefaultBucketBy when
+// empty, as a bucketing key. Bucketing by an attribute such as an organization ID
+// gives every context sharing it the same result. It reports false when the
+// attribute is missing or empty.
+func BucketingKey(attributes map[string]any, bucketBy string) (string, bool) {
+	if bucketBy == "" {
+		bucketBy = DefaultBucketBy
+	}
+	var key string
+	switch v := attributes[bucketBy].(type) {
+	case nil:
+		return "", false
+	case string:
+		key = v
+	case int:
+		key = strconv.Itoa(v)
+	case int64:
+		key = strconv.FormatInt(v, 10)
+	case float64:
+		key = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		key = fmt.Sprint(v)
+	}
+	return key, key != ""
+}
@@ -0,0 +1,66 @@
+package matrixflag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The vectors are the first 15 hex digits of SHA-1(salt + "." + key) scaled
+// to [0, 100); every SDK implementing the algorithm must reproduce them.
+func TestBucketVectors(t *testing.T) {
+	tests := []struct {
+		key, salt string
+		want      float64
+	}{
+		{"user-1", "flag.1", 50.91116029359005},
+		{"user-2", "flag.1", 26.94622272884335},
+		{"user-1", "rollout.1", 30.95801118530652},
+		{"user-2", "rollout.1", 39.19984656623321},
+		{"user-2", "variation.1", 23.156904761325652},
+		{"user-4", "variation.1", 85.61896843441613},
+		{"user-3", "holdout.q3", 48.23564021499113},
+		{"user-4", "holdout.q3", 53.082244298795075},
+		{"user-4", "layer.checkout", 8.350678950097144},
+		{"user-2", "traffic.1", 39.46544163021661},
+		{"org-42", "exp", 74.01991573470656},
+	}
+	for _, tt := range tests {
+		t.Run(tt.salt+"/"+tt.key, func(t *testing.T) {
+			assert.InDelta(t, tt.want, Bucket(tt.key, tt.salt), 1e-9)
+		})
+	}
+}
+
+func TestBucketingKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes map[string]any
+		bucketBy   string
+		want       string
+		ok         bool
+	}{
+		{"default attribute", map[string]any{"key": "user-1"}, "", "user-1", true},
+		{"custom attribute", map[string]any{"key": "user-1", "org": "acme"}, "org", "acme", true},
+		{"integer", map[string]any{"org": 42}, "org", "42", true},
+		{"float", map[string]any{"org": 42.0}, "org", "42", true},
+		{"missing", map[string]any{"key": "user-1"}, "org", "", false},
+		{"empty", map[string]any{"key": ""}, "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, ok := BucketingKey(tt.attributes, tt.bucketBy)
+			assert.Equal(t, tt.want, key)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
+func TestBucketIsMonotonicInPercentage(t *testing.T) {
+	flag := FeatureFlag{ID: 1, Rollout: &PercentageRollout{Percentage: 35}}
+	assert.True(t, flag.InRollout("user-1"))
+	assert.False(t, flag.InRollout("user-2"))
+	flag.Rollout.Percentage = 40
+	assert.True(t, flag.InRollout("user-1"), "raising the percentage keeps keys in")
+	assert.True(t, flag.InRollout("user-2"))
+}
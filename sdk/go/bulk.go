@@ -0,0 +1,178 @@
+package matrixflag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// bulkWorkers is the number of concurrent requests made by bulk operations
+// falling back to individual calls
+const bulkWorkers = 8
+
+// BulkUpdate is the update of one flag within BulkUpdateFlags
+type BulkUpdate struct {
+	FlagID int               `json:"flag_id"`
+	Update FeatureFlagUpdate `json:"update"`
+}
+
+// BulkError reports the flags that failed to change when a bulk operation fell
+// back to individual calls, which unlike a bulk request are not all-or-nothing
+type BulkError struct {
+	// Errors holds the error of each failed operation by its index in the call
+	Errors map[int]error
+	// Total is the number of operations of the call
+	Total int
+}
+
+func (e *BulkError) Error() string {
+	indexes := e.indexes()
+	return fmt.Sprintf("%d of %d bulk operations failed, first operation %d: %v", len(indexes), e.Total, indexes[0], e.Errors[indexes[0]])
+}
+
+// Unwrap returns the errors of the failed operations, in order
+func (e *BulkError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, i := range e.indexes() {
+		errs = append(errs, e.Errors[i])
+	}
+	return errs
+}
+
+// indexes returns the indexes of the failed operations, in order
+func (e *BulkError) indexes() []int {
+	indexes := make([]int, 0, len(e.Errors))
+	for i := range e.Errors {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// BulkCreateFlags creates flags in a single request, returning them in order.
+// When the server has no bulk endpoint the flags are created with concurrent
+// CreateFeatureFlag calls; flags that fail to create are then reported by a
+// *BulkError and left zero in the result.
+func (c *Client) BulkCreateFlags(ctx context.Context, flags []FeatureFlagCreate, reqOpts ...RequestOption) ([]FeatureFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	qualified := make([]FeatureFlagCreate, len(flags))
+	for i, flag := range flags {
+		if err := flag.validate(); err != nil {
+			return nil, fmt.Errorf("invalid bulk operation %d: %w", i, err)
+		}
+		qualified[i] = c.qualifyCreate(flag)
+	}
+	return c.bulkRequest(ctx, request{
+		method: "POST",
+		path:   "/api/v1/feature-flags/bulk",
+		class:  EndpointBulk,
+		body:   map[string]any{"flags": qualified},
+	}, len(flags), func(ctx context.Context, i int) (*FeatureFlag, error) {
+		return c.CreateFeatureFlag(ctx, flags[i])
+	})
+}
+
+// BulkUpdateFlags updates flags in a single request, returning them in the
+// order of updates. When the server has no bulk endpoint the flags are updated
+// with concurrent UpdateFeatureFlag calls, as by BulkCreateFlags.
+func (c *Client) BulkUpdateFlags(ctx context.Context, updates []BulkUpdate, reqOpts ...RequestOption) ([]FeatureFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	qualified := make([]BulkUpdate, len(updates))
+	seen := make(map[int]bool, len(updates))
+	for i, u := range updates {
+		if err := u.Update.validate(); err != nil {
+			return nil, fmt.Errorf("invalid bulk operation %d: %w", i, err)
+		}
+		if seen[u.FlagID] {
+			return nil, fmt.Errorf("invalid bulk operation %d: flag %d appears more than once", i, u.FlagID)
+		}
+		seen[u.FlagID] = true
+		qualified[i] = BulkUpdate{FlagID: u.FlagID, Update: c.qualifyUpdate(u.Update)}
+	}
+	return c.bulkRequest(ctx, request{
+		method: "PUT",
+		path:   "/api/v1/feature-flags/bulk",
+		class:  EndpointBulk,
+		body:   map[string]any{"updates": qualified},
+	}, len(updates), func(ctx context.Context, i int) (*FeatureFlag, error) {
+		return c.UpdateFeatureFlag(ctx, updates[i].FlagID, updates[i].Update)
+	})
+}
+
+// BulkToggle switches flags on or off in a single request, returning them in
+// the order of ids. When the server has no bulk endpoint each flag is updated
+// with a concurrent UpdateFeatureFlag call, as by BulkCreateFlags.
+func (c *Client) BulkToggle(ctx context.Context, ids []int, active bool, reqOpts ...RequestOption) ([]FeatureFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.bulkRequest(ctx, request{
+		method: "POST",
+		path:   "/api/v1/feature-flags/bulk/state",
+		class:  EndpointBulk,
+		body:   map[string]any{"flag_ids": ids, "is_active": active},
+	}, len(ids), func(ctx context.Context, i int) (*FeatureFlag, error) {
+		return c.UpdateFeatureFlag(ctx, ids[i], FeatureFlagUpdate{IsActive: active, Fields: FieldMask{FieldIsActive}})
+	})
+}
+
+// bulkRequest performs a bulk request of n operations, or calls individual for
+// each of them when the server lacks the bulk endpoint. Once a server is found
+// to lack it, later bulk operations of the client go straight to individual calls.
+func (c *Client) bulkRequest(ctx context.Context, req request, n int, individual func(ctx context.Context, i int) (*FeatureFlag, error)) ([]FeatureFlag, error) {
+	if n == 0 {
+		return []FeatureFlag{}, nil
+	}
+	if !c.noBulk.Load() {
+		flags, err := c.flagsRequest(ctx, req)
+		if !bulkUnsupported(err) {
+			return flags, err
+		}
+		c.noBulk.Store(true)
+	}
+
+	flags := make([]FeatureFlag, n)
+	var mu sync.Mutex
+	errs := make(map[int]error)
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(bulkWorkers, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				flag, err := individual(ctx, i)
+				mu.Lock()
+				if err != nil {
+					errs[i] = err
+				} else {
+					flags[i] = *flag
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	if len(errs) > 0 {
+		return flags, &BulkError{Errors: errs, Total: n}
+	}
+	return flags, nil
+}
+
+// bulkUnsupported reports whether a bulk request failed because the server has no bulk endpoint
+func bulkUnsupported(err error) bool {
+	var apiErr APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return true
+	}
+	return false
+}
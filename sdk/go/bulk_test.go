@@ -0,0 +1,92 @@
+package matrixflag
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkOperations(t *testing.T) {
+	var requests, bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		bodies = append(bodies, string(body))
+		writeJSON(w, []FeatureFlag{{ID: 1, Name: "team.checkout"}, {ID: 2, Name: "team.search"}})
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil, WithNamespace("team"))
+	ctx := context.Background()
+
+	flags, err := client.BulkCreateFlags(ctx, []FeatureFlagCreate{
+		{Name: "checkout", Environment: "production"},
+		{Name: "search", Environment: "production"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "checkout", flags[0].Name)
+	_, err = client.BulkUpdateFlags(ctx, []BulkUpdate{{FlagID: 1, Update: FeatureFlagUpdate{Name: "checkout-v2"}}})
+	require.NoError(t, err)
+	_, err = client.BulkToggle(ctx, []int{1, 2}, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"POST /api/v1/feature-flags/bulk",
+		"PUT /api/v1/feature-flags/bulk",
+		"POST /api/v1/feature-flags/bulk/state",
+	}, requests)
+	assert.JSONEq(t, `{"flags":[{"name":"team.checkout","is_active":false,"environment":"production"},{"name":"team.search","is_active":false,"environment":"production"}]}`, bodies[0])
+	assert.JSONEq(t, `{"updates":[{"flag_id":1,"update":{"name":"team.checkout-v2"}}]}`, bodies[1])
+	assert.JSONEq(t, `{"flag_ids":[1,2],"is_active":false}`, bodies[2])
+
+	_, err = client.BulkUpdateFlags(ctx, []BulkUpdate{{FlagID: 1}, {FlagID: 1}})
+	assert.ErrorContains(t, err, "appears more than once")
+	_, err = client.BulkCreateFlags(ctx, []FeatureFlagCreate{{Name: "a"}, {Name: "b", Tags: []string{"Bad"}}})
+	assert.ErrorContains(t, err, "invalid bulk operation 1")
+	flags, err = client.BulkToggle(ctx, nil, true)
+	require.NoError(t, err)
+	assert.Empty(t, flags)
+	assert.Len(t, requests, 3, "invalid and empty operations are not sent")
+}
+
+func TestBulkOperationsFallBackToIndividualCalls(t *testing.T) {
+	srv := newFakeServer(t)
+	checkout := srv.addFlag(FeatureFlag{Name: "checkout", Environment: "production"})
+	search := srv.addFlag(FeatureFlag{Name: "search", Environment: "production"})
+	client := NewClient(srv.URL, "key", nil, WithRetries(0, 0, 0))
+	ctx := context.Background()
+
+	flags, err := client.BulkToggle(ctx, []int{checkout.ID, search.ID}, true)
+	require.NoError(t, err)
+	assert.Equal(t, []int{checkout.ID, search.ID}, []int{flags[0].ID, flags[1].ID}, "results keep the order of the call")
+	assert.True(t, flags[0].IsActive && flags[1].IsActive)
+
+	flags, err = client.BulkUpdateFlags(ctx, []BulkUpdate{
+		{FlagID: checkout.ID, Update: FeatureFlagUpdate{Description: "new checkout"}},
+		{FlagID: 99, Update: FeatureFlagUpdate{Description: "missing"}},
+	})
+	var bulkErr *BulkError
+	require.ErrorAs(t, err, &bulkErr)
+	assert.Equal(t, 2, bulkErr.Total)
+	assert.Contains(t, bulkErr.Errors, 1)
+	assert.ErrorContains(t, err, "1 of 2 bulk operations failed, first operation 1")
+	assert.Equal(t, "new checkout", flags[0].Description, "the other updates are applied")
+	assert.Zero(t, flags[1].ID)
+
+	created, err := client.BulkCreateFlags(ctx, []FeatureFlagCreate{{Name: "billing", Environment: "production"}})
+	require.NoError(t, err)
+	assert.Equal(t, "billing", created[0].Name)
+	writes := srv.writes()
+	assert.Equal(t, "POST /api/v1/feature-flags/bulk/state", writes[0])
+	assert.ElementsMatch(t, []string{
+		"PUT /api/v1/feature-flags/1",
+		"PUT /api/v1/feature-flags/2",
+		"PUT /api/v1/feature-flags/1",
+		"PUT /api/v1/feature-flags/99",
+		"POST /api/v1/feature-flags/",
+	}, writes[1:], "the bulk endpoint is not tried again once missing")
+}
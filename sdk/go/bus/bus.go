@@ -0,0 +1,141 @@
+// Package bus publishes Matrix Flag flag changes to message buses such as NATS
+// or Kafka, so that event-driven systems, including consumers not written in
+// Go, can react to them.
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+// Message is a serialized flag change ready to be published
+type Message struct {
+	Topic string
+	// Key identifies the flag, so partitioned buses keep the changes of a flag in order
+	Key         []byte
+	Value       []byte
+	ContentType string
+}
+
+// Publisher sends messages to a bus
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+}
+
+// PublisherFunc adapts an ordinary function to the Publisher interface,
+// which is the simplest way to plug in a Kafka producer:
+//
+//	bus.PublisherFunc(func(ctx context.Context, msg bus.Message) error {
+//		return writer.WriteMessages(ctx, kafka.Message{Topic: msg.Topic, Key: msg.Key, Value: msg.Value})
+//	})
+type PublisherFunc func(ctx context.Context, msg Message) error
+
+// Publish calls f(ctx, msg)
+func (f PublisherFunc) Publish(ctx context.Context, msg Message) error {
+	return f(ctx, msg)
+}
+
+// NATSConn is the part of *nats.Conn used by NATSPublisher
+type NATSConn interface {
+	Publish(subject string, data []byte) error
+}
+
+// NATSPublisher publishes messages to NATS subjects named after their topic
+type NATSPublisher struct {
+	Conn NATSConn
+}
+
+// Publish implements Publisher
+func (p NATSPublisher) Publish(_ context.Context, msg Message) error {
+	return p.Conn.Publish(msg.Topic, msg.Value)
+}
+
+// Event is the serialized form of a flag change
+type Event struct {
+	Type        matrixflag.FlagChangeType `json:"type"`
+	FlagID      int                       `json:"flag_id"`
+	Flag        string                    `json:"flag"`
+	Environment string                    `json:"environment"`
+	Old         *matrixflag.FeatureFlag   `json:"old,omitempty"`
+	New         *matrixflag.FeatureFlag   `json:"new,omitempty"`
+	Time        time.Time                 `json:"time"`
+}
+
+// NewEvent creates the event of a flag change
+func NewEvent(change matrixflag.FlagChange) Event {
+	flag := change.Flag()
+	return Event{
+		Type:        change.Type,
+		FlagID:      flag.ID,
+		Flag:        flag.Name,
+		Environment: flag.Environment,
+		Old:         change.Old,
+		New:         change.New,
+		Time:        change.Time,
+	}
+}
+
+// Serializer encodes events into message values
+type Serializer interface {
+	Serialize(e Event) ([]byte, error)
+	ContentType() string
+}
+
+// JSONSerializer encodes events as plain JSON
+type JSONSerializer struct{}
+
+// Serialize implements Serializer
+func (JSONSerializer) Serialize(e Event) ([]byte, error) {
+	return json.Marshal(e)
+}
+
+// ContentType implements Serializer
+func (JSONSerializer) ContentType() string {
+	return "application/json"
+}
+
+// CloudEventsSerializer encodes events as CloudEvents 1.0 in structured JSON mode
+type CloudEventsSerializer struct {
+	// Source is the CloudEvents source attribute, "matrixflag" by default
+	Source string
+}
+
+// cloudEvent is a CloudEvents 1.0 event in structured JSON mode
+type cloudEvent struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            Event     `json:"data"`
+}
+
+// Serialize implements Serializer
+func (s CloudEventsSerializer) Serialize(e Event) ([]byte, error) {
+	source := s.Source
+	if source == "" {
+		source = "matrixflag"
+	}
+	return json.Marshal(cloudEvent{
+		SpecVersion:     "1.0",
+		ID:              uuid.NewString(),
+		Source:          source,
+		Type:            fmt.Sprintf("io.matrixflag.flag.%s", e.Type),
+		Subject:         e.Environment + "/" + e.Flag,
+		Time:            e.Time,
+		DataContentType: "application/json",
+		Data:            e,
+	})
+}
+
+// ContentType implements Serializer
+func (CloudEventsSerializer) ContentType() string {
+	return "application/cloudevents+json"
+}
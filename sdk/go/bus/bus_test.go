@@ -0,0 +1,145 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/matrixflag/sdk/matrixflagtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingPublisher keeps the messages it publishes
+type recordingPublisher struct {
+	mu       sync.Mutex
+	messages []Message
+	err      error
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, msg Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.messages = append(p.messages, msg)
+	return p.err
+}
+
+func (p *recordingPublisher) published() []Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Message(nil), p.messages...)
+}
+
+// natsConn records the subjects published to
+type natsConn struct {
+	subjects []string
+}
+
+func (c *natsConn) Publish(subject string, _ []byte) error {
+	c.subjects = append(c.subjects, subject)
+	return nil
+}
+
+var changeTime = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func toggleChange() matrixflag.FlagChange {
+	old := &matrixflag.FeatureFlag{ID: 7, Name: "checkout", Environment: "production"}
+	updated := *old
+	updated.IsActive = true
+	return matrixflag.FlagChange{Type: matrixflag.FlagUpdated, Old: old, New: &updated, Time: changeTime}
+}
+
+func TestConnectorPublish(t *testing.T) {
+	publisher := &recordingPublisher{}
+	connector := New(nil, publisher, Options{Topic: "flags.{environment}.{flag}.{type}"})
+	require.NoError(t, connector.Publish(context.Background(), toggleChange()))
+
+	messages := publisher.published()
+	require.Len(t, messages, 1)
+	msg := messages[0]
+	assert.Equal(t, "flags.production.checkout.updated", msg.Topic)
+	assert.Equal(t, []byte("7"), msg.Key, "messages are keyed by flag ID")
+	assert.Equal(t, "application/json", msg.ContentType)
+
+	var event Event
+	require.NoError(t, json.Unmarshal(msg.Value, &event))
+	assert.Equal(t, NewEvent(toggleChange()), event)
+	assert.False(t, event.Old.IsActive)
+	assert.True(t, event.New.IsActive)
+}
+
+func TestConnectorPublishError(t *testing.T) {
+	publisher := &recordingPublisher{err: errors.New("broker unavailable")}
+	err := New(nil, publisher, Options{}).Publish(context.Background(), toggleChange())
+	assert.EqualError(t, err, "failed to publish change of flag checkout to matrixflag.flags.production: broker unavailable")
+}
+
+func TestCloudEventsSerializer(t *testing.T) {
+	serializer := CloudEventsSerializer{Source: "flags.example.com"}
+	assert.Equal(t, "application/cloudevents+json", serializer.ContentType())
+	data, err := serializer.Serialize(NewEvent(toggleChange()))
+	require.NoError(t, err)
+
+	var event cloudEvent
+	require.NoError(t, json.Unmarshal(data, &event))
+	assert.NotEmpty(t, event.ID)
+	event.ID = ""
+	assert.Equal(t, cloudEvent{
+		SpecVersion:     "1.0",
+		Source:          "flags.example.com",
+		Type:            "io.matrixflag.flag.updated",
+		Subject:         "production/checkout",
+		Time:            changeTime,
+		DataContentType: "application/json",
+		Data:            NewEvent(toggleChange()),
+	}, event)
+
+	data, err = CloudEventsSerializer{}.Serialize(NewEvent(toggleChange()))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &event))
+	assert.Equal(t, "matrixflag", event.Source)
+}
+
+func TestNATSPublisher(t *testing.T) {
+	conn := &natsConn{}
+	connector := New(nil, NATSPublisher{Conn: conn}, Options{})
+	require.NoError(t, connector.Publish(context.Background(), toggleChange()))
+	assert.Equal(t, []string{"matrixflag.flags.production"}, conn.subjects)
+}
+
+func TestConnectorRun(t *testing.T) {
+	td := matrixflagtest.NewTestDataSource()
+	td.Set("checkout", false)
+	clock := matrixflagtest.NewFakeClock(changeTime)
+	publisher := &recordingPublisher{}
+	var errs []error
+	connector := New(td.Client(matrixflag.WithClock(clock)), publisher, Options{
+		Topic:   "{flag}.{type}",
+		OnError: func(err error) { errs = append(errs, err) },
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- connector.Run(ctx) }()
+
+	// poll waits for the watch to wait for its next poll, then changes the flags and triggers it
+	poll := func(change func()) {
+		require.Eventually(t, func() bool { return clock.Waiters() == 1 }, time.Second, time.Millisecond)
+		change()
+		clock.Advance(matrixflag.DefaultWatchInterval)
+	}
+	poll(func() { td.Set("checkout", true) })
+	require.Eventually(t, func() bool { return len(publisher.published()) == 1 }, time.Second, time.Millisecond)
+	poll(func() { td.Set("search", true) })
+	require.Eventually(t, func() bool { return len(publisher.published()) == 2 }, time.Second, time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	messages := publisher.published()
+	assert.Equal(t, "checkout.updated", messages[0].Topic)
+	assert.Equal(t, "search.created", messages[1].Topic)
+	assert.Empty(t, errs)
+}
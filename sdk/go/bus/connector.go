@@ -0,0 +1,91 @@
+package bus
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+// DefaultTopic is the default topic template
+const DefaultTopic = "matrixflag.flags.{environment}"
+
+// Options configures a Connector
+type Options struct {
+	// Watch configures the watch observing flag changes
+	Watch matrixflag.WatchOptions
+	// Topic is the topic template; {environment}, {flag} and {type} are replaced
+	// with the values of each change. DefaultTopic by default.
+	Topic string
+	// Serializer encodes events, JSONSerializer by default
+	Serializer Serializer
+	// OnError is called when a change cannot be serialized or published; the connector keeps running
+	OnError func(error)
+}
+
+// Connector publishes every flag change observed by a watch to a bus
+type Connector struct {
+	client    *matrixflag.Client
+	publisher Publisher
+	opts      Options
+}
+
+// New creates a new Connector
+func New(client *matrixflag.Client, publisher Publisher, opts Options) *Connector {
+	if opts.Topic == "" {
+		opts.Topic = DefaultTopic
+	}
+	if opts.Serializer == nil {
+		opts.Serializer = JSONSerializer{}
+	}
+	return &Connector{
+		client:    client,
+		publisher: publisher,
+		opts:      opts,
+	}
+}
+
+// Run watches flags and publishes their changes until ctx is canceled.
+// It returns an error only when the initial flag state cannot be fetched.
+func (c *Connector) Run(ctx context.Context) error {
+	changes, err := c.client.Watch(ctx, c.opts.Watch)
+	if err != nil {
+		return err
+	}
+	for change := range changes {
+		if err := c.Publish(ctx, change); err != nil && c.opts.OnError != nil {
+			c.opts.OnError(err)
+		}
+	}
+	return nil
+}
+
+// Publish serializes and publishes a single flag change
+func (c *Connector) Publish(ctx context.Context, change matrixflag.FlagChange) error {
+	event := NewEvent(change)
+	value, err := c.opts.Serializer.Serialize(event)
+	if err != nil {
+		return fmt.Errorf("failed to serialize change of flag %s: %w", event.Flag, err)
+	}
+	msg := Message{
+		Topic:       c.topic(event),
+		Key:         []byte(strconv.Itoa(event.FlagID)),
+		Value:       value,
+		ContentType: c.opts.Serializer.ContentType(),
+	}
+	if err := c.publisher.Publish(ctx, msg); err != nil {
+		return fmt.Errorf("failed to publish change of flag %s to %s: %w", event.Flag, msg.Topic, err)
+	}
+	return nil
+}
+
+// topic expands the topic template for an event
+func (c *Connector) topic(e Event) string {
+	return strings.NewReplacer(
+		"{environment}", e.Environment,
+		"{flag}", e.Flag,
+		"{type}", string(e.Type),
+	).Replace(c.opts.Topic)
+}
@@ -0,0 +1,144 @@
+package matrixflag
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Response cache defaults, see CacheOptions
+const (
+	DefaultCacheTTL        = 10 * time.Minute
+	DefaultCacheMaxEntries = 256
+)
+
+// CacheOptions configures the response cache of a client
+type CacheOptions struct {
+	// TTL is how long a response is kept after it was last downloaded or
+	// revalidated, DefaultCacheTTL by default
+	TTL time.Duration
+	// MaxEntries is the number of responses kept, the least recently used being
+	// evicted first, DefaultCacheMaxEntries by default
+	MaxEntries int
+}
+
+// WithResponseCache caches the GET responses of the API carrying an ETag, by
+// path and query. Later requests for them send If-None-Match, and a 304 Not
+// Modified answer is served from the cache, so polling loops do not download
+// unchanged flag lists and rulesets again. Responses are always revalidated,
+// so the cache never serves stale data.
+func WithResponseCache(opts CacheOptions) ClientOption {
+	return func(c *Client) {
+		c.cache.Store(newResponseCache(opts))
+	}
+}
+
+// enableResponseCache gives the client a response cache with the default
+// options, unless WithResponseCache configured one
+func (c *Client) enableResponseCache() {
+	c.cache.CompareAndSwap(nil, newResponseCache(CacheOptions{}))
+}
+
+// responseCache is a least recently used cache of GET responses by URL
+type responseCache struct {
+	opts    CacheOptions
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// lru holds the entries, the most recently used first
+	lru *list.List
+}
+
+// cacheEntry is a cached response
+type cacheEntry struct {
+	key     string
+	etag    string
+	body    []byte
+	expires time.Time
+}
+
+// newResponseCache creates an empty response cache, filling in the defaults of opts
+func newResponseCache(opts CacheOptions) *responseCache {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultCacheTTL
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = DefaultCacheMaxEntries
+	}
+	return &responseCache{opts: opts, entries: make(map[string]*list.Element), lru: list.New()}
+}
+
+// get returns the unexpired entry of a URL
+func (rc *responseCache) get(key string, now time.Time) (*cacheEntry, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	elem, ok := rc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if !now.Before(entry.expires) {
+		rc.lru.Remove(elem)
+		delete(rc.entries, key)
+		return nil, false
+	}
+	rc.lru.MoveToFront(elem)
+	return entry, true
+}
+
+// put stores the response of a URL, evicting the least recently used entries over MaxEntries
+func (rc *responseCache) put(key, etag string, body []byte, now time.Time) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	entry := &cacheEntry{key: key, etag: etag, body: body, expires: now.Add(rc.opts.TTL)}
+	if elem, ok := rc.entries[key]; ok {
+		elem.Value = entry
+		rc.lru.MoveToFront(elem)
+		return
+	}
+	rc.entries[key] = rc.lru.PushFront(entry)
+	for rc.lru.Len() > rc.opts.MaxEntries {
+		oldest := rc.lru.Back()
+		rc.lru.Remove(oldest)
+		delete(rc.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// revalidated extends the lifetime of an entry confirmed by a 304 answer
+func (rc *responseCache) revalidated(entry *cacheEntry, now time.Time) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	entry.expires = now.Add(rc.opts.TTL)
+}
+
+// cachedRequest returns the cache entry of a GET request and sets its
+// If-None-Match header, nil without a cache or an entry
+func (c *Client) cachedRequest(req *http.Request) *cacheEntry {
+	cache := c.cache.Load()
+	if cache == nil || req.Method != http.MethodGet {
+		return nil
+	}
+	entry, ok := cache.get(req.URL.String(), c.clock.Now())
+	if !ok {
+		return nil
+	}
+	req.Header.Set("If-None-Match", entry.etag)
+	return entry
+}
+
+// cacheResponse returns the body to serve for the response of a GET request:
+// the cached body on 304 Not Modified, or body, cached when it has an ETag
+func (c *Client) cacheResponse(req *http.Request, entry *cacheEntry, resp *http.Response, body []byte) []byte {
+	cache := c.cache.Load()
+	if cache == nil || req.Method != http.MethodGet {
+		return body
+	}
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		cache.revalidated(entry, c.clock.Now())
+		return entry.body
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" && resp.StatusCode == http.StatusOK {
+		cache.put(req.URL.String(), etag, body, c.clock.Now())
+	}
+	return body
+}
@@ -0,0 +1,87 @@
+package matrixflag
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseCache(t *testing.T) {
+	etag := `"v1"`
+	flags := []FeatureFlag{{ID: 1, Name: "checkout", Environment: "production"}}
+	var full, notModified int
+	var ifNoneMatch []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+		if r.Method == http.MethodGet && r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		if r.Method != http.MethodGet {
+			writeJSON(w, flags[0])
+			return
+		}
+		w.Header().Set("ETag", etag)
+		writeJSON(w, flags)
+	}))
+	defer srv.Close()
+	clock := &fixedClock{time.Unix(1700000000, 0)}
+	client := NewClient(srv.URL, "key", nil, WithClock(clock), WithResponseCache(CacheOptions{TTL: time.Minute, MaxEntries: 1}))
+	ctx := context.Background()
+	production := FlagFilter{Environment: "production"}
+
+	for i := 0; i < 3; i++ {
+		got, err := client.ListFeatureFlags(ctx, production)
+		require.NoError(t, err)
+		assert.Equal(t, "checkout", got[0].Name, "unchanged responses are served from the cache")
+	}
+	assert.Equal(t, 1, full)
+	assert.Equal(t, 2, notModified)
+
+	etag = `"v2"`
+	flags = []FeatureFlag{{ID: 1, Name: "checkout-v2", Environment: "production"}}
+	got, err := client.ListFeatureFlags(ctx, production)
+	require.NoError(t, err)
+	assert.Equal(t, "checkout-v2", got[0].Name, "changed responses replace the cached one")
+
+	_, err = client.ListFeatureFlags(ctx, FlagFilter{Environment: "staging"})
+	require.NoError(t, err)
+	ifNoneMatch = nil
+	_, err = client.ListFeatureFlags(ctx, production)
+	require.NoError(t, err)
+	assert.Equal(t, []string{""}, ifNoneMatch, "the other query evicted the entry over MaxEntries")
+
+	clock.now = clock.now.Add(time.Minute)
+	ifNoneMatch = nil
+	_, err = client.ListFeatureFlags(ctx, production)
+	require.NoError(t, err)
+	assert.Equal(t, []string{""}, ifNoneMatch, "expired entries are downloaded again")
+
+	ifNoneMatch = nil
+	_, err = client.ToggleFeatureFlag(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{""}, ifNoneMatch, "only GET requests are cached")
+}
+
+func TestNoResponseCacheByDefault(t *testing.T) {
+	var ifNoneMatch []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+		w.Header().Set("ETag", `"v1"`)
+		writeJSON(w, []FeatureFlag{})
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil)
+	for i := 0; i < 2; i++ {
+		_, err := client.ListFeatureFlags(context.Background(), FlagFilter{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"", ""}, ifNoneMatch)
+}
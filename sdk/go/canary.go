@@ -0,0 +1,130 @@
+package matrixflag
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// DefaultCanaryHeader is the request header carrying the canary decision between services
+const DefaultCanaryHeader = "X-MatrixFlag-Canary"
+
+// Canary assignment reasons
+const (
+	CanaryReasonHeader     = "header"
+	CanaryReasonList       = "list"
+	CanaryReasonPercentage = "percentage"
+	CanaryReasonNone       = "none"
+)
+
+// CanaryCohort deterministically assigns callers to a canary cohort.
+// Callers are assigned, in order, by an incoming header set upstream (for
+// example by the load balancer routing to canary instances), by an explicit
+// key list, or by a stable percentage of keys.
+type CanaryCohort struct {
+	// Name identifies the cohort and salts the percentage bucketing
+	Name string
+	// Header is the request header carrying an upstream decision, DefaultCanaryHeader by default
+	Header string
+	// Keys are always assigned to the canary
+	Keys []string
+	// Percentage of the remaining keys assigned to the canary, between 0 and 100
+	Percentage float64
+}
+
+// CanaryDecision is the result of a canary assignment
+type CanaryDecision struct {
+	Cohort   string  `json:"cohort"`
+	InCanary bool    `json:"in_canary"`
+	Reason   string  `json:"reason"`
+	Bucket   float64 `json:"bucket,omitempty"`
+}
+
+// header returns the header name of the cohort
+func (c CanaryCohort) header() string {
+	if c.Header != "" {
+		return c.Header
+	}
+	return DefaultCanaryHeader
+}
+
+// Assign decides whether the caller identified by key belongs to the canary.
+// An upstream decision in h takes precedence; h may be nil.
+func (c CanaryCohort) Assign(key string, h http.Header) CanaryDecision {
+	d := CanaryDecision{Cohort: c.Name}
+	if h != nil {
+		if v := h.Get(c.header()); v != "" {
+			if inCanary, err := strconv.ParseBool(v); err == nil {
+				d.InCanary = inCanary
+				d.Reason = CanaryReasonHeader
+				return d
+			}
+		}
+	}
+	for _, k := range c.Keys {
+		if k == key {
+			d.InCanary = true
+			d.Reason = CanaryReasonList
+			return d
+		}
+	}
+	if key != "" && c.Percentage > 0 {
+		d.Bucket = Bucket(key, c.Name)
+		if d.Bucket < c.Percentage {
+			d.InCanary = true
+			d.Reason = CanaryReasonPercentage
+			return d
+		}
+	}
+	d.Reason = CanaryReasonNone
+	return d
+}
+
+// SetHeader propagates the decision on outgoing requests, so downstream services (and
+// infrastructure routing on the same header) make the same decision
+func (c CanaryCohort) SetHeader(h http.Header, d CanaryDecision) {
+	h.Set(c.header(), strconv.FormatBool(d.InCanary))
+}
+
+// Attributes returns the decision as targeting attributes, so flag rules can match canary traffic
+func (d CanaryDecision) Attributes() map[string]any {
+	return map[string]any{
+		"canary":        d.InCanary,
+		"canary_cohort": d.Cohort,
+	}
+}
+
+// canaryContextKey is the context key of canary decisions
+type canaryContextKey struct{}
+
+// WithCanaryDecision returns a copy of ctx carrying the canary decision
+func WithCanaryDecision(ctx context.Context, d CanaryDecision) context.Context {
+	return context.WithValue(ctx, canaryContextKey{}, d)
+}
+
+// CanaryFromContext returns the canary decision stored in ctx
+func CanaryFromContext(ctx context.Context) (CanaryDecision, bool) {
+	d, ok := ctx.Value(canaryContextKey{}).(CanaryDecision)
+	return d, ok
+}
+
+// CanaryMiddleware assigns every request to the cohort and stores the decision in the request context.
+// key extracts the caller key, such as a user ID or session cookie; the remote address is used when nil.
+func CanaryMiddleware(cohort CanaryCohort, key func(*http.Request) string) func(http.Handler) http.Handler {
+	if key == nil {
+		key = func(r *http.Request) string {
+			host, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				return r.RemoteAddr
+			}
+			return host
+		}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			d := cohort.Assign(key(r), r.Header)
+			next.ServeHTTP(w, r.WithContext(WithCanaryDecision(r.Context(), d)))
+		})
+	}
+}
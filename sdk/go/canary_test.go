@@ -0,0 +1,105 @@
+package matrixflag
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanaryCohortAssign(t *testing.T) {
+	cohort := CanaryCohort{Name: "checkout-v2", Keys: []string{"qa-user"}, Percentage: 20}
+
+	assert.Equal(t, CanaryDecision{Cohort: "checkout-v2", InCanary: true, Reason: CanaryReasonList}, cohort.Assign("qa-user", nil))
+
+	h := http.Header{}
+	h.Set(DefaultCanaryHeader, "false")
+	assert.Equal(t, CanaryDecision{Cohort: "checkout-v2", Reason: CanaryReasonHeader}, cohort.Assign("qa-user", h),
+		"an upstream decision takes precedence over the key list")
+	h.Set(DefaultCanaryHeader, "maybe")
+	assert.Equal(t, CanaryReasonList, cohort.Assign("qa-user", h).Reason, "an invalid header is ignored")
+
+	custom := CanaryCohort{Name: "checkout-v2", Header: "X-Canary"}
+	h = http.Header{}
+	h.Set("X-Canary", "true")
+	assert.True(t, custom.Assign("user-1", h).InCanary)
+
+	assert.Equal(t, CanaryDecision{Cohort: "checkout-v2", Reason: CanaryReasonNone}, cohort.Assign("", nil), "callers without a key are not bucketed")
+	assert.Equal(t, CanaryReasonNone, CanaryCohort{Name: "off"}.Assign("user-1", nil).Reason)
+}
+
+func TestCanaryCohortStable(t *testing.T) {
+	cohort := CanaryCohort{Name: "checkout-v2", Percentage: 20}
+	in := 0
+	for i := 0; i < 5000; i++ {
+		key := fmt.Sprintf("user-%d", i)
+		d := cohort.Assign(key, nil)
+		assert.Equal(t, d, CanaryCohort{Name: "checkout-v2", Percentage: 20}.Assign(key, nil), "assignments are deterministic")
+		assert.Equal(t, Bucket(key, "checkout-v2"), d.Bucket)
+		if d.InCanary {
+			assert.Equal(t, CanaryReasonPercentage, d.Reason)
+			assert.True(t, CanaryCohort{Name: "checkout-v2", Percentage: 50}.Assign(key, nil).InCanary, "raising the percentage keeps the canary")
+			in++
+		}
+	}
+	assert.InDelta(t, 1000, in, 150, "about Percentage of the keys are in the canary")
+
+	differ := 0
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("user-%d", i)
+		if cohort.Assign(key, nil).InCanary != (CanaryCohort{Name: "search-v2", Percentage: 20}).Assign(key, nil).InCanary {
+			differ++
+		}
+	}
+	assert.Greater(t, differ, 100, "cohorts of other names bucket independently")
+}
+
+func TestCanaryMiddleware(t *testing.T) {
+	cohort := CanaryCohort{Name: "checkout-v2", Keys: []string{"qa-user"}}
+	var decision CanaryDecision
+	var ok bool
+	handler := CanaryMiddleware(cohort, func(r *http.Request) string { return r.Header.Get("X-User") })(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			decision, ok = CanaryFromContext(r.Context())
+			// propagate the decision downstream
+			out := http.Header{}
+			cohort.SetHeader(out, decision)
+			w.Header().Set(DefaultCanaryHeader, out.Get(DefaultCanaryHeader))
+		}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-User", "qa-user")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.True(t, ok)
+	assert.True(t, decision.InCanary)
+	assert.Equal(t, "true", rec.Header().Get(DefaultCanaryHeader))
+
+	// the downstream service makes the same decision from the header
+	downstream := httptest.NewRequest(http.MethodGet, "/", nil)
+	downstream.Header.Set(DefaultCanaryHeader, rec.Header().Get(DefaultCanaryHeader))
+	assert.Equal(t, CanaryDecision{Cohort: "checkout-v2", InCanary: true, Reason: CanaryReasonHeader},
+		CanaryCohort{Name: "checkout-v2"}.Assign("other-user", downstream.Header))
+
+	_, ok = CanaryFromContext(req.Context())
+	assert.False(t, ok)
+}
+
+func TestCanaryDecisionTargeting(t *testing.T) {
+	evaluator := NewEvaluator(NewClient("http://localhost", "key", nil), EvaluatorOptions{Environment: "production"})
+	evaluator.SetRuleset(&Ruleset{Environment: "production", Flags: []FeatureFlag{{
+		ID: 1, Name: "checkout-v2", IsActive: true, Rollout: &PercentageRollout{Percentage: 0},
+		Rules: []FlagRule{{ID: "canary", Conditions: []TargetingCondition{{Attribute: "canary", Operator: OpEquals, Value: true}}}},
+	}}})
+	cohort := CanaryCohort{Name: "checkout-v2", Keys: []string{"qa-user"}}
+
+	for key, want := range map[string]bool{"qa-user": true, "user-1": false} {
+		d := cohort.Assign(key, nil)
+		enabled, err := evaluator.IsEnabled("checkout-v2", EvaluationContext{Key: key, Attributes: d.Attributes()})
+		require.NoError(t, err)
+		assert.Equal(t, want, enabled, "the flag follows the canary decision of %s", key)
+	}
+}
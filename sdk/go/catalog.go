@@ -0,0 +1,258 @@
+package matrixflag
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Flag metadata keys read by the catalog exporter
+const (
+	MetadataOwner     = "owner"
+	MetadataLifecycle = "lifecycle"
+	MetadataServices  = "services"
+	MetadataLinks     = "links"
+)
+
+// Catalog entity API version, kind and resource type
+const (
+	CatalogAPIVersion   = "backstage.io/v1alpha1"
+	CatalogKind         = "Resource"
+	CatalogResourceType = "feature-flag"
+)
+
+const (
+	catalogAnnotationID        = "matrixflag.io/flag-id"
+	catalogAnnotationEnv       = "matrixflag.io/environment"
+	catalogMaxNameLength       = 63
+	defaultCatalogLifecycle    = "production"
+	catalogLifecycleDeprecated = "deprecated"
+	defaultCatalogOwner        = "unknown"
+)
+
+// catalogInvalidName matches runs of characters not allowed in catalog entity names
+var catalogInvalidName = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// CatalogOptions controls how flags are converted into catalog entities
+type CatalogOptions struct {
+	// Namespace is the catalog namespace of the generated entities and service references
+	Namespace string
+	// DefaultOwner is used for flags without an owner in their metadata
+	DefaultOwner string
+	// DefaultLifecycle is used for active flags without a lifecycle in their
+	// metadata; deprecated and archived flags are deprecated in the catalog
+	DefaultLifecycle string
+	// FlagURL optionally returns a link to the flag, such as a dashboard page
+	FlagURL func(FeatureFlag) string
+}
+
+// CatalogEntity represents a Backstage-compatible catalog entity describing a flag
+type CatalogEntity struct {
+	APIVersion string          `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string          `json:"kind" yaml:"kind"`
+	Metadata   CatalogMetadata `json:"metadata" yaml:"metadata"`
+	Spec       CatalogSpec     `json:"spec" yaml:"spec"`
+}
+
+// CatalogMetadata represents the metadata section of a catalog entity
+type CatalogMetadata struct {
+	Name        string            `json:"name" yaml:"name"`
+	Namespace   string            `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Title       string            `json:"title,omitempty" yaml:"title,omitempty"`
+	Description string            `json:"description,omitempty" yaml:"description,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Tags        []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Links       []CatalogLink     `json:"links,omitempty" yaml:"links,omitempty"`
+}
+
+// CatalogLink represents a link shown on a catalog entity
+type CatalogLink struct {
+	URL   string `json:"url" yaml:"url"`
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+}
+
+// CatalogSpec represents the spec section of a catalog entity
+type CatalogSpec struct {
+	Type         string   `json:"type" yaml:"type"`
+	Owner        string   `json:"owner" yaml:"owner"`
+	Lifecycle    string   `json:"lifecycle" yaml:"lifecycle"`
+	DependencyOf []string `json:"dependencyOf,omitempty" yaml:"dependencyOf,omitempty"`
+}
+
+// NewCatalogEntities converts flags into catalog entities.
+// Owners, lifecycle, services and links are read from the flag metadata.
+func NewCatalogEntities(flags []FeatureFlag, opts CatalogOptions) []CatalogEntity {
+	entities := make([]CatalogEntity, 0, len(flags))
+	for _, flag := range flags {
+		entities = append(entities, catalogEntity(flag, opts))
+	}
+	return entities
+}
+
+// catalogEntity converts a single flag into a catalog entity
+func catalogEntity(flag FeatureFlag, opts CatalogOptions) CatalogEntity {
+	owner := metadataString(flag.Metadata, MetadataOwner)
+	if owner == "" {
+		owner = opts.DefaultOwner
+	}
+	if owner == "" {
+		owner = defaultCatalogOwner
+	}
+	lifecycle := metadataString(flag.Metadata, MetadataLifecycle)
+	if lifecycle == "" && flag.State() != LifecycleActive {
+		lifecycle = catalogLifecycleDeprecated
+	}
+	if lifecycle == "" {
+		lifecycle = opts.DefaultLifecycle
+	}
+	if lifecycle == "" {
+		lifecycle = defaultCatalogLifecycle
+	}
+
+	namespace := opts.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	var dependencyOf []string
+	for _, service := range metadataStrings(flag.Metadata, MetadataServices) {
+		if !strings.Contains(service, ":") {
+			service = "component:" + namespace + "/" + service
+		}
+		dependencyOf = append(dependencyOf, service)
+	}
+
+	links := metadataLinks(flag.Metadata)
+	if opts.FlagURL != nil {
+		if url := opts.FlagURL(flag); url != "" {
+			links = append([]CatalogLink{{URL: url, Title: "Matrix Flag"}}, links...)
+		}
+	}
+
+	return CatalogEntity{
+		APIVersion: CatalogAPIVersion,
+		Kind:       CatalogKind,
+		Metadata: CatalogMetadata{
+			Name:        catalogName(flag.Name),
+			Namespace:   opts.Namespace,
+			Title:       flag.Name,
+			Description: flag.Description,
+			Annotations: map[string]string{
+				catalogAnnotationID:  strconv.Itoa(flag.ID),
+				catalogAnnotationEnv: flag.Environment,
+			},
+			Tags:  []string{CatalogResourceType},
+			Links: links,
+		},
+		Spec: CatalogSpec{
+			Type:         CatalogResourceType,
+			Owner:        owner,
+			Lifecycle:    lifecycle,
+			DependencyOf: dependencyOf,
+		},
+	}
+}
+
+// catalogName converts a flag name into a valid catalog entity name
+func catalogName(name string) string {
+	name = catalogInvalidName.ReplaceAllString(name, "-")
+	if len(name) > catalogMaxNameLength {
+		name = name[:catalogMaxNameLength]
+	}
+	return strings.Trim(name, "-_.")
+}
+
+// metadataString returns a string metadata value
+func metadataString(metadata map[string]any, key string) string {
+	s, _ := metadata[key].(string)
+	return s
+}
+
+// metadataStrings returns a metadata value holding a string or a list of strings
+func metadataStrings(metadata map[string]any, key string) []string {
+	switch v := metadata[key].(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []any:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// metadataLinks returns the links metadata value, given as URLs or {url, title} objects
+func metadataLinks(metadata map[string]any) []CatalogLink {
+	items, _ := metadata[MetadataLinks].([]any)
+	var links []CatalogLink
+	for _, item := range items {
+		switch v := item.(type) {
+		case string:
+			links = append(links, CatalogLink{URL: v})
+		case map[string]any:
+			url, _ := v["url"].(string)
+			title, _ := v["title"].(string)
+			if url != "" {
+				links = append(links, CatalogLink{URL: url, Title: title})
+			}
+		}
+	}
+	return links
+}
+
+// CatalogByService groups entities by the service entity references they belong to.
+// Entities without services are grouped under the empty key.
+func CatalogByService(entities []CatalogEntity) map[string][]CatalogEntity {
+	groups := make(map[string][]CatalogEntity)
+	for _, entity := range entities {
+		if len(entity.Spec.DependencyOf) == 0 {
+			groups[""] = append(groups[""], entity)
+			continue
+		}
+		for _, service := range entity.Spec.DependencyOf {
+			groups[service] = append(groups[service], entity)
+		}
+	}
+	for _, group := range groups {
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].Metadata.Name < group[j].Metadata.Name
+		})
+	}
+	return groups
+}
+
+// WriteCatalog writes entities to w as a multi-document catalog-info YAML file
+func WriteCatalog(w io.Writer, entities []CatalogEntity) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	for _, entity := range entities {
+		if err := enc.Encode(entity); err != nil {
+			return fmt.Errorf("failed to encode catalog entity %s: %w", entity.Metadata.Name, err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to encode catalog: %w", err)
+	}
+	return nil
+}
+
+// ExportCatalog exports the flags of an environment as catalog entities
+func (c *Client) ExportCatalog(ctx context.Context, environment string, opts CatalogOptions, reqOpts ...RequestOption) ([]CatalogEntity, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	flags, err := c.listFeatureFlags(ctx, FlagFilter{Environment: environment}, EndpointExport)
+	if err != nil {
+		return nil, err
+	}
+	return NewCatalogEntities(flags, opts), nil
+}
@@ -0,0 +1,94 @@
+package matrixflag
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestExportCatalog(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.addFlag(FeatureFlag{Name: "new_checkout flow!", Environment: "production", Description: "New checkout", Metadata: map[string]any{
+		MetadataOwner:     "team-checkout",
+		MetadataLifecycle: "experimental",
+		MetadataServices:  []any{"checkout-service", "component:payments/billing"},
+		MetadataLinks:     []any{"https://wiki.example.com/checkout", map[string]any{"url": "https://runbooks.example.com/checkout", "title": "Runbook"}},
+	}})
+	srv.addFlag(FeatureFlag{Name: "search", Environment: "production"})
+	srv.addFlag(FeatureFlag{Name: "search", Environment: "staging"})
+	client := NewClient(srv.URL, "key", nil)
+
+	entities, err := client.ExportCatalog(context.Background(), "production", CatalogOptions{
+		Namespace:    "flags",
+		DefaultOwner: "platform-team",
+		FlagURL:      func(f FeatureFlag) string { return "https://flags.example.com/" + f.Name },
+	})
+	require.NoError(t, err)
+	require.Len(t, entities, 2, "only the flags of the environment are exported")
+
+	checkout := entities[0]
+	assert.Equal(t, CatalogAPIVersion, checkout.APIVersion)
+	assert.Equal(t, CatalogKind, checkout.Kind)
+	assert.Equal(t, "new_checkout-flow", checkout.Metadata.Name, "invalid characters are replaced and trimmed")
+	assert.Equal(t, "new_checkout flow!", checkout.Metadata.Title)
+	assert.Equal(t, "flags", checkout.Metadata.Namespace)
+	assert.Equal(t, map[string]string{"matrixflag.io/flag-id": "1", "matrixflag.io/environment": "production"}, checkout.Metadata.Annotations)
+	assert.Equal(t, CatalogSpec{
+		Type:         CatalogResourceType,
+		Owner:        "team-checkout",
+		Lifecycle:    "experimental",
+		DependencyOf: []string{"component:flags/checkout-service", "component:payments/billing"},
+	}, checkout.Spec)
+	assert.Equal(t, []CatalogLink{
+		{URL: "https://flags.example.com/new_checkout flow!", Title: "Matrix Flag"},
+		{URL: "https://wiki.example.com/checkout"},
+		{URL: "https://runbooks.example.com/checkout", Title: "Runbook"},
+	}, checkout.Metadata.Links)
+
+	search := entities[1]
+	assert.Equal(t, "platform-team", search.Spec.Owner, "the default owner applies without an owner in the metadata")
+	assert.Equal(t, "production", search.Spec.Lifecycle)
+	assert.Empty(t, search.Spec.DependencyOf)
+}
+
+func TestCatalogByService(t *testing.T) {
+	entities := NewCatalogEntities([]FeatureFlag{
+		{Name: "search", Metadata: map[string]any{MetadataServices: "web"}},
+		{Name: "checkout", Metadata: map[string]any{MetadataServices: []string{"web", "api"}}},
+		{Name: "banner"},
+	}, CatalogOptions{})
+
+	groups := CatalogByService(entities)
+	names := func(group []CatalogEntity) []string {
+		var names []string
+		for _, e := range group {
+			names = append(names, e.Metadata.Name)
+		}
+		return names
+	}
+	assert.Len(t, groups, 3)
+	assert.Equal(t, []string{"checkout", "search"}, names(groups["component:default/web"]), "groups are sorted by name")
+	assert.Equal(t, []string{"checkout"}, names(groups["component:default/api"]))
+	assert.Equal(t, []string{"banner"}, names(groups[""]))
+}
+
+func TestWriteCatalog(t *testing.T) {
+	entities := NewCatalogEntities([]FeatureFlag{{ID: 1, Name: "checkout"}, {ID: 2, Name: "search"}}, CatalogOptions{})
+	var buf bytes.Buffer
+	require.NoError(t, WriteCatalog(&buf, entities))
+
+	dec := yaml.NewDecoder(&buf)
+	var decoded []CatalogEntity
+	for {
+		var entity CatalogEntity
+		if err := dec.Decode(&entity); err != nil {
+			break
+		}
+		decoded = append(decoded, entity)
+	}
+	assert.Equal(t, entities, decoded, "one YAML document is written per entity")
+}
@@ -0,0 +1,185 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ChangeRequestStatus is the state of a change request
+type ChangeRequestStatus string
+
+// Change request states
+const (
+	ChangeRequestPending  ChangeRequestStatus = "pending"
+	ChangeRequestApproved ChangeRequestStatus = "approved"
+	ChangeRequestRejected ChangeRequestStatus = "rejected"
+	ChangeRequestApplied  ChangeRequestStatus = "applied"
+)
+
+// ChangeRequest is a proposed change to a flag, applied once a reviewer other
+// than its author approves it. Environments can require change requests for
+// every change, so production toggles need a second pair of eyes.
+type ChangeRequest struct {
+	ID     int    `json:"id"`
+	FlagID int    `json:"flag_id"`
+	Author string `json:"author"`
+	// Description explains the change to the reviewers
+	Description string              `json:"description,omitempty"`
+	Update      FeatureFlagUpdate   `json:"update"`
+	Status      ChangeRequestStatus `json:"status"`
+	Reviews     []ChangeReview      `json:"reviews,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+// ChangeReview is the approval or rejection of a change request
+type ChangeReview struct {
+	Reviewer string    `json:"reviewer"`
+	Approved bool      `json:"approved"`
+	Comment  string    `json:"comment,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// ChangeRequestCreate represents the data needed to propose a change
+type ChangeRequestCreate struct {
+	FlagID      int               `json:"flag_id"`
+	Description string            `json:"description,omitempty"`
+	Update      FeatureFlagUpdate `json:"update"`
+	// Reviewers are asked to review the change; any allowed reviewer may when it is empty
+	Reviewers []string `json:"reviewers,omitempty"`
+}
+
+// ChangeRequestQuery selects change requests
+type ChangeRequestQuery struct {
+	// Status only returns the change requests in this state, such as ChangeRequestPending
+	Status ChangeRequestStatus
+	// FlagID only returns the change requests of this flag
+	FlagID int
+}
+
+// changeReview is the body of an approval or rejection
+type changeReview struct {
+	Comment string `json:"comment,omitempty"`
+}
+
+// CreateChangeRequest proposes a change to a flag, which is applied by
+// ApplyChangeRequest once approved
+func (c *Client) CreateChangeRequest(ctx context.Context, change ChangeRequestCreate, reqOpts ...RequestOption) (*ChangeRequest, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if change.FlagID == 0 {
+		return nil, fmt.Errorf("change request requires a flag ID")
+	}
+	if err := change.Update.validate(); err != nil {
+		return nil, err
+	}
+	change.Update = c.qualifyUpdate(change.Update)
+	return c.changeRequestRequest(ctx, request{
+		method: "POST",
+		path:   "/api/v1/change-requests/",
+		body:   change,
+	})
+}
+
+// ListChangeRequests retrieves the change requests matching query, such as
+// the pending ones awaiting review
+func (c *Client) ListChangeRequests(ctx context.Context, query ChangeRequestQuery, reqOpts ...RequestOption) ([]ChangeRequest, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	params := map[string]string{}
+	if query.Status != "" {
+		params["status"] = string(query.Status)
+	}
+	if query.FlagID != 0 {
+		params["flag_id"] = strconv.Itoa(query.FlagID)
+	}
+	respBody, err := c.doRequest(ctx, request{
+		method: "GET",
+		path:   "/api/v1/change-requests/",
+		query:  params,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []ChangeRequest
+	if err := json.Unmarshal(respBody, &changes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	for i := range changes {
+		changes[i].Update.Name = c.unqualifyName(changes[i].Update.Name)
+		c.unqualifyPrerequisites(changes[i].Update.Prerequisites)
+	}
+	return changes, nil
+}
+
+// GetChangeRequest retrieves a change request by ID
+func (c *Client) GetChangeRequest(ctx context.Context, id int, reqOpts ...RequestOption) (*ChangeRequest, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.changeRequestRequest(ctx, request{
+		method: "GET",
+		path:   fmt.Sprintf("/api/v1/change-requests/%d", id),
+	})
+}
+
+// ApproveChangeRequest approves a change request as the user of the client's
+// API key, who must not be its author
+func (c *Client) ApproveChangeRequest(ctx context.Context, id int, comment string, reqOpts ...RequestOption) (*ChangeRequest, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.changeRequestRequest(ctx, request{
+		method: "POST",
+		path:   fmt.Sprintf("/api/v1/change-requests/%d/approve", id),
+		body:   changeReview{Comment: comment},
+	})
+}
+
+// RejectChangeRequest rejects a change request, which can then no longer be applied
+func (c *Client) RejectChangeRequest(ctx context.Context, id int, comment string, reqOpts ...RequestOption) (*ChangeRequest, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.changeRequestRequest(ctx, request{
+		method: "POST",
+		path:   fmt.Sprintf("/api/v1/change-requests/%d/reject", id),
+		body:   changeReview{Comment: comment},
+	})
+}
+
+// ApplyChangeRequest applies an approved change request to its flag and
+// returns the updated flag. Applying a change request that is not approved
+// fails with an APIError.
+func (c *Client) ApplyChangeRequest(ctx context.Context, id int, reqOpts ...RequestOption) (*FeatureFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	respBody, err := c.doRequest(ctx, request{
+		method: "POST",
+		path:   fmt.Sprintf("/api/v1/change-requests/%d/apply", id),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var flag FeatureFlag
+	if err := json.Unmarshal(respBody, &flag); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if err := c.checkUnknownFields(flag); err != nil {
+		return nil, err
+	}
+	c.unqualifyFlag(&flag)
+	return &flag, nil
+}
+
+// changeRequestRequest performs a request returning a change request
+func (c *Client) changeRequestRequest(ctx context.Context, req request) (*ChangeRequest, error) {
+	respBody, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var change ChangeRequest
+	if err := json.Unmarshal(respBody, &change); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	change.Update.Name = c.unqualifyName(change.Update.Name)
+	c.unqualifyPrerequisites(change.Update.Prerequisites)
+	return &change, nil
+}
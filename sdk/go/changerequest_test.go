@@ -0,0 +1,76 @@
+package matrixflag
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeRequests(t *testing.T) {
+	var requests, bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		bodies = append(bodies, string(body))
+		switch r.URL.Path {
+		case "/api/v1/change-requests/":
+			if r.Method == http.MethodGet {
+				writeJSON(w, []ChangeRequest{{ID: 5, FlagID: 7, Status: ChangeRequestPending, Update: FeatureFlagUpdate{Name: "team.checkout-v2"}}})
+				return
+			}
+			writeJSON(w, ChangeRequest{ID: 5, FlagID: 7, Author: "jane", Status: ChangeRequestPending})
+		case "/api/v1/change-requests/5/approve":
+			writeJSON(w, ChangeRequest{ID: 5, Status: ChangeRequestApproved, Reviews: []ChangeReview{{Reviewer: "joe", Approved: true}}})
+		case "/api/v1/change-requests/5/apply":
+			writeJSON(w, FeatureFlag{ID: 7, Name: "team.checkout", IsActive: true})
+		default:
+			writeJSON(w, ChangeRequest{ID: 5, Status: ChangeRequestRejected})
+		}
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil, WithNamespace("team"))
+	ctx := context.Background()
+
+	change, err := client.CreateChangeRequest(ctx, ChangeRequestCreate{
+		FlagID:      7,
+		Description: "Enable the new checkout",
+		Update:      FeatureFlagUpdate{IsActive: true, Fields: []string{FieldIsActive}},
+		Reviewers:   []string{"joe"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ChangeRequestPending, change.Status)
+	pending, err := client.ListChangeRequests(ctx, ChangeRequestQuery{Status: ChangeRequestPending, FlagID: 7})
+	require.NoError(t, err)
+	assert.Equal(t, "checkout-v2", pending[0].Update.Name, "names are unqualified")
+	change, err = client.ApproveChangeRequest(ctx, 5, "LGTM")
+	require.NoError(t, err)
+	assert.Equal(t, ChangeRequestApproved, change.Status)
+	flag, err := client.ApplyChangeRequest(ctx, 5)
+	require.NoError(t, err)
+	assert.Equal(t, "checkout", flag.Name)
+	_, err = client.RejectChangeRequest(ctx, 5, "")
+	require.NoError(t, err)
+	_, err = client.GetChangeRequest(ctx, 5)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"POST /api/v1/change-requests/",
+		"GET /api/v1/change-requests/?flag_id=7&status=pending",
+		"POST /api/v1/change-requests/5/approve",
+		"POST /api/v1/change-requests/5/apply",
+		"POST /api/v1/change-requests/5/reject",
+		"GET /api/v1/change-requests/5",
+	}, requests)
+	assert.JSONEq(t, `{"flag_id":7,"description":"Enable the new checkout","update":{"is_active":true},"reviewers":["joe"]}`, bodies[0])
+	assert.JSONEq(t, `{"comment":"LGTM"}`, bodies[2])
+
+	_, err = client.CreateChangeRequest(ctx, ChangeRequestCreate{Update: FeatureFlagUpdate{IsActive: true}})
+	assert.Error(t, err)
+	_, err = client.CreateChangeRequest(ctx, ChangeRequestCreate{FlagID: 7, Update: FeatureFlagUpdate{Rollout: &PercentageRollout{Percentage: 150}}})
+	assert.Error(t, err)
+}
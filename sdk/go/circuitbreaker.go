@@ -0,0 +1,191 @@
+package matrixflag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Circuit breaker defaults, see BreakerOptions
+const (
+	DefaultBreakerFailureThreshold = 5
+	DefaultBreakerOpenDuration     = 30 * time.Second
+	DefaultBreakerHalfOpenProbes   = 1
+)
+
+// ErrCircuitOpen is returned by API calls failed fast by an open circuit breaker
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerState is the state of a circuit breaker
+type BreakerState string
+
+// Circuit breaker states
+const (
+	// BreakerClosed lets every call through
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen fails every call with ErrCircuitOpen without sending it
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a few probe calls through to find out whether the API recovered
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// BreakerOptions configures the circuit breaker of a client
+type BreakerOptions struct {
+	// FailureThreshold is the number of consecutive failed calls that opens the
+	// breaker, DefaultBreakerFailureThreshold by default. A call fails when it gets
+	// no response or a 5xx response after its retries.
+	FailureThreshold int
+	// OpenDuration is how long the breaker stays open before letting probes
+	// through, DefaultBreakerOpenDuration by default
+	OpenDuration time.Duration
+	// HalfOpenProbes is the number of concurrent probe calls let through while
+	// half-open, DefaultBreakerHalfOpenProbes by default. As many successful
+	// probes close the breaker, and a failed one opens it again.
+	HalfOpenProbes int
+	// OnStateChange is called when the breaker changes state
+	OnStateChange func(from, to BreakerState)
+}
+
+// WithCircuitBreaker fails API calls fast with ErrCircuitOpen while the API is
+// down, instead of having every call wait out its timeouts and retries. Flag
+// evaluations then serve their default value right away. Flag streams are not
+// affected by the breaker.
+func WithCircuitBreaker(opts BreakerOptions) ClientOption {
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = DefaultBreakerFailureThreshold
+	}
+	if opts.OpenDuration <= 0 {
+		opts.OpenDuration = DefaultBreakerOpenDuration
+	}
+	if opts.HalfOpenProbes <= 0 {
+		opts.HalfOpenProbes = DefaultBreakerHalfOpenProbes
+	}
+	return func(c *Client) {
+		c.breaker = &circuitBreaker{opts: opts, state: BreakerClosed}
+	}
+}
+
+// BreakerState returns the state of the client's circuit breaker, BreakerClosed without one
+func (c *Client) BreakerState() BreakerState {
+	if c.breaker == nil {
+		return BreakerClosed
+	}
+	c.breaker.mu.Lock()
+	defer c.breaker.mu.Unlock()
+	return c.breaker.state
+}
+
+// circuitBreaker counts failed calls, see WithCircuitBreaker
+type circuitBreaker struct {
+	opts BreakerOptions
+
+	mu    sync.Mutex
+	state BreakerState
+	// failures counts consecutive failed calls while closed
+	failures int
+	// openedAt is when the breaker last opened
+	openedAt time.Time
+	// probes counts the probes in flight and successes the successful ones while half-open
+	probes    int
+	successes int
+}
+
+// allow reports whether a call may be sent, taking a probe slot while half-open
+func (b *circuitBreaker) allow(now time.Time) (bool, BreakerState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	from := b.state
+	if b.state == BreakerOpen {
+		if now.Sub(b.openedAt) < b.opts.OpenDuration {
+			return false, from
+		}
+		b.state, b.probes, b.successes = BreakerHalfOpen, 0, 0
+	}
+	if b.state == BreakerHalfOpen {
+		if b.probes >= b.opts.HalfOpenProbes {
+			return false, from
+		}
+		b.probes++
+	}
+	return true, from
+}
+
+// record counts the outcome of an allowed call; a call that neither failed nor
+// succeeded, because its context was canceled, only frees its probe slot
+func (b *circuitBreaker) record(now time.Time, failed, counted bool) (from, to BreakerState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	from = b.state
+	switch b.state {
+	case BreakerClosed:
+		if !counted {
+			break
+		}
+		if !failed {
+			b.failures = 0
+			break
+		}
+		b.failures++
+		if b.failures >= b.opts.FailureThreshold {
+			b.state, b.openedAt = BreakerOpen, now
+		}
+	case BreakerHalfOpen:
+		b.probes--
+		switch {
+		case !counted:
+		case failed:
+			b.state, b.openedAt = BreakerOpen, now
+		default:
+			b.successes++
+			if b.successes >= b.opts.HalfOpenProbes {
+				b.state, b.failures = BreakerClosed, 0
+			}
+		}
+	}
+	// Calls finishing while open were let through before it opened and are ignored
+	return from, b.state
+}
+
+// breakerAllow reports whether a request may be sent, failing with
+// ErrCircuitOpen when the circuit breaker is open
+func (c *Client) breakerAllow(ctx context.Context, req request) error {
+	if c.breaker == nil {
+		return nil
+	}
+	ok, from := c.breaker.allow(c.clock.Now())
+	if from == BreakerOpen && ok {
+		c.breakerChanged(ctx, BreakerOpen, BreakerHalfOpen)
+	}
+	if !ok {
+		return fmt.Errorf("%w: %s %s not sent", ErrCircuitOpen, req.method, req.path)
+	}
+	return nil
+}
+
+// breakerRecord counts the outcome of a request sent with breakerAllow
+func (c *Client) breakerRecord(ctx context.Context, status int, err error) {
+	if c.breaker == nil {
+		return
+	}
+	failed := status >= 500 || (err != nil && status == 0)
+	counted := ctx.Err() == nil
+	if from, to := c.breaker.record(c.clock.Now(), failed, counted); from != to {
+		c.breakerChanged(ctx, from, to)
+	}
+}
+
+// breakerChanged logs a state change of the circuit breaker and calls its hook
+func (c *Client) breakerChanged(ctx context.Context, from, to BreakerState) {
+	level := slog.LevelInfo
+	if to == BreakerOpen {
+		level = slog.LevelWarn
+	}
+	c.Logger().LogAttrs(ctx, level, "circuit breaker changed state",
+		slog.String("from", string(from)), slog.String("to", string(to)))
+	if c.breaker.opts.OnStateChange != nil {
+		c.breaker.opts.OnStateChange(from, to)
+	}
+}
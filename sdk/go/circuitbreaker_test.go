@@ -0,0 +1,83 @@
+package matrixflag
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(status)
+		writeJSON(w, []FeatureFlag{})
+	}))
+	defer srv.Close()
+	clock := &fixedClock{time.Unix(1700000000, 0)}
+	var changes []BreakerState
+	client := NewClient(srv.URL, "key", nil, WithRetries(0, 0, 0), WithClock(clock), WithCircuitBreaker(BreakerOptions{
+		FailureThreshold: 2,
+		OpenDuration:     10 * time.Second,
+		OnStateChange:    func(from, to BreakerState) { changes = append(changes, to) },
+	}))
+	ctx := context.Background()
+	list := func() error {
+		_, err := client.ListFeatureFlags(ctx, FlagFilter{})
+		return err
+	}
+
+	assert.Equal(t, BreakerClosed, client.BreakerState())
+	for i := 0; i < 2; i++ {
+		err := list()
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrCircuitOpen)
+	}
+	assert.Equal(t, BreakerOpen, client.BreakerState())
+	assert.ErrorIs(t, list(), ErrCircuitOpen)
+	assert.Equal(t, 2, requests, "calls are not sent while open")
+
+	clock.now = clock.now.Add(10 * time.Second)
+	assert.Error(t, list(), "the failed probe opens the breaker again")
+	assert.ErrorIs(t, list(), ErrCircuitOpen)
+	assert.Equal(t, 3, requests)
+
+	clock.now = clock.now.Add(10 * time.Second)
+	status = http.StatusOK
+	require.NoError(t, list())
+	assert.Equal(t, BreakerClosed, client.BreakerState())
+	assert.Equal(t, []BreakerState{BreakerOpen, BreakerHalfOpen, BreakerOpen, BreakerHalfOpen, BreakerClosed}, changes)
+
+	status = http.StatusNotFound
+	for i := 0; i < 3; i++ {
+		assert.ErrorIs(t, list(), ErrNotFound)
+	}
+	assert.Equal(t, BreakerClosed, client.BreakerState(), "4xx responses are not failures of the API")
+}
+
+func TestCircuitBreakerCountsConsecutiveFailures(t *testing.T) {
+	breaker := &circuitBreaker{opts: BreakerOptions{FailureThreshold: 2, OpenDuration: time.Second, HalfOpenProbes: 1}, state: BreakerClosed}
+	now := time.Unix(1700000000, 0)
+
+	breaker.record(now, true, true)
+	breaker.record(now, false, true)
+	breaker.record(now, true, true)
+	breaker.record(now, true, false)
+	assert.Equal(t, BreakerClosed, breaker.state, "a success resets the count and canceled calls are not counted")
+
+	breaker.record(now, true, true)
+	assert.Equal(t, BreakerOpen, breaker.state)
+	ok, _ := breaker.allow(now.Add(time.Second))
+	assert.True(t, ok)
+	ok, _ = breaker.allow(now.Add(time.Second))
+	assert.False(t, ok, "only one probe is let through at a time")
+	breaker.record(now, true, false)
+	ok, _ = breaker.allow(now.Add(time.Second))
+	assert.True(t, ok, "a canceled probe frees its slot")
+}
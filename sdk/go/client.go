@@ -6,50 +6,152 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-// Client represents a Matrix Flag API client
+// DefaultBaseURL is the base URL of the hosted Matrix Flag API
+const DefaultBaseURL = "https://api.matrixflag.com"
+
+// Client represents a Matrix Flag API client. It is safe for concurrent use by
+// multiple goroutines, and meant to be shared: its options are applied by
+// NewClient, and the state it updates while in use, such as the rate limit of
+// the latest response, the circuit breaker, the rate limiter, the response
+// cache and the buffered events, is synchronized. Its transport keeps a pool
+// of keep-alive connections to the API, see NewTransport.
 type Client struct {
 	baseURL    string
 	apiKey     string
 	httpClient *http.Client
 	config     *Config
+	middleware []Middleware
+	doer       Doer
+	clock      Clock
+	logger     *slog.Logger
+	metrics    Metrics
+	tracer     Tracer
+	events     *eventBuffer
+	expiry     *ExpiryOptions
+	usage      flagUsage
+
+	subs          subscriptions
+	subscribeOpts WatchOptions
+	streamOpts    StreamOptions
+	startOpts     StartOptions
+
+	wrapperName    string
+	wrapperVersion string
+
+	offline offlineState
+	// local is the local evaluation of a client started with Start, nil before
+	local atomic.Pointer[localEvaluation]
+	// noBulk is set once the server is found to lack the bulk flag endpoints
+	noBulk atomic.Bool
+	// rateLimit is the rate limit reported by the latest response, nil before any
+	rateLimit atomic.Pointer[RateLimit]
+	limiter   *tokenBucket
+	breaker   *circuitBreaker
+	cache     atomic.Pointer[responseCache]
+	// closed is set once Close has shut the client down
+	closed    atomic.Bool
+	closeOnce sync.Once
 }
 
 // Config represents the client configuration
 type Config struct {
-	Timeout     time.Duration
-	MaxRetries  int
-	RetryDelay  time.Duration
+	Timeout       time.Duration
+	MaxRetries    int
+	RetryDelay    time.Duration
 	MaxRetryDelay time.Duration
+	// Timeouts overrides Timeout, the limit of each request attempt, for classes of endpoints
+	// such as slow exports and bulk operations
+	Timeouts map[EndpointClass]time.Duration
+	// Proxy returns the proxy for a request; the environment's HTTP(S)_PROXY settings are used when nil
+	Proxy func(*http.Request) (*url.URL, error)
+	// Namespace is prefixed to every flag name sent to the API and stripped from names read back,
+	// so that several tenants or applications can share a project. Flags outside the namespace
+	// are left out of list results.
+	Namespace string
+	// Environment is the environment of server-side flag evaluations
+	Environment string
+	// StrictDecoding makes calls fail when a returned flag has fields unknown to this SDK version
+	StrictDecoding bool
+	// OfflineFile is the path of an offline flag file, see OfflineFlags. When set, the client
+	// makes no network requests: rulesets and evaluations are served from the file and the
+	// other API calls fail with ErrOffline. A file holding another environment than
+	// Environment, when set, fails to load.
+	OfflineFile string
 }
 
 // DefaultConfig returns the default client configuration
 func DefaultConfig() *Config {
 	return &Config{
-		Timeout:     30 * time.Second,
-		MaxRetries:  3,
-		RetryDelay:  time.Second,
+		Timeout:       30 * time.Second,
+		MaxRetries:    3,
+		RetryDelay:    time.Second,
 		MaxRetryDelay: 10 * time.Second,
 	}
 }
 
-// NewClient creates a new Matrix Flag client
-func NewClient(baseURL, apiKey string, config *Config) *Client {
+// Connection pool defaults of the transport of a client, see NewTransport
+const (
+	DefaultMaxIdleConns        = 256
+	DefaultMaxIdleConnsPerHost = 64
+	DefaultIdleConnTimeout     = 90 * time.Second
+)
+
+// NewTransport returns the HTTP transport of clients created without
+// WithHTTPClient or WithTransport: a clone of http.DefaultTransport keeping up
+// to DefaultMaxIdleConnsPerHost idle keep-alive connections to the API, rather
+// than the two of the standard library, so concurrent management calls reuse
+// connections instead of opening one per request. Custom transports can start
+// from it.
+func NewTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = DefaultMaxIdleConns
+	transport.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	transport.IdleConnTimeout = DefaultIdleConnTimeout
+	transport.ForceAttemptHTTP2 = true
+	return transport
+}
+
+// NewClient creates a new Matrix Flag client. The configuration is copied, so
+// options such as WithTimeout do not modify it; see New for a client configured
+// with options only.
+func NewClient(baseURL, apiKey string, config *Config, opts ...ClientOption) *Client {
 	if config == nil {
 		config = DefaultConfig()
+	} else {
+		copied := *config
+		config = &copied
 	}
 
-	return &Client{
-		baseURL: baseURL,
-		apiKey:  apiKey,
-		httpClient: &http.Client{
-			Timeout: config.Timeout,
-		},
-		config: config,
+	// Timeouts are applied per request attempt, as they depend on the endpoint
+	transport := NewTransport()
+	if config.Proxy != nil {
+		transport.Proxy = config.Proxy
+	}
+	httpClient := &http.Client{Transport: transport}
+
+	c := &Client{
+		baseURL:    baseURL,
+		apiKey:     apiKey,
+		httpClient: httpClient,
+		config:     config,
+		clock:      SystemClock,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	c.doer = c.buildDoer()
+	if c.isOffline() {
+		c.loadOffline()
+	}
+	return c
 }
 
 // request represents an API request
@@ -59,27 +161,35 @@ type request struct {
 	body    interface{}
 	query   map[string]string
 	headers map[string]string
+	class   EndpointClass
 }
 
-// doRequest performs an HTTP request with retries
-func (c *Client) doRequest(ctx context.Context, req request) ([]byte, error) {
+// newHTTPRequest builds the HTTP request of req with the client's headers and its request ID
+func (c *Client) newHTTPRequest(ctx context.Context, req request) (*http.Request, string, error) {
 	var body io.Reader
 	if req.body != nil {
 		jsonBody, err := json.Marshal(req.body)
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+			return nil, "", fmt.Errorf("failed to marshal request body: %w", err)
 		}
 		body = bytes.NewReader(jsonBody)
 	}
 
 	httpReq, err := http.NewRequestWithContext(ctx, req.method, c.baseURL+req.path, body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Add headers
+	// Add headers, those of the call's options first so the SDK's take precedence
+	for k, v := range callOptions(ctx).headers {
+		httpReq.Header.Set(k, v)
+	}
+	reqID := requestID(ctx)
 	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
 	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", c.userAgent())
+	httpReq.Header.Set(SDKHeader, c.sdkHeader())
+	httpReq.Header.Set(RequestIDHeader, reqID)
 	for k, v := range req.headers {
 		httpReq.Header.Set(k, v)
 	}
@@ -90,112 +200,328 @@ func (c *Client) doRequest(ctx context.Context, req request) ([]byte, error) {
 		q.Set(k, v)
 	}
 	httpReq.URL.RawQuery = q.Encode()
+	return httpReq, reqID, nil
+}
+
+// doRequest performs an HTTP request with retries, traced by the client's Tracer
+// and guarded by its circuit breaker
+func (c *Client) doRequest(ctx context.Context, req request) ([]byte, error) {
+	if c.isOffline() {
+		return nil, c.offlineError(req)
+	}
+	if err := c.closedError(); err != nil {
+		return nil, err
+	}
+	if err := c.breakerAllow(ctx, req); err != nil {
+		return nil, err
+	}
+	ctx, end := c.Tracer().StartRequest(ctx, req.method, req.endpointClass(), req.path)
+	respBody, status, err := c.sendRequest(ctx, req)
+	end(status, err)
+	c.breakerRecord(ctx, status, err)
+	return respBody, err
+}
+
+// sendRequest performs an HTTP request with retries and returns the final status code, 0 without a response
+func (c *Client) sendRequest(ctx context.Context, req request) ([]byte, int, error) {
+	httpReq, reqID, err := c.newHTTPRequest(ctx, req)
+	if err != nil {
+		return nil, 0, err
+	}
+	cached := c.cachedRequest(httpReq)
 
 	// Perform request with retries
 	var resp *http.Response
 	var lastErr error
+	start := c.clock.Now()
+	status := 0
+	defer func() {
+		c.Metrics().RequestDone(req.method, req.endpointClass(), status, c.clock.Now().Sub(start))
+	}()
+	attempts := 0
+	timeout := c.timeout(ctx, req)
 	for i := 0; i <= c.config.MaxRetries; i++ {
-		resp, err = c.httpClient.Do(httpReq)
+		if err := c.pace(ctx); err != nil {
+			return nil, 0, fmt.Errorf("request %s canceled while waiting for the rate limiter: %w", reqID, err)
+		}
+		attempts++
+		attemptReq, cancel := httpReq, context.CancelFunc(func() {})
+		if timeout > 0 {
+			var attemptCtx context.Context
+			attemptCtx, cancel = context.WithTimeout(ctx, timeout)
+			attemptReq = httpReq.WithContext(attemptCtx)
+		}
+		resp, err = c.doer.Do(attemptReq)
+		delay := c.backoff(i)
 		if err == nil {
-			break
+			c.observeRateLimit(resp.Header)
+			wait, ok := c.retryDelay(httpReq, resp, i)
+			if !ok {
+				// The attempt context must stay alive while the body is read
+				defer cancel()
+				lastErr = nil
+				break
+			}
+			if wait >= 0 {
+				delay = wait
+			}
+			// Drain the body so the connection is reused
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		} else {
+			lastErr = err
 		}
-		lastErr = err
+		cancel()
 		if i < c.config.MaxRetries {
-			delay := c.config.RetryDelay * time.Duration(1<<uint(i))
-			if delay > c.config.MaxRetryDelay {
-				delay = c.config.MaxRetryDelay
+			attrs := []slog.Attr{
+				slog.String("request_id", reqID),
+				slog.String("method", req.method),
+				slog.String("path", req.path),
+				slog.Int("attempt", attempts),
+				slog.Duration("delay", delay),
+			}
+			if err != nil {
+				attrs = append(attrs, slog.Any("error", err))
+			} else {
+				attrs = append(attrs, slog.Int("status", resp.StatusCode))
+			}
+			c.Logger().LogAttrs(ctx, slog.LevelWarn, "retrying request", attrs...)
+			c.Metrics().RequestRetried(req.method, req.endpointClass())
+			if err := sleep(ctx, c.clock, delay); err != nil {
+				return nil, 0, fmt.Errorf("request %s canceled while waiting to retry: %w", reqID, err)
 			}
-			time.Sleep(delay)
 		}
 	}
 	if lastErr != nil {
-		return nil, fmt.Errorf("failed to perform request after %d retries: %w", c.config.MaxRetries, lastErr)
+		return nil, 0, fmt.Errorf("failed to perform request %s after %d retries: %w", reqID, c.config.MaxRetries, lastErr)
 	}
 	defer resp.Body.Close()
+	status = resp.StatusCode
+	if id := resp.Header.Get(RequestIDHeader); id != "" {
+		reqID = id
+	}
+	recordResponseMetadata(ctx, resp, reqID, c.clock.Now().Sub(start), attempts)
 
 	// Read response body
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, status, fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	// Check for errors
 	if resp.StatusCode >= 400 {
-		var apiErr APIError
-		if err := json.Unmarshal(respBody, &apiErr); err != nil {
-			return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
-		}
-		return nil, apiErr
+		return nil, status, responseError(resp.StatusCode, respBody, reqID)
 	}
 
-	return respBody, nil
+	return c.cacheResponse(httpReq, cached, resp, respBody), status, nil
+}
+
+// responseError decodes the error returned by the API with an error status
+func responseError(status int, body []byte, reqID string) error {
+	var apiErr APIError
+	if err := json.Unmarshal(body, &apiErr); err != nil {
+		// Proxies and load balancers reply with plain text or HTML
+		apiErr = APIError{Message: string(body)}
+	}
+	apiErr.StatusCode = status
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = reqID
+	}
+	return apiErr
 }
 
 // FeatureFlag represents a feature flag
 type FeatureFlag struct {
-	ID          int       `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description,omitempty"`
-	IsActive    bool      `json:"is_active"`
-	Environment string    `json:"environment"`
-	ProjectID   int       `json:"project_id,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID                int                `json:"id"`
+	Name              string             `json:"name"`
+	Description       string             `json:"description,omitempty"`
+	IsActive          bool               `json:"is_active"`
+	Environment       string             `json:"environment"`
+	ProjectID         int                `json:"project_id,omitempty"`
+	Metadata          map[string]any     `json:"metadata,omitempty"`
+	LayerID           int                `json:"layer_id,omitempty"`
+	TrafficAllocation *TrafficAllocation `json:"traffic_allocation,omitempty"`
+	Rollout           *PercentageRollout `json:"rollout,omitempty"`
+	// Variations are the values of a multivariate flag, served by weight. OffVariation
+	// is the key of the variation served while the flag is off or excludes a context;
+	// false is served instead when it is empty.
+	Variations   []Variation `json:"variations,omitempty"`
+	OffVariation string      `json:"off_variation,omitempty"`
+	// Rules target contexts ahead of the rollout and variation weights
+	Rules []FlagRule `json:"rules,omitempty"`
+	// Prerequisites must all be met for the flag to be evaluated; otherwise it
+	// serves its off value, see Prerequisite
+	Prerequisites []Prerequisite `json:"prerequisites,omitempty"`
+	DebugUntil    *time.Time     `json:"debug_until,omitempty"`
+	// InheritDefaults makes the flag inherit the defaults of its project and environment,
+	// with Overrides holding the values set on the flag itself; see ResolveDefaults
+	InheritDefaults bool          `json:"inherit_defaults,omitempty"`
+	Overrides       *FlagDefaults `json:"overrides,omitempty"`
+	// Tags group flags, such as by team, epic or cleanup status, see FlagFilter
+	Tags []string `json:"tags,omitempty"`
+	// Lifecycle is the lifecycle state of the flag, empty for active flags;
+	// archived flags serve the default value, see ArchiveFeatureFlag
+	Lifecycle FlagLifecycle `json:"lifecycle,omitempty"`
+	// ExpiresAt is the planned removal date of the flag, such as the end of an
+	// experiment, see Client.ExpiredFlags
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	// Extra holds fields returned by the server that this SDK version does not know,
+	// which are encoded again when the flag is marshaled
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // FeatureFlagCreate represents the data needed to create a feature flag
 type FeatureFlagCreate struct {
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
-	IsActive    bool   `json:"is_active"`
-	Environment string `json:"environment"`
-	ProjectID   int    `json:"project_id,omitempty"`
+	Name          string             `json:"name"`
+	Description   string             `json:"description,omitempty"`
+	IsActive      bool               `json:"is_active"`
+	Environment   string             `json:"environment"`
+	ProjectID     int                `json:"project_id,omitempty"`
+	Metadata      map[string]any     `json:"metadata,omitempty"`
+	Tags          []string           `json:"tags,omitempty"`
+	Rollout       *PercentageRollout `json:"rollout,omitempty"`
+	Variations    []Variation        `json:"variations,omitempty"`
+	OffVariation  string             `json:"off_variation,omitempty"`
+	Rules         []FlagRule         `json:"rules,omitempty"`
+	Prerequisites []Prerequisite     `json:"prerequisites,omitempty"`
+	ExpiresAt     *time.Time         `json:"expires_at,omitempty"`
 }
 
-// FeatureFlagUpdate represents the data needed to update a feature flag
+// FeatureFlagUpdate represents the data needed to update a feature flag. Empty
+// fields are left out, so the server leaves them unchanged: IsActive false or
+// an empty Description only apply when named in Fields, or use PatchFeatureFlag
+// to send exactly the fields of a mask.
 type FeatureFlagUpdate struct {
-	Name        string `json:"name,omitempty"`
-	Description string `json:"description,omitempty"`
-	IsActive    bool   `json:"is_active,omitempty"`
-	Environment string `json:"environment,omitempty"`
-	ProjectID   int    `json:"project_id,omitempty"`
+	Name          string             `json:"name,omitempty"`
+	Description   string             `json:"description,omitempty"`
+	IsActive      bool               `json:"is_active,omitempty"`
+	Environment   string             `json:"environment,omitempty"`
+	ProjectID     int                `json:"project_id,omitempty"`
+	Metadata      map[string]any     `json:"metadata,omitempty"`
+	Tags          []string           `json:"tags,omitempty"`
+	Rollout       *PercentageRollout `json:"rollout,omitempty"`
+	Variations    []Variation        `json:"variations,omitempty"`
+	OffVariation  string             `json:"off_variation,omitempty"`
+	Rules         []FlagRule         `json:"rules,omitempty"`
+	Prerequisites []Prerequisite     `json:"prerequisites,omitempty"`
+	Lifecycle     FlagLifecycle      `json:"lifecycle,omitempty"`
+	ExpiresAt     *time.Time         `json:"expires_at,omitempty"`
+	// Fields names fields sent even when empty, which the server otherwise leaves
+	// unchanged, such as FieldDescription to clear the description or
+	// FieldIsActive to deactivate the flag
+	Fields FieldMask `json:"-"`
 }
 
-// APIError represents an API error response
+// validate checks the flag before it is created
+func (f FeatureFlagCreate) validate() error {
+	return validateFlagFields(f.Name, f.Rollout, f.Variations, f.OffVariation, f.Rules, f.Prerequisites, f.Tags)
+}
+
+// validate checks the update before it is sent
+func (u FeatureFlagUpdate) validate() error {
+	if u.Lifecycle != "" {
+		if err := u.Lifecycle.Validate(); err != nil {
+			return err
+		}
+	}
+	return validateFlagFields(u.Name, u.Rollout, u.Variations, u.OffVariation, u.Rules, u.Prerequisites, u.Tags)
+}
+
+// validateFlagFields checks the fields shared by flag creates and updates
+func validateFlagFields(name string, rollout *PercentageRollout, variations []Variation, offVariation string,
+	rules []FlagRule, prerequisites []Prerequisite, tags []string) error {
+	if rollout != nil {
+		if err := rollout.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := validateFlagVariations(variations, offVariation); err != nil {
+		return err
+	}
+	if err := validateFlagRules(rules, variations); err != nil {
+		return err
+	}
+	if err := validatePrerequisites(name, prerequisites); err != nil {
+		return err
+	}
+	return validateTags(tags)
+}
+
+// APIError represents an API error response. Its kind can be matched with
+// errors.Is against ErrNotFound, ErrUnauthorized, ErrRateLimited and ErrValidation.
 type APIError struct {
-	Message string `json:"message"`
-	Code    string `json:"code"`
-	Details any    `json:"details,omitempty"`
+	StatusCode int    `json:"-"`
+	Message    string `json:"message"`
+	Code       string `json:"code"`
+	Details    any    `json:"details,omitempty"`
+	RequestID  string `json:"request_id,omitempty"`
 }
 
 func (e APIError) Error() string {
-	return fmt.Sprintf("API error: %s (code: %s)", e.Message, e.Code)
+	detail := "code: " + e.Code
+	if e.Code == "" {
+		detail = fmt.Sprintf("status: %d", e.StatusCode)
+	}
+	if e.RequestID != "" {
+		return fmt.Sprintf("API error: %s (%s, request ID: %s)", e.Message, detail, e.RequestID)
+	}
+	return fmt.Sprintf("API error: %s (%s)", e.Message, detail)
 }
 
-// ListFeatureFlags retrieves a list of feature flags
-func (c *Client) ListFeatureFlags(ctx context.Context, params map[string]string) ([]FeatureFlag, error) {
+// ListFeatureFlags retrieves the feature flags selected by filter
+func (c *Client) ListFeatureFlags(ctx context.Context, filter FlagFilter, reqOpts ...RequestOption) ([]FeatureFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.listFeatureFlags(ctx, filter, EndpointRead)
+}
+
+// listFeatureFlags retrieves a list of feature flags with the timeout of the given endpoint class
+func (c *Client) listFeatureFlags(ctx context.Context, filter FlagFilter, class EndpointClass) ([]FeatureFlag, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
 	respBody, err := c.doRequest(ctx, request{
 		method: "GET",
 		path:   "/api/v1/feature-flags/",
-		query:  params,
+		query:  c.qualifyFilter(filter).query(),
+		class:  class,
 	})
 	if err != nil {
 		return nil, err
 	}
+	flags, err := c.decodeFlags(respBody)
+	if err != nil {
+		return nil, err
+	}
+	return c.unqualifyFlags(flags), nil
+}
 
+// decodeFlags decodes a list of flags, still qualified by the client's namespace
+func (c *Client) decodeFlags(respBody []byte) ([]FeatureFlag, error) {
 	var flags []FeatureFlag
 	if err := json.Unmarshal(respBody, &flags); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	if err := c.checkUnknownFields(flags...); err != nil {
+		return nil, err
+	}
 	return flags, nil
 }
 
-// CreateFeatureFlag creates a new feature flag
-func (c *Client) CreateFeatureFlag(ctx context.Context, flag FeatureFlagCreate) (*FeatureFlag, error) {
+// CreateFeatureFlag creates a new feature flag. It is sent with a generated
+// idempotency key unless WithIdempotencyKey sets one, so a retry after a
+// timeout does not create the flag twice.
+func (c *Client) CreateFeatureFlag(ctx context.Context, flag FeatureFlagCreate, reqOpts ...RequestOption) (*FeatureFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := flag.validate(); err != nil {
+		return nil, err
+	}
 	respBody, err := c.doRequest(ctx, request{
-		method: "POST",
-		path:   "/api/v1/feature-flags/",
-		body:   flag,
+		method:  "POST",
+		path:    "/api/v1/feature-flags/",
+		body:    c.qualifyCreate(flag),
+		headers: idempotencyHeaders(ctx),
 	})
 	if err != nil {
 		return nil, err
@@ -205,11 +531,16 @@ func (c *Client) CreateFeatureFlag(ctx context.Context, flag FeatureFlagCreate)
 	if err := json.Unmarshal(respBody, &createdFlag); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	if err := c.checkUnknownFields(createdFlag); err != nil {
+		return nil, err
+	}
+	c.unqualifyFlag(&createdFlag)
 	return &createdFlag, nil
 }
 
 // GetFeatureFlag retrieves a feature flag by ID
-func (c *Client) GetFeatureFlag(ctx context.Context, id int) (*FeatureFlag, error) {
+func (c *Client) GetFeatureFlag(ctx context.Context, id int, reqOpts ...RequestOption) (*FeatureFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
 	respBody, err := c.doRequest(ctx, request{
 		method: "GET",
 		path:   fmt.Sprintf("/api/v1/feature-flags/%d", id),
@@ -222,15 +553,46 @@ func (c *Client) GetFeatureFlag(ctx context.Context, id int) (*FeatureFlag, erro
 	if err := json.Unmarshal(respBody, &flag); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	if err := c.checkUnknownFields(flag); err != nil {
+		return nil, err
+	}
+	c.unqualifyFlag(&flag)
 	return &flag, nil
 }
 
+// GetFeatureFlagByName retrieves a feature flag by its name, which is unique
+// within an environment, in the configured environment when environment is
+// empty. It fails with ErrFlagNotFound when the environment has no such flag.
+func (c *Client) GetFeatureFlagByName(ctx context.Context, name, environment string, reqOpts ...RequestOption) (*FeatureFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if environment == "" {
+		environment = c.config.Environment
+	}
+	if name == "" || environment == "" {
+		return nil, fmt.Errorf("looking up a flag by name requires a name and an environment")
+	}
+	flags, err := c.ListFeatureFlags(ctx, FlagFilter{Environment: environment, NamePrefix: name})
+	if err != nil {
+		return nil, err
+	}
+	for i := range flags {
+		if flags[i].Name == name && flags[i].Environment == environment {
+			return &flags[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s in environment %s", ErrFlagNotFound, name, environment)
+}
+
 // UpdateFeatureFlag updates a feature flag
-func (c *Client) UpdateFeatureFlag(ctx context.Context, id int, flag FeatureFlagUpdate) (*FeatureFlag, error) {
+func (c *Client) UpdateFeatureFlag(ctx context.Context, id int, flag FeatureFlagUpdate, reqOpts ...RequestOption) (*FeatureFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := flag.validate(); err != nil {
+		return nil, err
+	}
 	respBody, err := c.doRequest(ctx, request{
 		method: "PUT",
 		path:   fmt.Sprintf("/api/v1/feature-flags/%d", id),
-		body:   flag,
+		body:   c.qualifyUpdate(flag),
 	})
 	if err != nil {
 		return nil, err
@@ -240,11 +602,34 @@ func (c *Client) UpdateFeatureFlag(ctx context.Context, id int, flag FeatureFlag
 	if err := json.Unmarshal(respBody, &updatedFlag); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	if err := c.checkUnknownFields(updatedFlag); err != nil {
+		return nil, err
+	}
+	c.unqualifyFlag(&updatedFlag)
 	return &updatedFlag, nil
 }
 
+// PatchFeatureFlag sets exactly the fields of a feature flag named in mask to
+// their values in update, empty or not, and leaves its other fields unchanged;
+// fields of update missing from mask are not sent:
+//
+//	flag, err := client.PatchFeatureFlag(ctx, id, matrixflag.FeatureFlagUpdate{IsActive: false}, matrixflag.FieldMask{matrixflag.FieldIsActive})
+func (c *Client) PatchFeatureFlag(ctx context.Context, id int, update FeatureFlagUpdate, mask FieldMask, reqOpts ...RequestOption) (*FeatureFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if len(mask) == 0 {
+		return nil, fmt.Errorf("patch requires at least one field")
+	}
+	patch, err := onlyFields(update, mask)
+	if err != nil {
+		return nil, err
+	}
+	patch.Fields = mask
+	return c.UpdateFeatureFlag(ctx, id, patch)
+}
+
 // DeleteFeatureFlag deletes a feature flag
-func (c *Client) DeleteFeatureFlag(ctx context.Context, id int) (*FeatureFlag, error) {
+func (c *Client) DeleteFeatureFlag(ctx context.Context, id int, reqOpts ...RequestOption) (*FeatureFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
 	respBody, err := c.doRequest(ctx, request{
 		method: "DELETE",
 		path:   fmt.Sprintf("/api/v1/feature-flags/%d", id),
@@ -257,14 +642,22 @@ func (c *Client) DeleteFeatureFlag(ctx context.Context, id int) (*FeatureFlag, e
 	if err := json.Unmarshal(respBody, &deletedFlag); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	if err := c.checkUnknownFields(deletedFlag); err != nil {
+		return nil, err
+	}
+	c.unqualifyFlag(&deletedFlag)
 	return &deletedFlag, nil
 }
 
-// ToggleFeatureFlag toggles a feature flag's active status
-func (c *Client) ToggleFeatureFlag(ctx context.Context, id int) (*FeatureFlag, error) {
+// ToggleFeatureFlag toggles a feature flag's active status. Like
+// CreateFeatureFlag it is sent with an idempotency key, so a retry does not
+// toggle the flag back.
+func (c *Client) ToggleFeatureFlag(ctx context.Context, id int, reqOpts ...RequestOption) (*FeatureFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
 	respBody, err := c.doRequest(ctx, request{
-		method: "POST",
-		path:   fmt.Sprintf("/api/v1/feature-flags/%d/toggle", id),
+		method:  "POST",
+		path:    fmt.Sprintf("/api/v1/feature-flags/%d/toggle", id),
+		headers: idempotencyHeaders(ctx),
 	})
 	if err != nil {
 		return nil, err
@@ -274,23 +667,38 @@ func (c *Client) ToggleFeatureFlag(ctx context.Context, id int) (*FeatureFlag, e
 	if err := json.Unmarshal(respBody, &toggledFlag); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	if err := c.checkUnknownFields(toggledFlag); err != nil {
+		return nil, err
+	}
+	c.unqualifyFlag(&toggledFlag)
 	return &toggledFlag, nil
 }
 
-// AddWebhook adds a webhook URL
-func (c *Client) AddWebhook(ctx context.Context, url string) error {
-	_, err := c.doRequest(ctx, request{
-		method: "POST",
-		path:   fmt.Sprintf("/api/v1/feature-flags/webhooks/%s", url),
-	})
+// AddWebhook adds a webhook delivering every change to url
+//
+// Deprecated: use CreateWebhook, which filters events and signs deliveries.
+func (c *Client) AddWebhook(ctx context.Context, url string, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	_, err := c.CreateWebhook(ctx, WebhookCreate{URL: url})
 	return err
 }
 
-// RemoveWebhook removes a webhook URL
-func (c *Client) RemoveWebhook(ctx context.Context, url string) error {
-	_, err := c.doRequest(ctx, request{
-		method: "DELETE",
-		path:   fmt.Sprintf("/api/v1/feature-flags/webhooks/%s", url),
-	})
-	return err
-} 
\ No newline at end of file
+// RemoveWebhook removes the webhooks delivering to url
+//
+// Deprecated: use DeleteWebhook.
+func (c *Client) RemoveWebhook(ctx context.Context, url string, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	webhooks, err := c.ListWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+	for _, webhook := range webhooks {
+		if webhook.URL != url {
+			continue
+		}
+		if err := c.DeleteWebhook(ctx, webhook.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package matrixflag
+
+import (
+	"context"
+	"time"
+)
+
+// Clock abstracts time for backoff sleeps, polling intervals and timestamps,
+// so tests can control it instead of waiting in real time
+type Clock interface {
+	Now() time.Time
+	// After waits for the duration to elapse and then sends the current time on the returned channel
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock backed by the time package
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// WithClock sets the clock used by the client and the components polling through it
+func WithClock(clock Clock) ClientOption {
+	return func(c *Client) {
+		c.clock = clock
+	}
+}
+
+// Clock returns the clock of the client
+func (c *Client) Clock() Clock {
+	return c.clock
+}
+
+// sleep waits for d on clock, returning early with the context's error when it is canceled
+func sleep(ctx context.Context, clock Clock, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-clock.After(d):
+		return nil
+	}
+}
@@ -0,0 +1,57 @@
+package matrixflag
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrClientClosed is returned by the API calls of a client after Close
+var ErrClientClosed = errors.New("client is closed")
+
+// Close shuts the client down for a clean exit. It stops the polling and the
+// stream of a client started with Start, waiting for a poll in flight, and the
+// watch driving its subscriptions, then sends the buffered events of the event
+// pipeline, writes the snapshot of StartOptions.SnapshotFile with the latest
+// ruleset and closes the idle connections. The flush is bounded by the timeout
+// of the client. Later API calls fail with ErrClientClosed, and flag streams
+// stop reconnecting. Close returns the errors of the flush and the snapshot;
+// calling it again does nothing.
+func (c *Client) Close() error {
+	var err error
+	c.closeOnce.Do(func() { err = c.close() })
+	return err
+}
+
+// close runs the shutdown of Close
+func (c *Client) close() error {
+	var errs []error
+	if local := c.local.Load(); local != nil {
+		local.stop()
+		<-local.processor.stopped
+		if local.evaluator.opts.SnapshotFile != "" && local.evaluator.Ready() {
+			errs = append(errs, local.evaluator.SaveSnapshot())
+		}
+	}
+	c.subs.stop()
+
+	if c.events != nil {
+		ctx := context.Background()
+		if c.config.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
+			defer cancel()
+		}
+		errs = append(errs, c.FlushEvents(ctx))
+	}
+	c.closed.Store(true)
+	c.httpClient.CloseIdleConnections()
+	return errors.Join(errs...)
+}
+
+// closedError returns ErrClientClosed once the client is closed
+func (c *Client) closedError() error {
+	if c.closed.Load() {
+		return ErrClientClosed
+	}
+	return nil
+}
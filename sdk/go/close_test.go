@@ -0,0 +1,59 @@
+package matrixflag
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClose(t *testing.T) {
+	srv := newPollingServer(t, FeatureFlag{ID: 1, Name: "checkout", Environment: "production", IsActive: true})
+	snapshot := filepath.Join(t.TempDir(), "flags.json")
+	client := NewClient(srv.URL, "key", nil, WithEnvironment("production"), WithRetries(0, 0, 0),
+		WithEvents(EventOptions{}), WithStartOptions(StartOptions{SnapshotFile: snapshot}))
+	ctx := context.Background()
+	require.NoError(t, client.Start(ctx))
+	require.NoError(t, client.WaitForReady(ctx, 5*time.Second))
+	client.Evaluate(ctx, "checkout", EvaluationContext{Key: "user-1"}, false)
+	client.OnAnyChange(func(old, new FeatureFlag) {})
+
+	require.NoError(t, client.Close())
+	assert.Equal(t, 1, srv.events, "buffered events are flushed")
+	assert.Equal(t, DataSourceOff, client.DataSourceStatus().State)
+	ruleset, err := readSnapshot(snapshot)
+	require.NoError(t, err)
+	require.Len(t, ruleset.Flags, 1)
+	assert.Equal(t, "checkout", ruleset.Flags[0].Name)
+	client.subs.mu.Lock()
+	assert.Nil(t, client.subs.cancel, "the subscription watch is stopped")
+	client.subs.mu.Unlock()
+
+	_, err = client.ListFeatureFlags(ctx, FlagFilter{})
+	assert.ErrorIs(t, err, ErrClientClosed)
+	assert.ErrorIs(t, client.Start(ctx), ErrClientClosed)
+	client.OnAnyChange(func(old, new FeatureFlag) {})
+	client.subs.mu.Lock()
+	assert.Nil(t, client.subs.cancel, "no watch is started once closed")
+	client.subs.mu.Unlock()
+	assert.NoError(t, client.Close(), "closing again does nothing")
+
+	// a restart during an outage starts from the snapshot written on Close
+	srv.setDown(true)
+	client = NewClient(srv.URL, "key", nil, WithEnvironment("production"), WithRetries(0, 0, 0),
+		WithStartOptions(StartOptions{SnapshotFile: snapshot}))
+	require.NoError(t, client.Start(ctx))
+	require.NoError(t, client.WaitForReady(ctx, 0))
+	assert.Equal(t, true, client.Evaluate(ctx, "checkout", EvaluationContext{Key: "user-1"}, false).Value)
+	assert.NoError(t, client.Close())
+}
+
+func TestCloseUnstarted(t *testing.T) {
+	client := NewClient("http://localhost", "key", nil)
+	require.NoError(t, client.Close())
+	_, err := client.GetFeatureFlag(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrClientClosed)
+}
@@ -0,0 +1,133 @@
+// Command matrixflag-gen generates a typed Go package for the flags of an
+// environment, read from the API or from a manifest, see package codegen:
+//
+//	matrixflag-gen -env production -package flags -o internal/flags/flags_gen.go
+//	matrixflag-gen -manifest flags.yaml -package flags -o internal/flags/flags_gen.go
+//
+// It fits a go:generate directive in the directory of the generated package:
+//
+//	//go:generate matrixflag-gen -env production -package flags -o flags_gen.go
+//
+// With -env, the client is configured from the MATRIXFLAG_* environment
+// variables read by matrixflag.NewClientFromEnv. Flags defined in a manifest
+// have no variations, so they all get boolean accessors.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/matrixflag/sdk/codegen"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	g := &generator{stdout: os.Stdout, stderr: os.Stderr, newClient: func() (*matrixflag.Client, error) {
+		return matrixflag.NewClientFromEnv()
+	}}
+	if err := g.run(ctx, os.Args[1:]); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "matrixflag-gen:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// generator runs the matrixflag-gen command
+type generator struct {
+	stdout    io.Writer
+	stderr    io.Writer
+	newClient func() (*matrixflag.Client, error)
+}
+
+func (g *generator) run(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("matrixflag-gen", flag.ContinueOnError)
+	fs.SetOutput(g.stderr)
+	env := fs.String("env", "", "environment whose flags are read from the API, or of the manifest flags to generate")
+	project := fs.Int("project", 0, "project ID of the flags read from the API")
+	manifest := fs.String("manifest", "", "manifest file to read the flags from instead of the API")
+	pkg := fs.String("package", codegen.DefaultPackage, "name of the generated package")
+	output := fs.String("o", "", "file to write the package to, standard output by default")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments %v", fs.Args())
+	}
+
+	var flags []matrixflag.FeatureFlag
+	var source string
+	var err error
+	if *manifest != "" {
+		flags, err = manifestFlags(*manifest, *env)
+		source = *manifest
+	} else {
+		if *env == "" {
+			return errors.New("either -env or -manifest is required")
+		}
+		flags, err = g.apiFlags(ctx, *env, *project)
+		source = "environment " + *env
+	}
+	if err != nil {
+		return err
+	}
+	src, err := codegen.Generate(flags, codegen.Options{Package: *pkg, Source: source})
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = g.stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*output, src, 0o644)
+}
+
+// apiFlags lists the flags of an environment from the API
+func (g *generator) apiFlags(ctx context.Context, env string, project int) ([]matrixflag.FeatureFlag, error) {
+	client, err := g.newClient()
+	if err != nil {
+		return nil, err
+	}
+	flags, err := client.ListFeatureFlags(ctx, matrixflag.FlagFilter{Environment: env, ProjectID: project})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list flags: %w", err)
+	}
+	return flags, nil
+}
+
+// manifestFlags returns the flags of a manifest, only those of env when set
+func manifestFlags(path, env string) ([]matrixflag.FeatureFlag, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	m, err := matrixflag.LoadManifest(f)
+	if err != nil {
+		return nil, err
+	}
+	var flags []matrixflag.FeatureFlag
+	seen := make(map[string]bool, len(m.Flags))
+	for _, spec := range m.Flags {
+		specEnv := spec.Environment
+		if specEnv == "" {
+			specEnv = m.Environment
+		}
+		if env != "" && specEnv != env {
+			continue
+		}
+		if seen[spec.Name] {
+			return nil, fmt.Errorf("flag %s is defined for several environments, select one with -env", spec.Name)
+		}
+		seen[spec.Name] = true
+		flags = append(flags, matrixflag.FeatureFlag{Name: spec.Name, Description: spec.Description, Environment: specEnv})
+	}
+	return flags, nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateFromManifest(t *testing.T) {
+	dir := t.TempDir()
+	manifest := filepath.Join(dir, "flags.yaml")
+	require.NoError(t, os.WriteFile(manifest, []byte(`
+apiVersion: matrixflag.io/v1
+kind: FlagManifest
+environment: production
+flags:
+  - name: new-checkout
+    description: New checkout flow
+  - name: new-checkout
+    environment: staging
+  - name: dark-mode
+    environment: staging
+`), 0o600))
+	var stdout bytes.Buffer
+	g := &generator{stdout: &stdout, stderr: &bytes.Buffer{}}
+
+	output := filepath.Join(dir, "flags_gen.go")
+	require.NoError(t, g.run(context.Background(), []string{"-manifest", manifest, "-env", "production", "-package", "prodflags", "-o", output}))
+	src, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Contains(t, string(src), "package prodflags\n")
+	assert.Contains(t, string(src), "NewCheckout = BoolFlag{Key: \"new-checkout\"}")
+	assert.NotContains(t, string(src), "DarkMode")
+
+	require.NoError(t, g.run(context.Background(), []string{"-manifest", manifest, "-env", "staging"}))
+	assert.Contains(t, stdout.String(), "DarkMode = BoolFlag", "the package is written to standard output by default")
+
+	err = g.run(context.Background(), []string{"-manifest", manifest})
+	assert.EqualError(t, err, "flag new-checkout is defined for several environments, select one with -env")
+	assert.EqualError(t, g.run(context.Background(), nil), "either -env or -manifest is required")
+}
+
+func TestGenerateFromAPI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/feature-flags/", r.URL.Path)
+		assert.Equal(t, "production", r.URL.Query().Get("environment"))
+		json.NewEncoder(w).Encode([]matrixflag.FeatureFlag{
+			{ID: 1, Name: "new-checkout", Environment: "production"},
+			{ID: 2, Name: "theme", Environment: "production", Variations: []matrixflag.Variation{{Key: "dark", Value: "dark"}}},
+		})
+	}))
+	defer srv.Close()
+	var stdout bytes.Buffer
+	g := &generator{stdout: &stdout, stderr: &bytes.Buffer{}, newClient: func() (*matrixflag.Client, error) {
+		return matrixflag.NewClient(srv.URL, "key", nil), nil
+	}}
+
+	require.NoError(t, g.run(context.Background(), []string{"-env", "production"}))
+	assert.Contains(t, stdout.String(), "// Code generated by matrixflag-gen from environment production. DO NOT EDIT.")
+	assert.Contains(t, stdout.String(), "Theme = StringFlag{Key: \"theme\"}")
+}
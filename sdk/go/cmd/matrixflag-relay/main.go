@@ -0,0 +1,99 @@
+// Command matrixflag-relay serves Matrix Flag flags to many SDK instances from
+// one upstream connection to the API, see package relay.
+//
+// The upstream client is configured from the MATRIXFLAG_* environment
+// variables read by matrixflag.NewClientFromEnv. Downstream API keys are read
+// from MATRIXFLAG_RELAY_KEYS, separated by commas.
+//
+//	MATRIXFLAG_API_KEY=... MATRIXFLAG_RELAY_KEYS=key1,key2 \
+//	    matrixflag-relay -listen :8030 -environments production,staging
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/matrixflag/sdk/relay"
+)
+
+// EnvRelayKeys lists the API keys accepted from downstream clients
+const EnvRelayKeys = "MATRIXFLAG_RELAY_KEYS"
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "matrixflag-relay:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	listen := flag.String("listen", ":8030", "address to serve downstream clients on")
+	environments := flag.String("environments", "", "comma-separated environments to relay (required)")
+	syncInterval := flag.Duration("sync-interval", matrixflag.DefaultSyncInterval, "time between ruleset syncs")
+	snapshotDir := flag.String("snapshot-dir", "", "directory keeping a ruleset snapshot per environment")
+	flag.Parse()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	// The upstream stream carries the changes of every relayed environment
+	client, err := matrixflag.NewClientFromEnv(
+		matrixflag.WithLogger(logger),
+		matrixflag.WithStreamOptions(matrixflag.StreamOptions{}),
+		matrixflag.WithEnvironment(""),
+	)
+	if err != nil {
+		return err
+	}
+	r, err := relay.New(client, relay.Options{
+		Environments: splitList(*environments),
+		Keys:         splitList(os.Getenv(EnvRelayKeys)),
+		SyncInterval: *syncInterval,
+		SnapshotDir:  *snapshotDir,
+		OnError: func(err error) {
+			logger.Warn("relay error", slog.Any("error", err))
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if os.Getenv(EnvRelayKeys) == "" {
+		logger.Warn(EnvRelayKeys + " is not set, serving every request")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go r.Run(ctx)
+
+	srv := &http.Server{Addr: *listen, Handler: r, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+	logger.Info("relay listening", slog.String("address", *listen), slog.String("environments", *environments))
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// splitList splits a comma-separated list, dropping empty items
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+// apply converges the server to a flag manifest
+func (c *cli) apply(ctx context.Context, args []string) error {
+	fs, common := c.newFlagSet("apply")
+	file := fs.String("f", "", "manifest file, YAML or JSON, - for standard input")
+	dryRun := fs.Bool("dry-run", false, "print the plan without changing anything")
+	prune := fs.Bool("prune", true, "delete flags and segments missing from the manifest in its environments")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("apply requires -f")
+	}
+	var manifest *matrixflag.Manifest
+	var err error
+	if *file == "-" {
+		manifest, err = matrixflag.LoadManifest(os.Stdin)
+	} else {
+		manifest, err = matrixflag.LoadManifestFile(*file)
+	}
+	if err != nil {
+		return err
+	}
+
+	// --env and --project fill in the flags that do not set their own
+	if common.env != "" {
+		manifest.Environment = common.env
+	}
+	desired := manifest.DesiredState()
+	for i := range desired.Flags {
+		if desired.Flags[i].ProjectID == 0 {
+			desired.Flags[i].ProjectID = common.project
+		}
+	}
+
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+	result, err := client.Apply(ctx, desired, matrixflag.ApplyOptions{Prune: *prune, DryRun: *dryRun})
+	if result != nil {
+		if printErr := c.printChanges(common.output, result); printErr != nil && err == nil {
+			err = printErr
+		}
+	}
+	return err
+}
+
+// printChanges prints the changes of an apply, or its plan on a dry run
+func (c *cli) printChanges(output string, result *matrixflag.ApplyResult) error {
+	if output == "json" {
+		if result.Changes == nil {
+			result.Changes = []matrixflag.Change{}
+		}
+		return writeJSON(c.stdout, result)
+	}
+	counts := make(map[matrixflag.ChangeAction]int)
+	for _, change := range result.Changes {
+		counts[change.Action]++
+		name := change.Name
+		if change.Environment != "" {
+			name = change.Environment + "/" + name
+		}
+		fmt.Fprintf(c.stdout, "%s %s %s %s\n", changeSymbols[change.Action], change.Action, change.Kind, name)
+		for _, field := range change.Fields {
+			if change.Action == matrixflag.ChangeCreate {
+				fmt.Fprintf(c.stdout, "    %s: %s\n", field.Field, jsonValue(field.New))
+				continue
+			}
+			fmt.Fprintf(c.stdout, "    %s: %s -> %s\n", field.Field, jsonValue(field.Old), jsonValue(field.New))
+		}
+	}
+	switch {
+	case len(result.Changes) == 0:
+		fmt.Fprintln(c.stdout, "No changes.")
+	case result.DryRun:
+		fmt.Fprintf(c.stdout, "Plan: %d to create, %d to update, %d to delete.\n",
+			counts[matrixflag.ChangeCreate], counts[matrixflag.ChangeUpdate], counts[matrixflag.ChangeDelete])
+	default:
+		fmt.Fprintf(c.stdout, "Applied: %d created, %d updated, %d deleted.\n",
+			counts[matrixflag.ChangeCreate], counts[matrixflag.ChangeUpdate], counts[matrixflag.ChangeDelete])
+	}
+	return nil
+}
+
+// changeSymbols prefix the changes of an apply
+var changeSymbols = map[matrixflag.ChangeAction]string{
+	matrixflag.ChangeCreate: "+",
+	matrixflag.ChangeUpdate: "~",
+	matrixflag.ChangeDelete: "-",
+}
+
+// jsonValue formats a field value of a change
+func jsonValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApply(t *testing.T) {
+	c, stdout, api := newTestCLI(t,
+		matrixflag.FeatureFlag{Name: "checkout", Environment: "production"},
+		matrixflag.FeatureFlag{Name: "legacy", Environment: "production"},
+		matrixflag.FeatureFlag{Name: "legacy", Environment: "staging"},
+	)
+	file := filepath.Join(t.TempDir(), "flags.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`
+apiVersion: matrixflag.io/v1
+kind: FlagManifest
+environment: production
+flags:
+  - name: checkout
+    is_active: true
+  - name: dark-mode
+`), 0o600))
+	ctx := context.Background()
+
+	require.NoError(t, c.run(ctx, []string{"apply", "-f", file, "--dry-run"}))
+	assert.Equal(t, `+ create flag production/dark-mode
+    name: "dark-mode"
+    environment: "production"
+~ update flag production/checkout
+    is_active: false -> true
+- delete flag production/legacy
+Plan: 1 to create, 1 to update, 1 to delete.
+`, stdout.String())
+	assert.Len(t, api.flags, 3, "a dry run changes nothing")
+
+	stdout.Reset()
+	require.NoError(t, c.run(ctx, []string{"apply", "-f", file}))
+	assert.Contains(t, stdout.String(), "Applied: 1 created, 1 updated, 1 deleted.")
+	assert.True(t, api.flags[1].IsActive)
+	assert.Equal(t, "dark-mode", api.flags[4].Name)
+	_, ok := api.flags[2]
+	assert.False(t, ok, "flags missing from the manifest are pruned")
+	assert.Equal(t, "staging", api.flags[3].Environment, "other environments are left alone")
+
+	stdout.Reset()
+	require.NoError(t, c.run(ctx, []string{"apply", "-f", file, "--dry-run"}))
+	assert.Equal(t, "No changes.\n", stdout.String())
+}
+
+func TestApplyRejectsInvalidManifests(t *testing.T) {
+	c, _, _ := newTestCLI(t)
+	file := filepath.Join(t.TempDir(), "flags.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("apiVersion: matrixflag.io/v1\nkind: FlagManifest\nflags:\n  - name: checkout\n"), 0o600))
+	assert.ErrorContains(t, c.run(context.Background(), []string{"apply", "-f", file}), "flags[0].environment")
+	assert.ErrorContains(t, c.run(context.Background(), []string{"apply"}), "requires -f")
+}
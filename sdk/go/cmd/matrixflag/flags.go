@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+func (c *cli) list(ctx context.Context, args []string) error {
+	fs, common := c.newFlagSet("list")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+	flags, err := client.ListFeatureFlags(ctx, common.listFilter())
+	if err != nil {
+		return err
+	}
+	return c.printFlags(common.output, flags)
+}
+
+func (c *cli) get(ctx context.Context, args []string) error {
+	fs, common := c.newFlagSet("get")
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+	flag, err := resolveFlag(ctx, client, common, positional[0])
+	if err != nil {
+		return err
+	}
+	return c.printFlag(common.output, flag)
+}
+
+func (c *cli) create(ctx context.Context, args []string) error {
+	fs, common := c.newFlagSet("create")
+	description := fs.String("description", "", "description of the flag")
+	active := fs.Bool("active", false, "create the flag switched on")
+	rollout := fs.Float64("rollout", -1, "percentage of contexts served the flag")
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if common.env == "" {
+		return fmt.Errorf("create requires --env")
+	}
+	create := matrixflag.FeatureFlagCreate{
+		Name:        positional[0],
+		Description: *description,
+		IsActive:    *active,
+		Environment: common.env,
+		ProjectID:   common.project,
+	}
+	if *rollout >= 0 {
+		create.Rollout = &matrixflag.PercentageRollout{Percentage: *rollout}
+	}
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+	flag, err := client.CreateFeatureFlag(ctx, create)
+	if err != nil {
+		return err
+	}
+	return c.printFlag(common.output, flag)
+}
+
+func (c *cli) update(ctx context.Context, args []string) error {
+	fs, common := c.newFlagSet("update")
+	name := fs.String("name", "", "new name of the flag")
+	description := fs.String("description", "", "description of the flag, cleared when empty")
+	active := fs.Bool("active", false, "whether the flag is switched on")
+	rollout := fs.Float64("rollout", 0, "percentage of contexts served the flag")
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	// Only the given flags are changed, so --active=false and --description "" are sent explicitly
+	var update matrixflag.FeatureFlagUpdate
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "name":
+			update.Name = *name
+		case "description":
+			update.Description = *description
+			update.Fields = append(update.Fields, matrixflag.FieldDescription)
+		case "active":
+			update.IsActive = *active
+			update.Fields = append(update.Fields, matrixflag.FieldIsActive)
+		case "rollout":
+			update.Rollout = &matrixflag.PercentageRollout{Percentage: *rollout}
+		}
+	})
+	if update.Name == "" && update.Rollout == nil && len(update.Fields) == 0 {
+		return fmt.Errorf("update requires at least one of --name, --description, --active and --rollout")
+	}
+
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+	flag, err := resolveFlag(ctx, client, common, positional[0])
+	if err != nil {
+		return err
+	}
+	if update.Rollout != nil && flag.Rollout != nil {
+		// Keep the bucketing of the current rollout, so contexts stay in their buckets
+		rollout := *flag.Rollout
+		rollout.Percentage = update.Rollout.Percentage
+		update.Rollout = &rollout
+	}
+	flag, err = client.UpdateFeatureFlag(ctx, flag.ID, update)
+	if err != nil {
+		return err
+	}
+	return c.printFlag(common.output, flag)
+}
+
+func (c *cli) toggle(ctx context.Context, args []string) error {
+	fs, common := c.newFlagSet("toggle")
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+	flag, err := resolveFlag(ctx, client, common, positional[0])
+	if err != nil {
+		return err
+	}
+	flag, err = client.ToggleFeatureFlag(ctx, flag.ID)
+	if err != nil {
+		return err
+	}
+	return c.printFlag(common.output, flag)
+}
+
+func (c *cli) delete(ctx context.Context, args []string) error {
+	fs, common := c.newFlagSet("delete")
+	positional, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+	flag, err := resolveFlag(ctx, client, common, positional[0])
+	if err != nil {
+		return err
+	}
+	if _, err := client.DeleteFeatureFlag(ctx, flag.ID); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stdout, "deleted flag %d (%s/%s)\n", flag.ID, flag.Environment, flag.Name)
+	return nil
+}
+
+func (c *cli) export(ctx context.Context, args []string) error {
+	fs, common := c.newFlagSet("export")
+	file := fs.String("f", "", "file to write, standard output by default")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	if common.env == "" {
+		return fmt.Errorf("export requires --env")
+	}
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+	doc, err := client.ExportFlags(ctx, common.env)
+	if err != nil {
+		return err
+	}
+	if common.project != 0 {
+		flags := doc.Flags[:0]
+		for _, flag := range doc.Flags {
+			if flag.ProjectID == common.project {
+				flags = append(flags, flag)
+			}
+		}
+		doc.Flags = flags
+	}
+
+	w := c.stdout
+	if *file != "" {
+		f, err := os.Create(*file)
+		if err != nil {
+			return fmt.Errorf("failed to create export file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	return doc.Write(w)
+}
+
+func (c *cli) importFlags(ctx context.Context, args []string) error {
+	fs, common := c.newFlagSet("import")
+	file := fs.String("f", "", "file written by export, - for standard input")
+	overwrite := fs.Bool("overwrite", false, "update flags that exist to match the export")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	if *file == "" {
+		return fmt.Errorf("import requires -f")
+	}
+	var r io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return fmt.Errorf("failed to open import file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+	doc, err := matrixflag.LoadFlagExport(r)
+	if err != nil {
+		return err
+	}
+
+	// --env and --project move the flags, such as from staging to production
+	opts := matrixflag.ImportOptions{Env: common.env, Overwrite: *overwrite}
+	if common.project != 0 {
+		opts.Projects = make(map[int]int)
+		for _, flag := range doc.Flags {
+			opts.Projects[flag.ProjectID] = common.project
+		}
+	}
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+	result, err := client.ImportFlags(ctx, doc, opts)
+	if err != nil {
+		return err
+	}
+	for _, flag := range result.Skipped {
+		fmt.Fprintf(c.stderr, "skipped flag %s/%s, which exists\n", flag.Environment, flag.Name)
+	}
+	return c.printFlags(common.output, append(result.Created, result.Updated...))
+}
+
+// resolveFlag fetches a flag by ID, or by name among the flags matching the common flags
+func resolveFlag(ctx context.Context, client *matrixflag.Client, common *commonFlags, arg string) (*matrixflag.FeatureFlag, error) {
+	if id, err := strconv.Atoi(arg); err == nil {
+		return client.GetFeatureFlag(ctx, id)
+	}
+	flags, err := client.ListFeatureFlags(ctx, common.listFilter())
+	if err != nil {
+		return nil, err
+	}
+	var matches []matrixflag.FeatureFlag
+	for _, flag := range flags {
+		if flag.Name == arg {
+			matches = append(matches, flag)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("flag %s not found", arg)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf("flag %s is ambiguous, matching flags %s: select one with --env or its ID", arg, flagNames(matches))
+	}
+}
+
+func (c *cli) printFlag(output string, flag *matrixflag.FeatureFlag) error {
+	if output == "json" {
+		return writeJSON(c.stdout, flag)
+	}
+	return c.printFlags(output, []matrixflag.FeatureFlag{*flag})
+}
+
+func (c *cli) printFlags(output string, flags []matrixflag.FeatureFlag) error {
+	if output == "json" {
+		if flags == nil {
+			flags = []matrixflag.FeatureFlag{}
+		}
+		return writeJSON(c.stdout, flags)
+	}
+	w := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tENVIRONMENT\tPROJECT\tACTIVE\tROLLOUT\tDESCRIPTION")
+	for _, flag := range flags {
+		rollout := "-"
+		if flag.Rollout != nil {
+			rollout = strconv.FormatFloat(flag.Rollout.Percentage, 'f', -1, 64) + "%"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%t\t%s\t%s\n", flag.ID, flag.Name, flag.Environment, flag.ProjectID, flag.IsActive, rollout, flag.Description)
+	}
+	return w.Flush()
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
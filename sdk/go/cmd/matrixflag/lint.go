@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/matrixflag/sdk/lint"
+)
+
+// lint reports the flags referenced in Go code but missing on the server, and
+// the flags on the server unused in code, see package lint
+func (c *cli) lint(ctx context.Context, args []string) error {
+	fs, common := c.newFlagSet("lint")
+	failUnused := fs.Bool("fail-unused", false, "fail when flags on the server are unused in code")
+	patterns, err := parseArgs(fs, args, -1)
+	if err != nil {
+		return err
+	}
+	refs, err := lint.Scan(patterns...)
+	if err != nil {
+		return err
+	}
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+	flags, err := client.ListFeatureFlags(ctx, common.listFilter())
+	if err != nil {
+		return err
+	}
+	report := lint.Check(refs, flags)
+	if err := c.printReport(common.output, len(refs), report); err != nil {
+		return err
+	}
+	if len(report.Missing) > 0 {
+		return fmt.Errorf("%d flag reference(s) missing on the server", len(report.Missing))
+	}
+	if *failUnused && len(report.Unused) > 0 {
+		return fmt.Errorf("%d flag(s) unused in code", len(report.Unused))
+	}
+	return nil
+}
+
+// printReport prints a lint report, one line per finding as go vet does
+func (c *cli) printReport(output string, refs int, report *lint.Report) error {
+	if output == "json" {
+		return writeJSON(c.stdout, report)
+	}
+	for _, ref := range report.Missing {
+		fmt.Fprintf(c.stdout, "%s: flag %s is missing on the server\n", ref.Pos, ref.Flag)
+	}
+	for _, flag := range report.Unused {
+		fmt.Fprintf(c.stdout, "flag %s (%s) is unused in code\n", flag.Name, flag.Environment)
+	}
+	fmt.Fprintf(c.stdout, "%d flag reference(s) checked: %d missing on the server, %d flag(s) unused in code.\n", refs, len(report.Missing), len(report.Unused))
+	return nil
+}
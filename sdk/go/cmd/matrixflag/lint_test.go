@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/matrixflag/sdk/lint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	c, stdout, _ := newTestCLI(t,
+		matrixflag.FeatureFlag{Name: "new-checkout", Environment: "production"},
+		matrixflag.FeatureFlag{Name: "legacy-banner", Environment: "production"},
+		matrixflag.FeatureFlag{Name: "new-checkout", Environment: "staging"},
+	)
+	dir := t.TempDir()
+	file := filepath.Join(dir, "checkout.go")
+	require.NoError(t, os.WriteFile(file, []byte(`package checkout
+
+import (
+	"context"
+
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+func enabled(ctx context.Context, client *matrixflag.Client) bool {
+	return client.EvaluateBool(ctx, "new-checkout", matrixflag.EvaluationContext{}, false).Value
+}
+`), 0o600))
+	ctx := context.Background()
+
+	require.NoError(t, c.run(ctx, []string{"lint", "--env", "production", dir}))
+	assert.Equal(t, "flag legacy-banner (production) is unused in code\n1 flag reference(s) checked: 0 missing on the server, 1 flag(s) unused in code.\n", stdout.String())
+	assert.ErrorContains(t, c.run(ctx, []string{"lint", "--env", "production", "--fail-unused", dir}), "1 flag(s) unused in code")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "typo.go"), []byte(`package checkout
+
+func typo(ev interface{ IsEnabled(string, any) bool }) bool { return ev.IsEnabled("new-chekout", nil) }
+`), 0o600))
+	stdout.Reset()
+	err := c.run(ctx, []string{"lint", "--env", "staging", "-o", "json", dir + "/..."})
+	assert.EqualError(t, err, "1 flag reference(s) missing on the server")
+	var report lint.Report
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &report))
+	require.Len(t, report.Missing, 1)
+	assert.Equal(t, "new-chekout", report.Missing[0].Flag)
+	assert.Equal(t, 3, report.Missing[0].Pos.Line)
+	assert.Empty(t, report.Unused)
+}
@@ -0,0 +1,170 @@
+// Command matrixflag manages Matrix Flag feature flags from the command line.
+//
+//	matrixflag list --env production
+//	matrixflag get new-checkout --env production -o json
+//	matrixflag create new-checkout --env production --description "New checkout flow"
+//	matrixflag update new-checkout --env production --rollout 25
+//	matrixflag toggle new-checkout --env production
+//	matrixflag delete 42
+//	matrixflag export --env staging -f flags.json
+//	matrixflag import -f flags.json --env production --overwrite
+//	matrixflag apply -f flags.yaml --dry-run
+//	matrixflag lint --env production ./...
+//
+// The client is configured from the MATRIXFLAG_* environment variables read by
+// matrixflag.NewClientFromEnv; MATRIXFLAG_API_KEY holds the API key. When
+// MATRIXFLAG_CONFIG names a YAML, TOML or JSON file, the client is configured
+// from that file instead, see matrixflag.NewClientFromFile.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	c := &cli{stdout: os.Stdout, stderr: os.Stderr, newClient: func() (*matrixflag.Client, error) {
+		if path := os.Getenv("MATRIXFLAG_CONFIG"); path != "" {
+			return matrixflag.NewClientFromFile(path)
+		}
+		return matrixflag.NewClientFromEnv()
+	}}
+	if err := c.run(ctx, os.Args[1:]); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "matrixflag:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// cli runs the commands of the matrixflag command
+type cli struct {
+	stdout    io.Writer
+	stderr    io.Writer
+	newClient func() (*matrixflag.Client, error)
+	// commandUsage is the usage line of the running command
+	commandUsage string
+}
+
+// command is a subcommand of the matrixflag command
+type command struct {
+	usage   string
+	summary string
+	run     func(c *cli, ctx context.Context, args []string) error
+}
+
+// commands are the subcommands by name
+var commands = map[string]command{
+	"list":   {"list [--env ENV] [--project ID]", "list flags", (*cli).list},
+	"get":    {"get ID|NAME", "show a flag", (*cli).get},
+	"create": {"create NAME --env ENV [--description TEXT] [--active] [--rollout PERCENT]", "create a flag", (*cli).create},
+	"update": {"update ID|NAME [--name NAME] [--description TEXT] [--active=BOOL] [--rollout PERCENT]", "change the given fields of a flag", (*cli).update},
+	"toggle": {"toggle ID|NAME", "switch a flag on or off", (*cli).toggle},
+	"delete": {"delete ID|NAME", "delete a flag", (*cli).delete},
+	"export": {"export --env ENV [--project ID] [-f FILE]", "write the flags of an environment as JSON", (*cli).export},
+	"import": {"import -f FILE [--env ENV] [--project ID] [--overwrite]", "create the flags of an export", (*cli).importFlags},
+	"apply":  {"apply -f FILE [--dry-run] [--prune=BOOL] [--env ENV] [--project ID]", "converge flags and segments to a manifest", (*cli).apply},
+	"lint":   {"lint [--env ENV] [--project ID] [--fail-unused] [PATTERN...]", "report flags missing on the server or unused in Go code", (*cli).lint},
+}
+
+func (c *cli) run(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		c.usage()
+		if len(args) == 0 {
+			return errors.New("a command is required")
+		}
+		return nil
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		c.usage()
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+	c.commandUsage = cmd.usage
+	return cmd.run(c, ctx, args[1:])
+}
+
+func (c *cli) usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(c.stderr, "Usage: matrixflag COMMAND [FLAGS]\n\nCommands:")
+	for _, name := range names {
+		fmt.Fprintf(c.stderr, "  %-8s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(c.stderr, "\nThe API key is read from "+matrixflag.EnvAPIKey+". Run 'matrixflag COMMAND -h' for the flags of a command.")
+}
+
+// commonFlags are the flags shared by the commands
+type commonFlags struct {
+	env     string
+	project int
+	output  string
+}
+
+// newFlagSet creates the flag set of a command with the common flags
+func (c *cli) newFlagSet(name string) (*flag.FlagSet, *commonFlags) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: matrixflag %s\n\n", c.commandUsage)
+		fs.PrintDefaults()
+	}
+	common := &commonFlags{}
+	fs.StringVar(&common.env, "env", "", "environment of the flags")
+	fs.IntVar(&common.project, "project", 0, "project ID of the flags")
+	fs.StringVar(&common.output, "o", "table", "output format, table or json")
+	return fs, common
+}
+
+// parseArgs parses the flags of a command, which may follow its arguments, and
+// returns the arguments; want is their number, or -1 for any number
+func parseArgs(fs *flag.FlagSet, args []string, want int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	if want >= 0 && len(positional) != want {
+		fs.Usage()
+		return nil, fmt.Errorf("%s takes %d argument(s), got %d", fs.Name(), want, len(positional))
+	}
+	if output := fs.Lookup("o"); output != nil && output.Value.String() != "table" && output.Value.String() != "json" {
+		return nil, fmt.Errorf("invalid output format %q: must be table or json", output.Value.String())
+	}
+	return positional, nil
+}
+
+// listFilter returns the list filter of the common flags
+func (f *commonFlags) listFilter() matrixflag.FlagFilter {
+	return matrixflag.FlagFilter{Environment: f.env, ProjectID: f.project}
+}
+
+// flagNames lists the names of flags for error messages
+func flagNames(flags []matrixflag.FeatureFlag) string {
+	names := make([]string, len(flags))
+	for i, flag := range flags {
+		names[i] = fmt.Sprintf("%d (%s)", flag.ID, flag.Environment)
+	}
+	return strings.Join(names, ", ")
+}
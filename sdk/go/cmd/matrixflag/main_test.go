@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPI serves flag CRUD from memory, applying only the fields present in
+// updates, and no segments
+type fakeAPI struct {
+	mu     sync.Mutex
+	nextID int
+	flags  map[int]matrixflag.FeatureFlag
+}
+
+func (a *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if strings.HasPrefix(r.URL.Path, "/api/v1/targeting/segments") {
+		w.Write([]byte(`[]`))
+		return
+	}
+	rest := strings.TrimPrefix(r.URL.Path, "/api/v1/feature-flags/")
+	if rest == "" {
+		if r.Method == http.MethodPost {
+			var flag matrixflag.FeatureFlag
+			json.NewDecoder(r.Body).Decode(&flag)
+			a.nextID++
+			flag.ID = a.nextID
+			a.flags[flag.ID] = flag
+			json.NewEncoder(w).Encode(flag)
+			return
+		}
+		flags := []matrixflag.FeatureFlag{}
+		for id := 1; id <= a.nextID; id++ {
+			flag, ok := a.flags[id]
+			if env := r.URL.Query().Get("environment"); ok && (env == "" || env == flag.Environment) {
+				flags = append(flags, flag)
+			}
+		}
+		json.NewEncoder(w).Encode(flags)
+		return
+	}
+
+	idPart, action, _ := strings.Cut(rest, "/")
+	id, _ := strconv.Atoi(idPart)
+	flag, ok := a.flags[id]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"not found","code":"NOT_FOUND"}`))
+		return
+	}
+	switch {
+	case action == "toggle":
+		flag.IsActive = !flag.IsActive
+	case r.Method == http.MethodPut:
+		var fields map[string]json.RawMessage
+		json.NewDecoder(r.Body).Decode(&fields)
+		current, _ := json.Marshal(flag)
+		var merged map[string]json.RawMessage
+		json.Unmarshal(current, &merged)
+		for name, value := range fields {
+			merged[name] = value
+		}
+		data, _ := json.Marshal(merged)
+		flag = matrixflag.FeatureFlag{}
+		json.Unmarshal(data, &flag)
+	case r.Method == http.MethodDelete:
+		delete(a.flags, id)
+		w.Write([]byte(`{"message":"deleted"}`))
+		return
+	}
+	a.flags[id] = flag
+	json.NewEncoder(w).Encode(flag)
+}
+
+// newTestCLI returns a CLI talking to a fake API holding flags
+func newTestCLI(t *testing.T, flags ...matrixflag.FeatureFlag) (*cli, *bytes.Buffer, *fakeAPI) {
+	api := &fakeAPI{flags: make(map[int]matrixflag.FeatureFlag)}
+	for _, flag := range flags {
+		api.nextID++
+		flag.ID = api.nextID
+		api.flags[flag.ID] = flag
+	}
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	stdout := &bytes.Buffer{}
+	c := &cli{stdout: stdout, stderr: &bytes.Buffer{}, newClient: func() (*matrixflag.Client, error) {
+		return matrixflag.New("key", matrixflag.WithBaseURL(srv.URL), matrixflag.WithRetries(0, 0, 0)), nil
+	}}
+	return c, stdout, api
+}
+
+func TestListTable(t *testing.T) {
+	c, stdout, _ := newTestCLI(t,
+		matrixflag.FeatureFlag{Name: "checkout", Environment: "production", IsActive: true, Rollout: &matrixflag.PercentageRollout{Percentage: 25}},
+		matrixflag.FeatureFlag{Name: "search", Environment: "staging"},
+	)
+	require.NoError(t, c.run(context.Background(), []string{"list", "--env", "production"}))
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, []string{"ID", "NAME", "ENVIRONMENT", "PROJECT", "ACTIVE", "ROLLOUT", "DESCRIPTION"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"1", "checkout", "production", "0", "true", "25%"}, strings.Fields(lines[1]))
+}
+
+func TestGetByNameNeedsUniqueMatch(t *testing.T) {
+	c, stdout, _ := newTestCLI(t,
+		matrixflag.FeatureFlag{Name: "checkout", Environment: "production"},
+		matrixflag.FeatureFlag{Name: "checkout", Environment: "staging"},
+	)
+	ctx := context.Background()
+	assert.ErrorContains(t, c.run(ctx, []string{"get", "checkout"}), "ambiguous")
+
+	require.NoError(t, c.run(ctx, []string{"get", "checkout", "--env", "staging", "-o", "json"}))
+	var flag matrixflag.FeatureFlag
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &flag))
+	assert.Equal(t, 2, flag.ID)
+
+	assert.ErrorContains(t, c.run(ctx, []string{"get", "search", "--env", "staging"}), "not found")
+	assert.ErrorContains(t, c.run(ctx, []string{"get", "checkout", "-o", "yaml"}), "invalid output format")
+}
+
+func TestCreateUpdateToggleDelete(t *testing.T) {
+	c, _, api := newTestCLI(t)
+	ctx := context.Background()
+
+	assert.ErrorContains(t, c.run(ctx, []string{"create", "checkout"}), "requires --env")
+	require.NoError(t, c.run(ctx, []string{"create", "checkout", "--env", "production", "--description", "New checkout", "--active"}))
+	assert.Equal(t, matrixflag.FeatureFlag{ID: 1, Name: "checkout", Environment: "production", Description: "New checkout", IsActive: true}, api.flags[1])
+
+	require.NoError(t, c.run(ctx, []string{"update", "checkout", "--env", "production", "--active=false", "--description", "", "--rollout", "10"}))
+	assert.Equal(t, matrixflag.FeatureFlag{ID: 1, Name: "checkout", Environment: "production", Rollout: &matrixflag.PercentageRollout{Percentage: 10}}, api.flags[1])
+	assert.ErrorContains(t, c.run(ctx, []string{"update", "1"}), "at least one")
+
+	require.NoError(t, c.run(ctx, []string{"toggle", "1"}))
+	assert.True(t, api.flags[1].IsActive)
+
+	require.NoError(t, c.run(ctx, []string{"delete", "checkout"}))
+	assert.Empty(t, api.flags)
+}
+
+func TestExportImport(t *testing.T) {
+	c, _, api := newTestCLI(t,
+		matrixflag.FeatureFlag{Name: "checkout", Environment: "staging", IsActive: true, ProjectID: 3},
+		matrixflag.FeatureFlag{Name: "search", Environment: "staging"},
+		matrixflag.FeatureFlag{Name: "search", Environment: "production"},
+	)
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "flags.json")
+	require.NoError(t, c.run(ctx, []string{"export", "--env", "staging", "-f", file}))
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"checkout"`)
+
+	require.NoError(t, c.run(ctx, []string{"import", "-f", file, "--env", "production", "--project", "4"}))
+	require.Len(t, api.flags, 4, "the existing production search flag is skipped")
+	assert.Equal(t, matrixflag.FeatureFlag{ID: 4, Name: "checkout", Environment: "production", IsActive: true, ProjectID: 4}, api.flags[4])
+
+	require.NoError(t, c.run(ctx, []string{"import", "-f", file, "--env", "production", "--overwrite"}))
+	assert.Len(t, api.flags, 4)
+	assert.Equal(t, 3, api.flags[4].ProjectID, "without --project the exported project is kept")
+	assert.ErrorContains(t, c.run(ctx, []string{"export"}), "requires --env")
+}
+
+func TestUnknownCommand(t *testing.T) {
+	c, _, _ := newTestCLI(t)
+	assert.ErrorContains(t, c.run(context.Background(), []string{"frobnicate"}), "unknown command")
+	assert.Error(t, c.run(context.Background(), nil))
+}
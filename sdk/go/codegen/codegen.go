@@ -0,0 +1,388 @@
+// Package codegen generates a typed Go package for the flags of an
+// environment, so code evaluates flags through generated identifiers rather
+// than string keys:
+//
+//	if flags.NewCheckout.Enabled(ctx, user) {
+//		// ...
+//	}
+//
+// A flag deleted from the inventory disappears from the next generated
+// package, so code still using it fails to compile. The matrixflag-gen command
+// runs the generator on the flags of the API or of a manifest.
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"go/token"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+// DefaultPackage is the name of the generated package when Options sets none
+const DefaultPackage = "flags"
+
+// Options configures the generated package
+type Options struct {
+	// Package is the name of the generated package, DefaultPackage by default
+	Package string
+	// Source describes where the flags were read from, such as "environment
+	// production" or "flags.yaml", for the comments of the generated code
+	Source string
+}
+
+// Kind is the type of the value of a flag, which decides its accessor
+type Kind string
+
+// Flag kinds
+const (
+	// KindBool flags have no variations and serve true or false
+	KindBool Kind = "Bool"
+	// KindString, KindInt and KindFloat flags have variations serving values of that type
+	KindString Kind = "String"
+	KindInt    Kind = "Int"
+	KindFloat  Kind = "Float"
+	// KindJSON flags have variations with JSON payloads or values of mixed types
+	KindJSON Kind = "JSON"
+)
+
+// KindOf returns the kind of a flag from the values of its variations. Whole
+// numbers are integers, and a JSON value or mixed types make a JSON flag.
+func KindOf(flag matrixflag.FeatureFlag) Kind {
+	if len(flag.Variations) == 0 {
+		return KindBool
+	}
+	kind := Kind("")
+	for _, v := range flag.Variations {
+		k := valueKind(v.Value)
+		switch {
+		case kind == "":
+			kind = k
+		case kind == KindInt && k == KindFloat || kind == KindFloat && k == KindInt:
+			kind = KindFloat
+		case kind != k:
+			return KindJSON
+		}
+	}
+	return kind
+}
+
+// valueKind returns the kind of a variation value
+func valueKind(value any) Kind {
+	switch v := value.(type) {
+	case bool:
+		return KindBool
+	case string:
+		return KindString
+	case int, int64:
+		return KindInt
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) <= 1<<53 {
+			return KindInt
+		}
+		return KindFloat
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return KindInt
+		}
+		return KindFloat
+	}
+	return KindJSON
+}
+
+// Identifier returns the exported Go identifier of a flag name, in camel case
+// with common initialisms upper case ("new-checkout" is NewCheckout, "api-limits"
+// is APILimits), prefixed with Flag when the name starts with a digit
+func Identifier(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, word := range words {
+		if initialisms[strings.ToLower(word)] {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		r := []rune(word)
+		b.WriteString(string(unicode.ToUpper(r[0])) + string(r[1:]))
+	}
+	id := b.String()
+	if id == "" || unicode.IsDigit([]rune(id)[0]) {
+		id = "Flag" + id
+	}
+	return id
+}
+
+// initialisms are the words written upper case in identifiers, as Go code does
+var initialisms = map[string]bool{
+	"api": true, "html": true, "http": true, "https": true, "id": true, "ip": true,
+	"json": true, "sql": true, "ui": true, "uri": true, "url": true, "xml": true,
+}
+
+// genFlag is a flag as rendered in the generated package
+type genFlag struct {
+	Name        string
+	Ident       string
+	Kind        Kind
+	Description string
+	// Default is the Go expression of the value served when an evaluation
+	// fails, empty for the zero value
+	Default string
+}
+
+// scalarKind is a kind of flag served by variations of a Go scalar type
+type scalarKind struct {
+	Kind Kind
+	Type string
+	// Values describes the values served, for the doc comment of the flag type
+	Values string
+}
+
+// scalarKinds are the kinds of flags with a Value accessor and the Go types of their values
+var scalarKinds = []scalarKind{
+	{KindString, "string", "strings"},
+	{KindInt, "int", "integers"},
+	{KindFloat, "float64", "numbers"},
+}
+
+// Generate returns the source of a Go package with an accessor for every flag,
+// ordered by name. It fails when two flag names map to the same identifier.
+func Generate(flags []matrixflag.FeatureFlag, opts Options) ([]byte, error) {
+	if opts.Package == "" {
+		opts.Package = DefaultPackage
+	}
+	if !token.IsIdentifier(opts.Package) {
+		return nil, fmt.Errorf("invalid package name %q", opts.Package)
+	}
+	gen := make([]genFlag, 0, len(flags))
+	names := make(map[string]string, len(flags))
+	for _, flag := range flags {
+		ident := Identifier(flag.Name)
+		if reservedIdents[ident] {
+			ident += "Flag"
+		}
+		if other, ok := names[ident]; ok {
+			if other == flag.Name {
+				return nil, fmt.Errorf("flag %s is listed twice", flag.Name)
+			}
+			return nil, fmt.Errorf("flags %s and %s both map to the identifier %s", other, flag.Name, ident)
+		}
+		names[ident] = flag.Name
+		kind := KindOf(flag)
+		gen = append(gen, genFlag{
+			Name:        flag.Name,
+			Ident:       ident,
+			Kind:        kind,
+			Description: docText(flag.Description),
+			Default:     defaultValue(flag, kind),
+		})
+	}
+	sort.Slice(gen, func(i, j int) bool { return gen[i].Name < gen[j].Name })
+	kinds := make(map[Kind]bool)
+	for _, f := range gen {
+		kinds[f.Kind] = true
+	}
+	var scalars []scalarKind
+	for _, k := range scalarKinds {
+		if kinds[k.Kind] {
+			scalars = append(scalars, k)
+		}
+	}
+
+	var buf bytes.Buffer
+	err := packageTemplate.Execute(&buf, map[string]any{
+		"Package": opts.Package,
+		"Source":  opts.Source,
+		"Flags":   gen,
+		"Bool":    kinds[KindBool],
+		"Scalars": scalars,
+		"JSON":    kinds[KindJSON],
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render package: %w", err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format package: %w", err)
+	}
+	return src, nil
+}
+
+// reservedIdents are the identifiers declared by the generated package besides the flags
+var reservedIdents = map[string]bool{
+	"Use": true, "Keys": true,
+	"BoolFlag": true, "StringFlag": true, "IntFlag": true, "FloatFlag": true, "JSONFlag": true,
+}
+
+// docText returns a flag description as a single line for a doc comment
+func docText(description string) string {
+	return strings.Join(strings.Fields(description), " ")
+}
+
+// defaultValue returns the Go expression of the value a flag serves when its
+// evaluation fails, the value of its off variation, or "" for the zero value.
+// JSON flags serve nil, as their payloads have no Go type.
+func defaultValue(flag matrixflag.FeatureFlag, kind Kind) string {
+	v, ok := flag.Variation(flag.OffVariation)
+	if flag.OffVariation == "" || !ok {
+		return ""
+	}
+	switch kind {
+	case KindBool:
+		if v.Value == true {
+			return "true"
+		}
+	case KindString:
+		if s, _ := v.Value.(string); s != "" {
+			return strconv.Quote(s)
+		}
+	case KindInt, KindFloat:
+		if n, err := json.Marshal(v.Value); err == nil && string(n) != "0" {
+			return string(n)
+		}
+	}
+	return ""
+}
+
+var packageTemplate = template.Must(template.New("package").Parse(`// Code generated by matrixflag-gen{{with .Source}} from {{.}}{{end}}. DO NOT EDIT.
+
+// Package {{.Package}} gives typed access to the feature flags{{with .Source}} of {{.}}{{end}}.
+// Call Use with a client before evaluating them.
+package {{.Package}}
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+// The feature flags
+var (
+{{- range .Flags}}
+	// {{.Ident}} is the flag {{.Name}}{{with .Description}}: {{.}}{{end}}
+	{{.Ident}} = {{.Kind}}Flag{Key: {{printf "%q" .Name}}{{with .Default}}, Default: {{.}}{{end}}}
+{{- end}}
+)
+
+// Keys lists the keys of the flags
+var Keys = []string{
+{{- range .Flags}}
+	{{printf "%q" .Name}},
+{{- end}}
+}
+
+// evaluator evaluates the flags, see Use
+var evaluator atomic.Pointer[matrixflag.FlagEvaluator]
+
+// Use sets the client evaluating the flags, such as a *matrixflag.Client.
+// Until it is called, evaluations fail with the NOT_READY error code and serve
+// the default value of the flag.
+func Use(flags matrixflag.FlagEvaluator) {
+	evaluator.Store(&flags)
+}
+
+// current returns the client evaluating the flags, nil before Use
+func current() matrixflag.FlagEvaluator {
+	if flags := evaluator.Load(); flags != nil {
+		return *flags
+	}
+	return nil
+}
+
+// errNotReady is the error of evaluations made before Use
+var errNotReady = fmt.Errorf("%w: Use was not called", matrixflag.ErrEvaluatorNotReady)
+
+// notReady returns the detail of an evaluation made before Use
+func notReady[T any](defaultValue T) matrixflag.EvaluationDetail[T] {
+	return matrixflag.EvaluationDetail[T]{Value: defaultValue, Reason: matrixflag.ReasonError, ErrorCode: matrixflag.ErrorNotReady, Err: errNotReady}
+}
+{{- if .Bool}}
+
+// BoolFlag is a flag serving true or false
+type BoolFlag struct {
+	Key string
+	// Default is served when the evaluation fails
+	Default bool
+}
+
+// Enabled reports whether the flag is on for a context
+func (f BoolFlag) Enabled(ctx context.Context, evalCtx matrixflag.EvaluationContext) bool {
+	return f.Detail(ctx, evalCtx).Value
+}
+
+// Detail evaluates the flag for a context
+func (f BoolFlag) Detail(ctx context.Context, evalCtx matrixflag.EvaluationContext) matrixflag.EvaluationDetail[bool] {
+	flags := current()
+	if flags == nil {
+		return notReady(f.Default)
+	}
+	return flags.EvaluateBool(ctx, f.Key, evalCtx, f.Default)
+}
+{{- end}}
+{{- range .Scalars}}
+
+// {{.Kind}}Flag is a flag whose variations serve {{.Values}}
+type {{.Kind}}Flag struct {
+	Key string
+	// Default is served when the evaluation fails
+	Default {{.Type}}
+}
+
+// Value returns the value of the flag for a context
+func (f {{.Kind}}Flag) Value(ctx context.Context, evalCtx matrixflag.EvaluationContext) {{.Type}} {
+	return f.Detail(ctx, evalCtx).Value
+}
+
+// Detail evaluates the flag for a context
+func (f {{.Kind}}Flag) Detail(ctx context.Context, evalCtx matrixflag.EvaluationContext) matrixflag.EvaluationDetail[{{.Type}}] {
+	flags := current()
+	if flags == nil {
+		return notReady(f.Default)
+	}
+	return flags.Evaluate{{.Kind}}(ctx, f.Key, evalCtx, f.Default)
+}
+{{- end}}
+{{- if .JSON}}
+
+// JSONFlag is a flag whose variations serve JSON payloads
+type JSONFlag struct {
+	Key string
+	// Default is served when the evaluation fails
+	Default any
+}
+
+// Value returns the payload of the flag for a context
+func (f JSONFlag) Value(ctx context.Context, evalCtx matrixflag.EvaluationContext) any {
+	return f.Detail(ctx, evalCtx).Value
+}
+
+// Decode unmarshals the payload of the flag for a context into target, see
+// matrixflag.Client.GetJSONVariation
+func (f JSONFlag) Decode(ctx context.Context, evalCtx matrixflag.EvaluationContext, target any) error {
+	flags := current()
+	if flags == nil {
+		return errNotReady
+	}
+	return flags.GetJSONVariation(ctx, f.Key, evalCtx, target)
+}
+
+// Detail evaluates the flag for a context
+func (f JSONFlag) Detail(ctx context.Context, evalCtx matrixflag.EvaluationContext) matrixflag.EvaluationDetail[any] {
+	flags := current()
+	if flags == nil {
+		return notReady(f.Default)
+	}
+	return flags.EvaluateJSON(ctx, f.Key, evalCtx, f.Default)
+}
+{{- end}}
+`))
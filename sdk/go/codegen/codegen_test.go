@@ -0,0 +1,97 @@
+package codegen
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "regenerate the example package")
+
+// exampleFlags are the flags of the example package, one of each kind
+var exampleFlags = []matrixflag.FeatureFlag{
+	{Name: "new-checkout", Description: "New checkout\nflow"},
+	{Name: "theme", Variations: []matrixflag.Variation{
+		{Key: "light", Value: "light"}, {Key: "dark", Value: "dark"},
+	}, OffVariation: "light"},
+	{Name: "search.max_results", Variations: []matrixflag.Variation{
+		{Key: "few", Value: 10.0}, {Key: "many", Value: 50.0},
+	}, OffVariation: "few"},
+	{Name: "price-factor", Variations: []matrixflag.Variation{
+		{Key: "full", Value: 1.0}, {Key: "discount", Value: 0.8},
+	}},
+	{Name: "api-limits", Variations: []matrixflag.Variation{
+		{Key: "default", Value: map[string]any{"per_minute": 60}},
+	}},
+}
+
+func TestExamplePackageIsCurrent(t *testing.T) {
+	src, err := Generate(exampleFlags, Options{Package: "exampleflags", Source: "the codegen tests"})
+	require.NoError(t, err)
+	path := filepath.Join("internal", "exampleflags", "flags_gen.go")
+	if *update {
+		require.NoError(t, os.WriteFile(path, src, 0o644))
+	}
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(current), string(src), "run go test ./codegen -update to regenerate the example package")
+}
+
+func TestGenerate(t *testing.T) {
+	src, err := Generate([]matrixflag.FeatureFlag{{Name: "search"}, {Name: "dark-mode", Description: "Dark UI"}}, Options{})
+	require.NoError(t, err)
+	code := string(src)
+	assert.Contains(t, code, "package flags\n")
+	assert.Contains(t, code, "// DarkMode is the flag dark-mode: Dark UI\n")
+	assert.Contains(t, code, "DarkMode = BoolFlag{Key: \"dark-mode\"}\n")
+	assert.Contains(t, code, "var Keys = []string{\n\t\"dark-mode\",\n\t\"search\",\n}")
+	assert.NotContains(t, code, "StringFlag", "only the types of the flags' kinds are generated")
+
+	_, err = Generate([]matrixflag.FeatureFlag{{Name: "dark-mode"}, {Name: "dark_mode"}}, Options{})
+	assert.EqualError(t, err, "flags dark-mode and dark_mode both map to the identifier DarkMode")
+	_, err = Generate([]matrixflag.FeatureFlag{{Name: "search"}, {Name: "search"}}, Options{})
+	assert.EqualError(t, err, "flag search is listed twice")
+	_, err = Generate(nil, Options{Package: "my-flags"})
+	assert.EqualError(t, err, `invalid package name "my-flags"`)
+
+	src, err = Generate([]matrixflag.FeatureFlag{{Name: "use"}}, Options{})
+	require.NoError(t, err)
+	assert.Contains(t, string(src), "UseFlag = BoolFlag", "flags do not shadow the declarations of the package")
+}
+
+func TestIdentifier(t *testing.T) {
+	tests := map[string]string{
+		"new-checkout":       "NewCheckout",
+		"search.max_results": "SearchMaxResults",
+		"API v2":             "APIV2",
+		"2fa":                "Flag2fa",
+		"checkout.v2":        "CheckoutV2",
+		"api-limits":         "APILimits",
+		"user_id-hashing":    "UserIDHashing",
+	}
+	for name, want := range tests {
+		assert.Equal(t, want, Identifier(name), name)
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	variations := func(values ...any) matrixflag.FeatureFlag {
+		flag := matrixflag.FeatureFlag{}
+		for _, v := range values {
+			flag.Variations = append(flag.Variations, matrixflag.Variation{Value: v})
+		}
+		return flag
+	}
+	assert.Equal(t, KindBool, KindOf(matrixflag.FeatureFlag{}))
+	assert.Equal(t, KindBool, KindOf(variations(true, false)))
+	assert.Equal(t, KindString, KindOf(variations("a", "b")))
+	assert.Equal(t, KindInt, KindOf(variations(1.0, 2)))
+	assert.Equal(t, KindFloat, KindOf(variations(1.0, 2.5)))
+	assert.Equal(t, KindJSON, KindOf(variations("a", 1.0)))
+	assert.Equal(t, KindJSON, KindOf(variations(map[string]any{"a": 1})))
+}
@@ -0,0 +1,44 @@
+package exampleflags
+
+import (
+	"context"
+	"testing"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/matrixflag/sdk/matrixflagtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratedAccessors(t *testing.T) {
+	ctx := context.Background()
+	user := matrixflag.NewContext("user-1")
+
+	d := NewCheckout.Detail(ctx, user)
+	assert.False(t, d.Value)
+	assert.Equal(t, matrixflag.ErrorNotReady, d.ErrorCode, "flags serve their default before Use")
+	assert.Equal(t, 10, SearchMaxResults.Value(ctx, user))
+	assert.ErrorIs(t, APILimits.Decode(ctx, user, &struct{}{}), matrixflag.ErrEvaluatorNotReady)
+
+	td := matrixflagtest.NewTestDataSource()
+	td.Set("new-checkout", true)
+	td.Set("theme", "dark")
+	td.Set("search.max_results", 50)
+	td.Set("price-factor", 0.8)
+	td.Set("api-limits", map[string]any{"per_minute": 120})
+	Use(td.Client())
+
+	assert.True(t, NewCheckout.Enabled(ctx, user))
+	assert.Equal(t, "dark", Theme.Value(ctx, user))
+	assert.Equal(t, 50, SearchMaxResults.Value(ctx, user))
+	assert.Equal(t, 0.8, PriceFactor.Value(ctx, user))
+	var limits struct {
+		PerMinute int `json:"per_minute"`
+	}
+	require.NoError(t, APILimits.Decode(ctx, user, &limits))
+	assert.Equal(t, 120, limits.PerMinute)
+	td.AssertEvaluated(t, Keys...)
+
+	td.Delete("theme")
+	assert.Equal(t, "light", Theme.Value(ctx, user), "deleted flags serve their default")
+}
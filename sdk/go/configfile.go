@@ -0,0 +1,120 @@
+package matrixflag
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// configFileKeys are the keys of a configuration file, by the environment
+// variable of the same setting
+var configFileKeys = map[string]string{
+	EnvBaseURL:       "base_url",
+	EnvAPIKey:        "api_key",
+	EnvTimeout:       "timeout",
+	EnvMaxRetries:    "max_retries",
+	EnvRetryDelay:    "retry_delay",
+	EnvMaxRetryDelay: "max_retry_delay",
+	EnvProxyURL:      "proxy_url",
+	EnvNamespace:     "namespace",
+	EnvEnvironment:   "environment",
+	EnvOfflineFile:   "offline_file",
+}
+
+// NewClientFromFile creates a client configured from a YAML (.yaml, .yml),
+// TOML (.toml) or JSON (.json) file, chosen by its extension. The file holds
+// the settings of NewClientFromEnv in snake case, such as api_key, base_url,
+// timeout and max_retries, with the same defaults and validation:
+//
+//	base_url: https://flags.internal.example.com
+//	api_key: ${MATRIXFLAG_API_KEY}
+//	timeout: 5s
+//	environment: production
+//
+// String values expand ${VAR} references to environment variables, so secrets
+// such as the API key can stay out of the file, and a relative offline_file is
+// resolved against the directory of the file. Unknown keys and every invalid
+// setting are reported in the returned error.
+func NewClientFromFile(path string, opts ...ClientOption) (*Client, error) {
+	values, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if v := values[configFileKeys[EnvOfflineFile]]; v != "" && !filepath.IsAbs(v) {
+		values[configFileKeys[EnvOfflineFile]] = filepath.Join(filepath.Dir(path), v)
+	}
+	return newClientFromSettings(settingSource{
+		lookup: func(env string) (string, bool) {
+			v, ok := values[configFileKeys[env]]
+			return v, ok
+		},
+		name: func(env string) string { return configFileKeys[env] },
+		desc: "configuration file " + path,
+	}, opts...)
+}
+
+// envRef matches a ${VAR} reference in a configuration file
+var envRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnvRefs replaces the ${VAR} references of v with the values of the
+// environment variables; other dollar signs, as in API keys, are kept
+func expandEnvRefs(v string) string {
+	return envRef.ReplaceAllStringFunc(v, func(ref string) string {
+		return os.Getenv(ref[2 : len(ref)-1])
+	})
+}
+
+// readConfigFile reads the settings of a configuration file as strings, by key
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration file: %w", err)
+	}
+	var raw map[string]any
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml", ".json":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.NewDecoder(bytes.NewReader(data)).Decode(&raw)
+	default:
+		return nil, fmt.Errorf("failed to read configuration file %s: unsupported format %q, use .yaml, .yml, .toml or .json", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode configuration file %s: %w", path, err)
+	}
+
+	known := make(map[string]bool, len(configFileKeys))
+	for _, key := range configFileKeys {
+		known[key] = true
+	}
+	values := make(map[string]string, len(raw))
+	var problems []string
+	for key, v := range raw {
+		switch v := v.(type) {
+		case nil:
+			continue
+		case map[string]any, []any:
+			problems = append(problems, fmt.Sprintf("%s must be a single value", key))
+			continue
+		case string:
+			values[key] = expandEnvRefs(v)
+		default:
+			values[key] = fmt.Sprint(v)
+		}
+		if !known[key] {
+			problems = append(problems, fmt.Sprintf("unknown setting %s", key))
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("invalid Matrix Flag configuration file %s: %s", path, strings.Join(problems, "; "))
+	}
+	return values, nil
+}
@@ -0,0 +1,104 @@
+package matrixflag
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigFile writes a configuration file named name to a temporary directory
+func writeConfigFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestNewClientFromFile(t *testing.T) {
+	t.Setenv("TEST_MATRIXFLAG_KEY", "secret")
+	files := map[string]string{
+		"matrixflag.yaml": `
+base_url: https://flags.example.com/
+api_key: ${TEST_MATRIXFLAG_KEY}
+timeout: 5s
+max_retries: 5
+retry_delay: 0.5
+environment: production
+namespace:
+`,
+		"matrixflag.toml": `
+base_url = "https://flags.example.com/"
+api_key = "${TEST_MATRIXFLAG_KEY}"
+timeout = "5s"
+max_retries = 5
+retry_delay = 0.5
+environment = "production"
+`,
+	}
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			client, err := NewClientFromFile(writeConfigFile(t, name, content), WithNamespace("checkout"))
+			require.NoError(t, err)
+			assert.Equal(t, "https://flags.example.com", client.baseURL)
+			assert.Equal(t, "secret", client.apiKey)
+			assert.Equal(t, 5*time.Second, client.config.Timeout)
+			assert.Equal(t, 5, client.config.MaxRetries)
+			assert.Equal(t, 500*time.Millisecond, client.config.RetryDelay)
+			assert.Equal(t, 10*time.Second, client.config.MaxRetryDelay, "unset settings keep their default")
+			assert.Equal(t, "production", client.config.Environment)
+			assert.Equal(t, "checkout", client.config.Namespace, "options apply on top of the file")
+		})
+	}
+}
+
+func TestNewClientFromFileErrors(t *testing.T) {
+	path := writeConfigFile(t, "matrixflag.yml", `
+api_key: key
+timeout: soon
+max_retries: -1
+retries: 3
+proxy_url: [http://proxy]
+`)
+	_, err := NewClientFromFile(path)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "unknown setting retries")
+	assert.ErrorContains(t, err, "proxy_url must be a single value")
+
+	path = writeConfigFile(t, "matrixflag.yml", "timeout: soon\nmax_retries: -1\n")
+	_, err = NewClientFromFile(path)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "invalid Matrix Flag configuration file "+path)
+	assert.ErrorContains(t, err, "api_key is required")
+	assert.ErrorContains(t, err, `invalid timeout "soon"`)
+	assert.ErrorContains(t, err, `invalid max_retries "-1"`)
+
+	client, err := NewClientFromFile(writeConfigFile(t, "matrixflag.yml", "api_key: key$with$dollars\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "key$with$dollars", client.apiKey, "only ${VAR} references are expanded")
+
+	_, err = NewClientFromFile(writeConfigFile(t, "matrixflag.ini", "api_key=key"))
+	assert.ErrorContains(t, err, "unsupported format")
+}
+
+func TestNewClientFromFileOffline(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "flags.yaml"), []byte(`
+apiVersion: matrixflag.io/v1
+kind: OfflineFlags
+environment: production
+flags:
+  - id: 1
+    name: checkout
+    is_active: true
+`), 0o600))
+	path := filepath.Join(dir, "matrixflag.toml")
+	require.NoError(t, os.WriteFile(path, []byte(`offline_file = "flags.yaml"`), 0o600))
+
+	client, err := NewClientFromFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "flags.yaml"), client.config.OfflineFile, "the offline file is relative to the configuration file")
+	assert.True(t, client.isOffline())
+}
@@ -0,0 +1,147 @@
+package matrixflag
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Context kinds
+const (
+	// DefaultContextKind is the kind of contexts that do not set one
+	DefaultContextKind = "user"
+	// MultiContextKind is the kind of a context combining contexts of several kinds
+	MultiContextKind = "multi"
+)
+
+// ErrInvalidContext is returned when evaluating a flag for an invalid evaluation context
+var ErrInvalidContext = errors.New("invalid evaluation context")
+
+// EvaluationContext describes who or what a flag is evaluated for. An
+// individual context has a kind, such as user, organization or device, and a
+// key identifying it within that kind. A multi-kind context combines
+// individual contexts of different kinds, so a rule can target an
+// organization while rollouts bucket on the user.
+type EvaluationContext struct {
+	// Kind is the context kind, DefaultContextKind when empty
+	Kind string `json:"kind"`
+	// Key identifies the context within its kind; it is the default bucketing key
+	Key string `json:"key,omitempty"`
+	// Anonymous marks a context, such as a logged out visitor, that should not be stored by the server
+	Anonymous  bool           `json:"anonymous,omitempty"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+	// Contexts are the individual contexts of a multi-kind context
+	Contexts []EvaluationContext `json:"contexts,omitempty"`
+}
+
+// NewContext returns a context of DefaultContextKind identified by key
+func NewContext(key string) EvaluationContext {
+	return EvaluationContext{Kind: DefaultContextKind, Key: key}
+}
+
+// NewMultiContext returns a multi-kind context combining individual contexts of different kinds
+func NewMultiContext(contexts ...EvaluationContext) EvaluationContext {
+	return EvaluationContext{Kind: MultiContextKind, Contexts: contexts}
+}
+
+// With returns a copy of the context with the attribute name set to value
+func (c EvaluationContext) With(name string, value any) EvaluationContext {
+	attributes := make(map[string]any, len(c.Attributes)+1)
+	for k, v := range c.Attributes {
+		attributes[k] = v
+	}
+	attributes[name] = value
+	c.Attributes = attributes
+	return c
+}
+
+// kind returns the context kind, defaulting to DefaultContextKind
+func (c EvaluationContext) kind() string {
+	if c.Kind == "" {
+		return DefaultContextKind
+	}
+	return c.Kind
+}
+
+// IsMulti reports whether the context is a multi-kind context
+func (c EvaluationContext) IsMulti() bool {
+	return c.Kind == MultiContextKind
+}
+
+// Validate checks that an individual context has a key and that a multi-kind
+// context holds at least one individual context of each of its kinds
+func (c EvaluationContext) Validate() error {
+	if !c.IsMulti() {
+		if len(c.Contexts) > 0 {
+			return fmt.Errorf("%w: only a %s context can combine contexts", ErrInvalidContext, MultiContextKind)
+		}
+		if c.Key == "" {
+			return fmt.Errorf("%w: %s context has no key", ErrInvalidContext, c.kind())
+		}
+		return nil
+	}
+
+	if c.Key != "" || len(c.Attributes) > 0 {
+		return fmt.Errorf("%w: a %s context has no key or attributes of its own", ErrInvalidContext, MultiContextKind)
+	}
+	if len(c.Contexts) == 0 {
+		return fmt.Errorf("%w: %s context combines no contexts", ErrInvalidContext, MultiContextKind)
+	}
+	seen := make(map[string]bool, len(c.Contexts))
+	for _, individual := range c.Contexts {
+		if individual.IsMulti() {
+			return fmt.Errorf("%w: %s contexts cannot be nested", ErrInvalidContext, MultiContextKind)
+		}
+		if err := individual.Validate(); err != nil {
+			return err
+		}
+		kind := individual.kind()
+		if seen[kind] {
+			return fmt.Errorf("%w: more than one %s context", ErrInvalidContext, kind)
+		}
+		seen[kind] = true
+	}
+	return nil
+}
+
+// Individual returns the individual context of the given kind
+func (c EvaluationContext) Individual(kind string) (EvaluationContext, bool) {
+	if !c.IsMulti() {
+		return c, c.kind() == kind
+	}
+	for _, individual := range c.Contexts {
+		if individual.kind() == kind {
+			return individual, true
+		}
+	}
+	return EvaluationContext{}, false
+}
+
+// Flatten returns the context as the attribute map used for targeting and
+// bucketing. Every individual context contributes its key, anonymous flag and
+// attributes under its kind, such as "organization.key" or "device.os". A
+// single context, or the DefaultContextKind context of a multi-kind context,
+// also contributes them without the kind prefix, so "key" is its key.
+func (c EvaluationContext) Flatten() map[string]any {
+	individuals := []EvaluationContext{c}
+	if c.IsMulti() {
+		individuals = c.Contexts
+	}
+
+	attributes := make(map[string]any)
+	for _, individual := range individuals {
+		kind := individual.kind()
+		unprefixed := !c.IsMulti() || kind == DefaultContextKind
+		set := func(name string, value any) {
+			attributes[kind+"."+name] = value
+			if unprefixed {
+				attributes[name] = value
+			}
+		}
+		for name, value := range individual.Attributes {
+			set(name, value)
+		}
+		set("key", individual.Key)
+		set("anonymous", individual.Anonymous)
+	}
+	return attributes
+}
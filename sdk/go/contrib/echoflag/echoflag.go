@@ -0,0 +1,57 @@
+// Package echoflag evaluates Matrix Flag flags once per request in Echo servers
+// and gates routes with them, like matrixflag.HTTPMiddleware does for net/http.
+// It is a separate module, so only applications using it depend on Echo.
+package echoflag
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+// ContextExtractor returns the evaluation context of a request, such as the signed-in user
+type ContextExtractor func(c echo.Context) matrixflag.EvaluationContext
+
+// Middleware evaluates flagKeys once per request for the evaluation context
+// returned by extract, and stores the flags in the request context; other flags
+// are evaluated on first use, once per request too:
+//
+//	e.Use(echoflag.Middleware(client, userContext, "new-api"))
+func Middleware(flags matrixflag.FlagEvaluator, extract ContextExtractor, flagKeys ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			rf := matrixflag.NewRequestFlags(req.Context(), flags, extract(c), flagKeys)
+			c.SetRequest(req.WithContext(matrixflag.ContextWithFlags(req.Context(), rf)))
+			return next(c)
+		}
+	}
+}
+
+// FromContext returns the flags of a request served through Middleware, or nil
+func FromContext(c echo.Context) *matrixflag.RequestFlags {
+	return matrixflag.FromContext(c.Request().Context())
+}
+
+// Require fails requests with 404 Not Found unless a boolean flag is on for
+// them, hiding the routes it gates. It must run after Middleware.
+//
+//	e.GET("/v2/orders", listOrders, echoflag.Require("new-api"))
+func Require(flag string) echo.MiddlewareFunc {
+	return RequireStatus(flag, http.StatusNotFound)
+}
+
+// RequireStatus fails requests with an *echo.HTTPError of status unless a
+// boolean flag is on for them, such as 503 Service Unavailable for a kill
+// switch. It must run after Middleware.
+func RequireStatus(flag string, status int) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !FromContext(c).Bool(flag) {
+				return echo.NewHTTPError(status)
+			}
+			return next(c)
+		}
+	}
+}
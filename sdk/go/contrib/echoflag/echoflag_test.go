@@ -0,0 +1,46 @@
+package echoflag
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/matrixflag/sdk/matrixflagtest"
+)
+
+func TestMiddleware(t *testing.T) {
+	td := matrixflagtest.NewTestDataSource()
+	td.Set("new-api", true)
+	td.Set("refunds", false)
+	td.Set("banner", "spring-sale")
+	userContext := func(c echo.Context) matrixflag.EvaluationContext {
+		return matrixflag.NewContext(c.Request().Header.Get("X-User"))
+	}
+
+	e := echo.New()
+	e.Use(Middleware(td.Client(), userContext, "new-api"))
+	e.GET("/orders", func(c echo.Context) error { return c.String(http.StatusOK, FromContext(c).String("banner")) }, Require("new-api"))
+	e.GET("/refunds", func(c echo.Context) error { return c.NoContent(http.StatusOK) }, RequireStatus("refunds", http.StatusServiceUnavailable))
+	e.GET("/hidden", func(c echo.Context) error { return c.NoContent(http.StatusOK) }, Require("missing"))
+
+	for _, tt := range []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/orders", http.StatusOK, "spring-sale"},
+		{"/refunds", http.StatusServiceUnavailable, `{"message":"Service Unavailable"}` + "\n"},
+		{"/hidden", http.StatusNotFound, `{"message":"Not Found"}` + "\n"},
+	} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set("X-User", "user-1")
+		e.ServeHTTP(rec, req)
+		if rec.Code != tt.status || rec.Body.String() != tt.body {
+			t.Errorf("GET %s = %d %q, want %d %q", tt.path, rec.Code, rec.Body.String(), tt.status, tt.body)
+		}
+	}
+	td.AssertEvaluated(t, "new-api", "refunds", "banner")
+}
@@ -0,0 +1,52 @@
+// Package ginflag evaluates Matrix Flag flags once per request in Gin servers
+// and gates routes with them, like matrixflag.HTTPMiddleware does for net/http.
+// It is a separate module, so only applications using it depend on Gin.
+package ginflag
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+// ContextExtractor returns the evaluation context of a request, such as the signed-in user
+type ContextExtractor func(c *gin.Context) matrixflag.EvaluationContext
+
+// Middleware evaluates flagKeys once per request for the evaluation context
+// returned by extract, and stores the flags in the request context; other flags
+// are evaluated on first use, once per request too:
+//
+//	router.Use(ginflag.Middleware(client, userContext, "new-api"))
+func Middleware(flags matrixflag.FlagEvaluator, extract ContextExtractor, flagKeys ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rf := matrixflag.NewRequestFlags(c.Request.Context(), flags, extract(c), flagKeys)
+		c.Request = c.Request.WithContext(matrixflag.ContextWithFlags(c.Request.Context(), rf))
+		c.Next()
+	}
+}
+
+// FromContext returns the flags of a request served through Middleware, or nil
+func FromContext(c *gin.Context) *matrixflag.RequestFlags {
+	return matrixflag.FromContext(c.Request.Context())
+}
+
+// Require aborts requests with 404 Not Found unless a boolean flag is on for
+// them, hiding the routes it gates. It must run after Middleware.
+//
+//	router.GET("/v2/orders", ginflag.Require("new-api"), listOrders)
+func Require(flag string) gin.HandlerFunc {
+	return RequireStatus(flag, http.StatusNotFound)
+}
+
+// RequireStatus aborts requests with status unless a boolean flag is on for
+// them, such as 503 Service Unavailable for a kill switch. It must run after Middleware.
+func RequireStatus(flag string, status int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !FromContext(c).Bool(flag) {
+			c.AbortWithStatus(status)
+			return
+		}
+		c.Next()
+	}
+}
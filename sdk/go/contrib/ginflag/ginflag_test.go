@@ -0,0 +1,45 @@
+package ginflag
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/matrixflag/sdk/matrixflagtest"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	td := matrixflagtest.NewTestDataSource()
+	td.Set("new-api", true)
+	td.Set("refunds", false)
+	td.Set("banner", "spring-sale")
+	userContext := func(c *gin.Context) matrixflag.EvaluationContext { return matrixflag.NewContext(c.GetHeader("X-User")) }
+
+	router := gin.New()
+	router.Use(Middleware(td.Client(), userContext, "new-api"))
+	router.GET("/orders", Require("new-api"), func(c *gin.Context) { c.String(http.StatusOK, FromContext(c).String("banner")) })
+	router.GET("/refunds", RequireStatus("refunds", http.StatusServiceUnavailable), func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/hidden", Require("missing"), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, tt := range []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/orders", http.StatusOK, "spring-sale"},
+		{"/refunds", http.StatusServiceUnavailable, ""},
+		{"/hidden", http.StatusNotFound, ""},
+	} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set("X-User", "user-1")
+		router.ServeHTTP(rec, req)
+		if rec.Code != tt.status || rec.Body.String() != tt.body {
+			t.Errorf("GET %s = %d %q, want %d %q", tt.path, rec.Code, rec.Body.String(), tt.status, tt.body)
+		}
+	}
+	td.AssertEvaluated(t, "new-api", "refunds", "banner")
+}
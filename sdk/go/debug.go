@@ -0,0 +1,145 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxDebugDuration is the longest time a flag can stay in debug mode
+const MaxDebugDuration = 24 * time.Hour
+
+// DebugEvent is a full-fidelity record of one flag evaluation, emitted while
+// the flag is in debug mode to explain why a context got its result
+type DebugEvent struct {
+	FlagID      int       `json:"flag_id"`
+	FlagName    string    `json:"flag_name"`
+	Environment string    `json:"environment"`
+	Time        time.Time `json:"time"`
+	// Context holds the complete evaluation context, without redaction
+	Context map[string]any `json:"context"`
+	Value   any            `json:"value"`
+	// MatchedRule is the rule that produced the value, empty when the default was served
+	MatchedRule string `json:"matched_rule,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+// DebugEnabled reports whether the flag is in debug mode at time t
+func (f *FeatureFlag) DebugEnabled(t time.Time) bool {
+	return f.DebugUntil != nil && t.Before(*f.DebugUntil)
+}
+
+// maxPendingDebugEvents bounds the debug events an Evaluator buffers between flushes; later events are dropped
+const maxPendingDebugEvents = 1000
+
+// debugBuffer holds the debug events of local evaluations until they are sent
+type debugBuffer struct {
+	mu     sync.Mutex
+	events []DebugEvent
+}
+
+// add buffers an event, dropping it when the buffer is full
+func (b *debugBuffer) add(event DebugEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.events) < maxPendingDebugEvents {
+		b.events = append(b.events, event)
+	}
+}
+
+// take empties the buffer and returns its events
+func (b *debugBuffer) take() []DebugEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	events := b.events
+	b.events = nil
+	return events
+}
+
+// debugEvent records an evaluation of a flag in debug mode
+func debugEvent(flag *FeatureFlag, attributes map[string]any, d EvaluationDetail[any], t time.Time) DebugEvent {
+	return DebugEvent{
+		FlagID:      flag.ID,
+		FlagName:    flag.Name,
+		Environment: flag.Environment,
+		Time:        t,
+		Context:     attributes,
+		Value:       d.Value,
+		MatchedRule: d.RuleID,
+		Reason:      string(d.Reason),
+	}
+}
+
+// EnableDebug puts a flag in debug mode for the given duration, at most MaxDebugDuration
+func (c *Client) EnableDebug(ctx context.Context, flagID int, duration time.Duration, reqOpts ...RequestOption) (*FeatureFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if duration <= 0 || duration > MaxDebugDuration {
+		return nil, fmt.Errorf("invalid debug duration %s: must be positive and at most %s", duration, MaxDebugDuration)
+	}
+	return c.flagRequest(ctx, request{
+		method: "POST",
+		path:   fmt.Sprintf("/api/v1/feature-flags/%d/debug", flagID),
+		body:   map[string]int64{"duration_seconds": int64(duration / time.Second)},
+	})
+}
+
+// DisableDebug ends the debug mode of a flag
+func (c *Client) DisableDebug(ctx context.Context, flagID int, reqOpts ...RequestOption) (*FeatureFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.flagRequest(ctx, request{
+		method: "DELETE",
+		path:   fmt.Sprintf("/api/v1/feature-flags/%d/debug", flagID),
+	})
+}
+
+// SendDebugEvents records debug events of evaluations made outside the server
+func (c *Client) SendDebugEvents(ctx context.Context, events []DebugEvent, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if len(events) == 0 {
+		return nil
+	}
+	if c.namespacePrefix() != "" {
+		qualified := make([]DebugEvent, len(events))
+		for i, e := range events {
+			e.FlagName = c.qualifyName(e.FlagName)
+			qualified[i] = e
+		}
+		events = qualified
+	}
+	_, err := c.doRequest(ctx, request{
+		method: "POST",
+		path:   "/api/v1/feature-flags/debug-events",
+		body:   map[string][]DebugEvent{"events": events},
+		class:  EndpointBulk,
+	})
+	return err
+}
+
+// ListDebugEvents retrieves the debug events of a flag recorded since the given time
+func (c *Client) ListDebugEvents(ctx context.Context, flagID int, since time.Time, reqOpts ...RequestOption) ([]DebugEvent, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	var params map[string]string
+	if !since.IsZero() {
+		params = map[string]string{"since": since.UTC().Format(time.RFC3339)}
+	}
+	respBody, err := c.doRequest(ctx, request{
+		method: "GET",
+		path:   fmt.Sprintf("/api/v1/feature-flags/%d/debug-events", flagID),
+		query:  params,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var events []DebugEvent
+	if err := json.Unmarshal(respBody, &events); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	for i := range events {
+		events[i].FlagName = strings.TrimPrefix(events[i].FlagName, c.namespacePrefix())
+	}
+	return events, nil
+}
@@ -0,0 +1,55 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluatorRecordsDebugEvents(t *testing.T) {
+	var sent []DebugEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Events []DebugEvent `json:"events"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		sent = append(sent, body.Events...)
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	client := NewClient(srv.URL, "key", nil, WithClock(fixedClock{now}))
+	until, expired := now.Add(time.Minute), now.Add(-time.Minute)
+	evaluator := NewEvaluator(client, EvaluatorOptions{Environment: "production"})
+	evaluator.SetRuleset(&Ruleset{Environment: "production", Flags: []FeatureFlag{
+		{ID: 1, Name: "debugged", Environment: "production", IsActive: true, DebugUntil: &until, Rules: []FlagRule{
+			{ID: "nl", Conditions: []TargetingCondition{{Attribute: "country", Operator: OpEquals, Value: "NL"}}},
+		}},
+		{ID: 2, Name: "expired", Environment: "production", IsActive: true, DebugUntil: &expired},
+		{ID: 3, Name: "plain", Environment: "production", IsActive: true},
+	}})
+
+	user := EvaluationContext{Key: "user-1", Attributes: map[string]any{"country": "NL"}}
+	for _, name := range []string{"debugged", "expired", "plain"} {
+		require.NoError(t, evaluator.Evaluate(name, user, false).Err)
+	}
+	require.NoError(t, evaluator.FlushDebugEvents(context.Background()))
+
+	require.Len(t, sent, 1, "only the flag in its debug window is recorded")
+	assert.Equal(t, "debugged", sent[0].FlagName)
+	assert.Equal(t, "nl", sent[0].MatchedRule)
+	assert.Equal(t, string(ReasonRuleMatch), sent[0].Reason)
+	assert.Equal(t, "NL", sent[0].Context["country"])
+	assert.True(t, now.Equal(sent[0].Time))
+
+	sent = nil
+	require.NoError(t, evaluator.FlushDebugEvents(context.Background()))
+	assert.Empty(t, sent, "flushed events are not sent again")
+}
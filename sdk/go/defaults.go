@@ -0,0 +1,216 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// Levels of the defaults inheritance chain, from the least to the most specific
+const (
+	DefaultsLevelProject     = "project"
+	DefaultsLevelEnvironment = "environment"
+	DefaultsLevelFlag        = "flag"
+)
+
+// FlagDefaults are flag values defined at one level of the inheritance chain.
+// Unset fields are inherited from the level above; metadata is merged per key.
+type FlagDefaults struct {
+	IsActive          *bool              `json:"is_active,omitempty"`
+	TrafficAllocation *TrafficAllocation `json:"traffic_allocation,omitempty"`
+	Metadata          map[string]any     `json:"metadata,omitempty"`
+}
+
+// DefaultsLevel is one level of the inheritance chain
+type DefaultsLevel struct {
+	Name     string
+	Defaults FlagDefaults
+}
+
+// ResolvedFlag is a flag with its inherited values applied
+type ResolvedFlag struct {
+	Flag FeatureFlag
+	// Origins maps "is_active", "traffic_allocation" and "metadata.<key>" to the level
+	// that provided the resolved value
+	Origins map[string]string
+}
+
+// ResolveDefaults applies the inheritance chain to a flag. The chain is ordered
+// from the least to the most specific level, and the flag's Overrides form the
+// last level. Flags that do not inherit defaults are returned unchanged, with
+// every value originating from the flag itself.
+func ResolveDefaults(flag FeatureFlag, chain ...DefaultsLevel) ResolvedFlag {
+	resolved := ResolvedFlag{Flag: flag, Origins: map[string]string{}}
+	if !flag.InheritDefaults {
+		resolved.Origins["is_active"] = DefaultsLevelFlag
+		resolved.Origins["traffic_allocation"] = DefaultsLevelFlag
+		for key := range flag.Metadata {
+			resolved.Origins["metadata."+key] = DefaultsLevelFlag
+		}
+		return resolved
+	}
+
+	if flag.Overrides != nil {
+		chain = append(chain[:len(chain):len(chain)], DefaultsLevel{Name: DefaultsLevelFlag, Defaults: *flag.Overrides})
+	}
+	f := &resolved.Flag
+	f.IsActive = false
+	f.TrafficAllocation = nil
+	f.Metadata = nil
+	for _, level := range chain {
+		d := level.Defaults
+		if d.IsActive != nil {
+			f.IsActive = *d.IsActive
+			resolved.Origins["is_active"] = level.Name
+		}
+		if d.TrafficAllocation != nil {
+			allocation := *d.TrafficAllocation
+			f.TrafficAllocation = &allocation
+			resolved.Origins["traffic_allocation"] = level.Name
+		}
+		for key, value := range d.Metadata {
+			if f.Metadata == nil {
+				f.Metadata = make(map[string]any)
+			}
+			f.Metadata[key] = value
+			resolved.Origins["metadata."+key] = level.Name
+		}
+	}
+	return resolved
+}
+
+// GetProjectDefaults retrieves the flag defaults of a project
+func (c *Client) GetProjectDefaults(ctx context.Context, projectID int, reqOpts ...RequestOption) (*FlagDefaults, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.defaultsRequest(ctx, request{
+		method: "GET",
+		path:   fmt.Sprintf("/api/v1/projects/%d/defaults", projectID),
+	})
+}
+
+// UpdateProjectDefaults replaces the flag defaults of a project
+func (c *Client) UpdateProjectDefaults(ctx context.Context, projectID int, defaults FlagDefaults, reqOpts ...RequestOption) (*FlagDefaults, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := defaults.validate(); err != nil {
+		return nil, err
+	}
+	return c.defaultsRequest(ctx, request{
+		method: "PUT",
+		path:   fmt.Sprintf("/api/v1/projects/%d/defaults", projectID),
+		body:   defaults,
+	})
+}
+
+// GetEnvironmentDefaults retrieves the flag defaults of an environment of a project
+func (c *Client) GetEnvironmentDefaults(ctx context.Context, projectID int, environment string, reqOpts ...RequestOption) (*FlagDefaults, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.defaultsRequest(ctx, request{
+		method: "GET",
+		path:   fmt.Sprintf("/api/v1/projects/%d/environments/%s/defaults", projectID, url.PathEscape(environment)),
+	})
+}
+
+// UpdateEnvironmentDefaults replaces the flag defaults of an environment of a project
+func (c *Client) UpdateEnvironmentDefaults(ctx context.Context, projectID int, environment string, defaults FlagDefaults, reqOpts ...RequestOption) (*FlagDefaults, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := defaults.validate(); err != nil {
+		return nil, err
+	}
+	return c.defaultsRequest(ctx, request{
+		method: "PUT",
+		path:   fmt.Sprintf("/api/v1/projects/%d/environments/%s/defaults", projectID, url.PathEscape(environment)),
+		body:   defaults,
+	})
+}
+
+// ResolveFeatureFlag retrieves a flag together with the defaults of its project
+// and environment and resolves its inherited values
+func (c *Client) ResolveFeatureFlag(ctx context.Context, id int, reqOpts ...RequestOption) (*ResolvedFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	flag, err := c.GetFeatureFlag(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !flag.InheritDefaults || flag.ProjectID == 0 {
+		resolved := ResolveDefaults(*flag)
+		return &resolved, nil
+	}
+
+	project, err := c.GetProjectDefaults(ctx, flag.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get defaults of project %d: %w", flag.ProjectID, err)
+	}
+	environment, err := c.GetEnvironmentDefaults(ctx, flag.ProjectID, flag.Environment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get defaults of environment %s: %w", flag.Environment, err)
+	}
+	resolved := ResolveDefaults(*flag,
+		DefaultsLevel{Name: DefaultsLevelProject, Defaults: *project},
+		DefaultsLevel{Name: DefaultsLevelEnvironment, Defaults: *environment},
+	)
+	return &resolved, nil
+}
+
+// fetchRulesetDefaults fetches the project and environment defaults inherited by the flags of a ruleset
+func (c *Client) fetchRulesetDefaults(ctx context.Context, ruleset *Ruleset) error {
+	for _, flag := range ruleset.Flags {
+		if !flag.InheritDefaults || flag.ProjectID == 0 {
+			continue
+		}
+		if _, ok := ruleset.ProjectDefaults[flag.ProjectID]; ok {
+			continue
+		}
+		project, err := c.GetProjectDefaults(ctx, flag.ProjectID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch defaults of project %d: %w", flag.ProjectID, err)
+		}
+		environment, err := c.GetEnvironmentDefaults(ctx, flag.ProjectID, ruleset.Environment)
+		if err != nil {
+			return fmt.Errorf("failed to fetch defaults of environment %s: %w", ruleset.Environment, err)
+		}
+		if ruleset.ProjectDefaults == nil {
+			ruleset.ProjectDefaults = make(map[int]FlagDefaults)
+			ruleset.EnvironmentDefaults = make(map[int]FlagDefaults)
+		}
+		ruleset.ProjectDefaults[flag.ProjectID] = *project
+		ruleset.EnvironmentDefaults[flag.ProjectID] = *environment
+	}
+	return nil
+}
+
+// defaultsChain returns the inheritance chain of the flags of a project in the ruleset
+func (r *Ruleset) defaultsChain(projectID int) []DefaultsLevel {
+	var chain []DefaultsLevel
+	if d, ok := r.ProjectDefaults[projectID]; ok {
+		chain = append(chain, DefaultsLevel{Name: DefaultsLevelProject, Defaults: d})
+	}
+	if d, ok := r.EnvironmentDefaults[projectID]; ok {
+		chain = append(chain, DefaultsLevel{Name: DefaultsLevelEnvironment, Defaults: d})
+	}
+	return chain
+}
+
+// validate checks the values of a defaults level
+func (d FlagDefaults) validate() error {
+	if d.TrafficAllocation != nil {
+		if err := d.TrafficAllocation.Validate(); err != nil {
+			return fmt.Errorf("invalid defaults: %w", err)
+		}
+	}
+	return nil
+}
+
+// defaultsRequest performs a request returning flag defaults
+func (c *Client) defaultsRequest(ctx context.Context, req request) (*FlagDefaults, error) {
+	respBody, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var defaults FlagDefaults
+	if err := json.Unmarshal(respBody, &defaults); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return &defaults, nil
+}
@@ -0,0 +1,67 @@
+package matrixflag
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRulesetResolvesDefaults(t *testing.T) {
+	on, off := true, false
+	ruleset := &Ruleset{
+		Environment: "production",
+		Flags: []FeatureFlag{
+			{ID: 1, Name: "inherits", ProjectID: 3, InheritDefaults: true},
+			{ID: 2, Name: "overrides", ProjectID: 3, InheritDefaults: true, Overrides: &FlagDefaults{IsActive: &off}},
+			{ID: 3, Name: "own", ProjectID: 3, IsActive: false},
+			{ID: 4, Name: "environment", ProjectID: 4, InheritDefaults: true},
+		},
+		ProjectDefaults:     map[int]FlagDefaults{3: {IsActive: &on}, 4: {IsActive: &off}},
+		EnvironmentDefaults: map[int]FlagDefaults{4: {IsActive: &on}},
+	}
+	evaluator := NewEvaluator(NewClient("http://localhost", "key", nil), EvaluatorOptions{Environment: "production"})
+	evaluator.SetRuleset(ruleset)
+
+	user := EvaluationContext{Key: "user-1"}
+	for name, want := range map[string]bool{"inherits": true, "overrides": false, "own": false, "environment": true} {
+		enabled, err := evaluator.IsEnabled(name, user)
+		require.NoError(t, err)
+		assert.Equal(t, want, enabled, name)
+	}
+	assert.False(t, ruleset.Flags[0].IsActive, "the ruleset itself is not modified")
+}
+
+func TestFetchRulesetFetchesDefaults(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/feature-flags/", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, []FeatureFlag{
+			{ID: 1, Name: "a", Environment: "production", ProjectID: 3, InheritDefaults: true},
+			{ID: 2, Name: "b", Environment: "production", ProjectID: 3, InheritDefaults: true},
+			{ID: 3, Name: "c", Environment: "production", ProjectID: 5},
+		})
+	})
+	for _, path := range []string{"/api/v1/layers/", "/api/v1/holdouts/", "/api/v1/targeting/segments"} {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(`[]`)) })
+	}
+	var defaultsRequests []string
+	mux.HandleFunc("/api/v1/projects/", func(w http.ResponseWriter, r *http.Request) {
+		defaultsRequests = append(defaultsRequests, r.URL.Path)
+		w.Write([]byte(`{"is_active":true}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ruleset, err := NewClient(srv.URL, "key", nil).FetchRuleset(context.Background(), "production")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/api/v1/projects/3/defaults", "/api/v1/projects/3/environments/production/defaults"}, defaultsRequests,
+		"defaults are fetched once per project of a flag inheriting them")
+	require.Contains(t, ruleset.ProjectDefaults, 3)
+	assert.True(t, *ruleset.EnvironmentDefaults[3].IsActive)
+
+	offline := NewOfflineFlags(ruleset)
+	assert.Equal(t, ruleset.ProjectDefaults, offline.Ruleset().ProjectDefaults, "offline files keep the defaults")
+}
@@ -0,0 +1,157 @@
+package matrixflag
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables read by NewClientFromEnv; NewClientFromFile reads the same settings
+const (
+	EnvBaseURL       = "MATRIXFLAG_BASE_URL"
+	EnvAPIKey        = "MATRIXFLAG_API_KEY"
+	EnvTimeout       = "MATRIXFLAG_TIMEOUT"
+	EnvMaxRetries    = "MATRIXFLAG_MAX_RETRIES"
+	EnvRetryDelay    = "MATRIXFLAG_RETRY_DELAY"
+	EnvMaxRetryDelay = "MATRIXFLAG_MAX_RETRY_DELAY"
+	EnvProxyURL      = "MATRIXFLAG_PROXY_URL"
+	EnvNamespace     = "MATRIXFLAG_NAMESPACE"
+	EnvEnvironment   = "MATRIXFLAG_ENVIRONMENT"
+	EnvOfflineFile   = "MATRIXFLAG_OFFLINE_FILE"
+)
+
+// NewClientFromEnv creates a client configured from MATRIXFLAG_* environment variables.
+//
+// MATRIXFLAG_API_KEY is required unless MATRIXFLAG_OFFLINE_FILE selects offline
+// mode, in which case the file is loaded and validated as well.
+// MATRIXFLAG_BASE_URL defaults to DefaultBaseURL.
+// Durations accept Go duration strings ("30s", "1m") or a number of seconds, and
+// all other settings default to DefaultConfig. MATRIXFLAG_PROXY_URL overrides the
+// standard HTTP(S)_PROXY variables for API requests. Every invalid variable is
+// reported in the returned error.
+func NewClientFromEnv(opts ...ClientOption) (*Client, error) {
+	return newClientFromSettings(settingSource{
+		lookup: os.LookupEnv,
+		name:   func(env string) string { return env },
+		desc:   "environment configuration",
+	}, opts...)
+}
+
+// settingSource provides the settings of NewClientFromEnv and NewClientFromFile,
+// looked up by the name of their environment variable
+type settingSource struct {
+	lookup func(env string) (string, bool)
+	// name returns the name of a setting in the source, for error messages
+	name func(env string) string
+	// desc describes the source in the error reporting invalid settings
+	desc string
+}
+
+// newClientFromSettings creates a client from the settings of src
+func newClientFromSettings(src settingSource, opts ...ClientOption) (*Client, error) {
+	var errs []error
+	get := func(name string) string {
+		v, _ := src.lookup(name)
+		return strings.TrimSpace(v)
+	}
+
+	offlineFile := get(EnvOfflineFile)
+	if offlineFile != "" {
+		if _, err := LoadOfflineFlagsFile(offlineFile); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s: %w", src.name(EnvOfflineFile), err))
+		}
+	}
+
+	apiKey := get(EnvAPIKey)
+	if apiKey == "" && offlineFile == "" {
+		errs = append(errs, fmt.Errorf("%s is required", src.name(EnvAPIKey)))
+	}
+
+	baseURL := get(EnvBaseURL)
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	} else if err := validateHTTPURL(baseURL); err != nil {
+		errs = append(errs, fmt.Errorf("invalid %s %q: %w", src.name(EnvBaseURL), baseURL, err))
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	config := DefaultConfig()
+	durations := []struct {
+		name   string
+		target *time.Duration
+	}{
+		{EnvTimeout, &config.Timeout},
+		{EnvRetryDelay, &config.RetryDelay},
+		{EnvMaxRetryDelay, &config.MaxRetryDelay},
+	}
+	for _, d := range durations {
+		v := get(d.name)
+		if v == "" {
+			continue
+		}
+		parsed, err := parseEnvDuration(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s %q: %w", src.name(d.name), v, err))
+			continue
+		}
+		*d.target = parsed
+	}
+
+	if v := get(EnvMaxRetries); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			errs = append(errs, fmt.Errorf("invalid %s %q: must be a non-negative integer", src.name(EnvMaxRetries), v))
+		} else {
+			config.MaxRetries = n
+		}
+	}
+
+	if v := get(EnvProxyURL); v != "" {
+		if err := validateHTTPURL(v); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s: %w", src.name(EnvProxyURL), err))
+		} else {
+			proxyURL, _ := url.Parse(v)
+			config.Proxy = http.ProxyURL(proxyURL)
+		}
+	}
+
+	config.Namespace = get(EnvNamespace)
+	config.Environment = get(EnvEnvironment)
+	config.OfflineFile = offlineFile
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid Matrix Flag %s: %w", src.desc, errors.Join(errs...))
+	}
+	return NewClient(baseURL, apiKey, config, opts...), nil
+}
+
+// parseEnvDuration parses a Go duration string or a number of seconds
+func parseEnvDuration(v string) (time.Duration, error) {
+	var d time.Duration
+	if seconds, err := strconv.ParseFloat(v, 64); err == nil {
+		d = time.Duration(seconds * float64(time.Second))
+	} else if d, err = time.ParseDuration(v); err != nil {
+		return 0, errors.New(`must be a duration such as "30s" or a number of seconds`)
+	}
+	if d < 0 {
+		return 0, errors.New("must not be negative")
+	}
+	return d, nil
+}
+
+// validateHTTPURL checks that v is an absolute http or https URL
+func validateHTTPURL(v string) error {
+	u, err := url.Parse(v)
+	if err != nil {
+		return errors.New("not a valid URL")
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("must be an absolute http or https URL")
+	}
+	return nil
+}
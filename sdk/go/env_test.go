@@ -0,0 +1,111 @@
+package matrixflag
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setEnv sets the given environment variables for the duration of a test
+func setEnv(t *testing.T, vars map[string]string) {
+	for name, value := range vars {
+		t.Setenv(name, value)
+	}
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	setEnv(t, map[string]string{
+		EnvBaseURL:     "https://flags.example.com/",
+		EnvAPIKey:      " secret ",
+		EnvTimeout:     "5s",
+		EnvMaxRetries:  "5",
+		EnvRetryDelay:  "0.5",
+		EnvNamespace:   "web",
+		EnvEnvironment: "production",
+	})
+	client, err := NewClientFromEnv(WithNamespace("checkout"))
+	require.NoError(t, err)
+	assert.Equal(t, "https://flags.example.com", client.baseURL)
+	assert.Equal(t, "secret", client.apiKey, "values are trimmed")
+	assert.Equal(t, 5*time.Second, client.config.Timeout)
+	assert.Equal(t, 5, client.config.MaxRetries)
+	assert.Equal(t, 500*time.Millisecond, client.config.RetryDelay, "durations accept a number of seconds")
+	assert.Equal(t, DefaultConfig().MaxRetryDelay, client.config.MaxRetryDelay, "unset variables keep their default")
+	assert.Equal(t, "production", client.config.Environment)
+	assert.Equal(t, "checkout", client.config.Namespace, "options take precedence over the environment")
+}
+
+func TestNewClientFromEnvDefaults(t *testing.T) {
+	setEnv(t, map[string]string{EnvBaseURL: "", EnvAPIKey: "secret"})
+	client, err := NewClientFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultBaseURL, client.baseURL)
+	assert.Equal(t, DefaultConfig().Timeout, client.config.Timeout)
+	assert.Equal(t, DefaultConfig().MaxRetries, client.config.MaxRetries)
+}
+
+func TestNewClientFromEnvErrors(t *testing.T) {
+	setEnv(t, map[string]string{
+		EnvAPIKey:        "",
+		EnvBaseURL:       "flags.example.com",
+		EnvTimeout:       "soon",
+		EnvRetryDelay:    "-1s",
+		EnvMaxRetryDelay: "1m",
+		EnvMaxRetries:    "three",
+		EnvProxyURL:      "ftp://proxy.example.com",
+	})
+	_, err := NewClientFromEnv()
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "invalid Matrix Flag environment configuration")
+	for _, msg := range []string{
+		"MATRIXFLAG_API_KEY is required",
+		`invalid MATRIXFLAG_BASE_URL "flags.example.com": must be an absolute http or https URL`,
+		`invalid MATRIXFLAG_TIMEOUT "soon": must be a duration such as "30s" or a number of seconds`,
+		`invalid MATRIXFLAG_RETRY_DELAY "-1s": must not be negative`,
+		`invalid MATRIXFLAG_MAX_RETRIES "three": must be a non-negative integer`,
+		"invalid MATRIXFLAG_PROXY_URL: must be an absolute http or https URL",
+	} {
+		assert.ErrorContains(t, err, msg, "every invalid variable is reported")
+	}
+	assert.NotContains(t, err.Error(), "MATRIXFLAG_MAX_RETRY_DELAY")
+
+	t.Setenv(EnvOfflineFile, "/does/not/exist.json")
+	_, err = NewClientFromEnv()
+	assert.ErrorContains(t, err, "invalid MATRIXFLAG_OFFLINE_FILE")
+	assert.NotContains(t, err.Error(), "MATRIXFLAG_API_KEY is required", "offline mode needs no API key")
+}
+
+func TestNewClientFromEnvOffline(t *testing.T) {
+	path := writeOfflineFile(t, &Ruleset{Environment: "production", Flags: []FeatureFlag{{ID: 1, Name: "checkout", IsActive: true}}})
+	setEnv(t, map[string]string{EnvAPIKey: "", EnvOfflineFile: path})
+	client, err := NewClientFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, path, client.config.OfflineFile)
+}
+
+func TestNewClientFromEnvProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.Method + " " + r.URL.String()
+		writeJSON(w, FeatureFlag{ID: 1, Name: "checkout"})
+	}))
+	defer proxy.Close()
+	setEnv(t, map[string]string{
+		EnvAPIKey:     "secret",
+		EnvBaseURL:    "http://flags.example.com",
+		EnvProxyURL:   proxy.URL,
+		"HTTP_PROXY":  "http://unused.invalid",
+		"HTTPS_PROXY": "http://unused.invalid",
+	})
+	client, err := NewClientFromEnv()
+	require.NoError(t, err)
+
+	_, err = client.GetFeatureFlag(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "GET http://flags.example.com/api/v1/feature-flags/1", proxied, "MATRIXFLAG_PROXY_URL takes precedence over HTTP(S)_PROXY")
+}
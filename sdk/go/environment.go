@@ -0,0 +1,162 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// environmentKeyPattern matches valid environment keys
+var environmentKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// maxEnvironmentKeyLength is the longest environment key accepted by the API
+const maxEnvironmentKeyLength = 64
+
+// Environment is a deployment stage, such as staging or production. Flags
+// reference it by key in FeatureFlag.Environment, and each environment has its
+// own API key, so an SDK key for staging cannot read production flags.
+type Environment struct {
+	ID          int    `json:"id"`
+	Key         string `json:"key"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// ProjectID is the project of an environment scoped to one project, zero for shared environments
+	ProjectID int `json:"project_id,omitempty"`
+	// APIKey is the key of SDKs evaluating the environment's flags
+	APIKey    string    `json:"api_key,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// EnvironmentCreate represents the data needed to create an environment
+type EnvironmentCreate struct {
+	Key         string `json:"key"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	ProjectID   int    `json:"project_id,omitempty"`
+}
+
+// EnvironmentUpdate represents the data needed to update an environment. The
+// key cannot be changed, as flags reference it.
+type EnvironmentUpdate struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Fields names fields sent even when empty, such as FieldDescription to clear the description
+	Fields FieldMask `json:"-"`
+}
+
+// ValidateEnvironmentKey checks that key is a valid environment key: lowercase
+// letters, digits, underscores and hyphens, starting with a letter or digit
+func ValidateEnvironmentKey(key string) error {
+	switch {
+	case key == "":
+		return fmt.Errorf("environment key is required")
+	case len(key) > maxEnvironmentKeyLength:
+		return fmt.Errorf("invalid environment key %q: must be at most %d characters", key, maxEnvironmentKeyLength)
+	case !environmentKeyPattern.MatchString(key):
+		return fmt.Errorf("invalid environment key %q: must match %s", key, environmentKeyPattern)
+	}
+	return nil
+}
+
+// ListEnvironments retrieves all environments
+func (c *Client) ListEnvironments(ctx context.Context, reqOpts ...RequestOption) ([]Environment, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	respBody, err := c.doRequest(ctx, request{
+		method: "GET",
+		path:   "/api/v1/environments/",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var environments []Environment
+	if err := json.Unmarshal(respBody, &environments); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return environments, nil
+}
+
+// GetEnvironment retrieves an environment by key
+func (c *Client) GetEnvironment(ctx context.Context, key string, reqOpts ...RequestOption) (*Environment, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.environmentRequest(ctx, request{
+		method: "GET",
+		path:   "/api/v1/environments/" + url.PathEscape(key),
+	})
+}
+
+// CreateEnvironment creates a new environment, returning it with its API key
+func (c *Client) CreateEnvironment(ctx context.Context, environment EnvironmentCreate, reqOpts ...RequestOption) (*Environment, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := ValidateEnvironmentKey(environment.Key); err != nil {
+		return nil, err
+	}
+	return c.environmentRequest(ctx, request{
+		method: "POST",
+		path:   "/api/v1/environments/",
+		body:   environment,
+	})
+}
+
+// UpdateEnvironment updates an environment
+func (c *Client) UpdateEnvironment(ctx context.Context, key string, environment EnvironmentUpdate, reqOpts ...RequestOption) (*Environment, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.environmentRequest(ctx, request{
+		method: "PUT",
+		path:   "/api/v1/environments/" + url.PathEscape(key),
+		body:   environment,
+	})
+}
+
+// DeleteEnvironment deletes an environment
+func (c *Client) DeleteEnvironment(ctx context.Context, key string, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	_, err := c.doRequest(ctx, request{
+		method: "DELETE",
+		path:   "/api/v1/environments/" + url.PathEscape(key),
+	})
+	return err
+}
+
+// ValidateEnvironment checks that an environment with the given key exists,
+// naming the known environments otherwise, so a typo such as "prod" fails
+// before flags are written to it
+func (c *Client) ValidateEnvironment(ctx context.Context, key string, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := ValidateEnvironmentKey(key); err != nil {
+		return err
+	}
+	environments, err := c.ListEnvironments(ctx)
+	if err != nil {
+		return err
+	}
+	keys := make([]string, len(environments))
+	for i, environment := range environments {
+		if environment.Key == key {
+			return nil
+		}
+		keys[i] = environment.Key
+	}
+	sort.Strings(keys)
+	return fmt.Errorf("unknown environment %q: must be one of %s", key, strings.Join(keys, ", "))
+}
+
+// environmentRequest performs a request returning an environment
+func (c *Client) environmentRequest(ctx context.Context, req request) (*Environment, error) {
+	respBody, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var environment Environment
+	if err := json.Unmarshal(respBody, &environment); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return &environment, nil
+}
@@ -0,0 +1,61 @@
+package matrixflag
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironments(t *testing.T) {
+	var requests, bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		bodies = append(bodies, string(body))
+		switch {
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/api/v1/environments/" && r.Method == http.MethodGet:
+			writeJSON(w, []Environment{{ID: 1, Key: "staging"}, {ID: 2, Key: "production"}})
+		default:
+			writeJSON(w, Environment{ID: 3, Key: "qa", APIKey: "env-qa-key"})
+		}
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+
+	environment, err := client.CreateEnvironment(ctx, EnvironmentCreate{Key: "qa", Name: "QA"})
+	require.NoError(t, err)
+	assert.Equal(t, "env-qa-key", environment.APIKey)
+	_, err = client.GetEnvironment(ctx, "qa")
+	require.NoError(t, err)
+	_, err = client.UpdateEnvironment(ctx, "qa", EnvironmentUpdate{Fields: []string{FieldDescription}})
+	require.NoError(t, err)
+	require.NoError(t, client.DeleteEnvironment(ctx, "qa"))
+	assert.Equal(t, []string{
+		"POST /api/v1/environments/",
+		"GET /api/v1/environments/qa",
+		"PUT /api/v1/environments/qa",
+		"DELETE /api/v1/environments/qa",
+	}, requests)
+	assert.JSONEq(t, `{"key":"qa","name":"QA"}`, bodies[0])
+	assert.JSONEq(t, `{"description":""}`, bodies[2])
+
+	require.NoError(t, client.ValidateEnvironment(ctx, "production"))
+	assert.EqualError(t, client.ValidateEnvironment(ctx, "prod"), `unknown environment "prod": must be one of production, staging`)
+}
+
+func TestValidateEnvironmentKey(t *testing.T) {
+	assert.NoError(t, ValidateEnvironmentKey("eu-production_2"))
+	assert.Error(t, ValidateEnvironmentKey(""))
+	assert.Error(t, ValidateEnvironmentKey("Production"))
+	assert.Error(t, ValidateEnvironmentKey("-staging"))
+	_, err := NewClient("http://localhost", "key", nil).CreateEnvironment(context.Background(), EnvironmentCreate{Key: "my env"})
+	assert.Error(t, err, "invalid keys are rejected before any request")
+}
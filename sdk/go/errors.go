@@ -0,0 +1,58 @@
+package matrixflag
+
+import (
+	"errors"
+	"net/http"
+)
+
+// API error kinds, matched by errors.Is against the APIError returned for an
+// error response; errors.As still gives the APIError with its status code and
+// request ID
+var (
+	// ErrNotFound matches responses with status 404
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized matches responses with status 401 or 403, from a missing,
+	// invalid or insufficiently privileged API key
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrRateLimited matches responses with status 429
+	ErrRateLimited = errors.New("rate limited")
+	// ErrValidation matches responses with status 400 or 422, rejecting the request
+	ErrValidation = errors.New("validation failed")
+)
+
+// Is reports whether the error is of the kind of target, one of ErrNotFound,
+// ErrUnauthorized, ErrRateLimited or ErrValidation
+func (e APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	}
+	return false
+}
+
+// IsNotFound reports whether err is a not found API error, or an evaluation of
+// a flag missing from the ruleset
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound) || errors.Is(err, ErrFlagNotFound)
+}
+
+// IsUnauthorized reports whether err is an API error rejecting the API key
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsRateLimited reports whether err is an API error for exceeding the rate limit
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsValidation reports whether err is an API error rejecting an invalid request
+func IsValidation(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
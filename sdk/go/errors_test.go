@@ -0,0 +1,55 @@
+package matrixflag
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorKinds(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		kind   error
+		is     func(error) bool
+	}{
+		{http.StatusNotFound, `{"message":"flag not found","code":"not_found"}`, ErrNotFound, IsNotFound},
+		{http.StatusUnauthorized, `{"message":"invalid API key","code":"unauthorized"}`, ErrUnauthorized, IsUnauthorized},
+		{http.StatusForbidden, `{"message":"read-only API key","code":"forbidden"}`, ErrUnauthorized, IsUnauthorized},
+		{http.StatusTooManyRequests, `<html>Too Many Requests</html>`, ErrRateLimited, IsRateLimited},
+		{http.StatusUnprocessableEntity, `{"message":"invalid name","code":"validation"}`, ErrValidation, IsValidation},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(RequestIDHeader, "req-1")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+			client := NewClient(srv.URL, "key", nil, WithRetries(0, 0, 0))
+
+			_, err := client.GetFeatureFlag(context.Background(), 1)
+			assert.ErrorIs(t, err, tt.kind)
+			assert.True(t, tt.is(err))
+			for _, other := range []error{ErrNotFound, ErrUnauthorized, ErrRateLimited, ErrValidation} {
+				if other != tt.kind {
+					assert.False(t, errors.Is(err, other), "%v is not %v", err, other)
+				}
+			}
+			var apiErr APIError
+			require.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, tt.status, apiErr.StatusCode)
+			assert.Equal(t, "req-1", apiErr.RequestID)
+		})
+	}
+
+	assert.True(t, IsNotFound(errorDetail(false, ErrFlagNotFound).Err), "flags missing from a ruleset are not found too")
+	assert.False(t, IsNotFound(errors.New("boom")))
+	assert.Equal(t, "API error: Bad Gateway (status: 502)", APIError{StatusCode: 502, Message: "Bad Gateway"}.Error())
+}
@@ -0,0 +1,228 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// EvaluationReason explains why an evaluation produced its value
+type EvaluationReason string
+
+// Evaluation reasons
+const (
+	// ReasonRuleMatch means a targeting rule of the flag matched the context
+	ReasonRuleMatch EvaluationReason = "RULE_MATCH"
+	// ReasonDefault means the flag is on and no rule matched, so its default value was served
+	ReasonDefault EvaluationReason = "DEFAULT"
+	// ReasonOff means the flag is inactive and served its off value
+	ReasonOff EvaluationReason = "OFF"
+	// ReasonSplit means a percentage rollout decided the value by bucketing the context
+	ReasonSplit EvaluationReason = "SPLIT"
+	// ReasonExcluded means the context was kept out of the flag by a holdout, layer or traffic allocation
+	ReasonExcluded EvaluationReason = "EXCLUDED"
+	// ReasonPrerequisiteFailed means a prerequisite flag did not serve the required
+	// variation, so the flag served its off value
+	ReasonPrerequisiteFailed EvaluationReason = "PREREQUISITE_FAILED"
+	// ReasonError means the evaluation failed and the caller's default value was returned
+	ReasonError EvaluationReason = "ERROR"
+)
+
+// EvaluationErrorCode classifies evaluation errors
+type EvaluationErrorCode string
+
+// Evaluation error codes
+const (
+	ErrorFlagNotFound   EvaluationErrorCode = "FLAG_NOT_FOUND"
+	ErrorTypeMismatch   EvaluationErrorCode = "TYPE_MISMATCH"
+	ErrorNotReady       EvaluationErrorCode = "NOT_READY"
+	ErrorInvalidContext EvaluationErrorCode = "INVALID_CONTEXT"
+	ErrorFlagArchived   EvaluationErrorCode = "FLAG_ARCHIVED"
+	ErrorGeneral        EvaluationErrorCode = "GENERAL"
+)
+
+// EvaluationDetail is the result of a flag evaluation together with the reason for it
+type EvaluationDetail[T any] struct {
+	Value  T                `json:"value"`
+	Reason EvaluationReason `json:"reason"`
+	// Variation is the key of the variation served by a multivariate flag
+	Variation string `json:"variation,omitempty"`
+	// VariationIndex is the position of the served variation among the flag's
+	// variations, nil when the flag served no variation
+	VariationIndex *int `json:"variation_index,omitempty"`
+	// RuleID identifies the matched rule when Reason is ReasonRuleMatch
+	RuleID string `json:"rule_id,omitempty"`
+	// Prerequisite is the prerequisite flag that failed when Reason is ReasonPrerequisiteFailed
+	Prerequisite string `json:"prerequisite,omitempty"`
+	// Holdout is the holdout membership of the context for the flag, if a holdout applies
+	Holdout *HoldoutMembership `json:"holdout,omitempty"`
+	// ErrorCode and Err describe the failure when Reason is ReasonError
+	ErrorCode EvaluationErrorCode `json:"error_code,omitempty"`
+	Err       error               `json:"-"`
+}
+
+// errorDetail returns the detail of a failed evaluation serving defaultValue
+func errorDetail[T any](defaultValue T, err error) EvaluationDetail[T] {
+	code := ErrorGeneral
+	switch {
+	case IsNotFound(err):
+		code = ErrorFlagNotFound
+	case errors.Is(err, ErrEvaluatorNotReady):
+		code = ErrorNotReady
+	case errors.Is(err, ErrInvalidContext):
+		code = ErrorInvalidContext
+	case errors.Is(err, ErrFlagArchived):
+		code = ErrorFlagArchived
+	}
+	return EvaluationDetail[T]{Value: defaultValue, Reason: ReasonError, ErrorCode: code, Err: err}
+}
+
+// typedDetail converts the value of an evaluation, falling back to defaultValue on failure or type mismatch
+func typedDetail[T any](d EvaluationDetail[any], defaultValue T, convert func(any) (T, bool)) EvaluationDetail[T] {
+	if d.Reason == ReasonError {
+		return EvaluationDetail[T]{Value: defaultValue, Reason: ReasonError, ErrorCode: d.ErrorCode, Err: d.Err}
+	}
+	value, ok := convert(d.Value)
+	if !ok {
+		err := fmt.Errorf("flag value %v is a %T, not a %T", d.Value, d.Value, defaultValue)
+		return EvaluationDetail[T]{Value: defaultValue, Reason: ReasonError, ErrorCode: ErrorTypeMismatch, Err: err}
+	}
+	return EvaluationDetail[T]{
+		Value:          value,
+		Reason:         d.Reason,
+		Variation:      d.Variation,
+		VariationIndex: d.VariationIndex,
+		RuleID:         d.RuleID,
+		Prerequisite:   d.Prerequisite,
+		Holdout:        d.Holdout,
+	}
+}
+
+// asBool converts an evaluated value to a bool
+func asBool(v any) (bool, bool) {
+	b, ok := v.(bool)
+	return b, ok
+}
+
+// asString converts an evaluated value to a string
+func asString(v any) (string, bool) {
+	s, ok := v.(string)
+	return s, ok
+}
+
+// asFloat converts an evaluated value to a float64
+func asFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// asInt converts an evaluated value to an int, accepting only integral numbers
+func asInt(v any) (int, bool) {
+	if n, ok := v.(int); ok {
+		return n, true
+	}
+	f, ok := asFloat(v)
+	if !ok || f != math.Trunc(f) || math.Abs(f) > 1<<53 {
+		return 0, false
+	}
+	return int(f), true
+}
+
+// asJSON accepts any evaluated value
+func asJSON(v any) (any, bool) {
+	return v, true
+}
+
+// Evaluate evaluates a flag on the server for an evaluation context, in the
+// configured environment, or locally from the offline flag file in offline mode
+// and from the loaded ruleset once the client is started, see Start.
+// Failures are reported in the detail, which then carries defaultValue.
+func (c *Client) Evaluate(ctx context.Context, flagKey string, evalCtx EvaluationContext, defaultValue any, reqOpts ...RequestOption) EvaluationDetail[any] {
+	ctx = withRequestOptions(ctx, reqOpts)
+	ctx, end := c.Tracer().StartEvaluation(ctx, flagKey)
+	d := c.evaluate(ctx, flagKey, evalCtx, defaultValue)
+	end(d)
+	c.observeEvaluation(ctx, c.config.Environment, flagKey, evalCtx, d)
+	return d
+}
+
+// evaluate is Evaluate without tracing, logging, metrics and events
+func (c *Client) evaluate(ctx context.Context, flagKey string, evalCtx EvaluationContext, defaultValue any) EvaluationDetail[any] {
+	if err := evalCtx.Validate(); err != nil {
+		return errorDetail(defaultValue, err)
+	}
+	if c.isOffline() {
+		return c.evaluateOffline(flagKey, evalCtx, defaultValue)
+	}
+	if local := c.local.Load(); local != nil {
+		return c.evaluateStarted(local, flagKey, evalCtx, defaultValue)
+	}
+	respBody, err := c.doRequest(ctx, request{
+		method: "POST",
+		path:   "/api/v1/feature-flags/evaluate",
+		body: map[string]any{
+			"flag":        c.qualifyName(flagKey),
+			"environment": c.config.Environment,
+			"context":     evalCtx,
+		},
+	})
+	if err != nil {
+		return errorDetail(defaultValue, err)
+	}
+
+	var detail EvaluationDetail[any]
+	if err := json.Unmarshal(respBody, &detail); err != nil {
+		return errorDetail(defaultValue, fmt.Errorf("failed to unmarshal response: %w", err))
+	}
+	if detail.Reason == ReasonError {
+		if detail.ErrorCode == "" {
+			detail.ErrorCode = ErrorGeneral
+		}
+		detail.Value = defaultValue
+		detail.Err = fmt.Errorf("evaluation of flag %s failed: %s", flagKey, detail.ErrorCode)
+		if detail.ErrorCode == ErrorFlagArchived {
+			detail.Err = fmt.Errorf("%w: %s", ErrFlagArchived, flagKey)
+		}
+	}
+	return detail
+}
+
+// EvaluateBool evaluates a boolean flag on the server, see Evaluate
+func (c *Client) EvaluateBool(ctx context.Context, flagKey string, evalCtx EvaluationContext, defaultValue bool, reqOpts ...RequestOption) EvaluationDetail[bool] {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return typedDetail(c.Evaluate(ctx, flagKey, evalCtx, defaultValue), defaultValue, asBool)
+}
+
+// EvaluateString evaluates a string flag on the server, see Evaluate
+func (c *Client) EvaluateString(ctx context.Context, flagKey string, evalCtx EvaluationContext, defaultValue string, reqOpts ...RequestOption) EvaluationDetail[string] {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return typedDetail(c.Evaluate(ctx, flagKey, evalCtx, defaultValue), defaultValue, asString)
+}
+
+// EvaluateInt evaluates an integer flag on the server, see Evaluate
+func (c *Client) EvaluateInt(ctx context.Context, flagKey string, evalCtx EvaluationContext, defaultValue int, reqOpts ...RequestOption) EvaluationDetail[int] {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return typedDetail(c.Evaluate(ctx, flagKey, evalCtx, defaultValue), defaultValue, asInt)
+}
+
+// EvaluateFloat evaluates a numeric flag on the server, see Evaluate
+func (c *Client) EvaluateFloat(ctx context.Context, flagKey string, evalCtx EvaluationContext, defaultValue float64, reqOpts ...RequestOption) EvaluationDetail[float64] {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return typedDetail(c.Evaluate(ctx, flagKey, evalCtx, defaultValue), defaultValue, asFloat)
+}
+
+// EvaluateJSON evaluates a flag with a JSON value on the server, see Evaluate
+func (c *Client) EvaluateJSON(ctx context.Context, flagKey string, evalCtx EvaluationContext, defaultValue any, reqOpts ...RequestOption) EvaluationDetail[any] {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return typedDetail(c.Evaluate(ctx, flagKey, evalCtx, defaultValue), defaultValue, asJSON)
+}
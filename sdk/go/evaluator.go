@@ -0,0 +1,578 @@
+package matrixflag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultSyncInterval is the default interval between ruleset syncs of an Evaluator
+const DefaultSyncInterval = 30 * time.Second
+
+// Evaluation errors
+var (
+	// ErrEvaluatorNotReady is returned by evaluations before the first successful sync
+	ErrEvaluatorNotReady = errors.New("evaluator has not synced the ruleset yet")
+	// ErrFlagNotFound is returned when the evaluated flag is not part of the ruleset
+	ErrFlagNotFound = errors.New("flag not found")
+)
+
+// Ruleset is everything needed to evaluate the flags of an environment locally
+type Ruleset struct {
+	Environment string        `json:"environment"`
+	Flags       []FeatureFlag `json:"flags"`
+	Layers      []Layer       `json:"layers,omitempty"`
+	Holdouts    []Holdout     `json:"holdouts,omitempty"`
+	Segments    []Segment     `json:"segments,omitempty"`
+	// ProjectDefaults and EnvironmentDefaults hold the defaults inherited by the
+	// flags of each project, keyed by project ID, see ResolveDefaults
+	ProjectDefaults     map[int]FlagDefaults `json:"project_defaults,omitempty"`
+	EnvironmentDefaults map[int]FlagDefaults `json:"environment_defaults,omitempty"`
+	SyncedAt            time.Time            `json:"synced_at"`
+}
+
+// FetchRuleset retrieves the flags, experiment layers and holdouts of an environment, the segments they target,
+// and the project and environment defaults inherited by its flags. In offline mode it returns the ruleset of the offline flag file.
+func (c *Client) FetchRuleset(ctx context.Context, environment string, reqOpts ...RequestOption) (*Ruleset, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if environment == "" {
+		return nil, errors.New("invalid ruleset: an environment is required")
+	}
+	if c.isOffline() {
+		ix, err := c.offlineRuleset()
+		if err != nil {
+			return nil, err
+		}
+		if ix.Environment != environment {
+			return nil, fmt.Errorf("%w: the offline flag file holds environment %s, not %s", ErrOffline, ix.Environment, environment)
+		}
+		// Callers may modify the ruleset, so it must not share memory with the loaded file
+		return ix.Ruleset.clone()
+	}
+	flags, err := c.ListFeatureFlags(ctx, FlagFilter{Environment: environment})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch flags: %w", err)
+	}
+	layers, err := c.ListLayers(ctx, environment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch layers: %w", err)
+	}
+	holdouts, err := c.ListHoldouts(ctx, environment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch holdouts: %w", err)
+	}
+	segments, err := c.ListSegments(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch segments: %w", err)
+	}
+	ruleset := &Ruleset{
+		Environment: environment,
+		Flags:       flags,
+		Layers:      layers,
+		Holdouts:    holdouts,
+		Segments:    segments,
+		SyncedAt:    c.clock.Now(),
+	}
+	if err := c.fetchRulesetDefaults(ctx, ruleset); err != nil {
+		return nil, err
+	}
+	return ruleset, nil
+}
+
+// indexedRuleset is a ruleset indexed for evaluation
+type indexedRuleset struct {
+	*Ruleset
+	flags    map[string]*FeatureFlag
+	layers   map[int]*Layer
+	segments map[string]*Segment
+}
+
+// index builds the lookup tables of a ruleset
+func (r *Ruleset) index() *indexedRuleset {
+	ix := &indexedRuleset{
+		Ruleset:  r,
+		flags:    make(map[string]*FeatureFlag, len(r.Flags)),
+		layers:   make(map[int]*Layer, len(r.Layers)),
+		segments: make(map[string]*Segment, len(r.Segments)),
+	}
+	for i := range r.Flags {
+		flag := &r.Flags[i]
+		if flag.InheritDefaults {
+			// The ruleset may be shared with a store, so resolved values go into a copy
+			resolved := ResolveDefaults(*flag, r.defaultsChain(flag.ProjectID)...).Flag
+			flag = &resolved
+		}
+		ix.flags[flag.Name] = flag
+	}
+	for i := range r.Layers {
+		ix.layers[r.Layers[i].ID] = &r.Layers[i]
+	}
+	for i := range r.Segments {
+		ix.segments[r.Segments[i].Name] = &r.Segments[i]
+	}
+	return ix
+}
+
+// evaluate evaluates a flag for a context. It fails with ErrPrerequisiteCycle
+// when the prerequisites of the flag lead back to it.
+func (ix *indexedRuleset) evaluate(flag *FeatureFlag, evalCtx EvaluationContext) EvaluationDetail[any] {
+	return ix.trace(flag, evalCtx, nil)
+}
+
+// trace evaluates a flag for a context as evaluate, recording the checks made
+// in tr unless it is nil
+func (ix *indexedRuleset) trace(flag *FeatureFlag, evalCtx EvaluationContext, tr *EvaluationTrace) EvaluationDetail[any] {
+	d := ix.evaluateFlag(flag, evalCtx.Flatten(), nil, tr)
+	if i, ok := flag.variationIndex(d.Variation); ok && d.Variation != "" {
+		d.VariationIndex = &i
+	}
+	return d
+}
+
+// evaluateFlag evaluates a flag for flattened context attributes, with chain
+// holding the flags that require it, see checkPrerequisites. The checks made
+// are recorded in tr unless it is nil.
+func (ix *indexedRuleset) evaluateFlag(flag *FeatureFlag, attributes map[string]any, chain []string, tr *EvaluationTrace) EvaluationDetail[any] {
+	if flag.Archived() {
+		return EvaluationDetail[any]{Reason: ReasonError, ErrorCode: ErrorFlagArchived, Err: fmt.Errorf("%w: %s", ErrFlagArchived, flag.Name)}
+	}
+	tr.add(TraceStep{Check: CheckActive, Passed: flag.IsActive})
+	if !flag.IsActive {
+		return offDetail(flag, ReasonOff)
+	}
+	if d, failed := ix.checkPrerequisites(flag, attributes, chain, tr); failed {
+		return d
+	}
+	key, hasKey := BucketingKey(attributes, "")
+	if membership, out := HeldOutOf(ix.Holdouts, flag, key); hasKey && out {
+		tr.add(TraceStep{Check: CheckHoldout, Holdout: membership.HoldoutKey})
+		d := offDetail(flag, ReasonExcluded)
+		d.Holdout = membership
+		return d
+	}
+	if layer, ok := ix.layers[flag.LayerID]; ok {
+		allowed := hasKey && layer.Allows(flag.ID, key)
+		tr.add(TraceStep{Check: CheckLayer, Passed: allowed, Layer: layer.Key})
+		if !allowed {
+			return offDetail(flag, ReasonExcluded)
+		}
+	}
+	inTraffic := flag.InTrafficFor(attributes)
+	if flag.TrafficAllocation != nil {
+		tr.add(TraceStep{Check: CheckTraffic, Passed: inTraffic,
+			Bucketing: traceBucketing(attributes, flag.bucketBy(), flag.trafficSalt(), flag.TrafficAllocation.Percentage)})
+	}
+	if !inTraffic {
+		return offDetail(flag, ReasonExcluded)
+	}
+	for _, rule := range flag.Rules {
+		matched := ix.matchesRule(rule, attributes)
+		inRollout := matched && rule.inRolloutFor(flag.ID, attributes)
+		if tr != nil {
+			step := TraceStep{Check: CheckRule, Passed: inRollout, RuleID: rule.ID, Matched: matched}
+			if matched && rule.Rollout != nil {
+				step.Bucketing = traceBucketing(attributes, rule.Rollout.BucketBy, rule.rolloutSalt(flag.ID), rule.Rollout.Percentage)
+			}
+			tr.add(step)
+		}
+		if inRollout {
+			return ruleDetail(flag, rule)
+		}
+	}
+	inRollout := flag.InRolloutFor(attributes)
+	if flag.Rollout != nil {
+		tr.add(TraceStep{Check: CheckRollout, Passed: inRollout,
+			Bucketing: traceBucketing(attributes, flag.bucketBy(), flag.rolloutSalt(), flag.Rollout.Percentage)})
+	}
+	if !inRollout {
+		return offDetail(flag, ReasonSplit)
+	}
+	if len(flag.Variations) > 0 {
+		step := TraceStep{Check: CheckVariation}
+		if tr != nil {
+			step.Bucketing = traceBucketing(attributes, flag.bucketBy(), flag.variationSalt(), 0)
+		}
+		key, ok := BucketingKey(attributes, flag.bucketBy())
+		if !ok {
+			tr.add(step)
+			return offDetail(flag, ReasonExcluded)
+		}
+		if v, ok := flag.VariationFor(key); ok {
+			step.Passed, step.Variation = true, v.Key
+			tr.add(step)
+			return EvaluationDetail[any]{Value: v.Value, Reason: ReasonSplit, Variation: v.Key}
+		}
+		tr.add(step)
+	}
+	if flag.Rollout != nil {
+		return EvaluationDetail[any]{Value: true, Reason: ReasonSplit}
+	}
+	return EvaluationDetail[any]{Value: true, Reason: ReasonDefault}
+}
+
+// matchesRule reports whether a context matches the conditions of a rule and belongs to all of its
+// segments; rules targeting a segment missing from the ruleset never match
+func (ix *indexedRuleset) matchesRule(rule FlagRule, attributes map[string]any) bool {
+	if !matchesConditions(rule.Conditions, attributes) {
+		return false
+	}
+	for _, name := range rule.Segments {
+		segment, ok := ix.segments[name]
+		if !ok || !segment.Contains(attributes) {
+			return false
+		}
+	}
+	return true
+}
+
+// ruleDetail returns the detail serving the variation of a matched rule, or true when it has none
+func ruleDetail(flag *FeatureFlag, rule FlagRule) EvaluationDetail[any] {
+	d := EvaluationDetail[any]{Value: true, Reason: ReasonRuleMatch, RuleID: rule.ID}
+	if v, ok := flag.Variation(rule.Variation); rule.Variation != "" && ok {
+		d.Value, d.Variation = v.Value, v.Key
+	}
+	return d
+}
+
+// offDetail returns the detail serving a flag's off variation, or false when it has none
+func offDetail(flag *FeatureFlag, reason EvaluationReason) EvaluationDetail[any] {
+	if v, ok := flag.Variation(flag.OffVariation); flag.OffVariation != "" && ok {
+		return EvaluationDetail[any]{Value: v.Value, Reason: reason, Variation: v.Key}
+	}
+	return EvaluationDetail[any]{Value: false, Reason: reason}
+}
+
+// EvaluatorOptions configures an Evaluator
+type EvaluatorOptions struct {
+	// Environment is the environment whose flags are evaluated; it is required
+	Environment string
+	// SyncInterval is the time between ruleset syncs, DefaultSyncInterval by default
+	SyncInterval time.Duration
+	// Store persists the synced ruleset, a new MemoryStore by default. With a
+	// shared or persistent store the evaluator starts from the stored ruleset.
+	Store FlagStore
+	// SnapshotFile is the path of a last-known-good snapshot: every successful
+	// sync writes the ruleset there as an offline flag file, and Run starts from
+	// it when the store is empty, so a restart during an API outage keeps serving
+	// recent flags instead of defaults
+	SnapshotFile string
+	// Stream applies flag changes pushed by the server between syncs, see Client.StreamFlags
+	Stream bool
+	// OnError is called when a sync fails; evaluations keep using the last synced ruleset
+	OnError func(error)
+}
+
+// Evaluator evaluates the flags of an environment locally from an in-memory
+// copy of the ruleset, which it syncs from the server periodically. Evaluations
+// take no network round trip and keep working on the last synced ruleset while
+// the server is unreachable.
+type Evaluator struct {
+	client  *Client
+	opts    EvaluatorOptions
+	ruleset atomic.Pointer[indexedRuleset]
+	// mu serializes ruleset updates
+	mu    sync.Mutex
+	debug debugBuffer
+}
+
+// NewEvaluator creates an evaluator. Call Run, or Sync, before evaluating flags.
+func NewEvaluator(client *Client, opts EvaluatorOptions) *Evaluator {
+	if opts.SyncInterval <= 0 {
+		opts.SyncInterval = DefaultSyncInterval
+	}
+	if opts.Store == nil {
+		opts.Store = NewMemoryStore()
+	}
+	return &Evaluator{client: client, opts: opts}
+}
+
+// Run loads the stored ruleset, or else the snapshot, if any, then syncs the
+// ruleset immediately and every interval until ctx is canceled. With Stream set,
+// flag changes pushed by the server are applied in between. Debug events are
+// sent after every sync.
+func (e *Evaluator) Run(ctx context.Context) {
+	if !e.Ready() {
+		err := e.Load(ctx)
+		if errors.Is(err, ErrEvaluatorNotReady) && e.opts.SnapshotFile != "" {
+			if err = e.LoadSnapshot(ctx); errors.Is(err, os.ErrNotExist) {
+				err = nil
+			}
+		}
+		if err != nil && !errors.Is(err, ErrEvaluatorNotReady) {
+			e.reportError(err)
+		}
+	}
+	if e.opts.Stream {
+		go e.runStream(ctx)
+	}
+	for {
+		if err := e.Sync(ctx); err != nil && ctx.Err() == nil {
+			e.reportError(err)
+		}
+		if err := e.FlushDebugEvents(ctx); err != nil && ctx.Err() == nil {
+			e.reportError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-e.client.clock.After(e.opts.SyncInterval):
+		}
+	}
+}
+
+// runStream applies the events of a flag stream until ctx is canceled
+func (e *Evaluator) runStream(ctx context.Context) {
+	events, err := e.client.StreamFlags(ctx)
+	if err != nil {
+		e.reportError(fmt.Errorf("failed to open flag stream: %w", err))
+		return
+	}
+	for event := range events {
+		if err := e.Apply(ctx, event); err != nil {
+			e.reportError(err)
+		}
+	}
+}
+
+// reportError logs an error and passes it to the OnError callback, if any
+func (e *Evaluator) reportError(err error) {
+	e.client.Logger().Error("flag evaluator error", slog.String("environment", e.opts.Environment), slog.Any("error", err))
+	if e.opts.OnError != nil {
+		e.opts.OnError(err)
+	}
+}
+
+// Sync fetches the current ruleset from the server, saves it to the store and
+// swaps it in, then writes the snapshot file, if any. A failure to write the
+// snapshot is returned after the ruleset is swapped in.
+func (e *Evaluator) Sync(ctx context.Context) error {
+	ruleset, err := e.client.FetchRuleset(ctx, e.opts.Environment)
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := e.opts.Store.Init(ctx, ruleset); err != nil {
+		return fmt.Errorf("failed to store ruleset: %w", err)
+	}
+	e.ruleset.Store(ruleset.index())
+	e.client.Logger().LogAttrs(ctx, slog.LevelDebug, "synced ruleset",
+		slog.String("environment", ruleset.Environment),
+		slog.Int("flags", len(ruleset.Flags)),
+	)
+	if e.opts.SnapshotFile != "" {
+		return writeSnapshot(e.opts.SnapshotFile, ruleset)
+	}
+	return nil
+}
+
+// Load swaps in the ruleset of the store, such as one synced by another
+// instance or before a restart. It returns an error wrapping
+// ErrEvaluatorNotReady when the store is empty.
+func (e *Evaluator) Load(ctx context.Context) error {
+	ruleset, err := e.opts.Store.All(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load stored ruleset: %w", err)
+	}
+	if ruleset == nil {
+		return fmt.Errorf("%w: the flag store is empty", ErrEvaluatorNotReady)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.ruleset.Store(ruleset.index())
+	return nil
+}
+
+// Apply applies a flag change, such as one received from Client.StreamFlags,
+// to the store and the ruleset. Flags are matched by ID, so renaming a flag
+// replaces it. Changes are ignored before the first sync and for flags of
+// other environments.
+func (e *Evaluator) Apply(ctx context.Context, event FlagEvent) error {
+	if env := event.Flag.Environment; env != "" && env != e.opts.Environment {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	ix := e.ruleset.Load()
+	if ix == nil {
+		return nil
+	}
+
+	// Flags are matched by ID, so the change of a renamed flag also removes it under its old name
+	ruleset := ix.Ruleset
+	if stale := ix.staleName(event.Flag); stale != "" {
+		if err := e.opts.Store.Delete(ctx, stale); err != nil {
+			return fmt.Errorf("failed to store flag %s: %w", event.Flag.Name, err)
+		}
+		ruleset = ruleset.withoutFlag(stale)
+	}
+
+	var err error
+	if event.Type == FlagDeleted {
+		err = e.opts.Store.Delete(ctx, event.Flag.Name)
+		ruleset = ruleset.withoutFlag(event.Flag.Name)
+	} else {
+		err = e.opts.Store.Upsert(ctx, event.Flag)
+		ruleset = ruleset.withFlag(event.Flag)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store flag %s: %w", event.Flag.Name, err)
+	}
+	e.ruleset.Store(ruleset.index())
+	return nil
+}
+
+// staleName returns the name the ruleset holds the flag with the ID of flag
+// under, when it differs from the flag's name
+func (ix *indexedRuleset) staleName(flag FeatureFlag) string {
+	if flag.ID == 0 {
+		return ""
+	}
+	for _, f := range ix.Flags {
+		if f.ID == flag.ID && f.Name != flag.Name {
+			return f.Name
+		}
+	}
+	return ""
+}
+
+// SetRuleset replaces the ruleset used for evaluations without saving it to the store
+func (e *Evaluator) SetRuleset(ruleset *Ruleset) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.ruleset.Store(ruleset.index())
+}
+
+// Ruleset returns the ruleset currently used for evaluations, nil before the first sync.
+// It must not be modified.
+func (e *Evaluator) Ruleset() *Ruleset {
+	ix := e.ruleset.Load()
+	if ix == nil {
+		return nil
+	}
+	return ix.Ruleset
+}
+
+// Ready reports whether a ruleset has been synced
+func (e *Evaluator) Ready() bool {
+	return e.ruleset.Load() != nil
+}
+
+// IsEnabled evaluates a flag for a context. A flag is enabled when it is active,
+// the context is neither held out nor excluded by the flag's layer or traffic
+// allocation, and it is within the flag's percentage rollout, if any. Holdouts and layers bucket on the "key" attribute of the
+// flattened context, see EvaluationContext.Flatten.
+func (e *Evaluator) IsEnabled(flagKey string, evalCtx EvaluationContext) (bool, error) {
+	d := e.EvaluateBool(flagKey, evalCtx, false)
+	return d.Value, d.Err
+}
+
+// Evaluate evaluates a flag locally for a context. Failures are reported in the
+// detail, which then carries defaultValue. Evaluations of a flag in debug mode
+// are recorded as debug events, see FlushDebugEvents.
+func (e *Evaluator) Evaluate(flagKey string, evalCtx EvaluationContext, defaultValue any) EvaluationDetail[any] {
+	d, _ := e.evaluateWithFlag(flagKey, evalCtx, defaultValue)
+	return d
+}
+
+// evaluateWithFlag is Evaluate, also returning the flag evaluated, from the
+// same ruleset as the detail, or nil when the flag was not found
+func (e *Evaluator) evaluateWithFlag(flagKey string, evalCtx EvaluationContext, defaultValue any) (EvaluationDetail[any], *FeatureFlag) {
+	ctx, end := e.client.Tracer().StartEvaluation(context.Background(), flagKey)
+	d, flag := e.evaluate(flagKey, evalCtx, defaultValue)
+	end(d)
+	e.client.observeEvaluation(ctx, e.opts.Environment, flagKey, evalCtx, d)
+	return d, flag
+}
+
+// evaluate is evaluateWithFlag without tracing and the logging, metrics and
+// events of observeEvaluation
+func (e *Evaluator) evaluate(flagKey string, evalCtx EvaluationContext, defaultValue any) (EvaluationDetail[any], *FeatureFlag) {
+	ix := e.ruleset.Load()
+	if ix == nil {
+		e.client.Metrics().CacheLookup(false)
+		return errorDetail(defaultValue, ErrEvaluatorNotReady), nil
+	}
+	flag, ok := ix.flags[flagKey]
+	e.client.Metrics().CacheLookup(ok)
+	if !ok {
+		return errorDetail(defaultValue, fmt.Errorf("%w: %s", ErrFlagNotFound, flagKey)), nil
+	}
+	if err := evalCtx.Validate(); err != nil {
+		return errorDetail(defaultValue, err), flag
+	}
+	d := ix.evaluate(flag, evalCtx)
+	if d.Reason == ReasonError {
+		d = errorDetail(defaultValue, d.Err)
+	}
+	now := e.client.clock.Now()
+	e.client.observeExpiry(flag, now)
+	if flag.DebugEnabled(now) {
+		e.debug.add(debugEvent(flag, evalCtx.Flatten(), d, now))
+	}
+	return d, flag
+}
+
+// FlushDebugEvents sends the debug events recorded by evaluations of flags in
+// debug mode since the last flush. Run flushes them after every sync; events
+// that fail to send are dropped.
+func (e *Evaluator) FlushDebugEvents(ctx context.Context) error {
+	if err := e.client.SendDebugEvents(ctx, e.debug.take()); err != nil {
+		return fmt.Errorf("failed to send debug events: %w", err)
+	}
+	return nil
+}
+
+// GetVariation evaluates a multivariate flag locally for a context and returns the variation served
+func (e *Evaluator) GetVariation(flagKey string, evalCtx EvaluationContext) (*Variation, error) {
+	// The variation is looked up in the flag evaluated, as the ruleset may change meanwhile
+	d, flag := e.evaluateWithFlag(flagKey, evalCtx, nil)
+	if d.Err != nil {
+		return nil, d.Err
+	}
+	v, ok := flag.Variation(d.Variation)
+	if !ok || d.Variation == "" {
+		return nil, fmt.Errorf("flag %s served no variation", flagKey)
+	}
+	return v, nil
+}
+
+// GetJSONVariation evaluates a flag with a JSON payload locally for a context
+// and unmarshals the value served into target, see Client.GetJSONVariation
+func (e *Evaluator) GetJSONVariation(flagKey string, evalCtx EvaluationContext, target any) error {
+	d := e.Evaluate(flagKey, evalCtx, nil)
+	if d.Err != nil {
+		return d.Err
+	}
+	return decodeValue(flagKey, d.Value, target)
+}
+
+// EvaluateBool evaluates a boolean flag locally, see Evaluate
+func (e *Evaluator) EvaluateBool(flagKey string, evalCtx EvaluationContext, defaultValue bool) EvaluationDetail[bool] {
+	return typedDetail(e.Evaluate(flagKey, evalCtx, defaultValue), defaultValue, asBool)
+}
+
+// EvaluateString evaluates a string flag locally, see Evaluate
+func (e *Evaluator) EvaluateString(flagKey string, evalCtx EvaluationContext, defaultValue string) EvaluationDetail[string] {
+	return typedDetail(e.Evaluate(flagKey, evalCtx, defaultValue), defaultValue, asString)
+}
+
+// EvaluateInt evaluates an integer flag locally, see Evaluate
+func (e *Evaluator) EvaluateInt(flagKey string, evalCtx EvaluationContext, defaultValue int) EvaluationDetail[int] {
+	return typedDetail(e.Evaluate(flagKey, evalCtx, defaultValue), defaultValue, asInt)
+}
+
+// EvaluateFloat evaluates a numeric flag locally, see Evaluate
+func (e *Evaluator) EvaluateFloat(flagKey string, evalCtx EvaluationContext, defaultValue float64) EvaluationDetail[float64] {
+	return typedDetail(e.Evaluate(flagKey, evalCtx, defaultValue), defaultValue, asFloat)
+}
+
+// EvaluateJSON evaluates a flag with a JSON value locally, see Evaluate
+func (e *Evaluator) EvaluateJSON(flagKey string, evalCtx EvaluationContext, defaultValue any) EvaluationDetail[any] {
+	return typedDetail(e.Evaluate(flagKey, evalCtx, defaultValue), defaultValue, asJSON)
+}
@@ -0,0 +1,269 @@
+package matrixflag
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The cases rely on the buckets listed in TestBucketVectors
+func TestEvaluate(t *testing.T) {
+	variations := []Variation{
+		{Key: "control", Value: "A", Weight: 25},
+		{Key: "treatment", Value: "B", Weight: 75},
+	}
+	beta := Segment{Name: "beta", Included: []string{"user-9"}, Rules: []SegmentRule{
+		{Conditions: []TargetingCondition{{Attribute: "plan", Operator: OpEquals, Value: "enterprise"}}},
+	}}
+	globalHoldout := Holdout{ID: 1, Key: "q3", Percentage: 50}
+	listedHoldout := Holdout{ID: 2, Key: "q3", Percentage: 50, FlagIDs: []int{1}}
+	layer := Layer{ID: 7, Key: "checkout", Allocations: []LayerAllocation{{FlagID: 1, Start: 0, End: 50}}}
+
+	tests := []struct {
+		name      string
+		flag      FeatureFlag
+		ruleset   Ruleset
+		evalCtx   EvaluationContext
+		value     any
+		reason    EvaluationReason
+		variation string
+		heldOut   bool
+	}{
+		{
+			name:   "inactive",
+			flag:   FeatureFlag{ID: 1},
+			value:  false,
+			reason: ReasonOff,
+		},
+		{
+			name:      "inactive serves the off variation",
+			flag:      FeatureFlag{ID: 1, Variations: variations, OffVariation: "control"},
+			value:     "A",
+			reason:    ReasonOff,
+			variation: "control",
+		},
+		{
+			name:   "active",
+			flag:   FeatureFlag{ID: 1, IsActive: true},
+			value:  true,
+			reason: ReasonDefault,
+		},
+		{
+			name:    "plain flag ignores global holdouts",
+			flag:    FeatureFlag{ID: 1, IsActive: true},
+			ruleset: Ruleset{Holdouts: []Holdout{globalHoldout}},
+			evalCtx: EvaluationContext{Key: "user-3"},
+			value:   true,
+			reason:  ReasonDefault,
+		},
+		{
+			name:    "plain flag listed by a holdout",
+			flag:    FeatureFlag{ID: 1, IsActive: true},
+			ruleset: Ruleset{Holdouts: []Holdout{listedHoldout}},
+			evalCtx: EvaluationContext{Key: "user-3"},
+			value:   false,
+			reason:  ReasonExcluded,
+			heldOut: true,
+		},
+		{
+			name:      "experiment held out",
+			flag:      FeatureFlag{ID: 1, IsActive: true, Variations: variations, OffVariation: "control"},
+			ruleset:   Ruleset{Holdouts: []Holdout{globalHoldout}},
+			evalCtx:   EvaluationContext{Key: "user-3"},
+			value:     "A",
+			reason:    ReasonExcluded,
+			variation: "control",
+			heldOut:   true,
+		},
+		{
+			name:      "experiment outside the holdout",
+			flag:      FeatureFlag{ID: 1, IsActive: true, Variations: variations},
+			ruleset:   Ruleset{Holdouts: []Holdout{globalHoldout}},
+			evalCtx:   EvaluationContext{Key: "user-4"},
+			value:     "B",
+			reason:    ReasonSplit,
+			variation: "treatment",
+		},
+		{
+			name:    "layer allows its range",
+			flag:    FeatureFlag{ID: 1, IsActive: true, LayerID: 7},
+			ruleset: Ruleset{Layers: []Layer{layer}},
+			evalCtx: EvaluationContext{Key: "user-4"},
+			value:   true,
+			reason:  ReasonDefault,
+		},
+		{
+			name:    "layer excludes other ranges",
+			flag:    FeatureFlag{ID: 1, IsActive: true, LayerID: 7},
+			ruleset: Ruleset{Layers: []Layer{layer}},
+			evalCtx: EvaluationContext{Key: "user-1"},
+			value:   false,
+			reason:  ReasonExcluded,
+		},
+		{
+			name:    "inside the traffic allocation",
+			flag:    FeatureFlag{ID: 1, IsActive: true, TrafficAllocation: &TrafficAllocation{Percentage: 50}},
+			evalCtx: EvaluationContext{Key: "user-2"},
+			value:   true,
+			reason:  ReasonDefault,
+		},
+		{
+			name:    "outside the traffic allocation",
+			flag:    FeatureFlag{ID: 1, IsActive: true, TrafficAllocation: &TrafficAllocation{Percentage: 50}},
+			evalCtx: EvaluationContext{Key: "user-1"},
+			value:   false,
+			reason:  ReasonExcluded,
+		},
+		{
+			name:    "inside the rollout",
+			flag:    FeatureFlag{ID: 1, IsActive: true, Rollout: &PercentageRollout{Percentage: 35}},
+			evalCtx: EvaluationContext{Key: "user-1"},
+			value:   true,
+			reason:  ReasonSplit,
+		},
+		{
+			name:    "outside the rollout",
+			flag:    FeatureFlag{ID: 1, IsActive: true, Rollout: &PercentageRollout{Percentage: 35}},
+			evalCtx: EvaluationContext{Key: "user-2"},
+			value:   false,
+			reason:  ReasonSplit,
+		},
+		{
+			name:   "no bucketing key",
+			flag:   FeatureFlag{ID: 1, IsActive: true, Rollout: &PercentageRollout{Percentage: 100}},
+			value:  false,
+			reason: ReasonSplit,
+		},
+		{
+			name:      "multivariate split",
+			flag:      FeatureFlag{ID: 1, IsActive: true, Variations: variations},
+			evalCtx:   EvaluationContext{Key: "user-2"},
+			value:     "A",
+			reason:    ReasonSplit,
+			variation: "control",
+		},
+		{
+			name: "rule matching conditions",
+			flag: FeatureFlag{ID: 1, IsActive: true, Variations: variations, Rules: []FlagRule{
+				{ID: "nl", Conditions: []TargetingCondition{{Attribute: "country", Operator: OpEquals, Value: "NL"}}, Variation: "treatment"},
+			}},
+			evalCtx:   EvaluationContext{Key: "user-2", Attributes: map[string]any{"country": "NL"}},
+			value:     "B",
+			reason:    ReasonRuleMatch,
+			variation: "treatment",
+		},
+		{
+			name: "rule not matching falls through",
+			flag: FeatureFlag{ID: 1, IsActive: true, Rules: []FlagRule{
+				{ID: "nl", Conditions: []TargetingCondition{{Attribute: "country", Operator: OpEquals, Value: "NL"}}},
+			}, Rollout: &PercentageRollout{Percentage: 35}},
+			evalCtx: EvaluationContext{Key: "user-2", Attributes: map[string]any{"country": "DE"}},
+			value:   false,
+			reason:  ReasonSplit,
+		},
+		{
+			name:    "segment rule by attributes",
+			flag:    FeatureFlag{ID: 1, IsActive: true, Rules: []FlagRule{{ID: "beta", Segments: []string{"beta"}}}},
+			ruleset: Ruleset{Segments: []Segment{beta}},
+			evalCtx: EvaluationContext{Key: "user-1", Attributes: map[string]any{"plan": "enterprise"}},
+			value:   true,
+			reason:  ReasonRuleMatch,
+		},
+		{
+			name:    "segment rule by included key",
+			flag:    FeatureFlag{ID: 1, IsActive: true, Rules: []FlagRule{{ID: "beta", Segments: []string{"beta"}}}, Rollout: &PercentageRollout{}},
+			ruleset: Ruleset{Segments: []Segment{beta}},
+			evalCtx: EvaluationContext{Key: "user-9"},
+			value:   true,
+			reason:  ReasonRuleMatch,
+		},
+		{
+			name:    "missing segment never matches",
+			flag:    FeatureFlag{ID: 1, IsActive: true, Rules: []FlagRule{{ID: "beta", Segments: []string{"beta"}}}, Rollout: &PercentageRollout{}},
+			evalCtx: EvaluationContext{Key: "user-9"},
+			value:   false,
+			reason:  ReasonSplit,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ruleset := tt.ruleset
+			ruleset.Flags = []FeatureFlag{tt.flag}
+			d := ruleset.index().evaluate(&ruleset.Flags[0], tt.evalCtx)
+			require.NoError(t, d.Err)
+			assert.Equal(t, tt.value, d.Value)
+			assert.Equal(t, tt.reason, d.Reason)
+			assert.Equal(t, tt.variation, d.Variation)
+			assert.Equal(t, tt.heldOut, d.Holdout != nil && d.Holdout.InHoldout)
+		})
+	}
+}
+
+func TestEvaluatorNotReadyAndMissingFlag(t *testing.T) {
+	evaluator := NewEvaluator(NewClient("http://localhost", "key", nil), EvaluatorOptions{Environment: "production"})
+	d := evaluator.EvaluateBool("checkout", EvaluationContext{Key: "user-1"}, true)
+	assert.ErrorIs(t, d.Err, ErrEvaluatorNotReady)
+	assert.True(t, d.Value, "failures serve the default value")
+
+	evaluator.SetRuleset(&Ruleset{Environment: "production"})
+	d = evaluator.EvaluateBool("checkout", EvaluationContext{Key: "user-1"}, true)
+	assert.ErrorIs(t, d.Err, ErrFlagNotFound)
+	assert.Equal(t, ErrorFlagNotFound, d.ErrorCode)
+}
+
+func TestEvaluatorApplyMatchesFlagsByID(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	evaluator := NewEvaluator(NewClient("http://localhost", "key", nil), EvaluatorOptions{Environment: "production", Store: store})
+	ruleset := &Ruleset{Environment: "production", Flags: []FeatureFlag{
+		{ID: 1, Name: "checkout", Environment: "production", IsActive: true},
+		{ID: 2, Name: "search", Environment: "production", IsActive: true},
+	}}
+	require.NoError(t, store.Init(ctx, ruleset))
+	evaluator.SetRuleset(ruleset)
+
+	renamed := FeatureFlag{ID: 1, Name: "checkout-v2", Environment: "production", IsActive: true}
+	require.NoError(t, evaluator.Apply(ctx, FlagEvent{Type: FlagUpdated, Flag: renamed}))
+
+	user := EvaluationContext{Key: "user-1"}
+	assert.ErrorIs(t, evaluator.Evaluate("checkout", user, false).Err, ErrFlagNotFound, "the old name is gone")
+	assert.Equal(t, true, evaluator.Evaluate("checkout-v2", user, false).Value)
+	assert.Len(t, evaluator.Ruleset().Flags, 2)
+
+	_, err := store.Get(ctx, "checkout")
+	assert.ErrorIs(t, err, ErrFlagNotFound, "the store drops the old name too")
+	_, err = store.Get(ctx, "checkout-v2")
+	assert.NoError(t, err)
+
+	require.NoError(t, evaluator.Apply(ctx, FlagEvent{Type: FlagDeleted, Flag: renamed}))
+	assert.Len(t, evaluator.Ruleset().Flags, 1)
+}
+
+// swapTracer calls swap when an evaluation span ends, between the evaluation
+// and anything the caller does with its result
+type swapTracer struct {
+	NopTracer
+	swap func()
+}
+
+func (s swapTracer) StartEvaluation(ctx context.Context, _ string) (context.Context, func(EvaluationDetail[any])) {
+	return ctx, func(EvaluationDetail[any]) { s.swap() }
+}
+
+func TestGetVariationRulesetChange(t *testing.T) {
+	var evaluator *Evaluator
+	client := NewClient("http://localhost", "key", nil, WithTracer(swapTracer{swap: func() {
+		evaluator.SetRuleset(&Ruleset{Environment: "production"})
+	}}))
+	evaluator = NewEvaluator(client, EvaluatorOptions{Environment: "production"})
+	evaluator.SetRuleset(&Ruleset{Environment: "production", Flags: []FeatureFlag{{ID: 1, Name: "checkout", IsActive: true,
+		Variations: []Variation{{Key: "treatment", Value: "B", Weight: 1}}}}})
+
+	v, err := evaluator.GetVariation("checkout", EvaluationContext{Key: "user-1"})
+	require.NoError(t, err, "the variation comes from the ruleset evaluated, not the one replacing it")
+	assert.Equal(t, "treatment", v.Key)
+	_, err = evaluator.GetVariation("checkout", EvaluationContext{Key: "user-1"})
+	assert.True(t, IsNotFound(err))
+}
@@ -0,0 +1,242 @@
+package matrixflag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Event pipeline defaults
+const (
+	DefaultEventFlushInterval = 10 * time.Second
+	DefaultEventFlushSize     = 500
+	DefaultEventCapacity      = 10000
+)
+
+// EventKind is the kind of an analytics event
+type EventKind string
+
+// Event kinds
+const (
+	// EventEvaluation records a flag evaluation
+	EventEvaluation EventKind = "evaluation"
+	// EventCustom records a metric event sent with Track, such as a conversion
+	EventCustom EventKind = "custom"
+)
+
+// ErrEventsDisabled is returned by Track when the client has no event pipeline, see WithEvents
+var ErrEventsDisabled = errors.New("event pipeline is not enabled")
+
+// Event is an analytics event sent by the event pipeline of a client
+type Event struct {
+	Kind EventKind `json:"kind"`
+	Time time.Time `json:"time"`
+	// ContextKeys holds the key of each individual context the event is about, by kind
+	ContextKeys map[string]string `json:"context_keys"`
+	Environment string            `json:"environment,omitempty"`
+	// FlagKey, Variation and Reason describe the evaluation of EventEvaluation events
+	FlagKey   string           `json:"flag_key,omitempty"`
+	Variation string           `json:"variation,omitempty"`
+	Reason    EvaluationReason `json:"reason,omitempty"`
+	// Name, Value and Properties describe the metric of EventCustom events
+	Name       string         `json:"name,omitempty"`
+	Value      *float64       `json:"value,omitempty"`
+	Properties map[string]any `json:"properties,omitempty"`
+}
+
+// EventOptions configures the event pipeline of a client
+type EventOptions struct {
+	// FlushInterval is the time between flushes of RunEvents, DefaultEventFlushInterval by default
+	FlushInterval time.Duration
+	// FlushSize is the number of buffered events that makes RunEvents flush early, DefaultEventFlushSize by default
+	FlushSize int
+	// Capacity bounds the buffered events, DefaultEventCapacity by default; later events are dropped until the next flush
+	Capacity int
+	// OnError is called when a flush fails; its events are dropped
+	OnError func(error)
+}
+
+// WithEvents records an event for every flag evaluation of the client and of
+// its Evaluators, and enables Track. Call RunEvents to send them. Offline
+// clients record no events.
+func WithEvents(opts EventOptions) ClientOption {
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = DefaultEventFlushInterval
+	}
+	if opts.FlushSize <= 0 {
+		opts.FlushSize = DefaultEventFlushSize
+	}
+	if opts.Capacity <= 0 {
+		opts.Capacity = DefaultEventCapacity
+	}
+	return func(c *Client) {
+		c.events = &eventBuffer{opts: opts, full: make(chan struct{}, 1)}
+	}
+}
+
+// eventBuffer holds the events of a client until they are sent
+type eventBuffer struct {
+	opts EventOptions
+	// full is signaled when the buffer holds FlushSize events
+	full chan struct{}
+
+	mu     sync.Mutex
+	events []Event
+}
+
+// add buffers an event, dropping it when the buffer is at capacity
+func (b *eventBuffer) add(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.events) >= b.opts.Capacity {
+		return
+	}
+	b.events = append(b.events, event)
+	if len(b.events) == b.opts.FlushSize {
+		select {
+		case b.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// take empties the buffer and returns its events
+func (b *eventBuffer) take() []Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	events := b.events
+	b.events = nil
+	return events
+}
+
+// recordEvent buffers an analytics event when the event pipeline is enabled
+func (c *Client) recordEvent(event Event) {
+	if c.events == nil || c.isOffline() {
+		return
+	}
+	event.Time = c.clock.Now()
+	c.events.add(event)
+}
+
+// recordEvaluation buffers the evaluation event of an evaluation
+func (c *Client) recordEvaluation(environment, flagKey string, evalCtx EvaluationContext, d EvaluationDetail[any]) {
+	c.recordEvent(Event{
+		Kind:        EventEvaluation,
+		ContextKeys: contextKeys(evalCtx),
+		Environment: environment,
+		FlagKey:     flagKey,
+		Variation:   d.Variation,
+		Reason:      d.Reason,
+	})
+}
+
+// Track records a metric event, such as a conversion and its value, for the
+// contexts of evalCtx, so the server can tie it to their flag exposures when
+// computing experiment results. The event is sent with the evaluation events.
+func (c *Client) Track(ctx context.Context, eventName string, evalCtx EvaluationContext, value float64, properties map[string]any, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if c.events == nil {
+		return ErrEventsDisabled
+	}
+	if eventName == "" {
+		return errors.New("invalid event: name is required")
+	}
+	if err := evalCtx.Validate(); err != nil {
+		return err
+	}
+	c.recordEvent(Event{
+		Kind:        EventCustom,
+		ContextKeys: contextKeys(evalCtx),
+		Environment: c.config.Environment,
+		Name:        eventName,
+		Value:       &value,
+		Properties:  properties,
+	})
+	return nil
+}
+
+// contextKeys returns the key of each individual context of evalCtx by kind
+func contextKeys(evalCtx EvaluationContext) map[string]string {
+	if !evalCtx.IsMulti() {
+		return map[string]string{evalCtx.kind(): evalCtx.Key}
+	}
+	keys := make(map[string]string, len(evalCtx.Contexts))
+	for _, c := range evalCtx.Contexts {
+		keys[c.kind()] = c.Key
+	}
+	return keys
+}
+
+// SendEvents records analytics events
+func (c *Client) SendEvents(ctx context.Context, events []Event, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if len(events) == 0 {
+		return nil
+	}
+	if c.namespacePrefix() != "" {
+		qualified := make([]Event, len(events))
+		for i, e := range events {
+			if e.FlagKey != "" {
+				e.FlagKey = c.qualifyName(e.FlagKey)
+			}
+			qualified[i] = e
+		}
+		events = qualified
+	}
+	_, err := c.doRequest(ctx, request{
+		method: "POST",
+		path:   "/api/v1/events",
+		body:   map[string][]Event{"events": events},
+		class:  EndpointBulk,
+	})
+	return err
+}
+
+// FlushEvents sends the buffered events of the event pipeline. The events of a failed flush are dropped.
+func (c *Client) FlushEvents(ctx context.Context, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if c.events == nil {
+		return nil
+	}
+	events := c.events.take()
+	if err := c.SendEvents(ctx, events); err != nil {
+		return fmt.Errorf("failed to send %d events: %w", len(events), err)
+	}
+	return nil
+}
+
+// RunEvents flushes the event pipeline every FlushInterval, and as soon as
+// FlushSize events are buffered, until ctx is canceled. The remaining events
+// are flushed before it returns. It returns at once without WithEvents.
+func (c *Client) RunEvents(ctx context.Context, reqOpts ...RequestOption) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if c.events == nil {
+		return
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			c.flushEvents(context.WithoutCancel(ctx))
+			return
+		case <-c.events.full:
+		case <-c.clock.After(c.events.opts.FlushInterval):
+		}
+		c.flushEvents(ctx)
+	}
+}
+
+// flushEvents flushes the event pipeline and reports a failure
+func (c *Client) flushEvents(ctx context.Context) {
+	if err := c.FlushEvents(ctx); err != nil {
+		c.Logger().Warn("flag event flush failed", slog.Any("error", err))
+		if c.events.opts.OnError != nil {
+			c.events.opts.OnError(err)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package matrixflag
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEventEvaluator(client *Client) *Evaluator {
+	evaluator := NewEvaluator(client, EvaluatorOptions{Environment: "production"})
+	evaluator.SetRuleset(&Ruleset{Environment: "production", Flags: []FeatureFlag{
+		{ID: 1, Name: "checkout", Environment: "production", IsActive: true},
+	}})
+	return evaluator
+}
+
+func TestEvaluationEvents(t *testing.T) {
+	srv := newFakeServer(t)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	client := New("key", WithBaseURL(srv.URL), WithNamespace("payments"), WithClock(fixedClock{now}), WithEvents(EventOptions{}))
+	evaluator := newEventEvaluator(client)
+
+	evaluator.EvaluateBool("checkout", EvaluationContext{Key: "user-1"}, false)
+	evaluator.EvaluateBool("search", NewMultiContext(NewContext("user-2"), EvaluationContext{Kind: "organization", Key: "acme"}), false)
+	require.NoError(t, client.FlushEvents(context.Background()))
+
+	assert.Equal(t, []Event{
+		{Kind: EventEvaluation, Time: now, ContextKeys: map[string]string{"user": "user-1"}, Environment: "production", FlagKey: "payments.checkout", Reason: ReasonDefault},
+		{Kind: EventEvaluation, Time: now, ContextKeys: map[string]string{"organization": "acme", "user": "user-2"}, Environment: "production", FlagKey: "payments.search", Reason: ReasonError},
+	}, srv.receivedEvents())
+
+	require.NoError(t, client.FlushEvents(context.Background()))
+	assert.Len(t, srv.receivedEvents(), 2, "flushed events are not sent again")
+}
+
+func TestEventsDisabled(t *testing.T) {
+	srv := newFakeServer(t)
+	client := New("key", WithBaseURL(srv.URL))
+	newEventEvaluator(client).EvaluateBool("checkout", EvaluationContext{Key: "user-1"}, false)
+	require.NoError(t, client.FlushEvents(context.Background()))
+	assert.Empty(t, srv.writes())
+}
+
+func TestEventCapacity(t *testing.T) {
+	srv := newFakeServer(t)
+	client := New("key", WithBaseURL(srv.URL), WithEvents(EventOptions{Capacity: 2}))
+	evaluator := newEventEvaluator(client)
+	for i := 0; i < 5; i++ {
+		evaluator.EvaluateBool("checkout", EvaluationContext{Key: "user-1"}, false)
+	}
+	require.NoError(t, client.FlushEvents(context.Background()))
+	assert.Len(t, srv.receivedEvents(), 2)
+}
+
+func TestRunEventsFlushesWhenFull(t *testing.T) {
+	srv := newFakeServer(t)
+	client := New("key", WithBaseURL(srv.URL), WithEvents(EventOptions{FlushInterval: time.Hour, FlushSize: 3}))
+	evaluator := newEventEvaluator(client)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		client.RunEvents(ctx)
+		close(done)
+	}()
+	for i := 0; i < 3; i++ {
+		evaluator.EvaluateBool("checkout", EvaluationContext{Key: "user-1"}, false)
+	}
+	assert.Eventually(t, func() bool { return len(srv.receivedEvents()) == 3 }, time.Second, 5*time.Millisecond)
+
+	evaluator.EvaluateBool("checkout", EvaluationContext{Key: "user-1"}, false)
+	cancel()
+	<-done
+	assert.Len(t, srv.receivedEvents(), 4, "the remaining events are flushed on shutdown")
+}
+
+func TestTrack(t *testing.T) {
+	srv := newFakeServer(t)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	client := New("key", WithBaseURL(srv.URL), WithEnvironment("production"), WithClock(fixedClock{now}), WithEvents(EventOptions{}))
+	ctx := context.Background()
+
+	newEventEvaluator(client).EvaluateBool("checkout", EvaluationContext{Key: "user-1"}, false)
+	require.NoError(t, client.Track(ctx, "purchase", NewContext("user-1"), 49.5, map[string]any{"currency": "EUR"}))
+	require.NoError(t, client.FlushEvents(ctx))
+
+	events := srv.receivedEvents()
+	require.Len(t, events, 2, "metric events share the pipeline of evaluation events")
+	value := 49.5
+	assert.Equal(t, Event{
+		Kind:        EventCustom,
+		Time:        now,
+		ContextKeys: map[string]string{"user": "user-1"},
+		Environment: "production",
+		Name:        "purchase",
+		Value:       &value,
+		Properties:  map[string]any{"currency": "EUR"},
+	}, events[1])
+}
+
+func TestTrackErrors(t *testing.T) {
+	ctx := context.Background()
+	assert.ErrorIs(t, New("key").Track(ctx, "purchase", NewContext("user-1"), 1, nil), ErrEventsDisabled)
+
+	client := New("key", WithEvents(EventOptions{}))
+	assert.ErrorContains(t, client.Track(ctx, "", NewContext("user-1"), 1, nil), "name is required")
+	assert.ErrorIs(t, client.Track(ctx, "purchase", EvaluationContext{}, 1, nil), ErrInvalidContext)
+	assert.Empty(t, client.events.take())
+}
@@ -0,0 +1,40 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ExperimentResult represents the results of one variant of an experiment.
+// Metrics maps a metric name to its statistics, such as "value" (the mean)
+// and "variance" when the server reports it.
+type ExperimentResult struct {
+	ExperimentID string                        `json:"experiment_id"`
+	VariantName  string                        `json:"variant_name"`
+	TotalUsers   int                           `json:"total_users"`
+	Metrics      map[string]map[string]float64 `json:"metrics"`
+	StartTime    time.Time                     `json:"start_time"`
+	EndTime      time.Time                     `json:"end_time"`
+}
+
+// GetExperimentResults retrieves the per-variant results of an experiment
+func (c *Client) GetExperimentResults(ctx context.Context, name string, reqOpts ...RequestOption) ([]ExperimentResult, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	respBody, err := c.doRequest(ctx, request{
+		method: "GET",
+		path:   fmt.Sprintf("/api/v1/ab-testing/experiments/%s/results", url.PathEscape(name)),
+		class:  EndpointExport,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []ExperimentResult
+	if err := json.Unmarshal(respBody, &results); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,165 @@
+package matrixflag
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultExpiryWarningInterval is the default least time between two warnings
+// about the evaluations of the same expired flag
+const DefaultExpiryWarningInterval = time.Hour
+
+// Expired reports whether the flag is past its expiry at time t
+func (f *FeatureFlag) Expired(t time.Time) bool {
+	return f.ExpiresAt != nil && !t.Before(*f.ExpiresAt)
+}
+
+// ExpiryOptions configures the warnings about evaluations of expired flags
+type ExpiryOptions struct {
+	// Interval is the least time between two warnings about the same flag,
+	// DefaultExpiryWarningInterval by default
+	Interval time.Duration
+}
+
+// WithExpiryWarnings logs a warning when a flag past its ExpiresAt is evaluated
+// locally, by a started or offline client or by an Evaluator, at most once per
+// Interval for each flag. The Metrics hook is told of every such evaluation
+// either way. Evaluations on the server are counted by ExpiredFlags only.
+func WithExpiryWarnings(opts ExpiryOptions) ClientOption {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultExpiryWarningInterval
+	}
+	return func(c *Client) {
+		c.expiry = &opts
+	}
+}
+
+// ExpiredFlag is a flag past its expiry that the client still evaluates, see
+// Client.ExpiredFlags
+type ExpiredFlag struct {
+	Flag FeatureFlag
+	// Evaluations counts the evaluations of the flag by the client since it was created
+	Evaluations int64
+	// LastEvaluated is the time of the latest evaluation of the flag by the client
+	LastEvaluated time.Time
+}
+
+// ExpiredFlags returns the flags of the client's environment that are past
+// their ExpiresAt but were evaluated by the client since it was created,
+// sorted by name: the flags code still references after their planned removal.
+// Started and offline clients check the flags of their local ruleset; other
+// clients list the flags of the environment on the server.
+func (c *Client) ExpiredFlags(ctx context.Context, reqOpts ...RequestOption) ([]ExpiredFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	flags, err := c.expiryFlags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := c.clock.Now()
+	expired := []ExpiredFlag{}
+	for i := range flags {
+		if !flags[i].Expired(now) {
+			continue
+		}
+		if stats, ok := c.usage.get(flags[i].Name); ok {
+			expired = append(expired, ExpiredFlag{Flag: flags[i], Evaluations: stats.evaluations, LastEvaluated: stats.last})
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool { return expired[i].Flag.Name < expired[j].Flag.Name })
+	return expired, nil
+}
+
+// expiryFlags returns the flags checked by ExpiredFlags
+func (c *Client) expiryFlags(ctx context.Context) ([]FeatureFlag, error) {
+	if c.isOffline() {
+		ix, err := c.offlineRuleset()
+		if err != nil {
+			return nil, err
+		}
+		return ix.Flags, nil
+	}
+	if local := c.local.Load(); local != nil {
+		if ruleset := local.evaluator.Ruleset(); ruleset != nil {
+			return ruleset.Flags, nil
+		}
+	}
+	return c.ListAll(ctx, ListOptions{Filter: FlagFilter{Environment: c.config.Environment}})
+}
+
+// observeExpiry reports the local evaluation of a flag at time now to the
+// Metrics hook and, with WithExpiryWarnings, in a warning when it is expired
+func (c *Client) observeExpiry(flag *FeatureFlag, now time.Time) {
+	if !flag.Expired(now) {
+		return
+	}
+	c.Metrics().ExpiredFlagEvaluated(flag.Name)
+	if c.expiry != nil && c.usage.warn(flag.Name, now, c.expiry.Interval) {
+		c.Logger().Warn("expired flag evaluated",
+			slog.String("flag", flag.Name),
+			slog.Time("expires_at", *flag.ExpiresAt),
+		)
+	}
+}
+
+// flagUsage keeps the evaluations of each flag by a client
+type flagUsage struct {
+	mu    sync.Mutex
+	flags map[string]*flagStats
+}
+
+// flagStats are the evaluations of a flag by a client
+type flagStats struct {
+	evaluations int64
+	last        time.Time
+	// warned is the time of the latest warning that the flag is expired
+	warned time.Time
+}
+
+// stats returns the statistics of a flag, adding them if missing; u.mu must be held
+func (u *flagUsage) stats(key string) *flagStats {
+	if u.flags == nil {
+		u.flags = make(map[string]*flagStats)
+	}
+	s, ok := u.flags[key]
+	if !ok {
+		s = &flagStats{}
+		u.flags[key] = s
+	}
+	return s
+}
+
+// record counts an evaluation of a flag at time t
+func (u *flagUsage) record(key string, t time.Time) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	s := u.stats(key)
+	s.evaluations++
+	s.last = t
+}
+
+// get returns the evaluations of a flag, false when it was never evaluated
+func (u *flagUsage) get(key string) (flagStats, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	s, ok := u.flags[key]
+	if !ok || s.evaluations == 0 {
+		return flagStats{}, false
+	}
+	return *s, true
+}
+
+// warn reports whether a warning about a flag is due at time t, interval after
+// the previous one, and records it if so
+func (u *flagUsage) warn(key string, t time.Time, interval time.Duration) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	s := u.stats(key)
+	if !s.warned.IsZero() && t.Sub(s.warned) < interval {
+		return false
+	}
+	s.warned = t
+	return true
+}
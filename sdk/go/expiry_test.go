@@ -0,0 +1,84 @@
+package matrixflag
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// expiredMetrics counts the evaluations of expired flags it receives
+type expiredMetrics struct {
+	NopMetrics
+	expired []string
+}
+
+func (m *expiredMetrics) ExpiredFlagEvaluated(flagKey string) {
+	m.expired = append(m.expired, flagKey)
+}
+
+func TestExpiredFlags(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			writeJSON(w, map[string]any{"value": true, "reason": "DEFAULT"})
+			return
+		}
+		query = r.URL.Query().Get("environment")
+		writeJSON(w, []FeatureFlag{
+			{ID: 1, Name: "checkout", ExpiresAt: &past},
+			{ID: 2, Name: "search", ExpiresAt: &future},
+			{ID: 3, Name: "legacy", ExpiresAt: &past},
+			{ID: 4, Name: "banner"},
+		})
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil, WithEnvironment("production"), WithClock(fixedClock{now}))
+	ctx := context.Background()
+	user := EvaluationContext{Key: "user-1"}
+
+	for _, key := range []string{"checkout", "checkout", "search", "banner"} {
+		require.NoError(t, client.EvaluateBool(ctx, key, user, false).Err)
+	}
+	expired, err := client.ExpiredFlags(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "production", query)
+	require.Len(t, expired, 1, "flags not evaluated by the client are left out")
+	assert.Equal(t, "checkout", expired[0].Flag.Name)
+	assert.Equal(t, int64(2), expired[0].Evaluations)
+	assert.Equal(t, now, expired[0].LastEvaluated)
+}
+
+func TestExpiryWarnings(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(-24 * time.Hour)
+	handler := &recordingHandler{}
+	metrics := &expiredMetrics{}
+	clock := &fixedClock{now}
+	client := NewClient("http://localhost", "key", nil, WithClock(clock), WithMetrics(metrics),
+		WithLogger(slog.New(handler)), WithExpiryWarnings(ExpiryOptions{Interval: time.Minute}))
+	evaluator := NewEvaluator(client, EvaluatorOptions{Environment: "production"})
+	evaluator.SetRuleset(&Ruleset{Environment: "production", Flags: []FeatureFlag{
+		{ID: 1, Name: "checkout", IsActive: true, ExpiresAt: &expiresAt},
+		{ID: 2, Name: "search", IsActive: true},
+	}})
+	user := EvaluationContext{Key: "user-1"}
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, true, evaluator.EvaluateBool("checkout", user, false).Value, "expired flags evaluate normally")
+		evaluator.EvaluateBool("search", user, false)
+	}
+	clock.now = now.Add(time.Minute)
+	evaluator.EvaluateBool("checkout", user, false)
+
+	assert.Equal(t, []string{"checkout", "checkout", "checkout", "checkout"}, metrics.expired)
+	assert.Equal(t, []string{"WARN expired flag evaluated", "WARN expired flag evaluated"}, handler.messages(),
+		"warnings about a flag are at least an interval apart")
+}
@@ -0,0 +1,187 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ExportKind is the kind of flag export documents
+const ExportKind = "FlagExport"
+
+// FlagExport is a portable document of the flags of one environment, written by
+// ExportFlags and read by ImportFlags to promote flags, such as from staging to
+// production. Its flags have no IDs, timestamps or layer membership, which
+// belong to the server they were exported from:
+//
+//	{
+//	  "apiVersion": "matrixflag.io/v1",
+//	  "kind": "FlagExport",
+//	  "environment": "staging",
+//	  "exported_at": "2024-05-01T12:00:00Z",
+//	  "flags": [{"name": "new-checkout", "is_active": true, "project_id": 3}]
+//	}
+type FlagExport struct {
+	APIVersion  string        `json:"apiVersion"`
+	Kind        string        `json:"kind"`
+	Environment string        `json:"environment"`
+	ExportedAt  time.Time     `json:"exported_at"`
+	Flags       []FeatureFlag `json:"flags"`
+}
+
+// ImportOptions configures ImportFlags
+type ImportOptions struct {
+	// Overwrite updates flags that exist in the target environment to match the
+	// export; by default they are skipped
+	Overwrite bool
+	// Env is the environment the flags are imported into, the environment of the
+	// export when empty
+	Env string
+	// Projects maps the project IDs of the export to those of the target; flags
+	// of projects missing from it keep their project ID
+	Projects map[int]int
+}
+
+// ImportResult reports the flags changed by ImportFlags
+type ImportResult struct {
+	Created []FeatureFlag `json:"created"`
+	Updated []FeatureFlag `json:"updated"`
+	// Skipped are the existing flags left unchanged without Overwrite
+	Skipped []FeatureFlag `json:"skipped"`
+}
+
+// ExportFlags exports the flags of an environment as a portable document
+func (c *Client) ExportFlags(ctx context.Context, environment string, reqOpts ...RequestOption) (*FlagExport, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	flags, err := c.listFeatureFlags(ctx, FlagFilter{Environment: environment}, EndpointExport)
+	if err != nil {
+		return nil, err
+	}
+	return NewFlagExport(environment, flags), nil
+}
+
+// NewFlagExport creates an export of flags, dropping the fields that do not
+// carry over to another environment
+func NewFlagExport(environment string, flags []FeatureFlag) *FlagExport {
+	exported := make([]FeatureFlag, len(flags))
+	for i, flag := range flags {
+		flag.ID = 0
+		flag.LayerID = 0
+		flag.TrafficAllocation = nil
+		flag.DebugUntil = nil
+		flag.CreatedAt = time.Time{}
+		flag.UpdatedAt = time.Time{}
+		exported[i] = flag
+	}
+	return &FlagExport{
+		APIVersion:  ManifestAPIVersionV1,
+		Kind:        ExportKind,
+		Environment: environment,
+		ExportedAt:  time.Now().UTC(),
+		Flags:       exported,
+	}
+}
+
+// LoadFlagExport decodes and checks an export written by FlagExport.Write
+func LoadFlagExport(r io.Reader) (*FlagExport, error) {
+	var doc FlagExport
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode flag export: %w", err)
+	}
+	if doc.APIVersion != ManifestAPIVersionV1 {
+		return nil, fmt.Errorf("unsupported flag export apiVersion %q", doc.APIVersion)
+	}
+	if doc.Kind != ExportKind {
+		return nil, fmt.Errorf("unexpected flag export kind %q", doc.Kind)
+	}
+	return &doc, nil
+}
+
+// Write writes the export to w as indented JSON
+func (e *FlagExport) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(e); err != nil {
+		return fmt.Errorf("failed to encode flag export: %w", err)
+	}
+	return nil
+}
+
+// ImportFlags creates the flags of an export in the environment of opts, with
+// their project IDs remapped. Flags are matched to existing ones by name, and
+// an error stops the import with the flags changed so far in the result.
+func (c *Client) ImportFlags(ctx context.Context, doc *FlagExport, opts ImportOptions, reqOpts ...RequestOption) (*ImportResult, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	environment := opts.Env
+	if environment == "" {
+		environment = doc.Environment
+	}
+	if environment == "" {
+		return nil, fmt.Errorf("import requires an environment")
+	}
+	current, err := c.listFeatureFlags(ctx, FlagFilter{Environment: environment}, EndpointExport)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]FeatureFlag, len(current))
+	for _, flag := range current {
+		existing[flag.Name] = flag
+	}
+
+	result := &ImportResult{}
+	for _, flag := range doc.Flags {
+		flag.Environment = environment
+		if projectID, ok := opts.Projects[flag.ProjectID]; ok {
+			flag.ProjectID = projectID
+		}
+		target, exists := existing[flag.Name]
+		switch {
+		case !exists:
+			created, err := c.CreateFeatureFlag(ctx, FeatureFlagCreate{
+				Name:          flag.Name,
+				Description:   flag.Description,
+				IsActive:      flag.IsActive,
+				Environment:   flag.Environment,
+				ProjectID:     flag.ProjectID,
+				Metadata:      flag.Metadata,
+				Tags:          flag.Tags,
+				Rollout:       flag.Rollout,
+				Variations:    flag.Variations,
+				OffVariation:  flag.OffVariation,
+				Rules:         flag.Rules,
+				Prerequisites: flag.Prerequisites,
+				ExpiresAt:     flag.ExpiresAt,
+			})
+			if err != nil {
+				return result, fmt.Errorf("failed to import flag %s: %w", flag.Name, err)
+			}
+			result.Created = append(result.Created, *created)
+		case opts.Overwrite:
+			// Every imported field is sent, so fields cleared in the export are cleared on the target
+			updated, err := c.UpdateFeatureFlag(ctx, target.ID, FeatureFlagUpdate{
+				Description:   flag.Description,
+				IsActive:      flag.IsActive,
+				ProjectID:     flag.ProjectID,
+				Metadata:      flag.Metadata,
+				Tags:          flag.Tags,
+				Rollout:       flag.Rollout,
+				Variations:    flag.Variations,
+				OffVariation:  flag.OffVariation,
+				Rules:         flag.Rules,
+				Prerequisites: flag.Prerequisites,
+				ExpiresAt:     flag.ExpiresAt,
+				Fields: FieldMask{FieldDescription, FieldIsActive, FieldProjectID, FieldMetadata, FieldTags,
+					FieldRollout, FieldVariations, FieldOffVariation, FieldRules, FieldPrerequisites, FieldExpiresAt},
+			})
+			if err != nil {
+				return result, fmt.Errorf("failed to import flag %s: %w", flag.Name, err)
+			}
+			result.Updated = append(result.Updated, *updated)
+		default:
+			result.Skipped = append(result.Skipped, target)
+		}
+	}
+	return result, nil
+}
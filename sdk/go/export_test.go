@@ -0,0 +1,58 @@
+package matrixflag
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImportPromotesFlags(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.addFlag(FeatureFlag{Name: "checkout", Environment: "staging", IsActive: true, ProjectID: 3, LayerID: 7,
+		Rollout: &PercentageRollout{Percentage: 50}})
+	srv.addFlag(FeatureFlag{Name: "search", Environment: "staging", Description: "new search", ProjectID: 5})
+	srv.addFlag(FeatureFlag{Name: "search", Environment: "production", Description: "old search", IsActive: true})
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+
+	doc, err := client.ExportFlags(ctx, "staging")
+	require.NoError(t, err)
+	require.Len(t, doc.Flags, 2)
+	assert.Zero(t, doc.Flags[0].ID)
+	assert.Zero(t, doc.Flags[0].LayerID, "layers do not carry over")
+
+	var buf bytes.Buffer
+	require.NoError(t, doc.Write(&buf))
+	doc, err = LoadFlagExport(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, "staging", doc.Environment)
+
+	result, err := client.ImportFlags(ctx, doc, ImportOptions{Env: "production", Projects: map[int]int{3: 30}})
+	require.NoError(t, err)
+	require.Len(t, result.Created, 1)
+	assert.Equal(t, "production", result.Created[0].Environment)
+	assert.Equal(t, 30, result.Created[0].ProjectID)
+	assert.Equal(t, 50.0, result.Created[0].Rollout.Percentage)
+	require.Len(t, result.Skipped, 1)
+	assert.Equal(t, "old search", result.Skipped[0].Description)
+
+	result, err = client.ImportFlags(ctx, doc, ImportOptions{Env: "production", Overwrite: true})
+	require.NoError(t, err)
+	assert.Len(t, result.Updated, 2)
+	search, err := client.GetFeatureFlag(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "new search", search.Description)
+	assert.False(t, search.IsActive, "overwriting switches the flag off as in the export")
+	assert.Equal(t, 5, search.ProjectID, "unmapped projects are kept")
+}
+
+func TestLoadFlagExportChecksKind(t *testing.T) {
+	_, err := LoadFlagExport(strings.NewReader(`{"apiVersion":"matrixflag.io/v1","kind":"FlagManifest"}`))
+	assert.ErrorContains(t, err, "kind")
+	_, err = LoadFlagExport(strings.NewReader(`[]`))
+	assert.Error(t, err)
+}
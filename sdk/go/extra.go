@@ -0,0 +1,90 @@
+package matrixflag
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// featureFlagFields returns the JSON names of the fields known to FeatureFlag
+var featureFlagFields = sync.OnceValue(func() map[string]bool {
+	fields := make(map[string]bool)
+	t := reflect.TypeOf(FeatureFlag{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+})
+
+// UnmarshalJSON decodes a flag, keeping fields unknown to this SDK version in Extra
+func (f *FeatureFlag) UnmarshalJSON(data []byte) error {
+	type plain FeatureFlag
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	known := featureFlagFields()
+	for name := range fields {
+		if known[name] {
+			delete(fields, name)
+		}
+	}
+	if len(fields) == 0 {
+		fields = nil
+	}
+	p.Extra = fields
+
+	*f = FeatureFlag(p)
+	return nil
+}
+
+// MarshalJSON encodes a flag together with the unknown fields kept in Extra,
+// so that flags round-trip without losing fields added by newer servers
+func (f FeatureFlag) MarshalJSON() ([]byte, error) {
+	type plain FeatureFlag
+	data, err := json.Marshal(plain(f))
+	if err != nil || len(f.Extra) == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range f.Extra {
+		if _, ok := fields[name]; !ok {
+			fields[name] = value
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// checkUnknownFields fails in strict decoding mode when a flag has fields unknown to this SDK version
+func (c *Client) checkUnknownFields(flags ...FeatureFlag) error {
+	if !c.config.StrictDecoding {
+		return nil
+	}
+	for _, flag := range flags {
+		if len(flag.Extra) == 0 {
+			continue
+		}
+		names := make([]string, 0, len(flag.Extra))
+		for name := range flag.Extra {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("failed to unmarshal response: flag %d has unknown fields %s", flag.ID, strings.Join(names, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newerServerFlag is a flag as sent by a server newer than this SDK version
+const newerServerFlag = `{"id":1,"name":"checkout","is_active":true,"environment":"production",` +
+	`"sticky_assignment":true,"schedule":{"starts_at":"2024-05-01T00:00:00Z"}}`
+
+func newExtraServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/feature-flags/" {
+			w.Write([]byte("[" + newerServerFlag + `,{"id":2,"name":"search"}]`))
+			return
+		}
+		w.Write([]byte(newerServerFlag))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestExtraRoundTrip(t *testing.T) {
+	client := NewClient(newExtraServer(t).URL, "key", nil)
+
+	flag, err := client.GetFeatureFlag(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "checkout", flag.Name)
+	assert.Equal(t, map[string]json.RawMessage{
+		"sticky_assignment": json.RawMessage(`true`),
+		"schedule":          json.RawMessage(`{"starts_at":"2024-05-01T00:00:00Z"}`),
+	}, flag.Extra, "unknown fields are kept")
+
+	data, err := json.Marshal(flag)
+	require.NoError(t, err)
+	var encoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &encoded))
+	assert.Equal(t, true, encoded["sticky_assignment"])
+	assert.Equal(t, map[string]any{"starts_at": "2024-05-01T00:00:00Z"}, encoded["schedule"])
+	assert.NotContains(t, encoded, "Extra")
+
+	var decoded FeatureFlag
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, *flag, decoded, "flags round-trip without losing fields")
+}
+
+func TestExtraKnownFieldsWin(t *testing.T) {
+	flag := FeatureFlag{ID: 1, Name: "checkout", Extra: map[string]json.RawMessage{
+		"name":    json.RawMessage(`"stale"`),
+		"cohorts": json.RawMessage(`["beta"]`),
+	}}
+	data, err := json.Marshal(flag)
+	require.NoError(t, err)
+	var encoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &encoded))
+	assert.Equal(t, "checkout", encoded["name"], "extra fields never override known ones")
+	assert.Equal(t, []any{"beta"}, encoded["cohorts"])
+
+	var decoded FeatureFlag
+	require.NoError(t, json.Unmarshal([]byte(`{"id":1,"name":"checkout"}`), &decoded))
+	assert.Nil(t, decoded.Extra, "flags without unknown fields have no extra fields")
+}
+
+func TestStrictDecoding(t *testing.T) {
+	srv := newExtraServer(t)
+	ctx := context.Background()
+
+	client := NewClient(srv.URL, "key", &Config{StrictDecoding: true})
+	_, err := client.GetFeatureFlag(ctx, 1)
+	assert.EqualError(t, err, "failed to unmarshal response: flag 1 has unknown fields schedule, sticky_assignment")
+	_, err = client.ListFeatureFlags(ctx, FlagFilter{})
+	assert.ErrorContains(t, err, "flag 1 has unknown fields")
+
+	flags, err := NewClient(srv.URL, "key", nil).ListFeatureFlags(ctx, FlagFilter{})
+	require.NoError(t, err)
+	assert.Len(t, flags, 2)
+}
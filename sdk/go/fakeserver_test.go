@@ -0,0 +1,328 @@
+package matrixflag
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeServer is an in-memory API serving flags and segments. Updates only
+// change the fields present in the request body, as the real server does.
+type fakeServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	nextID   int
+	flags    map[int]FeatureFlag
+	segments map[string]Segment
+	webhooks map[int]Webhook
+	requests []string
+	// streams receive the events published while they are connected
+	streams  []chan string
+	noStream bool
+	// events holds the analytics events received
+	events []Event
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	s := &fakeServer{flags: make(map[int]FeatureFlag), segments: make(map[string]Segment), webhooks: make(map[int]Webhook)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/feature-flags/", s.handleFlags)
+	mux.HandleFunc("/api/v1/feature-flags/stream", s.handleStream)
+	mux.HandleFunc("/api/v1/targeting/segments", s.handleSegments)
+	mux.HandleFunc("/api/v1/events", s.handleEvents)
+	mux.HandleFunc("/api/v1/targeting/segments/", s.handleSegments)
+	mux.HandleFunc("/api/v1/webhooks/", s.handleWebhooks)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		s.mu.Unlock()
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// addFlag stores a flag, assigning it an ID
+func (s *fakeServer) addFlag(flag FeatureFlag) FeatureFlag {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	flag.ID = s.nextID
+	s.flags[flag.ID] = flag
+	return flag
+}
+
+// publish stores a change to a flag and sends it to the connected streams
+func (s *fakeServer) publish(eventType FlagChangeType, flag FeatureFlag) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if eventType == FlagDeleted {
+		delete(s.flags, flag.ID)
+	} else {
+		s.flags[flag.ID] = flag
+	}
+	data, _ := json.Marshal(flag)
+	for _, stream := range s.streams {
+		stream <- fmt.Sprintf("event: %s\ndata: %s\n\n", eventType, data)
+	}
+}
+
+// writes returns the requests that changed state
+func (s *fakeServer) writes() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var writes []string
+	for _, r := range s.requests {
+		if !strings.HasPrefix(r, "GET ") {
+			writes = append(writes, r)
+		}
+	}
+	return writes
+}
+
+func (s *fakeServer) handleFlags(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rest := strings.TrimPrefix(r.URL.Path, "/api/v1/feature-flags/")
+	if rest == "" {
+		switch r.Method {
+		case http.MethodGet:
+			ids := make([]int, 0, len(s.flags))
+			for id, flag := range s.flags {
+				if env := r.URL.Query().Get("environment"); env == "" || env == flag.Environment {
+					ids = append(ids, id)
+				}
+			}
+			sort.Ints(ids)
+			flags := make([]FeatureFlag, 0, len(ids))
+			for _, id := range ids {
+				flags = append(flags, s.flags[id])
+			}
+			writeJSON(w, flags)
+		case http.MethodPost:
+			var flag FeatureFlag
+			if !decodeBody(w, r, &flag) {
+				return
+			}
+			s.nextID++
+			flag.ID = s.nextID
+			s.flags[flag.ID] = flag
+			writeJSON(w, flag)
+		}
+		return
+	}
+
+	idPart, action, _ := strings.Cut(rest, "/")
+	id, err := strconv.Atoi(idPart)
+	flag, ok := s.flags[id]
+	if err != nil || !ok {
+		http.Error(w, `{"message":"not found","code":"NOT_FOUND"}`, http.StatusNotFound)
+		return
+	}
+	switch {
+	case action == "toggle":
+		flag.IsActive = !flag.IsActive
+	case r.Method == http.MethodPut:
+		if !patchJSON(w, r, &flag) {
+			return
+		}
+	case r.Method == http.MethodDelete:
+		delete(s.flags, id)
+		writeJSON(w, map[string]any{"message": "deleted"})
+		return
+	}
+	s.flags[id] = flag
+	writeJSON(w, flag)
+}
+
+func (s *fakeServer) handleStream(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	if s.noStream {
+		s.mu.Unlock()
+		http.Error(w, `{"message":"not found","code":"NOT_FOUND"}`, http.StatusNotFound)
+		return
+	}
+	stream := make(chan string, 16)
+	s.streams = append(s.streams, stream)
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-stream:
+			w.Write([]byte(event))
+			w.(http.Flusher).Flush()
+		}
+	}
+}
+
+func (s *fakeServer) handleEvents(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var body struct {
+		Events []Event `json:"events"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	s.events = append(s.events, body.Events...)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// receivedEvents returns the analytics events received
+func (s *fakeServer) receivedEvents() []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Event(nil), s.events...)
+}
+
+func (s *fakeServer) handleSegments(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/v1/targeting/segments"), "/")
+	if name == "" {
+		switch r.Method {
+		case http.MethodGet:
+			names := make([]string, 0, len(s.segments))
+			for name := range s.segments {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			segments := make([]Segment, 0, len(names))
+			for _, name := range names {
+				segments = append(segments, s.segments[name])
+			}
+			writeJSON(w, segments)
+		case http.MethodPost:
+			var segment Segment
+			if !decodeBody(w, r, &segment) {
+				return
+			}
+			s.segments[segment.Name] = segment
+			writeJSON(w, segment)
+		}
+		return
+	}
+
+	segment, ok := s.segments[name]
+	if !ok {
+		http.Error(w, `{"message":"not found","code":"NOT_FOUND"}`, http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		if !patchJSON(w, r, &segment) {
+			return
+		}
+		s.segments[name] = segment
+	case http.MethodDelete:
+		delete(s.segments, name)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, segment)
+}
+
+// handleWebhooks serves webhooks, which are signed while they have a secret
+func (s *fakeServer) handleWebhooks(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rest := strings.TrimPrefix(r.URL.Path, "/api/v1/webhooks/")
+	if rest == "" {
+		switch r.Method {
+		case http.MethodGet:
+			ids := make([]int, 0, len(s.webhooks))
+			for id := range s.webhooks {
+				ids = append(ids, id)
+			}
+			sort.Ints(ids)
+			webhooks := make([]Webhook, 0, len(ids))
+			for _, id := range ids {
+				webhooks = append(webhooks, s.webhooks[id])
+			}
+			writeJSON(w, webhooks)
+		case http.MethodPost:
+			var create WebhookCreate
+			if !decodeBody(w, r, &create) {
+				return
+			}
+			s.nextID++
+			webhook := Webhook{ID: s.nextID, URL: create.URL, Events: create.Events, IsActive: true, Signed: create.Secret != ""}
+			s.webhooks[webhook.ID] = webhook
+			writeJSON(w, webhook)
+		}
+		return
+	}
+
+	id, err := strconv.Atoi(rest)
+	webhook, ok := s.webhooks[id]
+	if err != nil || !ok {
+		http.Error(w, `{"message":"not found","code":"NOT_FOUND"}`, http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		var fields map[string]json.RawMessage
+		if !decodeBody(w, r, &fields) {
+			return
+		}
+		if secret, ok := fields[FieldSecret]; ok {
+			webhook.Signed = string(secret) != `""`
+			delete(fields, FieldSecret)
+		}
+		data, _ := json.Marshal(fields)
+		json.Unmarshal(data, &webhook)
+		s.webhooks[id] = webhook
+	case http.MethodDelete:
+		delete(s.webhooks, id)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, webhook)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		http.Error(w, `{"message":"bad request","code":"BAD_REQUEST"}`, http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// patchJSON applies the fields present in the request body to v
+func patchJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	var fields map[string]json.RawMessage
+	if !decodeBody(w, r, &fields) {
+		return false
+	}
+	current, _ := json.Marshal(v)
+	var merged map[string]json.RawMessage
+	json.Unmarshal(current, &merged)
+	for name, value := range fields {
+		merged[name] = value
+	}
+	data, _ := json.Marshal(merged)
+	// Decode into a zero value, so fields cleared to null or [] are not merged with the old ones
+	switch p := v.(type) {
+	case *FeatureFlag:
+		*p = FeatureFlag{}
+	case *Segment:
+		*p = Segment{}
+	}
+	return json.Unmarshal(data, v) == nil
+}
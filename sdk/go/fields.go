@@ -0,0 +1,139 @@
+package matrixflag
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Update fields that can be sent explicitly, see the Fields of FeatureFlagUpdate, SegmentUpdate, ProjectUpdate, EnvironmentUpdate and WebhookUpdate
+const (
+	FieldName          = "name"
+	FieldDescription   = "description"
+	FieldIsActive      = "is_active"
+	FieldEnvironment   = "environment"
+	FieldProjectID     = "project_id"
+	FieldMetadata      = "metadata"
+	FieldTags          = "tags"
+	FieldRollout       = "rollout"
+	FieldVariations    = "variations"
+	FieldOffVariation  = "off_variation"
+	FieldRules         = "rules"
+	FieldPrerequisites = "prerequisites"
+	FieldExpiresAt     = "expires_at"
+	FieldIncluded      = "included"
+	FieldExcluded      = "excluded"
+	FieldEvents        = "events"
+	FieldSecret        = "secret"
+)
+
+// FieldMask names fields of an update by their JSON names, the Field constants
+type FieldMask []string
+
+// Has reports whether the mask names a field
+func (m FieldMask) Has(field string) bool {
+	for _, name := range m {
+		if name == field {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON encodes the update, including the fields named in Fields even when they are empty
+func (u FeatureFlagUpdate) MarshalJSON() ([]byte, error) {
+	type plain FeatureFlagUpdate
+	return marshalFields(plain(u), u.Fields)
+}
+
+// MarshalJSON encodes the update, including the fields named in Fields even when they are empty
+func (u SegmentUpdate) MarshalJSON() ([]byte, error) {
+	type plain SegmentUpdate
+	return marshalFields(plain(u), u.Fields)
+}
+
+// MarshalJSON encodes the update, including the fields named in Fields even when they are empty
+func (u ProjectUpdate) MarshalJSON() ([]byte, error) {
+	type plain ProjectUpdate
+	return marshalFields(plain(u), u.Fields)
+}
+
+// MarshalJSON encodes the update, including the fields named in Fields even when they are empty
+func (u EnvironmentUpdate) MarshalJSON() ([]byte, error) {
+	type plain EnvironmentUpdate
+	return marshalFields(plain(u), u.Fields)
+}
+
+// MarshalJSON encodes the update, including the fields named in Fields even when they are empty
+func (u WebhookUpdate) MarshalJSON() ([]byte, error) {
+	type plain WebhookUpdate
+	return marshalFields(plain(u), u.Fields)
+}
+
+// marshalFields encodes the struct v, adding the named fields that omitempty
+// leaves out. Empty lists and maps are sent as [] and {} rather than null, so
+// the server clears them.
+func marshalFields(v any, names []string) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(names) == 0 {
+		return data, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	rv := reflect.ValueOf(v)
+	for _, name := range names {
+		if _, ok := fields[name]; ok {
+			continue
+		}
+		value, ok := fieldByJSONName(rv, name)
+		if !ok {
+			return nil, fmt.Errorf("unknown update field %q", name)
+		}
+		switch {
+		case value.Kind() == reflect.Slice && value.IsNil():
+			fields[name] = json.RawMessage("[]")
+		case value.Kind() == reflect.Map && value.IsNil():
+			fields[name] = json.RawMessage("{}")
+		default:
+			if fields[name], err = json.Marshal(value.Interface()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// onlyFields returns a copy of the update struct with the fields missing from
+// mask zeroed, failing when mask names a field the update does not have
+func onlyFields[T any](update T, mask FieldMask) (T, error) {
+	v := reflect.ValueOf(&update).Elem()
+	for _, name := range mask {
+		if _, ok := fieldByJSONName(v, name); !ok {
+			return update, fmt.Errorf("unknown update field %q", name)
+		}
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if tag != "-" && !mask.Has(tag) {
+			v.Field(i).SetZero()
+		}
+	}
+	return update, nil
+}
+
+// fieldByJSONName returns the field of struct v encoded under name
+func fieldByJSONName(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if tag == name && tag != "-" {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,29 @@
+package matrixflag
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchFeatureFlagSendsExactlyTheMask(t *testing.T) {
+	srv := newFakeServer(t)
+	flag := srv.addFlag(FeatureFlag{Name: "checkout", Environment: "production", IsActive: true, Description: "new checkout", Tags: []string{"payments"}})
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+
+	patched, err := client.PatchFeatureFlag(ctx, flag.ID, FeatureFlagUpdate{Description: "ignored"},
+		FieldMask{FieldIsActive, FieldTags})
+	require.NoError(t, err)
+	assert.False(t, patched.IsActive, "false is sent for a masked field")
+	assert.Empty(t, patched.Tags, "a masked nil slice clears the tags")
+	assert.Equal(t, "new checkout", patched.Description, "fields missing from the mask are not sent")
+
+	_, err = client.PatchFeatureFlag(ctx, flag.ID, FeatureFlagUpdate{}, FieldMask{"is_enabled"})
+	assert.ErrorContains(t, err, `unknown update field "is_enabled"`)
+	_, err = client.PatchFeatureFlag(ctx, flag.ID, FeatureFlagUpdate{IsActive: true}, nil)
+	assert.ErrorContains(t, err, "at least one field")
+	assert.Len(t, srv.writes(), 1, "invalid patches are not sent")
+}
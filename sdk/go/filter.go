@@ -0,0 +1,74 @@
+package matrixflag
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FlagFilter selects the flags listed by ListFeatureFlags; zero fields do not
+// filter, so the zero FlagFilter lists every flag
+type FlagFilter struct {
+	Environment string
+	ProjectID   int
+	// Active only lists the flags that are on, or off, when set
+	Active *bool
+	// NamePrefix only lists the flags whose name starts with it
+	NamePrefix string
+	// Tags only lists the flags having every one of these tags
+	Tags []string
+	// UpdatedSince only lists the flags updated at or after it
+	UpdatedSince time.Time
+	// Lifecycle only lists the flags in this lifecycle state, such as
+	// LifecycleDeprecated to find the flags due for archiving
+	Lifecycle FlagLifecycle
+}
+
+// Validate checks the filter before it is sent
+func (f FlagFilter) Validate() error {
+	if f.Environment != "" {
+		if err := ValidateEnvironmentKey(f.Environment); err != nil {
+			return fmt.Errorf("invalid flag filter: %w", err)
+		}
+	}
+	if f.ProjectID < 0 {
+		return fmt.Errorf("invalid flag filter: project ID %d is negative", f.ProjectID)
+	}
+	if err := validateTags(f.Tags); err != nil {
+		return fmt.Errorf("invalid flag filter: %w", err)
+	}
+	if f.Lifecycle != "" {
+		if err := f.Lifecycle.Validate(); err != nil {
+			return fmt.Errorf("invalid flag filter: %w", err)
+		}
+	}
+	return nil
+}
+
+// query returns the query parameters of the filter
+func (f FlagFilter) query() map[string]string {
+	params := map[string]string{}
+	if f.Environment != "" {
+		params["environment"] = f.Environment
+	}
+	if f.ProjectID != 0 {
+		params["project_id"] = strconv.Itoa(f.ProjectID)
+	}
+	if f.Active != nil {
+		params["is_active"] = strconv.FormatBool(*f.Active)
+	}
+	if f.NamePrefix != "" {
+		params["name_prefix"] = f.NamePrefix
+	}
+	if len(f.Tags) > 0 {
+		params["tags"] = strings.Join(f.Tags, ",")
+	}
+	if !f.UpdatedSince.IsZero() {
+		params["updated_since"] = f.UpdatedSince.UTC().Format(time.RFC3339)
+	}
+	if f.Lifecycle != "" {
+		params["lifecycle"] = string(f.Lifecycle)
+	}
+	return params
+}
@@ -0,0 +1,70 @@
+package matrixflag
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListFeatureFlagsFilter(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		writeJSON(w, []FeatureFlag{{ID: 1, Name: "team.checkout"}})
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil, WithNamespace("team"))
+	ctx := context.Background()
+
+	active := false
+	flags, err := client.ListFeatureFlags(ctx, FlagFilter{
+		Environment:  "production",
+		ProjectID:    3,
+		Active:       &active,
+		NamePrefix:   "check",
+		Tags:         []string{"payments", "q3"},
+		UpdatedSince: time.Date(2024, 5, 1, 14, 0, 0, 0, time.FixedZone("CEST", 2*60*60)),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "checkout", flags[0].Name)
+	assert.Equal(t, "environment=production&is_active=false&name_prefix=team.check&project_id=3&tags=payments%2Cq3&updated_since=2024-05-01T12%3A00%3A00Z", query)
+
+	_, err = client.ListFeatureFlags(ctx, FlagFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, query, "the zero filter lists every flag")
+
+	query = "unchanged"
+	_, err = client.ListFeatureFlags(ctx, FlagFilter{Environment: "Production"})
+	assert.ErrorContains(t, err, "invalid flag filter")
+	_, err = client.ListFeatureFlags(ctx, FlagFilter{Tags: []string{"a,b"}})
+	assert.ErrorContains(t, err, `invalid tag "a,b"`)
+	_, err = client.ListAll(ctx, ListOptions{Filter: FlagFilter{ProjectID: -1}})
+	assert.ErrorContains(t, err, "negative")
+	assert.Equal(t, "unchanged", query, "invalid filters are not sent")
+}
+
+func TestGetFeatureFlagByName(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.addFlag(FeatureFlag{Name: "checkout", Environment: "staging"})
+	srv.addFlag(FeatureFlag{Name: "checkout-v2", Environment: "production"})
+	want := srv.addFlag(FeatureFlag{Name: "checkout", Environment: "production", IsActive: true})
+	client := New("key", WithBaseURL(srv.URL), WithEnvironment("production"))
+	ctx := context.Background()
+
+	flag, err := client.GetFeatureFlagByName(ctx, "checkout", "")
+	require.NoError(t, err)
+	assert.Equal(t, want.ID, flag.ID, "the configured environment is used by default")
+	flag, err = client.GetFeatureFlagByName(ctx, "checkout", "staging")
+	require.NoError(t, err)
+	assert.Equal(t, "staging", flag.Environment)
+
+	_, err = client.GetFeatureFlagByName(ctx, "search", "production")
+	assert.ErrorIs(t, err, ErrFlagNotFound)
+	_, err = New("key", WithBaseURL(srv.URL)).GetFeatureFlagByName(ctx, "checkout", "")
+	assert.ErrorContains(t, err, "requires a name and an environment")
+}
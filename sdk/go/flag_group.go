@@ -0,0 +1,228 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// FlagAction is the change applied to a flag by an atomic operation
+type FlagAction string
+
+// Flag actions
+const (
+	FlagEnable  FlagAction = "enable"
+	FlagDisable FlagAction = "disable"
+	FlagToggle  FlagAction = "toggle"
+	FlagUpdate  FlagAction = "update"
+)
+
+// FlagOperation represents a change to a single flag within an atomic request
+type FlagOperation struct {
+	FlagID int        `json:"flag_id"`
+	Action FlagAction `json:"action"`
+	// Update holds the changes of a FlagUpdate action
+	Update *FeatureFlagUpdate `json:"update,omitempty"`
+}
+
+// FlagGroup represents a named group of flags that belong to one feature and must change together
+type FlagGroup struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	FlagIDs     []int     `json:"flag_ids"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// FlagGroupCreate represents the data needed to create a flag group
+type FlagGroupCreate struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	FlagIDs     []int  `json:"flag_ids"`
+}
+
+// FlagGroupUpdate represents the data needed to update a flag group
+type FlagGroupUpdate struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	FlagIDs     []int  `json:"flag_ids,omitempty"`
+}
+
+// validateFlagOperations checks a batch of atomic operations before it is sent
+func validateFlagOperations(ops []FlagOperation) error {
+	if len(ops) == 0 {
+		return fmt.Errorf("invalid atomic operation: no flag operations")
+	}
+	seen := make(map[int]bool, len(ops))
+	for i, op := range ops {
+		switch op.Action {
+		case FlagEnable, FlagDisable, FlagToggle:
+		case FlagUpdate:
+			if op.Update == nil {
+				return fmt.Errorf("invalid atomic operation %d: update action requires an update", i)
+			}
+		default:
+			return fmt.Errorf("invalid atomic operation %d: unknown action %q", i, op.Action)
+		}
+		if seen[op.FlagID] {
+			return fmt.Errorf("invalid atomic operation %d: flag %d appears more than once", i, op.FlagID)
+		}
+		seen[op.FlagID] = true
+	}
+	return nil
+}
+
+// AtomicFlagOperation applies all operations in a single transaction: either every flag changes or none does
+func (c *Client) AtomicFlagOperation(ctx context.Context, ops []FlagOperation, reqOpts ...RequestOption) ([]FeatureFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := validateFlagOperations(ops); err != nil {
+		return nil, err
+	}
+	if c.namespacePrefix() != "" {
+		qualified := make([]FlagOperation, len(ops))
+		for i, op := range ops {
+			if op.Update != nil {
+				update := c.qualifyUpdate(*op.Update)
+				op.Update = &update
+			}
+			qualified[i] = op
+		}
+		ops = qualified
+	}
+	return c.flagsRequest(ctx, request{
+		method: "POST",
+		path:   "/api/v1/feature-flags/atomic",
+		class:  EndpointBulk,
+		body:   map[string]any{"operations": ops},
+	})
+}
+
+// ListFlagGroups retrieves all flag groups
+func (c *Client) ListFlagGroups(ctx context.Context, reqOpts ...RequestOption) ([]FlagGroup, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	respBody, err := c.doRequest(ctx, request{
+		method: "GET",
+		path:   "/api/v1/flag-groups/",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []FlagGroup
+	if err := json.Unmarshal(respBody, &groups); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return groups, nil
+}
+
+// CreateFlagGroup creates a new flag group
+func (c *Client) CreateFlagGroup(ctx context.Context, group FlagGroupCreate, reqOpts ...RequestOption) (*FlagGroup, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.flagGroupRequest(ctx, request{
+		method: "POST",
+		path:   "/api/v1/flag-groups/",
+		body:   group,
+	})
+}
+
+// GetFlagGroup retrieves a flag group by ID
+func (c *Client) GetFlagGroup(ctx context.Context, id int, reqOpts ...RequestOption) (*FlagGroup, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.flagGroupRequest(ctx, request{
+		method: "GET",
+		path:   fmt.Sprintf("/api/v1/flag-groups/%d", id),
+	})
+}
+
+// UpdateFlagGroup updates a flag group
+func (c *Client) UpdateFlagGroup(ctx context.Context, id int, group FlagGroupUpdate, reqOpts ...RequestOption) (*FlagGroup, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.flagGroupRequest(ctx, request{
+		method: "PUT",
+		path:   fmt.Sprintf("/api/v1/flag-groups/%d", id),
+		body:   group,
+	})
+}
+
+// DeleteFlagGroup deletes a flag group without changing its flags
+func (c *Client) DeleteFlagGroup(ctx context.Context, id int, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	_, err := c.doRequest(ctx, request{
+		method: "DELETE",
+		path:   fmt.Sprintf("/api/v1/flag-groups/%d", id),
+	})
+	return err
+}
+
+// SetFlagGroupActive atomically activates or deactivates every flag of a group
+func (c *Client) SetFlagGroupActive(ctx context.Context, id int, active bool, reqOpts ...RequestOption) ([]FeatureFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.flagsRequest(ctx, request{
+		method: "POST",
+		path:   fmt.Sprintf("/api/v1/flag-groups/%d/state", id),
+		class:  EndpointBulk,
+		body:   map[string]bool{"is_active": active},
+	})
+}
+
+// UpdateFlagGroupFlags atomically applies the same update to every flag of a group
+func (c *Client) UpdateFlagGroupFlags(ctx context.Context, id int, update FeatureFlagUpdate, reqOpts ...RequestOption) ([]FeatureFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.flagsRequest(ctx, request{
+		method: "PUT",
+		path:   fmt.Sprintf("/api/v1/flag-groups/%d/flags", id),
+		class:  EndpointBulk,
+		body:   c.qualifyUpdate(update),
+	})
+}
+
+// flagGroupRequest performs a request returning a flag group
+func (c *Client) flagGroupRequest(ctx context.Context, req request) (*FlagGroup, error) {
+	respBody, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var group FlagGroup
+	if err := json.Unmarshal(respBody, &group); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return &group, nil
+}
+
+// flagRequest performs a request returning a flag
+func (c *Client) flagRequest(ctx context.Context, req request) (*FeatureFlag, error) {
+	respBody, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var flag FeatureFlag
+	if err := json.Unmarshal(respBody, &flag); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if err := c.checkUnknownFields(flag); err != nil {
+		return nil, err
+	}
+	c.unqualifyFlag(&flag)
+	return &flag, nil
+}
+
+// flagsRequest performs a request returning a list of flags
+func (c *Client) flagsRequest(ctx context.Context, req request) ([]FeatureFlag, error) {
+	respBody, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var flags []FeatureFlag
+	if err := json.Unmarshal(respBody, &flags); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if err := c.checkUnknownFields(flags...); err != nil {
+		return nil, err
+	}
+	return c.unqualifyFlags(flags), nil
+}
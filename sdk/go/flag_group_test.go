@@ -0,0 +1,275 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// groupServer serves the flag groups and atomic operations of the flags of a
+// fakeServer. Operations apply to every flag or, when one fails, to none.
+type groupServer struct {
+	*httptest.Server
+	fake   *fakeServer
+	mu     sync.Mutex
+	groups map[int]FlagGroup
+	nextID int
+}
+
+func newGroupServer(t *testing.T, fake *fakeServer) *groupServer {
+	s := &groupServer{fake: fake, groups: make(map[int]FlagGroup)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/feature-flags/atomic":
+			var body struct {
+				Operations []FlagOperation `json:"operations"`
+			}
+			if decodeBody(w, r, &body) {
+				s.apply(w, body.Operations)
+			}
+		case strings.HasPrefix(r.URL.Path, "/api/v1/flag-groups/"):
+			s.handleGroups(w, r)
+		default:
+			fake.Config.Handler.ServeHTTP(w, r)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *groupServer) handleGroups(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// /api/v1/flag-groups/[{group}[/state|/flags]]
+	rest := strings.TrimPrefix(r.URL.Path, "/api/v1/flag-groups/")
+	if rest == "" {
+		switch r.Method {
+		case http.MethodGet:
+			groups := []FlagGroup{}
+			for id := 1; id <= s.nextID; id++ {
+				if group, ok := s.groups[id]; ok {
+					groups = append(groups, group)
+				}
+			}
+			writeJSON(w, groups)
+		case http.MethodPost:
+			var create FlagGroupCreate
+			if !decodeBody(w, r, &create) {
+				return
+			}
+			s.nextID++
+			group := FlagGroup{ID: s.nextID, Name: create.Name, Description: create.Description, FlagIDs: create.FlagIDs}
+			s.groups[group.ID] = group
+			writeJSON(w, group)
+		}
+		return
+	}
+	idPart, action, _ := strings.Cut(rest, "/")
+	id, _ := strconv.Atoi(idPart)
+	group, ok := s.groups[id]
+	if !ok {
+		http.Error(w, `{"message":"not found","code":"NOT_FOUND"}`, http.StatusNotFound)
+		return
+	}
+	switch {
+	case action == "state":
+		var body struct {
+			IsActive bool `json:"is_active"`
+		}
+		if !decodeBody(w, r, &body) {
+			return
+		}
+		flagAction := FlagDisable
+		if body.IsActive {
+			flagAction = FlagEnable
+		}
+		var ops []FlagOperation
+		for _, flagID := range group.FlagIDs {
+			ops = append(ops, FlagOperation{FlagID: flagID, Action: flagAction})
+		}
+		s.apply(w, ops)
+		return
+	case action == "flags":
+		var update FeatureFlagUpdate
+		if !decodeBody(w, r, &update) {
+			return
+		}
+		var ops []FlagOperation
+		for _, flagID := range group.FlagIDs {
+			ops = append(ops, FlagOperation{FlagID: flagID, Action: FlagUpdate, Update: &update})
+		}
+		s.apply(w, ops)
+		return
+	case r.Method == http.MethodPut:
+		if !patchJSON(w, r, &group) {
+			return
+		}
+		s.groups[id] = group
+	case r.Method == http.MethodDelete:
+		delete(s.groups, id)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, group)
+}
+
+// apply changes the flags of the fake server in a single transaction
+func (s *groupServer) apply(w http.ResponseWriter, ops []FlagOperation) {
+	s.fake.mu.Lock()
+	defer s.fake.mu.Unlock()
+	changed := make([]FeatureFlag, 0, len(ops))
+	for _, op := range ops {
+		flag, ok := s.fake.flags[op.FlagID]
+		if !ok {
+			http.Error(w, `{"message":"flag not found","code":"NOT_FOUND"}`, http.StatusNotFound)
+			return
+		}
+		switch op.Action {
+		case FlagEnable:
+			flag.IsActive = true
+		case FlagDisable:
+			flag.IsActive = false
+		case FlagToggle:
+			flag.IsActive = !flag.IsActive
+		case FlagUpdate:
+			update, _ := json.Marshal(op.Update)
+			var fields map[string]json.RawMessage
+			json.Unmarshal(update, &fields)
+			current, _ := json.Marshal(flag)
+			var merged map[string]json.RawMessage
+			json.Unmarshal(current, &merged)
+			for name, value := range fields {
+				merged[name] = value
+			}
+			data, _ := json.Marshal(merged)
+			flag = FeatureFlag{}
+			json.Unmarshal(data, &flag)
+		}
+		changed = append(changed, flag)
+	}
+	for _, flag := range changed {
+		s.fake.flags[flag.ID] = flag
+	}
+	writeJSON(w, changed)
+}
+
+func TestAtomicFlagOperation(t *testing.T) {
+	fake := newFakeServer(t)
+	checkout := fake.addFlag(FeatureFlag{Name: "checkout", IsActive: true})
+	payments := fake.addFlag(FeatureFlag{Name: "payments"})
+	srv := newGroupServer(t, fake)
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+
+	description := "new checkout"
+	flags, err := client.AtomicFlagOperation(ctx, []FlagOperation{
+		{FlagID: checkout.ID, Action: FlagToggle},
+		{FlagID: payments.ID, Action: FlagUpdate, Update: &FeatureFlagUpdate{Description: description}},
+	})
+	require.NoError(t, err)
+	require.Len(t, flags, 2)
+	assert.False(t, flags[0].IsActive)
+	assert.Equal(t, "new checkout", flags[1].Description)
+
+	_, err = client.AtomicFlagOperation(ctx, []FlagOperation{
+		{FlagID: checkout.ID, Action: FlagEnable},
+		{FlagID: 99, Action: FlagEnable},
+	})
+	assert.ErrorContains(t, err, "flag not found")
+	got, err := client.GetFeatureFlag(ctx, checkout.ID)
+	require.NoError(t, err)
+	assert.False(t, got.IsActive, "a failed operation changes no flag")
+}
+
+func TestAtomicFlagOperationValidate(t *testing.T) {
+	fake := newFakeServer(t)
+	client := NewClient(fake.URL, "key", nil)
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		ops []FlagOperation
+		err string
+	}{
+		{nil, "invalid atomic operation: no flag operations"},
+		{[]FlagOperation{{FlagID: 1, Action: FlagUpdate}}, "invalid atomic operation 0: update action requires an update"},
+		{[]FlagOperation{{FlagID: 1, Action: "explode"}}, `invalid atomic operation 0: unknown action "explode"`},
+		{[]FlagOperation{{FlagID: 1, Action: FlagEnable}, {FlagID: 1, Action: FlagDisable}}, "invalid atomic operation 1: flag 1 appears more than once"},
+	} {
+		_, err := client.AtomicFlagOperation(ctx, tt.ops)
+		assert.EqualError(t, err, tt.err)
+	}
+	assert.Empty(t, fake.writes(), "invalid operations are not sent")
+}
+
+func TestFlagGroups(t *testing.T) {
+	fake := newFakeServer(t)
+	checkout := fake.addFlag(FeatureFlag{Name: "checkout"})
+	payments := fake.addFlag(FeatureFlag{Name: "payments"})
+	search := fake.addFlag(FeatureFlag{Name: "search"})
+	srv := newGroupServer(t, fake)
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+
+	group, err := client.CreateFlagGroup(ctx, FlagGroupCreate{Name: "checkout-v2", FlagIDs: []int{checkout.ID, payments.ID}})
+	require.NoError(t, err)
+	assert.Equal(t, "checkout-v2", group.Name)
+
+	flags, err := client.SetFlagGroupActive(ctx, group.ID, true)
+	require.NoError(t, err)
+	require.Len(t, flags, 2)
+	for _, flag := range flags {
+		assert.True(t, flag.IsActive, "every flag of the group changes together")
+	}
+	got, err := client.GetFeatureFlag(ctx, search.ID)
+	require.NoError(t, err)
+	assert.False(t, got.IsActive, "flags outside the group are unchanged")
+
+	description := "part of checkout v2"
+	flags, err = client.UpdateFlagGroupFlags(ctx, group.ID, FeatureFlagUpdate{Description: description})
+	require.NoError(t, err)
+	for _, flag := range flags {
+		assert.Equal(t, description, flag.Description)
+		assert.True(t, flag.IsActive)
+	}
+
+	group, err = client.UpdateFlagGroup(ctx, group.ID, FlagGroupUpdate{FlagIDs: []int{checkout.ID}})
+	require.NoError(t, err)
+	assert.Equal(t, "checkout-v2", group.Name, "fields left empty are unchanged")
+	assert.Equal(t, []int{checkout.ID}, group.FlagIDs)
+	got2, err := client.GetFlagGroup(ctx, group.ID)
+	require.NoError(t, err)
+	assert.Equal(t, group, got2)
+
+	require.NoError(t, client.DeleteFlagGroup(ctx, group.ID))
+	groups, err := client.ListFlagGroups(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+	got, err = client.GetFeatureFlag(ctx, checkout.ID)
+	require.NoError(t, err)
+	assert.True(t, got.IsActive, "deleting a group does not change its flags")
+}
+
+func TestAtomicFlagOperationNamespace(t *testing.T) {
+	fake := newFakeServer(t)
+	checkout := fake.addFlag(FeatureFlag{Name: "web.checkout"})
+	srv := newGroupServer(t, fake)
+	client := NewClient(srv.URL, "key", nil, WithNamespace("web"))
+
+	name := "checkout-v2"
+	flags, err := client.AtomicFlagOperation(context.Background(), []FlagOperation{
+		{FlagID: checkout.ID, Action: FlagUpdate, Update: &FeatureFlagUpdate{Name: name}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "checkout-v2", flags[0].Name)
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	assert.Equal(t, "web.checkout-v2", fake.flags[checkout.ID].Name, "updated names are qualified by the namespace")
+}
@@ -0,0 +1,129 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// FlagsState is the evaluation of every flag of an environment for one
+// context, keyed by flag key. It marshals to a single JSON object, so a backend
+// can embed it into a server-rendered page or hand it to a JavaScript client:
+//
+//	{"new-checkout": {"value": true}, "theme": {"value": "dark", "variation": "dark", "variation_index": 1}}
+type FlagsState map[string]FlagState
+
+// FlagState is the evaluation of one flag of a FlagsState
+type FlagState struct {
+	Value any `json:"value"`
+	// Variation and VariationIndex identify the variation served by a multivariate flag
+	Variation      string `json:"variation,omitempty"`
+	VariationIndex *int   `json:"variation_index,omitempty"`
+	// Reason, RuleID, Prerequisite and ErrorCode explain the value, see
+	// EvaluationDetail; they are only set with FlagsStateOptions.WithReasons
+	Reason       EvaluationReason    `json:"reason,omitempty"`
+	RuleID       string              `json:"rule_id,omitempty"`
+	Prerequisite string              `json:"prerequisite,omitempty"`
+	ErrorCode    EvaluationErrorCode `json:"error_code,omitempty"`
+}
+
+// FlagsStateOptions configures AllFlagsState
+type FlagsStateOptions struct {
+	// WithReasons adds the reason of every value, for troubleshooting; they are
+	// left out by default to keep the state small
+	WithReasons bool
+}
+
+// Values returns the value of every flag, keyed by flag key
+func (s FlagsState) Values() map[string]any {
+	values := make(map[string]any, len(s))
+	for key, flag := range s {
+		values[key] = flag.Value
+	}
+	return values
+}
+
+// flagState returns the state of an evaluated flag; failed evaluations have a nil value
+func flagState(d EvaluationDetail[any], opts FlagsStateOptions) FlagState {
+	state := FlagState{Value: d.Value, Variation: d.Variation, VariationIndex: d.VariationIndex}
+	if d.Reason == ReasonError {
+		state.Value = nil
+	}
+	if opts.WithReasons {
+		state.Reason, state.RuleID, state.Prerequisite, state.ErrorCode = d.Reason, d.RuleID, d.Prerequisite, d.ErrorCode
+	}
+	return state
+}
+
+// allFlagsState evaluates every flag of the ruleset for a context
+func (ix *indexedRuleset) allFlagsState(evalCtx EvaluationContext, opts FlagsStateOptions) FlagsState {
+	state := make(FlagsState, len(ix.flags))
+	for key, flag := range ix.flags {
+		state[key] = flagState(ix.evaluate(flag, evalCtx), opts)
+	}
+	return state
+}
+
+// AllFlagsState evaluates every flag of the configured environment for an
+// evaluation context on the server, in one request, or locally from the
+// offline flag file in offline mode and from the loaded ruleset once the
+// client is started. Flags failing to evaluate hold a nil
+// value. The evaluations are not traced, counted in metrics or sent as events,
+// as the state is usually handed to a client that evaluates the flags itself.
+func (c *Client) AllFlagsState(ctx context.Context, evalCtx EvaluationContext, opts FlagsStateOptions, reqOpts ...RequestOption) (FlagsState, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := evalCtx.Validate(); err != nil {
+		return nil, err
+	}
+	if ix, local, err := c.localRuleset(); local {
+		if err != nil {
+			return nil, err
+		}
+		return ix.allFlagsState(evalCtx, opts), nil
+	}
+	respBody, err := c.doRequest(ctx, request{
+		method: "POST",
+		path:   "/api/v1/feature-flags/evaluate/all",
+		body: map[string]any{
+			"environment": c.config.Environment,
+			"context":     evalCtx,
+			"name_prefix": c.namespacePrefix(),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Flags map[string]EvaluationDetail[any] `json:"flags"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	state := make(FlagsState, len(resp.Flags))
+	prefix := c.namespacePrefix()
+	for name, d := range resp.Flags {
+		// Flags of other namespaces are left out should the server return them
+		if key, ok := strings.CutPrefix(name, prefix); ok {
+			if d.Reason == ReasonError && d.ErrorCode == "" {
+				d.ErrorCode = ErrorGeneral
+			}
+			state[key] = flagState(d, opts)
+		}
+	}
+	return state, nil
+}
+
+// AllFlagsState evaluates every flag of the ruleset locally for a context, see
+// Client.AllFlagsState. The evaluations are not recorded as debug events.
+func (e *Evaluator) AllFlagsState(evalCtx EvaluationContext, opts FlagsStateOptions) (FlagsState, error) {
+	ix := e.ruleset.Load()
+	if ix == nil {
+		return nil, ErrEvaluatorNotReady
+	}
+	if err := evalCtx.Validate(); err != nil {
+		return nil, err
+	}
+	return ix.allFlagsState(evalCtx, opts), nil
+}
@@ -0,0 +1,78 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluatorAllFlagsState(t *testing.T) {
+	evaluator := NewEvaluator(NewClient("http://localhost", "key", nil), EvaluatorOptions{Environment: "production"})
+	user := EvaluationContext{Key: "user-1", Attributes: map[string]any{"country": "NL"}}
+	_, err := evaluator.AllFlagsState(user, FlagsStateOptions{})
+	assert.ErrorIs(t, err, ErrEvaluatorNotReady)
+
+	evaluator.SetRuleset(&Ruleset{Environment: "production", Flags: []FeatureFlag{
+		{ID: 1, Name: "new-checkout", IsActive: true, Rules: []FlagRule{
+			{ID: "nl", Conditions: []TargetingCondition{{Attribute: "country", Operator: OpEquals, Value: "NL"}}},
+		}},
+		{ID: 2, Name: "theme", IsActive: true,
+			Variations: []Variation{{Key: "light", Value: "light"}, {Key: "dark", Value: "dark"}},
+			Rules:      []FlagRule{{ID: "all", Variation: "dark"}}},
+		{ID: 3, Name: "search"},
+	}})
+
+	state, err := evaluator.AllFlagsState(user, FlagsStateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"new-checkout": true, "theme": "dark", "search": false}, state.Values())
+	body, err := json.Marshal(state)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"new-checkout": {"value": true},
+		"theme": {"value": "dark", "variation": "dark", "variation_index": 1},
+		"search": {"value": false}
+	}`, string(body))
+
+	state, err = evaluator.AllFlagsState(user, FlagsStateOptions{WithReasons: true})
+	require.NoError(t, err)
+	assert.Equal(t, FlagState{Value: true, Reason: ReasonRuleMatch, RuleID: "nl"}, state["new-checkout"])
+	assert.Equal(t, ReasonOff, state["search"].Reason)
+
+	_, err = evaluator.AllFlagsState(EvaluationContext{}, FlagsStateOptions{})
+	assert.ErrorIs(t, err, ErrInvalidContext)
+}
+
+func TestClientAllFlagsState(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/v1/feature-flags/evaluate/all", r.URL.Path)
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "production", body["environment"])
+		assert.Equal(t, "payments.", body["name_prefix"])
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"flags": {
+			"payments.new-checkout": {"value": true, "reason": "RULE_MATCH", "rule_id": "beta"},
+			"payments.broken": {"value": "garbage", "reason": "ERROR"},
+			"search.instant": {"value": true, "reason": "DEFAULT"}
+		}}`))
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil, WithEnvironment("production"), WithNamespace("payments"))
+
+	state, err := client.AllFlagsState(context.Background(), EvaluationContext{Key: "user-1"}, FlagsStateOptions{WithReasons: true})
+	require.NoError(t, err)
+	assert.Equal(t, FlagsState{
+		"new-checkout": {Value: true, Reason: ReasonRuleMatch, RuleID: "beta"},
+		"broken":       {Reason: ReasonError, ErrorCode: ErrorGeneral},
+	}, state, "flags of other namespaces are left out and failed ones hold no value")
+
+	state, err = client.AllFlagsState(context.Background(), EvaluationContext{Key: "user-1"}, FlagsStateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, FlagState{Value: true}, state["new-checkout"])
+}
@@ -0,0 +1,23 @@
+module github.com/matrixflag/sdk/grpcflag
+
+go 1.21
+
+require (
+	github.com/matrixflag/sdk v0.0.0
+	google.golang.org/grpc v1.62.1
+)
+
+require (
+	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	github.com/pelletier/go-toml/v2 v2.0.8 // indirect
+	golang.org/x/net v0.20.0 // indirect
+	golang.org/x/sys v0.16.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240123012728-ef4313101c80 // indirect
+	google.golang.org/protobuf v1.32.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/matrixflag/sdk => ..
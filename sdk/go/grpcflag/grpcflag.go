@@ -0,0 +1,77 @@
+// Package grpcflag gates gRPC methods with a Matrix Flag kill switch, failing
+// calls of disabled methods with codes.Unavailable. It is a separate module, so
+// only applications using it depend on gRPC.
+package grpcflag
+
+import (
+	"context"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MethodAttribute is the context attribute holding the full method name of a
+// call, such as "/checkout.v1.Checkout/PlaceOrder", for flag rules to target
+const MethodAttribute = "grpc.method"
+
+// MethodContextKind is the kind of the evaluation contexts of calls when
+// Options sets no Context
+const MethodContextKind = "grpc-method"
+
+// Options configures the interceptors
+type Options struct {
+	// Flag is the kill-switch flag evaluated for every call; calls are allowed
+	// while it serves true, and fail with codes.Unavailable when it serves false
+	Flag string
+	// Context returns the evaluation context of a call, such as its
+	// authenticated user. By default it is a context of MethodContextKind keyed
+	// by the method. The method is set as MethodAttribute either way.
+	Context func(ctx context.Context, fullMethod string) matrixflag.EvaluationContext
+	// FailClosed fails calls when the flag cannot be evaluated; by default
+	// they are allowed, so an unreachable flag service does not take the methods down
+	FailClosed bool
+	// Skip reports whether a method is not gated, such as health checks; every
+	// method is gated by default
+	Skip func(fullMethod string) bool
+}
+
+// UnaryServerInterceptor gates unary methods with the kill-switch flag:
+//
+//	server := grpc.NewServer(grpc.UnaryInterceptor(grpcflag.UnaryServerInterceptor(client, grpcflag.Options{Flag: "rpc-enabled"})))
+func UnaryServerInterceptor(flags matrixflag.FlagEvaluator, opts Options) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := check(ctx, flags, opts, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor gates streaming methods with the kill-switch flag,
+// once when the stream is opened
+func StreamServerInterceptor(flags matrixflag.FlagEvaluator, opts Options) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := check(ss.Context(), flags, opts, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// check evaluates the kill-switch flag for a call, returning the error failing it when the method is disabled
+func check(ctx context.Context, flags matrixflag.FlagEvaluator, opts Options, fullMethod string) error {
+	if opts.Skip != nil && opts.Skip(fullMethod) {
+		return nil
+	}
+	evalCtx := matrixflag.EvaluationContext{Kind: MethodContextKind, Key: fullMethod}
+	if opts.Context != nil {
+		evalCtx = opts.Context(ctx, fullMethod)
+	}
+	d := flags.EvaluateBool(ctx, opts.Flag, evalCtx.With(MethodAttribute, fullMethod), !opts.FailClosed)
+	if d.Value {
+		return nil
+	}
+	return status.Errorf(codes.Unavailable, "method %s is disabled by flag %s", fullMethod, opts.Flag)
+}
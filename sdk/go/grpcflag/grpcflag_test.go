@@ -0,0 +1,71 @@
+package grpcflag
+
+import (
+	"context"
+	"testing"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/matrixflag/sdk/matrixflagtest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// serverStream is a grpc.ServerStream carrying only a context
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s serverStream) Context() context.Context { return s.ctx }
+
+func TestInterceptors(t *testing.T) {
+	td := matrixflagtest.NewTestDataSource()
+	td.SetFlag(matrixflag.FeatureFlag{
+		Name:       "rpc-enabled",
+		IsActive:   true,
+		Variations: []matrixflag.Variation{{Key: "on", Value: true, Weight: 1}, {Key: "off", Value: false}},
+		Rules: []matrixflag.FlagRule{{
+			ID:         "kill-refunds",
+			Conditions: []matrixflag.TargetingCondition{{Attribute: MethodAttribute, Operator: matrixflag.OpEquals, Value: "/shop.v1.Shop/Refund"}},
+			Variation:  "off",
+		}},
+	})
+	client := td.Client()
+	opts := Options{Flag: "rpc-enabled", Skip: func(method string) bool { return method == "/grpc.health.v1.Health/Check" }}
+	unary := UnaryServerInterceptor(client, opts)
+	ctx := context.Background()
+	call := func(interceptor grpc.UnaryServerInterceptor, method string) error {
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(context.Context, any) (any, error) { return "ok", nil })
+		return err
+	}
+
+	if err := call(unary, "/shop.v1.Shop/Order"); err != nil {
+		t.Errorf("enabled method failed: %v", err)
+	}
+	err := call(unary, "/shop.v1.Shop/Refund")
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("disabled method returned %v, want Unavailable", err)
+	}
+	if err := call(unary, "/grpc.health.v1.Health/Check"); err != nil {
+		t.Errorf("skipped method failed: %v", err)
+	}
+	if evaluations := len(td.Evaluations()); evaluations != 2 {
+		t.Errorf("the flag was evaluated %d times, want 2 as skipped methods are not gated", evaluations)
+	}
+
+	stream := StreamServerInterceptor(client, opts)
+	err = stream(nil, serverStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/shop.v1.Shop/Refund"}, func(any, grpc.ServerStream) error { return nil })
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("disabled stream returned %v, want Unavailable", err)
+	}
+
+	td.Delete("rpc-enabled")
+	if err := call(unary, "/shop.v1.Shop/Order"); err != nil {
+		t.Errorf("calls failed open while the flag cannot be evaluated: %v", err)
+	}
+	opts.FailClosed = true
+	if err := call(UnaryServerInterceptor(client, opts), "/shop.v1.Shop/Order"); status.Code(err) != codes.Unavailable {
+		t.Errorf("a fail closed call returned %v, want Unavailable", err)
+	}
+}
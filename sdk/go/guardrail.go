@@ -0,0 +1,346 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MetricProvider identifies the monitoring system a guardrail query runs against
+type MetricProvider string
+
+// Metric providers
+const (
+	MetricProviderPrometheus MetricProvider = "prometheus"
+	MetricProviderDatadog    MetricProvider = "datadog"
+)
+
+// GuardrailAction is the action taken when a guardrail is breached
+type GuardrailAction string
+
+// Guardrail actions
+const (
+	GuardrailPause    GuardrailAction = "pause"
+	GuardrailRollback GuardrailAction = "rollback"
+)
+
+// Comparison operators for guardrail thresholds
+const (
+	ComparisonGreaterThan = "gt"
+	ComparisonLessThan    = "lt"
+)
+
+// Guardrail represents a metric threshold attached to a rollout
+type Guardrail struct {
+	Name     string         `json:"name"`
+	Provider MetricProvider `json:"provider"`
+	// Query must evaluate to a single number, e.g. an error ratio or a latency quantile
+	Query string `json:"query"`
+	// Comparison is "gt" (breached above the threshold) or "lt" (breached below it)
+	Comparison string          `json:"comparison"`
+	Threshold  float64         `json:"threshold"`
+	Action     GuardrailAction `json:"action"`
+	// WindowSeconds is the lookback window of providers that need one, such as Datadog
+	WindowSeconds int `json:"window_seconds,omitempty"`
+}
+
+// Validate checks that the guardrail is well formed
+func (g Guardrail) Validate() error {
+	if g.Name == "" || g.Query == "" {
+		return fmt.Errorf("name and query are required")
+	}
+	switch g.Provider {
+	case MetricProviderPrometheus, MetricProviderDatadog:
+	default:
+		return fmt.Errorf("unknown provider %q", g.Provider)
+	}
+	if g.Comparison != ComparisonGreaterThan && g.Comparison != ComparisonLessThan {
+		return fmt.Errorf("comparison must be %q or %q", ComparisonGreaterThan, ComparisonLessThan)
+	}
+	if g.Action != GuardrailPause && g.Action != GuardrailRollback {
+		return fmt.Errorf("action must be %q or %q", GuardrailPause, GuardrailRollback)
+	}
+	return nil
+}
+
+// Breached reports whether value violates the guardrail threshold
+func (g Guardrail) Breached(value float64) bool {
+	if g.Comparison == ComparisonLessThan {
+		return value < g.Threshold
+	}
+	return value > g.Threshold
+}
+
+// MetricSource evaluates guardrail queries
+type MetricSource interface {
+	Query(ctx context.Context, g Guardrail) (float64, error)
+}
+
+// PrometheusSource evaluates guardrail queries with the Prometheus instant query API
+type PrometheusSource struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+// Query implements MetricSource
+func (s *PrometheusSource) Query(ctx context.Context, g Guardrail) (float64, error) {
+	endpoint := strings.TrimRight(s.URL, "/") + "/api/v1/query?" + url.Values{"query": {g.Query}}.Encode()
+	var resp struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Data   struct {
+			ResultType string          `json:"resultType"`
+			Result     json.RawMessage `json:"result"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, s.HTTPClient, endpoint, nil, &resp); err != nil {
+		return 0, err
+	}
+	if resp.Status != "success" {
+		return 0, fmt.Errorf("prometheus query failed: %s", resp.Error)
+	}
+
+	var sample []any
+	switch resp.Data.ResultType {
+	case "scalar":
+		if err := json.Unmarshal(resp.Data.Result, &sample); err != nil {
+			return 0, fmt.Errorf("failed to decode prometheus scalar: %w", err)
+		}
+	case "vector":
+		var series []struct {
+			Value []any `json:"value"`
+		}
+		if err := json.Unmarshal(resp.Data.Result, &series); err != nil {
+			return 0, fmt.Errorf("failed to decode prometheus vector: %w", err)
+		}
+		if len(series) != 1 {
+			return 0, fmt.Errorf("prometheus query returned %d series, expected 1", len(series))
+		}
+		sample = series[0].Value
+	default:
+		return 0, fmt.Errorf("unsupported prometheus result type %q", resp.Data.ResultType)
+	}
+
+	if len(sample) != 2 {
+		return 0, fmt.Errorf("unexpected prometheus sample %v", sample)
+	}
+	str, _ := sample[1].(string)
+	value, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid prometheus sample value %v: %w", sample[1], err)
+	}
+	return value, nil
+}
+
+// DatadogSource evaluates guardrail queries with the Datadog metrics query API
+type DatadogSource struct {
+	APIKey string
+	AppKey string
+	// Site is the Datadog site, "datadoghq.com" by default
+	Site       string
+	HTTPClient *http.Client
+	// Clock sets the end of the query window, SystemClock by default
+	Clock Clock
+}
+
+// Query implements MetricSource and returns the latest point of the first series
+func (s *DatadogSource) Query(ctx context.Context, g Guardrail) (float64, error) {
+	site := s.Site
+	if site == "" {
+		site = "datadoghq.com"
+	}
+	window := time.Duration(g.WindowSeconds) * time.Second
+	if window <= 0 {
+		window = 5 * time.Minute
+	}
+	clock := s.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	now := clock.Now()
+	endpoint := "https://api." + site + "/api/v1/query?" + url.Values{
+		"query": {g.Query},
+		"from":  {strconv.FormatInt(now.Add(-window).Unix(), 10)},
+		"to":    {strconv.FormatInt(now.Unix(), 10)},
+	}.Encode()
+
+	var resp struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Series []struct {
+			Pointlist [][]*float64 `json:"pointlist"`
+		} `json:"series"`
+	}
+	headers := map[string]string{"DD-API-KEY": s.APIKey, "DD-APPLICATION-KEY": s.AppKey}
+	if err := getJSON(ctx, s.HTTPClient, endpoint, headers, &resp); err != nil {
+		return 0, err
+	}
+	if resp.Status == "error" {
+		return 0, fmt.Errorf("datadog query failed: %s", resp.Error)
+	}
+	if len(resp.Series) == 0 {
+		return 0, fmt.Errorf("datadog query returned no data")
+	}
+	points := resp.Series[0].Pointlist
+	for i := len(points) - 1; i >= 0; i-- {
+		if len(points[i]) == 2 && points[i][1] != nil {
+			return *points[i][1], nil
+		}
+	}
+	return 0, fmt.Errorf("datadog query returned no points")
+}
+
+// getJSON performs a GET request and decodes the JSON response into v
+func getJSON(ctx context.Context, client *http.Client, endpoint string, headers map[string]string, v any) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("metric query failed (status %d): %s", resp.StatusCode, string(body))
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// GuardrailBreach describes a breached guardrail and the action taken
+type GuardrailBreach struct {
+	FlagID    int
+	Guardrail Guardrail
+	Value     float64
+	Action    GuardrailAction
+}
+
+// GuardrailMonitorOptions configures a GuardrailMonitor
+type GuardrailMonitorOptions struct {
+	// Interval is the time between guardrail checks, one minute by default
+	Interval time.Duration
+	// OnBreach is called after a breached guardrail paused or rolled back the rollout
+	OnBreach func(GuardrailBreach)
+	// OnError is called when a check fails
+	OnError func(error)
+}
+
+// GuardrailMonitor enforces the guardrails of a rollout from the SDK side, for
+// deployments where the monitoring system is not reachable from the server
+type GuardrailMonitor struct {
+	client  *Client
+	flagID  int
+	sources map[MetricProvider]MetricSource
+	opts    GuardrailMonitorOptions
+}
+
+// NewGuardrailMonitor creates a monitor for the rollout of a flag, using sources to evaluate guardrail queries
+func NewGuardrailMonitor(client *Client, flagID int, sources map[MetricProvider]MetricSource, opts GuardrailMonitorOptions) *GuardrailMonitor {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Minute
+	}
+	return &GuardrailMonitor{
+		client:  client,
+		flagID:  flagID,
+		sources: sources,
+		opts:    opts,
+	}
+}
+
+// Run checks the guardrails every interval until ctx is canceled or the rollout stops running
+func (m *GuardrailMonitor) Run(ctx context.Context) {
+	for {
+		breach, running, err := m.check(ctx)
+		if err != nil && m.opts.OnError != nil {
+			m.opts.OnError(err)
+		}
+		if breach != nil || (err == nil && !running) {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.client.clock.After(m.opts.Interval):
+		}
+	}
+}
+
+// Check evaluates the guardrails of a running rollout once and acts on the first breach.
+// Rollback guardrails are checked before pause guardrails. A guardrail that fails to
+// evaluate does not stop the others from being checked: its error is returned, joined
+// with those of the other failed guardrails, along with the breach acted on, if any.
+func (m *GuardrailMonitor) Check(ctx context.Context) (*GuardrailBreach, error) {
+	breach, _, err := m.check(ctx)
+	return breach, err
+}
+
+// check is Check, also reporting whether the rollout is still running
+func (m *GuardrailMonitor) check(ctx context.Context) (*GuardrailBreach, bool, error) {
+	rollout, err := m.client.GetRollout(ctx, m.flagID)
+	if err != nil {
+		return nil, true, err
+	}
+	if rollout.Status != RolloutRunning {
+		return nil, false, nil
+	}
+
+	var errs []error
+	for _, action := range []GuardrailAction{GuardrailRollback, GuardrailPause} {
+		for _, g := range rollout.Guardrails {
+			if g.Action != action {
+				continue
+			}
+			source, ok := m.sources[g.Provider]
+			if !ok {
+				errs = append(errs, fmt.Errorf("no metric source for guardrail %s (provider %s)", g.Name, g.Provider))
+				continue
+			}
+			value, err := source.Query(ctx, g)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to evaluate guardrail %s: %w", g.Name, err))
+				continue
+			}
+			if !g.Breached(value) {
+				continue
+			}
+
+			breach := &GuardrailBreach{FlagID: m.flagID, Guardrail: g, Value: value, Action: action}
+			reason := fmt.Sprintf("guardrail %s breached: %g %s %g", g.Name, value, g.Comparison, g.Threshold)
+			if action == GuardrailRollback {
+				_, err = m.client.RollbackRollout(ctx, m.flagID, reason)
+			} else {
+				_, err = m.client.PauseRollout(ctx, m.flagID, reason)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to %s rollout: %w", action, err))
+				return nil, true, errors.Join(errs...)
+			}
+			if m.opts.OnBreach != nil {
+				m.opts.OnBreach(*breach)
+			}
+			return breach, false, errors.Join(errs...)
+		}
+	}
+	return nil, true, errors.Join(errs...)
+}
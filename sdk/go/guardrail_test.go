@@ -0,0 +1,132 @@
+package matrixflag
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedClock is a Clock stopped at now whose timers fire immediately
+type fixedClock struct{ now time.Time }
+
+func (c fixedClock) Now() time.Time { return c.now }
+
+func (c fixedClock) After(time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func TestGuardrailMonitorStopsWhenRolloutEnds(t *testing.T) {
+	var mu sync.Mutex
+	statuses := []RolloutStatus{RolloutRunning, RolloutRunning, RolloutCompleted}
+	gets := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		status := statuses[min(gets, len(statuses)-1)]
+		gets++
+		writeJSON(w, Rollout{FlagID: 1, Status: status})
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil, WithClock(fixedClock{time.Now()}))
+
+	done := make(chan struct{})
+	go func() {
+		NewGuardrailMonitor(client, 1, nil, GuardrailMonitorOptions{}).Run(context.Background())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the rollout completed")
+	}
+	assert.Equal(t, 3, gets)
+}
+
+func TestDatadogSourceUsesClock(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var query map[string][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"status":"ok","series":[{"pointlist":[[1,0.5],[2,0.25]]}]}`))
+	}))
+	defer srv.Close()
+
+	source := &DatadogSource{
+		HTTPClient: &http.Client{Transport: rewriteTransport{srv.URL}},
+		Clock:      fixedClock{now},
+	}
+	value, err := source.Query(context.Background(), Guardrail{Query: "avg:errors{*}", WindowSeconds: 60})
+	require.NoError(t, err)
+	assert.Equal(t, 0.25, value)
+	assert.Equal(t, []string{"1714564740"}, query["from"])
+	assert.Equal(t, []string{"1714564800"}, query["to"])
+}
+
+// rewriteTransport sends every request to the test server
+type rewriteTransport struct{ target string }
+
+func (t rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme = "http"
+	r.URL.Host = t.target[len("http://"):]
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// metricSourceFunc is a MetricSource calling a function
+type metricSourceFunc func(ctx context.Context, g Guardrail) (float64, error)
+
+func (f metricSourceFunc) Query(ctx context.Context, g Guardrail) (float64, error) { return f(ctx, g) }
+
+func TestGuardrailMonitorChecksPastFailures(t *testing.T) {
+	var actions []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			actions = append(actions, r.URL.Path)
+		}
+		writeJSON(w, Rollout{FlagID: 1, Status: RolloutRunning, Guardrails: []Guardrail{
+			{Name: "latency", Provider: MetricProviderDatadog, Action: GuardrailPause, Comparison: ComparisonGreaterThan, Threshold: 500},
+			{Name: "apdex", Provider: MetricProviderDatadog, Action: GuardrailRollback, Comparison: ComparisonLessThan, Threshold: 0.9},
+			{Name: "saturation", Provider: "cloudwatch", Action: GuardrailRollback, Comparison: ComparisonGreaterThan, Threshold: 0.8},
+			{Name: "errors", Provider: MetricProviderPrometheus, Action: GuardrailRollback, Comparison: ComparisonGreaterThan, Threshold: 0.05},
+		}})
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil, WithRetries(0, 0, 0))
+	sources := map[MetricProvider]MetricSource{
+		MetricProviderDatadog: metricSourceFunc(func(context.Context, Guardrail) (float64, error) {
+			return 0, errors.New("datadog unavailable")
+		}),
+		MetricProviderPrometheus: metricSourceFunc(func(context.Context, Guardrail) (float64, error) {
+			return 0.2, nil
+		}),
+	}
+	var breaches []GuardrailBreach
+	monitor := NewGuardrailMonitor(client, 1, sources, GuardrailMonitorOptions{
+		OnBreach: func(b GuardrailBreach) { breaches = append(breaches, b) },
+	})
+
+	breach, err := monitor.Check(context.Background())
+	require.NotNil(t, breach, "a failing guardrail does not hide the breach of another")
+	assert.Equal(t, "errors", breach.Guardrail.Name)
+	assert.Equal(t, GuardrailRollback, breach.Action)
+	assert.Equal(t, []string{"/api/v1/feature-flags/1/rollout/rollback"}, actions)
+	assert.Len(t, breaches, 1)
+	assert.ErrorContains(t, err, "failed to evaluate guardrail apdex: datadog unavailable")
+	assert.ErrorContains(t, err, "no metric source for guardrail saturation (provider cloudwatch)")
+
+	actions = nil
+	sources[MetricProviderPrometheus] = metricSourceFunc(func(context.Context, Guardrail) (float64, error) { return 0.01, nil })
+	breach, err = monitor.Check(context.Background())
+	assert.Nil(t, breach)
+	assert.ErrorContains(t, err, "failed to evaluate guardrail latency")
+	assert.Empty(t, actions)
+}
@@ -0,0 +1,217 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Holdout represents a slice of traffic excluded from experiments, used as
+// a long-term control group for measuring the combined impact of launches
+type Holdout struct {
+	ID          int    `json:"id"`
+	Key         string `json:"key"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Environment string `json:"environment"`
+	// Percentage of traffic held out, between 0 and 100
+	Percentage float64 `json:"percentage"`
+	Salt       string  `json:"salt,omitempty"`
+	// FlagIDs limits the holdout to the given experiments; it applies to all experiments when empty
+	FlagIDs   []int     `json:"flag_ids,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// HoldoutCreate represents the data needed to create a holdout
+type HoldoutCreate struct {
+	Key         string  `json:"key"`
+	Name        string  `json:"name,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Environment string  `json:"environment"`
+	Percentage  float64 `json:"percentage"`
+	Salt        string  `json:"salt,omitempty"`
+	FlagIDs     []int   `json:"flag_ids,omitempty"`
+}
+
+// HoldoutUpdate represents the data needed to update a holdout
+type HoldoutUpdate struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Percentage is left unchanged when nil; set it to a zero value to release the whole holdout
+	Percentage *float64 `json:"percentage,omitempty"`
+	FlagIDs    []int    `json:"flag_ids,omitempty"`
+}
+
+// HoldoutMembership records whether a key was held out of an experiment, for
+// use in evaluation reasons and analytics events
+type HoldoutMembership struct {
+	HoldoutID  int    `json:"holdout_id"`
+	HoldoutKey string `json:"holdout_key"`
+	InHoldout  bool   `json:"in_holdout"`
+}
+
+// salt returns the bucketing salt of the holdout
+func (h *Holdout) salt() string {
+	if h.Salt != "" {
+		return h.Salt
+	}
+	return "holdout." + h.Key
+}
+
+// Contains reports whether key falls into the held out slice of traffic
+func (h *Holdout) Contains(key string) bool {
+	return key != "" && Bucket(key, h.salt()) < h.Percentage
+}
+
+// AppliesTo reports whether the holdout covers the experiment of flagID
+func (h *Holdout) AppliesTo(flagID int) bool {
+	if len(h.FlagIDs) == 0 {
+		return true
+	}
+	for _, id := range h.FlagIDs {
+		if id == flagID {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns the holdout membership of key for the experiment of flagID, or nil when the holdout does not apply
+func (h *Holdout) Check(flagID int, key string) *HoldoutMembership {
+	if !h.AppliesTo(flagID) {
+		return nil
+	}
+	return &HoldoutMembership{HoldoutID: h.ID, HoldoutKey: h.Key, InHoldout: h.Contains(key)}
+}
+
+// HeldOut returns the first holdout that excludes key from the experiment of flagID.
+//
+// Deprecated: HeldOut applies holdouts without FlagIDs to every flag, while
+// evaluation only applies them to experiments (see FeatureFlag.IsExperiment).
+// Use HeldOutOf, which agrees with evaluation.
+func HeldOut(holdouts []Holdout, flagID int, key string) (*HoldoutMembership, bool) {
+	for i := range holdouts {
+		if m := holdouts[i].Check(flagID, key); m != nil && m.InHoldout {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+// IsExperiment reports whether a flag runs an experiment: it belongs to a
+// layer, serves variations or allocates traffic. Holdouts without FlagIDs only
+// apply to experiments.
+func (f *FeatureFlag) IsExperiment() bool {
+	return f.LayerID != 0 || len(f.Variations) > 0 || f.TrafficAllocation != nil
+}
+
+// HeldOutOf returns the first holdout that excludes key from flag, as evaluation
+// does. Holdouts listing the flag apply to it in any case, the others only when
+// it is an experiment.
+func HeldOutOf(holdouts []Holdout, flag *FeatureFlag, key string) (*HoldoutMembership, bool) {
+	experiment := flag.IsExperiment()
+	for i := range holdouts {
+		h := &holdouts[i]
+		if len(h.FlagIDs) == 0 && !experiment {
+			continue
+		}
+		if m := h.Check(flag.ID, key); m != nil && m.InHoldout {
+			return m, true
+		}
+	}
+	return nil, false
+}
+
+// validateHoldoutPercentage checks that a holdout percentage lies within [0, 100]
+func validateHoldoutPercentage(p float64) error {
+	if p < 0 || p > 100 {
+		return fmt.Errorf("invalid holdout percentage %g: must be between 0 and 100", p)
+	}
+	return nil
+}
+
+// ListHoldouts retrieves the holdouts of an environment
+func (c *Client) ListHoldouts(ctx context.Context, environment string, reqOpts ...RequestOption) ([]Holdout, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	var query map[string]string
+	if environment != "" {
+		query = map[string]string{"environment": environment}
+	}
+	respBody, err := c.doRequest(ctx, request{
+		method: "GET",
+		path:   "/api/v1/holdouts/",
+		query:  query,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var holdouts []Holdout
+	if err := json.Unmarshal(respBody, &holdouts); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return holdouts, nil
+}
+
+// CreateHoldout creates a new holdout
+func (c *Client) CreateHoldout(ctx context.Context, holdout HoldoutCreate, reqOpts ...RequestOption) (*Holdout, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := validateHoldoutPercentage(holdout.Percentage); err != nil {
+		return nil, err
+	}
+	return c.holdoutRequest(ctx, request{
+		method: "POST",
+		path:   "/api/v1/holdouts/",
+		body:   holdout,
+	})
+}
+
+// GetHoldout retrieves a holdout by ID
+func (c *Client) GetHoldout(ctx context.Context, id int, reqOpts ...RequestOption) (*Holdout, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.holdoutRequest(ctx, request{
+		method: "GET",
+		path:   fmt.Sprintf("/api/v1/holdouts/%d", id),
+	})
+}
+
+// UpdateHoldout updates a holdout
+func (c *Client) UpdateHoldout(ctx context.Context, id int, holdout HoldoutUpdate, reqOpts ...RequestOption) (*Holdout, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if holdout.Percentage != nil {
+		if err := validateHoldoutPercentage(*holdout.Percentage); err != nil {
+			return nil, err
+		}
+	}
+	return c.holdoutRequest(ctx, request{
+		method: "PUT",
+		path:   fmt.Sprintf("/api/v1/holdouts/%d", id),
+		body:   holdout,
+	})
+}
+
+// DeleteHoldout deletes a holdout
+func (c *Client) DeleteHoldout(ctx context.Context, id int, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	_, err := c.doRequest(ctx, request{
+		method: "DELETE",
+		path:   fmt.Sprintf("/api/v1/holdouts/%d", id),
+	})
+	return err
+}
+
+// holdoutRequest performs a request returning a holdout
+func (c *Client) holdoutRequest(ctx context.Context, req request) (*Holdout, error) {
+	respBody, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var holdout Holdout
+	if err := json.Unmarshal(respBody, &holdout); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return &holdout, nil
+}
@@ -0,0 +1,85 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateHoldoutPercentage(t *testing.T) {
+	var body map[string]json.RawMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		writeJSON(w, Holdout{ID: 1})
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+
+	zero := 0.0
+	_, err := client.UpdateHoldout(ctx, 1, HoldoutUpdate{Percentage: &zero})
+	require.NoError(t, err)
+	assert.JSONEq(t, `0`, string(body["percentage"]), "a zero percentage is sent")
+
+	_, err = client.UpdateHoldout(ctx, 1, HoldoutUpdate{Name: "renamed"})
+	require.NoError(t, err)
+	assert.NotContains(t, body, "percentage", "an unset percentage is left unchanged")
+
+	tooLarge := 101.0
+	_, err = client.UpdateHoldout(ctx, 1, HoldoutUpdate{Percentage: &tooLarge})
+	assert.Error(t, err)
+}
+
+func TestHeldOutOfAgreesWithEvaluation(t *testing.T) {
+	holdouts := []Holdout{
+		{ID: 1, Key: "global", Percentage: 20},
+		{ID: 2, Key: "checkout", Percentage: 30, FlagIDs: []int{2}},
+	}
+	flags := []FeatureFlag{
+		{ID: 1, Name: "banner", IsActive: true},
+		{ID: 2, Name: "checkout", IsActive: true},
+		{ID: 3, Name: "pricing", IsActive: true, Variations: []Variation{{Key: "a", Value: "a", Weight: 1}}},
+		{ID: 4, Name: "search", IsActive: true, TrafficAllocation: &TrafficAllocation{Percentage: 100}},
+	}
+	evaluator := NewEvaluator(NewClient("http://localhost", "key", nil), EvaluatorOptions{Environment: "production"})
+	evaluator.SetRuleset(&Ruleset{Environment: "production", Flags: flags, Holdouts: holdouts})
+
+	for i := 0; i < 500; i++ {
+		key := fmt.Sprintf("user-%d", i)
+		for j := range flags {
+			flag := &flags[j]
+			membership, out := HeldOutOf(holdouts, flag, key)
+			d := evaluator.Evaluate(flag.Name, EvaluationContext{Key: key}, false)
+			assert.Equal(t, out, d.Reason == ReasonExcluded, "%s for %s", flag.Name, key)
+			if out {
+				assert.Equal(t, membership, d.Holdout)
+			}
+
+			legacyMembership, legacyOut := HeldOut(holdouts, flag.ID, key)
+			if flag.IsExperiment() {
+				assert.Equal(t, out, legacyOut, "HeldOut agrees for experiments")
+				assert.Equal(t, membership, legacyMembership)
+			} else if out {
+				assert.True(t, legacyOut, "HeldOut holds out plain flags at least as often")
+			}
+		}
+	}
+
+	// The deprecated HeldOut applies the global holdout to the plain banner flag, evaluation does not
+	key := "user-0"
+	for i := 1; !holdouts[0].Contains(key); i++ {
+		key = fmt.Sprintf("user-%d", i)
+	}
+	_, legacyOut := HeldOut(holdouts, flags[0].ID, key)
+	_, out := HeldOutOf(holdouts, &flags[0], key)
+	assert.True(t, legacyOut)
+	assert.False(t, out)
+	assert.Equal(t, ReasonDefault, evaluator.Evaluate("banner", EvaluationContext{Key: key}, false).Reason)
+}
@@ -0,0 +1,187 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Layer represents a namespace of mutually exclusive experiments. Each flag
+// in the layer owns a disjoint range of the layer's buckets, so a user is
+// only ever in one of the layer's experiments.
+type Layer struct {
+	ID          int               `json:"id"`
+	Key         string            `json:"key"`
+	Name        string            `json:"name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Environment string            `json:"environment"`
+	Salt        string            `json:"salt,omitempty"`
+	Allocations []LayerAllocation `json:"allocations"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// LayerAllocation assigns the bucket range [Start, End) of a layer, in percent, to a flag
+type LayerAllocation struct {
+	FlagID int     `json:"flag_id"`
+	Start  float64 `json:"start"`
+	End    float64 `json:"end"`
+}
+
+// LayerCreate represents the data needed to create a layer
+type LayerCreate struct {
+	Key         string            `json:"key"`
+	Name        string            `json:"name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Environment string            `json:"environment"`
+	Salt        string            `json:"salt,omitempty"`
+	Allocations []LayerAllocation `json:"allocations,omitempty"`
+}
+
+// LayerUpdate represents the data needed to update a layer
+type LayerUpdate struct {
+	Name        string            `json:"name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Allocations []LayerAllocation `json:"allocations,omitempty"`
+}
+
+// ValidateLayerAllocations checks that allocations lie within [0, 100], do not
+// overlap and reference each flag at most once
+func ValidateLayerAllocations(allocations []LayerAllocation) error {
+	sorted := append([]LayerAllocation(nil), allocations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	flags := make(map[int]bool, len(sorted))
+	for i, a := range sorted {
+		if a.Start < 0 || a.End > 100 || a.Start >= a.End {
+			return fmt.Errorf("invalid layer allocation for flag %d: range [%g, %g) must lie within [0, 100]", a.FlagID, a.Start, a.End)
+		}
+		if flags[a.FlagID] {
+			return fmt.Errorf("invalid layer allocation: flag %d is allocated more than once", a.FlagID)
+		}
+		flags[a.FlagID] = true
+		if i > 0 && a.Start < sorted[i-1].End {
+			return fmt.Errorf("invalid layer allocation: flags %d and %d overlap", sorted[i-1].FlagID, a.FlagID)
+		}
+	}
+	return nil
+}
+
+// salt returns the bucketing salt of the layer
+func (l *Layer) salt() string {
+	if l.Salt != "" {
+		return l.Salt
+	}
+	return "layer." + l.Key
+}
+
+// Assign returns the flag whose range contains the bucket of key, if any
+func (l *Layer) Assign(key string) (flagID int, ok bool) {
+	b := Bucket(key, l.salt())
+	for _, a := range l.Allocations {
+		if b >= a.Start && b < a.End {
+			return a.FlagID, true
+		}
+	}
+	return 0, false
+}
+
+// Allows reports whether key may enter the experiment of flagID. Flags outside the layer are always allowed.
+func (l *Layer) Allows(flagID int, key string) bool {
+	allocated := false
+	for _, a := range l.Allocations {
+		if a.FlagID == flagID {
+			allocated = true
+			break
+		}
+	}
+	if !allocated {
+		return true
+	}
+	assigned, ok := l.Assign(key)
+	return ok && assigned == flagID
+}
+
+// ListLayers retrieves the experiment layers of an environment
+func (c *Client) ListLayers(ctx context.Context, environment string, reqOpts ...RequestOption) ([]Layer, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	var query map[string]string
+	if environment != "" {
+		query = map[string]string{"environment": environment}
+	}
+	respBody, err := c.doRequest(ctx, request{
+		method: "GET",
+		path:   "/api/v1/layers/",
+		query:  query,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var layers []Layer
+	if err := json.Unmarshal(respBody, &layers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return layers, nil
+}
+
+// CreateLayer creates a new experiment layer
+func (c *Client) CreateLayer(ctx context.Context, layer LayerCreate, reqOpts ...RequestOption) (*Layer, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := ValidateLayerAllocations(layer.Allocations); err != nil {
+		return nil, err
+	}
+	return c.layerRequest(ctx, request{
+		method: "POST",
+		path:   "/api/v1/layers/",
+		body:   layer,
+	})
+}
+
+// GetLayer retrieves an experiment layer by ID
+func (c *Client) GetLayer(ctx context.Context, id int, reqOpts ...RequestOption) (*Layer, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.layerRequest(ctx, request{
+		method: "GET",
+		path:   fmt.Sprintf("/api/v1/layers/%d", id),
+	})
+}
+
+// UpdateLayer updates an experiment layer
+func (c *Client) UpdateLayer(ctx context.Context, id int, layer LayerUpdate, reqOpts ...RequestOption) (*Layer, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := ValidateLayerAllocations(layer.Allocations); err != nil {
+		return nil, err
+	}
+	return c.layerRequest(ctx, request{
+		method: "PUT",
+		path:   fmt.Sprintf("/api/v1/layers/%d", id),
+		body:   layer,
+	})
+}
+
+// DeleteLayer deletes an experiment layer
+func (c *Client) DeleteLayer(ctx context.Context, id int, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	_, err := c.doRequest(ctx, request{
+		method: "DELETE",
+		path:   fmt.Sprintf("/api/v1/layers/%d", id),
+	})
+	return err
+}
+
+// layerRequest performs a request returning a layer
+func (c *Client) layerRequest(ctx context.Context, req request) (*Layer, error) {
+	respBody, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var layer Layer
+	if err := json.Unmarshal(respBody, &layer); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return &layer, nil
+}
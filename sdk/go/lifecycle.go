@@ -0,0 +1,68 @@
+package matrixflag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// FlagLifecycle is the lifecycle state of a flag, from active to archived
+type FlagLifecycle string
+
+// Flag lifecycle states
+const (
+	// LifecycleActive flags are in use; flags without a lifecycle are active
+	LifecycleActive FlagLifecycle = "active"
+	// LifecycleDeprecated flags still evaluate normally but are due for removal
+	LifecycleDeprecated FlagLifecycle = "deprecated"
+	// LifecycleArchived flags are retired: evaluations serve the default value
+	// with ErrFlagArchived until the flag is unarchived or deleted
+	LifecycleArchived FlagLifecycle = "archived"
+)
+
+// ErrFlagArchived is the error of evaluations of an archived flag
+var ErrFlagArchived = errors.New("flag is archived")
+
+// Validate checks that the lifecycle is one of the lifecycle states
+func (l FlagLifecycle) Validate() error {
+	switch l {
+	case LifecycleActive, LifecycleDeprecated, LifecycleArchived:
+		return nil
+	}
+	return fmt.Errorf("invalid lifecycle %q: must be %s, %s or %s", l, LifecycleActive, LifecycleDeprecated, LifecycleArchived)
+}
+
+// State returns the lifecycle state of the flag, LifecycleActive when it has none
+func (f *FeatureFlag) State() FlagLifecycle {
+	if f.Lifecycle == "" {
+		return LifecycleActive
+	}
+	return f.Lifecycle
+}
+
+// Archived reports whether the flag is archived
+func (f *FeatureFlag) Archived() bool {
+	return f.Lifecycle == LifecycleArchived
+}
+
+// ArchiveFeatureFlag archives a feature flag, retiring it ahead of its
+// deletion: evaluations of the flag serve their default value and log a
+// warning, so the code still referencing it shows up before the flag is gone.
+// Archiving an archived flag is not an error.
+func (c *Client) ArchiveFeatureFlag(ctx context.Context, id int, reqOpts ...RequestOption) (*FeatureFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.flagRequest(ctx, request{
+		method: "POST",
+		path:   fmt.Sprintf("/api/v1/feature-flags/%d/archive", id),
+	})
+}
+
+// UnarchiveFeatureFlag makes an archived feature flag active again, so it
+// evaluates as before it was archived
+func (c *Client) UnarchiveFeatureFlag(ctx context.Context, id int, reqOpts ...RequestOption) (*FeatureFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.flagRequest(ctx, request{
+		method: "POST",
+		path:   fmt.Sprintf("/api/v1/feature-flags/%d/unarchive", id),
+	})
+}
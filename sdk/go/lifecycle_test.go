@@ -0,0 +1,103 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveFeatureFlag(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		switch r.URL.Path {
+		case "/api/v1/feature-flags/7/archive":
+			writeJSON(w, FeatureFlag{ID: 7, Name: "checkout", Lifecycle: LifecycleArchived})
+		case "/api/v1/feature-flags/7/unarchive":
+			writeJSON(w, FeatureFlag{ID: 7, Name: "checkout", Lifecycle: LifecycleActive})
+		default:
+			writeJSON(w, []FeatureFlag{{ID: 8, Name: "legacy", Lifecycle: LifecycleDeprecated}})
+		}
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+
+	flag, err := client.ArchiveFeatureFlag(ctx, 7)
+	require.NoError(t, err)
+	assert.True(t, flag.Archived())
+	flag, err = client.UnarchiveFeatureFlag(ctx, 7)
+	require.NoError(t, err)
+	assert.False(t, flag.Archived())
+	assert.Equal(t, LifecycleActive, flag.State())
+
+	flags, err := client.ListFeatureFlags(ctx, FlagFilter{Lifecycle: LifecycleDeprecated})
+	require.NoError(t, err)
+	assert.Equal(t, LifecycleDeprecated, flags[0].State())
+	_, err = client.ListFeatureFlags(ctx, FlagFilter{Lifecycle: "retired"})
+	assert.ErrorContains(t, err, `invalid lifecycle "retired"`)
+	_, err = client.UpdateFeatureFlag(ctx, 7, FeatureFlagUpdate{Lifecycle: "retired"})
+	assert.ErrorContains(t, err, `invalid lifecycle "retired"`)
+
+	assert.Equal(t, []string{
+		"POST /api/v1/feature-flags/7/archive",
+		"POST /api/v1/feature-flags/7/unarchive",
+		"GET /api/v1/feature-flags/?lifecycle=deprecated",
+	}, requests)
+}
+
+func TestEvaluateArchivedFlag(t *testing.T) {
+	handler := &recordingHandler{}
+	client := NewClient("http://localhost", "key", nil, WithLogger(slog.New(handler)))
+	evaluator := NewEvaluator(client, EvaluatorOptions{Environment: "production"})
+	evaluator.SetRuleset(&Ruleset{Environment: "production", Flags: []FeatureFlag{
+		{ID: 1, Name: "checkout", IsActive: true, Lifecycle: LifecycleArchived},
+		{ID: 2, Name: "express", IsActive: true, Prerequisites: []Prerequisite{{Flag: "checkout"}}},
+		{ID: 3, Name: "legacy", IsActive: true, Lifecycle: LifecycleDeprecated},
+	}})
+	user := EvaluationContext{Key: "user-1"}
+
+	d := evaluator.EvaluateBool("checkout", user, false)
+	require.ErrorIs(t, d.Err, ErrFlagArchived)
+	assert.Equal(t, false, d.Value, "an archived flag serves the default value")
+	assert.Equal(t, ErrorFlagArchived, d.ErrorCode)
+	assert.Equal(t, []string{"WARN flag evaluation failed"}, handler.messages())
+
+	d = evaluator.EvaluateBool("express", user, true)
+	assert.NoError(t, d.Err)
+	assert.Equal(t, false, d.Value, "an archived prerequisite is never met")
+	assert.Equal(t, ReasonPrerequisiteFailed, d.Reason)
+
+	assert.Equal(t, true, evaluator.EvaluateBool("legacy", user, false).Value, "deprecated flags evaluate normally")
+}
+
+func TestEvaluateArchivedFlagOnServer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"reason": "ERROR", "error_code": "FLAG_ARCHIVED"})
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil)
+
+	d := client.EvaluateBool(context.Background(), "checkout", EvaluationContext{Key: "user-1"}, true)
+	assert.ErrorIs(t, d.Err, ErrFlagArchived)
+	assert.Equal(t, true, d.Value)
+	assert.Equal(t, ErrorFlagArchived, d.ErrorCode)
+}
+
+func TestCatalogLifecycle(t *testing.T) {
+	entities := NewCatalogEntities([]FeatureFlag{
+		{Name: "checkout", Environment: "production"},
+		{Name: "legacy", Environment: "production", Lifecycle: LifecycleArchived},
+		{Name: "beta", Environment: "production", Lifecycle: LifecycleDeprecated, Metadata: map[string]any{MetadataLifecycle: "experimental"}},
+	}, CatalogOptions{})
+	require.Len(t, entities, 3)
+	assert.Equal(t, "production", entities[0].Spec.Lifecycle)
+	assert.Equal(t, "deprecated", entities[1].Spec.Lifecycle)
+	assert.Equal(t, "experimental", entities[2].Spec.Lifecycle, "the metadata lifecycle takes precedence")
+}
@@ -0,0 +1,345 @@
+// Package lint finds the feature flags referenced by Go source code and
+// cross-references them with the flag inventory of the server, reporting the
+// flags referenced in code but missing on the server and the flags on the
+// server no code uses, so stale flags can be cleaned up. The matrixflag lint
+// command runs it.
+//
+// References are found syntactically: flag keys given as string literals or
+// string constants of the same package to the evaluation methods of the SDK,
+// such as Client.EvaluateBool and Evaluator.IsEnabled, to the methods of the
+// request flags returned by FromContext, to Gate and the Require helpers of the
+// framework adapters, and the keys of packages generated by matrixflag-gen.
+// Keys built at run time are not found, so flags only evaluated that way are
+// reported unused.
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+// Reference is a flag key found in source code
+type Reference struct {
+	Flag string `json:"flag"`
+	// Pos is the position of the key in the source
+	Pos token.Position `json:"pos"`
+}
+
+// evaluationMethods are the methods evaluating a flag by key, taken as the
+// first argument or, after a context.Context, the second one
+var evaluationMethods = map[string]bool{
+	"Evaluate": true, "EvaluateBool": true, "EvaluateString": true, "EvaluateInt": true,
+	"EvaluateFloat": true, "EvaluateJSON": true, "GetVariation": true, "GetJSONVariation": true,
+	"DebugEvaluate": true, "IsEnabled": true,
+}
+
+// requestFlagMethods are the methods of RequestFlags taking a flag key, only
+// matched on request flags returned by FromContext as their names are common
+var requestFlagMethods = map[string]bool{
+	"Bool": true, "String": true, "Int": true, "Float": true, "JSON": true, "Detail": true,
+}
+
+// gateFuncs are the functions of the SDK and its adapters taking a flag key first
+var gateFuncs = map[string]bool{
+	"Gate": true, "Require": true, "RequireStatus": true,
+}
+
+// generatedHeader starts the files generated by matrixflag-gen
+const generatedHeader = "// Code generated by matrixflag-gen"
+
+// Scan parses the Go files matched by patterns and returns the flag references
+// they hold, ordered by position. A pattern is a directory, a directory
+// followed by /... for it and its subdirectories, or a Go file. Directories
+// named vendor or testdata, or starting with . or _, are skipped.
+func Scan(patterns ...string) ([]Reference, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	dirs := make(map[string][]string)
+	for _, pattern := range patterns {
+		if err := addPattern(dirs, pattern); err != nil {
+			return nil, err
+		}
+	}
+	var refs []Reference
+	for _, files := range dirs {
+		pkgRefs, err := scanPackage(files)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, pkgRefs...)
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		a, b := refs[i].Pos, refs[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+	return refs, nil
+}
+
+// addPattern adds the Go files matched by pattern to dirs, grouped by directory
+func addPattern(dirs map[string][]string, pattern string) error {
+	root, recursive := strings.CutSuffix(pattern, "/...")
+	if root == "" || root == "..." {
+		root, recursive = ".", true
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		dirs[filepath.Dir(root)] = appendFile(dirs[filepath.Dir(root)], root)
+		return nil
+	}
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && (!recursive || skipDir(d.Name())) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(path, ".go") {
+			dirs[filepath.Dir(path)] = appendFile(dirs[filepath.Dir(path)], path)
+		}
+		return nil
+	})
+}
+
+// appendFile adds a file to the files of a directory unless a pattern added it before
+func appendFile(files []string, file string) []string {
+	for _, f := range files {
+		if f == file {
+			return files
+		}
+	}
+	return append(files, file)
+}
+
+// skipDir reports whether a directory is left out of recursive patterns, as the go command does
+func skipDir(name string) bool {
+	return name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
+
+// scanPackage returns the flag references of the files of one directory;
+// string constants are resolved across the files
+func scanPackage(files []string) ([]Reference, error) {
+	fset := token.NewFileSet()
+	parsed := make([]*ast.File, 0, len(files))
+	for _, path := range files {
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		parsed = append(parsed, f)
+	}
+	consts := stringConsts(parsed)
+	var refs []Reference
+	for _, f := range parsed {
+		s := &scanner{fset: fset, consts: consts, generated: isGenerated(f)}
+		ast.Inspect(f, s.visit)
+		refs = append(refs, s.refs...)
+	}
+	return refs, nil
+}
+
+// isGenerated reports whether a file was generated by matrixflag-gen
+func isGenerated(f *ast.File) bool {
+	for _, group := range f.Comments {
+		if group.Pos() > f.Package {
+			break
+		}
+		for _, c := range group.List {
+			if strings.HasPrefix(c.Text, generatedHeader) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// stringConsts returns the values of the string constants declared in files, by name
+func stringConsts(files []*ast.File) map[string]string {
+	consts := make(map[string]string)
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for i, name := range vs.Names {
+					if i < len(vs.Values) {
+						if value, ok := stringLiteral(vs.Values[i]); ok {
+							consts[name.Name] = value
+						}
+					}
+				}
+			}
+		}
+	}
+	return consts
+}
+
+// stringLiteral returns the value of a string literal
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
+
+// scanner collects the flag references of a file
+type scanner struct {
+	fset      *token.FileSet
+	consts    map[string]string
+	generated bool
+	// requestFlags holds the variables assigned the result of FromContext
+	requestFlags map[string]bool
+	refs         []Reference
+}
+
+func (s *scanner) visit(n ast.Node) bool {
+	switch n := n.(type) {
+	case *ast.AssignStmt:
+		if len(n.Lhs) == 1 && len(n.Rhs) == 1 && isFromContext(n.Rhs[0]) {
+			if id, ok := n.Lhs[0].(*ast.Ident); ok {
+				if s.requestFlags == nil {
+					s.requestFlags = make(map[string]bool)
+				}
+				s.requestFlags[id.Name] = true
+			}
+		}
+	case *ast.CompositeLit:
+		// Generated accessors hold their key in a Key field
+		if !s.generated {
+			break
+		}
+		for _, elt := range n.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "Key" {
+					s.add(kv.Value)
+				}
+			}
+		}
+	case *ast.CallExpr:
+		s.call(n)
+	}
+	return true
+}
+
+// call records the flag key passed to a call of the SDK
+func (s *scanner) call(call *ast.CallExpr) {
+	var name string
+	var recv ast.Expr
+	switch fn := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		name, recv = fn.Sel.Name, fn.X
+	case *ast.Ident:
+		name = fn.Name
+	default:
+		return
+	}
+	switch {
+	case evaluationMethods[name] && recv != nil:
+		// The key follows the context of Client methods and comes first for an Evaluator
+		for i := 0; i < 2 && i < len(call.Args); i++ {
+			if s.add(call.Args[i]) {
+				return
+			}
+		}
+	case requestFlagMethods[name] && recv != nil && s.isRequestFlags(recv):
+		if len(call.Args) > 0 {
+			s.add(call.Args[0])
+		}
+	case gateFuncs[name]:
+		if len(call.Args) > 0 {
+			s.add(call.Args[0])
+		}
+	}
+}
+
+// isRequestFlags reports whether an expression holds request flags: a call of
+// FromContext or a variable assigned one
+func (s *scanner) isRequestFlags(expr ast.Expr) bool {
+	if id, ok := expr.(*ast.Ident); ok {
+		return s.requestFlags[id.Name]
+	}
+	return isFromContext(expr)
+}
+
+// isFromContext reports whether an expression calls a FromContext function
+func isFromContext(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	switch fn := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		return fn.Sel.Name == "FromContext"
+	case *ast.Ident:
+		return fn.Name == "FromContext"
+	}
+	return false
+}
+
+// add records a flag key given as a string literal or constant, reporting whether it was one
+func (s *scanner) add(expr ast.Expr) bool {
+	key, ok := stringLiteral(expr)
+	if id, isIdent := expr.(*ast.Ident); isIdent {
+		key, ok = s.consts[id.Name]
+	}
+	if !ok || key == "" {
+		return false
+	}
+	s.refs = append(s.refs, Reference{Flag: key, Pos: s.fset.Position(expr.Pos())})
+	return true
+}
+
+// Report cross-references the flag references of code with the flags of the server
+type Report struct {
+	// Missing are the references to flags missing on the server
+	Missing []Reference `json:"missing"`
+	// Unused are the flags on the server no code references
+	Unused []matrixflag.FeatureFlag `json:"unused"`
+}
+
+// Check cross-references flag references with the flags of the server, listed
+// for example with Client.ListFeatureFlags
+func Check(refs []Reference, flags []matrixflag.FeatureFlag) *Report {
+	onServer := make(map[string]bool, len(flags))
+	for _, flag := range flags {
+		onServer[flag.Name] = true
+	}
+	referenced := make(map[string]bool, len(refs))
+	report := &Report{Missing: []Reference{}, Unused: []matrixflag.FeatureFlag{}}
+	for _, ref := range refs {
+		referenced[ref.Flag] = true
+		if !onServer[ref.Flag] {
+			report.Missing = append(report.Missing, ref)
+		}
+	}
+	for _, flag := range flags {
+		if !referenced[flag.Name] {
+			report.Unused = append(report.Unused, flag)
+		}
+	}
+	return report
+}
@@ -0,0 +1,124 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFiles writes source files under a temporary directory and returns it
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(src), 0o600))
+	}
+	return dir
+}
+
+// flagsOf returns the flag keys of references
+func flagsOf(refs []Reference) []string {
+	flags := make([]string, len(refs))
+	for i, ref := range refs {
+		flags[i] = ref.Flag
+	}
+	return flags
+}
+
+func TestScan(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"app/handler.go": `package app
+
+import (
+	"flag"
+	"net/http"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/matrixflag/sdk/contrib/ginflag"
+)
+
+const searchFlag = "new-search"
+
+func handle(client *matrixflag.Client, ev *matrixflag.Evaluator, r *http.Request, key string) {
+	client.EvaluateBool(r.Context(), "new-checkout", matrixflag.EvaluationContext{}, false)
+	client.EvaluateString(r.Context(), searchFlag, matrixflag.EvaluationContext{}, "")
+	ev.IsEnabled(pricingFlag, matrixflag.EvaluationContext{})
+	client.EvaluateBool(r.Context(), key, matrixflag.EvaluationContext{}, false)
+
+	rf := matrixflag.FromContext(r.Context())
+	rf.Int("page-size")
+	matrixflag.FromContext(r.Context()).JSON("banner")
+	flag.Bool("verbose", false, "not a feature flag")
+
+	http.Handle("/beta", matrixflag.Gate("beta", nil, nil))
+	ginflag.Require("admin-panel")
+}
+`,
+		"app/consts.go": `package app
+
+const pricingFlag = "dynamic-pricing"
+`,
+		"app/flags/flags_gen.go": `// Code generated by matrixflag-gen from environment production. DO NOT EDIT.
+
+package flags
+
+var DarkMode = BoolFlag{Key: "dark-mode"}
+`,
+		"app/config.go": `package app
+
+type option struct{ Key string }
+
+var opt = option{Key: "not-a-flag"}
+`,
+		"vendor/lib/lib.go": `package lib
+
+func f(client interface{ IsEnabled(string, any) bool }) { client.IsEnabled("vendored", nil) }
+`,
+	})
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	refs, err := Scan("./...")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dark-mode", "new-checkout", "new-search", "dynamic-pricing", "page-size", "banner", "beta", "admin-panel"}, flagsOf(refs))
+	assert.Equal(t, filepath.Join("app", "handler.go"), refs[1].Pos.Filename)
+	assert.Equal(t, 14, refs[1].Pos.Line)
+
+	refs, err = Scan("app")
+	require.NoError(t, err)
+	assert.NotContains(t, flagsOf(refs), "dark-mode", "a directory without /... leaves out its subdirectories")
+
+	refs, err = Scan("app/flags/flags_gen.go", "app/flags")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dark-mode"}, flagsOf(refs), "files matched twice are scanned once")
+
+	_, err = Scan("missing")
+	assert.Error(t, err)
+}
+
+func TestScanSyntaxError(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"broken.go": "package broken\n\nfunc {"})
+	_, err := Scan(dir)
+	assert.ErrorContains(t, err, "failed to parse")
+}
+
+func TestCheck(t *testing.T) {
+	refs := []Reference{{Flag: "new-checkout"}, {Flag: "new-chekout"}, {Flag: "new-checkout"}}
+	report := Check(refs, []matrixflag.FeatureFlag{
+		{ID: 1, Name: "new-checkout", Environment: "production"},
+		{ID: 2, Name: "legacy-banner", Environment: "production"},
+	})
+	assert.Equal(t, []Reference{{Flag: "new-chekout"}}, report.Missing)
+	assert.Equal(t, []matrixflag.FeatureFlag{{ID: 2, Name: "legacy-banner", Environment: "production"}}, report.Unused)
+
+	report = Check(nil, nil)
+	assert.Empty(t, report.Missing)
+	assert.NotNil(t, report.Unused, "empty findings encode as empty lists")
+}
@@ -0,0 +1,36 @@
+package matrixflag
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WithLogger sets the structured logger of the client. The SDK logs retries
+// and stream reconnects at Warn level, ruleset syncs at Debug level and sync
+// and evaluation failures at Warn or Error level. Nothing is logged by default.
+//
+//	client := matrixflag.New(apiKey, matrixflag.WithLogger(slog.Default().With("component", "flags")))
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// Logger returns the logger of the client, which discards everything unless set with WithLogger
+func (c *Client) Logger() *slog.Logger {
+	if c.logger == nil {
+		return discardLogger
+	}
+	return c.logger
+}
+
+// discardLogger is the logger of clients without one
+var discardLogger = slog.New(discardHandler{})
+
+// discardHandler is a slog.Handler discarding every record
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
@@ -0,0 +1,91 @@
+package matrixflag
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHandler keeps the records it handles
+type recordingHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *recordingHandler) WithGroup(string) slog.Handler      { return h }
+
+// messages returns the level and message of each record
+func (h *recordingHandler) messages() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	msgs := make([]string, len(h.records))
+	for i, r := range h.records {
+		msgs[i] = r.Level.String() + " " + r.Message
+	}
+	return msgs
+}
+
+func TestLoggerDiscardsByDefault(t *testing.T) {
+	client := New("key")
+	assert.False(t, client.Logger().Enabled(context.Background(), slog.LevelError))
+}
+
+func TestLogRetries(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	handler := &recordingHandler{}
+	client := New("key", WithBaseURL(srv.URL), WithRetries(2, time.Millisecond, time.Second),
+		WithClock(fixedClock{time.Now()}), WithLogger(slog.New(handler)))
+	_, err := client.ListFeatureFlags(context.Background(), FlagFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"WARN retrying request"}, handler.messages())
+
+	attrs := map[string]slog.Value{}
+	handler.records[0].Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value
+		return true
+	})
+	assert.Equal(t, int64(http.StatusServiceUnavailable), attrs["status"].Int64())
+	assert.Equal(t, int64(1), attrs["attempt"].Int64())
+	assert.Equal(t, http.MethodGet, attrs["method"].String())
+}
+
+func TestLogEvaluationErrors(t *testing.T) {
+	handler := &recordingHandler{}
+	client := New("key", WithLogger(slog.New(handler)))
+	evaluator := NewEvaluator(client, EvaluatorOptions{Environment: "production"})
+	evaluator.SetRuleset(&Ruleset{Environment: "production", Flags: []FeatureFlag{
+		{ID: 1, Name: "checkout", Environment: "production", IsActive: true},
+	}})
+
+	evaluator.EvaluateBool("checkout", EvaluationContext{Key: "user-1"}, false)
+	assert.Empty(t, handler.messages(), "successful evaluations are not logged")
+	evaluator.EvaluateBool("search", EvaluationContext{Key: "user-1"}, false)
+	assert.Equal(t, []string{"WARN flag evaluation failed"}, handler.messages())
+}
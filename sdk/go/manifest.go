@@ -0,0 +1,190 @@
+package matrixflag
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest API versions and kinds
+const (
+	ManifestAPIVersionV1 = "matrixflag.io/v1"
+	ManifestKind         = "FlagManifest"
+)
+
+// ManifestSchemaV1 is the JSON schema of the v1 manifest format
+//
+//go:embed schema/manifest.v1.json
+var ManifestSchemaV1 []byte
+
+// flagNamePattern matches valid flag names
+var flagNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// maxFlagNameLength is the longest flag name accepted by the API
+const maxFlagNameLength = 128
+
+// Manifest represents a declarative flag definition file
+type Manifest struct {
+	APIVersion  string     `json:"apiVersion" yaml:"apiVersion"`
+	Kind        string     `json:"kind" yaml:"kind"`
+	Environment string     `json:"environment,omitempty" yaml:"environment,omitempty"`
+	Flags       []FlagSpec `json:"flags,omitempty" yaml:"flags,omitempty"`
+	// Segments are shared by all environments
+	Segments []SegmentSpec `json:"segments,omitempty" yaml:"segments,omitempty"`
+}
+
+// ManifestProblem describes a single validation problem in a manifest
+type ManifestProblem struct {
+	Path    string
+	Message string
+}
+
+// ManifestError is returned when a manifest fails validation
+type ManifestError struct {
+	Problems []ManifestProblem
+}
+
+func (e *ManifestError) Error() string {
+	msgs := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		msgs[i] = p.Path + ": " + p.Message
+	}
+	return "invalid manifest: " + strings.Join(msgs, "; ")
+}
+
+// LoadManifest decodes and validates a YAML or JSON manifest.
+// Unknown fields are rejected so that typos do not go unnoticed.
+func LoadManifest(r io.Reader) (*Manifest, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	var m Manifest
+	if err := dec.Decode(&m); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to decode manifest: empty document")
+		}
+		return nil, fmt.Errorf("failed to decode manifest: %w", err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// LoadManifestFile loads and validates a manifest from a file
+func LoadManifestFile(path string) (*Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open manifest: %w", err)
+	}
+	defer f.Close()
+
+	m, err := LoadManifest(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// Validate checks the manifest against the schema rules and returns a *ManifestError listing every problem
+func (m *Manifest) Validate() error {
+	var problems []ManifestProblem
+	add := func(path, format string, args ...any) {
+		problems = append(problems, ManifestProblem{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch m.APIVersion {
+	case ManifestAPIVersionV1:
+	case "":
+		add("apiVersion", "is required")
+	default:
+		add("apiVersion", "unsupported version %q (supported: %s)", m.APIVersion, ManifestAPIVersionV1)
+	}
+	if m.Kind != ManifestKind {
+		add("kind", "must be %q", ManifestKind)
+	}
+
+	seen := make(map[flagKey]int, len(m.Flags))
+	for i, flag := range m.Flags {
+		path := fmt.Sprintf("flags[%d]", i)
+		switch {
+		case flag.Name == "":
+			add(path+".name", "is required")
+		case len(flag.Name) > maxFlagNameLength:
+			add(path+".name", "must be at most %d characters", maxFlagNameLength)
+		case !flagNamePattern.MatchString(flag.Name):
+			add(path+".name", "%q must start with a letter or digit and contain only letters, digits, '.', '_' and '-'", flag.Name)
+		}
+
+		env := flag.Environment
+		if env == "" {
+			env = m.Environment
+		}
+		if env == "" {
+			add(path+".environment", "is required when the manifest has no default environment")
+		}
+		if flag.ProjectID < 0 {
+			add(path+".project_id", "must not be negative")
+		}
+
+		key := flagKey{env, flag.Name}
+		if j, ok := seen[key]; ok && flag.Name != "" {
+			add(path, "duplicates flags[%d] (%s/%s)", j, env, flag.Name)
+		} else {
+			seen[key] = i
+		}
+	}
+
+	segments := make(map[string]int, len(m.Segments))
+	for i, segment := range m.Segments {
+		path := fmt.Sprintf("segments[%d]", i)
+		if segment.Name == "" {
+			add(path+".name", "is required")
+		} else if j, ok := segments[segment.Name]; ok {
+			add(path, "duplicates segments[%d] (%s)", j, segment.Name)
+		} else {
+			segments[segment.Name] = i
+		}
+		for j, rule := range segment.Rules {
+			if len(rule.Conditions) == 0 {
+				add(fmt.Sprintf("%s.rules[%d].conditions", path, j), "must not be empty")
+			}
+			for k, cond := range rule.Conditions {
+				if err := cond.Validate(); err != nil {
+					add(fmt.Sprintf("%s.rules[%d].conditions[%d]", path, j, k), "%v", err)
+				}
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ManifestError{Problems: problems}
+	}
+	return nil
+}
+
+// DesiredState converts the manifest into a desired state for Apply,
+// filling in the manifest's default environment. Segments are pruned only
+// when the manifest has a segments list, which may be empty.
+func (m *Manifest) DesiredState() DesiredState {
+	flags := make([]FlagSpec, len(m.Flags))
+	for i, flag := range m.Flags {
+		if flag.Environment == "" {
+			flag.Environment = m.Environment
+		}
+		flags[i] = flag
+	}
+	return DesiredState{Flags: flags, Segments: m.Segments}
+}
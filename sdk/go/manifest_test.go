@@ -0,0 +1,78 @@
+package matrixflag
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadManifestSegments(t *testing.T) {
+	m, err := LoadManifest(strings.NewReader(`
+apiVersion: matrixflag.io/v1
+kind: FlagManifest
+environment: production
+flags:
+  - name: new-checkout
+segments:
+  - name: beta-testers
+    included: [user-1]
+    rules:
+      - conditions:
+          - attribute: organization.plan
+            operator: in
+            value: [enterprise]
+`))
+	require.NoError(t, err)
+	require.Len(t, m.Segments, 1)
+	segment := m.Segments[0]
+	assert.Equal(t, []string{"user-1"}, segment.Included)
+	require.Len(t, segment.Rules, 1)
+	assert.Equal(t, TargetingCondition{Attribute: "organization.plan", Operator: OpIn, Value: []any{"enterprise"}}, segment.Rules[0].Conditions[0])
+
+	state := m.DesiredState()
+	assert.Equal(t, m.Segments, state.Segments)
+	assert.Equal(t, "production", state.Flags[0].Environment)
+}
+
+func TestLoadManifestSegmentProblems(t *testing.T) {
+	_, err := LoadManifest(strings.NewReader(`
+apiVersion: matrixflag.io/v1
+kind: FlagManifest
+segments:
+  - name: beta
+  - name: beta
+  - description: no name
+  - name: ruled
+    rules:
+      - conditions: []
+      - conditions:
+          - attribute: plan
+            operator: like
+`))
+	var manifestErr *ManifestError
+	require.True(t, errors.As(err, &manifestErr), err)
+	var paths []string
+	for _, p := range manifestErr.Problems {
+		paths = append(paths, p.Path)
+	}
+	assert.Equal(t, []string{
+		"segments[1]",
+		"segments[2].name",
+		"segments[3].rules[0].conditions",
+		"segments[3].rules[1].conditions[0]",
+	}, paths)
+}
+
+func TestLoadManifestRejectsUnknownSegmentFields(t *testing.T) {
+	_, err := LoadManifest(strings.NewReader(`
+apiVersion: matrixflag.io/v1
+kind: FlagManifest
+segments:
+  - name: beta
+    include: [user-1]
+`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,3331 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../api.go
+//
+// Generated by this command:
+//
+//	mockgen -source=../api.go -destination=api_mock.go -package=matrixflagmock -write_package_comment=false
+package matrixflagmock
+
+import (
+	context "context"
+	slog "log/slog"
+	reflect "reflect"
+	time "time"
+
+	sdk "github.com/matrixflag/sdk"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFlagEvaluator is a mock of FlagEvaluator interface.
+type MockFlagEvaluator struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlagEvaluatorMockRecorder
+}
+
+// MockFlagEvaluatorMockRecorder is the mock recorder for MockFlagEvaluator.
+type MockFlagEvaluatorMockRecorder struct {
+	mock *MockFlagEvaluator
+}
+
+// NewMockFlagEvaluator creates a new mock instance.
+func NewMockFlagEvaluator(ctrl *gomock.Controller) *MockFlagEvaluator {
+	mock := &MockFlagEvaluator{ctrl: ctrl}
+	mock.recorder = &MockFlagEvaluatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFlagEvaluator) EXPECT() *MockFlagEvaluatorMockRecorder {
+	return m.recorder
+}
+
+// Evaluate mocks base method.
+func (m *MockFlagEvaluator) Evaluate(ctx context.Context, flagKey string, evalCtx sdk.EvaluationContext, defaultValue any, reqOpts ...sdk.RequestOption) sdk.EvaluationDetail[any] {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagKey, evalCtx, defaultValue}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Evaluate", varargs...)
+	ret0, _ := ret[0].(sdk.EvaluationDetail[any])
+	return ret0
+}
+
+// Evaluate indicates an expected call of Evaluate.
+func (mr *MockFlagEvaluatorMockRecorder) Evaluate(ctx, flagKey, evalCtx, defaultValue any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagKey, evalCtx, defaultValue}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Evaluate", reflect.TypeOf((*MockFlagEvaluator)(nil).Evaluate), varargs...)
+}
+
+// EvaluateBool mocks base method.
+func (m *MockFlagEvaluator) EvaluateBool(ctx context.Context, flagKey string, evalCtx sdk.EvaluationContext, defaultValue bool, reqOpts ...sdk.RequestOption) sdk.EvaluationDetail[bool] {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagKey, evalCtx, defaultValue}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "EvaluateBool", varargs...)
+	ret0, _ := ret[0].(sdk.EvaluationDetail[bool])
+	return ret0
+}
+
+// EvaluateBool indicates an expected call of EvaluateBool.
+func (mr *MockFlagEvaluatorMockRecorder) EvaluateBool(ctx, flagKey, evalCtx, defaultValue any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagKey, evalCtx, defaultValue}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvaluateBool", reflect.TypeOf((*MockFlagEvaluator)(nil).EvaluateBool), varargs...)
+}
+
+// EvaluateFloat mocks base method.
+func (m *MockFlagEvaluator) EvaluateFloat(ctx context.Context, flagKey string, evalCtx sdk.EvaluationContext, defaultValue float64, reqOpts ...sdk.RequestOption) sdk.EvaluationDetail[float64] {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagKey, evalCtx, defaultValue}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "EvaluateFloat", varargs...)
+	ret0, _ := ret[0].(sdk.EvaluationDetail[float64])
+	return ret0
+}
+
+// EvaluateFloat indicates an expected call of EvaluateFloat.
+func (mr *MockFlagEvaluatorMockRecorder) EvaluateFloat(ctx, flagKey, evalCtx, defaultValue any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagKey, evalCtx, defaultValue}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvaluateFloat", reflect.TypeOf((*MockFlagEvaluator)(nil).EvaluateFloat), varargs...)
+}
+
+// EvaluateInt mocks base method.
+func (m *MockFlagEvaluator) EvaluateInt(ctx context.Context, flagKey string, evalCtx sdk.EvaluationContext, defaultValue int, reqOpts ...sdk.RequestOption) sdk.EvaluationDetail[int] {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagKey, evalCtx, defaultValue}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "EvaluateInt", varargs...)
+	ret0, _ := ret[0].(sdk.EvaluationDetail[int])
+	return ret0
+}
+
+// EvaluateInt indicates an expected call of EvaluateInt.
+func (mr *MockFlagEvaluatorMockRecorder) EvaluateInt(ctx, flagKey, evalCtx, defaultValue any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagKey, evalCtx, defaultValue}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvaluateInt", reflect.TypeOf((*MockFlagEvaluator)(nil).EvaluateInt), varargs...)
+}
+
+// EvaluateJSON mocks base method.
+func (m *MockFlagEvaluator) EvaluateJSON(ctx context.Context, flagKey string, evalCtx sdk.EvaluationContext, defaultValue any, reqOpts ...sdk.RequestOption) sdk.EvaluationDetail[any] {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagKey, evalCtx, defaultValue}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "EvaluateJSON", varargs...)
+	ret0, _ := ret[0].(sdk.EvaluationDetail[any])
+	return ret0
+}
+
+// EvaluateJSON indicates an expected call of EvaluateJSON.
+func (mr *MockFlagEvaluatorMockRecorder) EvaluateJSON(ctx, flagKey, evalCtx, defaultValue any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagKey, evalCtx, defaultValue}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvaluateJSON", reflect.TypeOf((*MockFlagEvaluator)(nil).EvaluateJSON), varargs...)
+}
+
+// EvaluateString mocks base method.
+func (m *MockFlagEvaluator) EvaluateString(ctx context.Context, flagKey string, evalCtx sdk.EvaluationContext, defaultValue string, reqOpts ...sdk.RequestOption) sdk.EvaluationDetail[string] {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagKey, evalCtx, defaultValue}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "EvaluateString", varargs...)
+	ret0, _ := ret[0].(sdk.EvaluationDetail[string])
+	return ret0
+}
+
+// EvaluateString indicates an expected call of EvaluateString.
+func (mr *MockFlagEvaluatorMockRecorder) EvaluateString(ctx, flagKey, evalCtx, defaultValue any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagKey, evalCtx, defaultValue}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvaluateString", reflect.TypeOf((*MockFlagEvaluator)(nil).EvaluateString), varargs...)
+}
+
+// GetJSONVariation mocks base method.
+func (m *MockFlagEvaluator) GetJSONVariation(ctx context.Context, flagKey string, evalCtx sdk.EvaluationContext, target any, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagKey, evalCtx, target}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetJSONVariation", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetJSONVariation indicates an expected call of GetJSONVariation.
+func (mr *MockFlagEvaluatorMockRecorder) GetJSONVariation(ctx, flagKey, evalCtx, target any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagKey, evalCtx, target}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJSONVariation", reflect.TypeOf((*MockFlagEvaluator)(nil).GetJSONVariation), varargs...)
+}
+
+// GetVariation mocks base method.
+func (m *MockFlagEvaluator) GetVariation(ctx context.Context, flagKey string, evalCtx sdk.EvaluationContext, reqOpts ...sdk.RequestOption) (*sdk.Variation, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagKey, evalCtx}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetVariation", varargs...)
+	ret0, _ := ret[0].(*sdk.Variation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVariation indicates an expected call of GetVariation.
+func (mr *MockFlagEvaluatorMockRecorder) GetVariation(ctx, flagKey, evalCtx any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagKey, evalCtx}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVariation", reflect.TypeOf((*MockFlagEvaluator)(nil).GetVariation), varargs...)
+}
+
+// Track mocks base method.
+func (m *MockFlagEvaluator) Track(ctx context.Context, eventName string, evalCtx sdk.EvaluationContext, value float64, properties map[string]any, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, eventName, evalCtx, value, properties}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Track", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Track indicates an expected call of Track.
+func (mr *MockFlagEvaluatorMockRecorder) Track(ctx, eventName, evalCtx, value, properties any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, eventName, evalCtx, value, properties}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Track", reflect.TypeOf((*MockFlagEvaluator)(nil).Track), varargs...)
+}
+
+// MockFlagManager is a mock of FlagManager interface.
+type MockFlagManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlagManagerMockRecorder
+}
+
+// MockFlagManagerMockRecorder is the mock recorder for MockFlagManager.
+type MockFlagManagerMockRecorder struct {
+	mock *MockFlagManager
+}
+
+// NewMockFlagManager creates a new mock instance.
+func NewMockFlagManager(ctrl *gomock.Controller) *MockFlagManager {
+	mock := &MockFlagManager{ctrl: ctrl}
+	mock.recorder = &MockFlagManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFlagManager) EXPECT() *MockFlagManagerMockRecorder {
+	return m.recorder
+}
+
+// AddTag mocks base method.
+func (m *MockFlagManager) AddTag(ctx context.Context, id int, tag string, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id, tag}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddTag", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddTag indicates an expected call of AddTag.
+func (mr *MockFlagManagerMockRecorder) AddTag(ctx, id, tag any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id, tag}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTag", reflect.TypeOf((*MockFlagManager)(nil).AddTag), varargs...)
+}
+
+// ArchiveFeatureFlag mocks base method.
+func (m *MockFlagManager) ArchiveFeatureFlag(ctx context.Context, id int, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ArchiveFeatureFlag", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveFeatureFlag indicates an expected call of ArchiveFeatureFlag.
+func (mr *MockFlagManagerMockRecorder) ArchiveFeatureFlag(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveFeatureFlag", reflect.TypeOf((*MockFlagManager)(nil).ArchiveFeatureFlag), varargs...)
+}
+
+// BulkCreateFlags mocks base method.
+func (m *MockFlagManager) BulkCreateFlags(ctx context.Context, flags []sdk.FeatureFlagCreate, reqOpts ...sdk.RequestOption) ([]sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flags}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BulkCreateFlags", varargs...)
+	ret0, _ := ret[0].([]sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkCreateFlags indicates an expected call of BulkCreateFlags.
+func (mr *MockFlagManagerMockRecorder) BulkCreateFlags(ctx, flags any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flags}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkCreateFlags", reflect.TypeOf((*MockFlagManager)(nil).BulkCreateFlags), varargs...)
+}
+
+// BulkToggle mocks base method.
+func (m *MockFlagManager) BulkToggle(ctx context.Context, ids []int, active bool, reqOpts ...sdk.RequestOption) ([]sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, ids, active}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BulkToggle", varargs...)
+	ret0, _ := ret[0].([]sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkToggle indicates an expected call of BulkToggle.
+func (mr *MockFlagManagerMockRecorder) BulkToggle(ctx, ids, active any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, ids, active}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkToggle", reflect.TypeOf((*MockFlagManager)(nil).BulkToggle), varargs...)
+}
+
+// BulkUpdateFlags mocks base method.
+func (m *MockFlagManager) BulkUpdateFlags(ctx context.Context, updates []sdk.BulkUpdate, reqOpts ...sdk.RequestOption) ([]sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, updates}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BulkUpdateFlags", varargs...)
+	ret0, _ := ret[0].([]sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkUpdateFlags indicates an expected call of BulkUpdateFlags.
+func (mr *MockFlagManagerMockRecorder) BulkUpdateFlags(ctx, updates any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, updates}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdateFlags", reflect.TypeOf((*MockFlagManager)(nil).BulkUpdateFlags), varargs...)
+}
+
+// CreateFeatureFlag mocks base method.
+func (m *MockFlagManager) CreateFeatureFlag(ctx context.Context, flag sdk.FeatureFlagCreate, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flag}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateFeatureFlag", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFeatureFlag indicates an expected call of CreateFeatureFlag.
+func (mr *MockFlagManagerMockRecorder) CreateFeatureFlag(ctx, flag any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flag}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFeatureFlag", reflect.TypeOf((*MockFlagManager)(nil).CreateFeatureFlag), varargs...)
+}
+
+// DeleteFeatureFlag mocks base method.
+func (m *MockFlagManager) DeleteFeatureFlag(ctx context.Context, id int, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteFeatureFlag", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFeatureFlag indicates an expected call of DeleteFeatureFlag.
+func (mr *MockFlagManagerMockRecorder) DeleteFeatureFlag(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFeatureFlag", reflect.TypeOf((*MockFlagManager)(nil).DeleteFeatureFlag), varargs...)
+}
+
+// GetFeatureFlag mocks base method.
+func (m *MockFlagManager) GetFeatureFlag(ctx context.Context, id int, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetFeatureFlag", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeatureFlag indicates an expected call of GetFeatureFlag.
+func (mr *MockFlagManagerMockRecorder) GetFeatureFlag(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeatureFlag", reflect.TypeOf((*MockFlagManager)(nil).GetFeatureFlag), varargs...)
+}
+
+// GetFeatureFlagByName mocks base method.
+func (m *MockFlagManager) GetFeatureFlagByName(ctx context.Context, name, environment string, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, name, environment}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetFeatureFlagByName", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeatureFlagByName indicates an expected call of GetFeatureFlagByName.
+func (mr *MockFlagManagerMockRecorder) GetFeatureFlagByName(ctx, name, environment any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, name, environment}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeatureFlagByName", reflect.TypeOf((*MockFlagManager)(nil).GetFeatureFlagByName), varargs...)
+}
+
+// IterateFeatureFlags mocks base method.
+func (m *MockFlagManager) IterateFeatureFlags(opts sdk.ListOptions) *sdk.FlagIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IterateFeatureFlags", opts)
+	ret0, _ := ret[0].(*sdk.FlagIterator)
+	return ret0
+}
+
+// IterateFeatureFlags indicates an expected call of IterateFeatureFlags.
+func (mr *MockFlagManagerMockRecorder) IterateFeatureFlags(opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterateFeatureFlags", reflect.TypeOf((*MockFlagManager)(nil).IterateFeatureFlags), opts)
+}
+
+// ListAll mocks base method.
+func (m *MockFlagManager) ListAll(ctx context.Context, opts sdk.ListOptions, reqOpts ...sdk.RequestOption) ([]sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, opts}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListAll", varargs...)
+	ret0, _ := ret[0].([]sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAll indicates an expected call of ListAll.
+func (mr *MockFlagManagerMockRecorder) ListAll(ctx, opts any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, opts}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockFlagManager)(nil).ListAll), varargs...)
+}
+
+// ListFeatureFlags mocks base method.
+func (m *MockFlagManager) ListFeatureFlags(ctx context.Context, filter sdk.FlagFilter, reqOpts ...sdk.RequestOption) ([]sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, filter}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListFeatureFlags", varargs...)
+	ret0, _ := ret[0].([]sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFeatureFlags indicates an expected call of ListFeatureFlags.
+func (mr *MockFlagManagerMockRecorder) ListFeatureFlags(ctx, filter any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, filter}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFeatureFlags", reflect.TypeOf((*MockFlagManager)(nil).ListFeatureFlags), varargs...)
+}
+
+// PatchFeatureFlag mocks base method.
+func (m *MockFlagManager) PatchFeatureFlag(ctx context.Context, id int, update sdk.FeatureFlagUpdate, mask sdk.FieldMask, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id, update, mask}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PatchFeatureFlag", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchFeatureFlag indicates an expected call of PatchFeatureFlag.
+func (mr *MockFlagManagerMockRecorder) PatchFeatureFlag(ctx, id, update, mask any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id, update, mask}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchFeatureFlag", reflect.TypeOf((*MockFlagManager)(nil).PatchFeatureFlag), varargs...)
+}
+
+// RemoveTag mocks base method.
+func (m *MockFlagManager) RemoveTag(ctx context.Context, id int, tag string, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id, tag}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RemoveTag", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveTag indicates an expected call of RemoveTag.
+func (mr *MockFlagManagerMockRecorder) RemoveTag(ctx, id, tag any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id, tag}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTag", reflect.TypeOf((*MockFlagManager)(nil).RemoveTag), varargs...)
+}
+
+// ToggleFeatureFlag mocks base method.
+func (m *MockFlagManager) ToggleFeatureFlag(ctx context.Context, id int, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ToggleFeatureFlag", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ToggleFeatureFlag indicates an expected call of ToggleFeatureFlag.
+func (mr *MockFlagManagerMockRecorder) ToggleFeatureFlag(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToggleFeatureFlag", reflect.TypeOf((*MockFlagManager)(nil).ToggleFeatureFlag), varargs...)
+}
+
+// UnarchiveFeatureFlag mocks base method.
+func (m *MockFlagManager) UnarchiveFeatureFlag(ctx context.Context, id int, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UnarchiveFeatureFlag", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnarchiveFeatureFlag indicates an expected call of UnarchiveFeatureFlag.
+func (mr *MockFlagManagerMockRecorder) UnarchiveFeatureFlag(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnarchiveFeatureFlag", reflect.TypeOf((*MockFlagManager)(nil).UnarchiveFeatureFlag), varargs...)
+}
+
+// UpdateFeatureFlag mocks base method.
+func (m *MockFlagManager) UpdateFeatureFlag(ctx context.Context, id int, flag sdk.FeatureFlagUpdate, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id, flag}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateFeatureFlag", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFeatureFlag indicates an expected call of UpdateFeatureFlag.
+func (mr *MockFlagManagerMockRecorder) UpdateFeatureFlag(ctx, id, flag any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id, flag}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFeatureFlag", reflect.TypeOf((*MockFlagManager)(nil).UpdateFeatureFlag), varargs...)
+}
+
+// MockAPI is a mock of API interface.
+type MockAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIMockRecorder
+}
+
+// MockAPIMockRecorder is the mock recorder for MockAPI.
+type MockAPIMockRecorder struct {
+	mock *MockAPI
+}
+
+// NewMockAPI creates a new mock instance.
+func NewMockAPI(ctrl *gomock.Controller) *MockAPI {
+	mock := &MockAPI{ctrl: ctrl}
+	mock.recorder = &MockAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPI) EXPECT() *MockAPIMockRecorder {
+	return m.recorder
+}
+
+// AddTag mocks base method.
+func (m *MockAPI) AddTag(ctx context.Context, id int, tag string, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id, tag}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddTag", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddTag indicates an expected call of AddTag.
+func (mr *MockAPIMockRecorder) AddTag(ctx, id, tag any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id, tag}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTag", reflect.TypeOf((*MockAPI)(nil).AddTag), varargs...)
+}
+
+// AddWebhook mocks base method.
+func (m *MockAPI) AddWebhook(ctx context.Context, url string, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, url}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddWebhook", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddWebhook indicates an expected call of AddWebhook.
+func (mr *MockAPIMockRecorder) AddWebhook(ctx, url any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, url}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWebhook", reflect.TypeOf((*MockAPI)(nil).AddWebhook), varargs...)
+}
+
+// AdvanceRelease mocks base method.
+func (m *MockAPI) AdvanceRelease(ctx context.Context, pipelineID int, flag string, reqOpts ...sdk.RequestOption) (*sdk.FlagRelease, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, pipelineID, flag}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AdvanceRelease", varargs...)
+	ret0, _ := ret[0].(*sdk.FlagRelease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdvanceRelease indicates an expected call of AdvanceRelease.
+func (mr *MockAPIMockRecorder) AdvanceRelease(ctx, pipelineID, flag any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, pipelineID, flag}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceRelease", reflect.TypeOf((*MockAPI)(nil).AdvanceRelease), varargs...)
+}
+
+// AllFlagsState mocks base method.
+func (m *MockAPI) AllFlagsState(ctx context.Context, evalCtx sdk.EvaluationContext, opts sdk.FlagsStateOptions, reqOpts ...sdk.RequestOption) (sdk.FlagsState, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, evalCtx, opts}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AllFlagsState", varargs...)
+	ret0, _ := ret[0].(sdk.FlagsState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AllFlagsState indicates an expected call of AllFlagsState.
+func (mr *MockAPIMockRecorder) AllFlagsState(ctx, evalCtx, opts any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, evalCtx, opts}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllFlagsState", reflect.TypeOf((*MockAPI)(nil).AllFlagsState), varargs...)
+}
+
+// Apply mocks base method.
+func (m *MockAPI) Apply(ctx context.Context, desired sdk.DesiredState, opts sdk.ApplyOptions, reqOpts ...sdk.RequestOption) (*sdk.ApplyResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, desired, opts}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Apply", varargs...)
+	ret0, _ := ret[0].(*sdk.ApplyResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Apply indicates an expected call of Apply.
+func (mr *MockAPIMockRecorder) Apply(ctx, desired, opts any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, desired, opts}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockAPI)(nil).Apply), varargs...)
+}
+
+// ApplyChangeRequest mocks base method.
+func (m *MockAPI) ApplyChangeRequest(ctx context.Context, id int, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ApplyChangeRequest", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyChangeRequest indicates an expected call of ApplyChangeRequest.
+func (mr *MockAPIMockRecorder) ApplyChangeRequest(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyChangeRequest", reflect.TypeOf((*MockAPI)(nil).ApplyChangeRequest), varargs...)
+}
+
+// ApproveChangeRequest mocks base method.
+func (m *MockAPI) ApproveChangeRequest(ctx context.Context, id int, comment string, reqOpts ...sdk.RequestOption) (*sdk.ChangeRequest, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id, comment}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ApproveChangeRequest", varargs...)
+	ret0, _ := ret[0].(*sdk.ChangeRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveChangeRequest indicates an expected call of ApproveChangeRequest.
+func (mr *MockAPIMockRecorder) ApproveChangeRequest(ctx, id, comment any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id, comment}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveChangeRequest", reflect.TypeOf((*MockAPI)(nil).ApproveChangeRequest), varargs...)
+}
+
+// ApproveRelease mocks base method.
+func (m *MockAPI) ApproveRelease(ctx context.Context, pipelineID int, flag, environment, comment string, reqOpts ...sdk.RequestOption) (*sdk.FlagRelease, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, pipelineID, flag, environment, comment}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ApproveRelease", varargs...)
+	ret0, _ := ret[0].(*sdk.FlagRelease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveRelease indicates an expected call of ApproveRelease.
+func (mr *MockAPIMockRecorder) ApproveRelease(ctx, pipelineID, flag, environment, comment any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, pipelineID, flag, environment, comment}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveRelease", reflect.TypeOf((*MockAPI)(nil).ApproveRelease), varargs...)
+}
+
+// ArchiveFeatureFlag mocks base method.
+func (m *MockAPI) ArchiveFeatureFlag(ctx context.Context, id int, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ArchiveFeatureFlag", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveFeatureFlag indicates an expected call of ArchiveFeatureFlag.
+func (mr *MockAPIMockRecorder) ArchiveFeatureFlag(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveFeatureFlag", reflect.TypeOf((*MockAPI)(nil).ArchiveFeatureFlag), varargs...)
+}
+
+// AtomicFlagOperation mocks base method.
+func (m *MockAPI) AtomicFlagOperation(ctx context.Context, ops []sdk.FlagOperation, reqOpts ...sdk.RequestOption) ([]sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, ops}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AtomicFlagOperation", varargs...)
+	ret0, _ := ret[0].([]sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AtomicFlagOperation indicates an expected call of AtomicFlagOperation.
+func (mr *MockAPIMockRecorder) AtomicFlagOperation(ctx, ops any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, ops}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AtomicFlagOperation", reflect.TypeOf((*MockAPI)(nil).AtomicFlagOperation), varargs...)
+}
+
+// BreakerState mocks base method.
+func (m *MockAPI) BreakerState() sdk.BreakerState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BreakerState")
+	ret0, _ := ret[0].(sdk.BreakerState)
+	return ret0
+}
+
+// BreakerState indicates an expected call of BreakerState.
+func (mr *MockAPIMockRecorder) BreakerState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BreakerState", reflect.TypeOf((*MockAPI)(nil).BreakerState))
+}
+
+// BulkCreateFlags mocks base method.
+func (m *MockAPI) BulkCreateFlags(ctx context.Context, flags []sdk.FeatureFlagCreate, reqOpts ...sdk.RequestOption) ([]sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flags}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BulkCreateFlags", varargs...)
+	ret0, _ := ret[0].([]sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkCreateFlags indicates an expected call of BulkCreateFlags.
+func (mr *MockAPIMockRecorder) BulkCreateFlags(ctx, flags any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flags}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkCreateFlags", reflect.TypeOf((*MockAPI)(nil).BulkCreateFlags), varargs...)
+}
+
+// BulkToggle mocks base method.
+func (m *MockAPI) BulkToggle(ctx context.Context, ids []int, active bool, reqOpts ...sdk.RequestOption) ([]sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, ids, active}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BulkToggle", varargs...)
+	ret0, _ := ret[0].([]sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkToggle indicates an expected call of BulkToggle.
+func (mr *MockAPIMockRecorder) BulkToggle(ctx, ids, active any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, ids, active}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkToggle", reflect.TypeOf((*MockAPI)(nil).BulkToggle), varargs...)
+}
+
+// BulkUpdateFlags mocks base method.
+func (m *MockAPI) BulkUpdateFlags(ctx context.Context, updates []sdk.BulkUpdate, reqOpts ...sdk.RequestOption) ([]sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, updates}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BulkUpdateFlags", varargs...)
+	ret0, _ := ret[0].([]sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkUpdateFlags indicates an expected call of BulkUpdateFlags.
+func (mr *MockAPIMockRecorder) BulkUpdateFlags(ctx, updates any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, updates}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdateFlags", reflect.TypeOf((*MockAPI)(nil).BulkUpdateFlags), varargs...)
+}
+
+// CancelRollout mocks base method.
+func (m *MockAPI) CancelRollout(ctx context.Context, flagID int, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagID}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CancelRollout", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelRollout indicates an expected call of CancelRollout.
+func (mr *MockAPIMockRecorder) CancelRollout(ctx, flagID any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagID}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelRollout", reflect.TypeOf((*MockAPI)(nil).CancelRollout), varargs...)
+}
+
+// Clock mocks base method.
+func (m *MockAPI) Clock() sdk.Clock {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clock")
+	ret0, _ := ret[0].(sdk.Clock)
+	return ret0
+}
+
+// Clock indicates an expected call of Clock.
+func (mr *MockAPIMockRecorder) Clock() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clock", reflect.TypeOf((*MockAPI)(nil).Clock))
+}
+
+// Close mocks base method.
+func (m *MockAPI) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockAPIMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockAPI)(nil).Close))
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockAPI) CreateAPIKey(ctx context.Context, key sdk.APIKeyCreate, reqOpts ...sdk.RequestOption) (*sdk.APIKey, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateAPIKey", varargs...)
+	ret0, _ := ret[0].(*sdk.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockAPIMockRecorder) CreateAPIKey(ctx, key any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockAPI)(nil).CreateAPIKey), varargs...)
+}
+
+// CreateChangeRequest mocks base method.
+func (m *MockAPI) CreateChangeRequest(ctx context.Context, change sdk.ChangeRequestCreate, reqOpts ...sdk.RequestOption) (*sdk.ChangeRequest, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, change}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateChangeRequest", varargs...)
+	ret0, _ := ret[0].(*sdk.ChangeRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateChangeRequest indicates an expected call of CreateChangeRequest.
+func (mr *MockAPIMockRecorder) CreateChangeRequest(ctx, change any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, change}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChangeRequest", reflect.TypeOf((*MockAPI)(nil).CreateChangeRequest), varargs...)
+}
+
+// CreateEnvironment mocks base method.
+func (m *MockAPI) CreateEnvironment(ctx context.Context, environment sdk.EnvironmentCreate, reqOpts ...sdk.RequestOption) (*sdk.Environment, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, environment}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateEnvironment", varargs...)
+	ret0, _ := ret[0].(*sdk.Environment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEnvironment indicates an expected call of CreateEnvironment.
+func (mr *MockAPIMockRecorder) CreateEnvironment(ctx, environment any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, environment}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEnvironment", reflect.TypeOf((*MockAPI)(nil).CreateEnvironment), varargs...)
+}
+
+// CreateFeatureFlag mocks base method.
+func (m *MockAPI) CreateFeatureFlag(ctx context.Context, flag sdk.FeatureFlagCreate, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flag}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateFeatureFlag", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFeatureFlag indicates an expected call of CreateFeatureFlag.
+func (mr *MockAPIMockRecorder) CreateFeatureFlag(ctx, flag any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flag}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFeatureFlag", reflect.TypeOf((*MockAPI)(nil).CreateFeatureFlag), varargs...)
+}
+
+// CreateFlagGroup mocks base method.
+func (m *MockAPI) CreateFlagGroup(ctx context.Context, group sdk.FlagGroupCreate, reqOpts ...sdk.RequestOption) (*sdk.FlagGroup, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, group}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateFlagGroup", varargs...)
+	ret0, _ := ret[0].(*sdk.FlagGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFlagGroup indicates an expected call of CreateFlagGroup.
+func (mr *MockAPIMockRecorder) CreateFlagGroup(ctx, group any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, group}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFlagGroup", reflect.TypeOf((*MockAPI)(nil).CreateFlagGroup), varargs...)
+}
+
+// CreateHoldout mocks base method.
+func (m *MockAPI) CreateHoldout(ctx context.Context, holdout sdk.HoldoutCreate, reqOpts ...sdk.RequestOption) (*sdk.Holdout, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, holdout}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateHoldout", varargs...)
+	ret0, _ := ret[0].(*sdk.Holdout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHoldout indicates an expected call of CreateHoldout.
+func (mr *MockAPIMockRecorder) CreateHoldout(ctx, holdout any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, holdout}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHoldout", reflect.TypeOf((*MockAPI)(nil).CreateHoldout), varargs...)
+}
+
+// CreateLayer mocks base method.
+func (m *MockAPI) CreateLayer(ctx context.Context, layer sdk.LayerCreate, reqOpts ...sdk.RequestOption) (*sdk.Layer, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, layer}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateLayer", varargs...)
+	ret0, _ := ret[0].(*sdk.Layer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLayer indicates an expected call of CreateLayer.
+func (mr *MockAPIMockRecorder) CreateLayer(ctx, layer any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, layer}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLayer", reflect.TypeOf((*MockAPI)(nil).CreateLayer), varargs...)
+}
+
+// CreateProject mocks base method.
+func (m *MockAPI) CreateProject(ctx context.Context, project sdk.ProjectCreate, reqOpts ...sdk.RequestOption) (*sdk.Project, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, project}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateProject", varargs...)
+	ret0, _ := ret[0].(*sdk.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateProject indicates an expected call of CreateProject.
+func (mr *MockAPIMockRecorder) CreateProject(ctx, project any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, project}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProject", reflect.TypeOf((*MockAPI)(nil).CreateProject), varargs...)
+}
+
+// CreateReleasePipeline mocks base method.
+func (m *MockAPI) CreateReleasePipeline(ctx context.Context, pipeline sdk.ReleasePipelineCreate, reqOpts ...sdk.RequestOption) (*sdk.ReleasePipeline, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, pipeline}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateReleasePipeline", varargs...)
+	ret0, _ := ret[0].(*sdk.ReleasePipeline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReleasePipeline indicates an expected call of CreateReleasePipeline.
+func (mr *MockAPIMockRecorder) CreateReleasePipeline(ctx, pipeline any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, pipeline}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReleasePipeline", reflect.TypeOf((*MockAPI)(nil).CreateReleasePipeline), varargs...)
+}
+
+// CreateRollout mocks base method.
+func (m *MockAPI) CreateRollout(ctx context.Context, flagID int, rollout sdk.RolloutCreate, reqOpts ...sdk.RequestOption) (*sdk.Rollout, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagID, rollout}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateRollout", varargs...)
+	ret0, _ := ret[0].(*sdk.Rollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRollout indicates an expected call of CreateRollout.
+func (mr *MockAPIMockRecorder) CreateRollout(ctx, flagID, rollout any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagID, rollout}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRollout", reflect.TypeOf((*MockAPI)(nil).CreateRollout), varargs...)
+}
+
+// CreateSegment mocks base method.
+func (m *MockAPI) CreateSegment(ctx context.Context, segment sdk.SegmentCreate, reqOpts ...sdk.RequestOption) (*sdk.Segment, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, segment}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateSegment", varargs...)
+	ret0, _ := ret[0].(*sdk.Segment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSegment indicates an expected call of CreateSegment.
+func (mr *MockAPIMockRecorder) CreateSegment(ctx, segment any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, segment}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSegment", reflect.TypeOf((*MockAPI)(nil).CreateSegment), varargs...)
+}
+
+// CreateTrigger mocks base method.
+func (m *MockAPI) CreateTrigger(ctx context.Context, flagID int, trigger sdk.TriggerCreate, reqOpts ...sdk.RequestOption) (*sdk.Trigger, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagID, trigger}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateTrigger", varargs...)
+	ret0, _ := ret[0].(*sdk.Trigger)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTrigger indicates an expected call of CreateTrigger.
+func (mr *MockAPIMockRecorder) CreateTrigger(ctx, flagID, trigger any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagID, trigger}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTrigger", reflect.TypeOf((*MockAPI)(nil).CreateTrigger), varargs...)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockAPI) CreateWebhook(ctx context.Context, webhook sdk.WebhookCreate, reqOpts ...sdk.RequestOption) (*sdk.Webhook, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, webhook}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateWebhook", varargs...)
+	ret0, _ := ret[0].(*sdk.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockAPIMockRecorder) CreateWebhook(ctx, webhook any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, webhook}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockAPI)(nil).CreateWebhook), varargs...)
+}
+
+// DataSourceStatus mocks base method.
+func (m *MockAPI) DataSourceStatus() sdk.DataSourceStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DataSourceStatus")
+	ret0, _ := ret[0].(sdk.DataSourceStatus)
+	return ret0
+}
+
+// DataSourceStatus indicates an expected call of DataSourceStatus.
+func (mr *MockAPIMockRecorder) DataSourceStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DataSourceStatus", reflect.TypeOf((*MockAPI)(nil).DataSourceStatus))
+}
+
+// DebugEvaluate mocks base method.
+func (m *MockAPI) DebugEvaluate(ctx context.Context, flagKey string, evalCtx sdk.EvaluationContext, reqOpts ...sdk.RequestOption) (*sdk.EvaluationTrace, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagKey, evalCtx}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DebugEvaluate", varargs...)
+	ret0, _ := ret[0].(*sdk.EvaluationTrace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DebugEvaluate indicates an expected call of DebugEvaluate.
+func (mr *MockAPIMockRecorder) DebugEvaluate(ctx, flagKey, evalCtx any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagKey, evalCtx}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DebugEvaluate", reflect.TypeOf((*MockAPI)(nil).DebugEvaluate), varargs...)
+}
+
+// DeleteEnvironment mocks base method.
+func (m *MockAPI) DeleteEnvironment(ctx context.Context, key string, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteEnvironment", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEnvironment indicates an expected call of DeleteEnvironment.
+func (mr *MockAPIMockRecorder) DeleteEnvironment(ctx, key any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEnvironment", reflect.TypeOf((*MockAPI)(nil).DeleteEnvironment), varargs...)
+}
+
+// DeleteFeatureFlag mocks base method.
+func (m *MockAPI) DeleteFeatureFlag(ctx context.Context, id int, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteFeatureFlag", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteFeatureFlag indicates an expected call of DeleteFeatureFlag.
+func (mr *MockAPIMockRecorder) DeleteFeatureFlag(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFeatureFlag", reflect.TypeOf((*MockAPI)(nil).DeleteFeatureFlag), varargs...)
+}
+
+// DeleteFlagGroup mocks base method.
+func (m *MockAPI) DeleteFlagGroup(ctx context.Context, id int, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteFlagGroup", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFlagGroup indicates an expected call of DeleteFlagGroup.
+func (mr *MockAPIMockRecorder) DeleteFlagGroup(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFlagGroup", reflect.TypeOf((*MockAPI)(nil).DeleteFlagGroup), varargs...)
+}
+
+// DeleteHoldout mocks base method.
+func (m *MockAPI) DeleteHoldout(ctx context.Context, id int, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteHoldout", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteHoldout indicates an expected call of DeleteHoldout.
+func (mr *MockAPIMockRecorder) DeleteHoldout(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHoldout", reflect.TypeOf((*MockAPI)(nil).DeleteHoldout), varargs...)
+}
+
+// DeleteLayer mocks base method.
+func (m *MockAPI) DeleteLayer(ctx context.Context, id int, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteLayer", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLayer indicates an expected call of DeleteLayer.
+func (mr *MockAPIMockRecorder) DeleteLayer(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLayer", reflect.TypeOf((*MockAPI)(nil).DeleteLayer), varargs...)
+}
+
+// DeleteProject mocks base method.
+func (m *MockAPI) DeleteProject(ctx context.Context, id int, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteProject", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProject indicates an expected call of DeleteProject.
+func (mr *MockAPIMockRecorder) DeleteProject(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProject", reflect.TypeOf((*MockAPI)(nil).DeleteProject), varargs...)
+}
+
+// DeleteReleasePipeline mocks base method.
+func (m *MockAPI) DeleteReleasePipeline(ctx context.Context, id int, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteReleasePipeline", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReleasePipeline indicates an expected call of DeleteReleasePipeline.
+func (mr *MockAPIMockRecorder) DeleteReleasePipeline(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReleasePipeline", reflect.TypeOf((*MockAPI)(nil).DeleteReleasePipeline), varargs...)
+}
+
+// DeleteSegment mocks base method.
+func (m *MockAPI) DeleteSegment(ctx context.Context, name string, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, name}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteSegment", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSegment indicates an expected call of DeleteSegment.
+func (mr *MockAPIMockRecorder) DeleteSegment(ctx, name any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, name}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSegment", reflect.TypeOf((*MockAPI)(nil).DeleteSegment), varargs...)
+}
+
+// DeleteTrigger mocks base method.
+func (m *MockAPI) DeleteTrigger(ctx context.Context, flagID, triggerID int, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagID, triggerID}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteTrigger", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTrigger indicates an expected call of DeleteTrigger.
+func (mr *MockAPIMockRecorder) DeleteTrigger(ctx, flagID, triggerID any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagID, triggerID}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTrigger", reflect.TypeOf((*MockAPI)(nil).DeleteTrigger), varargs...)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockAPI) DeleteWebhook(ctx context.Context, id int, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteWebhook", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockAPIMockRecorder) DeleteWebhook(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockAPI)(nil).DeleteWebhook), varargs...)
+}
+
+// DisableDebug mocks base method.
+func (m *MockAPI) DisableDebug(ctx context.Context, flagID int, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagID}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DisableDebug", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableDebug indicates an expected call of DisableDebug.
+func (mr *MockAPIMockRecorder) DisableDebug(ctx, flagID any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagID}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableDebug", reflect.TypeOf((*MockAPI)(nil).DisableDebug), varargs...)
+}
+
+// EnableDebug mocks base method.
+func (m *MockAPI) EnableDebug(ctx context.Context, flagID int, duration time.Duration, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagID, duration}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "EnableDebug", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableDebug indicates an expected call of EnableDebug.
+func (mr *MockAPIMockRecorder) EnableDebug(ctx, flagID, duration any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagID, duration}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableDebug", reflect.TypeOf((*MockAPI)(nil).EnableDebug), varargs...)
+}
+
+// Evaluate mocks base method.
+func (m *MockAPI) Evaluate(ctx context.Context, flagKey string, evalCtx sdk.EvaluationContext, defaultValue any, reqOpts ...sdk.RequestOption) sdk.EvaluationDetail[any] {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagKey, evalCtx, defaultValue}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Evaluate", varargs...)
+	ret0, _ := ret[0].(sdk.EvaluationDetail[any])
+	return ret0
+}
+
+// Evaluate indicates an expected call of Evaluate.
+func (mr *MockAPIMockRecorder) Evaluate(ctx, flagKey, evalCtx, defaultValue any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagKey, evalCtx, defaultValue}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Evaluate", reflect.TypeOf((*MockAPI)(nil).Evaluate), varargs...)
+}
+
+// EvaluateBool mocks base method.
+func (m *MockAPI) EvaluateBool(ctx context.Context, flagKey string, evalCtx sdk.EvaluationContext, defaultValue bool, reqOpts ...sdk.RequestOption) sdk.EvaluationDetail[bool] {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagKey, evalCtx, defaultValue}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "EvaluateBool", varargs...)
+	ret0, _ := ret[0].(sdk.EvaluationDetail[bool])
+	return ret0
+}
+
+// EvaluateBool indicates an expected call of EvaluateBool.
+func (mr *MockAPIMockRecorder) EvaluateBool(ctx, flagKey, evalCtx, defaultValue any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagKey, evalCtx, defaultValue}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvaluateBool", reflect.TypeOf((*MockAPI)(nil).EvaluateBool), varargs...)
+}
+
+// EvaluateFloat mocks base method.
+func (m *MockAPI) EvaluateFloat(ctx context.Context, flagKey string, evalCtx sdk.EvaluationContext, defaultValue float64, reqOpts ...sdk.RequestOption) sdk.EvaluationDetail[float64] {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagKey, evalCtx, defaultValue}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "EvaluateFloat", varargs...)
+	ret0, _ := ret[0].(sdk.EvaluationDetail[float64])
+	return ret0
+}
+
+// EvaluateFloat indicates an expected call of EvaluateFloat.
+func (mr *MockAPIMockRecorder) EvaluateFloat(ctx, flagKey, evalCtx, defaultValue any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagKey, evalCtx, defaultValue}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvaluateFloat", reflect.TypeOf((*MockAPI)(nil).EvaluateFloat), varargs...)
+}
+
+// EvaluateInt mocks base method.
+func (m *MockAPI) EvaluateInt(ctx context.Context, flagKey string, evalCtx sdk.EvaluationContext, defaultValue int, reqOpts ...sdk.RequestOption) sdk.EvaluationDetail[int] {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagKey, evalCtx, defaultValue}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "EvaluateInt", varargs...)
+	ret0, _ := ret[0].(sdk.EvaluationDetail[int])
+	return ret0
+}
+
+// EvaluateInt indicates an expected call of EvaluateInt.
+func (mr *MockAPIMockRecorder) EvaluateInt(ctx, flagKey, evalCtx, defaultValue any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagKey, evalCtx, defaultValue}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvaluateInt", reflect.TypeOf((*MockAPI)(nil).EvaluateInt), varargs...)
+}
+
+// EvaluateJSON mocks base method.
+func (m *MockAPI) EvaluateJSON(ctx context.Context, flagKey string, evalCtx sdk.EvaluationContext, defaultValue any, reqOpts ...sdk.RequestOption) sdk.EvaluationDetail[any] {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagKey, evalCtx, defaultValue}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "EvaluateJSON", varargs...)
+	ret0, _ := ret[0].(sdk.EvaluationDetail[any])
+	return ret0
+}
+
+// EvaluateJSON indicates an expected call of EvaluateJSON.
+func (mr *MockAPIMockRecorder) EvaluateJSON(ctx, flagKey, evalCtx, defaultValue any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagKey, evalCtx, defaultValue}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvaluateJSON", reflect.TypeOf((*MockAPI)(nil).EvaluateJSON), varargs...)
+}
+
+// EvaluateString mocks base method.
+func (m *MockAPI) EvaluateString(ctx context.Context, flagKey string, evalCtx sdk.EvaluationContext, defaultValue string, reqOpts ...sdk.RequestOption) sdk.EvaluationDetail[string] {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagKey, evalCtx, defaultValue}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "EvaluateString", varargs...)
+	ret0, _ := ret[0].(sdk.EvaluationDetail[string])
+	return ret0
+}
+
+// EvaluateString indicates an expected call of EvaluateString.
+func (mr *MockAPIMockRecorder) EvaluateString(ctx, flagKey, evalCtx, defaultValue any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagKey, evalCtx, defaultValue}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvaluateString", reflect.TypeOf((*MockAPI)(nil).EvaluateString), varargs...)
+}
+
+// ExpiredFlags mocks base method.
+func (m *MockAPI) ExpiredFlags(ctx context.Context, reqOpts ...sdk.RequestOption) ([]sdk.ExpiredFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ExpiredFlags", varargs...)
+	ret0, _ := ret[0].([]sdk.ExpiredFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpiredFlags indicates an expected call of ExpiredFlags.
+func (mr *MockAPIMockRecorder) ExpiredFlags(ctx any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpiredFlags", reflect.TypeOf((*MockAPI)(nil).ExpiredFlags), varargs...)
+}
+
+// ExportCatalog mocks base method.
+func (m *MockAPI) ExportCatalog(ctx context.Context, environment string, opts sdk.CatalogOptions, reqOpts ...sdk.RequestOption) ([]sdk.CatalogEntity, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, environment, opts}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ExportCatalog", varargs...)
+	ret0, _ := ret[0].([]sdk.CatalogEntity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportCatalog indicates an expected call of ExportCatalog.
+func (mr *MockAPIMockRecorder) ExportCatalog(ctx, environment, opts any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, environment, opts}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportCatalog", reflect.TypeOf((*MockAPI)(nil).ExportCatalog), varargs...)
+}
+
+// ExportFlags mocks base method.
+func (m *MockAPI) ExportFlags(ctx context.Context, environment string, reqOpts ...sdk.RequestOption) (*sdk.FlagExport, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, environment}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ExportFlags", varargs...)
+	ret0, _ := ret[0].(*sdk.FlagExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportFlags indicates an expected call of ExportFlags.
+func (mr *MockAPIMockRecorder) ExportFlags(ctx, environment any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, environment}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportFlags", reflect.TypeOf((*MockAPI)(nil).ExportFlags), varargs...)
+}
+
+// ExportOpenFeature mocks base method.
+func (m *MockAPI) ExportOpenFeature(ctx context.Context, environment string, reqOpts ...sdk.RequestOption) (*sdk.OpenFeatureDocument, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, environment}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ExportOpenFeature", varargs...)
+	ret0, _ := ret[0].(*sdk.OpenFeatureDocument)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportOpenFeature indicates an expected call of ExportOpenFeature.
+func (mr *MockAPIMockRecorder) ExportOpenFeature(ctx, environment any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, environment}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportOpenFeature", reflect.TypeOf((*MockAPI)(nil).ExportOpenFeature), varargs...)
+}
+
+// FetchRuleset mocks base method.
+func (m *MockAPI) FetchRuleset(ctx context.Context, environment string, reqOpts ...sdk.RequestOption) (*sdk.Ruleset, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, environment}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "FetchRuleset", varargs...)
+	ret0, _ := ret[0].(*sdk.Ruleset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchRuleset indicates an expected call of FetchRuleset.
+func (mr *MockAPIMockRecorder) FetchRuleset(ctx, environment any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, environment}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchRuleset", reflect.TypeOf((*MockAPI)(nil).FetchRuleset), varargs...)
+}
+
+// FlushEvents mocks base method.
+func (m *MockAPI) FlushEvents(ctx context.Context, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "FlushEvents", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FlushEvents indicates an expected call of FlushEvents.
+func (mr *MockAPIMockRecorder) FlushEvents(ctx any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushEvents", reflect.TypeOf((*MockAPI)(nil).FlushEvents), varargs...)
+}
+
+// GetAPIKey mocks base method.
+func (m *MockAPI) GetAPIKey(ctx context.Context, id int, reqOpts ...sdk.RequestOption) (*sdk.APIKey, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetAPIKey", varargs...)
+	ret0, _ := ret[0].(*sdk.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKey indicates an expected call of GetAPIKey.
+func (mr *MockAPIMockRecorder) GetAPIKey(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKey", reflect.TypeOf((*MockAPI)(nil).GetAPIKey), varargs...)
+}
+
+// GetChangeRequest mocks base method.
+func (m *MockAPI) GetChangeRequest(ctx context.Context, id int, reqOpts ...sdk.RequestOption) (*sdk.ChangeRequest, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetChangeRequest", varargs...)
+	ret0, _ := ret[0].(*sdk.ChangeRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChangeRequest indicates an expected call of GetChangeRequest.
+func (mr *MockAPIMockRecorder) GetChangeRequest(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChangeRequest", reflect.TypeOf((*MockAPI)(nil).GetChangeRequest), varargs...)
+}
+
+// GetEnvironment mocks base method.
+func (m *MockAPI) GetEnvironment(ctx context.Context, key string, reqOpts ...sdk.RequestOption) (*sdk.Environment, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetEnvironment", varargs...)
+	ret0, _ := ret[0].(*sdk.Environment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEnvironment indicates an expected call of GetEnvironment.
+func (mr *MockAPIMockRecorder) GetEnvironment(ctx, key any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEnvironment", reflect.TypeOf((*MockAPI)(nil).GetEnvironment), varargs...)
+}
+
+// GetEnvironmentDefaults mocks base method.
+func (m *MockAPI) GetEnvironmentDefaults(ctx context.Context, projectID int, environment string, reqOpts ...sdk.RequestOption) (*sdk.FlagDefaults, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, projectID, environment}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetEnvironmentDefaults", varargs...)
+	ret0, _ := ret[0].(*sdk.FlagDefaults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEnvironmentDefaults indicates an expected call of GetEnvironmentDefaults.
+func (mr *MockAPIMockRecorder) GetEnvironmentDefaults(ctx, projectID, environment any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, projectID, environment}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEnvironmentDefaults", reflect.TypeOf((*MockAPI)(nil).GetEnvironmentDefaults), varargs...)
+}
+
+// GetExperimentResults mocks base method.
+func (m *MockAPI) GetExperimentResults(ctx context.Context, name string, reqOpts ...sdk.RequestOption) ([]sdk.ExperimentResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, name}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetExperimentResults", varargs...)
+	ret0, _ := ret[0].([]sdk.ExperimentResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExperimentResults indicates an expected call of GetExperimentResults.
+func (mr *MockAPIMockRecorder) GetExperimentResults(ctx, name any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, name}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExperimentResults", reflect.TypeOf((*MockAPI)(nil).GetExperimentResults), varargs...)
+}
+
+// GetFeatureFlag mocks base method.
+func (m *MockAPI) GetFeatureFlag(ctx context.Context, id int, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetFeatureFlag", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeatureFlag indicates an expected call of GetFeatureFlag.
+func (mr *MockAPIMockRecorder) GetFeatureFlag(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeatureFlag", reflect.TypeOf((*MockAPI)(nil).GetFeatureFlag), varargs...)
+}
+
+// GetFeatureFlagByName mocks base method.
+func (m *MockAPI) GetFeatureFlagByName(ctx context.Context, name, environment string, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, name, environment}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetFeatureFlagByName", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeatureFlagByName indicates an expected call of GetFeatureFlagByName.
+func (mr *MockAPIMockRecorder) GetFeatureFlagByName(ctx, name, environment any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, name, environment}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeatureFlagByName", reflect.TypeOf((*MockAPI)(nil).GetFeatureFlagByName), varargs...)
+}
+
+// GetFlagGroup mocks base method.
+func (m *MockAPI) GetFlagGroup(ctx context.Context, id int, reqOpts ...sdk.RequestOption) (*sdk.FlagGroup, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetFlagGroup", varargs...)
+	ret0, _ := ret[0].(*sdk.FlagGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFlagGroup indicates an expected call of GetFlagGroup.
+func (mr *MockAPIMockRecorder) GetFlagGroup(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFlagGroup", reflect.TypeOf((*MockAPI)(nil).GetFlagGroup), varargs...)
+}
+
+// GetFlagRelease mocks base method.
+func (m *MockAPI) GetFlagRelease(ctx context.Context, pipelineID int, flag string, reqOpts ...sdk.RequestOption) (*sdk.FlagRelease, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, pipelineID, flag}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetFlagRelease", varargs...)
+	ret0, _ := ret[0].(*sdk.FlagRelease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFlagRelease indicates an expected call of GetFlagRelease.
+func (mr *MockAPIMockRecorder) GetFlagRelease(ctx, pipelineID, flag any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, pipelineID, flag}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFlagRelease", reflect.TypeOf((*MockAPI)(nil).GetFlagRelease), varargs...)
+}
+
+// GetHoldout mocks base method.
+func (m *MockAPI) GetHoldout(ctx context.Context, id int, reqOpts ...sdk.RequestOption) (*sdk.Holdout, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetHoldout", varargs...)
+	ret0, _ := ret[0].(*sdk.Holdout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHoldout indicates an expected call of GetHoldout.
+func (mr *MockAPIMockRecorder) GetHoldout(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHoldout", reflect.TypeOf((*MockAPI)(nil).GetHoldout), varargs...)
+}
+
+// GetJSONVariation mocks base method.
+func (m *MockAPI) GetJSONVariation(ctx context.Context, flagKey string, evalCtx sdk.EvaluationContext, target any, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagKey, evalCtx, target}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetJSONVariation", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetJSONVariation indicates an expected call of GetJSONVariation.
+func (mr *MockAPIMockRecorder) GetJSONVariation(ctx, flagKey, evalCtx, target any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagKey, evalCtx, target}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJSONVariation", reflect.TypeOf((*MockAPI)(nil).GetJSONVariation), varargs...)
+}
+
+// GetLayer mocks base method.
+func (m *MockAPI) GetLayer(ctx context.Context, id int, reqOpts ...sdk.RequestOption) (*sdk.Layer, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetLayer", varargs...)
+	ret0, _ := ret[0].(*sdk.Layer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLayer indicates an expected call of GetLayer.
+func (mr *MockAPIMockRecorder) GetLayer(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLayer", reflect.TypeOf((*MockAPI)(nil).GetLayer), varargs...)
+}
+
+// GetProject mocks base method.
+func (m *MockAPI) GetProject(ctx context.Context, id int, reqOpts ...sdk.RequestOption) (*sdk.Project, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetProject", varargs...)
+	ret0, _ := ret[0].(*sdk.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProject indicates an expected call of GetProject.
+func (mr *MockAPIMockRecorder) GetProject(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProject", reflect.TypeOf((*MockAPI)(nil).GetProject), varargs...)
+}
+
+// GetProjectDefaults mocks base method.
+func (m *MockAPI) GetProjectDefaults(ctx context.Context, projectID int, reqOpts ...sdk.RequestOption) (*sdk.FlagDefaults, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, projectID}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetProjectDefaults", varargs...)
+	ret0, _ := ret[0].(*sdk.FlagDefaults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjectDefaults indicates an expected call of GetProjectDefaults.
+func (mr *MockAPIMockRecorder) GetProjectDefaults(ctx, projectID any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, projectID}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectDefaults", reflect.TypeOf((*MockAPI)(nil).GetProjectDefaults), varargs...)
+}
+
+// GetReleasePipeline mocks base method.
+func (m *MockAPI) GetReleasePipeline(ctx context.Context, id int, reqOpts ...sdk.RequestOption) (*sdk.ReleasePipeline, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetReleasePipeline", varargs...)
+	ret0, _ := ret[0].(*sdk.ReleasePipeline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReleasePipeline indicates an expected call of GetReleasePipeline.
+func (mr *MockAPIMockRecorder) GetReleasePipeline(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReleasePipeline", reflect.TypeOf((*MockAPI)(nil).GetReleasePipeline), varargs...)
+}
+
+// GetRollout mocks base method.
+func (m *MockAPI) GetRollout(ctx context.Context, flagID int, reqOpts ...sdk.RequestOption) (*sdk.Rollout, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagID}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetRollout", varargs...)
+	ret0, _ := ret[0].(*sdk.Rollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRollout indicates an expected call of GetRollout.
+func (mr *MockAPIMockRecorder) GetRollout(ctx, flagID any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagID}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRollout", reflect.TypeOf((*MockAPI)(nil).GetRollout), varargs...)
+}
+
+// GetSegment mocks base method.
+func (m *MockAPI) GetSegment(ctx context.Context, name string, reqOpts ...sdk.RequestOption) (*sdk.Segment, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, name}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetSegment", varargs...)
+	ret0, _ := ret[0].(*sdk.Segment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSegment indicates an expected call of GetSegment.
+func (mr *MockAPIMockRecorder) GetSegment(ctx, name any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, name}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSegment", reflect.TypeOf((*MockAPI)(nil).GetSegment), varargs...)
+}
+
+// GetTrafficAllocation mocks base method.
+func (m *MockAPI) GetTrafficAllocation(ctx context.Context, flagID int, reqOpts ...sdk.RequestOption) (*sdk.TrafficAllocation, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagID}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetTrafficAllocation", varargs...)
+	ret0, _ := ret[0].(*sdk.TrafficAllocation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrafficAllocation indicates an expected call of GetTrafficAllocation.
+func (mr *MockAPIMockRecorder) GetTrafficAllocation(ctx, flagID any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagID}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrafficAllocation", reflect.TypeOf((*MockAPI)(nil).GetTrafficAllocation), varargs...)
+}
+
+// GetTrigger mocks base method.
+func (m *MockAPI) GetTrigger(ctx context.Context, flagID, triggerID int, reqOpts ...sdk.RequestOption) (*sdk.Trigger, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagID, triggerID}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetTrigger", varargs...)
+	ret0, _ := ret[0].(*sdk.Trigger)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrigger indicates an expected call of GetTrigger.
+func (mr *MockAPIMockRecorder) GetTrigger(ctx, flagID, triggerID any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagID, triggerID}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrigger", reflect.TypeOf((*MockAPI)(nil).GetTrigger), varargs...)
+}
+
+// GetVariation mocks base method.
+func (m *MockAPI) GetVariation(ctx context.Context, flagKey string, evalCtx sdk.EvaluationContext, reqOpts ...sdk.RequestOption) (*sdk.Variation, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagKey, evalCtx}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetVariation", varargs...)
+	ret0, _ := ret[0].(*sdk.Variation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVariation indicates an expected call of GetVariation.
+func (mr *MockAPIMockRecorder) GetVariation(ctx, flagKey, evalCtx any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagKey, evalCtx}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVariation", reflect.TypeOf((*MockAPI)(nil).GetVariation), varargs...)
+}
+
+// GetWebhook mocks base method.
+func (m *MockAPI) GetWebhook(ctx context.Context, id int, reqOpts ...sdk.RequestOption) (*sdk.Webhook, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetWebhook", varargs...)
+	ret0, _ := ret[0].(*sdk.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockAPIMockRecorder) GetWebhook(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockAPI)(nil).GetWebhook), varargs...)
+}
+
+// ImportFlags mocks base method.
+func (m *MockAPI) ImportFlags(ctx context.Context, doc *sdk.FlagExport, opts sdk.ImportOptions, reqOpts ...sdk.RequestOption) (*sdk.ImportResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, doc, opts}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ImportFlags", varargs...)
+	ret0, _ := ret[0].(*sdk.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportFlags indicates an expected call of ImportFlags.
+func (mr *MockAPIMockRecorder) ImportFlags(ctx, doc, opts any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, doc, opts}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportFlags", reflect.TypeOf((*MockAPI)(nil).ImportFlags), varargs...)
+}
+
+// IterateFeatureFlags mocks base method.
+func (m *MockAPI) IterateFeatureFlags(opts sdk.ListOptions) *sdk.FlagIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IterateFeatureFlags", opts)
+	ret0, _ := ret[0].(*sdk.FlagIterator)
+	return ret0
+}
+
+// IterateFeatureFlags indicates an expected call of IterateFeatureFlags.
+func (mr *MockAPIMockRecorder) IterateFeatureFlags(opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterateFeatureFlags", reflect.TypeOf((*MockAPI)(nil).IterateFeatureFlags), opts)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockAPI) ListAPIKeys(ctx context.Context, params map[string]string, reqOpts ...sdk.RequestOption) ([]sdk.APIKey, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListAPIKeys", varargs...)
+	ret0, _ := ret[0].([]sdk.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockAPIMockRecorder) ListAPIKeys(ctx, params any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockAPI)(nil).ListAPIKeys), varargs...)
+}
+
+// ListAll mocks base method.
+func (m *MockAPI) ListAll(ctx context.Context, opts sdk.ListOptions, reqOpts ...sdk.RequestOption) ([]sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, opts}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListAll", varargs...)
+	ret0, _ := ret[0].([]sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAll indicates an expected call of ListAll.
+func (mr *MockAPIMockRecorder) ListAll(ctx, opts any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, opts}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockAPI)(nil).ListAll), varargs...)
+}
+
+// ListAuditEvents mocks base method.
+func (m *MockAPI) ListAuditEvents(ctx context.Context, query sdk.AuditQuery, reqOpts ...sdk.RequestOption) (*sdk.AuditPage, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, query}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListAuditEvents", varargs...)
+	ret0, _ := ret[0].(*sdk.AuditPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditEvents indicates an expected call of ListAuditEvents.
+func (mr *MockAPIMockRecorder) ListAuditEvents(ctx, query any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, query}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEvents", reflect.TypeOf((*MockAPI)(nil).ListAuditEvents), varargs...)
+}
+
+// ListAuditLog mocks base method.
+func (m *MockAPI) ListAuditLog(ctx context.Context, query sdk.AuditLogQuery, reqOpts ...sdk.RequestOption) ([]sdk.AuditLogEntry, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, query}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListAuditLog", varargs...)
+	ret0, _ := ret[0].([]sdk.AuditLogEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditLog indicates an expected call of ListAuditLog.
+func (mr *MockAPIMockRecorder) ListAuditLog(ctx, query any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, query}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditLog", reflect.TypeOf((*MockAPI)(nil).ListAuditLog), varargs...)
+}
+
+// ListChangeRequests mocks base method.
+func (m *MockAPI) ListChangeRequests(ctx context.Context, query sdk.ChangeRequestQuery, reqOpts ...sdk.RequestOption) ([]sdk.ChangeRequest, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, query}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListChangeRequests", varargs...)
+	ret0, _ := ret[0].([]sdk.ChangeRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChangeRequests indicates an expected call of ListChangeRequests.
+func (mr *MockAPIMockRecorder) ListChangeRequests(ctx, query any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, query}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChangeRequests", reflect.TypeOf((*MockAPI)(nil).ListChangeRequests), varargs...)
+}
+
+// ListDebugEvents mocks base method.
+func (m *MockAPI) ListDebugEvents(ctx context.Context, flagID int, since time.Time, reqOpts ...sdk.RequestOption) ([]sdk.DebugEvent, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagID, since}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListDebugEvents", varargs...)
+	ret0, _ := ret[0].([]sdk.DebugEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDebugEvents indicates an expected call of ListDebugEvents.
+func (mr *MockAPIMockRecorder) ListDebugEvents(ctx, flagID, since any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagID, since}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDebugEvents", reflect.TypeOf((*MockAPI)(nil).ListDebugEvents), varargs...)
+}
+
+// ListEnvironments mocks base method.
+func (m *MockAPI) ListEnvironments(ctx context.Context, reqOpts ...sdk.RequestOption) ([]sdk.Environment, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListEnvironments", varargs...)
+	ret0, _ := ret[0].([]sdk.Environment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEnvironments indicates an expected call of ListEnvironments.
+func (mr *MockAPIMockRecorder) ListEnvironments(ctx any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEnvironments", reflect.TypeOf((*MockAPI)(nil).ListEnvironments), varargs...)
+}
+
+// ListFeatureFlags mocks base method.
+func (m *MockAPI) ListFeatureFlags(ctx context.Context, filter sdk.FlagFilter, reqOpts ...sdk.RequestOption) ([]sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, filter}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListFeatureFlags", varargs...)
+	ret0, _ := ret[0].([]sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFeatureFlags indicates an expected call of ListFeatureFlags.
+func (mr *MockAPIMockRecorder) ListFeatureFlags(ctx, filter any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, filter}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFeatureFlags", reflect.TypeOf((*MockAPI)(nil).ListFeatureFlags), varargs...)
+}
+
+// ListFlagGroups mocks base method.
+func (m *MockAPI) ListFlagGroups(ctx context.Context, reqOpts ...sdk.RequestOption) ([]sdk.FlagGroup, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListFlagGroups", varargs...)
+	ret0, _ := ret[0].([]sdk.FlagGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFlagGroups indicates an expected call of ListFlagGroups.
+func (mr *MockAPIMockRecorder) ListFlagGroups(ctx any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFlagGroups", reflect.TypeOf((*MockAPI)(nil).ListFlagGroups), varargs...)
+}
+
+// ListHoldouts mocks base method.
+func (m *MockAPI) ListHoldouts(ctx context.Context, environment string, reqOpts ...sdk.RequestOption) ([]sdk.Holdout, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, environment}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListHoldouts", varargs...)
+	ret0, _ := ret[0].([]sdk.Holdout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHoldouts indicates an expected call of ListHoldouts.
+func (mr *MockAPIMockRecorder) ListHoldouts(ctx, environment any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, environment}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHoldouts", reflect.TypeOf((*MockAPI)(nil).ListHoldouts), varargs...)
+}
+
+// ListLayers mocks base method.
+func (m *MockAPI) ListLayers(ctx context.Context, environment string, reqOpts ...sdk.RequestOption) ([]sdk.Layer, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, environment}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListLayers", varargs...)
+	ret0, _ := ret[0].([]sdk.Layer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLayers indicates an expected call of ListLayers.
+func (mr *MockAPIMockRecorder) ListLayers(ctx, environment any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, environment}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLayers", reflect.TypeOf((*MockAPI)(nil).ListLayers), varargs...)
+}
+
+// ListProjects mocks base method.
+func (m *MockAPI) ListProjects(ctx context.Context, reqOpts ...sdk.RequestOption) ([]sdk.Project, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListProjects", varargs...)
+	ret0, _ := ret[0].([]sdk.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProjects indicates an expected call of ListProjects.
+func (mr *MockAPIMockRecorder) ListProjects(ctx any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProjects", reflect.TypeOf((*MockAPI)(nil).ListProjects), varargs...)
+}
+
+// ListReleasePipelines mocks base method.
+func (m *MockAPI) ListReleasePipelines(ctx context.Context, reqOpts ...sdk.RequestOption) ([]sdk.ReleasePipeline, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListReleasePipelines", varargs...)
+	ret0, _ := ret[0].([]sdk.ReleasePipeline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReleasePipelines indicates an expected call of ListReleasePipelines.
+func (mr *MockAPIMockRecorder) ListReleasePipelines(ctx any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReleasePipelines", reflect.TypeOf((*MockAPI)(nil).ListReleasePipelines), varargs...)
+}
+
+// ListSegments mocks base method.
+func (m *MockAPI) ListSegments(ctx context.Context, reqOpts ...sdk.RequestOption) ([]sdk.Segment, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListSegments", varargs...)
+	ret0, _ := ret[0].([]sdk.Segment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSegments indicates an expected call of ListSegments.
+func (mr *MockAPIMockRecorder) ListSegments(ctx any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSegments", reflect.TypeOf((*MockAPI)(nil).ListSegments), varargs...)
+}
+
+// ListTriggers mocks base method.
+func (m *MockAPI) ListTriggers(ctx context.Context, flagID int, reqOpts ...sdk.RequestOption) ([]sdk.Trigger, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagID}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListTriggers", varargs...)
+	ret0, _ := ret[0].([]sdk.Trigger)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTriggers indicates an expected call of ListTriggers.
+func (mr *MockAPIMockRecorder) ListTriggers(ctx, flagID any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagID}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTriggers", reflect.TypeOf((*MockAPI)(nil).ListTriggers), varargs...)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockAPI) ListWebhooks(ctx context.Context, reqOpts ...sdk.RequestOption) ([]sdk.Webhook, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListWebhooks", varargs...)
+	ret0, _ := ret[0].([]sdk.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockAPIMockRecorder) ListWebhooks(ctx any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockAPI)(nil).ListWebhooks), varargs...)
+}
+
+// Logger mocks base method.
+func (m *MockAPI) Logger() *slog.Logger {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logger")
+	ret0, _ := ret[0].(*slog.Logger)
+	return ret0
+}
+
+// Logger indicates an expected call of Logger.
+func (mr *MockAPIMockRecorder) Logger() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logger", reflect.TypeOf((*MockAPI)(nil).Logger))
+}
+
+// Metrics mocks base method.
+func (m *MockAPI) Metrics() sdk.Metrics {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Metrics")
+	ret0, _ := ret[0].(sdk.Metrics)
+	return ret0
+}
+
+// Metrics indicates an expected call of Metrics.
+func (mr *MockAPIMockRecorder) Metrics() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Metrics", reflect.TypeOf((*MockAPI)(nil).Metrics))
+}
+
+// OnAnyChange mocks base method.
+func (m *MockAPI) OnAnyChange(fn sdk.FlagChangeFunc) *sdk.Subscription {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnAnyChange", fn)
+	ret0, _ := ret[0].(*sdk.Subscription)
+	return ret0
+}
+
+// OnAnyChange indicates an expected call of OnAnyChange.
+func (mr *MockAPIMockRecorder) OnAnyChange(fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnAnyChange", reflect.TypeOf((*MockAPI)(nil).OnAnyChange), fn)
+}
+
+// OnFlagChange mocks base method.
+func (m *MockAPI) OnFlagChange(key string, fn sdk.FlagChangeFunc) *sdk.Subscription {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnFlagChange", key, fn)
+	ret0, _ := ret[0].(*sdk.Subscription)
+	return ret0
+}
+
+// OnFlagChange indicates an expected call of OnFlagChange.
+func (mr *MockAPIMockRecorder) OnFlagChange(key, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnFlagChange", reflect.TypeOf((*MockAPI)(nil).OnFlagChange), key, fn)
+}
+
+// PatchFeatureFlag mocks base method.
+func (m *MockAPI) PatchFeatureFlag(ctx context.Context, id int, update sdk.FeatureFlagUpdate, mask sdk.FieldMask, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id, update, mask}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PatchFeatureFlag", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchFeatureFlag indicates an expected call of PatchFeatureFlag.
+func (mr *MockAPIMockRecorder) PatchFeatureFlag(ctx, id, update, mask any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id, update, mask}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchFeatureFlag", reflect.TypeOf((*MockAPI)(nil).PatchFeatureFlag), varargs...)
+}
+
+// PauseRollout mocks base method.
+func (m *MockAPI) PauseRollout(ctx context.Context, flagID int, reason string, reqOpts ...sdk.RequestOption) (*sdk.Rollout, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagID, reason}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PauseRollout", varargs...)
+	ret0, _ := ret[0].(*sdk.Rollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PauseRollout indicates an expected call of PauseRollout.
+func (mr *MockAPIMockRecorder) PauseRollout(ctx, flagID, reason any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagID, reason}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseRollout", reflect.TypeOf((*MockAPI)(nil).PauseRollout), varargs...)
+}
+
+// Plan mocks base method.
+func (m *MockAPI) Plan(ctx context.Context, desired []sdk.FlagSpec, reqOpts ...sdk.RequestOption) (*sdk.FlagPlan, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, desired}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Plan", varargs...)
+	ret0, _ := ret[0].(*sdk.FlagPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Plan indicates an expected call of Plan.
+func (mr *MockAPIMockRecorder) Plan(ctx, desired any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, desired}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Plan", reflect.TypeOf((*MockAPI)(nil).Plan), varargs...)
+}
+
+// RateLimit mocks base method.
+func (m *MockAPI) RateLimit() (sdk.RateLimit, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RateLimit")
+	ret0, _ := ret[0].(sdk.RateLimit)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// RateLimit indicates an expected call of RateLimit.
+func (mr *MockAPIMockRecorder) RateLimit() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RateLimit", reflect.TypeOf((*MockAPI)(nil).RateLimit))
+}
+
+// RejectChangeRequest mocks base method.
+func (m *MockAPI) RejectChangeRequest(ctx context.Context, id int, comment string, reqOpts ...sdk.RequestOption) (*sdk.ChangeRequest, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id, comment}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RejectChangeRequest", varargs...)
+	ret0, _ := ret[0].(*sdk.ChangeRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RejectChangeRequest indicates an expected call of RejectChangeRequest.
+func (mr *MockAPIMockRecorder) RejectChangeRequest(ctx, id, comment any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id, comment}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectChangeRequest", reflect.TypeOf((*MockAPI)(nil).RejectChangeRequest), varargs...)
+}
+
+// RemoveTag mocks base method.
+func (m *MockAPI) RemoveTag(ctx context.Context, id int, tag string, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id, tag}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RemoveTag", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveTag indicates an expected call of RemoveTag.
+func (mr *MockAPIMockRecorder) RemoveTag(ctx, id, tag any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id, tag}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTag", reflect.TypeOf((*MockAPI)(nil).RemoveTag), varargs...)
+}
+
+// RemoveWebhook mocks base method.
+func (m *MockAPI) RemoveWebhook(ctx context.Context, url string, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, url}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RemoveWebhook", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveWebhook indicates an expected call of RemoveWebhook.
+func (mr *MockAPIMockRecorder) RemoveWebhook(ctx, url any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, url}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveWebhook", reflect.TypeOf((*MockAPI)(nil).RemoveWebhook), varargs...)
+}
+
+// ReportReleaseCheck mocks base method.
+func (m *MockAPI) ReportReleaseCheck(ctx context.Context, pipelineID int, flag, environment, check string, passed bool, reqOpts ...sdk.RequestOption) (*sdk.FlagRelease, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, pipelineID, flag, environment, check, passed}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ReportReleaseCheck", varargs...)
+	ret0, _ := ret[0].(*sdk.FlagRelease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportReleaseCheck indicates an expected call of ReportReleaseCheck.
+func (mr *MockAPIMockRecorder) ReportReleaseCheck(ctx, pipelineID, flag, environment, check, passed any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, pipelineID, flag, environment, check, passed}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportReleaseCheck", reflect.TypeOf((*MockAPI)(nil).ReportReleaseCheck), varargs...)
+}
+
+// ResetTriggerURL mocks base method.
+func (m *MockAPI) ResetTriggerURL(ctx context.Context, flagID, triggerID int, reqOpts ...sdk.RequestOption) (*sdk.Trigger, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagID, triggerID}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ResetTriggerURL", varargs...)
+	ret0, _ := ret[0].(*sdk.Trigger)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetTriggerURL indicates an expected call of ResetTriggerURL.
+func (mr *MockAPIMockRecorder) ResetTriggerURL(ctx, flagID, triggerID any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagID, triggerID}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetTriggerURL", reflect.TypeOf((*MockAPI)(nil).ResetTriggerURL), varargs...)
+}
+
+// ResolveFeatureFlag mocks base method.
+func (m *MockAPI) ResolveFeatureFlag(ctx context.Context, id int, reqOpts ...sdk.RequestOption) (*sdk.ResolvedFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ResolveFeatureFlag", varargs...)
+	ret0, _ := ret[0].(*sdk.ResolvedFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveFeatureFlag indicates an expected call of ResolveFeatureFlag.
+func (mr *MockAPIMockRecorder) ResolveFeatureFlag(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveFeatureFlag", reflect.TypeOf((*MockAPI)(nil).ResolveFeatureFlag), varargs...)
+}
+
+// ResumeRollout mocks base method.
+func (m *MockAPI) ResumeRollout(ctx context.Context, flagID int, reqOpts ...sdk.RequestOption) (*sdk.Rollout, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagID}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ResumeRollout", varargs...)
+	ret0, _ := ret[0].(*sdk.Rollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumeRollout indicates an expected call of ResumeRollout.
+func (mr *MockAPIMockRecorder) ResumeRollout(ctx, flagID any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagID}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeRollout", reflect.TypeOf((*MockAPI)(nil).ResumeRollout), varargs...)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockAPI) RevokeAPIKey(ctx context.Context, id int, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RevokeAPIKey", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockAPIMockRecorder) RevokeAPIKey(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockAPI)(nil).RevokeAPIKey), varargs...)
+}
+
+// RollbackRollout mocks base method.
+func (m *MockAPI) RollbackRollout(ctx context.Context, flagID int, reason string, reqOpts ...sdk.RequestOption) (*sdk.Rollout, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagID, reason}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RollbackRollout", varargs...)
+	ret0, _ := ret[0].(*sdk.Rollout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RollbackRollout indicates an expected call of RollbackRollout.
+func (mr *MockAPIMockRecorder) RollbackRollout(ctx, flagID, reason any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagID, reason}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RollbackRollout", reflect.TypeOf((*MockAPI)(nil).RollbackRollout), varargs...)
+}
+
+// RotateAPIKey mocks base method.
+func (m *MockAPI) RotateAPIKey(ctx context.Context, id int, gracePeriod time.Duration, reqOpts ...sdk.RequestOption) (*sdk.APIKey, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id, gracePeriod}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RotateAPIKey", varargs...)
+	ret0, _ := ret[0].(*sdk.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateAPIKey indicates an expected call of RotateAPIKey.
+func (mr *MockAPIMockRecorder) RotateAPIKey(ctx, id, gracePeriod any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id, gracePeriod}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateAPIKey", reflect.TypeOf((*MockAPI)(nil).RotateAPIKey), varargs...)
+}
+
+// RunEvents mocks base method.
+func (m *MockAPI) RunEvents(ctx context.Context, reqOpts ...sdk.RequestOption) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "RunEvents", varargs...)
+}
+
+// RunEvents indicates an expected call of RunEvents.
+func (mr *MockAPIMockRecorder) RunEvents(ctx any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunEvents", reflect.TypeOf((*MockAPI)(nil).RunEvents), varargs...)
+}
+
+// SendDebugEvents mocks base method.
+func (m *MockAPI) SendDebugEvents(ctx context.Context, events []sdk.DebugEvent, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, events}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SendDebugEvents", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendDebugEvents indicates an expected call of SendDebugEvents.
+func (mr *MockAPIMockRecorder) SendDebugEvents(ctx, events any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, events}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendDebugEvents", reflect.TypeOf((*MockAPI)(nil).SendDebugEvents), varargs...)
+}
+
+// SendEvents mocks base method.
+func (m *MockAPI) SendEvents(ctx context.Context, events []sdk.Event, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, events}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SendEvents", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendEvents indicates an expected call of SendEvents.
+func (mr *MockAPIMockRecorder) SendEvents(ctx, events any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, events}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendEvents", reflect.TypeOf((*MockAPI)(nil).SendEvents), varargs...)
+}
+
+// SetFlagGroupActive mocks base method.
+func (m *MockAPI) SetFlagGroupActive(ctx context.Context, id int, active bool, reqOpts ...sdk.RequestOption) ([]sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id, active}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SetFlagGroupActive", varargs...)
+	ret0, _ := ret[0].([]sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetFlagGroupActive indicates an expected call of SetFlagGroupActive.
+func (mr *MockAPIMockRecorder) SetFlagGroupActive(ctx, id, active any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id, active}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFlagGroupActive", reflect.TypeOf((*MockAPI)(nil).SetFlagGroupActive), varargs...)
+}
+
+// Simulate mocks base method.
+func (m *MockAPI) Simulate(ctx context.Context, draft sdk.FeatureFlag, contexts []sdk.EvaluationContext, reqOpts ...sdk.RequestOption) (*sdk.SimulationResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, draft, contexts}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Simulate", varargs...)
+	ret0, _ := ret[0].(*sdk.SimulationResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Simulate indicates an expected call of Simulate.
+func (mr *MockAPIMockRecorder) Simulate(ctx, draft, contexts any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, draft, contexts}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Simulate", reflect.TypeOf((*MockAPI)(nil).Simulate), varargs...)
+}
+
+// Start mocks base method.
+func (m *MockAPI) Start(ctx context.Context, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Start", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockAPIMockRecorder) Start(ctx any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockAPI)(nil).Start), varargs...)
+}
+
+// StreamFlags mocks base method.
+func (m *MockAPI) StreamFlags(ctx context.Context, reqOpts ...sdk.RequestOption) (<-chan sdk.FlagEvent, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StreamFlags", varargs...)
+	ret0, _ := ret[0].(<-chan sdk.FlagEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StreamFlags indicates an expected call of StreamFlags.
+func (mr *MockAPIMockRecorder) StreamFlags(ctx any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamFlags", reflect.TypeOf((*MockAPI)(nil).StreamFlags), varargs...)
+}
+
+// Subscribe mocks base method.
+func (m *MockAPI) Subscribe(key string, fn sdk.FlagChangeFunc) *sdk.Subscription {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", key, fn)
+	ret0, _ := ret[0].(*sdk.Subscription)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockAPIMockRecorder) Subscribe(key, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockAPI)(nil).Subscribe), key, fn)
+}
+
+// ToggleFeatureFlag mocks base method.
+func (m *MockAPI) ToggleFeatureFlag(ctx context.Context, id int, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ToggleFeatureFlag", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ToggleFeatureFlag indicates an expected call of ToggleFeatureFlag.
+func (mr *MockAPIMockRecorder) ToggleFeatureFlag(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToggleFeatureFlag", reflect.TypeOf((*MockAPI)(nil).ToggleFeatureFlag), varargs...)
+}
+
+// Tracer mocks base method.
+func (m *MockAPI) Tracer() sdk.Tracer {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tracer")
+	ret0, _ := ret[0].(sdk.Tracer)
+	return ret0
+}
+
+// Tracer indicates an expected call of Tracer.
+func (mr *MockAPIMockRecorder) Tracer() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tracer", reflect.TypeOf((*MockAPI)(nil).Tracer))
+}
+
+// Track mocks base method.
+func (m *MockAPI) Track(ctx context.Context, eventName string, evalCtx sdk.EvaluationContext, value float64, properties map[string]any, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, eventName, evalCtx, value, properties}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Track", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Track indicates an expected call of Track.
+func (mr *MockAPIMockRecorder) Track(ctx, eventName, evalCtx, value, properties any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, eventName, evalCtx, value, properties}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Track", reflect.TypeOf((*MockAPI)(nil).Track), varargs...)
+}
+
+// UnarchiveFeatureFlag mocks base method.
+func (m *MockAPI) UnarchiveFeatureFlag(ctx context.Context, id int, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UnarchiveFeatureFlag", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnarchiveFeatureFlag indicates an expected call of UnarchiveFeatureFlag.
+func (mr *MockAPIMockRecorder) UnarchiveFeatureFlag(ctx, id any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnarchiveFeatureFlag", reflect.TypeOf((*MockAPI)(nil).UnarchiveFeatureFlag), varargs...)
+}
+
+// UpdateEnvironment mocks base method.
+func (m *MockAPI) UpdateEnvironment(ctx context.Context, key string, environment sdk.EnvironmentUpdate, reqOpts ...sdk.RequestOption) (*sdk.Environment, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key, environment}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateEnvironment", varargs...)
+	ret0, _ := ret[0].(*sdk.Environment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEnvironment indicates an expected call of UpdateEnvironment.
+func (mr *MockAPIMockRecorder) UpdateEnvironment(ctx, key, environment any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key, environment}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEnvironment", reflect.TypeOf((*MockAPI)(nil).UpdateEnvironment), varargs...)
+}
+
+// UpdateEnvironmentDefaults mocks base method.
+func (m *MockAPI) UpdateEnvironmentDefaults(ctx context.Context, projectID int, environment string, defaults sdk.FlagDefaults, reqOpts ...sdk.RequestOption) (*sdk.FlagDefaults, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, projectID, environment, defaults}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateEnvironmentDefaults", varargs...)
+	ret0, _ := ret[0].(*sdk.FlagDefaults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEnvironmentDefaults indicates an expected call of UpdateEnvironmentDefaults.
+func (mr *MockAPIMockRecorder) UpdateEnvironmentDefaults(ctx, projectID, environment, defaults any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, projectID, environment, defaults}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEnvironmentDefaults", reflect.TypeOf((*MockAPI)(nil).UpdateEnvironmentDefaults), varargs...)
+}
+
+// UpdateFeatureFlag mocks base method.
+func (m *MockAPI) UpdateFeatureFlag(ctx context.Context, id int, flag sdk.FeatureFlagUpdate, reqOpts ...sdk.RequestOption) (*sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id, flag}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateFeatureFlag", varargs...)
+	ret0, _ := ret[0].(*sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFeatureFlag indicates an expected call of UpdateFeatureFlag.
+func (mr *MockAPIMockRecorder) UpdateFeatureFlag(ctx, id, flag any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id, flag}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFeatureFlag", reflect.TypeOf((*MockAPI)(nil).UpdateFeatureFlag), varargs...)
+}
+
+// UpdateFlagGroup mocks base method.
+func (m *MockAPI) UpdateFlagGroup(ctx context.Context, id int, group sdk.FlagGroupUpdate, reqOpts ...sdk.RequestOption) (*sdk.FlagGroup, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id, group}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateFlagGroup", varargs...)
+	ret0, _ := ret[0].(*sdk.FlagGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFlagGroup indicates an expected call of UpdateFlagGroup.
+func (mr *MockAPIMockRecorder) UpdateFlagGroup(ctx, id, group any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id, group}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFlagGroup", reflect.TypeOf((*MockAPI)(nil).UpdateFlagGroup), varargs...)
+}
+
+// UpdateFlagGroupFlags mocks base method.
+func (m *MockAPI) UpdateFlagGroupFlags(ctx context.Context, id int, update sdk.FeatureFlagUpdate, reqOpts ...sdk.RequestOption) ([]sdk.FeatureFlag, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id, update}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateFlagGroupFlags", varargs...)
+	ret0, _ := ret[0].([]sdk.FeatureFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFlagGroupFlags indicates an expected call of UpdateFlagGroupFlags.
+func (mr *MockAPIMockRecorder) UpdateFlagGroupFlags(ctx, id, update any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id, update}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFlagGroupFlags", reflect.TypeOf((*MockAPI)(nil).UpdateFlagGroupFlags), varargs...)
+}
+
+// UpdateHoldout mocks base method.
+func (m *MockAPI) UpdateHoldout(ctx context.Context, id int, holdout sdk.HoldoutUpdate, reqOpts ...sdk.RequestOption) (*sdk.Holdout, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id, holdout}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateHoldout", varargs...)
+	ret0, _ := ret[0].(*sdk.Holdout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateHoldout indicates an expected call of UpdateHoldout.
+func (mr *MockAPIMockRecorder) UpdateHoldout(ctx, id, holdout any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id, holdout}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateHoldout", reflect.TypeOf((*MockAPI)(nil).UpdateHoldout), varargs...)
+}
+
+// UpdateLayer mocks base method.
+func (m *MockAPI) UpdateLayer(ctx context.Context, id int, layer sdk.LayerUpdate, reqOpts ...sdk.RequestOption) (*sdk.Layer, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id, layer}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateLayer", varargs...)
+	ret0, _ := ret[0].(*sdk.Layer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLayer indicates an expected call of UpdateLayer.
+func (mr *MockAPIMockRecorder) UpdateLayer(ctx, id, layer any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id, layer}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLayer", reflect.TypeOf((*MockAPI)(nil).UpdateLayer), varargs...)
+}
+
+// UpdateProject mocks base method.
+func (m *MockAPI) UpdateProject(ctx context.Context, id int, project sdk.ProjectUpdate, reqOpts ...sdk.RequestOption) (*sdk.Project, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id, project}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateProject", varargs...)
+	ret0, _ := ret[0].(*sdk.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProject indicates an expected call of UpdateProject.
+func (mr *MockAPIMockRecorder) UpdateProject(ctx, id, project any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id, project}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProject", reflect.TypeOf((*MockAPI)(nil).UpdateProject), varargs...)
+}
+
+// UpdateProjectDefaults mocks base method.
+func (m *MockAPI) UpdateProjectDefaults(ctx context.Context, projectID int, defaults sdk.FlagDefaults, reqOpts ...sdk.RequestOption) (*sdk.FlagDefaults, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, projectID, defaults}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateProjectDefaults", varargs...)
+	ret0, _ := ret[0].(*sdk.FlagDefaults)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProjectDefaults indicates an expected call of UpdateProjectDefaults.
+func (mr *MockAPIMockRecorder) UpdateProjectDefaults(ctx, projectID, defaults any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, projectID, defaults}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProjectDefaults", reflect.TypeOf((*MockAPI)(nil).UpdateProjectDefaults), varargs...)
+}
+
+// UpdateReleasePipeline mocks base method.
+func (m *MockAPI) UpdateReleasePipeline(ctx context.Context, id int, pipeline sdk.ReleasePipelineUpdate, reqOpts ...sdk.RequestOption) (*sdk.ReleasePipeline, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id, pipeline}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateReleasePipeline", varargs...)
+	ret0, _ := ret[0].(*sdk.ReleasePipeline)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateReleasePipeline indicates an expected call of UpdateReleasePipeline.
+func (mr *MockAPIMockRecorder) UpdateReleasePipeline(ctx, id, pipeline any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id, pipeline}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReleasePipeline", reflect.TypeOf((*MockAPI)(nil).UpdateReleasePipeline), varargs...)
+}
+
+// UpdateSegment mocks base method.
+func (m *MockAPI) UpdateSegment(ctx context.Context, name string, segment sdk.SegmentUpdate, reqOpts ...sdk.RequestOption) (*sdk.Segment, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, name, segment}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateSegment", varargs...)
+	ret0, _ := ret[0].(*sdk.Segment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSegment indicates an expected call of UpdateSegment.
+func (mr *MockAPIMockRecorder) UpdateSegment(ctx, name, segment any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, name, segment}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSegment", reflect.TypeOf((*MockAPI)(nil).UpdateSegment), varargs...)
+}
+
+// UpdateTrafficAllocation mocks base method.
+func (m *MockAPI) UpdateTrafficAllocation(ctx context.Context, flagID int, allocation sdk.TrafficAllocation, reqOpts ...sdk.RequestOption) (*sdk.TrafficAllocation, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagID, allocation}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateTrafficAllocation", varargs...)
+	ret0, _ := ret[0].(*sdk.TrafficAllocation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTrafficAllocation indicates an expected call of UpdateTrafficAllocation.
+func (mr *MockAPIMockRecorder) UpdateTrafficAllocation(ctx, flagID, allocation any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagID, allocation}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTrafficAllocation", reflect.TypeOf((*MockAPI)(nil).UpdateTrafficAllocation), varargs...)
+}
+
+// UpdateTrigger mocks base method.
+func (m *MockAPI) UpdateTrigger(ctx context.Context, flagID, triggerID int, trigger sdk.TriggerUpdate, reqOpts ...sdk.RequestOption) (*sdk.Trigger, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, flagID, triggerID, trigger}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateTrigger", varargs...)
+	ret0, _ := ret[0].(*sdk.Trigger)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTrigger indicates an expected call of UpdateTrigger.
+func (mr *MockAPIMockRecorder) UpdateTrigger(ctx, flagID, triggerID, trigger any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, flagID, triggerID, trigger}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTrigger", reflect.TypeOf((*MockAPI)(nil).UpdateTrigger), varargs...)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockAPI) UpdateWebhook(ctx context.Context, id int, webhook sdk.WebhookUpdate, reqOpts ...sdk.RequestOption) (*sdk.Webhook, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, id, webhook}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateWebhook", varargs...)
+	ret0, _ := ret[0].(*sdk.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockAPIMockRecorder) UpdateWebhook(ctx, id, webhook any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, id, webhook}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockAPI)(nil).UpdateWebhook), varargs...)
+}
+
+// ValidateEnvironment mocks base method.
+func (m *MockAPI) ValidateEnvironment(ctx context.Context, key string, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, key}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ValidateEnvironment", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateEnvironment indicates an expected call of ValidateEnvironment.
+func (mr *MockAPIMockRecorder) ValidateEnvironment(ctx, key any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, key}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateEnvironment", reflect.TypeOf((*MockAPI)(nil).ValidateEnvironment), varargs...)
+}
+
+// WaitForReady mocks base method.
+func (m *MockAPI) WaitForReady(ctx context.Context, timeout time.Duration, reqOpts ...sdk.RequestOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, timeout}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WaitForReady", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaitForReady indicates an expected call of WaitForReady.
+func (mr *MockAPIMockRecorder) WaitForReady(ctx, timeout any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, timeout}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForReady", reflect.TypeOf((*MockAPI)(nil).WaitForReady), varargs...)
+}
+
+// Watch mocks base method.
+func (m *MockAPI) Watch(ctx context.Context, opts sdk.WatchOptions, reqOpts ...sdk.RequestOption) (<-chan sdk.FlagChange, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, opts}
+	for _, a := range reqOpts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Watch", varargs...)
+	ret0, _ := ret[0].(<-chan sdk.FlagChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Watch indicates an expected call of Watch.
+func (mr *MockAPIMockRecorder) Watch(ctx, opts any, reqOpts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, opts}, reqOpts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Watch", reflect.TypeOf((*MockAPI)(nil).Watch), varargs...)
+}
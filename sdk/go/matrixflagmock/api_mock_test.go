@@ -0,0 +1,32 @@
+package matrixflagmock
+
+import (
+	"context"
+	"testing"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	_ matrixflag.API           = (*MockAPI)(nil)
+	_ matrixflag.FlagEvaluator = (*MockFlagEvaluator)(nil)
+	_ matrixflag.FlagManager   = (*MockFlagManager)(nil)
+)
+
+// checkoutEnabled is code under test depending on the evaluation interface only
+func checkoutEnabled(ctx context.Context, flags matrixflag.FlagEvaluator, user string) bool {
+	return flags.EvaluateBool(ctx, "new-checkout", matrixflag.NewContext(user), false).Value
+}
+
+func TestMockFlagEvaluator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	api := NewMockAPI(ctrl)
+	api.EXPECT().
+		EvaluateBool(gomock.Any(), "new-checkout", matrixflag.NewContext("user-1"), false).
+		Return(matrixflag.EvaluationDetail[bool]{Value: true, Reason: matrixflag.ReasonDefault})
+
+	if !checkoutEnabled(context.Background(), api, "user-1") {
+		t.Error("checkoutEnabled returned false, want the mocked true")
+	}
+}
@@ -0,0 +1,12 @@
+// Package matrixflagmock provides gomock doubles of the matrixflag.API,
+// FlagEvaluator and FlagManager interfaces, for tests of code depending on
+// them. It is a separate module so the SDK does not depend on gomock.
+//
+//	ctrl := gomock.NewController(t)
+//	api := matrixflagmock.NewMockAPI(ctrl)
+//	api.EXPECT().
+//		EvaluateBool(gomock.Any(), "new-checkout", gomock.Any(), false).
+//		Return(matrixflag.EvaluationDetail[bool]{Value: true, Reason: matrixflag.ReasonDefault})
+package matrixflagmock
+
+//go:generate go run go.uber.org/mock/mockgen@v0.4.0 -source=../api.go -destination=api_mock.go -package=matrixflagmock -write_package_comment=false
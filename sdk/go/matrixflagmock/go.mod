@@ -0,0 +1,17 @@
+module github.com/matrixflag/sdk/matrixflagmock
+
+go 1.21
+
+require (
+	github.com/matrixflag/sdk v0.0.0
+	go.uber.org/mock v0.4.0
+)
+
+require (
+	github.com/google/uuid v1.4.0 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	github.com/pelletier/go-toml/v2 v2.0.8 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/matrixflag/sdk => ..
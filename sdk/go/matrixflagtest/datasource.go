@@ -0,0 +1,316 @@
+package matrixflagtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+// TestEnvironment is the environment of the flags of a TestDataSource
+const TestEnvironment = "test"
+
+// valueRule is the ID of the rule serving the value of a flag set with Set
+const valueRule = "matrixflagtest"
+
+// Evaluation is a flag evaluation recorded by a TestDataSource
+type Evaluation struct {
+	Flag   string
+	Detail matrixflag.EvaluationDetail[any]
+}
+
+// TestDataSource holds flags in memory and serves them to the clients it
+// creates, so code using a matrixflag.Client can be tested without a server:
+//
+//	td := matrixflagtest.NewTestDataSource()
+//	td.Set("new-checkout", true)
+//	client := td.Client()
+//	// run the code under test with client
+//	td.AssertEvaluated(t, "new-checkout")
+//
+// The clients evaluate flags, one or all at once, list and get them and fetch rulesets for
+// Evaluators from the data source; other API calls fail with status 501.
+// Changes to the flags apply to the next call; Evaluators see them on their
+// next Sync. A TestDataSource is safe for concurrent use.
+type TestDataSource struct {
+	evaluator *matrixflag.Evaluator
+
+	mu          sync.Mutex
+	flags       map[string]matrixflag.FeatureFlag
+	nextID      int
+	evaluations []Evaluation
+}
+
+// NewTestDataSource creates a data source without flags
+func NewTestDataSource() *TestDataSource {
+	td := &TestDataSource{
+		evaluator: matrixflag.NewEvaluator(matrixflag.NewClient("", "", nil), matrixflag.EvaluatorOptions{Environment: TestEnvironment}),
+		flags:     make(map[string]matrixflag.FeatureFlag),
+	}
+	td.evaluator.SetRuleset(td.ruleset())
+	return td
+}
+
+// Set sets a flag to serve value to every context. A bool flag is switched on
+// or off; other values, such as strings, numbers or JSON objects, are served as
+// the single variation of an active flag.
+func (td *TestDataSource) Set(flag string, value any) {
+	f := matrixflag.FeatureFlag{Name: flag}
+	if on, ok := value.(bool); ok {
+		f.IsActive = on
+	} else {
+		f.IsActive = true
+		f.Variations = []matrixflag.Variation{{Key: "value", Value: value, Weight: 1}}
+		f.Rules = []matrixflag.FlagRule{{ID: valueRule, Variation: "value"}}
+	}
+	td.SetFlag(f)
+}
+
+// SetFlag sets a flag with its full configuration, such as targeting rules or
+// a percentage rollout, which are evaluated as by an Evaluator. The flag keeps
+// its ID when it was set before, and is moved to TestEnvironment.
+func (td *TestDataSource) SetFlag(flag matrixflag.FeatureFlag) {
+	td.mu.Lock()
+	defer td.mu.Unlock()
+	if prev, ok := td.flags[flag.Name]; ok {
+		flag.ID = prev.ID
+	} else {
+		td.nextID++
+		flag.ID = td.nextID
+	}
+	flag.Environment = TestEnvironment
+	td.flags[flag.Name] = flag
+	td.evaluator.SetRuleset(td.ruleset())
+}
+
+// Delete removes a flag, so evaluations of it fail with FLAG_NOT_FOUND
+func (td *TestDataSource) Delete(flag string) {
+	td.mu.Lock()
+	defer td.mu.Unlock()
+	delete(td.flags, flag)
+	td.evaluator.SetRuleset(td.ruleset())
+}
+
+// ruleset returns the flags as a ruleset ordered by ID; td.mu must be held
+func (td *TestDataSource) ruleset() *matrixflag.Ruleset {
+	flags := make([]matrixflag.FeatureFlag, 0, len(td.flags))
+	for _, flag := range td.flags {
+		flags = append(flags, flag)
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].ID < flags[j].ID })
+	return &matrixflag.Ruleset{Environment: TestEnvironment, Flags: flags}
+}
+
+// Client creates a client served by the data source, in TestEnvironment. The
+// options are applied after those of the data source; a WithTracer option
+// stops evaluations from being recorded.
+func (td *TestDataSource) Client(opts ...matrixflag.ClientOption) *matrixflag.Client {
+	opts = append([]matrixflag.ClientOption{
+		matrixflag.WithRetries(0, 0, 0),
+		matrixflag.WithEnvironment(TestEnvironment),
+		matrixflag.WithTracer(evaluationRecorder{td}),
+		matrixflag.WithInterceptor(td.intercept),
+	}, opts...)
+	return matrixflag.NewClient("http://matrixflagtest.invalid", "test", nil, opts...)
+}
+
+// intercept answers an API request of a client from the data source
+func (td *TestDataSource) intercept(_ context.Context, req *http.Request, _ matrixflag.DoerFunc) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	td.serve(rec, req)
+	return rec.Result(), nil
+}
+
+// serve answers the API requests the data source supports
+func (td *TestDataSource) serve(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1")
+	switch {
+	case r.Method == http.MethodPost && path == "/feature-flags/evaluate":
+		td.serveEvaluation(w, r)
+	case r.Method == http.MethodPost && path == "/feature-flags/evaluate/all":
+		td.serveAllFlags(w, r)
+	case r.Method == http.MethodGet && path == "/feature-flags/":
+		td.serveFlags(w, r)
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/feature-flags/"):
+		id, err := strconv.Atoi(strings.TrimPrefix(path, "/feature-flags/"))
+		flag, ok := td.flagByID(id)
+		if err != nil || !ok {
+			writeError(w, http.StatusNotFound, "not_found", "flag not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, flag)
+	case r.Method == http.MethodGet && (path == "/layers/" || path == "/holdouts/" || path == "/targeting/segments"):
+		writeJSON(w, http.StatusOK, []any{})
+	default:
+		writeError(w, http.StatusNotImplemented, "not_implemented",
+			fmt.Sprintf("%s %s is not served by the test data source", r.Method, r.URL.Path))
+	}
+}
+
+// serveEvaluation evaluates a flag as the API does
+func (td *TestDataSource) serveEvaluation(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Flag    string                       `json:"flag"`
+		Context matrixflag.EvaluationContext `json:"context"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	if _, ok := td.flag(body.Flag); !ok {
+		writeError(w, http.StatusNotFound, "not_found", "flag not found: "+body.Flag)
+		return
+	}
+	writeJSON(w, http.StatusOK, td.evaluator.Evaluate(body.Flag, body.Context, nil))
+}
+
+// serveAllFlags evaluates every flag as the API does
+func (td *TestDataSource) serveAllFlags(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Context matrixflag.EvaluationContext `json:"context"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	td.mu.Lock()
+	all := td.ruleset().Flags
+	td.mu.Unlock()
+	flags := make(map[string]matrixflag.EvaluationDetail[any], len(all))
+	for _, flag := range all {
+		flags[flag.Name] = td.evaluator.Evaluate(flag.Name, body.Context, nil)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"flags": flags})
+}
+
+// serveFlags lists the flags selected by the supported filters, a page at a time
+func (td *TestDataSource) serveFlags(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	td.mu.Lock()
+	all := td.ruleset().Flags
+	td.mu.Unlock()
+	flags := []matrixflag.FeatureFlag{}
+	for _, flag := range all {
+		if env := query.Get("environment"); env != "" && env != flag.Environment {
+			continue
+		}
+		if active := query.Get("is_active"); active != "" && active != strconv.FormatBool(flag.IsActive) {
+			continue
+		}
+		if !strings.HasPrefix(flag.Name, query.Get("name_prefix")) {
+			continue
+		}
+		flags = append(flags, flag)
+	}
+	if page, err := strconv.Atoi(query.Get("page")); err == nil && page > 0 {
+		perPage, _ := strconv.Atoi(query.Get("per_page"))
+		if perPage <= 0 {
+			perPage = matrixflag.DefaultPerPage
+		}
+		start := min((page-1)*perPage, len(flags))
+		flags = flags[start:min(start+perPage, len(flags))]
+	}
+	writeJSON(w, http.StatusOK, flags)
+}
+
+// flag returns the flag with the given name
+func (td *TestDataSource) flag(name string) (matrixflag.FeatureFlag, bool) {
+	td.mu.Lock()
+	defer td.mu.Unlock()
+	flag, ok := td.flags[name]
+	return flag, ok
+}
+
+// flagByID returns the flag with the given ID
+func (td *TestDataSource) flagByID(id int) (matrixflag.FeatureFlag, bool) {
+	td.mu.Lock()
+	defer td.mu.Unlock()
+	for _, flag := range td.flags {
+		if flag.ID == id {
+			return flag, true
+		}
+	}
+	return matrixflag.FeatureFlag{}, false
+}
+
+// Evaluations returns the evaluations made by the clients of the data source
+// and their Evaluators, in order
+func (td *TestDataSource) Evaluations() []Evaluation {
+	td.mu.Lock()
+	defer td.mu.Unlock()
+	return append([]Evaluation(nil), td.evaluations...)
+}
+
+// Evaluated reports whether a flag has been evaluated
+func (td *TestDataSource) Evaluated(flag string) bool {
+	for _, e := range td.Evaluations() {
+		if e.Flag == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// ResetEvaluations forgets the recorded evaluations
+func (td *TestDataSource) ResetEvaluations() {
+	td.mu.Lock()
+	defer td.mu.Unlock()
+	td.evaluations = nil
+}
+
+// AssertEvaluated fails the test unless every flag has been evaluated
+func (td *TestDataSource) AssertEvaluated(t testing.TB, flags ...string) {
+	t.Helper()
+	for _, flag := range flags {
+		if !td.Evaluated(flag) {
+			t.Errorf("flag %s was not evaluated", flag)
+		}
+	}
+}
+
+// AssertNotEvaluated fails the test if any of the flags has been evaluated
+func (td *TestDataSource) AssertNotEvaluated(t testing.TB, flags ...string) {
+	t.Helper()
+	for _, flag := range flags {
+		if td.Evaluated(flag) {
+			t.Errorf("flag %s was evaluated", flag)
+		}
+	}
+}
+
+// evaluationRecorder is the tracer recording the evaluations of a data source's clients
+type evaluationRecorder struct {
+	td *TestDataSource
+}
+
+func (r evaluationRecorder) StartRequest(ctx context.Context, _ string, _ matrixflag.EndpointClass, _ string) (context.Context, func(int, error)) {
+	return ctx, func(int, error) {}
+}
+
+func (r evaluationRecorder) StartEvaluation(ctx context.Context, flagKey string) (context.Context, func(matrixflag.EvaluationDetail[any])) {
+	return ctx, func(d matrixflag.EvaluationDetail[any]) {
+		r.td.mu.Lock()
+		defer r.td.mu.Unlock()
+		r.td.evaluations = append(r.td.evaluations, Evaluation{Flag: flagKey, Detail: d})
+	}
+}
+
+// writeJSON writes v as a JSON response with the status code
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an API error response
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, matrixflag.APIError{Code: code, Message: message})
+}
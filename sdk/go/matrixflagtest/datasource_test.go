@@ -0,0 +1,77 @@
+package matrixflagtest
+
+import (
+	"context"
+	"testing"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestDataSource(t *testing.T) {
+	td := NewTestDataSource()
+	td.Set("new-checkout", true)
+	td.Set("banner", "spring-sale")
+	td.SetFlag(matrixflag.FeatureFlag{Name: "beta", IsActive: true, Rules: []matrixflag.FlagRule{{
+		ID:         "staff",
+		Conditions: []matrixflag.TargetingCondition{{Attribute: "email", Operator: matrixflag.OpContains, Value: "@example.com"}},
+	}}, Rollout: &matrixflag.PercentageRollout{Percentage: 0}})
+	client := td.Client()
+	ctx := context.Background()
+	user := matrixflag.NewContext("user-1")
+
+	assert.True(t, client.EvaluateBool(ctx, "new-checkout", user, false).Value)
+	banner := client.EvaluateString(ctx, "banner", user, "")
+	assert.Equal(t, "spring-sale", banner.Value)
+	assert.NoError(t, banner.Err)
+	assert.True(t, client.EvaluateBool(ctx, "beta", user.With("email", "ann@example.com"), false).Value)
+	assert.False(t, client.EvaluateBool(ctx, "beta", user.With("email", "bob@mail.test"), false).Value)
+
+	td.Set("new-checkout", false)
+	assert.False(t, client.EvaluateBool(ctx, "new-checkout", user, true).Value, "changes apply to the next call")
+	td.Delete("banner")
+	missing := client.EvaluateString(ctx, "banner", user, "none")
+	assert.Equal(t, "none", missing.Value)
+	assert.Equal(t, matrixflag.ErrorFlagNotFound, missing.ErrorCode)
+
+	td.AssertEvaluated(t, "new-checkout", "banner", "beta")
+	td.AssertNotEvaluated(t, "search")
+	assert.Len(t, td.Evaluations(), 6)
+	assert.Equal(t, Evaluation{Flag: "new-checkout", Detail: matrixflag.EvaluationDetail[any]{Value: true, Reason: matrixflag.ReasonDefault}}, td.Evaluations()[0])
+	td.ResetEvaluations()
+	assert.Empty(t, td.Evaluations())
+}
+
+func TestTestDataSourceServesFlags(t *testing.T) {
+	td := NewTestDataSource()
+	td.Set("new-checkout", true)
+	td.Set("search", false)
+	client := td.Client()
+	ctx := context.Background()
+
+	flags, err := client.ListFeatureFlags(ctx, matrixflag.FlagFilter{Environment: TestEnvironment})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new-checkout", "search"}, []string{flags[0].Name, flags[1].Name})
+	flag, err := client.GetFeatureFlagByName(ctx, "search", "")
+	require.NoError(t, err)
+	got, err := client.GetFeatureFlag(ctx, flag.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "search", got.Name)
+	all, err := client.ListAll(ctx, matrixflag.ListOptions{PerPage: 1})
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+	state, err := client.AllFlagsState(ctx, matrixflag.NewContext("user-1"), matrixflag.FlagsStateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"new-checkout": true, "search": false}, state.Values())
+
+	_, err = client.DeleteFeatureFlag(ctx, flag.ID)
+	assert.ErrorContains(t, err, "not served by the test data source")
+
+	evaluator := matrixflag.NewEvaluator(client, matrixflag.EvaluatorOptions{Environment: TestEnvironment})
+	require.NoError(t, evaluator.Sync(ctx))
+	on, err := evaluator.IsEnabled("new-checkout", matrixflag.NewContext("user-1"))
+	require.NoError(t, err)
+	assert.True(t, on)
+	td.AssertEvaluated(t, "new-checkout")
+}
@@ -0,0 +1,133 @@
+package matrixflagtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+// TestFlagStore checks that a matrixflag.FlagStore implementation behaves as the
+// interface describes. newStore must return a new, empty store on every call.
+//
+//	func TestStore(t *testing.T) {
+//		matrixflagtest.TestFlagStore(t, func() matrixflag.FlagStore { return NewStore() })
+//	}
+func TestFlagStore(t *testing.T, newStore func() matrixflag.FlagStore) {
+	ctx := context.Background()
+	flag := func(id int, name string, active bool) matrixflag.FeatureFlag {
+		return matrixflag.FeatureFlag{ID: id, Name: name, Environment: "production", IsActive: active}
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		store := newStore()
+		expectRuleset(t, store, nil)
+		if _, err := store.Get(ctx, "checkout"); !errors.Is(err, matrixflag.ErrFlagNotFound) {
+			t.Errorf("Get on an empty store returned %v, want ErrFlagNotFound", err)
+		}
+		if err := store.Upsert(ctx, flag(1, "checkout", true)); err != nil {
+			t.Fatalf("Upsert: %v", err)
+		}
+		expectRuleset(t, store, nil)
+		if err := store.Delete(ctx, "checkout"); err != nil {
+			t.Errorf("Delete on an empty store: %v", err)
+		}
+	})
+
+	t.Run("init", func(t *testing.T) {
+		store := newStore()
+		ruleset := &matrixflag.Ruleset{
+			Environment: "production",
+			Flags:       []matrixflag.FeatureFlag{flag(1, "checkout", true), flag(2, "search", false)},
+			Segments:    []matrixflag.Segment{{Name: "beta", Included: []string{"user-1"}}},
+		}
+		if err := store.Init(ctx, ruleset); err != nil {
+			t.Fatalf("Init: %v", err)
+		}
+		ruleset.Flags[0].IsActive = false
+		expectRuleset(t, store, []matrixflag.FeatureFlag{flag(1, "checkout", true), flag(2, "search", false)})
+		all, err := store.All(ctx)
+		if err != nil {
+			t.Fatalf("All: %v", err)
+		}
+		if all.Environment != "production" || len(all.Segments) != 1 {
+			t.Errorf("All returned environment %q and %d segments, want production and 1", all.Environment, len(all.Segments))
+		}
+
+		got, err := store.Get(ctx, "search")
+		if err != nil || got.ID != 2 {
+			t.Errorf("Get(search) = %v, %v, want flag 2", got, err)
+		}
+		if _, err := store.Get(ctx, "missing"); !errors.Is(err, matrixflag.ErrFlagNotFound) {
+			t.Errorf("Get of a missing flag returned %v, want ErrFlagNotFound", err)
+		}
+
+		if err := store.Init(ctx, &matrixflag.Ruleset{Environment: "production", Flags: []matrixflag.FeatureFlag{flag(3, "new", true)}}); err != nil {
+			t.Fatalf("Init: %v", err)
+		}
+		expectRuleset(t, store, []matrixflag.FeatureFlag{flag(3, "new", true)})
+	})
+
+	t.Run("upsert and delete", func(t *testing.T) {
+		store := newStore()
+		if err := store.Init(ctx, &matrixflag.Ruleset{Environment: "production", Flags: []matrixflag.FeatureFlag{flag(1, "checkout", false)}}); err != nil {
+			t.Fatalf("Init: %v", err)
+		}
+		for _, f := range []matrixflag.FeatureFlag{flag(1, "checkout", true), flag(2, "search", true)} {
+			if err := store.Upsert(ctx, f); err != nil {
+				t.Fatalf("Upsert(%s): %v", f.Name, err)
+			}
+		}
+		expectRuleset(t, store, []matrixflag.FeatureFlag{flag(1, "checkout", true), flag(2, "search", true)})
+
+		if err := store.Delete(ctx, "checkout"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if err := store.Delete(ctx, "missing"); err != nil {
+			t.Errorf("Delete of a missing flag: %v", err)
+		}
+		expectRuleset(t, store, []matrixflag.FeatureFlag{flag(2, "search", true)})
+	})
+
+	t.Run("all returns a copy", func(t *testing.T) {
+		store := newStore()
+		if err := store.Init(ctx, &matrixflag.Ruleset{Environment: "production", Flags: []matrixflag.FeatureFlag{flag(1, "checkout", true)}}); err != nil {
+			t.Fatalf("Init: %v", err)
+		}
+		all, err := store.All(ctx)
+		if err != nil {
+			t.Fatalf("All: %v", err)
+		}
+		all.Flags[0].IsActive = false
+		expectRuleset(t, store, []matrixflag.FeatureFlag{flag(1, "checkout", true)})
+	})
+}
+
+// expectRuleset checks the flags of the stored ruleset by ID, name and active
+// state, in order; nil flags expect an uninitialized store
+func expectRuleset(t *testing.T, store matrixflag.FlagStore, flags []matrixflag.FeatureFlag) {
+	t.Helper()
+	ruleset, err := store.All(context.Background())
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if flags == nil {
+		if ruleset != nil {
+			t.Errorf("All returned a ruleset with %d flags, want nil for an uninitialized store", len(ruleset.Flags))
+		}
+		return
+	}
+	if ruleset == nil {
+		t.Fatalf("All returned nil, want %d flags", len(flags))
+	}
+	if len(ruleset.Flags) != len(flags) {
+		t.Fatalf("All returned %d flags, want %d", len(ruleset.Flags), len(flags))
+	}
+	for i, want := range flags {
+		got := ruleset.Flags[i]
+		if got.ID != want.ID || got.Name != want.Name || got.IsActive != want.IsActive {
+			t.Errorf("flag %d is %d/%s (active %t), want %d/%s (active %t)", i, got.ID, got.Name, got.IsActive, want.ID, want.Name, want.IsActive)
+		}
+	}
+}
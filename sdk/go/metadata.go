@@ -0,0 +1,51 @@
+package matrixflag
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestIDHeader is the header carrying the ID of an API request
+const RequestIDHeader = "X-Request-ID"
+
+// ResponseMetadata describes the HTTP response to an API call
+type ResponseMetadata struct {
+	StatusCode int
+	Header     http.Header
+	// RequestID is the ID of the request, for correlating with server traces.
+	// It is the ID returned by the server, or else the one sent by the SDK.
+	RequestID string
+	// Duration is the total time of the call, including retries
+	Duration time.Duration
+	// Attempts is the number of attempts made, including the first
+	Attempts int
+}
+
+type responseMetadataKey struct{}
+
+// WithResponseMetadata returns a context that makes SDK calls made with it record
+// their response metadata into meta. The metadata of the last call is kept when
+// the context is reused, and is also recorded when the call fails with an API error.
+//
+//	var meta matrixflag.ResponseMetadata
+//	flag, err := client.GetFeatureFlag(matrixflag.WithResponseMetadata(ctx, &meta), 1)
+//	log.Printf("request %s took %s", meta.RequestID, meta.Duration)
+func WithResponseMetadata(ctx context.Context, meta *ResponseMetadata) context.Context {
+	return context.WithValue(ctx, responseMetadataKey{}, meta)
+}
+
+// recordResponseMetadata stores the metadata of a response into the context's target, if any
+func recordResponseMetadata(ctx context.Context, resp *http.Response, requestID string, duration time.Duration, attempts int) {
+	meta, ok := ctx.Value(responseMetadataKey{}).(*ResponseMetadata)
+	if !ok || meta == nil {
+		return
+	}
+	*meta = ResponseMetadata{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		RequestID:  requestID,
+		Duration:   duration,
+		Attempts:   attempts,
+	}
+}
@@ -0,0 +1,65 @@
+package matrixflag
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Metrics receives measurements of the SDK, so operators can alert on its
+// health. Implementations must be safe for concurrent use and return quickly;
+// promexporter.SDKMetrics records them as Prometheus metrics.
+type Metrics interface {
+	// RequestDone is called once per API call with the final status code, 0 when no response was received,
+	// and the duration including retries
+	RequestDone(method string, class EndpointClass, status int, duration time.Duration)
+	// RequestRetried is called before every retry of an API call
+	RequestRetried(method string, class EndpointClass)
+	// CacheLookup is called for every flag an Evaluator looks up in its local ruleset
+	CacheLookup(hit bool)
+	// FlagEvaluated is called for every evaluation of a Client or Evaluator
+	FlagEvaluated(flagKey string, reason EvaluationReason)
+	// StreamStatus is called when a flag stream connects or breaks
+	StreamStatus(connected bool)
+	// ExpiredFlagEvaluated is called for every local evaluation of a flag past its ExpiresAt
+	ExpiredFlagEvaluated(flagKey string)
+}
+
+// NopMetrics discards all measurements. Embed it to implement only part of Metrics.
+type NopMetrics struct{}
+
+func (NopMetrics) RequestDone(string, EndpointClass, int, time.Duration) {}
+func (NopMetrics) RequestRetried(string, EndpointClass)                  {}
+func (NopMetrics) CacheLookup(bool)                                      {}
+func (NopMetrics) FlagEvaluated(string, EvaluationReason)                {}
+func (NopMetrics) StreamStatus(bool)                                     {}
+func (NopMetrics) ExpiredFlagEvaluated(string)                           {}
+
+// WithMetrics sets the metrics hook of the client
+func WithMetrics(metrics Metrics) ClientOption {
+	return func(c *Client) {
+		c.metrics = metrics
+	}
+}
+
+// Metrics returns the metrics hook of the client, which discards everything unless set with WithMetrics
+func (c *Client) Metrics() Metrics {
+	if c.metrics == nil {
+		return NopMetrics{}
+	}
+	return c.metrics
+}
+
+// observeEvaluation counts and records an evaluation and logs it when it failed
+func (c *Client) observeEvaluation(ctx context.Context, environment, flagKey string, evalCtx EvaluationContext, d EvaluationDetail[any]) {
+	c.Metrics().FlagEvaluated(flagKey, d.Reason)
+	c.usage.record(flagKey, c.clock.Now())
+	c.recordEvaluation(environment, flagKey, evalCtx, d)
+	if d.Err != nil {
+		c.Logger().LogAttrs(ctx, slog.LevelWarn, "flag evaluation failed",
+			slog.String("flag", flagKey),
+			slog.String("error_code", string(d.ErrorCode)),
+			slog.Any("error", d.Err),
+		)
+	}
+}
@@ -0,0 +1,67 @@
+package matrixflag
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingMetrics keeps the stream statuses and request statuses it receives
+type recordingMetrics struct {
+	NopMetrics
+	mu       sync.Mutex
+	statuses []int
+	stream   []bool
+}
+
+func (m *recordingMetrics) RequestDone(_ string, _ EndpointClass, status int, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.statuses = append(m.statuses, status)
+}
+
+func (m *recordingMetrics) StreamStatus(connected bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stream = append(m.stream, connected)
+}
+
+func (m *recordingMetrics) streamStatuses() []bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]bool(nil), m.stream...)
+}
+
+func TestMetricsRequestStatus(t *testing.T) {
+	srv := newFakeServer(t)
+	metrics := &recordingMetrics{}
+	client := New("key", WithBaseURL(srv.URL), WithRetries(0, 0, 0), WithMetrics(metrics))
+
+	_, err := client.ListFeatureFlags(context.Background(), FlagFilter{})
+	require.NoError(t, err)
+	_, err = client.GetFeatureFlag(context.Background(), 42)
+	require.Error(t, err)
+	srv.Close()
+	_, err = client.ListFeatureFlags(context.Background(), FlagFilter{})
+	require.Error(t, err)
+	assert.Equal(t, []int{200, 404, 0}, metrics.statuses)
+}
+
+func TestMetricsStreamStatus(t *testing.T) {
+	srv := newFakeServer(t)
+	metrics := &recordingMetrics{}
+	client := New("key", WithBaseURL(srv.URL), WithMetrics(metrics))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := client.StreamFlags(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true}, metrics.streamStatuses())
+	cancel()
+	for range events {
+	}
+	assert.Equal(t, []bool{true, false}, metrics.streamStatuses())
+}
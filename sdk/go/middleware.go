@@ -0,0 +1,59 @@
+package matrixflag
+
+import (
+	"context"
+	"net/http"
+)
+
+// Doer sends an HTTP request and returns its response; *http.Client implements it
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc adapts an ordinary function to the Doer interface
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req)
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the Doer that sends API requests with custom behavior,
+// such as header injection, caching or fault injection
+type Middleware func(next Doer) Doer
+
+// ClientOption configures optional client behavior
+type ClientOption func(*Client)
+
+// WithRequestMiddleware adds middleware around every API request attempt.
+// Middleware runs in the order given, the first one being outermost, and
+// several options may be combined.
+func WithRequestMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// Interceptor intercepts every API request attempt, calling next to send the
+// request on; it may change the request, wrap the call, or answer without calling next
+type Interceptor func(ctx context.Context, req *http.Request, next DoerFunc) (*http.Response, error)
+
+// WithInterceptor adds an interceptor around every API request attempt, such
+// as for custom auth headers, logging or metrics. It is a Middleware in the
+// same chain as those of WithRequestMiddleware, in the order of the options.
+func WithInterceptor(interceptor Interceptor) ClientOption {
+	return WithRequestMiddleware(func(next Doer) Doer {
+		return DoerFunc(func(req *http.Request) (*http.Response, error) {
+			return interceptor(req.Context(), req, next.Do)
+		})
+	})
+}
+
+// buildDoer wraps the HTTP client with the configured middleware chain
+func (c *Client) buildDoer() Doer {
+	var doer Doer = c.httpClient
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		doer = c.middleware[i](doer)
+	}
+	return doer
+}
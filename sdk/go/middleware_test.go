@@ -0,0 +1,52 @@
+package matrixflag
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithInterceptor(t *testing.T) {
+	var tenants []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenants = append(tenants, r.Header.Get("X-Tenant"))
+		writeJSON(w, []FeatureFlag{})
+	}))
+	defer srv.Close()
+	var order []string
+	type key struct{}
+	client := NewClient(srv.URL, "key", nil,
+		WithInterceptor(func(ctx context.Context, req *http.Request, next DoerFunc) (*http.Response, error) {
+			order = append(order, "interceptor")
+			req.Header.Set("X-Tenant", ctx.Value(key{}).(string))
+			resp, err := next(req)
+			order = append(order, "interceptor done")
+			return resp, err
+		}),
+		WithRequestMiddleware(func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, "middleware")
+				return next.Do(req)
+			})
+		}),
+	)
+
+	_, err := client.ListFeatureFlags(context.WithValue(context.Background(), key{}, "acme"), FlagFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"acme"}, tenants, "the interceptor sees the context of the call")
+	assert.Equal(t, []string{"interceptor", "middleware", "interceptor done"}, order)
+
+	client = NewClient(srv.URL, "key", nil, WithInterceptor(func(ctx context.Context, req *http.Request, next DoerFunc) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		writeJSON(rec, []FeatureFlag{{ID: 7}})
+		return rec.Result(), nil
+	}))
+	flags, err := client.ListFeatureFlags(context.Background(), FlagFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, 7, flags[0].ID)
+	assert.Len(t, tenants, 1, "an interceptor can answer without sending the request")
+}
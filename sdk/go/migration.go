@@ -0,0 +1,202 @@
+package matrixflag
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// MigrationStage is a stage of a dual-read/dual-write migration
+type MigrationStage string
+
+// Migration stages, in order
+const (
+	// MigrationOff reads and writes the old store only
+	MigrationOff MigrationStage = "off"
+	// MigrationDualWrite writes both stores and reads the old one
+	MigrationDualWrite MigrationStage = "dualwrite"
+	// MigrationShadow writes both stores, reads both, compares and returns the old result
+	MigrationShadow MigrationStage = "shadow"
+	// MigrationLive writes both stores, reads both, compares and returns the new result
+	MigrationLive MigrationStage = "live"
+	// MigrationRampDown writes both stores and reads the new one
+	MigrationRampDown MigrationStage = "rampdown"
+	// MigrationComplete reads and writes the new store only
+	MigrationComplete MigrationStage = "complete"
+)
+
+// MetadataMigrationStage is the flag metadata key holding a migration stage
+const MetadataMigrationStage = "migration_stage"
+
+// MigrationOrigin identifies the store an operation ran against
+type MigrationOrigin string
+
+// Migration origins
+const (
+	MigrationOld MigrationOrigin = "old"
+	MigrationNew MigrationOrigin = "new"
+)
+
+// Valid reports whether s is a known migration stage
+func (s MigrationStage) Valid() bool {
+	switch s {
+	case MigrationOff, MigrationDualWrite, MigrationShadow, MigrationLive, MigrationRampDown, MigrationComplete:
+		return true
+	}
+	return false
+}
+
+// readsOld reports whether reads in this stage hit the old store
+func (s MigrationStage) readsOld() bool {
+	return s == MigrationOff || s == MigrationDualWrite || s == MigrationShadow || s == MigrationLive
+}
+
+// readsNew reports whether reads in this stage hit the new store
+func (s MigrationStage) readsNew() bool {
+	return s == MigrationShadow || s == MigrationLive || s == MigrationRampDown || s == MigrationComplete
+}
+
+// authoritative returns the store whose result is returned and whose writes must succeed
+func (s MigrationStage) authoritative() MigrationOrigin {
+	switch s {
+	case MigrationOff, MigrationDualWrite, MigrationShadow:
+		return MigrationOld
+	default:
+		return MigrationNew
+	}
+}
+
+// writesBoth reports whether writes in this stage hit both stores
+func (s MigrationStage) writesBoth() bool {
+	return s != MigrationOff && s != MigrationComplete
+}
+
+// MigrationStageFunc returns the current migration stage
+type MigrationStageFunc func(ctx context.Context) (MigrationStage, error)
+
+// FlagMigrationStage returns a stage function reading the "migration_stage" metadata of a flag
+func FlagMigrationStage(client *Client, flagID int) MigrationStageFunc {
+	return func(ctx context.Context) (MigrationStage, error) {
+		flag, err := client.GetFeatureFlag(ctx, flagID)
+		if err != nil {
+			return "", err
+		}
+		if !flag.IsActive {
+			return MigrationOff, nil
+		}
+		stage := MigrationStage(metadataString(flag.Metadata, MetadataMigrationStage))
+		if !stage.Valid() {
+			return "", fmt.Errorf("flag %d has invalid migration stage %q", flagID, stage)
+		}
+		return stage, nil
+	}
+}
+
+// MigrationOptions configures a Migration
+type MigrationOptions[T any] struct {
+	// DefaultStage is used when the stage cannot be determined, MigrationOff by default
+	DefaultStage MigrationStage
+	// Compare reports whether the old and new read results are consistent; reads are not compared when nil
+	Compare func(old, new T) bool
+	// OnInconsistency is called when Compare reports a mismatch
+	OnInconsistency func(ctx context.Context, stage MigrationStage, old, new T)
+	// OnError is called for errors that do not fail the operation: stage lookups and non-authoritative reads and writes
+	OnError func(ctx context.Context, origin MigrationOrigin, err error)
+	// Concurrent runs both reads in parallel in the shadow and live stages
+	Concurrent bool
+}
+
+// Migration implements the six-stage dual-read/dual-write migration pattern driven by a single flag
+type Migration[T any] struct {
+	stage MigrationStageFunc
+	opts  MigrationOptions[T]
+}
+
+// NewMigration creates a migration whose stage is returned by stage
+func NewMigration[T any](stage MigrationStageFunc, opts MigrationOptions[T]) *Migration[T] {
+	if !opts.DefaultStage.Valid() {
+		opts.DefaultStage = MigrationOff
+	}
+	return &Migration[T]{stage: stage, opts: opts}
+}
+
+// Stage returns the current stage, falling back to the default stage on error
+func (m *Migration[T]) Stage(ctx context.Context) MigrationStage {
+	stage, err := m.stage(ctx)
+	if err != nil || !stage.Valid() {
+		if err == nil {
+			err = fmt.Errorf("invalid migration stage %q", stage)
+		}
+		m.reportError(ctx, "", err)
+		return m.opts.DefaultStage
+	}
+	return stage
+}
+
+// Read reads from the stores required by the current stage and returns the authoritative result
+func (m *Migration[T]) Read(ctx context.Context, readOld, readNew func(context.Context) (T, error)) (T, error) {
+	stage := m.Stage(ctx)
+	if !stage.readsOld() {
+		return readNew(ctx)
+	}
+	if !stage.readsNew() {
+		return readOld(ctx)
+	}
+
+	var oldVal, newVal T
+	var oldErr, newErr error
+	if m.opts.Concurrent {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			newVal, newErr = readNew(ctx)
+		}()
+		oldVal, oldErr = readOld(ctx)
+		wg.Wait()
+	} else {
+		oldVal, oldErr = readOld(ctx)
+		newVal, newErr = readNew(ctx)
+	}
+
+	if oldErr == nil && newErr == nil && m.opts.Compare != nil && !m.opts.Compare(oldVal, newVal) && m.opts.OnInconsistency != nil {
+		m.opts.OnInconsistency(ctx, stage, oldVal, newVal)
+	}
+
+	if stage.authoritative() == MigrationNew {
+		if oldErr != nil {
+			m.reportError(ctx, MigrationOld, oldErr)
+		}
+		return newVal, newErr
+	}
+	if newErr != nil {
+		m.reportError(ctx, MigrationNew, newErr)
+	}
+	return oldVal, oldErr
+}
+
+// Write writes to the stores required by the current stage. The authoritative store is written
+// first; the other store is only written when that succeeds, and its errors are reported via OnError.
+func (m *Migration[T]) Write(ctx context.Context, writeOld, writeNew func(context.Context) (T, error)) (T, error) {
+	stage := m.Stage(ctx)
+	first, second, secondOrigin := writeOld, writeNew, MigrationNew
+	if stage.authoritative() == MigrationNew {
+		first, second, secondOrigin = writeNew, writeOld, MigrationOld
+	}
+
+	result, err := first(ctx)
+	if err != nil || !stage.writesBoth() {
+		return result, err
+	}
+	if _, err := second(ctx); err != nil {
+		m.reportError(ctx, secondOrigin, err)
+	}
+	return result, nil
+}
+
+// reportError passes a non-fatal error to OnError
+func (m *Migration[T]) reportError(ctx context.Context, origin MigrationOrigin, err error) {
+	if m.opts.OnError != nil {
+		m.opts.OnError(ctx, origin, err)
+	}
+}
@@ -0,0 +1,180 @@
+package matrixflag
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// migrationStores records the operations of a migration against fake old and new stores
+type migrationStores struct {
+	calls          []string
+	oldVal, newVal string
+	oldErr, newErr error
+}
+
+func (s *migrationStores) readOld(context.Context) (string, error) {
+	s.calls = append(s.calls, "read old")
+	return s.oldVal, s.oldErr
+}
+
+func (s *migrationStores) readNew(context.Context) (string, error) {
+	s.calls = append(s.calls, "read new")
+	return s.newVal, s.newErr
+}
+
+func (s *migrationStores) writeOld(context.Context) (string, error) {
+	s.calls = append(s.calls, "write old")
+	return s.oldVal, s.oldErr
+}
+
+func (s *migrationStores) writeNew(context.Context) (string, error) {
+	s.calls = append(s.calls, "write new")
+	return s.newVal, s.newErr
+}
+
+func fixedStage(stage MigrationStage) MigrationStageFunc {
+	return func(context.Context) (MigrationStage, error) { return stage, nil }
+}
+
+func TestMigrationStages(t *testing.T) {
+	tests := []struct {
+		stage      MigrationStage
+		reads      []string
+		read       string
+		writes     []string
+		write      string
+		consistent bool
+	}{
+		{stage: MigrationOff, reads: []string{"read old"}, read: "old", writes: []string{"write old"}, write: "old", consistent: true},
+		{stage: MigrationDualWrite, reads: []string{"read old"}, read: "old", writes: []string{"write old", "write new"}, write: "old", consistent: true},
+		{stage: MigrationShadow, reads: []string{"read old", "read new"}, read: "old", writes: []string{"write old", "write new"}, write: "old"},
+		{stage: MigrationLive, reads: []string{"read old", "read new"}, read: "new", writes: []string{"write new", "write old"}, write: "new"},
+		{stage: MigrationRampDown, reads: []string{"read new"}, read: "new", writes: []string{"write new", "write old"}, write: "new", consistent: true},
+		{stage: MigrationComplete, reads: []string{"read new"}, read: "new", writes: []string{"write new"}, write: "new", consistent: true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.stage), func(t *testing.T) {
+			var inconsistent []MigrationStage
+			m := NewMigration(fixedStage(tt.stage), MigrationOptions[string]{
+				Compare: func(old, new string) bool { return old == new },
+				OnInconsistency: func(_ context.Context, stage MigrationStage, old, new string) {
+					assert.Equal(t, "old", old)
+					assert.Equal(t, "new", new)
+					inconsistent = append(inconsistent, stage)
+				},
+			})
+			ctx := context.Background()
+
+			stores := &migrationStores{oldVal: "old", newVal: "new"}
+			got, err := m.Read(ctx, stores.readOld, stores.readNew)
+			require.NoError(t, err)
+			assert.Equal(t, tt.read, got)
+			assert.Equal(t, tt.reads, stores.calls)
+			if tt.consistent {
+				assert.Empty(t, inconsistent, "reads of a single store are not compared")
+			} else {
+				assert.Equal(t, []MigrationStage{tt.stage}, inconsistent)
+			}
+
+			stores.calls = nil
+			got, err = m.Write(ctx, stores.writeOld, stores.writeNew)
+			require.NoError(t, err)
+			assert.Equal(t, tt.write, got)
+			assert.Equal(t, tt.writes, stores.calls, "the authoritative store is written first")
+		})
+	}
+}
+
+func TestMigrationErrors(t *testing.T) {
+	ctx := context.Background()
+	type reported struct {
+		origin MigrationOrigin
+		err    string
+	}
+	var errs []reported
+	opts := MigrationOptions[string]{OnError: func(_ context.Context, origin MigrationOrigin, err error) {
+		errs = append(errs, reported{origin, err.Error()})
+	}}
+
+	t.Run("non-authoritative read", func(t *testing.T) {
+		errs = nil
+		stores := &migrationStores{oldVal: "old", newErr: errors.New("new store down")}
+		got, err := NewMigration(fixedStage(MigrationShadow), opts).Read(ctx, stores.readOld, stores.readNew)
+		require.NoError(t, err)
+		assert.Equal(t, "old", got)
+		assert.Equal(t, []reported{{MigrationNew, "new store down"}}, errs)
+	})
+
+	t.Run("authoritative read", func(t *testing.T) {
+		errs = nil
+		stores := &migrationStores{oldVal: "old", newErr: errors.New("new store down")}
+		_, err := NewMigration(fixedStage(MigrationLive), opts).Read(ctx, stores.readOld, stores.readNew)
+		assert.EqualError(t, err, "new store down")
+		assert.Empty(t, errs)
+	})
+
+	t.Run("authoritative write", func(t *testing.T) {
+		errs = nil
+		stores := &migrationStores{oldErr: errors.New("old store down")}
+		_, err := NewMigration(fixedStage(MigrationDualWrite), opts).Write(ctx, stores.writeOld, stores.writeNew)
+		assert.EqualError(t, err, "old store down")
+		assert.Equal(t, []string{"write old"}, stores.calls, "the second store is not written when the first fails")
+	})
+
+	t.Run("non-authoritative write", func(t *testing.T) {
+		errs = nil
+		stores := &migrationStores{newVal: "new", oldErr: errors.New("old store down")}
+		got, err := NewMigration(fixedStage(MigrationRampDown), opts).Write(ctx, stores.writeOld, stores.writeNew)
+		require.NoError(t, err)
+		assert.Equal(t, "new", got)
+		assert.Equal(t, []reported{{MigrationOld, "old store down"}}, errs)
+	})
+
+	t.Run("stage lookup", func(t *testing.T) {
+		errs = nil
+		failing := func(context.Context) (MigrationStage, error) { return "", errors.New("flag unavailable") }
+		defaultOpts := opts
+		defaultOpts.DefaultStage = MigrationDualWrite
+		assert.Equal(t, MigrationDualWrite, NewMigration(failing, defaultOpts).Stage(ctx))
+		assert.Equal(t, MigrationOff, NewMigration(fixedStage("sideways"), opts).Stage(ctx))
+		assert.Equal(t, []reported{{"", "flag unavailable"}, {"", `invalid migration stage "sideways"`}}, errs)
+	})
+}
+
+func TestMigrationConcurrentRead(t *testing.T) {
+	m := NewMigration(fixedStage(MigrationLive), MigrationOptions[string]{Concurrent: true})
+	started := make(chan struct{})
+	readOld := func(context.Context) (string, error) {
+		<-started
+		return "old", nil
+	}
+	readNew := func(context.Context) (string, error) {
+		close(started)
+		return "new", nil
+	}
+	got, err := m.Read(context.Background(), readOld, readNew)
+	require.NoError(t, err)
+	assert.Equal(t, "new", got, "both stores are read in parallel")
+}
+
+func TestFlagMigrationStage(t *testing.T) {
+	srv := newFakeServer(t)
+	migrating := srv.addFlag(FeatureFlag{Name: "orders-db", IsActive: true, Metadata: map[string]any{MetadataMigrationStage: "shadow"}})
+	inactive := srv.addFlag(FeatureFlag{Name: "users-db", Metadata: map[string]any{MetadataMigrationStage: "complete"}})
+	invalid := srv.addFlag(FeatureFlag{Name: "events-db", IsActive: true, Metadata: map[string]any{MetadataMigrationStage: "sideways"}})
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+
+	stage, err := FlagMigrationStage(client, migrating.ID)(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, MigrationShadow, stage)
+	stage, err = FlagMigrationStage(client, inactive.ID)(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, MigrationOff, stage, "inactive flags turn the migration off")
+	_, err = FlagMigrationStage(client, invalid.ID)(ctx)
+	assert.EqualError(t, err, `flag 3 has invalid migration stage "sideways"`)
+}
@@ -0,0 +1,100 @@
+package matrixflag
+
+import "strings"
+
+// NamespaceSeparator separates the namespace from the flag name in a stored flag key
+const NamespaceSeparator = "."
+
+// namespacePrefix returns the prefix applied to flag names, or "" when the client has no namespace
+func (c *Client) namespacePrefix() string {
+	if c.config.Namespace == "" {
+		return ""
+	}
+	return c.config.Namespace + NamespaceSeparator
+}
+
+// qualifyName adds the client's namespace prefix to a flag name
+func (c *Client) qualifyName(name string) string {
+	if name == "" {
+		return name
+	}
+	return c.namespacePrefix() + name
+}
+
+// qualifyCreate returns flag with its name and prerequisites qualified by the client's namespace
+func (c *Client) qualifyCreate(flag FeatureFlagCreate) FeatureFlagCreate {
+	flag.Name = c.qualifyName(flag.Name)
+	flag.Prerequisites = c.qualifyPrerequisites(flag.Prerequisites)
+	return flag
+}
+
+// qualifyUpdate returns update with its name and prerequisites qualified by the client's namespace
+func (c *Client) qualifyUpdate(update FeatureFlagUpdate) FeatureFlagUpdate {
+	update.Name = c.qualifyName(update.Name)
+	update.Prerequisites = c.qualifyPrerequisites(update.Prerequisites)
+	return update
+}
+
+// qualifyPrerequisites returns a copy of prerequisites with their flag names qualified by the client's namespace
+func (c *Client) qualifyPrerequisites(prerequisites []Prerequisite) []Prerequisite {
+	if c.namespacePrefix() == "" || len(prerequisites) == 0 {
+		return prerequisites
+	}
+	qualified := make([]Prerequisite, len(prerequisites))
+	for i, p := range prerequisites {
+		p.Flag = c.qualifyName(p.Flag)
+		qualified[i] = p
+	}
+	return qualified
+}
+
+// unqualifyPrerequisites strips the client's namespace prefix from the flag names of decoded prerequisites
+func (c *Client) unqualifyPrerequisites(prerequisites []Prerequisite) {
+	for i := range prerequisites {
+		prerequisites[i].Flag = c.unqualifyName(prerequisites[i].Flag)
+	}
+}
+
+// qualifyFilter returns the list filter with its name prefix qualified by the client's namespace
+func (c *Client) qualifyFilter(filter FlagFilter) FlagFilter {
+	filter.NamePrefix = c.qualifyName(filter.NamePrefix)
+	return filter
+}
+
+// unqualifyName strips the client's namespace prefix from a flag name
+func (c *Client) unqualifyName(name string) string {
+	if prefix := c.namespacePrefix(); prefix != "" {
+		return strings.TrimPrefix(name, prefix)
+	}
+	return name
+}
+
+// unqualifyFlag strips the client's namespace prefix from a flag's name.
+// It reports false when the flag belongs to another namespace.
+func (c *Client) unqualifyFlag(flag *FeatureFlag) bool {
+	prefix := c.namespacePrefix()
+	if prefix == "" {
+		return true
+	}
+	name, ok := strings.CutPrefix(flag.Name, prefix)
+	if !ok {
+		return false
+	}
+	flag.Name = name
+	c.unqualifyPrerequisites(flag.Prerequisites)
+	return true
+}
+
+// unqualifyFlags strips the client's namespace prefix from flag names and drops flags of other namespaces
+func (c *Client) unqualifyFlags(flags []FeatureFlag) []FeatureFlag {
+	if c.namespacePrefix() == "" {
+		return flags
+	}
+	kept := flags[:0]
+	for _, flag := range flags {
+		if c.unqualifyFlag(&flag) {
+			kept = append(kept, flag)
+		}
+	}
+	return kept
+}
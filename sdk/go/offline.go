@@ -0,0 +1,325 @@
+package matrixflag
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OfflineKind is the kind of offline flag files
+const OfflineKind = "OfflineFlags"
+
+// OfflineSchemaV1 is the JSON schema of the v1 offline flag file format
+//
+//go:embed schema/offline.v1.json
+var OfflineSchemaV1 []byte
+
+// ErrOffline is returned by API calls of a client in offline mode
+var ErrOffline = errors.New("client is in offline mode")
+
+// OfflineFlags is an offline flag file, served by clients in offline mode. It
+// holds the ruleset of one environment, with flags, layers, holdouts and
+// segments in the same fields as the API, and is written as YAML or JSON:
+//
+//	apiVersion: matrixflag.io/v1
+//	kind: OfflineFlags
+//	environment: production
+//	flags:
+//	  - id: 1
+//	    name: new-checkout
+//	    is_active: true
+//	    rollout: {percentage: 25}
+type OfflineFlags struct {
+	APIVersion  string        `json:"apiVersion"`
+	Kind        string        `json:"kind"`
+	Environment string        `json:"environment"`
+	Flags       []FeatureFlag `json:"flags,omitempty"`
+	Layers      []Layer       `json:"layers,omitempty"`
+	Holdouts    []Holdout     `json:"holdouts,omitempty"`
+	Segments    []Segment     `json:"segments,omitempty"`
+	// ProjectDefaults and EnvironmentDefaults are the inherited defaults of the file's flags, see Ruleset
+	ProjectDefaults     map[int]FlagDefaults `json:"project_defaults,omitempty"`
+	EnvironmentDefaults map[int]FlagDefaults `json:"environment_defaults,omitempty"`
+}
+
+// OfflineFlagsError is returned when an offline flag file fails validation
+type OfflineFlagsError struct {
+	Problems []ManifestProblem
+}
+
+func (e *OfflineFlagsError) Error() string {
+	msgs := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		msgs[i] = p.Path + ": " + p.Message
+	}
+	return "invalid offline flag file: " + strings.Join(msgs, "; ")
+}
+
+// NewOfflineFlags creates an offline flag file from a ruleset, such as one
+// fetched with FetchRuleset, to be written with Write
+func NewOfflineFlags(ruleset *Ruleset) *OfflineFlags {
+	return &OfflineFlags{
+		APIVersion:  ManifestAPIVersionV1,
+		Kind:        OfflineKind,
+		Environment: ruleset.Environment,
+		Flags:       ruleset.Flags,
+		Layers:      ruleset.Layers,
+		Holdouts:    ruleset.Holdouts,
+		Segments:    ruleset.Segments,
+
+		ProjectDefaults:     ruleset.ProjectDefaults,
+		EnvironmentDefaults: ruleset.EnvironmentDefaults,
+	}
+}
+
+// LoadOfflineFlags decodes and validates a YAML or JSON offline flag file
+func LoadOfflineFlags(r io.Reader) (*OfflineFlags, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read offline flag file: %w", err)
+	}
+
+	// YAML is decoded through JSON, so both formats share the API's field names
+	var doc any
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to decode offline flag file: empty document")
+		}
+		return nil, fmt.Errorf("failed to decode offline flag file: %w", err)
+	}
+	jsonData, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode offline flag file: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(jsonData))
+	dec.DisallowUnknownFields()
+
+	var f OfflineFlags
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("failed to decode offline flag file: %w", err)
+	}
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// LoadOfflineFlagsFile loads and validates an offline flag file from a path
+func LoadOfflineFlagsFile(path string) (*OfflineFlags, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open offline flag file: %w", err)
+	}
+	defer file.Close()
+
+	f, err := LoadOfflineFlags(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Validate checks the file and returns an *OfflineFlagsError listing every problem
+func (f *OfflineFlags) Validate() error {
+	var problems []ManifestProblem
+	add := func(path string, err error) {
+		problems = append(problems, ManifestProblem{Path: path, Message: err.Error()})
+	}
+
+	switch f.APIVersion {
+	case ManifestAPIVersionV1:
+	case "":
+		add("apiVersion", errors.New("is required"))
+	default:
+		add("apiVersion", fmt.Errorf("unsupported version %q (supported: %s)", f.APIVersion, ManifestAPIVersionV1))
+	}
+	if f.Kind != OfflineKind {
+		add("kind", fmt.Errorf("must be %q", OfflineKind))
+	}
+	if f.Environment == "" {
+		add("environment", errors.New("is required"))
+	}
+
+	seen := make(map[string]int, len(f.Flags))
+	for i, flag := range f.Flags {
+		path := fmt.Sprintf("flags[%d]", i)
+		switch {
+		case flag.Name == "":
+			add(path+".name", errors.New("is required"))
+		case !flagNamePattern.MatchString(flag.Name) || len(flag.Name) > maxFlagNameLength:
+			add(path+".name", fmt.Errorf("%q is not a valid flag name", flag.Name))
+		}
+		if j, ok := seen[flag.Name]; ok && flag.Name != "" {
+			add(path, fmt.Errorf("duplicates flags[%d] (%s)", j, flag.Name))
+		} else {
+			seen[flag.Name] = i
+		}
+		unknown := make([]string, 0, len(flag.Extra))
+		for field := range flag.Extra {
+			unknown = append(unknown, field)
+		}
+		sort.Strings(unknown)
+		for _, field := range unknown {
+			add(path+"."+field, errors.New("is not a known flag field"))
+		}
+		if flag.Environment != "" && flag.Environment != f.Environment {
+			add(path+".environment", fmt.Errorf("%q differs from the file's environment %q", flag.Environment, f.Environment))
+		}
+		if err := validateTags(flag.Tags); err != nil {
+			add(path+".tags", err)
+		}
+		if flag.Lifecycle != "" {
+			if err := flag.Lifecycle.Validate(); err != nil {
+				add(path+".lifecycle", err)
+			}
+		}
+		if flag.Rollout != nil {
+			if err := flag.Rollout.Validate(); err != nil {
+				add(path+".rollout", err)
+			}
+		}
+		if flag.TrafficAllocation != nil {
+			if err := flag.TrafficAllocation.Validate(); err != nil {
+				add(path+".traffic_allocation", err)
+			}
+		}
+		if err := validateBucketBy(flag.Rollout, flag.TrafficAllocation); err != nil {
+			add(path+".rollout.bucket_by", err)
+		}
+		if err := validateFlagVariations(flag.Variations, flag.OffVariation); err != nil {
+			add(path+".variations", err)
+		}
+		if err := validateFlagRules(flag.Rules, flag.Variations); err != nil {
+			add(path+".rules", err)
+		}
+	}
+	if err := ValidatePrerequisites(f.Flags); err != nil {
+		add("flags", err)
+	}
+	for i, layer := range f.Layers {
+		if err := ValidateLayerAllocations(layer.Allocations); err != nil {
+			add(fmt.Sprintf("layers[%d].allocations", i), err)
+		}
+	}
+	for i, segment := range f.Segments {
+		if segment.Name == "" {
+			add(fmt.Sprintf("segments[%d].name", i), errors.New("is required"))
+		}
+		if err := ValidateSegmentRules(segment.Rules); err != nil {
+			add(fmt.Sprintf("segments[%d].rules", i), err)
+		}
+	}
+	for _, level := range []struct {
+		name     string
+		defaults map[int]FlagDefaults
+	}{{"project_defaults", f.ProjectDefaults}, {"environment_defaults", f.EnvironmentDefaults}} {
+		ids := make([]int, 0, len(level.defaults))
+		for id := range level.defaults {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+		for _, id := range ids {
+			if err := level.defaults[id].validate(); err != nil {
+				add(fmt.Sprintf("%s.%d", level.name, id), err)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &OfflineFlagsError{Problems: problems}
+	}
+	return nil
+}
+
+// Ruleset returns the ruleset of the file
+func (f *OfflineFlags) Ruleset() *Ruleset {
+	return &Ruleset{
+		Environment: f.Environment,
+		Flags:       f.Flags,
+		Layers:      f.Layers,
+		Holdouts:    f.Holdouts,
+		Segments:    f.Segments,
+
+		ProjectDefaults:     f.ProjectDefaults,
+		EnvironmentDefaults: f.EnvironmentDefaults,
+	}
+}
+
+// Write writes the file to w as indented JSON
+func (f *OfflineFlags) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(f); err != nil {
+		return fmt.Errorf("failed to encode offline flag file: %w", err)
+	}
+	return nil
+}
+
+// offlineRuleset returns the ruleset of the client's offline file, or the error loading it
+func (c *Client) offlineRuleset() (*indexedRuleset, error) {
+	if c.offline.err != nil {
+		return nil, c.offline.err
+	}
+	return c.offline.ruleset, nil
+}
+
+// offlineState is the offline flag file loaded by a client
+type offlineState struct {
+	ruleset *indexedRuleset
+	err     error
+}
+
+// loadOffline loads the client's offline flag file
+func (c *Client) loadOffline() {
+	f, err := LoadOfflineFlagsFile(c.config.OfflineFile)
+	if err != nil {
+		c.offline.err = fmt.Errorf("%w: %w", ErrOffline, err)
+		return
+	}
+	if env := c.config.Environment; env != "" && f.Environment != env {
+		c.offline.err = fmt.Errorf("%w: %s: the offline flag file holds environment %s, not the configured %s", ErrOffline, c.config.OfflineFile, f.Environment, env)
+		return
+	}
+	ruleset := f.Ruleset()
+	ruleset.SyncedAt = c.clock.Now()
+	c.offline.ruleset = ruleset.index()
+}
+
+// evaluateOffline evaluates a flag from the offline flag file
+func (c *Client) evaluateOffline(flagKey string, evalCtx EvaluationContext, defaultValue any) EvaluationDetail[any] {
+	ix, err := c.offlineRuleset()
+	if err != nil {
+		return errorDetail(defaultValue, err)
+	}
+	flag, ok := ix.flags[flagKey]
+	if !ok {
+		return errorDetail(defaultValue, fmt.Errorf("%w: %s", ErrFlagNotFound, flagKey))
+	}
+	c.observeExpiry(flag, c.clock.Now())
+	d := ix.evaluate(flag, evalCtx)
+	if d.Reason == ReasonError {
+		return errorDetail(defaultValue, d.Err)
+	}
+	return d
+}
+
+// offlineError returns the error of an API request made in offline mode
+func (c *Client) offlineError(req request) error {
+	if _, err := c.offlineRuleset(); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %s %s is not available", ErrOffline, req.method, req.path)
+}
+
+// isOffline reports whether the client serves flags from an offline file
+func (c *Client) isOffline() bool {
+	return c.config.OfflineFile != ""
+}
@@ -0,0 +1,57 @@
+package matrixflag
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeOfflineFile writes ruleset as an offline flag file and returns its path
+func writeOfflineFile(t *testing.T, ruleset *Ruleset) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "flags.json")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	require.NoError(t, NewOfflineFlags(ruleset).Write(f))
+	return path
+}
+
+func TestOfflineFetchRulesetCopies(t *testing.T) {
+	path := writeOfflineFile(t, &Ruleset{
+		Environment: "production",
+		Flags:       []FeatureFlag{{ID: 1, Name: "checkout", IsActive: true, Rollout: &PercentageRollout{Percentage: 50}}},
+		Segments:    []Segment{{Name: "beta", Included: []string{"user-1"}}},
+	})
+	client := NewClient("", "", &Config{OfflineFile: path})
+	ctx := context.Background()
+
+	ruleset, err := client.FetchRuleset(ctx, "production")
+	require.NoError(t, err)
+	ruleset.Flags[0].Rollout.Percentage = 0
+	ruleset.Flags[0].IsActive = false
+	ruleset.Segments[0].Included[0] = "user-2"
+
+	again, err := client.FetchRuleset(ctx, "production")
+	require.NoError(t, err)
+	assert.True(t, again.Flags[0].IsActive)
+	assert.Equal(t, 50.0, again.Flags[0].Rollout.Percentage)
+	assert.Equal(t, []string{"user-1"}, again.Segments[0].Included)
+}
+
+func TestOfflineFileEnvironmentMismatch(t *testing.T) {
+	path := writeOfflineFile(t, &Ruleset{Environment: "staging", Flags: []FeatureFlag{{ID: 1, Name: "checkout", IsActive: true}}})
+
+	client := NewClient("", "", &Config{OfflineFile: path, Environment: "production"})
+	_, err := client.FetchRuleset(context.Background(), "staging")
+	assert.ErrorIs(t, err, ErrOffline)
+	assert.ErrorContains(t, err, "holds environment staging, not the configured production")
+
+	client = NewClient("", "", &Config{OfflineFile: path, Environment: "staging"})
+	_, err = client.FetchRuleset(context.Background(), "staging")
+	assert.NoError(t, err)
+}
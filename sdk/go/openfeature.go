@@ -0,0 +1,195 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// OpenFeatureSchema is the JSON schema URL of the flagd flag definition format
+const OpenFeatureSchema = "https://flagd.dev/schema/v0/flags.json"
+
+// OpenFeature flag states
+const (
+	OpenFeatureStateEnabled  = "ENABLED"
+	OpenFeatureStateDisabled = "DISABLED"
+)
+
+// OpenFeatureDocument represents a set of flag definitions in the OpenFeature/flagd format
+type OpenFeatureDocument struct {
+	Schema string                     `json:"$schema,omitempty"`
+	Flags  map[string]OpenFeatureFlag `json:"flags"`
+}
+
+// OpenFeatureFlag represents a single flagd flag definition
+type OpenFeatureFlag struct {
+	State          string         `json:"state"`
+	Variants       map[string]any `json:"variants"`
+	DefaultVariant string         `json:"defaultVariant"`
+	Targeting      map[string]any `json:"targeting,omitempty"`
+	Metadata       map[string]any `json:"metadata,omitempty"`
+}
+
+// OpenFeatureOffVariant is the variant exported for the false value served by
+// flags without an off variation
+const OpenFeatureOffVariant = "off"
+
+// NewOpenFeatureDocument converts feature flags into an OpenFeature flag definition document.
+// Flags are keyed by name, so the flags should belong to a single environment.
+// Each flag serves the same values as the Evaluator: off flags serve their off
+// variation, and rollouts, traffic allocations and variation weights become a
+// fractional targeting rule with the same shares. flagd buckets with its own
+// hash, so the shares match but not which contexts fall in them. Flags with
+// targeting rules or in an experiment layer cannot be expressed and fail the
+// conversion.
+func NewOpenFeatureDocument(flags []FeatureFlag) (*OpenFeatureDocument, error) {
+	doc := &OpenFeatureDocument{
+		Schema: OpenFeatureSchema,
+		Flags:  make(map[string]OpenFeatureFlag, len(flags)),
+	}
+	var problems []string
+	for _, flag := range flags {
+		f, err := openFeatureFlag(flag)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		doc.Flags[flag.Name] = f
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("failed to export flags to OpenFeature: %s", strings.Join(problems, "; "))
+	}
+	return doc, nil
+}
+
+// openFeatureFlag converts a feature flag into a flagd definition, boolean unless the flag has variations
+func openFeatureFlag(flag FeatureFlag) (OpenFeatureFlag, error) {
+	if len(flag.Rules) > 0 {
+		return OpenFeatureFlag{}, fmt.Errorf("flag %s has targeting rules", flag.Name)
+	}
+	if len(flag.Prerequisites) > 0 {
+		return OpenFeatureFlag{}, fmt.Errorf("flag %s has prerequisites", flag.Name)
+	}
+	if flag.LayerID != 0 {
+		return OpenFeatureFlag{}, fmt.Errorf("flag %s is in experiment layer %d", flag.Name, flag.LayerID)
+	}
+
+	// The off variant serves what offDetail serves: the off variation, or false
+	variants := make(map[string]any, len(flag.Variations)+2)
+	offVariant := offDetail(&flag, ReasonOff).Variation
+	if offVariant == "" {
+		if hasVariation(flag.Variations, OpenFeatureOffVariant) {
+			return OpenFeatureFlag{}, fmt.Errorf("flag %s has a variation named %s but no off variation", flag.Name, OpenFeatureOffVariant)
+		}
+		offVariant = OpenFeatureOffVariant
+		variants[OpenFeatureOffVariant] = false
+	}
+
+	// shares are the percentages of contexts served each variant while the flag is active
+	share := 100.0
+	if flag.TrafficAllocation != nil {
+		share = share * flag.TrafficAllocation.Percentage / 100
+	}
+	if flag.Rollout != nil {
+		share = share * flag.Rollout.Percentage / 100
+	}
+	var shares []openFeatureShare
+	if len(flag.Variations) == 0 {
+		variants["on"] = true
+		shares = append(shares, openFeatureShare{"on", share})
+	} else {
+		total := 0.0
+		for _, v := range flag.Variations {
+			variants[v.Key] = v.Value
+			total += v.Weight
+		}
+		for _, v := range flag.Variations {
+			if v.Weight > 0 {
+				shares = append(shares, openFeatureShare{v.Key, share * v.Weight / total})
+			}
+		}
+	}
+
+	defaultVariant := offVariant
+	var targeting map[string]any
+	switch {
+	case !flag.IsActive:
+	case len(shares) == 1 && share == 100:
+		defaultVariant = shares[0].variant
+	default:
+		shares = append(shares, openFeatureShare{offVariant, 100 - share})
+		targeting = map[string]any{"fractional": openFeatureFractional(flag.bucketBy(), shares)}
+	}
+
+	metadata := map[string]any{
+		"id":          flag.ID,
+		"environment": flag.Environment,
+	}
+	if flag.Description != "" {
+		metadata["description"] = flag.Description
+	}
+	if flag.ProjectID != 0 {
+		metadata["projectId"] = flag.ProjectID
+	}
+
+	return OpenFeatureFlag{
+		State:          OpenFeatureStateEnabled,
+		Variants:       variants,
+		DefaultVariant: defaultVariant,
+		Targeting:      targeting,
+		Metadata:       metadata,
+	}, nil
+}
+
+// openFeatureShare is the percentage of contexts served a variant
+type openFeatureShare struct {
+	variant string
+	percent float64
+}
+
+// openFeatureFractional returns the arguments of a fractional rule bucketing on
+// bucketBy. Shares of the same variant are merged, and percentages become
+// integer weights in hundredths of a percent.
+func openFeatureFractional(bucketBy string, shares []openFeatureShare) []any {
+	var args []any
+	if bucketBy != DefaultBucketBy {
+		args = append(args, map[string]any{"var": bucketBy})
+	}
+	weights := make(map[string]int, len(shares))
+	var order []string
+	for _, s := range shares {
+		if _, ok := weights[s.variant]; !ok {
+			order = append(order, s.variant)
+		}
+		weights[s.variant] += int(math.Round(s.percent * 100))
+	}
+	for _, variant := range order {
+		if weights[variant] > 0 {
+			args = append(args, []any{variant, weights[variant]})
+		}
+	}
+	return args
+}
+
+// Write writes the document to w as indented JSON
+func (d *OpenFeatureDocument) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(d); err != nil {
+		return fmt.Errorf("failed to encode OpenFeature document: %w", err)
+	}
+	return nil
+}
+
+// ExportOpenFeature exports the flags of an environment as an OpenFeature flag definition document
+func (c *Client) ExportOpenFeature(ctx context.Context, environment string, reqOpts ...RequestOption) (*OpenFeatureDocument, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	flags, err := c.listFeatureFlags(ctx, FlagFilter{Environment: environment}, EndpointExport)
+	if err != nil {
+		return nil, err
+	}
+	return NewOpenFeatureDocument(flags)
+}
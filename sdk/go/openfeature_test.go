@@ -0,0 +1,117 @@
+package matrixflag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenFeatureFlag(t *testing.T) {
+	variations := []Variation{
+		{Key: "control", Value: "blue", Weight: 3},
+		{Key: "treatment", Value: "green", Weight: 1},
+	}
+	tests := []struct {
+		name           string
+		flag           FeatureFlag
+		defaultVariant string
+		variants       map[string]any
+		fractional     []any
+	}{
+		{
+			name:           "active boolean",
+			flag:           FeatureFlag{Name: "f", IsActive: true},
+			defaultVariant: "on",
+			variants:       map[string]any{"on": true, "off": false},
+		},
+		{
+			name:           "inactive boolean",
+			flag:           FeatureFlag{Name: "f"},
+			defaultVariant: "off",
+			variants:       map[string]any{"on": true, "off": false},
+		},
+		{
+			name:           "rollout",
+			flag:           FeatureFlag{Name: "f", IsActive: true, Rollout: &PercentageRollout{Percentage: 10}},
+			defaultVariant: "off",
+			variants:       map[string]any{"on": true, "off": false},
+			fractional:     []any{[]any{"on", 1000}, []any{"off", 9000}},
+		},
+		{
+			name: "rollout within traffic allocation by organization",
+			flag: FeatureFlag{
+				Name:              "f",
+				IsActive:          true,
+				Rollout:           &PercentageRollout{Percentage: 50, BucketBy: "org"},
+				TrafficAllocation: &TrafficAllocation{Percentage: 20},
+			},
+			defaultVariant: "off",
+			variants:       map[string]any{"on": true, "off": false},
+			fractional:     []any{map[string]any{"var": "org"}, []any{"on", 1000}, []any{"off", 9000}},
+		},
+		{
+			name:           "inactive multivariate without off variation",
+			flag:           FeatureFlag{Name: "f", Variations: variations},
+			defaultVariant: "off",
+			variants:       map[string]any{"control": "blue", "treatment": "green", "off": false},
+		},
+		{
+			name:           "inactive multivariate",
+			flag:           FeatureFlag{Name: "f", Variations: variations, OffVariation: "control"},
+			defaultVariant: "control",
+			variants:       map[string]any{"control": "blue", "treatment": "green"},
+		},
+		{
+			name:           "active multivariate",
+			flag:           FeatureFlag{Name: "f", IsActive: true, Variations: variations},
+			defaultVariant: "off",
+			variants:       map[string]any{"control": "blue", "treatment": "green", "off": false},
+			fractional:     []any{[]any{"control", 7500}, []any{"treatment", 2500}},
+		},
+		{
+			name:           "multivariate rollout serving the off variation",
+			flag:           FeatureFlag{Name: "f", IsActive: true, Variations: variations, OffVariation: "control", Rollout: &PercentageRollout{Percentage: 40}},
+			defaultVariant: "control",
+			variants:       map[string]any{"control": "blue", "treatment": "green"},
+			fractional:     []any{[]any{"control", 9000}, []any{"treatment", 1000}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := openFeatureFlag(tt.flag)
+			require.NoError(t, err)
+			assert.Equal(t, tt.defaultVariant, f.DefaultVariant)
+			assert.Equal(t, tt.variants, f.Variants)
+			if tt.fractional == nil {
+				assert.Nil(t, f.Targeting)
+			} else {
+				assert.Equal(t, map[string]any{"fractional": tt.fractional}, f.Targeting)
+			}
+		})
+	}
+}
+
+func TestOpenFeatureOffVariantMatchesEvaluator(t *testing.T) {
+	flag := FeatureFlag{Name: "f", Variations: []Variation{{Key: "a", Value: "x", Weight: 1}}}
+	ix := (&Ruleset{Flags: []FeatureFlag{flag}}).index()
+	d := ix.evaluate(ix.flags["f"], NewContext("user-1"))
+
+	f, err := openFeatureFlag(flag)
+	require.NoError(t, err)
+	assert.Equal(t, d.Value, f.Variants[f.DefaultVariant])
+}
+
+func TestNewOpenFeatureDocumentRejectsUnrepresentableFlags(t *testing.T) {
+	_, err := NewOpenFeatureDocument([]FeatureFlag{
+		{Name: "ok", IsActive: true},
+		{Name: "ruled", IsActive: true, Rules: []FlagRule{{ID: "r"}}},
+		{Name: "layered", IsActive: true, LayerID: 2},
+		{Name: "clash", Variations: []Variation{{Key: "off", Value: 1, Weight: 1}}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "flag ruled has targeting rules")
+	assert.Contains(t, err.Error(), "flag layered is in experiment layer 2")
+	assert.Contains(t, err.Error(), "flag clash has a variation named off")
+	assert.NotContains(t, err.Error(), "flag ok")
+}
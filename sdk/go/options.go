@@ -0,0 +1,79 @@
+package matrixflag
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// New creates a client for the hosted API configured with functional options.
+// Unlike NewClient, settings added in later versions come as new options, so
+// callers never have to change:
+//
+//	client := matrixflag.New(apiKey,
+//		matrixflag.WithBaseURL("https://flags.internal.example.com"),
+//		matrixflag.WithTimeout(5*time.Second),
+//	)
+//
+// Settings start from DefaultConfig, and the options are applied in order.
+func New(apiKey string, opts ...ClientOption) *Client {
+	return NewClient(DefaultBaseURL, apiKey, nil, opts...)
+}
+
+// WithBaseURL sets the base URL of the API, DefaultBaseURL for clients created with New
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithTimeout sets the limit of each request attempt, see Config.Timeout
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.config.Timeout = timeout
+	}
+}
+
+// WithRetries sets the maximum number of retries of a call and the bounds of
+// the delay between them, see Config.MaxRetries
+func WithRetries(maxRetries int, retryDelay, maxRetryDelay time.Duration) ClientOption {
+	return func(c *Client) {
+		c.config.MaxRetries = maxRetries
+		c.config.RetryDelay = retryDelay
+		c.config.MaxRetryDelay = maxRetryDelay
+	}
+}
+
+// WithEnvironment sets the environment of server-side flag evaluations, see Config.Environment
+func WithEnvironment(environment string) ClientOption {
+	return func(c *Client) {
+		c.config.Environment = environment
+	}
+}
+
+// WithNamespace sets the namespace prefixed to flag names, see Config.Namespace
+func WithNamespace(namespace string) ClientOption {
+	return func(c *Client) {
+		c.config.Namespace = namespace
+	}
+}
+
+// WithHTTPClient sets the HTTP client that sends API requests, for custom
+// transports, TLS settings or connection pooling. The client's own Timeout, if
+// any, applies on top of the per-attempt timeouts; Config.Proxy is ignored.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTransport sets the transport of the HTTP client that sends API requests,
+// for mTLS, tracing transports or tuned connection pools. Config.Proxy is
+// ignored; set the transport's own Proxy instead.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		httpClient := *c.httpClient
+		httpClient.Transport = transport
+		c.httpClient = &httpClient
+	}
+}
@@ -0,0 +1,89 @@
+package matrixflag
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.addFlag(FeatureFlag{Name: "checkout", Environment: "production"})
+
+	client := New("key",
+		WithBaseURL(srv.URL+"/"),
+		WithTimeout(5*time.Second),
+		WithRetries(1, time.Millisecond, time.Millisecond),
+		WithEnvironment("production"),
+	)
+	flag, err := client.GetFeatureFlag(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "checkout", flag.Name)
+	assert.Equal(t, 5*time.Second, client.config.Timeout)
+	assert.Equal(t, 1, client.config.MaxRetries)
+	assert.Equal(t, "production", client.config.Environment)
+
+	assert.Equal(t, DefaultBaseURL, New("key").baseURL)
+}
+
+func TestNewClientCopiesConfig(t *testing.T) {
+	config := DefaultConfig()
+	NewClient("http://localhost", "key", config, WithTimeout(time.Second))
+	assert.Equal(t, 30*time.Second, config.Timeout, "options do not modify the caller's configuration")
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, FeatureFlag{ID: 1, Name: r.Header.Get("X-Transport")})
+	}))
+	defer srv.Close()
+
+	httpClient := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		req.Header.Set("X-Transport", "custom")
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+	flag, err := New("key", WithBaseURL(srv.URL), WithHTTPClient(httpClient)).GetFeatureFlag(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "custom", flag.Name)
+}
+
+func TestWithTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, FeatureFlag{ID: 1, Name: r.Header.Get("X-Transport")})
+	}))
+	defer srv.Close()
+
+	jar := &http.Client{Timeout: time.Minute}
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		req.Header.Set("X-Transport", "traced")
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	client := New("key", WithBaseURL(srv.URL), WithHTTPClient(jar), WithTransport(transport))
+	flag, err := client.GetFeatureFlag(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "traced", flag.Name)
+	assert.Equal(t, time.Minute, client.httpClient.Timeout, "the HTTP client's other settings are kept")
+	assert.Nil(t, jar.Transport, "the given HTTP client is not modified")
+}
+
+func TestDefaultTransport(t *testing.T) {
+	transport, ok := New("key").httpClient.Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.Equal(t, DefaultMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+	assert.NotSame(t, http.DefaultTransport, transport, "clients do not share the default transport")
+
+	config := DefaultConfig()
+	config.Proxy = http.ProxyURL(nil)
+	transport = NewClient("http://localhost", "key", config).httpClient.Transport.(*http.Transport)
+	assert.NotNil(t, transport.Proxy)
+	assert.Equal(t, DefaultMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost, "the proxy keeps the tuned pool")
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
@@ -0,0 +1,125 @@
+// Package otelflag traces Matrix Flag API calls and flag evaluations with
+// OpenTelemetry and propagates the trace context on outgoing requests. It is a
+// separate module, so only applications using it depend on OpenTelemetry.
+package otelflag
+
+import (
+	"context"
+	"net/http"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the spans
+const ScopeName = "github.com/matrixflag/sdk/otelflag"
+
+// Span attribute keys, following the OpenTelemetry conventions for feature flags and HTTP clients
+const (
+	FlagKeyKey       attribute.Key = "feature_flag.key"
+	ProviderNameKey  attribute.Key = "feature_flag.provider_name"
+	VariantKey       attribute.Key = "feature_flag.variant"
+	ReasonKey        attribute.Key = "feature_flag.reason"
+	ErrorCodeKey     attribute.Key = "feature_flag.error_code"
+	MethodKey        attribute.Key = "http.request.method"
+	PathKey          attribute.Key = "url.path"
+	StatusCodeKey    attribute.Key = "http.response.status_code"
+	EndpointClassKey attribute.Key = "matrixflag.endpoint_class"
+)
+
+// providerName is the feature_flag.provider_name of evaluation spans
+const providerName = "matrixflag"
+
+// Options configures tracing
+type Options struct {
+	// TracerProvider creates the tracer, the global provider by default
+	TracerProvider trace.TracerProvider
+	// Propagator injects the trace context into API requests, the global propagator by default
+	Propagator propagation.TextMapPropagator
+}
+
+// WithTracing traces a client's API calls and evaluations and propagates the
+// trace context on its API requests:
+//
+//	client := matrixflag.New(apiKey, otelflag.WithTracing(otelflag.Options{}))
+func WithTracing(opts Options) matrixflag.ClientOption {
+	tracer := NewTracer(opts.TracerProvider)
+	middleware := Middleware(opts.Propagator)
+	return func(c *matrixflag.Client) {
+		matrixflag.WithTracer(tracer)(c)
+		matrixflag.WithRequestMiddleware(middleware)(c)
+	}
+}
+
+// Tracer is a matrixflag.Tracer recording OpenTelemetry spans
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+var _ matrixflag.Tracer = (*Tracer)(nil)
+
+// NewTracer creates a tracer from a provider, the global provider when nil
+func NewTracer(provider trace.TracerProvider) *Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return &Tracer{tracer: provider.Tracer(ScopeName)}
+}
+
+// StartRequest implements matrixflag.Tracer with a client span named after the method
+func (t *Tracer) StartRequest(ctx context.Context, method string, class matrixflag.EndpointClass, path string) (context.Context, func(int, error)) {
+	ctx, span := t.tracer.Start(ctx, method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		MethodKey.String(method),
+		PathKey.String(path),
+		EndpointClassKey.String(string(class)),
+	))
+	return ctx, func(status int, err error) {
+		if status != 0 {
+			span.SetAttributes(StatusCodeKey.Int(status))
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// StartEvaluation implements matrixflag.Tracer with a span named "evaluate <flag>"
+func (t *Tracer) StartEvaluation(ctx context.Context, flagKey string) (context.Context, func(matrixflag.EvaluationDetail[any])) {
+	ctx, span := t.tracer.Start(ctx, "evaluate "+flagKey, trace.WithAttributes(
+		FlagKeyKey.String(flagKey),
+		ProviderNameKey.String(providerName),
+	))
+	return ctx, func(d matrixflag.EvaluationDetail[any]) {
+		span.SetAttributes(ReasonKey.String(string(d.Reason)))
+		if d.Variation != "" {
+			span.SetAttributes(VariantKey.String(d.Variation))
+		}
+		if d.Err != nil {
+			span.SetAttributes(ErrorCodeKey.String(string(d.ErrorCode)))
+			span.RecordError(d.Err)
+			span.SetStatus(codes.Error, d.Err.Error())
+		}
+		span.End()
+	}
+}
+
+// Middleware injects the trace context of each API request into its headers,
+// using the global propagator when propagator is nil
+func Middleware(propagator propagation.TextMapPropagator) matrixflag.Middleware {
+	return func(next matrixflag.Doer) matrixflag.Doer {
+		return matrixflag.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			p := propagator
+			if p == nil {
+				p = otel.GetTextMapPropagator()
+			}
+			p.Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+			return next.Do(req)
+		})
+	}
+}
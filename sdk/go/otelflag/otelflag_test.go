@@ -0,0 +1,92 @@
+package otelflag
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTracedClient(t *testing.T, handler http.HandlerFunc) (*matrixflag.Client, *tracetest.SpanRecorder) {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client := matrixflag.New("key", matrixflag.WithBaseURL(srv.URL), matrixflag.WithRetries(0, 0, 0),
+		WithTracing(Options{TracerProvider: provider, Propagator: propagation.TraceContext{}}))
+	return client, recorder
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestTraceEvaluation(t *testing.T) {
+	var traceparent string
+	client, recorder := newTracedClient(t, func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Write([]byte(`{"value":"B","variation":"treatment","reason":"SPLIT"}`))
+	})
+
+	d := client.EvaluateString(context.Background(), "checkout", matrixflag.EvaluationContext{Key: "user-1"}, "A")
+	if d.Err != nil {
+		t.Fatal(d.Err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want a request and an evaluation span", len(spans))
+	}
+	request, evaluation := spans[0], spans[1]
+	if request.Parent().SpanID() != evaluation.SpanContext().SpanID() {
+		t.Error("the request span is not a child of the evaluation span")
+	}
+	if want := "00-" + request.SpanContext().TraceID().String() + "-" + request.SpanContext().SpanID().String() + "-01"; traceparent != want {
+		t.Errorf("traceparent = %q, want %q", traceparent, want)
+	}
+
+	attrs := attributes(evaluation)
+	if evaluation.Name() != "evaluate checkout" || attrs[FlagKeyKey].AsString() != "checkout" {
+		t.Errorf("evaluation span %q has flag key %q", evaluation.Name(), attrs[FlagKeyKey].AsString())
+	}
+	if attrs[ReasonKey].AsString() != "SPLIT" || attrs[VariantKey].AsString() != "treatment" {
+		t.Errorf("evaluation span has reason %q and variant %q", attrs[ReasonKey].AsString(), attrs[VariantKey].AsString())
+	}
+
+	attrs = attributes(request)
+	if attrs[MethodKey].AsString() != http.MethodPost || attrs[StatusCodeKey].AsInt64() != http.StatusOK {
+		t.Errorf("request span has method %q and status %d", attrs[MethodKey].AsString(), attrs[StatusCodeKey].AsInt64())
+	}
+}
+
+func TestTraceFailedRequest(t *testing.T) {
+	client, recorder := newTracedClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"not found","code":"NOT_FOUND"}`))
+	})
+
+	if _, err := client.GetFeatureFlag(context.Background(), 42); err == nil {
+		t.Fatal("expected an error")
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if spans[0].Status().Code != codes.Error {
+		t.Errorf("span status = %v, want error", spans[0].Status().Code)
+	}
+	if got := attributes(spans[0])[StatusCodeKey].AsInt64(); got != http.StatusNotFound {
+		t.Errorf("status code = %d, want 404", got)
+	}
+}
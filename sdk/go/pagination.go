@@ -0,0 +1,151 @@
+package matrixflag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DefaultPerPage is the page size of flag listings when ListOptions sets none
+const DefaultPerPage = 100
+
+// ErrIteratorDone is returned by FlagIterator.Next after the last flag
+var ErrIteratorDone = errors.New("no more flags")
+
+// ListOptions selects the flags listed by IterateFeatureFlags and ListAll
+type ListOptions struct {
+	// Page is the first page listed, starting at 1; the first page when zero
+	Page int
+	// PerPage is the number of flags of a page, DefaultPerPage when zero
+	PerPage int
+	// Sort orders the flags by a field, such as "name", or "-updated_at" for
+	// descending order; the server's order when empty
+	Sort string
+	// Filter selects the flags listed
+	Filter FlagFilter
+}
+
+// query returns the query parameters listing the page of opts
+func (o ListOptions) query() map[string]string {
+	params := o.Filter.query()
+	params["page"] = strconv.Itoa(o.Page)
+	params["per_page"] = strconv.Itoa(o.PerPage)
+	if o.Sort != "" {
+		params["sort"] = o.Sort
+	}
+	return params
+}
+
+// FlagIterator pages through the flags of a listing, requesting each page as
+// the flags of the previous one run out:
+//
+//	it := client.IterateFeatureFlags(matrixflag.ListOptions{Filter: matrixflag.FlagFilter{Environment: "production"}})
+//	for {
+//		flag, err := it.Next(ctx)
+//		if errors.Is(err, matrixflag.ErrIteratorDone) {
+//			break
+//		}
+//		if err != nil {
+//			return err
+//		}
+//		// use flag
+//	}
+//
+// When a response has used up the rate limit, the next page is requested once
+// the limit resets, waiting at most MaxRetryDelay. A FlagIterator is not safe
+// for concurrent use.
+type FlagIterator struct {
+	client *Client
+	opts   ListOptions
+	flags  []FeatureFlag
+	done   bool
+	// resetAt is when the rate limit resets, zero while it has not run out
+	resetAt time.Time
+}
+
+// IterateFeatureFlags returns an iterator over the flags selected by opts
+func (c *Client) IterateFeatureFlags(opts ListOptions) *FlagIterator {
+	if opts.Page <= 0 {
+		opts.Page = 1
+	}
+	if opts.PerPage <= 0 {
+		opts.PerPage = DefaultPerPage
+	}
+	return &FlagIterator{client: c, opts: opts}
+}
+
+// Next returns the next flag, or ErrIteratorDone after the last one. A failed
+// page request is returned as is, and calling Next again retries it.
+func (it *FlagIterator) Next(ctx context.Context) (*FeatureFlag, error) {
+	for len(it.flags) == 0 {
+		if it.done {
+			return nil, ErrIteratorDone
+		}
+		if err := it.fetch(ctx); err != nil {
+			return nil, err
+		}
+	}
+	flag := it.flags[0]
+	it.flags = it.flags[1:]
+	return &flag, nil
+}
+
+// fetch requests the next page, after the rate limit resets if it ran out
+func (it *FlagIterator) fetch(ctx context.Context) error {
+	c := it.client
+	if err := it.opts.Filter.Validate(); err != nil {
+		return err
+	}
+	if !it.resetAt.IsZero() {
+		wait := it.resetAt.Sub(c.clock.Now())
+		if wait > c.config.MaxRetryDelay {
+			wait = c.config.MaxRetryDelay
+		}
+		if err := sleep(ctx, c.clock, wait); err != nil {
+			return fmt.Errorf("flag listing canceled while waiting for the rate limit: %w", err)
+		}
+		it.resetAt = time.Time{}
+	}
+
+	query := it.opts
+	query.Filter = c.qualifyFilter(query.Filter)
+	var meta ResponseMetadata
+	respBody, err := c.doRequest(WithResponseMetadata(ctx, &meta), request{
+		method: "GET",
+		path:   "/api/v1/feature-flags/",
+		query:  query.query(),
+	})
+	if err != nil {
+		return err
+	}
+	flags, err := c.decodeFlags(respBody)
+	if err != nil {
+		return err
+	}
+	// A short page is the last one; flags of other namespaces count toward it
+	it.done = len(flags) < it.opts.PerPage
+	it.flags = c.unqualifyFlags(flags)
+	it.opts.Page++
+	it.resetAt = rateLimitReset(meta.Header, c.clock.Now())
+	return nil
+}
+
+// ListAll retrieves every flag selected by opts, from opts.Page on, a page at a
+// time; see FlagIterator for how it keeps to the rate limit
+func (c *Client) ListAll(ctx context.Context, opts ListOptions, reqOpts ...RequestOption) ([]FeatureFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	it := c.IterateFeatureFlags(opts)
+	flags := []FeatureFlag{}
+	for {
+		flag, err := it.Next(ctx)
+		if errors.Is(err, ErrIteratorDone) {
+			return flags, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		flags = append(flags, *flag)
+	}
+}
@@ -0,0 +1,96 @@
+package matrixflag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sleepRecorder is a fixedClock recording the durations waited for
+type sleepRecorder struct {
+	fixedClock
+	waits *[]time.Duration
+}
+
+func (c sleepRecorder) After(d time.Duration) <-chan time.Time {
+	*c.waits = append(*c.waits, d)
+	return c.fixedClock.After(d)
+}
+
+func TestFlagIteratorPages(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RawQuery)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 1 {
+			// The first page uses up the rate limit for 3 seconds
+			w.Header().Set(RateLimitRemainingHeader, "0")
+			w.Header().Set(RateLimitResetHeader, strconv.FormatInt(now.Unix()+3, 10))
+		}
+		var flags []FeatureFlag
+		for id := page*2 - 1; id <= page*2 && id <= 5; id++ {
+			flags = append(flags, FeatureFlag{ID: id, Name: fmt.Sprintf("team.flag-%d", id)})
+		}
+		if page == 2 {
+			flags[1].Name = "other.flag-4"
+		}
+		writeJSON(w, flags)
+	}))
+	defer srv.Close()
+	var waits []time.Duration
+	client := NewClient(srv.URL, "key", nil, WithNamespace("team"), WithClock(sleepRecorder{fixedClock{now}, &waits}))
+
+	it := client.IterateFeatureFlags(ListOptions{PerPage: 2, Sort: "name", Filter: FlagFilter{Environment: "production"}})
+	var ids []int
+	for {
+		flag, err := it.Next(context.Background())
+		if errors.Is(err, ErrIteratorDone) {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, flag.ID)
+	}
+	assert.Equal(t, []int{1, 2, 3, 5}, ids, "flags of other namespaces are skipped without ending the listing")
+	assert.Equal(t, []string{
+		"environment=production&page=1&per_page=2&sort=name",
+		"environment=production&page=2&per_page=2&sort=name",
+		"environment=production&page=3&per_page=2&sort=name",
+	}, requests)
+	assert.Equal(t, []time.Duration{3 * time.Second}, waits, "the second page waits for the rate limit to reset")
+
+	_, err := it.Next(context.Background())
+	assert.ErrorIs(t, err, ErrIteratorDone)
+}
+
+func TestListAll(t *testing.T) {
+	pages := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		if r.URL.Query().Get("page") == "2" {
+			writeJSON(w, []FeatureFlag{})
+			return
+		}
+		writeJSON(w, []FeatureFlag{{ID: 1}, {ID: 2}})
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil)
+
+	flags, err := client.ListAll(context.Background(), ListOptions{PerPage: 2})
+	require.NoError(t, err)
+	assert.Len(t, flags, 2)
+	assert.Equal(t, 2, pages, "a full page is followed by another request")
+
+	flags, err = client.ListAll(context.Background(), ListOptions{Page: 2})
+	require.NoError(t, err)
+	assert.Empty(t, flags)
+	assert.NotNil(t, flags)
+}
@@ -0,0 +1,111 @@
+package matrixflag
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketByResolution(t *testing.T) {
+	tests := []struct {
+		name string
+		flag FeatureFlag
+		want string
+	}{
+		{"default", FeatureFlag{}, DefaultBucketBy},
+		{"rollout", FeatureFlag{Rollout: &PercentageRollout{BucketBy: "org"}}, "org"},
+		{"traffic", FeatureFlag{TrafficAllocation: &TrafficAllocation{BucketBy: "team"}}, "team"},
+		{
+			"rollout before traffic",
+			FeatureFlag{Rollout: &PercentageRollout{BucketBy: "org"}, TrafficAllocation: &TrafficAllocation{Percentage: 50}},
+			"org",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.flag.bucketBy())
+		})
+	}
+}
+
+func TestValidateBucketBy(t *testing.T) {
+	assert.NoError(t, validateBucketBy(&PercentageRollout{BucketBy: "org"}, nil))
+	assert.NoError(t, validateBucketBy(&PercentageRollout{BucketBy: "org"}, &TrafficAllocation{}))
+	assert.NoError(t, validateBucketBy(&PercentageRollout{BucketBy: "org"}, &TrafficAllocation{BucketBy: "org"}))
+	assert.Error(t, validateBucketBy(&PercentageRollout{BucketBy: "org"}, &TrafficAllocation{BucketBy: "key"}))
+}
+
+func TestRolloutByOrganizationAssignsVariationPerOrganization(t *testing.T) {
+	flag := FeatureFlag{
+		ID:       7,
+		Name:     "pricing",
+		IsActive: true,
+		Rollout:  &PercentageRollout{Percentage: 50, BucketBy: "org"},
+		Variations: []Variation{
+			{Key: "a", Value: "a", Weight: 1},
+			{Key: "b", Value: "b", Weight: 1},
+		},
+	}
+	ix := (&Ruleset{Flags: []FeatureFlag{flag}}).index()
+
+	for org := 0; org < 20; org++ {
+		var first EvaluationDetail[any]
+		for user := 0; user < 10; user++ {
+			evalCtx := NewContext(fmt.Sprintf("user-%d", user)).With("org", fmt.Sprintf("org-%d", org))
+			d := ix.evaluate(ix.flags["pricing"], evalCtx)
+			if user == 0 {
+				first = d
+				continue
+			}
+			assert.Equal(t, first.Value, d.Value, "org-%d", org)
+			assert.Equal(t, first.Variation, d.Variation, "org-%d", org)
+		}
+	}
+}
+
+func TestRuleRollout(t *testing.T) {
+	flag := FeatureFlag{
+		ID:       3,
+		Name:     "beta",
+		IsActive: true,
+		Rules: []FlagRule{{
+			ID:         "nl",
+			Conditions: []TargetingCondition{{Attribute: "country", Operator: OpEquals, Value: "NL"}},
+			Rollout:    &PercentageRollout{Percentage: 30, BucketBy: "org"},
+		}},
+		Rollout: &PercentageRollout{Percentage: 0},
+	}
+	ix := (&Ruleset{Flags: []FeatureFlag{flag}}).index()
+
+	matched := 0
+	for org := 0; org < 200; org++ {
+		orgKey := fmt.Sprintf("org-%d", org)
+		want := Bucket(orgKey, "rule.3.nl") < 30
+		for _, user := range []string{"u1", "u2"} {
+			d := ix.evaluate(ix.flags["beta"], NewContext(user).With("country", "NL").With("org", orgKey))
+			if want {
+				assert.Equal(t, ReasonRuleMatch, d.Reason, orgKey)
+				assert.Equal(t, "nl", d.RuleID)
+			} else {
+				// Matched contexts outside the rule's rollout fall through to the flag's 0% rollout
+				assert.Equal(t, ReasonSplit, d.Reason, orgKey)
+				assert.Equal(t, false, d.Value)
+			}
+		}
+		if want {
+			matched++
+		}
+	}
+	assert.InDelta(t, 60, matched, 20)
+
+	d := ix.evaluate(ix.flags["beta"], NewContext("u1").With("country", "NL"))
+	assert.Equal(t, ReasonSplit, d.Reason, "a context without the rule's bucketing attribute is outside its rollout")
+}
+
+func TestValidateFlagRulesRollout(t *testing.T) {
+	err := validateFlagRules([]FlagRule{{ID: "r", Rollout: &PercentageRollout{Percentage: 120}}}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rule r")
+}
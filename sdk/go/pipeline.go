@@ -0,0 +1,258 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ReleasePipeline defines the ordered environments a flag is released through,
+// such as dev, staging and production, with the checks and approvals each
+// stage requires before the flag may enter it
+type ReleasePipeline struct {
+	ID          int             `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Stages      []PipelineStage `json:"stages"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// PipelineStage is one environment of a release pipeline
+type PipelineStage struct {
+	Environment string `json:"environment"`
+	// RequiredChecks must all have passed before the flag enters the stage
+	RequiredChecks []string `json:"required_checks,omitempty"`
+	// RequiredApprovals is the number of approvals needed before the flag enters the stage
+	RequiredApprovals int `json:"required_approvals,omitempty"`
+}
+
+// ReleasePipelineCreate represents the data needed to create a release pipeline
+type ReleasePipelineCreate struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Stages      []PipelineStage `json:"stages"`
+}
+
+// ReleasePipelineUpdate represents the data needed to update a release pipeline
+type ReleasePipelineUpdate struct {
+	Name        string          `json:"name,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Stages      []PipelineStage `json:"stages,omitempty"`
+}
+
+// ValidatePipelineStages checks that a pipeline has stages, each for a distinct environment
+func ValidatePipelineStages(stages []PipelineStage) error {
+	if len(stages) == 0 {
+		return fmt.Errorf("invalid release pipeline: no stages")
+	}
+	seen := make(map[string]bool, len(stages))
+	for i, stage := range stages {
+		if stage.Environment == "" {
+			return fmt.Errorf("invalid release pipeline stage %d: missing environment", i)
+		}
+		if seen[stage.Environment] {
+			return fmt.Errorf("invalid release pipeline stage %d: environment %s appears more than once", i, stage.Environment)
+		}
+		if stage.RequiredApprovals < 0 {
+			return fmt.Errorf("invalid release pipeline stage %d: required approvals must not be negative", i)
+		}
+		seen[stage.Environment] = true
+	}
+	return nil
+}
+
+// ReleaseStatus is the state of a flag release
+type ReleaseStatus string
+
+// Release statuses
+const (
+	ReleaseInProgress ReleaseStatus = "in_progress"
+	ReleaseCompleted  ReleaseStatus = "completed"
+)
+
+// FlagRelease is the progress of a flag through a release pipeline
+type FlagRelease struct {
+	PipelineID int           `json:"pipeline_id"`
+	Flag       string        `json:"flag"`
+	Status     ReleaseStatus `json:"status"`
+	// Stage is the index of the last stage the flag entered, -1 before the first
+	Stage  int            `json:"stage"`
+	Stages []StageRelease `json:"stages"`
+}
+
+// StageRelease is the state of one stage of a flag release
+type StageRelease struct {
+	Environment string            `json:"environment"`
+	Checks      map[string]bool   `json:"checks,omitempty"`
+	Approvals   []ReleaseApproval `json:"approvals,omitempty"`
+	EnteredAt   *time.Time        `json:"entered_at,omitempty"`
+}
+
+// ReleaseApproval is an approval for a flag to enter a stage
+type ReleaseApproval struct {
+	Approver   string    `json:"approver"`
+	Comment    string    `json:"comment,omitempty"`
+	ApprovedAt time.Time `json:"approved_at"`
+}
+
+// Blockers lists what keeps a release from entering its next stage, given the
+// pipeline definition. It is empty when the flag can be advanced.
+func (r *FlagRelease) Blockers(pipeline *ReleasePipeline) []string {
+	next := r.Stage + 1
+	if next >= len(pipeline.Stages) || next >= len(r.Stages) {
+		return nil
+	}
+	stage, state := pipeline.Stages[next], r.Stages[next]
+
+	var blockers []string
+	for _, check := range stage.RequiredChecks {
+		if !state.Checks[check] {
+			blockers = append(blockers, fmt.Sprintf("check %s has not passed", check))
+		}
+	}
+	if missing := stage.RequiredApprovals - len(state.Approvals); missing > 0 {
+		blockers = append(blockers, fmt.Sprintf("%d more approval(s) required", missing))
+	}
+	return blockers
+}
+
+// ListReleasePipelines retrieves all release pipelines
+func (c *Client) ListReleasePipelines(ctx context.Context, reqOpts ...RequestOption) ([]ReleasePipeline, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	respBody, err := c.doRequest(ctx, request{
+		method: "GET",
+		path:   "/api/v1/release-pipelines/",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var pipelines []ReleasePipeline
+	if err := json.Unmarshal(respBody, &pipelines); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return pipelines, nil
+}
+
+// CreateReleasePipeline creates a new release pipeline
+func (c *Client) CreateReleasePipeline(ctx context.Context, pipeline ReleasePipelineCreate, reqOpts ...RequestOption) (*ReleasePipeline, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := ValidatePipelineStages(pipeline.Stages); err != nil {
+		return nil, err
+	}
+	return c.pipelineRequest(ctx, request{
+		method: "POST",
+		path:   "/api/v1/release-pipelines/",
+		body:   pipeline,
+	})
+}
+
+// GetReleasePipeline retrieves a release pipeline by ID
+func (c *Client) GetReleasePipeline(ctx context.Context, id int, reqOpts ...RequestOption) (*ReleasePipeline, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.pipelineRequest(ctx, request{
+		method: "GET",
+		path:   fmt.Sprintf("/api/v1/release-pipelines/%d", id),
+	})
+}
+
+// UpdateReleasePipeline updates a release pipeline
+func (c *Client) UpdateReleasePipeline(ctx context.Context, id int, pipeline ReleasePipelineUpdate, reqOpts ...RequestOption) (*ReleasePipeline, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if pipeline.Stages != nil {
+		if err := ValidatePipelineStages(pipeline.Stages); err != nil {
+			return nil, err
+		}
+	}
+	return c.pipelineRequest(ctx, request{
+		method: "PUT",
+		path:   fmt.Sprintf("/api/v1/release-pipelines/%d", id),
+		body:   pipeline,
+	})
+}
+
+// DeleteReleasePipeline deletes a release pipeline
+func (c *Client) DeleteReleasePipeline(ctx context.Context, id int, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	_, err := c.doRequest(ctx, request{
+		method: "DELETE",
+		path:   fmt.Sprintf("/api/v1/release-pipelines/%d", id),
+	})
+	return err
+}
+
+// GetFlagRelease retrieves the progress of a flag through a release pipeline
+func (c *Client) GetFlagRelease(ctx context.Context, pipelineID int, flag string, reqOpts ...RequestOption) (*FlagRelease, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.releaseRequest(ctx, pipelineID, flag, request{method: "GET"})
+}
+
+// ReportReleaseCheck records the result of a required check for the stage of a release
+func (c *Client) ReportReleaseCheck(ctx context.Context, pipelineID int, flag, environment, check string, passed bool, reqOpts ...RequestOption) (*FlagRelease, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.releaseRequest(ctx, pipelineID, flag, request{
+		method: "POST",
+		path:   "/checks",
+		body: map[string]any{
+			"environment": environment,
+			"check":       check,
+			"passed":      passed,
+		},
+	})
+}
+
+// ApproveRelease approves a flag entering a stage of its release pipeline
+func (c *Client) ApproveRelease(ctx context.Context, pipelineID int, flag, environment, comment string, reqOpts ...RequestOption) (*FlagRelease, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.releaseRequest(ctx, pipelineID, flag, request{
+		method: "POST",
+		path:   "/approvals",
+		body: map[string]string{
+			"environment": environment,
+			"comment":     comment,
+		},
+	})
+}
+
+// AdvanceRelease moves a flag into the next stage of its release pipeline. The
+// server rejects the request when the stage's checks or approvals are missing.
+func (c *Client) AdvanceRelease(ctx context.Context, pipelineID int, flag string, reqOpts ...RequestOption) (*FlagRelease, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.releaseRequest(ctx, pipelineID, flag, request{
+		method: "POST",
+		path:   "/advance",
+	})
+}
+
+// pipelineRequest performs a request returning a release pipeline
+func (c *Client) pipelineRequest(ctx context.Context, req request) (*ReleasePipeline, error) {
+	respBody, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var pipeline ReleasePipeline
+	if err := json.Unmarshal(respBody, &pipeline); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return &pipeline, nil
+}
+
+// releaseRequest performs a request on the release of a flag, whose path is relative to the release
+func (c *Client) releaseRequest(ctx context.Context, pipelineID int, flag string, req request) (*FlagRelease, error) {
+	req.path = fmt.Sprintf("/api/v1/release-pipelines/%d/releases/%s", pipelineID, url.PathEscape(c.qualifyName(flag))) + req.path
+	respBody, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var release FlagRelease
+	if err := json.Unmarshal(respBody, &release); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	release.Flag = flag
+	return &release, nil
+}
@@ -0,0 +1,266 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pipelineServer serves release pipelines and the releases of flags through
+// them, refusing to advance a release while FlagRelease.Blockers reports any
+type pipelineServer struct {
+	*httptest.Server
+	mu        sync.Mutex
+	pipelines map[int]ReleasePipeline
+	// releases are keyed by pipeline ID and flag name
+	releases map[string]*FlagRelease
+	nextID   int
+}
+
+func newPipelineServer(t *testing.T) *pipelineServer {
+	s := &pipelineServer{pipelines: make(map[int]ReleasePipeline), releases: make(map[string]*FlagRelease)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *pipelineServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// /api/v1/release-pipelines/[{pipeline}[/releases/{flag}[/{action}]]]
+	rest := strings.TrimPrefix(r.URL.Path, "/api/v1/release-pipelines/")
+	if rest == "" {
+		switch r.Method {
+		case http.MethodGet:
+			pipelines := []ReleasePipeline{}
+			for id := 1; id <= s.nextID; id++ {
+				if pipeline, ok := s.pipelines[id]; ok {
+					pipelines = append(pipelines, pipeline)
+				}
+			}
+			writeJSON(w, pipelines)
+		case http.MethodPost:
+			var create ReleasePipelineCreate
+			if !decodeBody(w, r, &create) {
+				return
+			}
+			s.nextID++
+			pipeline := ReleasePipeline{ID: s.nextID, Name: create.Name, Description: create.Description, Stages: create.Stages}
+			s.pipelines[pipeline.ID] = pipeline
+			writeJSON(w, pipeline)
+		}
+		return
+	}
+	parts := strings.Split(rest, "/")
+	id, _ := strconv.Atoi(parts[0])
+	pipeline, ok := s.pipelines[id]
+	if !ok {
+		http.Error(w, `{"message":"not found","code":"NOT_FOUND"}`, http.StatusNotFound)
+		return
+	}
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodPut:
+			if !patchJSON(w, r, &pipeline) {
+				return
+			}
+			s.pipelines[id] = pipeline
+		case http.MethodDelete:
+			delete(s.pipelines, id)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeJSON(w, pipeline)
+		return
+	}
+	s.handleRelease(w, r, pipeline, parts[2], strings.Join(parts[3:], "/"))
+}
+
+// handleRelease serves the release of a flag, created before its first stage on first use
+func (s *pipelineServer) handleRelease(w http.ResponseWriter, r *http.Request, pipeline ReleasePipeline, flag, action string) {
+	key := strconv.Itoa(pipeline.ID) + "/" + flag
+	release, ok := s.releases[key]
+	if !ok {
+		release = &FlagRelease{PipelineID: pipeline.ID, Flag: flag, Status: ReleaseInProgress, Stage: -1}
+		for _, stage := range pipeline.Stages {
+			release.Stages = append(release.Stages, StageRelease{Environment: stage.Environment})
+		}
+		s.releases[key] = release
+	}
+	stage := func(environment string) *StageRelease {
+		for i := range release.Stages {
+			if release.Stages[i].Environment == environment {
+				return &release.Stages[i]
+			}
+		}
+		return nil
+	}
+
+	switch action {
+	case "checks":
+		var body struct {
+			Environment string `json:"environment"`
+			Check       string `json:"check"`
+			Passed      bool   `json:"passed"`
+		}
+		if !decodeBody(w, r, &body) {
+			return
+		}
+		st := stage(body.Environment)
+		if st.Checks == nil {
+			st.Checks = make(map[string]bool)
+		}
+		st.Checks[body.Check] = body.Passed
+	case "approvals":
+		var body map[string]string
+		if !decodeBody(w, r, &body) {
+			return
+		}
+		st := stage(body["environment"])
+		st.Approvals = append(st.Approvals, ReleaseApproval{Approver: "alice@example.com", Comment: body["comment"], ApprovedAt: time.Now()})
+	case "advance":
+		if blockers := release.Blockers(&pipeline); len(blockers) > 0 || release.Status == ReleaseCompleted {
+			msg, _ := json.Marshal(map[string]string{"message": "release blocked: " + strings.Join(blockers, ", "), "code": "VALIDATION_ERROR"})
+			http.Error(w, string(msg), http.StatusUnprocessableEntity)
+			return
+		}
+		release.Stage++
+		now := time.Now()
+		release.Stages[release.Stage].EnteredAt = &now
+		if release.Stage == len(pipeline.Stages)-1 {
+			release.Status = ReleaseCompleted
+		}
+	}
+	writeJSON(w, release)
+}
+
+func TestReleasePipelineAdvance(t *testing.T) {
+	srv := newPipelineServer(t)
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+
+	pipeline, err := client.CreateReleasePipeline(ctx, ReleasePipelineCreate{Name: "standard", Stages: []PipelineStage{
+		{Environment: "dev"},
+		{Environment: "staging", RequiredChecks: []string{"e2e"}},
+		{Environment: "production", RequiredChecks: []string{"e2e", "load"}, RequiredApprovals: 2},
+	}})
+	require.NoError(t, err)
+
+	release, err := client.GetFlagRelease(ctx, pipeline.ID, "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, -1, release.Stage)
+	assert.Empty(t, release.Blockers(pipeline), "the first stage has no requirements")
+
+	release, err = client.AdvanceRelease(ctx, pipeline.ID, "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, 0, release.Stage)
+	assert.NotNil(t, release.Stages[0].EnteredAt)
+	assert.Equal(t, []string{"check e2e has not passed"}, release.Blockers(pipeline))
+
+	_, err = client.AdvanceRelease(ctx, pipeline.ID, "checkout")
+	assert.True(t, IsValidation(err), "a blocked release is not advanced")
+	assert.ErrorContains(t, err, "check e2e has not passed")
+
+	release, err = client.ReportReleaseCheck(ctx, pipeline.ID, "checkout", "staging", "e2e", true)
+	require.NoError(t, err)
+	assert.Empty(t, release.Blockers(pipeline))
+	release, err = client.AdvanceRelease(ctx, pipeline.ID, "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, 1, release.Stage)
+	assert.Equal(t, ReleaseInProgress, release.Status)
+
+	_, err = client.ReportReleaseCheck(ctx, pipeline.ID, "checkout", "production", "e2e", true)
+	require.NoError(t, err)
+	release, err = client.ReportReleaseCheck(ctx, pipeline.ID, "checkout", "production", "load", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"check load has not passed", "2 more approval(s) required"}, release.Blockers(pipeline))
+
+	_, err = client.ReportReleaseCheck(ctx, pipeline.ID, "checkout", "production", "load", true)
+	require.NoError(t, err)
+	release, err = client.ApproveRelease(ctx, pipeline.ID, "checkout", "production", "looks good")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1 more approval(s) required"}, release.Blockers(pipeline))
+	assert.Equal(t, "looks good", release.Stages[2].Approvals[0].Comment)
+	_, err = client.ApproveRelease(ctx, pipeline.ID, "checkout", "production", "")
+	require.NoError(t, err)
+
+	release, err = client.AdvanceRelease(ctx, pipeline.ID, "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, 2, release.Stage)
+	assert.Equal(t, ReleaseCompleted, release.Status)
+	assert.Empty(t, release.Blockers(pipeline), "a completed release has no next stage")
+
+	other, err := client.GetFlagRelease(ctx, pipeline.ID, "search")
+	require.NoError(t, err)
+	assert.Equal(t, -1, other.Stage, "each flag is released separately")
+}
+
+func TestReleasePipelineNamespace(t *testing.T) {
+	srv := newPipelineServer(t)
+	client := NewClient(srv.URL, "key", nil, WithNamespace("web"))
+	ctx := context.Background()
+
+	pipeline, err := client.CreateReleasePipeline(ctx, ReleasePipelineCreate{Name: "standard", Stages: []PipelineStage{{Environment: "dev"}}})
+	require.NoError(t, err)
+	release, err := client.AdvanceRelease(ctx, pipeline.ID, "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, "checkout", release.Flag)
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	assert.Contains(t, srv.releases, "1/web.checkout", "releases are requested by qualified name")
+}
+
+func TestReleasePipelines(t *testing.T) {
+	srv := newPipelineServer(t)
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+
+	pipeline, err := client.CreateReleasePipeline(ctx, ReleasePipelineCreate{Name: "standard", Stages: []PipelineStage{{Environment: "dev"}}})
+	require.NoError(t, err)
+	pipeline, err = client.UpdateReleasePipeline(ctx, pipeline.ID, ReleasePipelineUpdate{Description: "dev then production"})
+	require.NoError(t, err)
+	assert.Equal(t, "standard", pipeline.Name, "fields left empty are unchanged")
+	assert.Equal(t, []PipelineStage{{Environment: "dev"}}, pipeline.Stages)
+	got, err := client.GetReleasePipeline(ctx, pipeline.ID)
+	require.NoError(t, err)
+	assert.Equal(t, pipeline, got)
+
+	require.NoError(t, client.DeleteReleasePipeline(ctx, pipeline.ID))
+	pipelines, err := client.ListReleasePipelines(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, pipelines)
+}
+
+func TestValidatePipelineStages(t *testing.T) {
+	srv := newPipelineServer(t)
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		stages []PipelineStage
+		err    string
+	}{
+		{nil, "invalid release pipeline: no stages"},
+		{[]PipelineStage{{Environment: "dev"}, {}}, "invalid release pipeline stage 1: missing environment"},
+		{[]PipelineStage{{Environment: "dev"}, {Environment: "dev"}}, "invalid release pipeline stage 1: environment dev appears more than once"},
+		{[]PipelineStage{{Environment: "dev", RequiredApprovals: -1}}, "invalid release pipeline stage 0: required approvals must not be negative"},
+	} {
+		assert.EqualError(t, ValidatePipelineStages(tt.stages), tt.err)
+		_, err := client.CreateReleasePipeline(ctx, ReleasePipelineCreate{Name: "standard", Stages: tt.stages})
+		assert.EqualError(t, err, tt.err)
+	}
+	_, err := client.UpdateReleasePipeline(ctx, 1, ReleasePipelineUpdate{Stages: []PipelineStage{{}}})
+	assert.EqualError(t, err, "invalid release pipeline stage 0: missing environment")
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	assert.Zero(t, srv.nextID, "invalid pipelines are not sent")
+}
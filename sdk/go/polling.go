@@ -0,0 +1,231 @@
+package matrixflag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// DefaultPollingJitter is the default fraction of the interval by which the
+// polls of a PollingProcessor vary, see PollingOptions.Jitter
+const DefaultPollingJitter = 0.1
+
+// DataSourceState is the state of a source of flag data, such as a PollingProcessor
+type DataSourceState string
+
+// Data source states
+const (
+	// DataSourceInitializing is the state until the first ruleset is loaded
+	DataSourceInitializing DataSourceState = "initializing"
+	// DataSourceValid is the state while the latest refresh succeeded
+	DataSourceValid DataSourceState = "valid"
+	// DataSourceInterrupted is the state after a refresh failed once the data
+	// was valid; the last loaded ruleset is kept meanwhile
+	DataSourceInterrupted DataSourceState = "interrupted"
+	// DataSourceOff is the state once the data source stopped
+	DataSourceOff DataSourceState = "off"
+)
+
+// DataSourceStatus is the status of a source of flag data
+type DataSourceStatus struct {
+	State DataSourceState
+	// Since is when the data source entered State
+	Since time.Time
+	// LastError is the error of the latest failed refresh, nil before any
+	LastError error
+}
+
+// PollingOptions configures a PollingProcessor
+type PollingOptions struct {
+	// Environment is the environment whose ruleset is polled; it is required
+	Environment string
+	// Interval is the average time between polls, DefaultSyncInterval by default
+	Interval time.Duration
+	// Jitter is the fraction of Interval by which each wait varies at random,
+	// so many instances started together do not poll in lockstep,
+	// DefaultPollingJitter by default. Negative values disable it.
+	Jitter float64
+	// Store receives the polled rulesets, a new MemoryStore by default
+	Store FlagStore
+	// OnUpdate is called with every polled ruleset once it is stored, such as
+	// Evaluator.SetRuleset
+	OnUpdate func(*Ruleset)
+	// OnStatusChange is called when the state of the processor changes
+	OnStatusChange func(DataSourceStatus)
+}
+
+// PollingProcessor refreshes a flag store with the ruleset of an environment
+// on an interval. It sends conditional requests, enabling the response cache
+// of its client unless WithResponseCache configured one, so polls of an
+// unchanged ruleset transfer no flags. It is safe for concurrent use.
+type PollingProcessor struct {
+	client *Client
+	opts   PollingOptions
+
+	mu     sync.Mutex
+	status DataSourceStatus
+	// initialized is closed once the first ruleset is stored
+	initialized chan struct{}
+	// stopped is closed once Run returns
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// NewPollingProcessor creates a polling processor; call Run to start polling
+func NewPollingProcessor(client *Client, opts PollingOptions) *PollingProcessor {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultSyncInterval
+	}
+	if opts.Jitter == 0 {
+		opts.Jitter = DefaultPollingJitter
+	}
+	opts.Jitter = min(max(opts.Jitter, 0), 1)
+	if opts.Store == nil {
+		opts.Store = NewMemoryStore()
+	}
+	client.enableResponseCache()
+	return &PollingProcessor{
+		client:      client,
+		opts:        opts,
+		status:      DataSourceStatus{State: DataSourceInitializing, Since: client.clock.Now()},
+		initialized: make(chan struct{}),
+		stopped:     make(chan struct{}),
+	}
+}
+
+// Run polls the ruleset immediately and then every interval, varied by the
+// jitter, until ctx is canceled, when the processor turns off
+func (p *PollingProcessor) Run(ctx context.Context) {
+	defer p.once.Do(func() {
+		p.setStatus(DataSourceOff)
+		close(p.stopped)
+	})
+	for {
+		if err := p.Poll(ctx); err != nil && ctx.Err() == nil {
+			p.client.Logger().Error("flag polling error", slog.String("environment", p.opts.Environment), slog.Any("error", err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.client.clock.After(p.wait()):
+		}
+	}
+}
+
+// wait returns the time until the next poll
+func (p *PollingProcessor) wait() time.Duration {
+	spread := float64(p.opts.Interval) * p.opts.Jitter
+	return p.opts.Interval + time.Duration(spread*(2*rand.Float64()-1))
+}
+
+// Poll fetches the ruleset once, stores it and passes it to OnUpdate. Run
+// calls it on every interval; a failure turns the processor interrupted once
+// it was valid.
+func (p *PollingProcessor) Poll(ctx context.Context) error {
+	ruleset, err := p.client.FetchRuleset(ctx, p.opts.Environment)
+	if err == nil {
+		if err = p.opts.Store.Init(ctx, ruleset); err != nil {
+			err = fmt.Errorf("failed to store ruleset: %w", err)
+		}
+	}
+	if err != nil {
+		if ctx.Err() == nil {
+			p.failed(err)
+		}
+		return err
+	}
+	if p.opts.OnUpdate != nil {
+		p.opts.OnUpdate(ruleset)
+	}
+	p.setStatus(DataSourceValid)
+	return nil
+}
+
+// failed records the error of a failed poll
+func (p *PollingProcessor) failed(err error) {
+	p.transition(func(state DataSourceState) DataSourceState {
+		if state == DataSourceValid {
+			return DataSourceInterrupted
+		}
+		return state
+	}, err)
+}
+
+// setStatus records the state of the processor
+func (p *PollingProcessor) setStatus(state DataSourceState) {
+	p.transition(func(DataSourceState) DataSourceState { return state }, nil)
+}
+
+// transition moves the processor to the state next returns for the current
+// one, recording err if not nil, and notifies OnStatusChange when the state changes
+func (p *PollingProcessor) transition(next func(DataSourceState) DataSourceState, err error) {
+	p.mu.Lock()
+	state := next(p.status.State)
+	changed := p.status.State != state
+	if changed {
+		p.status.State = state
+		p.status.Since = p.client.clock.Now()
+	}
+	if err != nil {
+		p.status.LastError = err
+	}
+	status := p.status
+	if state == DataSourceValid && !p.Initialized() {
+		close(p.initialized)
+	}
+	p.mu.Unlock()
+	if changed && p.opts.OnStatusChange != nil {
+		p.opts.OnStatusChange(status)
+	}
+}
+
+// Status returns the current status of the processor
+func (p *PollingProcessor) Status() DataSourceStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.status
+}
+
+// Store returns the store the processor refreshes
+func (p *PollingProcessor) Store() FlagStore {
+	return p.opts.Store
+}
+
+// Initialized reports whether a ruleset has been stored
+func (p *PollingProcessor) Initialized() bool {
+	select {
+	case <-p.initialized:
+		return true
+	default:
+		return false
+	}
+}
+
+// WaitForInitialization blocks until the first ruleset is stored. It returns an
+// error wrapping ErrEvaluatorNotReady, and the error of the latest poll, if
+// any, when ctx ends or the processor stops first.
+func (p *PollingProcessor) WaitForInitialization(ctx context.Context) error {
+	select {
+	case <-p.initialized:
+		return nil
+	case <-ctx.Done():
+		return p.notInitialized(ctx.Err())
+	case <-p.stopped:
+		return p.notInitialized(errors.New("the processor stopped"))
+	}
+}
+
+// notInitialized returns the error of a wait for initialization ended by cause
+func (p *PollingProcessor) notInitialized(cause error) error {
+	if p.Initialized() {
+		return nil
+	}
+	if last := p.Status().LastError; last != nil {
+		return fmt.Errorf("%w: %w, last error: %w", ErrEvaluatorNotReady, cause, last)
+	}
+	return fmt.Errorf("%w: %w", ErrEvaluatorNotReady, cause)
+}
@@ -0,0 +1,171 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pollingServer serves the ruleset of production with an ETag on the flag
+// list and counts the events it receives, failing every request while down
+type pollingServer struct {
+	*httptest.Server
+	mu          sync.Mutex
+	down        bool
+	flags       []FeatureFlag
+	notModified int
+	events      int
+}
+
+func newPollingServer(t *testing.T, flags ...FeatureFlag) *pollingServer {
+	s := &pollingServer{flags: flags}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/feature-flags/", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		etag := `"` + s.flags[0].Name + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			s.notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		writeJSON(w, s.flags)
+	})
+	mux.HandleFunc("/api/v1/events", func(w http.ResponseWriter, r *http.Request) {
+		var body struct{ Events []Event }
+		json.NewDecoder(r.Body).Decode(&body)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.events += len(body.Events)
+	})
+	for _, path := range []string{"/api/v1/layers/", "/api/v1/holdouts/", "/api/v1/targeting/segments"} {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(`[]`)) })
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		down := s.down
+		s.mu.Unlock()
+		if down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *pollingServer) setDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.down = down
+}
+
+func TestPollingProcessor(t *testing.T) {
+	srv := newPollingServer(t, FeatureFlag{ID: 1, Name: "checkout", Environment: "production", IsActive: true})
+	client := NewClient(srv.URL, "key", nil, WithRetries(0, 0, 0))
+	var states []DataSourceState
+	var updates int
+	processor := NewPollingProcessor(client, PollingOptions{
+		Environment:    "production",
+		OnUpdate:       func(*Ruleset) { updates++ },
+		OnStatusChange: func(status DataSourceStatus) { states = append(states, status.State) },
+	})
+	ctx := context.Background()
+	assert.Equal(t, DataSourceInitializing, processor.Status().State)
+	assert.False(t, processor.Initialized())
+
+	require.NoError(t, processor.Poll(ctx))
+	require.NoError(t, processor.WaitForInitialization(ctx))
+	ruleset, err := processor.Store().All(ctx)
+	require.NoError(t, err)
+	require.Len(t, ruleset.Flags, 1)
+	assert.Equal(t, "checkout", ruleset.Flags[0].Name)
+
+	require.NoError(t, processor.Poll(ctx))
+	assert.Equal(t, 1, srv.notModified, "polls send conditional requests")
+	assert.Equal(t, 2, updates)
+
+	srv.setDown(true)
+	assert.Error(t, processor.Poll(ctx))
+	status := processor.Status()
+	assert.Equal(t, DataSourceInterrupted, status.State)
+	assert.ErrorContains(t, status.LastError, "503")
+	ruleset, err = processor.Store().All(ctx)
+	require.NoError(t, err)
+	assert.Len(t, ruleset.Flags, 1, "the last ruleset is kept while interrupted")
+
+	srv.setDown(false)
+	require.NoError(t, processor.Poll(ctx))
+	assert.Equal(t, []DataSourceState{DataSourceValid, DataSourceInterrupted, DataSourceValid}, states)
+}
+
+func TestPollingProcessorWaitForInitialization(t *testing.T) {
+	srv := newPollingServer(t, FeatureFlag{ID: 1, Name: "checkout", Environment: "production"})
+	srv.setDown(true)
+	client := NewClient(srv.URL, "key", nil, WithRetries(0, 0, 0))
+	processor := NewPollingProcessor(client, PollingOptions{Environment: "production"})
+
+	assert.Error(t, processor.Poll(context.Background()))
+	assert.Equal(t, DataSourceInitializing, processor.Status().State, "failures before the first ruleset are not interruptions")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := processor.WaitForInitialization(ctx)
+	assert.ErrorIs(t, err, ErrEvaluatorNotReady)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "503")
+
+	stopped, stop := context.WithCancel(context.Background())
+	stop()
+	processor.Run(stopped)
+	assert.Equal(t, DataSourceOff, processor.Status().State)
+	assert.ErrorContains(t, processor.WaitForInitialization(context.Background()), "the processor stopped")
+}
+
+// cancelingClock is a fixedClock recording the waits and canceling a context
+// after some of them, whose timers stop firing then
+type cancelingClock struct {
+	fixedClock
+	waits  *[]time.Duration
+	after  int
+	cancel context.CancelFunc
+}
+
+func (c cancelingClock) After(d time.Duration) <-chan time.Time {
+	*c.waits = append(*c.waits, d)
+	if len(*c.waits) >= c.after {
+		c.cancel()
+		return nil
+	}
+	return c.fixedClock.After(d)
+}
+
+func TestPollingProcessorJitter(t *testing.T) {
+	srv := newPollingServer(t, FeatureFlag{ID: 1, Name: "checkout", Environment: "production"})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var waits []time.Duration
+	clock := cancelingClock{fixedClock{time.Unix(1700000000, 0)}, &waits, 20, cancel}
+	client := NewClient(srv.URL, "key", nil, WithRetries(0, 0, 0), WithClock(clock))
+	processor := NewPollingProcessor(client, PollingOptions{Environment: "production", Interval: 10 * time.Second, Jitter: 0.2})
+	processor.Run(ctx)
+
+	require.Len(t, waits, 20)
+	for _, wait := range waits {
+		assert.GreaterOrEqual(t, wait, 8*time.Second)
+		assert.LessOrEqual(t, wait, 12*time.Second)
+	}
+	assert.NotEqual(t, waits[0], waits[1], "waits vary at random")
+	assert.True(t, processor.Initialized())
+
+	processor = NewPollingProcessor(client, PollingOptions{Environment: "production", Interval: time.Second, Jitter: -1})
+	assert.Equal(t, time.Second, processor.wait(), "a negative jitter disables it")
+}
@@ -0,0 +1,163 @@
+package matrixflag
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrPrerequisiteCycle is returned when the prerequisites of a flag lead back to it
+var ErrPrerequisiteCycle = errors.New("prerequisite cycle")
+
+// Prerequisite makes a flag serve its off value unless another flag of the
+// same environment serves a variation for the context
+type Prerequisite struct {
+	// Flag is the name of the prerequisite flag
+	Flag string `json:"flag"`
+	// Variation is the key of the variation the prerequisite must serve; when
+	// empty, the prerequisite must serve true
+	Variation string `json:"variation,omitempty"`
+}
+
+// satisfiedBy reports whether the evaluation of the prerequisite flag meets the prerequisite
+func (p Prerequisite) satisfiedBy(d EvaluationDetail[any]) bool {
+	if p.Variation == "" {
+		return d.Value == true
+	}
+	return d.Variation == p.Variation
+}
+
+// validatePrerequisites checks that the prerequisites of the flag named name
+// each name another flag, before they are sent
+func validatePrerequisites(name string, prerequisites []Prerequisite) error {
+	for i, p := range prerequisites {
+		switch {
+		case p.Flag == "":
+			return fmt.Errorf("invalid prerequisite %d: a flag is required", i)
+		case p.Flag == name:
+			return fmt.Errorf("invalid prerequisite %d: a flag cannot require itself", i)
+		}
+	}
+	return nil
+}
+
+// ValidatePrerequisites checks that the prerequisites of flags name other flags
+// of the list and existing variations of them, and that they contain no cycle
+func ValidatePrerequisites(flags []FeatureFlag) error {
+	byName := make(map[string]*FeatureFlag, len(flags))
+	for i := range flags {
+		byName[flags[i].Name] = &flags[i]
+	}
+	for _, flag := range flags {
+		for _, p := range flag.Prerequisites {
+			prereq, ok := byName[p.Flag]
+			switch {
+			case p.Flag == flag.Name:
+				return fmt.Errorf("invalid prerequisite of flag %s: a flag cannot require itself", flag.Name)
+			case !ok:
+				return fmt.Errorf("invalid prerequisite of flag %s: unknown flag %q", flag.Name, p.Flag)
+			case p.Variation != "" && !hasVariation(prereq.Variations, p.Variation):
+				return fmt.Errorf("invalid prerequisite of flag %s: flag %s has no variation %q", flag.Name, p.Flag, p.Variation)
+			}
+		}
+	}
+	if cycle := prerequisiteCycle(byName); cycle != nil {
+		return fmt.Errorf("%w: %s", ErrPrerequisiteCycle, strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
+// prerequisiteCycle returns the names along a cycle of prerequisites, starting
+// and ending with the same flag, or nil when there is none
+func prerequisiteCycle(flags map[string]*FeatureFlag) []string {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(flags))
+	var path []string
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		switch state[name] {
+		case visiting:
+			for i, n := range path {
+				if n == name {
+					return append(append([]string(nil), path[i:]...), name)
+				}
+			}
+		case done:
+			return nil
+		}
+		flag, ok := flags[name]
+		if !ok {
+			return nil
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, p := range flag.Prerequisites {
+			if cycle := visit(p.Flag); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = done
+		return nil
+	}
+
+	// Names are visited in order so the reported cycle does not depend on map order
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if cycle := visit(name); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// checkPrerequisites evaluates the prerequisites of a flag in order, with chain
+// holding the flags whose prerequisites are being evaluated. It returns the
+// detail to serve and true when a prerequisite fails: the off value of the flag
+// when a prerequisite is missing, archived or serves another variation, or an
+// error when the prerequisites form a cycle or a prerequisite fails to evaluate.
+func (ix *indexedRuleset) checkPrerequisites(flag *FeatureFlag, attributes map[string]any, chain []string, tr *EvaluationTrace) (EvaluationDetail[any], bool) {
+	if len(flag.Prerequisites) == 0 {
+		return EvaluationDetail[any]{}, false
+	}
+	// The chain is copied so sibling prerequisites do not share its backing array
+	chain = append(chain[:len(chain):len(chain)], flag.Name)
+	for _, p := range flag.Prerequisites {
+		for _, name := range chain {
+			if name == p.Flag {
+				err := fmt.Errorf("%w: %s -> %s", ErrPrerequisiteCycle, strings.Join(chain, " -> "), p.Flag)
+				return EvaluationDetail[any]{Reason: ReasonError, ErrorCode: ErrorGeneral, Err: err}, true
+			}
+		}
+		prereq, ok := ix.flags[p.Flag]
+		step := TraceStep{Check: CheckPrerequisite, Prerequisite: p.Flag}
+		// An archived prerequisite is failed as if it were deleted
+		ok = ok && !prereq.Archived()
+		if ok {
+			// The checks of the prerequisite itself are not traced, only its result
+			d := ix.evaluateFlag(prereq, attributes, chain, nil)
+			if d.Reason == ReasonError {
+				tr.add(step)
+				return d, true
+			}
+			ok = p.satisfiedBy(d)
+			step.Variation = d.Variation
+		}
+		step.Passed = ok
+		tr.add(step)
+		if !ok {
+			d := offDetail(flag, ReasonPrerequisiteFailed)
+			d.Prerequisite = p.Flag
+			return d, true
+		}
+	}
+	return EvaluationDetail[any]{}, false
+}
@@ -0,0 +1,108 @@
+package matrixflag
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluatePrerequisites(t *testing.T) {
+	variations := []Variation{
+		{Key: "control", Value: "A", Weight: 0},
+		{Key: "treatment", Value: "B", Weight: 100},
+	}
+	evaluator := NewEvaluator(NewClient("http://localhost", "key", nil), EvaluatorOptions{Environment: "production"})
+	evaluator.SetRuleset(&Ruleset{Environment: "production", Flags: []FeatureFlag{
+		{ID: 1, Name: "checkout", IsActive: true},
+		{ID: 2, Name: "checkout-ab", IsActive: true, Variations: variations},
+		{ID: 3, Name: "express", IsActive: true, Prerequisites: []Prerequisite{{Flag: "checkout"}}},
+		{ID: 4, Name: "express-b", IsActive: true, Prerequisites: []Prerequisite{{Flag: "express"}, {Flag: "checkout-ab", Variation: "treatment"}}},
+		{ID: 5, Name: "express-a", IsActive: true, Variations: variations, OffVariation: "control",
+			Prerequisites: []Prerequisite{{Flag: "checkout-ab", Variation: "control"}}},
+		{ID: 6, Name: "legacy", IsActive: true, Prerequisites: []Prerequisite{{Flag: "removed"}}},
+		{ID: 7, Name: "ping", IsActive: true, Prerequisites: []Prerequisite{{Flag: "pong"}}},
+		{ID: 8, Name: "pong", IsActive: true, Prerequisites: []Prerequisite{{Flag: "ping"}}},
+		{ID: 9, Name: "ping-user", IsActive: true, Prerequisites: []Prerequisite{{Flag: "ping"}}},
+	}})
+	user := EvaluationContext{Key: "user-1"}
+
+	d := evaluator.Evaluate("express-b", user, false)
+	assert.Equal(t, true, d.Value, "every prerequisite is met")
+	assert.Equal(t, ReasonDefault, d.Reason)
+
+	d = evaluator.Evaluate("express-a", user, nil)
+	assert.Equal(t, "A", d.Value, "the off variation is served")
+	assert.Equal(t, ReasonPrerequisiteFailed, d.Reason)
+	assert.Equal(t, "checkout-ab", d.Prerequisite)
+
+	d = evaluator.Evaluate("legacy", user, true)
+	assert.Equal(t, false, d.Value, "a missing prerequisite is never met")
+	assert.Equal(t, "removed", d.Prerequisite)
+	assert.Equal(t, "removed", evaluator.EvaluateBool("legacy", user, true).Prerequisite, "typed details keep the prerequisite")
+
+	d = evaluator.Evaluate("ping-user", user, true)
+	require.ErrorIs(t, d.Err, ErrPrerequisiteCycle)
+	assert.EqualError(t, d.Err, "prerequisite cycle: ping-user -> ping -> pong -> ping")
+	assert.Equal(t, true, d.Value, "a cycle serves the default value")
+	assert.Equal(t, ErrorGeneral, d.ErrorCode)
+
+	ruleset := evaluator.Ruleset()
+	ruleset.Flags[0].IsActive = false
+	evaluator.SetRuleset(ruleset)
+	d = evaluator.Evaluate("express-b", user, true)
+	assert.Equal(t, false, d.Value, "prerequisites apply transitively")
+	assert.Equal(t, "express", d.Prerequisite)
+}
+
+func TestValidatePrerequisites(t *testing.T) {
+	variations := []Variation{{Key: "on", Value: true, Weight: 100}}
+	assert.NoError(t, ValidatePrerequisites([]FeatureFlag{
+		{Name: "a", Variations: variations},
+		{Name: "b", Prerequisites: []Prerequisite{{Flag: "a", Variation: "on"}}},
+		{Name: "c", Prerequisites: []Prerequisite{{Flag: "a"}, {Flag: "b"}}},
+	}))
+	assert.ErrorContains(t, ValidatePrerequisites([]FeatureFlag{
+		{Name: "a", Prerequisites: []Prerequisite{{Flag: "a"}}},
+	}), "cannot require itself")
+	assert.ErrorContains(t, ValidatePrerequisites([]FeatureFlag{
+		{Name: "a", Prerequisites: []Prerequisite{{Flag: "b"}}},
+	}), `unknown flag "b"`)
+	assert.ErrorContains(t, ValidatePrerequisites([]FeatureFlag{
+		{Name: "a", Variations: variations},
+		{Name: "b", Prerequisites: []Prerequisite{{Flag: "a", Variation: "off"}}},
+	}), `no variation "off"`)
+
+	err := ValidatePrerequisites([]FeatureFlag{
+		{Name: "c", Prerequisites: []Prerequisite{{Flag: "a"}}},
+		{Name: "a", Prerequisites: []Prerequisite{{Flag: "b"}}},
+		{Name: "b", Prerequisites: []Prerequisite{{Flag: "c"}}},
+	})
+	assert.ErrorIs(t, err, ErrPrerequisiteCycle)
+	assert.EqualError(t, err, "prerequisite cycle: a -> b -> c -> a")
+}
+
+func TestCreateFlagQualifiesPrerequisites(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		writeJSON(w, FeatureFlag{ID: 3, Name: "team.express", Prerequisites: []Prerequisite{{Flag: "team.checkout"}}})
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil, WithNamespace("team"))
+
+	prerequisites := []Prerequisite{{Flag: "checkout"}}
+	flag, err := client.CreateFeatureFlag(context.Background(), FeatureFlagCreate{Name: "express", Environment: "production", Prerequisites: prerequisites})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"team.express","is_active":false,"environment":"production","prerequisites":[{"flag":"team.checkout"}]}`, body)
+	assert.Equal(t, "checkout", prerequisites[0].Flag, "the caller's prerequisites are not modified")
+	assert.Equal(t, []Prerequisite{{Flag: "checkout"}}, flag.Prerequisites)
+
+	_, err = client.CreateFeatureFlag(context.Background(), FeatureFlagCreate{Name: "express", Prerequisites: []Prerequisite{{Flag: "express"}}})
+	assert.ErrorContains(t, err, "cannot require itself")
+}
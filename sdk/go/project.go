@@ -0,0 +1,106 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Project groups the flags of an application across environments; flags
+// reference it with FeatureFlag.ProjectID
+type Project struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ProjectCreate represents the data needed to create a project
+type ProjectCreate struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// ProjectUpdate represents the data needed to update a project
+type ProjectUpdate struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Fields names fields sent even when empty, such as FieldDescription to clear the description
+	Fields FieldMask `json:"-"`
+}
+
+// ListProjects retrieves all projects
+func (c *Client) ListProjects(ctx context.Context, reqOpts ...RequestOption) ([]Project, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	respBody, err := c.doRequest(ctx, request{
+		method: "GET",
+		path:   "/api/v1/projects/",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []Project
+	if err := json.Unmarshal(respBody, &projects); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return projects, nil
+}
+
+// GetProject retrieves a project by ID
+func (c *Client) GetProject(ctx context.Context, id int, reqOpts ...RequestOption) (*Project, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.projectRequest(ctx, request{
+		method: "GET",
+		path:   fmt.Sprintf("/api/v1/projects/%d", id),
+	})
+}
+
+// CreateProject creates a new project
+func (c *Client) CreateProject(ctx context.Context, project ProjectCreate, reqOpts ...RequestOption) (*Project, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if project.Name == "" {
+		return nil, fmt.Errorf("project name is required")
+	}
+	return c.projectRequest(ctx, request{
+		method: "POST",
+		path:   "/api/v1/projects/",
+		body:   project,
+	})
+}
+
+// UpdateProject updates a project
+func (c *Client) UpdateProject(ctx context.Context, id int, project ProjectUpdate, reqOpts ...RequestOption) (*Project, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.projectRequest(ctx, request{
+		method: "PUT",
+		path:   fmt.Sprintf("/api/v1/projects/%d", id),
+		body:   project,
+	})
+}
+
+// DeleteProject deletes a project
+func (c *Client) DeleteProject(ctx context.Context, id int, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	_, err := c.doRequest(ctx, request{
+		method: "DELETE",
+		path:   fmt.Sprintf("/api/v1/projects/%d", id),
+	})
+	return err
+}
+
+// projectRequest performs a request returning a project
+func (c *Client) projectRequest(ctx context.Context, req request) (*Project, error) {
+	respBody, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var project Project
+	if err := json.Unmarshal(respBody, &project); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return &project, nil
+}
@@ -0,0 +1,57 @@
+package matrixflag
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjects(t *testing.T) {
+	var requests, bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		bodies = append(bodies, string(body))
+		switch {
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/api/v1/projects/" && r.Method == http.MethodGet:
+			writeJSON(w, []Project{{ID: 3, Name: "checkout"}})
+		default:
+			writeJSON(w, Project{ID: 3, Name: "checkout"})
+		}
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+
+	projects, err := client.ListProjects(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []Project{{ID: 3, Name: "checkout"}}, projects)
+	project, err := client.CreateProject(ctx, ProjectCreate{Name: "checkout", Description: "Checkout service"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, project.ID)
+	_, err = client.GetProject(ctx, 3)
+	require.NoError(t, err)
+	_, err = client.UpdateProject(ctx, 3, ProjectUpdate{Fields: []string{FieldDescription}})
+	require.NoError(t, err)
+	require.NoError(t, client.DeleteProject(ctx, 3))
+
+	assert.Equal(t, []string{
+		"GET /api/v1/projects/",
+		"POST /api/v1/projects/",
+		"GET /api/v1/projects/3",
+		"PUT /api/v1/projects/3",
+		"DELETE /api/v1/projects/3",
+	}, requests)
+	assert.JSONEq(t, `{"name":"checkout","description":"Checkout service"}`, bodies[1])
+	assert.JSONEq(t, `{"description":""}`, bodies[3], "named fields are sent to clear them")
+
+	_, err = client.CreateProject(ctx, ProjectCreate{})
+	assert.Error(t, err)
+}
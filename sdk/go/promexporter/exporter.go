@@ -0,0 +1,154 @@
+// Package promexporter publishes the state of Matrix Flag feature flags as
+// Prometheus metrics, so alerting rules can fire when a kill switch flips.
+package promexporter
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultInterval is the default interval between flag refreshes
+const DefaultInterval = 30 * time.Second
+
+// Options configures an Exporter
+type Options struct {
+	// Environments limits the exported flags to the given environments; all flags are exported when empty
+	Environments []string
+	// Interval is the time between flag refreshes
+	Interval time.Duration
+	// Namespace is the metric namespace, "matrixflag" by default
+	Namespace string
+}
+
+// Exporter is a prometheus.Collector exposing the last fetched state of every flag
+type Exporter struct {
+	client *matrixflag.Client
+	opts   Options
+
+	active      *prometheus.Desc
+	rollout     *prometheus.Desc
+	lastRefresh *prometheus.Desc
+	refreshErrs prometheus.Counter
+
+	mu        sync.RWMutex
+	flags     []matrixflag.FeatureFlag
+	refreshed time.Time
+}
+
+// New creates a new flag state exporter. Call Run to keep it up to date.
+func New(client *matrixflag.Client, opts Options) *Exporter {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.Namespace == "" {
+		opts.Namespace = "matrixflag"
+	}
+
+	labels := []string{"flag", "environment", "project_id"}
+	return &Exporter{
+		client: client,
+		opts:   opts,
+		active: prometheus.NewDesc(
+			prometheus.BuildFQName(opts.Namespace, "flag", "active"),
+			"Whether the feature flag is active (1) or not (0).",
+			labels, nil,
+		),
+		rollout: prometheus.NewDesc(
+			prometheus.BuildFQName(opts.Namespace, "flag", "rollout_percentage"),
+			"Percentage of contexts the feature flag is rolled out to, 100 without a percentage rollout.",
+			labels, nil,
+		),
+		lastRefresh: prometheus.NewDesc(
+			prometheus.BuildFQName(opts.Namespace, "flag", "last_refresh_timestamp_seconds"),
+			"Unix time of the last successful flag refresh.",
+			nil, nil,
+		),
+		refreshErrs: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: opts.Namespace,
+			Subsystem: "flag",
+			Name:      "refresh_errors_total",
+			Help:      "Total number of failed flag refreshes.",
+		}),
+	}
+}
+
+// Run refreshes the flag state immediately and then every interval until ctx is canceled
+func (e *Exporter) Run(ctx context.Context) {
+	for {
+		_ = e.Refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-e.client.Clock().After(e.opts.Interval):
+		}
+	}
+}
+
+// Refresh fetches the current flag state from the API
+func (e *Exporter) Refresh(ctx context.Context) error {
+	environments := e.opts.Environments
+	if len(environments) == 0 {
+		environments = []string{""}
+	}
+
+	var flags []matrixflag.FeatureFlag
+	for _, env := range environments {
+		page, err := e.client.ListFeatureFlags(ctx, matrixflag.FlagFilter{Environment: env})
+		if err != nil {
+			e.refreshErrs.Inc()
+			return err
+		}
+		flags = append(flags, page...)
+	}
+
+	e.mu.Lock()
+	e.flags = flags
+	e.refreshed = e.client.Clock().Now()
+	e.mu.Unlock()
+	return nil
+}
+
+// Describe implements prometheus.Collector
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.active
+	ch <- e.rollout
+	ch <- e.lastRefresh
+	e.refreshErrs.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for _, flag := range e.flags {
+		labels := []string{flag.Name, flag.Environment, strconv.Itoa(flag.ProjectID)}
+		ch <- prometheus.MustNewConstMetric(e.active, prometheus.GaugeValue, boolValue(flag.IsActive), labels...)
+		ch <- prometheus.MustNewConstMetric(e.rollout, prometheus.GaugeValue, rolloutPercentage(flag), labels...)
+	}
+	if !e.refreshed.IsZero() {
+		ch <- prometheus.MustNewConstMetric(e.lastRefresh, prometheus.GaugeValue, float64(e.refreshed.Unix()))
+	}
+	e.refreshErrs.Collect(ch)
+}
+
+// rolloutPercentage returns the rollout percentage of a flag, 100 when it has no rollout
+func rolloutPercentage(flag matrixflag.FeatureFlag) float64 {
+	if flag.Rollout == nil {
+		return 100
+	}
+	return flag.Rollout.Percentage
+}
+
+// boolValue converts a boolean into a gauge value
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,31 @@
+package promexporter
+
+import (
+	"strings"
+	"testing"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollectFlagGauges(t *testing.T) {
+	e := New(matrixflag.NewClient("http://localhost", "key", nil), Options{})
+	e.flags = []matrixflag.FeatureFlag{
+		{Name: "checkout", Environment: "production", IsActive: true, Rollout: &matrixflag.PercentageRollout{Percentage: 25}},
+		{Name: "dark-mode", Environment: "production", ProjectID: 2},
+	}
+
+	expected := `
+# HELP matrixflag_flag_active Whether the feature flag is active (1) or not (0).
+# TYPE matrixflag_flag_active gauge
+matrixflag_flag_active{environment="production",flag="checkout",project_id="0"} 1
+matrixflag_flag_active{environment="production",flag="dark-mode",project_id="2"} 0
+# HELP matrixflag_flag_rollout_percentage Percentage of contexts the feature flag is rolled out to, 100 without a percentage rollout.
+# TYPE matrixflag_flag_rollout_percentage gauge
+matrixflag_flag_rollout_percentage{environment="production",flag="checkout",project_id="0"} 25
+matrixflag_flag_rollout_percentage{environment="production",flag="dark-mode",project_id="2"} 100
+`
+	if err := testutil.CollectAndCompare(e, strings.NewReader(expected), "matrixflag_flag_active", "matrixflag_flag_rollout_percentage"); err != nil {
+		t.Fatal(err)
+	}
+}
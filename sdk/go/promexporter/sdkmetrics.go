@@ -0,0 +1,137 @@
+package promexporter
+
+import (
+	"strconv"
+	"time"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SDKMetrics is a matrixflag.Metrics hook and a prometheus.Collector exposing
+// the health of a client: API requests, retries, local ruleset lookups,
+// evaluations, evaluations of expired flags and the flag stream connection.
+//
+//	metrics := promexporter.NewSDKMetrics("")
+//	prometheus.MustRegister(metrics)
+//	client := matrixflag.New(apiKey, matrixflag.WithMetrics(metrics))
+type SDKMetrics struct {
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	retries         *prometheus.CounterVec
+	cacheLookups    *prometheus.CounterVec
+	evaluations     *prometheus.CounterVec
+	expired         *prometheus.CounterVec
+	streamConnected prometheus.Gauge
+}
+
+var _ matrixflag.Metrics = (*SDKMetrics)(nil)
+
+// NewSDKMetrics creates the SDK metrics. The namespace is "matrixflag" when empty.
+func NewSDKMetrics(namespace string) *SDKMetrics {
+	if namespace == "" {
+		namespace = "matrixflag"
+	}
+	return &SDKMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "sdk",
+			Name:      "requests_total",
+			Help:      "Total number of API requests by final status code, 0 when no response was received.",
+		}, []string{"method", "endpoint", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "sdk",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of API requests including retries.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "endpoint"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "sdk",
+			Name:      "request_retries_total",
+			Help:      "Total number of retried API requests.",
+		}, []string{"method", "endpoint"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "sdk",
+			Name:      "cache_lookups_total",
+			Help:      "Total number of flag lookups in the local ruleset by result, hit or miss.",
+		}, []string{"result"}),
+		evaluations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "sdk",
+			Name:      "evaluations_total",
+			Help:      "Total number of flag evaluations by flag and reason.",
+		}, []string{"flag", "reason"}),
+		expired: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "sdk",
+			Name:      "expired_flag_evaluations_total",
+			Help:      "Total number of local evaluations of flags past their expiry, by flag.",
+		}, []string{"flag"}),
+		streamConnected: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "sdk",
+			Name:      "stream_connected",
+			Help:      "Whether the flag stream is connected (1) or not (0).",
+		}),
+	}
+}
+
+// RequestDone implements matrixflag.Metrics
+func (m *SDKMetrics) RequestDone(method string, class matrixflag.EndpointClass, status int, duration time.Duration) {
+	m.requests.WithLabelValues(method, string(class), strconv.Itoa(status)).Inc()
+	m.requestDuration.WithLabelValues(method, string(class)).Observe(duration.Seconds())
+}
+
+// RequestRetried implements matrixflag.Metrics
+func (m *SDKMetrics) RequestRetried(method string, class matrixflag.EndpointClass) {
+	m.retries.WithLabelValues(method, string(class)).Inc()
+}
+
+// CacheLookup implements matrixflag.Metrics
+func (m *SDKMetrics) CacheLookup(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.cacheLookups.WithLabelValues(result).Inc()
+}
+
+// FlagEvaluated implements matrixflag.Metrics
+func (m *SDKMetrics) FlagEvaluated(flagKey string, reason matrixflag.EvaluationReason) {
+	m.evaluations.WithLabelValues(flagKey, string(reason)).Inc()
+}
+
+// ExpiredFlagEvaluated implements matrixflag.Metrics
+func (m *SDKMetrics) ExpiredFlagEvaluated(flagKey string) {
+	m.expired.WithLabelValues(flagKey).Inc()
+}
+
+// StreamStatus implements matrixflag.Metrics
+func (m *SDKMetrics) StreamStatus(connected bool) {
+	m.streamConnected.Set(boolValue(connected))
+}
+
+// Describe implements prometheus.Collector
+func (m *SDKMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.requests.Describe(ch)
+	m.requestDuration.Describe(ch)
+	m.retries.Describe(ch)
+	m.cacheLookups.Describe(ch)
+	m.evaluations.Describe(ch)
+	m.expired.Describe(ch)
+	m.streamConnected.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (m *SDKMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.requests.Collect(ch)
+	m.requestDuration.Collect(ch)
+	m.retries.Collect(ch)
+	m.cacheLookups.Collect(ch)
+	m.evaluations.Collect(ch)
+	m.expired.Collect(ch)
+	m.streamConnected.Collect(ch)
+}
@@ -0,0 +1,72 @@
+package promexporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestSDKMetrics(t *testing.T) {
+	failed := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !failed {
+			failed = true
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	metrics := NewSDKMetrics("")
+	client := matrixflag.New("key", matrixflag.WithBaseURL(srv.URL), matrixflag.WithRetries(1, time.Millisecond, time.Millisecond), matrixflag.WithMetrics(metrics))
+	if _, err := client.ListFeatureFlags(context.Background(), matrixflag.FlagFilter{}); err != nil {
+		t.Fatal(err)
+	}
+
+	expiresAt := time.Now().Add(-time.Hour)
+	evaluator := matrixflag.NewEvaluator(client, matrixflag.EvaluatorOptions{Environment: "production"})
+	evaluator.SetRuleset(&matrixflag.Ruleset{Environment: "production", Flags: []matrixflag.FeatureFlag{
+		{ID: 1, Name: "checkout", Environment: "production", IsActive: true, ExpiresAt: &expiresAt},
+	}})
+	user := matrixflag.EvaluationContext{Key: "user-1"}
+	evaluator.EvaluateBool("checkout", user, false)
+	evaluator.EvaluateBool("checkout", user, false)
+	evaluator.EvaluateBool("search", user, false)
+
+	expected := `
+# HELP matrixflag_sdk_cache_lookups_total Total number of flag lookups in the local ruleset by result, hit or miss.
+# TYPE matrixflag_sdk_cache_lookups_total counter
+matrixflag_sdk_cache_lookups_total{result="hit"} 2
+matrixflag_sdk_cache_lookups_total{result="miss"} 1
+# HELP matrixflag_sdk_evaluations_total Total number of flag evaluations by flag and reason.
+# TYPE matrixflag_sdk_evaluations_total counter
+matrixflag_sdk_evaluations_total{flag="checkout",reason="DEFAULT"} 2
+matrixflag_sdk_evaluations_total{flag="search",reason="ERROR"} 1
+# HELP matrixflag_sdk_expired_flag_evaluations_total Total number of local evaluations of flags past their expiry, by flag.
+# TYPE matrixflag_sdk_expired_flag_evaluations_total counter
+matrixflag_sdk_expired_flag_evaluations_total{flag="checkout"} 2
+# HELP matrixflag_sdk_request_retries_total Total number of retried API requests.
+# TYPE matrixflag_sdk_request_retries_total counter
+matrixflag_sdk_request_retries_total{endpoint="read",method="GET"} 1
+# HELP matrixflag_sdk_requests_total Total number of API requests by final status code, 0 when no response was received.
+# TYPE matrixflag_sdk_requests_total counter
+matrixflag_sdk_requests_total{endpoint="read",method="GET",status="200"} 1
+`
+	if err := testutil.CollectAndCompare(metrics, strings.NewReader(expected),
+		"matrixflag_sdk_cache_lookups_total", "matrixflag_sdk_evaluations_total", "matrixflag_sdk_expired_flag_evaluations_total",
+		"matrixflag_sdk_request_retries_total", "matrixflag_sdk_requests_total"); err != nil {
+		t.Fatal(err)
+	}
+
+	metrics.StreamStatus(true)
+	if got := testutil.ToFloat64(metrics.streamConnected); got != 1 {
+		t.Fatalf("stream_connected = %v, want 1", got)
+	}
+}
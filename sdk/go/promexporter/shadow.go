@@ -0,0 +1,57 @@
+package promexporter
+
+import (
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ShadowCollector is a prometheus.Collector exposing the counts of a shadow evaluator
+type ShadowCollector struct {
+	shadow *matrixflag.ShadowEvaluator
+
+	evaluations   *prometheus.Desc
+	discrepancies *prometheus.Desc
+	shadowErrors  *prometheus.Desc
+}
+
+// NewShadowCollector creates a collector for a shadow evaluator. The namespace is
+// "matrixflag" when empty, and name tells several shadow evaluators apart.
+func NewShadowCollector(shadow *matrixflag.ShadowEvaluator, namespace, name string) *ShadowCollector {
+	if namespace == "" {
+		namespace = "matrixflag"
+	}
+	labels := prometheus.Labels{"shadow": name}
+	return &ShadowCollector{
+		shadow: shadow,
+		evaluations: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "shadow", "evaluations_total"),
+			"Total number of shadow evaluations.",
+			nil, labels,
+		),
+		discrepancies: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "shadow", "discrepancies_total"),
+			"Total number of shadow evaluations that disagreed with the primary evaluation.",
+			nil, labels,
+		),
+		shadowErrors: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "shadow", "errors_total"),
+			"Total number of failed shadow evaluations.",
+			nil, labels,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *ShadowCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.evaluations
+	ch <- c.discrepancies
+	ch <- c.shadowErrors
+}
+
+// Collect implements prometheus.Collector
+func (c *ShadowCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.shadow.Stats()
+	ch <- prometheus.MustNewConstMetric(c.evaluations, prometheus.CounterValue, float64(stats.Evaluations))
+	ch <- prometheus.MustNewConstMetric(c.discrepancies, prometheus.CounterValue, float64(stats.Discrepancies))
+	ch <- prometheus.MustNewConstMetric(c.shadowErrors, prometheus.CounterValue, float64(stats.ShadowErrors))
+}
@@ -0,0 +1,55 @@
+package promexporter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestShadowCollector(t *testing.T) {
+	primary := func(_ context.Context, flagKey string, _ matrixflag.EvaluationContext) (any, error) {
+		return true, nil
+	}
+	shadow := func(_ context.Context, flagKey string, _ matrixflag.EvaluationContext) (any, error) {
+		switch flagKey {
+		case "checkout":
+			return false, nil
+		case "search":
+			return nil, errors.New("local engine not ready")
+		}
+		return true, nil
+	}
+	evaluator := matrixflag.NewShadowEvaluator(primary, shadow, matrixflag.ShadowOptions{})
+	user := matrixflag.EvaluationContext{Key: "user-1"}
+	for _, flag := range []string{"checkout", "search", "banner", "banner"} {
+		evaluator.Evaluate(context.Background(), flag, user)
+	}
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(NewShadowCollector(evaluator, "", "local"))
+	expected := `
+# HELP matrixflag_shadow_discrepancies_total Total number of shadow evaluations that disagreed with the primary evaluation.
+# TYPE matrixflag_shadow_discrepancies_total counter
+matrixflag_shadow_discrepancies_total{shadow="local"} 1
+# HELP matrixflag_shadow_errors_total Total number of failed shadow evaluations.
+# TYPE matrixflag_shadow_errors_total counter
+matrixflag_shadow_errors_total{shadow="local"} 1
+# HELP matrixflag_shadow_evaluations_total Total number of shadow evaluations.
+# TYPE matrixflag_shadow_evaluations_total counter
+matrixflag_shadow_evaluations_total{shadow="local"} 4
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Several shadow evaluators are told apart by name
+	registry.MustRegister(NewShadowCollector(matrixflag.NewShadowEvaluator(primary, primary, matrixflag.ShadowOptions{}), "", "edge"))
+	if n, err := testutil.GatherAndCount(registry, "matrixflag_shadow_evaluations_total"); err != nil || n != 2 {
+		t.Fatalf("expected 2 series, got %d: %v", n, err)
+	}
+}
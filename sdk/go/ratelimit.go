@@ -0,0 +1,133 @@
+package matrixflag
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Rate limit headers of API responses
+const (
+	// RateLimitLimitHeader holds the number of requests allowed in a window
+	RateLimitLimitHeader = "X-RateLimit-Limit"
+	// RateLimitRemainingHeader holds the number of requests left in the current window
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	// RateLimitResetHeader holds the Unix time the window resets at
+	RateLimitResetHeader = "X-RateLimit-Reset"
+)
+
+// RateLimit is the rate limit of the API key as of the latest response
+type RateLimit struct {
+	// Limit is the number of requests allowed in a window, zero when not sent
+	Limit int
+	// Remaining is the number of requests left in the current window
+	Remaining int
+	// Reset is when the current window ends, zero when not sent
+	Reset time.Time
+}
+
+// parseRateLimit reads the rate limit headers of a response, reporting false
+// when the response has no valid X-RateLimit-Remaining header
+func parseRateLimit(header http.Header) (RateLimit, bool) {
+	remaining, err := strconv.Atoi(header.Get(RateLimitRemainingHeader))
+	if err != nil {
+		return RateLimit{}, false
+	}
+	limit := RateLimit{Remaining: remaining}
+	limit.Limit, _ = strconv.Atoi(header.Get(RateLimitLimitHeader))
+	if unix, err := strconv.ParseInt(header.Get(RateLimitResetHeader), 10, 64); err == nil {
+		limit.Reset = time.Unix(unix, 0)
+	}
+	return limit, true
+}
+
+// rateLimitReset returns when the rate limit of a response resets if the
+// response used it up, or the zero time
+func rateLimitReset(header http.Header, now time.Time) time.Time {
+	limit, ok := parseRateLimit(header)
+	if !ok || limit.Remaining > 0 || !limit.Reset.After(now) {
+		return time.Time{}
+	}
+	return limit.Reset
+}
+
+// RateLimit returns the rate limit reported by the latest response carrying
+// rate limit headers, and false before any such response
+func (c *Client) RateLimit() (RateLimit, bool) {
+	limit := c.rateLimit.Load()
+	if limit == nil {
+		return RateLimit{}, false
+	}
+	return *limit, true
+}
+
+// observeRateLimit records the rate limit reported by a response
+func (c *Client) observeRateLimit(header http.Header) {
+	if limit, ok := parseRateLimit(header); ok {
+		c.rateLimit.Store(&limit)
+	}
+}
+
+// WithRateLimiter paces the requests of the client to at most requestsPerSecond
+// on average, allowing bursts of up to burst requests, so bulk scripts keep
+// under the API's rate limit instead of failing with 429 responses. Requests,
+// including retries, wait for the limiter before they are sent, and also wait
+// for the window to reset once a response has used up the rate limit. A
+// requestsPerSecond of zero or less disables the limiter.
+func WithRateLimiter(requestsPerSecond float64, burst int) ClientOption {
+	return func(c *Client) {
+		if requestsPerSecond <= 0 {
+			c.limiter = nil
+			return
+		}
+		c.limiter = &tokenBucket{rate: requestsPerSecond, burst: float64(max(burst, 1))}
+	}
+}
+
+// tokenBucket is a token bucket refilled at rate tokens per second up to burst
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	// last is when tokens was last refilled, zero while the bucket is unused
+	last time.Time
+}
+
+// reserve takes a token, returning how long to wait for it to be available
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.last.IsZero() {
+		b.tokens = b.burst
+	} else if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+	}
+	if now.After(b.last) {
+		b.last = now
+	}
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// pace waits before a request is sent, for a token of the limiter and for a
+// used up rate limit to reset; requests are not paced without a limiter
+func (c *Client) pace(ctx context.Context) error {
+	if c.limiter == nil {
+		return nil
+	}
+	now := c.clock.Now()
+	wait := c.limiter.reserve(now)
+	if limit, ok := c.RateLimit(); ok && limit.Remaining <= 0 {
+		wait = max(wait, min(limit.Reset.Sub(now), c.config.MaxRetryDelay))
+	}
+	if wait <= 0 {
+		return nil
+	}
+	return sleep(ctx, c.clock, wait)
+}
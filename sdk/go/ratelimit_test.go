@@ -0,0 +1,94 @@
+package matrixflag
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stoppedClock is a fixedClock whose waits never end
+type stoppedClock struct {
+	fixedClock
+}
+
+func (stoppedClock) After(time.Duration) <-chan time.Time { return nil }
+
+func TestClientRateLimit(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	remaining := 2
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if remaining >= 0 {
+			w.Header().Set(RateLimitLimitHeader, "100")
+			w.Header().Set(RateLimitRemainingHeader, strconv.Itoa(remaining))
+			w.Header().Set(RateLimitResetHeader, strconv.FormatInt(now.Unix()+5, 10))
+		}
+		remaining--
+		writeJSON(w, []FeatureFlag{})
+	}))
+	defer srv.Close()
+	var waits []time.Duration
+	client := NewClient(srv.URL, "key", nil, WithClock(sleepRecorder{fixedClock{now}, &waits}))
+	ctx := context.Background()
+
+	_, ok := client.RateLimit()
+	assert.False(t, ok, "unknown before the first response")
+	_, err := client.ListFeatureFlags(ctx, FlagFilter{})
+	require.NoError(t, err)
+	limit, ok := client.RateLimit()
+	require.True(t, ok)
+	assert.Equal(t, RateLimit{Limit: 100, Remaining: 2, Reset: now.Add(5 * time.Second)}, limit)
+
+	for i := 0; i < 3; i++ {
+		_, err = client.ListFeatureFlags(ctx, FlagFilter{})
+		require.NoError(t, err)
+	}
+	limit, _ = client.RateLimit()
+	assert.Equal(t, 0, limit.Remaining, "responses without the headers keep the latest rate limit")
+	assert.Empty(t, waits, "requests are not paced without a limiter")
+}
+
+func TestWithRateLimiter(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	exhausted := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if exhausted {
+			w.Header().Set(RateLimitRemainingHeader, "0")
+			w.Header().Set(RateLimitResetHeader, strconv.FormatInt(now.Unix()+3, 10))
+		}
+		writeJSON(w, []FeatureFlag{})
+	}))
+	defer srv.Close()
+	var waits []time.Duration
+	client := NewClient(srv.URL, "key", nil, WithRateLimiter(10, 2), WithClock(sleepRecorder{fixedClock{now}, &waits}))
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		_, err := client.ListFeatureFlags(ctx, FlagFilter{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, waits,
+		"a burst of 2 goes straight through, later requests wait for their token")
+
+	waits = nil
+	client = NewClient(srv.URL, "key", nil, WithRateLimiter(1000, 10), WithClock(sleepRecorder{fixedClock{now}, &waits}))
+	exhausted = true
+	for i := 0; i < 2; i++ {
+		_, err := client.ListFeatureFlags(ctx, FlagFilter{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []time.Duration{3 * time.Second}, waits, "a used up rate limit is waited out")
+
+	client = NewClient(srv.URL, "key", nil, WithRateLimiter(1000, 10), WithClock(stoppedClock{fixedClock{now}}))
+	_, err := client.ListFeatureFlags(ctx, FlagFilter{})
+	require.NoError(t, err)
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = client.ListFeatureFlags(canceled, FlagFilter{})
+	assert.ErrorContains(t, err, "canceled while waiting for the rate limiter")
+}
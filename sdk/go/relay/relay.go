@@ -0,0 +1,418 @@
+// Package relay serves Matrix Flag flags to many SDK instances from one
+// upstream connection to the API. A Relay keeps the rulesets of its
+// environments in memory, syncing them periodically and applying the changes
+// of one flag stream, and serves the read endpoints of the API that clients
+// and evaluators use: flag, layer, holdout, segment and defaults lists, remote
+// evaluations and the flag stream. Clients point their base URL at the relay;
+// writes are not relayed.
+package relay
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+// heartbeatInterval is the time between comments sent on idle downstream streams,
+// so proxies do not close them
+const heartbeatInterval = 30 * time.Second
+
+// subscriberBuffer is the number of events buffered for a downstream stream;
+// a stream falling further behind is closed, and its client reconnects and resyncs
+const subscriberBuffer = 64
+
+// Options configures a Relay
+type Options struct {
+	// Environments are the environments served by the relay; at least one is required
+	Environments []string
+	// Keys are the API keys accepted from downstream clients; any request is
+	// served when empty, in which case the relay must only be reachable from a
+	// private network
+	Keys []string
+	// SyncInterval is the time between ruleset syncs, matrixflag.DefaultSyncInterval by default
+	SyncInterval time.Duration
+	// SnapshotDir keeps a snapshot of each environment's ruleset, so a restarted
+	// relay serves flags while the API is unreachable, see matrixflag.EvaluatorOptions.SnapshotFile
+	SnapshotDir string
+	// OnError is called when a sync or the upstream stream fails; the relay keeps serving the last synced rulesets
+	OnError func(error)
+}
+
+// Relay is an http.Handler serving the flags of its environments. Call Run to keep them up to date.
+type Relay struct {
+	client     *matrixflag.Client
+	opts       Options
+	evaluators map[string]*matrixflag.Evaluator
+	mux        *http.ServeMux
+
+	mu          sync.Mutex
+	subscribers map[chan matrixflag.FlagEvent]string
+}
+
+// New creates a relay fetching flags with client, which must not limit its
+// flag streams to one environment when the relay serves several
+func New(client *matrixflag.Client, opts Options) (*Relay, error) {
+	if len(opts.Environments) == 0 {
+		return nil, errors.New("invalid relay options: at least one environment is required")
+	}
+	r := &Relay{
+		client:      client,
+		opts:        opts,
+		evaluators:  make(map[string]*matrixflag.Evaluator, len(opts.Environments)),
+		subscribers: make(map[chan matrixflag.FlagEvent]string),
+	}
+	for _, env := range opts.Environments {
+		if env == "" {
+			return nil, errors.New("invalid relay options: environment names must not be empty")
+		}
+		evalOpts := matrixflag.EvaluatorOptions{Environment: env, SyncInterval: opts.SyncInterval, OnError: opts.OnError}
+		if opts.SnapshotDir != "" {
+			evalOpts.SnapshotFile = filepath.Join(opts.SnapshotDir, url.PathEscape(env)+".json")
+		}
+		r.evaluators[env] = matrixflag.NewEvaluator(client, evalOpts)
+	}
+
+	r.mux = http.NewServeMux()
+	r.mux.HandleFunc("/healthz", r.handleHealth)
+	r.mux.HandleFunc("/api/v1/feature-flags/", r.handleFlags)
+	r.mux.HandleFunc("/api/v1/feature-flags/evaluate", r.handleEvaluate)
+	r.mux.HandleFunc("/api/v1/feature-flags/stream", r.handleStream)
+	r.mux.HandleFunc("/api/v1/layers/", r.handleLayers)
+	r.mux.HandleFunc("/api/v1/holdouts/", r.handleHoldouts)
+	r.mux.HandleFunc("/api/v1/targeting/segments", r.handleSegments)
+	r.mux.HandleFunc("/api/v1/projects/", r.handleDefaults)
+	return r, nil
+}
+
+// Run syncs the rulesets of the relay's environments and applies the changes
+// of the upstream flag stream until ctx is canceled
+func (r *Relay) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, evaluator := range r.evaluators {
+		wg.Add(1)
+		go func(evaluator *matrixflag.Evaluator) {
+			defer wg.Done()
+			evaluator.Run(ctx)
+		}(evaluator)
+	}
+	r.runStream(ctx)
+	wg.Wait()
+}
+
+// runStream applies and forwards the events of the upstream flag stream until ctx is canceled
+func (r *Relay) runStream(ctx context.Context) {
+	events, err := r.client.StreamFlags(ctx)
+	if err != nil {
+		r.reportError(fmt.Errorf("failed to open upstream flag stream: %w", err))
+		return
+	}
+	for event := range events {
+		evaluator, ok := r.evaluators[event.Flag.Environment]
+		if !ok {
+			continue
+		}
+		if err := evaluator.Apply(ctx, event); err != nil {
+			r.reportError(err)
+		}
+		r.publish(event)
+	}
+}
+
+// reportError passes an error to the OnError callback, if any
+func (r *Relay) reportError(err error) {
+	if r.opts.OnError != nil {
+		r.opts.OnError(err)
+	}
+}
+
+// subscribe registers a downstream stream for the events of env, or of all environments when env is empty
+func (r *Relay) subscribe(env string) chan matrixflag.FlagEvent {
+	ch := make(chan matrixflag.FlagEvent, subscriberBuffer)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscribers[ch] = env
+	return ch
+}
+
+// unsubscribe removes a downstream stream, if it is still registered
+func (r *Relay) unsubscribe(ch chan matrixflag.FlagEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.subscribers[ch]; ok {
+		delete(r.subscribers, ch)
+		close(ch)
+	}
+}
+
+// publish sends an event to the downstream streams of its environment, closing those that fell behind
+func (r *Relay) publish(event matrixflag.FlagEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for ch, env := range r.subscribers {
+		if env != "" && env != event.Flag.Environment {
+			continue
+		}
+		select {
+		case ch <- event:
+		default:
+			delete(r.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (r *Relay) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != "/healthz" && !r.authorized(req) {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "invalid API key")
+		return
+	}
+	r.mux.ServeHTTP(w, req)
+}
+
+// authorized reports whether a request carries one of the accepted API keys
+func (r *Relay) authorized(req *http.Request) bool {
+	if len(r.opts.Keys) == 0 {
+		return true
+	}
+	key, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	for _, accepted := range r.opts.Keys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(accepted)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Relay) handleHealth(w http.ResponseWriter, req *http.Request) {
+	status := http.StatusOK
+	environments := make(map[string]bool, len(r.evaluators))
+	for env, evaluator := range r.evaluators {
+		environments[env] = evaluator.Ready()
+		if !evaluator.Ready() {
+			status = http.StatusServiceUnavailable
+		}
+	}
+	writeJSON(w, status, map[string]any{"ready": status == http.StatusOK, "environments": environments})
+}
+
+// rulesets returns the synced rulesets of env, or of all environments when env
+// is empty, writing an error response when there are none
+func (r *Relay) rulesets(w http.ResponseWriter, req *http.Request) ([]*matrixflag.Ruleset, bool) {
+	if req.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "the relay only serves reads")
+		return nil, false
+	}
+	envs := r.opts.Environments
+	if env := req.URL.Query().Get("environment"); env != "" {
+		envs = []string{env}
+	}
+	rulesets := make([]*matrixflag.Ruleset, 0, len(envs))
+	for _, env := range envs {
+		evaluator, ok := r.evaluators[env]
+		if !ok {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("environment %s is not served by the relay", env))
+			return nil, false
+		}
+		ruleset := evaluator.Ruleset()
+		if ruleset == nil {
+			writeError(w, http.StatusServiceUnavailable, "NOT_READY", fmt.Sprintf("environment %s has not been synced yet", env))
+			return nil, false
+		}
+		rulesets = append(rulesets, ruleset)
+	}
+	return rulesets, true
+}
+
+func (r *Relay) handleFlags(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != "/api/v1/feature-flags/" {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "not served by the relay")
+		return
+	}
+	rulesets, ok := r.rulesets(w, req)
+	if !ok {
+		return
+	}
+	flags := []matrixflag.FeatureFlag{}
+	for _, ruleset := range rulesets {
+		flags = append(flags, ruleset.Flags...)
+	}
+	writeJSON(w, http.StatusOK, flags)
+}
+
+func (r *Relay) handleLayers(w http.ResponseWriter, req *http.Request) {
+	rulesets, ok := r.rulesets(w, req)
+	if !ok {
+		return
+	}
+	layers := []matrixflag.Layer{}
+	for _, ruleset := range rulesets {
+		layers = append(layers, ruleset.Layers...)
+	}
+	writeJSON(w, http.StatusOK, layers)
+}
+
+func (r *Relay) handleHoldouts(w http.ResponseWriter, req *http.Request) {
+	rulesets, ok := r.rulesets(w, req)
+	if !ok {
+		return
+	}
+	holdouts := []matrixflag.Holdout{}
+	for _, ruleset := range rulesets {
+		holdouts = append(holdouts, ruleset.Holdouts...)
+	}
+	writeJSON(w, http.StatusOK, holdouts)
+}
+
+// handleSegments serves the segments, which all environments share
+func (r *Relay) handleSegments(w http.ResponseWriter, req *http.Request) {
+	rulesets, ok := r.rulesets(w, req)
+	if !ok {
+		return
+	}
+	segments := rulesets[0].Segments
+	if segments == nil {
+		segments = []matrixflag.Segment{}
+	}
+	writeJSON(w, http.StatusOK, segments)
+}
+
+// handleDefaults serves /api/v1/projects/{id}/defaults and /api/v1/projects/{id}/environments/{env}/defaults
+func (r *Relay) handleDefaults(w http.ResponseWriter, req *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(req.URL.EscapedPath(), "/api/v1/projects/"), "/")
+	projectID, err := strconv.Atoi(parts[0])
+	var env string
+	switch {
+	case err == nil && len(parts) == 2 && parts[1] == "defaults":
+	case err == nil && len(parts) == 4 && parts[1] == "environments" && parts[3] == "defaults":
+		env, err = url.PathUnescape(parts[2])
+	default:
+		err = errors.New("not served by the relay")
+	}
+	if err != nil {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "not served by the relay")
+		return
+	}
+
+	if env != "" {
+		q := req.URL.Query()
+		q.Set("environment", env)
+		req.URL.RawQuery = q.Encode()
+	}
+	rulesets, ok := r.rulesets(w, req)
+	if !ok {
+		return
+	}
+	for _, ruleset := range rulesets {
+		defaults := ruleset.ProjectDefaults
+		if env != "" {
+			defaults = ruleset.EnvironmentDefaults
+		}
+		if d, ok := defaults[projectID]; ok {
+			writeJSON(w, http.StatusOK, d)
+			return
+		}
+	}
+	// Only the defaults of projects with inheriting flags are synced; the others inherit nothing
+	writeJSON(w, http.StatusOK, matrixflag.FlagDefaults{})
+}
+
+// evaluateRequest is the body of a remote evaluation, as sent by Client.Evaluate
+type evaluateRequest struct {
+	Flag        string                       `json:"flag"`
+	Environment string                       `json:"environment"`
+	Context     matrixflag.EvaluationContext `json:"context"`
+}
+
+func (r *Relay) handleEvaluate(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "evaluations must be posted")
+		return
+	}
+	var body evaluateRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "BAD_REQUEST", "invalid evaluation request: "+err.Error())
+		return
+	}
+	env := body.Environment
+	if env == "" && len(r.opts.Environments) == 1 {
+		env = r.opts.Environments[0]
+	}
+	evaluator, ok := r.evaluators[env]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("environment %q is not served by the relay", env))
+		return
+	}
+	writeJSON(w, http.StatusOK, evaluator.Evaluate(body.Flag, body.Context, nil))
+}
+
+// handleStream forwards the upstream flag events of an environment, or of all
+// environments, as Server-Sent Events. Events missed while disconnected are not
+// replayed; clients catch up with their next sync.
+func (r *Relay) handleStream(w http.ResponseWriter, req *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "STREAMING_UNSUPPORTED", "the server does not support streaming")
+		return
+	}
+	env := req.URL.Query().Get("environment")
+	if _, ok := r.evaluators[env]; env != "" && !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("environment %s is not served by the relay", env))
+		return
+	}
+	events := r.subscribe(env)
+	defer r.unsubscribe(events)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event.Flag)
+			if err != nil {
+				continue
+			}
+			if event.ID != "" {
+				fmt.Fprintf(w, "id: %s\n", event.ID)
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+		}
+		flusher.Flush()
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error response in the format of the API, see matrixflag.APIError
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, matrixflag.APIError{Code: code, Message: message})
+}
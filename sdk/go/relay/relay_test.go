@@ -0,0 +1,175 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// upstream is a fake API serving a fixed set of flags and a flag stream
+type upstream struct {
+	mu      sync.Mutex
+	flags   []matrixflag.FeatureFlag
+	streams []chan string
+}
+
+func (u *upstream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/api/v1/feature-flags/":
+		u.mu.Lock()
+		defer u.mu.Unlock()
+		var flags []matrixflag.FeatureFlag
+		for _, flag := range u.flags {
+			if flag.Environment == r.URL.Query().Get("environment") {
+				flags = append(flags, flag)
+			}
+		}
+		json.NewEncoder(w).Encode(flags)
+	case "/api/v1/feature-flags/stream":
+		stream := make(chan string, 16)
+		u.mu.Lock()
+		u.streams = append(u.streams, stream)
+		u.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case event := <-stream:
+				w.Write([]byte(event))
+				w.(http.Flusher).Flush()
+			}
+		}
+	default:
+		w.Write([]byte(`[]`))
+	}
+}
+
+func (u *upstream) connected() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return len(u.streams) > 0
+}
+
+func (u *upstream) publish(id string, eventType matrixflag.FlagChangeType, flag matrixflag.FeatureFlag) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	data, _ := json.Marshal(flag)
+	for _, stream := range u.streams {
+		stream <- fmt.Sprintf("id: %s\nevent: %s\ndata: %s\n\n", id, eventType, data)
+	}
+}
+
+// startRelay runs a relay for the production and staging flags of an upstream
+// and returns the URL it serves on
+func startRelay(t *testing.T, api *upstream, opts Options) string {
+	upstreamSrv := httptest.NewServer(api)
+	t.Cleanup(upstreamSrv.Close)
+	r, err := New(matrixflag.New("upstream-key", matrixflag.WithBaseURL(upstreamSrv.URL)), opts)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		r.Run(ctx)
+		close(done)
+	}()
+	srv := httptest.NewServer(r)
+	t.Cleanup(func() {
+		srv.CloseClientConnections()
+		srv.Close()
+		cancel()
+		<-done
+	})
+	require.Eventually(t, func() bool {
+		resp, err := http.Get(srv.URL + "/healthz")
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK && api.connected()
+	}, 5*time.Second, 10*time.Millisecond)
+	return srv.URL
+}
+
+func newUpstream() *upstream {
+	return &upstream{flags: []matrixflag.FeatureFlag{
+		{ID: 1, Name: "checkout", Environment: "production", IsActive: true},
+		{ID: 2, Name: "checkout", Environment: "staging"},
+	}}
+}
+
+func TestRelayServesClients(t *testing.T) {
+	url := startRelay(t, newUpstream(), Options{Environments: []string{"production", "staging"}, Keys: []string{"sdk-key"}})
+	ctx := context.Background()
+	user := matrixflag.NewContext("user-1")
+
+	production := matrixflag.New("sdk-key", matrixflag.WithBaseURL(url), matrixflag.WithEnvironment("production"))
+	assert.True(t, production.EvaluateBool(ctx, "checkout", user, false).Value)
+	staging := matrixflag.New("sdk-key", matrixflag.WithBaseURL(url), matrixflag.WithEnvironment("staging"))
+	assert.False(t, staging.EvaluateBool(ctx, "checkout", user, true).Value)
+	d := production.EvaluateBool(ctx, "search", user, true)
+	assert.Equal(t, matrixflag.ErrorFlagNotFound, d.ErrorCode)
+	assert.True(t, d.Value)
+
+	evaluator := matrixflag.NewEvaluator(production, matrixflag.EvaluatorOptions{Environment: "production"})
+	require.NoError(t, evaluator.Sync(ctx), "evaluators sync through the relay")
+	assert.True(t, evaluator.EvaluateBool("checkout", user, false).Value)
+
+	flags, err := production.ListFeatureFlags(ctx, matrixflag.FlagFilter{})
+	require.NoError(t, err)
+	assert.Len(t, flags, 2)
+	_, err = production.ListFeatureFlags(ctx, matrixflag.FlagFilter{Environment: "development"})
+	var apiErr matrixflag.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+}
+
+func TestRelayRejectsUnknownKeys(t *testing.T) {
+	url := startRelay(t, newUpstream(), Options{Environments: []string{"production"}, Keys: []string{"sdk-key"}})
+	client := matrixflag.New("other-key", matrixflag.WithBaseURL(url), matrixflag.WithRetries(0, 0, 0))
+	_, err := client.ListFeatureFlags(context.Background(), matrixflag.FlagFilter{})
+	var apiErr matrixflag.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+}
+
+func TestRelayForwardsStream(t *testing.T) {
+	api := newUpstream()
+	url := startRelay(t, api, Options{Environments: []string{"production", "staging"}})
+	client := matrixflag.New("sdk-key", matrixflag.WithBaseURL(url), matrixflag.WithEnvironment("production"))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := client.StreamFlags(ctx)
+	require.NoError(t, err)
+
+	api.publish("41", matrixflag.FlagUpdated, matrixflag.FeatureFlag{ID: 2, Name: "checkout", Environment: "staging", IsActive: true})
+	api.publish("42", matrixflag.FlagToggled, matrixflag.FeatureFlag{ID: 1, Name: "checkout", Environment: "production"})
+	select {
+	case event := <-events:
+		assert.Equal(t, "42", event.ID, "events of other environments are filtered out")
+		assert.Equal(t, matrixflag.FlagToggled, event.Type)
+		assert.False(t, event.Flag.IsActive)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event was forwarded")
+	}
+
+	assert.False(t, client.EvaluateBool(ctx, "checkout", matrixflag.NewContext("user-1"), true).Value, "the relay applies upstream changes")
+}
+
+func TestNewRequiresEnvironments(t *testing.T) {
+	_, err := New(matrixflag.New("key"), Options{})
+	assert.Error(t, err)
+	_, err = New(matrixflag.New("key"), Options{Environments: []string{""}})
+	assert.Error(t, err)
+}
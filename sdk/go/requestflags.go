@@ -0,0 +1,141 @@
+package matrixflag
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// ContextExtractor returns the evaluation context of an incoming HTTP request,
+// such as the signed-in user
+type ContextExtractor func(r *http.Request) EvaluationContext
+
+// RequestFlags holds the flag evaluations of one incoming request. Each flag is
+// evaluated at most once, so every handler of the request sees the same value
+// even when the flag changes while the request is served. Its methods may be
+// called on a nil *RequestFlags, returning zero values, and are safe for
+// concurrent use.
+type RequestFlags struct {
+	ctx     context.Context
+	flags   FlagEvaluator
+	evalCtx EvaluationContext
+
+	mu      sync.Mutex
+	details map[string]EvaluationDetail[any]
+}
+
+type requestFlagsKey struct{}
+
+// NewRequestFlags evaluates flagKeys for evalCtx, returning the request flags
+// that hold them. Other flags are evaluated on first use. ctx is the context of
+// the request, used for every evaluation. Framework adapters use it with
+// ContextWithFlags; net/http servers use HTTPMiddleware.
+func NewRequestFlags(ctx context.Context, flags FlagEvaluator, evalCtx EvaluationContext, flagKeys []string) *RequestFlags {
+	rf := &RequestFlags{ctx: ctx, flags: flags, evalCtx: evalCtx, details: make(map[string]EvaluationDetail[any], len(flagKeys))}
+	for _, key := range flagKeys {
+		rf.Detail(key)
+	}
+	return rf
+}
+
+// ContextWithFlags returns a context carrying the request flags, see FromContext
+func ContextWithFlags(ctx context.Context, rf *RequestFlags) context.Context {
+	return context.WithValue(ctx, requestFlagsKey{}, rf)
+}
+
+// FromContext returns the request flags of an incoming request, or nil when the
+// request was not served through HTTPMiddleware or a framework adapter
+func FromContext(ctx context.Context) *RequestFlags {
+	rf, _ := ctx.Value(requestFlagsKey{}).(*RequestFlags)
+	return rf
+}
+
+// HTTPMiddleware evaluates flagKeys once per request for the evaluation context
+// returned by extract, and serves the request with the flags in its context:
+//
+//	handler = matrixflag.HTTPMiddleware(client, userContext, "new-checkout")(handler)
+//
+//	func checkout(w http.ResponseWriter, r *http.Request) {
+//		if matrixflag.FromContext(r.Context()).Bool("new-checkout") {
+//			// ...
+//		}
+//	}
+//
+// Flags missing from flagKeys are evaluated on first use, once per request too.
+// It is not named Middleware, which is the client middleware wrapping API requests.
+func HTTPMiddleware(flags FlagEvaluator, extract ContextExtractor, flagKeys ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rf := NewRequestFlags(r.Context(), flags, extract(r), flagKeys)
+			next.ServeHTTP(w, r.WithContext(ContextWithFlags(r.Context(), rf)))
+		})
+	}
+}
+
+// Gate routes each request to newHandler while a boolean flag is on for it, and
+// to oldHandler otherwise, for canary routing of an endpoint:
+//
+//	mux.Handle("/checkout", matrixflag.Gate("new-checkout", newCheckout, legacyCheckout))
+//
+// The flag is evaluated with the request flags of HTTPMiddleware, which must
+// wrap the gate, so a percentage rollout splits traffic by the evaluation
+// context of each request and a context keeps being routed the same way.
+// Requests without request flags go to oldHandler.
+func Gate(flagKey string, newHandler, oldHandler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if FromContext(r.Context()).Bool(flagKey) {
+			newHandler.ServeHTTP(w, r)
+			return
+		}
+		oldHandler.ServeHTTP(w, r)
+	})
+}
+
+// Context returns the evaluation context of the request
+func (rf *RequestFlags) Context() EvaluationContext {
+	if rf == nil {
+		return EvaluationContext{}
+	}
+	return rf.evalCtx
+}
+
+// Detail returns the evaluation of a flag for the request, evaluating it on
+// first use. Failed evaluations have a nil value.
+func (rf *RequestFlags) Detail(flagKey string) EvaluationDetail[any] {
+	if rf == nil {
+		return EvaluationDetail[any]{Reason: ReasonError, ErrorCode: ErrorGeneral}
+	}
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	d, ok := rf.details[flagKey]
+	if !ok {
+		d = rf.flags.Evaluate(rf.ctx, flagKey, rf.evalCtx, nil)
+		rf.details[flagKey] = d
+	}
+	return d
+}
+
+// Bool returns the value of a boolean flag, false when it failed or is not a bool
+func (rf *RequestFlags) Bool(flagKey string) bool {
+	return typedDetail(rf.Detail(flagKey), false, asBool).Value
+}
+
+// String returns the value of a string flag, "" when it failed or is not a string
+func (rf *RequestFlags) String(flagKey string) string {
+	return typedDetail(rf.Detail(flagKey), "", asString).Value
+}
+
+// Int returns the value of an integer flag, 0 when it failed or is not an integer
+func (rf *RequestFlags) Int(flagKey string) int {
+	return typedDetail(rf.Detail(flagKey), 0, asInt).Value
+}
+
+// Float returns the value of a numeric flag, 0 when it failed or is not a number
+func (rf *RequestFlags) Float(flagKey string) float64 {
+	return typedDetail(rf.Detail(flagKey), 0, asFloat).Value
+}
+
+// JSON returns the value of a flag, nil when it failed
+func (rf *RequestFlags) JSON(flagKey string) any {
+	return rf.Detail(flagKey).Value
+}
@@ -0,0 +1,75 @@
+package matrixflag_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/matrixflag/sdk/matrixflagtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	td := matrixflagtest.NewTestDataSource()
+	td.Set("new-checkout", true)
+	td.Set("banner", "spring-sale")
+	td.Set("max-items", 20)
+	userContext := func(r *http.Request) matrixflag.EvaluationContext {
+		return matrixflag.NewContext(r.Header.Get("X-User"))
+	}
+
+	var seen []any
+	handler := matrixflag.HTTPMiddleware(td.Client(), userContext, "new-checkout")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flags := matrixflag.FromContext(r.Context())
+		assert.Equal(t, "user-1", flags.Context().Key)
+		td.Set("new-checkout", false)
+		seen = append(seen, flags.Bool("new-checkout"), flags.String("banner"), flags.Int("max-items"), flags.Float("max-items"), flags.Bool("banner"))
+		td.Set("banner", "summer-sale")
+		seen = append(seen, flags.String("banner"), flags.JSON("missing"))
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/checkout", nil)
+	req.Header.Set("X-User", "user-1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, []any{true, "spring-sale", 20, 20.0, false, "spring-sale", nil}, seen, "values stay the same for the whole request")
+	assert.Len(t, td.Evaluations(), 4, "each flag is evaluated once per request")
+	assert.Equal(t, "new-checkout", td.Evaluations()[0].Flag, "configured flags are evaluated before the handler")
+
+	var none *matrixflag.RequestFlags
+	assert.False(t, none.Bool("new-checkout"))
+	assert.Nil(t, matrixflag.FromContext(req.Context()), "requests not served through the middleware have no flags")
+}
+
+func TestGate(t *testing.T) {
+	td := matrixflagtest.NewTestDataSource()
+	td.SetFlag(matrixflag.FeatureFlag{Name: "new-checkout", IsActive: true, Rollout: &matrixflag.PercentageRollout{Percentage: 50}})
+	handler := func(name string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(name)) })
+	}
+	gate := matrixflag.Gate("new-checkout", handler("new"), handler("old"))
+	userContext := func(r *http.Request) matrixflag.EvaluationContext {
+		return matrixflag.NewContext(r.Header.Get("X-User"))
+	}
+	serve := func(h http.Handler, user string) string {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/checkout", nil)
+		req.Header.Set("X-User", user)
+		h.ServeHTTP(rec, req)
+		return rec.Body.String()
+	}
+
+	gated := matrixflag.HTTPMiddleware(td.Client(), userContext)(gate)
+	routed := map[string]int{}
+	for i := 0; i < 200; i++ {
+		user := fmt.Sprintf("user-%d", i)
+		got := serve(gated, user)
+		assert.Equal(t, got, serve(gated, user), "a user keeps being routed the same way")
+		routed[got]++
+	}
+	assert.InDelta(t, 100, routed["new"], 30, "the rollout splits the traffic")
+	assert.Equal(t, 200, routed["new"]+routed["old"])
+
+	assert.Equal(t, "old", serve(gate, "user-1"), "requests without request flags take the old handler")
+}
@@ -0,0 +1,30 @@
+package matrixflag
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context whose SDK calls send id as their X-Request-ID,
+// propagating a request ID of the caller (such as the ID of an incoming request)
+// to the API. Calls made without one are sent with a generated ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID set by WithRequestID
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// requestID returns the request ID to send for a call
+func requestID(ctx context.Context) string {
+	if id, ok := RequestIDFromContext(ctx); ok {
+		return id
+	}
+	return uuid.NewString()
+}
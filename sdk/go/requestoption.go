@@ -0,0 +1,89 @@
+package matrixflag
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKeyHeader carries the idempotency key of a write, see WithIdempotencyKey
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// RequestOption configures one call of a Client method, such as
+// WithRequestTimeout. Every method taking a context takes them last, and they
+// apply to every request the call sends.
+type RequestOption func(*requestOptions)
+
+// requestOptions holds the RequestOptions of a call
+type requestOptions struct {
+	timeout time.Duration
+	headers map[string]string
+}
+
+// WithRequestTimeout limits each attempt of the requests of a call to timeout,
+// overriding Config.Timeout and Config.Timeouts, so hot-path calls can have a
+// tighter budget than the client. Flag streams are not limited.
+func WithRequestTimeout(timeout time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithHeader sets a header on the requests of a call, such as a tenant or
+// routing header. The headers set by the SDK, such as Authorization and
+// X-Request-ID, take precedence.
+func WithHeader(key, value string) RequestOption {
+	return func(o *requestOptions) {
+		o.headers[http.CanonicalHeaderKey(key)] = value
+	}
+}
+
+// WithIdempotencyKey sends key as the Idempotency-Key of the requests of a
+// call, so the server applies a write once however many times it is received.
+// Writes carrying a key are retried like idempotent requests, on 500, 502 and
+// 504 responses too. CreateFeatureFlag and ToggleFeatureFlag generate a key
+// when none is set; setting one, such as the ID of the operation, also dedupes
+// the calls of the caller's own retries. It is meant for calls sending a single
+// write; the writes of calls sending several share the key.
+func WithIdempotencyKey(key string) RequestOption {
+	return WithHeader(IdempotencyKeyHeader, key)
+}
+
+// idempotencyHeaders returns the headers of a write that is always sent with
+// an idempotency key: a generated one, unless the call's options set it
+func idempotencyHeaders(ctx context.Context) map[string]string {
+	if _, ok := callOptions(ctx).headers[IdempotencyKeyHeader]; ok {
+		return nil
+	}
+	return map[string]string{IdempotencyKeyHeader: uuid.NewString()}
+}
+
+type requestOptionsKey struct{}
+
+// withRequestOptions returns a context carrying the options of a call, added
+// to those of ctx, so calls made by the call itself apply them as well
+func withRequestOptions(ctx context.Context, opts []RequestOption) context.Context {
+	if len(opts) == 0 {
+		return ctx
+	}
+	parent := callOptions(ctx)
+	o := &requestOptions{timeout: parent.timeout, headers: maps.Clone(parent.headers)}
+	if o.headers == nil {
+		o.headers = make(map[string]string)
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return context.WithValue(ctx, requestOptionsKey{}, o)
+}
+
+// callOptions returns the options of the call making a request
+func callOptions(ctx context.Context) *requestOptions {
+	if o, ok := ctx.Value(requestOptionsKey{}).(*requestOptions); ok {
+		return o
+	}
+	return &requestOptions{}
+}
@@ -0,0 +1,122 @@
+package matrixflag
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRequestTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(200 * time.Millisecond):
+		}
+		writeJSON(w, FeatureFlag{ID: 1, Name: "checkout"})
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil, WithRetries(0, 0, 0))
+
+	_, err := client.GetFeatureFlag(context.Background(), 1, WithRequestTimeout(10*time.Millisecond))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	_, err = client.GetFeatureFlag(context.Background(), 1)
+	assert.NoError(t, err, "the timeout only applies to the call")
+}
+
+func TestWithHeader(t *testing.T) {
+	var mu sync.Mutex
+	var headers []http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		headers = append(headers, r.Header.Clone())
+		mu.Unlock()
+		writeJSON(w, []FeatureFlag{{ID: 1, Name: "checkout", Environment: "production"}})
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil)
+
+	_, err := client.GetFeatureFlagByName(context.Background(), "checkout", "production",
+		WithHeader("X-Tenant", "acme"), WithHeader("Authorization", "Bearer other"))
+	require.NoError(t, err)
+	_, err = client.ListFeatureFlags(context.Background(), FlagFilter{})
+	require.NoError(t, err)
+
+	require.Len(t, headers, 2)
+	assert.Equal(t, "acme", headers[0].Get("X-Tenant"))
+	assert.Equal(t, "Bearer key", headers[0].Get("Authorization"), "the SDK's headers take precedence")
+	assert.Empty(t, headers[1].Get("X-Tenant"))
+}
+
+func TestWithIdempotencyKey(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		if len(keys)%2 == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		writeJSON(w, ChangeRequest{ID: 1})
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil, WithRetries(1, time.Millisecond, time.Millisecond))
+	ctx := context.Background()
+
+	change, err := client.ApproveChangeRequest(ctx, 1, "", WithIdempotencyKey("approve-1"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, change.ID)
+	assert.Equal(t, []string{"approve-1", "approve-1"}, keys, "writes with a key are retried with the same key")
+
+	keys = nil
+	_, err = client.ApproveChangeRequest(ctx, 1, "")
+	var apiErr APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	assert.Len(t, keys, 1, "writes without a key are not retried on a 502")
+}
+
+func TestCreateAndToggleIdempotencyKeys(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		attempt := len(keys)
+		mu.Unlock()
+		switch {
+		case attempt%2 == 0:
+			writeJSON(w, FeatureFlag{ID: 1, Name: "checkout"})
+		case r.URL.Path == "/api/v1/feature-flags/":
+			w.WriteHeader(http.StatusGatewayTimeout)
+		default:
+			// the first attempt of a toggle times out after the server applied it
+			<-r.Context().Done()
+		}
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil, WithRetries(1, time.Millisecond, time.Millisecond))
+	ctx := context.Background()
+	create := FeatureFlagCreate{Name: "checkout", Environment: "production"}
+
+	_, err := client.CreateFeatureFlag(ctx, create)
+	require.NoError(t, err)
+	_, err = client.ToggleFeatureFlag(ctx, 1, WithRequestTimeout(20*time.Millisecond))
+	require.NoError(t, err)
+	_, err = client.CreateFeatureFlag(ctx, create, WithHeader("idempotency-key", "create-1"))
+	require.NoError(t, err)
+
+	require.Len(t, keys, 6)
+	assert.NotEmpty(t, keys[0])
+	assert.Equal(t, keys[0], keys[1], "a retried create keeps its key")
+	assert.Equal(t, keys[2], keys[3], "a toggle retried after a timeout keeps its key")
+	assert.NotEqual(t, keys[0], keys[2], "every call has its own key")
+	assert.Equal(t, []string{"create-1", "create-1"}, keys[4:], "a key set by the caller is sent instead")
+}
@@ -0,0 +1,108 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+// Environment represents the state of an environment resource. Environments
+// are identified by key, which is therefore also their ID.
+type Environment struct {
+	ID          string `json:"id,omitempty"`
+	Key         string `json:"key"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	ProjectID   int    `json:"project_id,omitempty"`
+	// APIKey is computed by the server and not compared by Diff
+	APIKey string `json:"api_key,omitempty"`
+}
+
+// EnvironmentResource manages environments as resources
+type EnvironmentResource struct {
+	client *matrixflag.Client
+}
+
+var _ Resource[Environment] = (*EnvironmentResource)(nil)
+
+// NewEnvironmentResource creates a new environment resource manager
+func NewEnvironmentResource(client *matrixflag.Client) *EnvironmentResource {
+	return &EnvironmentResource{client: client}
+}
+
+// environmentFromAPI converts an API environment into resource state
+func environmentFromAPI(environment *matrixflag.Environment) *Environment {
+	return &Environment{
+		ID:          environment.Key,
+		Key:         environment.Key,
+		Name:        environment.Name,
+		Description: environment.Description,
+		ProjectID:   environment.ProjectID,
+		APIKey:      environment.APIKey,
+	}
+}
+
+// Create creates an environment
+func (r *EnvironmentResource) Create(ctx context.Context, planned Environment) (*Environment, error) {
+	created, err := r.client.CreateEnvironment(ctx, matrixflag.EnvironmentCreate{
+		Key:         planned.Key,
+		Name:        planned.Name,
+		Description: planned.Description,
+		ProjectID:   planned.ProjectID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return environmentFromAPI(created), nil
+}
+
+// Read returns the current state of an environment
+func (r *EnvironmentResource) Read(ctx context.Context, id string) (*Environment, error) {
+	environment, err := r.client.GetEnvironment(ctx, id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("environment %s: %w", id, ErrNotFound)
+		}
+		return nil, err
+	}
+	return environmentFromAPI(environment), nil
+}
+
+// Update converges an environment to the planned state
+func (r *EnvironmentResource) Update(ctx context.Context, id string, planned Environment) (*Environment, error) {
+	// Fields are sent even when empty, so clearing one converges
+	updated, err := r.client.UpdateEnvironment(ctx, id, matrixflag.EnvironmentUpdate{
+		Name:        planned.Name,
+		Description: planned.Description,
+		Fields:      matrixflag.FieldMask{matrixflag.FieldName, matrixflag.FieldDescription},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return environmentFromAPI(updated), nil
+}
+
+// Delete deletes an environment
+func (r *EnvironmentResource) Delete(ctx context.Context, id string) error {
+	if err := r.client.DeleteEnvironment(ctx, id); err != nil && !isNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// Import resolves an import ID, the environment key
+func (r *EnvironmentResource) Import(ctx context.Context, importID string) (*Environment, error) {
+	return r.Read(ctx, importID)
+}
+
+// Diff compares the prior and planned state of an environment. Changing the
+// key or project requires replacing it.
+func (r *EnvironmentResource) Diff(prior, planned Environment) Diff {
+	var d Diff
+	d.add("key", prior.Key, planned.Key, true)
+	d.add("name", prior.Name, planned.Name, false)
+	d.add("description", prior.Description, planned.Description, false)
+	d.add("project_id", prior.ProjectID, planned.ProjectID, true)
+	return d
+}
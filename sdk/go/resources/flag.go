@@ -0,0 +1,155 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+// Flag represents the state of a feature flag resource
+type Flag struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	IsActive    bool   `json:"is_active"`
+	Environment string `json:"environment"`
+	ProjectID   int    `json:"project_id,omitempty"`
+}
+
+// FlagResource manages feature flags as resources
+type FlagResource struct {
+	client *matrixflag.Client
+}
+
+var _ Resource[Flag] = (*FlagResource)(nil)
+
+// NewFlagResource creates a new feature flag resource manager
+func NewFlagResource(client *matrixflag.Client) *FlagResource {
+	return &FlagResource{client: client}
+}
+
+// flagFromAPI converts an API feature flag into resource state
+func flagFromAPI(flag *matrixflag.FeatureFlag) *Flag {
+	return &Flag{
+		ID:          strconv.Itoa(flag.ID),
+		Name:        flag.Name,
+		Description: flag.Description,
+		IsActive:    flag.IsActive,
+		Environment: flag.Environment,
+		ProjectID:   flag.ProjectID,
+	}
+}
+
+// parseID converts a resource ID into a server ID
+func parseID(id string) (int, error) {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, fmt.Errorf("invalid resource ID %q: %w", id, err)
+	}
+	return n, nil
+}
+
+// Create creates a feature flag
+func (r *FlagResource) Create(ctx context.Context, planned Flag) (*Flag, error) {
+	created, err := r.client.CreateFeatureFlag(ctx, matrixflag.FeatureFlagCreate{
+		Name:        planned.Name,
+		Description: planned.Description,
+		IsActive:    planned.IsActive,
+		Environment: planned.Environment,
+		ProjectID:   planned.ProjectID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return flagFromAPI(created), nil
+}
+
+// Read returns the current state of a feature flag
+func (r *FlagResource) Read(ctx context.Context, id string) (*Flag, error) {
+	n, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	flag, err := r.client.GetFeatureFlag(ctx, n)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("feature flag %s: %w", id, ErrNotFound)
+		}
+		return nil, err
+	}
+	return flagFromAPI(flag), nil
+}
+
+// Update converges a feature flag to the planned state
+func (r *FlagResource) Update(ctx context.Context, id string, planned Flag) (*Flag, error) {
+	n, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	// Fields are sent even when empty, so clearing one converges
+	updated, err := r.client.UpdateFeatureFlag(ctx, n, matrixflag.FeatureFlagUpdate{
+		Name:        planned.Name,
+		Description: planned.Description,
+		IsActive:    planned.IsActive,
+		Environment: planned.Environment,
+		ProjectID:   planned.ProjectID,
+		Fields: matrixflag.FieldMask{
+			matrixflag.FieldDescription,
+			matrixflag.FieldIsActive,
+			matrixflag.FieldProjectID,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return flagFromAPI(updated), nil
+}
+
+// Delete deletes a feature flag
+func (r *FlagResource) Delete(ctx context.Context, id string) error {
+	n, err := parseID(id)
+	if err != nil {
+		return err
+	}
+	if _, err := r.client.DeleteFeatureFlag(ctx, n); err != nil && !isNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// Import resolves an import ID of the form "<environment>/<name>" or a numeric server ID
+func (r *FlagResource) Import(ctx context.Context, importID string) (*Flag, error) {
+	environment, name, ok := strings.Cut(importID, "/")
+	if !ok {
+		return r.Read(ctx, importID)
+	}
+	return r.ImportByName(ctx, environment, name)
+}
+
+// ImportByName looks up a feature flag by environment and name
+func (r *FlagResource) ImportByName(ctx context.Context, environment, name string) (*Flag, error) {
+	flag, err := r.client.GetFeatureFlagByName(ctx, name, environment)
+	if errors.Is(err, matrixflag.ErrFlagNotFound) {
+		return nil, fmt.Errorf("feature flag %s/%s: %w", environment, name, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return flagFromAPI(flag), nil
+}
+
+// Diff compares the prior and planned state of a feature flag.
+// Moving a flag to another environment or project requires replacing it.
+func (r *FlagResource) Diff(prior, planned Flag) Diff {
+	var d Diff
+	d.add("name", prior.Name, planned.Name, false)
+	d.add("description", prior.Description, planned.Description, false)
+	d.add("is_active", prior.IsActive, planned.IsActive, false)
+	d.add("environment", prior.Environment, planned.Environment, true)
+	d.add("project_id", prior.ProjectID, planned.ProjectID, true)
+	return d
+}
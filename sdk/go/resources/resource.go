@@ -0,0 +1,102 @@
+// Package resources exposes Matrix Flag objects as CRUD resources with stable
+// string IDs, import-by-name and plan-friendly diffs, so infrastructure-as-code
+// tooling such as a Terraform provider can be built directly on top of it.
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+// ErrNotFound is returned by Read and Import when the remote object no longer exists
+var ErrNotFound = errors.New("resource not found")
+
+// Resource is implemented by every resource type in this package
+type Resource[T any] interface {
+	// Create creates the object and returns its state, including the assigned ID
+	Create(ctx context.Context, planned T) (*T, error)
+	// Read returns the current state of the object, or ErrNotFound
+	Read(ctx context.Context, id string) (*T, error)
+	// Update converges the object to the planned state
+	Update(ctx context.Context, id string, planned T) (*T, error)
+	// Delete deletes the object; deleting a missing object is not an error
+	Delete(ctx context.Context, id string) error
+	// Import resolves a human-friendly import ID (such as a name) to the object state
+	Import(ctx context.Context, importID string) (*T, error)
+	// Diff compares the prior and planned state of the object
+	Diff(prior, planned T) Diff
+}
+
+// FieldChange describes a change to a single attribute
+type FieldChange struct {
+	Field           string `json:"field"`
+	Old             any    `json:"old"`
+	New             any    `json:"new"`
+	RequiresReplace bool   `json:"requires_replace"`
+}
+
+// Diff describes the changes between a prior and a planned state
+type Diff struct {
+	Changes []FieldChange `json:"changes,omitempty"`
+}
+
+// Empty reports whether the diff contains no changes
+func (d Diff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// RequiresReplace reports whether any change forces the object to be recreated
+func (d Diff) RequiresReplace() bool {
+	for _, change := range d.Changes {
+		if change.RequiresReplace {
+			return true
+		}
+	}
+	return false
+}
+
+// add records a change of field when old and new differ
+func (d *Diff) add(field string, old, new any, requiresReplace bool) {
+	if reflect.DeepEqual(old, new) {
+		return
+	}
+	d.Changes = append(d.Changes, FieldChange{
+		Field:           field,
+		Old:             old,
+		New:             new,
+		RequiresReplace: requiresReplace,
+	})
+}
+
+// addJSON records a change of field when old and new encode to different JSON,
+// so values read from the server compare equal to the planned Go values. Empty
+// lists equal nil ones.
+func (d *Diff) addJSON(field string, old, new any) {
+	if encodeJSON(old) == encodeJSON(new) {
+		return
+	}
+	d.Changes = append(d.Changes, FieldChange{Field: field, Old: old, New: new})
+}
+
+// encodeJSON encodes v for comparison, treating empty lists and maps as null
+func encodeJSON(v any) string {
+	rv := reflect.ValueOf(v)
+	if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Map) && rv.Len() == 0 {
+		return "null"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// isNotFound reports whether err is an API not found error
+func isNotFound(err error) bool {
+	return errors.Is(err, matrixflag.ErrNotFound)
+}
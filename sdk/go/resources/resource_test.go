@@ -0,0 +1,212 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPI stores objects as JSON by path. Updates only change the fields
+// present in the request body, as the real server does.
+type fakeAPI struct {
+	mu      sync.Mutex
+	nextID  int
+	objects map[string]map[string]json.RawMessage
+}
+
+func newTestClient(t *testing.T, objects map[string]any) *matrixflag.Client {
+	api := &fakeAPI{objects: make(map[string]map[string]json.RawMessage)}
+	for path, obj := range objects {
+		data, _ := json.Marshal(obj)
+		var fields map[string]json.RawMessage
+		json.Unmarshal(data, &fields)
+		api.objects[path] = fields
+	}
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	return matrixflag.NewClient(srv.URL, "key", nil)
+}
+
+func (a *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var body map[string]json.RawMessage
+	json.NewDecoder(r.Body).Decode(&body)
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	if r.Method == http.MethodPost {
+		// Objects are stored under their key or name, or a new numeric ID if they have neither
+		var id string
+		if json.Unmarshal(body["key"], &id) != nil && json.Unmarshal(body["name"], &id) != nil {
+			a.nextID++
+			id = strconv.Itoa(a.nextID)
+			body["id"] = json.RawMessage(id)
+			body["is_active"] = json.RawMessage("true")
+		}
+		a.objects[path+"/"+id] = body
+		json.NewEncoder(w).Encode(body)
+		return
+	}
+	if r.Method == http.MethodGet && path == "/api/v1/webhooks" {
+		list := []map[string]json.RawMessage{}
+		for objPath, obj := range a.objects {
+			if strings.HasPrefix(objPath, path+"/") {
+				list = append(list, obj)
+			}
+		}
+		json.NewEncoder(w).Encode(list)
+		return
+	}
+	obj, ok := a.objects[path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"not found","code":"NOT_FOUND"}`))
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		for name, value := range body {
+			obj[name] = value
+		}
+	case http.MethodDelete:
+		delete(a.objects, path)
+		w.Write([]byte(`{}`))
+		return
+	}
+	json.NewEncoder(w).Encode(obj)
+}
+
+func TestFlagUpdateClearsFields(t *testing.T) {
+	client := newTestClient(t, map[string]any{
+		"/api/v1/feature-flags/7": matrixflag.FeatureFlag{ID: 7, Name: "f", Environment: "production", IsActive: true, Description: "old", ProjectID: 3},
+	})
+	flags := NewFlagResource(client)
+	ctx := context.Background()
+
+	prior, err := flags.Read(ctx, "7")
+	require.NoError(t, err)
+	planned := Flag{ID: "7", Name: "f", Environment: "production"}
+	assert.Len(t, flags.Diff(*prior, planned).Changes, 3)
+
+	_, err = flags.Update(ctx, "7", planned)
+	require.NoError(t, err)
+	state, err := flags.Read(ctx, "7")
+	require.NoError(t, err)
+	assert.True(t, flags.Diff(*state, planned).Empty(), "the update converges")
+}
+
+func TestSegmentResource(t *testing.T) {
+	client := newTestClient(t, nil)
+	segments := NewSegmentResource(client)
+	ctx := context.Background()
+
+	planned := Segment{
+		Name:     "beta",
+		Included: []string{"user-1"},
+		Rules: []matrixflag.SegmentRule{{Conditions: []matrixflag.TargetingCondition{
+			{Attribute: "plan", Operator: matrixflag.OpIn, Value: []string{"enterprise"}},
+		}}},
+	}
+	created, err := segments.Create(ctx, planned)
+	require.NoError(t, err)
+	assert.Equal(t, "beta", created.ID)
+	planned.ID = created.ID
+
+	state, err := segments.Import(ctx, "beta")
+	require.NoError(t, err)
+	assert.True(t, segments.Diff(*state, planned).Empty(), "rule values read back compare equal")
+
+	planned.Included = nil
+	diff := segments.Diff(*state, planned)
+	require.Len(t, diff.Changes, 1)
+	assert.Equal(t, "included", diff.Changes[0].Field)
+	_, err = segments.Update(ctx, state.ID, planned)
+	require.NoError(t, err)
+	state, err = segments.Read(ctx, "beta")
+	require.NoError(t, err)
+	assert.True(t, segments.Diff(*state, planned).Empty())
+
+	assert.True(t, segments.Diff(*state, Segment{Name: "gamma"}).RequiresReplace())
+
+	require.NoError(t, segments.Delete(ctx, "beta"))
+	require.NoError(t, segments.Delete(ctx, "beta"), "deleting a missing segment is not an error")
+	_, err = segments.Read(ctx, "beta")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestEnvironmentResource(t *testing.T) {
+	client := newTestClient(t, nil)
+	environments := NewEnvironmentResource(client)
+	ctx := context.Background()
+
+	planned := Environment{Key: "qa", Name: "QA", Description: "manual testing"}
+	created, err := environments.Create(ctx, planned)
+	require.NoError(t, err)
+	assert.Equal(t, "qa", created.ID)
+	planned.ID = created.ID
+
+	state, err := environments.Import(ctx, "qa")
+	require.NoError(t, err)
+	assert.True(t, environments.Diff(*state, planned).Empty())
+
+	planned.Description = ""
+	_, err = environments.Update(ctx, state.ID, planned)
+	require.NoError(t, err)
+	state, err = environments.Read(ctx, "qa")
+	require.NoError(t, err)
+	assert.True(t, environments.Diff(*state, planned).Empty(), "the update clears the description")
+
+	assert.True(t, environments.Diff(*state, Environment{Key: "uat", Name: "QA"}).RequiresReplace())
+
+	require.NoError(t, environments.Delete(ctx, "qa"))
+	require.NoError(t, environments.Delete(ctx, "qa"), "deleting a missing environment is not an error")
+	_, err = environments.Read(ctx, "qa")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestWebhookResource(t *testing.T) {
+	client := newTestClient(t, nil)
+	webhooks := NewWebhookResource(client)
+	ctx := context.Background()
+
+	planned := Webhook{URL: "https://example.com/hook", Events: []matrixflag.WebhookEventType{matrixflag.WebhookFlagToggled}}
+	created, err := webhooks.Create(ctx, planned)
+	require.NoError(t, err)
+	assert.Equal(t, "1", created.ID)
+	assert.False(t, created.IsActive, "an inactive webhook is paused after creation")
+	planned.ID = created.ID
+
+	state, err := webhooks.Import(ctx, "https://example.com/hook")
+	require.NoError(t, err)
+	assert.True(t, webhooks.Diff(*state, planned).Empty())
+
+	planned.Events = nil
+	planned.IsActive = true
+	assert.Len(t, webhooks.Diff(*state, planned).Changes, 2)
+	_, err = webhooks.Update(ctx, state.ID, planned)
+	require.NoError(t, err)
+	state, err = webhooks.Read(ctx, "1")
+	require.NoError(t, err)
+	assert.True(t, webhooks.Diff(*state, planned).Empty(), "the update clears the events")
+
+	signed := planned
+	signed.Secret = "s3cret"
+	diff := webhooks.Diff(*state, signed)
+	require.Len(t, diff.Changes, 1)
+	assert.Equal(t, "signed", diff.Changes[0].Field)
+	assert.True(t, webhooks.Diff(Webhook{URL: planned.URL, IsActive: true, Signed: true}, signed).Empty(), "a secret read back as signed matches")
+
+	require.NoError(t, webhooks.Delete(ctx, "1"))
+	require.NoError(t, webhooks.Delete(ctx, "1"))
+	_, err = webhooks.Import(ctx, "https://example.com/hook")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
@@ -0,0 +1,122 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+// Segment represents the state of a segment resource. Segments are identified
+// by name, which is therefore also their ID.
+type Segment struct {
+	ID          string                   `json:"id,omitempty"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description,omitempty"`
+	Included    []string                 `json:"included,omitempty"`
+	Excluded    []string                 `json:"excluded,omitempty"`
+	Rules       []matrixflag.SegmentRule `json:"rules,omitempty"`
+}
+
+// SegmentResource manages segments as resources
+type SegmentResource struct {
+	client *matrixflag.Client
+}
+
+var _ Resource[Segment] = (*SegmentResource)(nil)
+
+// NewSegmentResource creates a new segment resource manager
+func NewSegmentResource(client *matrixflag.Client) *SegmentResource {
+	return &SegmentResource{client: client}
+}
+
+// segmentFromAPI converts an API segment into resource state
+func segmentFromAPI(segment *matrixflag.Segment) *Segment {
+	return &Segment{
+		ID:          segment.Name,
+		Name:        segment.Name,
+		Description: segment.Description,
+		Included:    segment.Included,
+		Excluded:    segment.Excluded,
+		Rules:       segment.Rules,
+	}
+}
+
+// Create creates a segment
+func (r *SegmentResource) Create(ctx context.Context, planned Segment) (*Segment, error) {
+	if err := matrixflag.ValidateSegmentRules(planned.Rules); err != nil {
+		return nil, err
+	}
+	created, err := r.client.CreateSegment(ctx, matrixflag.SegmentCreate{
+		Name:        planned.Name,
+		Description: planned.Description,
+		Included:    planned.Included,
+		Excluded:    planned.Excluded,
+		Rules:       planned.Rules,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return segmentFromAPI(created), nil
+}
+
+// Read returns the current state of a segment
+func (r *SegmentResource) Read(ctx context.Context, id string) (*Segment, error) {
+	segment, err := r.client.GetSegment(ctx, id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("segment %s: %w", id, ErrNotFound)
+		}
+		return nil, err
+	}
+	return segmentFromAPI(segment), nil
+}
+
+// Update converges a segment to the planned state
+func (r *SegmentResource) Update(ctx context.Context, id string, planned Segment) (*Segment, error) {
+	if err := matrixflag.ValidateSegmentRules(planned.Rules); err != nil {
+		return nil, err
+	}
+	// Fields are sent even when empty, so clearing one converges
+	updated, err := r.client.UpdateSegment(ctx, id, matrixflag.SegmentUpdate{
+		Description: planned.Description,
+		Included:    planned.Included,
+		Excluded:    planned.Excluded,
+		Rules:       planned.Rules,
+		Fields: matrixflag.FieldMask{
+			matrixflag.FieldDescription,
+			matrixflag.FieldIncluded,
+			matrixflag.FieldExcluded,
+			matrixflag.FieldRules,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return segmentFromAPI(updated), nil
+}
+
+// Delete deletes a segment
+func (r *SegmentResource) Delete(ctx context.Context, id string) error {
+	if err := r.client.DeleteSegment(ctx, id); err != nil && !isNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// Import resolves an import ID, the segment name
+func (r *SegmentResource) Import(ctx context.Context, importID string) (*Segment, error) {
+	return r.Read(ctx, importID)
+}
+
+// Diff compares the prior and planned state of a segment.
+// Renaming a segment requires replacing it.
+func (r *SegmentResource) Diff(prior, planned Segment) Diff {
+	var d Diff
+	d.add("name", prior.Name, planned.Name, true)
+	d.add("description", prior.Description, planned.Description, false)
+	d.addJSON("included", prior.Included, planned.Included)
+	d.addJSON("excluded", prior.Excluded, planned.Excluded)
+	d.addJSON("rules", prior.Rules, planned.Rules)
+	return d
+}
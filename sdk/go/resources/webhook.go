@@ -0,0 +1,150 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+// Webhook represents the state of a webhook resource
+type Webhook struct {
+	ID       string                        `json:"id,omitempty"`
+	URL      string                        `json:"url"`
+	Events   []matrixflag.WebhookEventType `json:"events,omitempty"`
+	IsActive bool                          `json:"is_active"`
+	// Secret is write-only: the server never returns it, so Read leaves it
+	// empty and reports whether one is set in Signed
+	Secret string `json:"secret,omitempty"`
+	Signed bool   `json:"signed"`
+}
+
+// WebhookResource manages webhooks as resources
+type WebhookResource struct {
+	client *matrixflag.Client
+}
+
+var _ Resource[Webhook] = (*WebhookResource)(nil)
+
+// NewWebhookResource creates a new webhook resource manager
+func NewWebhookResource(client *matrixflag.Client) *WebhookResource {
+	return &WebhookResource{client: client}
+}
+
+// webhookFromAPI converts an API webhook into resource state
+func webhookFromAPI(webhook *matrixflag.Webhook) *Webhook {
+	return &Webhook{
+		ID:       strconv.Itoa(webhook.ID),
+		URL:      webhook.URL,
+		Events:   webhook.Events,
+		IsActive: webhook.IsActive,
+		Signed:   webhook.Signed,
+	}
+}
+
+// Create creates a webhook. Webhooks are created active, so an inactive one
+// is paused right after.
+func (r *WebhookResource) Create(ctx context.Context, planned Webhook) (*Webhook, error) {
+	created, err := r.client.CreateWebhook(ctx, matrixflag.WebhookCreate{
+		URL:    planned.URL,
+		Events: planned.Events,
+		Secret: planned.Secret,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !planned.IsActive {
+		return r.Update(ctx, strconv.Itoa(created.ID), planned)
+	}
+	return webhookFromAPI(created), nil
+}
+
+// Read returns the current state of a webhook
+func (r *WebhookResource) Read(ctx context.Context, id string) (*Webhook, error) {
+	n, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := r.client.GetWebhook(ctx, n)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, fmt.Errorf("webhook %s: %w", id, ErrNotFound)
+		}
+		return nil, err
+	}
+	return webhookFromAPI(webhook), nil
+}
+
+// Update converges a webhook to the planned state
+func (r *WebhookResource) Update(ctx context.Context, id string, planned Webhook) (*Webhook, error) {
+	n, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	// Fields are sent even when empty, so clearing the events or secret converges
+	updated, err := r.client.UpdateWebhook(ctx, n, matrixflag.WebhookUpdate{
+		URL:      planned.URL,
+		Events:   planned.Events,
+		IsActive: planned.IsActive,
+		Secret:   planned.Secret,
+		Fields: matrixflag.FieldMask{
+			matrixflag.FieldEvents,
+			matrixflag.FieldIsActive,
+			matrixflag.FieldSecret,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return webhookFromAPI(updated), nil
+}
+
+// Delete deletes a webhook
+func (r *WebhookResource) Delete(ctx context.Context, id string) error {
+	n, err := parseID(id)
+	if err != nil {
+		return err
+	}
+	if err := r.client.DeleteWebhook(ctx, n); err != nil && !isNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// Import resolves an import ID, the URL of the webhook or its numeric server ID
+func (r *WebhookResource) Import(ctx context.Context, importID string) (*Webhook, error) {
+	if _, err := strconv.Atoi(importID); err == nil {
+		return r.Read(ctx, importID)
+	}
+	webhooks, err := r.client.ListWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var matches []matrixflag.Webhook
+	for _, webhook := range webhooks {
+		if webhook.URL == importID {
+			matches = append(matches, webhook)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("webhook %s: %w", importID, ErrNotFound)
+	case 1:
+		return webhookFromAPI(&matches[0]), nil
+	default:
+		return nil, fmt.Errorf("webhook %s is ambiguous: %d webhooks deliver to it, import one by ID", importID, len(matches))
+	}
+}
+
+// Diff compares the prior and planned state of a webhook. As the secret is
+// never read back, only adding or removing it shows up, as a change of signed;
+// rotating a secret needs an explicit Update.
+func (r *WebhookResource) Diff(prior, planned Webhook) Diff {
+	var d Diff
+	d.add("url", prior.URL, planned.URL, false)
+	d.addJSON("events", prior.Events, planned.Events)
+	d.add("is_active", prior.IsActive, planned.IsActive, false)
+	d.add("signed", prior.Signed || prior.Secret != "", planned.Signed || planned.Secret != "", false)
+	return d
+}
@@ -0,0 +1,85 @@
+package matrixflag
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// backoff returns the delay before retry attempt+1: a random duration up to
+// RetryDelay doubled for every attempt and capped at MaxRetryDelay (full jitter),
+// so clients failing together do not retry in lockstep
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.config.RetryDelay
+	for i := 0; i < attempt && delay < c.config.MaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > c.config.MaxRetryDelay {
+		delay = c.config.MaxRetryDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
+
+// retryDelay reports whether a response should be retried, and the delay the
+// server asked for with Retry-After, or -1 when it set none. 429 and 503
+// responses are retried for every method, as the server did not process the
+// request; 500, 502 and 504 only for idempotent methods and writes carrying an
+// idempotency key. A Retry-After beyond MaxRetryDelay ends the retries, so
+// calls are not blocked for minutes.
+func (c *Client) retryDelay(req *http.Request, resp *http.Response, attempt int) (time.Duration, bool) {
+	if attempt >= c.config.MaxRetries {
+		return 0, false
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		if !idempotent(req.Method) && req.Header.Get(IdempotencyKeyHeader) == "" {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	wait, ok := retryAfter(resp.Header.Get("Retry-After"), c.clock.Now())
+	if !ok {
+		return -1, true
+	}
+	if wait > c.config.MaxRetryDelay {
+		return 0, false
+	}
+	return wait, true
+}
+
+// idempotent reports whether requests of method can be repeated without side effects
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses a Retry-After header, given in seconds or as an HTTP date
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
@@ -0,0 +1,107 @@
+package matrixflag
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryStatuses(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		status   int
+		header   string
+		attempts int
+	}{
+		{"too many requests", http.MethodPost, http.StatusTooManyRequests, "", 3},
+		{"unavailable", http.MethodPost, http.StatusServiceUnavailable, "", 3},
+		{"server error on a read", http.MethodGet, http.StatusInternalServerError, "", 3},
+		{"bad gateway on a delete", http.MethodDelete, http.StatusBadGateway, "", 3},
+		{"server error on a create", http.MethodPost, http.StatusInternalServerError, "", 1},
+		{"gateway timeout on a create", http.MethodPost, http.StatusGatewayTimeout, "", 1},
+		{"client error", http.MethodGet, http.StatusBadRequest, "", 1},
+		{"not found", http.MethodGet, http.StatusNotFound, "", 1},
+		{"retry after within bounds", http.MethodGet, http.StatusTooManyRequests, "1", 3},
+		{"retry after beyond the maximum delay", http.MethodGet, http.StatusTooManyRequests, "120", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts.Add(1)
+				if tt.header != "" {
+					w.Header().Set("Retry-After", tt.header)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"message":"failed","code":"FAILED"}`))
+			}))
+			defer srv.Close()
+
+			client := New("key", WithBaseURL(srv.URL), WithRetries(2, time.Millisecond, 10*time.Second), WithClock(fixedClock{time.Now()}))
+			_, err := client.doRequest(context.Background(), request{method: tt.method, path: "/api/v1/feature-flags/"})
+			var apiErr APIError
+			require.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, tt.status, apiErr.StatusCode)
+			assert.Equal(t, tt.attempts, int(attempts.Load()))
+		})
+	}
+}
+
+func TestRetryRecovers(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeJSON(w, FeatureFlag{ID: 1, Name: "checkout"})
+	}))
+	defer srv.Close()
+
+	var meta ResponseMetadata
+	client := New("key", WithBaseURL(srv.URL), WithRetries(3, time.Millisecond, time.Millisecond))
+	flag, err := client.GetFeatureFlag(WithResponseMetadata(context.Background(), &meta), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "checkout", flag.Name)
+	assert.Equal(t, 2, meta.Attempts)
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{"-1", 0, false},
+		{"Wed, 01 May 2024 12:00:30 GMT", 30 * time.Second, true},
+		{"Wed, 01 May 2024 11:59:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := retryAfter(tt.value, now)
+		assert.Equal(t, tt.want, got, tt.value)
+		assert.Equal(t, tt.ok, ok, tt.value)
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	client := New("key", WithRetries(10, 100*time.Millisecond, time.Second))
+	for attempt, limit := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		for i := 0; i < 50; i++ {
+			d := client.backoff(attempt)
+			assert.GreaterOrEqual(t, d, time.Duration(0))
+			assert.LessOrEqual(t, d, limit)
+		}
+	}
+	assert.LessOrEqual(t, client.backoff(200), time.Second, "large attempts do not overflow")
+}
@@ -0,0 +1,218 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// RolloutStatus represents the state of a progressive rollout
+type RolloutStatus string
+
+// Rollout statuses
+const (
+	RolloutRunning    RolloutStatus = "running"
+	RolloutPaused     RolloutStatus = "paused"
+	RolloutCompleted  RolloutStatus = "completed"
+	RolloutCanceled   RolloutStatus = "canceled"
+	RolloutRolledBack RolloutStatus = "rolled_back"
+)
+
+// RolloutStep represents a single stage of a progressive rollout
+type RolloutStep struct {
+	// Percentage of traffic served the flag during this step, between 0 and 100
+	Percentage float64 `json:"percentage"`
+	// DwellSeconds is how long the rollout stays at this step before advancing
+	DwellSeconds int `json:"dwell_seconds"`
+	// PauseAfter pauses the rollout at the end of this step until it is resumed
+	PauseAfter bool `json:"pause_after,omitempty"`
+}
+
+// PauseConditionType represents the kind of a rollout pause condition
+type PauseConditionType string
+
+// Pause condition types
+const (
+	// PauseOnFlagChange pauses the rollout when the flag is edited manually
+	PauseOnFlagChange PauseConditionType = "flag_change"
+	// PauseOutsideWindow only advances the rollout inside a daily time window
+	PauseOutsideWindow PauseConditionType = "time_window"
+)
+
+// PauseCondition represents a condition under which a rollout stops advancing
+type PauseCondition struct {
+	Type PauseConditionType `json:"type"`
+	// WindowStart and WindowEnd bound the daily window as "HH:MM" for PauseOutsideWindow
+	WindowStart string `json:"window_start,omitempty"`
+	WindowEnd   string `json:"window_end,omitempty"`
+	// Timezone is the IANA time zone of the window, UTC by default
+	Timezone string `json:"timezone,omitempty"`
+	// Weekdays restricts the window to the given days; every day when empty
+	Weekdays []time.Weekday `json:"weekdays,omitempty"`
+}
+
+// validate checks the type of the condition and, for PauseOutsideWindow, its
+// window: "HH:MM" bounds, a known time zone and weekdays from Sunday to Saturday
+func (p PauseCondition) validate() error {
+	switch p.Type {
+	case PauseOnFlagChange:
+		return nil
+	case PauseOutsideWindow:
+	default:
+		return fmt.Errorf("unknown type %q", p.Type)
+	}
+	if p.WindowStart == "" || p.WindowEnd == "" {
+		return fmt.Errorf("window_start and window_end are required")
+	}
+	for _, bound := range []string{p.WindowStart, p.WindowEnd} {
+		if _, err := time.Parse("15:04", bound); err != nil {
+			return fmt.Errorf("invalid window time %q: must be HH:MM", bound)
+		}
+	}
+	if p.Timezone != "" {
+		if _, err := time.LoadLocation(p.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone %q: %w", p.Timezone, err)
+		}
+	}
+	for _, day := range p.Weekdays {
+		if day < time.Sunday || day > time.Saturday {
+			return fmt.Errorf("invalid weekday %d: must be 0 (Sunday) to 6 (Saturday)", day)
+		}
+	}
+	return nil
+}
+
+// Rollout represents a progressive rollout of a feature flag
+type Rollout struct {
+	FlagID            int              `json:"flag_id"`
+	Steps             []RolloutStep    `json:"steps"`
+	PauseConditions   []PauseCondition `json:"pause_conditions,omitempty"`
+	Guardrails        []Guardrail      `json:"guardrails,omitempty"`
+	Status            RolloutStatus    `json:"status"`
+	CurrentStep       int              `json:"current_step"`
+	CurrentPercentage float64          `json:"current_percentage"`
+	StepStartedAt     time.Time        `json:"step_started_at"`
+	PausedReason      string           `json:"paused_reason,omitempty"`
+	CreatedAt         time.Time        `json:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at"`
+}
+
+// RolloutCreate represents the data needed to start a progressive rollout
+type RolloutCreate struct {
+	Steps           []RolloutStep    `json:"steps"`
+	PauseConditions []PauseCondition `json:"pause_conditions,omitempty"`
+	Guardrails      []Guardrail      `json:"guardrails,omitempty"`
+}
+
+// RolloutSteps builds evenly paced rollout steps with the same dwell time, e.g. RolloutSteps(time.Hour, 10, 25, 50, 100)
+func RolloutSteps(dwell time.Duration, percentages ...float64) []RolloutStep {
+	steps := make([]RolloutStep, len(percentages))
+	for i, p := range percentages {
+		steps[i] = RolloutStep{Percentage: p, DwellSeconds: int(dwell / time.Second)}
+	}
+	return steps
+}
+
+// Validate checks that the rollout steps are well formed
+func (r RolloutCreate) Validate() error {
+	if len(r.Steps) == 0 {
+		return fmt.Errorf("invalid rollout: at least one step is required")
+	}
+	prev := 0.0
+	for i, step := range r.Steps {
+		if step.Percentage <= prev || step.Percentage > 100 {
+			return fmt.Errorf("invalid rollout: step %d percentage %.2f must be greater than %.2f and at most 100", i, step.Percentage, prev)
+		}
+		if step.DwellSeconds < 0 {
+			return fmt.Errorf("invalid rollout: step %d dwell time must not be negative", i)
+		}
+		prev = step.Percentage
+	}
+	for i, cond := range r.PauseConditions {
+		if err := cond.validate(); err != nil {
+			return fmt.Errorf("invalid rollout: pause condition %d: %w", i, err)
+		}
+	}
+	for i, guardrail := range r.Guardrails {
+		if err := guardrail.Validate(); err != nil {
+			return fmt.Errorf("invalid rollout: guardrail %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// CreateRollout starts a progressive rollout of a feature flag, replacing any rollout that is not running
+func (c *Client) CreateRollout(ctx context.Context, flagID int, rollout RolloutCreate, reqOpts ...RequestOption) (*Rollout, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := rollout.Validate(); err != nil {
+		return nil, err
+	}
+	return c.rolloutRequest(ctx, request{
+		method: "PUT",
+		path:   fmt.Sprintf("/api/v1/feature-flags/%d/rollout", flagID),
+		body:   rollout,
+	})
+}
+
+// GetRollout retrieves the progressive rollout of a feature flag
+func (c *Client) GetRollout(ctx context.Context, flagID int, reqOpts ...RequestOption) (*Rollout, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.rolloutRequest(ctx, request{
+		method: "GET",
+		path:   fmt.Sprintf("/api/v1/feature-flags/%d/rollout", flagID),
+	})
+}
+
+// PauseRollout pauses the progressive rollout of a feature flag at its current step
+func (c *Client) PauseRollout(ctx context.Context, flagID int, reason string, reqOpts ...RequestOption) (*Rollout, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.rolloutRequest(ctx, request{
+		method: "POST",
+		path:   fmt.Sprintf("/api/v1/feature-flags/%d/rollout/pause", flagID),
+		body:   map[string]string{"reason": reason},
+	})
+}
+
+// ResumeRollout resumes a paused progressive rollout
+func (c *Client) ResumeRollout(ctx context.Context, flagID int, reqOpts ...RequestOption) (*Rollout, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.rolloutRequest(ctx, request{
+		method: "POST",
+		path:   fmt.Sprintf("/api/v1/feature-flags/%d/rollout/resume", flagID),
+	})
+}
+
+// RollbackRollout stops the progressive rollout of a feature flag and reverts it to 0%
+func (c *Client) RollbackRollout(ctx context.Context, flagID int, reason string, reqOpts ...RequestOption) (*Rollout, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.rolloutRequest(ctx, request{
+		method: "POST",
+		path:   fmt.Sprintf("/api/v1/feature-flags/%d/rollout/rollback", flagID),
+		body:   map[string]string{"reason": reason},
+	})
+}
+
+// CancelRollout cancels the progressive rollout of a feature flag, leaving it at its current percentage
+func (c *Client) CancelRollout(ctx context.Context, flagID int, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	_, err := c.doRequest(ctx, request{
+		method: "DELETE",
+		path:   fmt.Sprintf("/api/v1/feature-flags/%d/rollout", flagID),
+	})
+	return err
+}
+
+// rolloutRequest performs a request returning a rollout
+func (c *Client) rolloutRequest(ctx context.Context, req request) (*Rollout, error) {
+	respBody, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var rollout Rollout
+	if err := json.Unmarshal(respBody, &rollout); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return &rollout, nil
+}
@@ -0,0 +1,106 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRolloutSteps(t *testing.T) {
+	assert.Equal(t, []RolloutStep{
+		{Percentage: 10, DwellSeconds: 3600},
+		{Percentage: 25, DwellSeconds: 3600},
+		{Percentage: 50, DwellSeconds: 3600},
+		{Percentage: 100, DwellSeconds: 3600},
+	}, RolloutSteps(time.Hour, 10, 25, 50, 100))
+	assert.Empty(t, RolloutSteps(time.Hour))
+}
+
+func TestRolloutCreateValidate(t *testing.T) {
+	steps := RolloutSteps(time.Hour, 10, 50, 100)
+	window := func(start, end, timezone string, weekdays ...time.Weekday) RolloutCreate {
+		return RolloutCreate{Steps: steps, PauseConditions: []PauseCondition{
+			{Type: PauseOnFlagChange},
+			{Type: PauseOutsideWindow, WindowStart: start, WindowEnd: end, Timezone: timezone, Weekdays: weekdays},
+		}}
+	}
+	tests := []struct {
+		name    string
+		rollout RolloutCreate
+		err     string
+	}{
+		{name: "steps", rollout: RolloutCreate{Steps: steps}},
+		{name: "window", rollout: window("09:00", "17:30", "Europe/Berlin", time.Monday, time.Friday)},
+		{name: "window in UTC every day", rollout: window("22:00", "06:00", "")},
+		{name: "no steps", rollout: RolloutCreate{}, err: "at least one step is required"},
+		{name: "decreasing percentage", rollout: RolloutCreate{Steps: RolloutSteps(time.Hour, 50, 25)}, err: "step 1 percentage 25.00 must be greater than 50.00"},
+		{name: "over 100", rollout: RolloutCreate{Steps: RolloutSteps(time.Hour, 50, 150)}, err: "step 1 percentage 150.00"},
+		{name: "negative dwell", rollout: RolloutCreate{Steps: RolloutSteps(-time.Hour, 50)}, err: "step 0 dwell time must not be negative"},
+		{name: "unknown condition", rollout: RolloutCreate{Steps: steps, PauseConditions: []PauseCondition{{Type: "weather"}}}, err: `pause condition 0: unknown type "weather"`},
+		{name: "missing window end", rollout: window("09:00", "", ""), err: "pause condition 1: window_start and window_end are required"},
+		{name: "hour out of range", rollout: window("25:99", "17:00", ""), err: `pause condition 1: invalid window time "25:99": must be HH:MM`},
+		{name: "not a time", rollout: window("09:00", "9am", ""), err: `pause condition 1: invalid window time "9am"`},
+		{name: "unknown timezone", rollout: window("09:00", "17:00", "Mars/Olympus_Mons"), err: `pause condition 1: invalid timezone "Mars/Olympus_Mons"`},
+		{name: "weekday out of range", rollout: window("09:00", "17:00", "", time.Monday, 7), err: "pause condition 1: invalid weekday 7: must be 0 (Sunday) to 6 (Saturday)"},
+		{name: "guardrail", rollout: RolloutCreate{Steps: steps, Guardrails: []Guardrail{{Name: "errors", Query: "up"}}}, err: `guardrail 0: unknown provider ""`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rollout.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, "invalid rollout: "+tt.err)
+			}
+		})
+	}
+}
+
+func TestCreateRollout(t *testing.T) {
+	var requests []string
+	var body RolloutCreate
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPut {
+			json.NewDecoder(r.Body).Decode(&body)
+		}
+		status := RolloutRunning
+		if r.URL.Path == "/api/v1/feature-flags/7/rollout/pause" {
+			status = RolloutPaused
+		}
+		writeJSON(w, Rollout{FlagID: 7, Steps: body.Steps, Status: status})
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+
+	_, err := client.CreateRollout(ctx, 7, RolloutCreate{Steps: RolloutSteps(time.Hour, 100), PauseConditions: []PauseCondition{
+		{Type: PauseOutsideWindow, WindowStart: "9am", WindowEnd: "17:00"},
+	}})
+	assert.ErrorContains(t, err, "invalid window time")
+	assert.Empty(t, requests, "invalid rollouts are not sent")
+
+	rollout, err := client.CreateRollout(ctx, 7, RolloutCreate{Steps: RolloutSteps(30*time.Minute, 10, 100)})
+	require.NoError(t, err)
+	assert.Equal(t, RolloutRunning, rollout.Status)
+	assert.Equal(t, RolloutSteps(30*time.Minute, 10, 100), body.Steps)
+	rollout, err = client.PauseRollout(ctx, 7, "investigating")
+	require.NoError(t, err)
+	assert.Equal(t, RolloutPaused, rollout.Status)
+	_, err = client.ResumeRollout(ctx, 7)
+	require.NoError(t, err)
+	require.NoError(t, client.CancelRollout(ctx, 7))
+
+	assert.Equal(t, []string{
+		"PUT /api/v1/feature-flags/7/rollout",
+		"POST /api/v1/feature-flags/7/rollout/pause",
+		"POST /api/v1/feature-flags/7/rollout/resume",
+		"DELETE /api/v1/feature-flags/7/rollout",
+	}, requests)
+}
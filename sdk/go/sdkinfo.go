@@ -0,0 +1,44 @@
+package matrixflag
+
+import "strings"
+
+// Version is the version of the SDK
+const Version = "1.0.0"
+
+// SDKHeader is the header describing the SDK that sent a request
+const SDKHeader = "X-MatrixFlag-SDK"
+
+// WithWrapper identifies a library wrapping the SDK, such as an OpenFeature
+// provider, in the User-Agent and X-MatrixFlag-SDK headers of every request
+func WithWrapper(name, version string) ClientOption {
+	return func(c *Client) {
+		c.wrapperName = name
+		c.wrapperVersion = version
+	}
+}
+
+// userAgent returns the User-Agent header sent with every request
+func (c *Client) userAgent() string {
+	ua := "matrixflag-go/" + Version
+	if c.wrapperName != "" {
+		ua += " " + product(c.wrapperName, c.wrapperVersion)
+	}
+	return ua
+}
+
+// sdkHeader returns the X-MatrixFlag-SDK header sent with every request
+func (c *Client) sdkHeader() string {
+	fields := []string{"language=go", "version=" + Version}
+	if c.wrapperName != "" {
+		fields = append(fields, "wrapper="+product(c.wrapperName, c.wrapperVersion))
+	}
+	return strings.Join(fields, "; ")
+}
+
+// product formats a name and optional version as a User-Agent product token
+func product(name, version string) string {
+	if version == "" {
+		return name
+	}
+	return name + "/" + version
+}
@@ -0,0 +1,87 @@
+package matrixflag
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// headerServer records the SDK headers of the requests sent to a fakeServer
+type headerServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	headers map[string][2]string
+}
+
+func newHeaderServer(t *testing.T, fake *fakeServer) *headerServer {
+	s := &headerServer{headers: make(map[string][2]string)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.headers[r.Method+" "+r.URL.Path] = [2]string{r.Header.Get("User-Agent"), r.Header.Get(SDKHeader)}
+		s.mu.Unlock()
+		fake.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *headerServer) received() map[string][2]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	received := make(map[string][2]string, len(s.headers))
+	for k, v := range s.headers {
+		received[k] = v
+	}
+	return received
+}
+
+func TestSDKHeaders(t *testing.T) {
+	fake := newFakeServer(t)
+	flag := fake.addFlag(FeatureFlag{Name: "checkout"})
+	srv := newHeaderServer(t, fake)
+	client := NewClient(srv.URL, "key", nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := client.GetFeatureFlag(ctx, flag.ID, WithHeader("User-Agent", "curl/8.0"))
+	require.NoError(t, err)
+	_, err = client.ToggleFeatureFlag(ctx, flag.ID)
+	require.NoError(t, err)
+	_, err = client.StreamFlags(ctx)
+	require.NoError(t, err)
+
+	want := [2]string{"matrixflag-go/" + Version, "language=go; version=" + Version}
+	assert.Equal(t, map[string][2]string{
+		"GET /api/v1/feature-flags/1":         want,
+		"POST /api/v1/feature-flags/1/toggle": want,
+		"GET /api/v1/feature-flags/stream":    want,
+	}, srv.received(), "every request identifies the SDK, and call options cannot override it")
+}
+
+func TestSDKHeadersWrapper(t *testing.T) {
+	fake := newFakeServer(t)
+	flag := fake.addFlag(FeatureFlag{Name: "checkout"})
+	srv := newHeaderServer(t, fake)
+	ctx := context.Background()
+
+	client := NewClient(srv.URL, "key", nil, WithWrapper("openfeature-provider", "0.2.0"))
+	_, err := client.GetFeatureFlag(ctx, flag.ID)
+	require.NoError(t, err)
+	assert.Equal(t, [2]string{
+		"matrixflag-go/" + Version + " openfeature-provider/0.2.0",
+		"language=go; version=" + Version + "; wrapper=openfeature-provider/0.2.0",
+	}, srv.received()["GET /api/v1/feature-flags/1"])
+
+	client = NewClient(srv.URL, "key", nil, WithWrapper("ginflag", ""))
+	_, err = client.GetFeatureFlag(ctx, flag.ID)
+	require.NoError(t, err)
+	assert.Equal(t, [2]string{
+		"matrixflag-go/" + Version + " ginflag",
+		"language=go; version=" + Version + "; wrapper=ginflag",
+	}, srv.received()["GET /api/v1/feature-flags/1"], "the wrapper version is optional")
+}
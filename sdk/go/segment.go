@@ -0,0 +1,163 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Segment is a reusable audience that flag rules target by name. A context
+// belongs to a segment when its key is included, or when it matches any of the
+// segment's rules, unless its key is excluded.
+type Segment struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Included and Excluded list context keys always and never in the segment
+	Included []string `json:"included,omitempty"`
+	Excluded []string `json:"excluded,omitempty"`
+	// Rules match contexts by attributes; a context matching all conditions of any rule belongs to the segment
+	Rules     []SegmentRule `json:"rules,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// SegmentRule matches the contexts satisfying all of its conditions
+type SegmentRule struct {
+	Conditions []TargetingCondition `json:"conditions"`
+}
+
+// SegmentCreate represents the data needed to create a segment
+type SegmentCreate struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Included    []string      `json:"included,omitempty"`
+	Excluded    []string      `json:"excluded,omitempty"`
+	Rules       []SegmentRule `json:"rules,omitempty"`
+}
+
+// SegmentUpdate represents the data needed to update a segment
+type SegmentUpdate struct {
+	Description string        `json:"description,omitempty"`
+	Included    []string      `json:"included,omitempty"`
+	Excluded    []string      `json:"excluded,omitempty"`
+	Rules       []SegmentRule `json:"rules,omitempty"`
+	// Fields names fields sent even when empty, such as FieldIncluded to clear the included keys
+	Fields FieldMask `json:"-"`
+}
+
+// ValidateSegmentRules checks the conditions of segment rules
+func ValidateSegmentRules(rules []SegmentRule) error {
+	for i, rule := range rules {
+		if len(rule.Conditions) == 0 {
+			return fmt.Errorf("invalid segment rule %d: at least one condition is required", i)
+		}
+		for _, cond := range rule.Conditions {
+			if err := cond.Validate(); err != nil {
+				return fmt.Errorf("invalid segment rule %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Contains reports whether a context, given as attributes, belongs to the segment.
+// Included and excluded keys are compared with the "key" attribute.
+func (s *Segment) Contains(attributes map[string]any) bool {
+	if key, ok := BucketingKey(attributes, ""); ok {
+		for _, excluded := range s.Excluded {
+			if excluded == key {
+				return false
+			}
+		}
+		for _, included := range s.Included {
+			if included == key {
+				return true
+			}
+		}
+	}
+	for _, rule := range s.Rules {
+		if matchesConditions(rule.Conditions, attributes) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListSegments retrieves all segments
+func (c *Client) ListSegments(ctx context.Context, reqOpts ...RequestOption) ([]Segment, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	respBody, err := c.doRequest(ctx, request{
+		method: "GET",
+		path:   "/api/v1/targeting/segments",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var segments []Segment
+	if err := json.Unmarshal(respBody, &segments); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return segments, nil
+}
+
+// GetSegment retrieves a segment by name
+func (c *Client) GetSegment(ctx context.Context, name string, reqOpts ...RequestOption) (*Segment, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.segmentRequest(ctx, request{
+		method: "GET",
+		path:   "/api/v1/targeting/segments/" + url.PathEscape(name),
+	})
+}
+
+// CreateSegment creates a new segment
+func (c *Client) CreateSegment(ctx context.Context, segment SegmentCreate, reqOpts ...RequestOption) (*Segment, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := ValidateSegmentRules(segment.Rules); err != nil {
+		return nil, err
+	}
+	return c.segmentRequest(ctx, request{
+		method: "POST",
+		path:   "/api/v1/targeting/segments",
+		body:   segment,
+	})
+}
+
+// UpdateSegment updates a segment
+func (c *Client) UpdateSegment(ctx context.Context, name string, segment SegmentUpdate, reqOpts ...RequestOption) (*Segment, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := ValidateSegmentRules(segment.Rules); err != nil {
+		return nil, err
+	}
+	return c.segmentRequest(ctx, request{
+		method: "PUT",
+		path:   "/api/v1/targeting/segments/" + url.PathEscape(name),
+		body:   segment,
+	})
+}
+
+// DeleteSegment deletes a segment
+func (c *Client) DeleteSegment(ctx context.Context, name string, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	_, err := c.doRequest(ctx, request{
+		method: "DELETE",
+		path:   "/api/v1/targeting/segments/" + url.PathEscape(name),
+	})
+	return err
+}
+
+// segmentRequest performs a request returning a segment
+func (c *Client) segmentRequest(ctx context.Context, req request) (*Segment, error) {
+	respBody, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var segment Segment
+	if err := json.Unmarshal(respBody, &segment); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return &segment, nil
+}
@@ -0,0 +1,122 @@
+package matrixflag
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// EvaluateFunc evaluates a flag for an evaluation context
+type EvaluateFunc func(ctx context.Context, flagKey string, evalCtx EvaluationContext) (any, error)
+
+// ShadowDiscrepancy describes a flag evaluation where the shadow result differed from the primary one
+type ShadowDiscrepancy struct {
+	FlagKey string
+	Context EvaluationContext
+	Primary any
+	Shadow  any
+}
+
+// ShadowStats counts the outcomes of shadow evaluations
+type ShadowStats struct {
+	Evaluations   uint64
+	Discrepancies uint64
+	ShadowErrors  uint64
+}
+
+// ShadowOptions configures a ShadowEvaluator
+type ShadowOptions struct {
+	// Compare reports whether the primary and shadow values agree, reflect.DeepEqual by default
+	Compare func(primary, shadow any) bool
+	// OnDiscrepancy is called when the values disagree
+	OnDiscrepancy func(ctx context.Context, d ShadowDiscrepancy)
+	// OnShadowError is called when the shadow evaluation fails
+	OnShadowError func(ctx context.Context, flagKey string, err error)
+	// Async runs the shadow evaluation in the background, so it never adds latency
+	Async bool
+}
+
+// ShadowEvaluator evaluates flags with a primary evaluator, such as the remote
+// API, and compares every result with a shadow evaluator, such as a local
+// engine, to build confidence before switching to it. Callers always get the
+// primary result.
+type ShadowEvaluator struct {
+	primary EvaluateFunc
+	shadow  EvaluateFunc
+	opts    ShadowOptions
+
+	evaluations   atomic.Uint64
+	discrepancies atomic.Uint64
+	shadowErrors  atomic.Uint64
+	pending       sync.WaitGroup
+}
+
+// NewShadowEvaluator creates a shadow evaluator
+func NewShadowEvaluator(primary, shadow EvaluateFunc, opts ShadowOptions) *ShadowEvaluator {
+	if opts.Compare == nil {
+		opts.Compare = func(primary, shadow any) bool {
+			return reflect.DeepEqual(primary, shadow)
+		}
+	}
+	return &ShadowEvaluator{primary: primary, shadow: shadow, opts: opts}
+}
+
+// Evaluate returns the primary evaluation and compares it with the shadow evaluation.
+// Results are only compared when the primary evaluation succeeds.
+func (s *ShadowEvaluator) Evaluate(ctx context.Context, flagKey string, evalCtx EvaluationContext) (any, error) {
+	value, err := s.primary(ctx, flagKey, evalCtx)
+	if err != nil {
+		return value, err
+	}
+
+	if !s.opts.Async {
+		s.compare(ctx, flagKey, evalCtx, value)
+		return value, nil
+	}
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+		s.compare(context.WithoutCancel(ctx), flagKey, evalCtx, value)
+	}()
+	return value, nil
+}
+
+// compare runs the shadow evaluation and reports how it relates to the primary value
+func (s *ShadowEvaluator) compare(ctx context.Context, flagKey string, evalCtx EvaluationContext, primary any) {
+	s.evaluations.Add(1)
+	shadow, err := s.shadow(ctx, flagKey, evalCtx)
+	if err != nil {
+		s.shadowErrors.Add(1)
+		if s.opts.OnShadowError != nil {
+			s.opts.OnShadowError(ctx, flagKey, err)
+		}
+		return
+	}
+	if s.opts.Compare(primary, shadow) {
+		return
+	}
+	s.discrepancies.Add(1)
+	if s.opts.OnDiscrepancy != nil {
+		s.opts.OnDiscrepancy(ctx, ShadowDiscrepancy{
+			FlagKey: flagKey,
+			Context: evalCtx,
+			Primary: primary,
+			Shadow:  shadow,
+		})
+	}
+}
+
+// Stats returns the counts of shadow evaluations so far
+func (s *ShadowEvaluator) Stats() ShadowStats {
+	return ShadowStats{
+		Evaluations:   s.evaluations.Load(),
+		Discrepancies: s.discrepancies.Load(),
+		ShadowErrors:  s.shadowErrors.Load(),
+	}
+}
+
+// Wait blocks until all background shadow evaluations have finished
+func (s *ShadowEvaluator) Wait() {
+	s.pending.Wait()
+}
@@ -0,0 +1,132 @@
+package matrixflag
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// evaluatorFunc adapts an Evaluator to an EvaluateFunc
+func evaluatorFunc(e *Evaluator) EvaluateFunc {
+	return func(_ context.Context, flagKey string, evalCtx EvaluationContext) (any, error) {
+		d := e.Evaluate(flagKey, evalCtx, false)
+		return d.Value, d.Err
+	}
+}
+
+// newShadowEvaluators returns a primary and a shadow evaluator whose rulesets
+// disagree on checkout for the users of the beta plan
+func newShadowEvaluators() (primary, shadow EvaluateFunc) {
+	client := NewClient("http://localhost", "key", nil)
+	flags := []FeatureFlag{{ID: 1, Name: "checkout", IsActive: true}, {ID: 2, Name: "search", IsActive: true}}
+	p := NewEvaluator(client, EvaluatorOptions{Environment: "production"})
+	p.SetRuleset(&Ruleset{Environment: "production", Flags: flags})
+
+	flags = append([]FeatureFlag(nil), flags...)
+	flags[0].Rollout = &PercentageRollout{Percentage: 0}
+	flags[0].Rules = []FlagRule{{ID: "free", Conditions: []TargetingCondition{{Attribute: "plan", Operator: OpEquals, Value: "free"}}}}
+	s := NewEvaluator(client, EvaluatorOptions{Environment: "production"})
+	s.SetRuleset(&Ruleset{Environment: "production", Flags: flags})
+	return evaluatorFunc(p), evaluatorFunc(s)
+}
+
+func TestShadowEvaluatorDiscrepancy(t *testing.T) {
+	primary, shadow := newShadowEvaluators()
+	var discrepancies []ShadowDiscrepancy
+	evaluator := NewShadowEvaluator(primary, shadow, ShadowOptions{
+		OnDiscrepancy: func(_ context.Context, d ShadowDiscrepancy) { discrepancies = append(discrepancies, d) },
+	})
+	ctx := context.Background()
+	free := EvaluationContext{Key: "user-1", Attributes: map[string]any{"plan": "free"}}
+	beta := EvaluationContext{Key: "user-2", Attributes: map[string]any{"plan": "beta"}}
+
+	for _, evalCtx := range []EvaluationContext{free, beta} {
+		for _, flag := range []string{"checkout", "search"} {
+			value, err := evaluator.Evaluate(ctx, flag, evalCtx)
+			require.NoError(t, err)
+			assert.Equal(t, true, value, "callers get the primary result")
+		}
+	}
+
+	assert.Equal(t, []ShadowDiscrepancy{{FlagKey: "checkout", Context: beta, Primary: true, Shadow: false}}, discrepancies)
+	assert.Equal(t, ShadowStats{Evaluations: 4, Discrepancies: 1}, evaluator.Stats())
+}
+
+func TestShadowEvaluatorErrors(t *testing.T) {
+	primary, _ := newShadowEvaluators()
+	var shadowErrors []string
+	calls := 0
+	evaluator := NewShadowEvaluator(primary, func(context.Context, string, EvaluationContext) (any, error) {
+		calls++
+		return nil, errors.New("local engine not ready")
+	}, ShadowOptions{
+		OnShadowError: func(_ context.Context, flagKey string, err error) {
+			shadowErrors = append(shadowErrors, flagKey+": "+err.Error())
+		},
+	})
+	ctx := context.Background()
+	user := EvaluationContext{Key: "user-1"}
+
+	value, err := evaluator.Evaluate(ctx, "checkout", user)
+	require.NoError(t, err, "shadow errors are not returned")
+	assert.Equal(t, true, value)
+	assert.Equal(t, []string{"checkout: local engine not ready"}, shadowErrors)
+
+	_, err = evaluator.Evaluate(ctx, "missing", user)
+	assert.True(t, IsNotFound(err), "primary errors are returned")
+	assert.Equal(t, 1, calls, "failed primary evaluations are not compared")
+	assert.Equal(t, ShadowStats{Evaluations: 1, ShadowErrors: 1}, evaluator.Stats())
+}
+
+func TestShadowEvaluatorCompare(t *testing.T) {
+	primary := func(context.Context, string, EvaluationContext) (any, error) { return float64(3), nil }
+	shadow := func(context.Context, string, EvaluationContext) (any, error) { return 3, nil }
+	ctx := context.Background()
+
+	evaluator := NewShadowEvaluator(primary, shadow, ShadowOptions{})
+	evaluator.Evaluate(ctx, "limit", EvaluationContext{Key: "user-1"})
+	assert.Equal(t, uint64(1), evaluator.Stats().Discrepancies, "values are compared with reflect.DeepEqual by default")
+
+	evaluator = NewShadowEvaluator(primary, shadow, ShadowOptions{Compare: func(p, s any) bool {
+		return p.(float64) == float64(s.(int))
+	}})
+	evaluator.Evaluate(ctx, "limit", EvaluationContext{Key: "user-1"})
+	assert.Zero(t, evaluator.Stats().Discrepancies)
+}
+
+func TestShadowEvaluatorAsync(t *testing.T) {
+	primary, shadow := newShadowEvaluators()
+	var mu sync.Mutex
+	var discrepancies []ShadowDiscrepancy
+	evaluator := NewShadowEvaluator(primary, shadow, ShadowOptions{
+		Async: true,
+		OnDiscrepancy: func(ctx context.Context, d ShadowDiscrepancy) {
+			assert.NoError(t, ctx.Err(), "background comparisons outlive the caller's context")
+			mu.Lock()
+			defer mu.Unlock()
+			discrepancies = append(discrepancies, d)
+		},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	beta := EvaluationContext{Key: "user-2", Attributes: map[string]any{"plan": "beta"}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := evaluator.Evaluate(ctx, "checkout", beta)
+			assert.NoError(t, err)
+			assert.Equal(t, true, value)
+		}()
+	}
+	wg.Wait()
+	cancel()
+	evaluator.Wait()
+	assert.Len(t, discrepancies, 20)
+	assert.Equal(t, ShadowStats{Evaluations: 20, Discrepancies: 20}, evaluator.Stats())
+}
@@ -0,0 +1,91 @@
+package siem
+
+import (
+	"context"
+	"time"
+
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+// Defaults of an Exporter
+const (
+	DefaultInterval  = 30 * time.Second
+	DefaultBatchSize = 100
+)
+
+// Options configures an Exporter
+type Options struct {
+	// Interval is the time between audit log polls, DefaultInterval by default
+	Interval time.Duration
+	// BatchSize is the number of entries fetched and sent at a time, DefaultBatchSize by default
+	BatchSize int
+	// OnError is called when an export fails; the failed batch is retried on the next poll
+	OnError func(error)
+}
+
+// Exporter streams audit log entries to a sink
+type Exporter struct {
+	client     *matrixflag.Client
+	sink       Sink
+	checkpoint Checkpoint
+	opts       Options
+}
+
+// New creates a new Exporter
+func New(client *matrixflag.Client, sink Sink, checkpoint Checkpoint, opts Options) *Exporter {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	return &Exporter{
+		client:     client,
+		sink:       sink,
+		checkpoint: checkpoint,
+		opts:       opts,
+	}
+}
+
+// Run exports new entries immediately and then every interval until ctx is canceled
+func (e *Exporter) Run(ctx context.Context) {
+	for {
+		if _, err := e.Export(ctx); err != nil && ctx.Err() == nil && e.opts.OnError != nil {
+			e.opts.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-e.client.Clock().After(e.opts.Interval):
+		}
+	}
+}
+
+// Export sends every entry recorded since the checkpoint, one batch at a time,
+// and returns the number of entries delivered. The checkpoint advances after
+// each delivered batch, so a failure resends at most the failed batch.
+func (e *Exporter) Export(ctx context.Context) (int, error) {
+	after, err := e.checkpoint.Load(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for {
+		entries, err := e.client.ListAuditLog(ctx, matrixflag.AuditLogQuery{After: after, Limit: e.opts.BatchSize})
+		if err != nil || len(entries) == 0 {
+			return delivered, err
+		}
+		if err := e.sink.Send(ctx, entries); err != nil {
+			return delivered, err
+		}
+		after = entries[len(entries)-1].ID
+		if err := e.checkpoint.Save(ctx, after); err != nil {
+			return delivered, err
+		}
+		delivered += len(entries)
+		if len(entries) < e.opts.BatchSize {
+			return delivered, nil
+		}
+	}
+}
@@ -0,0 +1,211 @@
+// Package siem mirrors the Matrix Flag audit log into a SIEM, forwarding every
+// entry over syslog in CEF format or to an HTTP collector. Delivery is at least
+// once: the position in the audit log is checkpointed only after entries have
+// been delivered, so an exporter resumes where it stopped after a restart.
+package siem
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+// Sink receives audit log entries
+type Sink interface {
+	Send(ctx context.Context, entries []matrixflag.AuditLogEntry) error
+}
+
+// CEF formats an audit log entry as an ArcSight Common Event Format record
+func CEF(e matrixflag.AuditLogEntry) string {
+	ext := []string{
+		fmt.Sprintf("rt=%d", e.Time.UnixMilli()),
+		"suser=" + cefValue(e.Actor),
+		"act=" + cefValue(e.Action),
+		"cs1Label=resourceType", "cs1=" + cefValue(e.ResourceType),
+		"cs2Label=resourceId", "cs2=" + cefValue(e.ResourceID),
+		"cs3Label=environment", "cs3=" + cefValue(e.Environment),
+		"externalId=" + cefValue(e.ID),
+	}
+	if e.ResourceName != "" {
+		ext = append(ext, "cs4Label=resourceName", "cs4="+cefValue(e.ResourceName))
+	}
+	if e.SourceIP != "" {
+		ext = append(ext, "src="+cefValue(e.SourceIP))
+	}
+	// Configuration changes are reported with low severity (3 of 10)
+	return fmt.Sprintf("CEF:0|Matrix Flag|matrixflag|%s|%s|%s|3|%s",
+		cefHeader(matrixflag.Version),
+		cefHeader(e.ResourceType+"."+e.Action),
+		cefHeader(fmt.Sprintf("%s %s %s", e.ResourceType, e.ResourceName, e.Action)),
+		strings.Join(ext, " "),
+	)
+}
+
+// cefHeader escapes a CEF header field
+func cefHeader(v string) string {
+	return strings.NewReplacer(`\`, `\\`, "|", `\|`, "\n", " ", "\r", " ").Replace(v)
+}
+
+// cefValue escapes a CEF extension value
+func cefValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, "=", `\=`, "\n", `\n`, "\r", `\r`).Replace(v)
+}
+
+// SyslogSink sends entries as CEF records in RFC 5424 syslog messages over TCP or UDP
+type SyslogSink struct {
+	// Network is "tcp" or "udp"
+	Network string
+	// Address is the host:port of the syslog server
+	Address string
+	// Hostname is reported as the message origin, the local hostname by default
+	Hostname string
+	// Facility is the syslog facility, 13 (log audit) by default
+	Facility int
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// Send implements Sink
+func (s *SyslogSink) Send(ctx context.Context, entries []matrixflag.AuditLogEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, s.Network, s.Address)
+		if err != nil {
+			return fmt.Errorf("failed to connect to syslog server: %w", err)
+		}
+		s.conn = conn
+	}
+
+	hostname := s.Hostname
+	if hostname == "" {
+		hostname, _ = os.Hostname()
+	}
+	facility := s.Facility
+	if facility == 0 {
+		facility = 13
+	}
+	// Severity 5 (notice)
+	priority := facility*8 + 5
+
+	for _, e := range entries {
+		msg := fmt.Sprintf("<%d>1 %s %s matrixflag - audit - %s\n",
+			priority, e.Time.UTC().Format(time.RFC3339Nano), hostname, CEF(e))
+		if _, err := io.WriteString(s.conn, msg); err != nil {
+			s.conn.Close()
+			s.conn = nil
+			return fmt.Errorf("failed to write to syslog server: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close closes the connection to the syslog server
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// HTTPSink posts entries as a JSON array to an HTTP collector
+type HTTPSink struct {
+	URL string
+	// Headers are added to every request, for example for authentication
+	Headers map[string]string
+	// HTTPClient is used to send requests, http.DefaultClient by default
+	HTTPClient *http.Client
+}
+
+// Send implements Sink
+func (s *HTTPSink) Send(ctx context.Context, entries []matrixflag.AuditLogEntry) error {
+	body, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit log entries: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.Headers {
+		req.Header.Set(k, v)
+	}
+
+	httpClient := s.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post audit log entries: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("collector returned status %d: %s", resp.StatusCode, msg)
+	}
+	return nil
+}
+
+// Checkpoint stores the ID of the last delivered audit log entry
+type Checkpoint interface {
+	// Load returns the stored ID, or "" when nothing has been delivered yet
+	Load(ctx context.Context) (string, error)
+	Save(ctx context.Context, id string) error
+}
+
+// FileCheckpoint stores the checkpoint in a file, replaced atomically on every save
+type FileCheckpoint struct {
+	Path string
+}
+
+// Load implements Checkpoint
+func (f FileCheckpoint) Load(context.Context) (string, error) {
+	data, err := os.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Save implements Checkpoint
+func (f FileCheckpoint) Save(_ context.Context, id string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), filepath.Base(f.Path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(id + "\n"); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), f.Path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package siem
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// auditServer serves an audit log, a page of entries after a given ID at a time
+type auditServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	entries []matrixflag.AuditLogEntry
+}
+
+func newAuditServer(t *testing.T) *auditServer {
+	s := &auditServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		entries := s.entries
+		if after := r.URL.Query().Get("after"); after != "" {
+			for i, e := range entries {
+				if e.ID == after {
+					entries = entries[i+1:]
+					break
+				}
+			}
+		}
+		if limit, _ := strconv.Atoi(r.URL.Query().Get("limit")); limit > 0 && limit < len(entries) {
+			entries = entries[:limit]
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(entries)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// record appends n entries to the audit log
+func (s *auditServer) record(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < n; i++ {
+		id := len(s.entries) + 1
+		s.entries = append(s.entries, matrixflag.AuditLogEntry{
+			ID:           fmt.Sprintf("a%d", id),
+			Time:         time.Date(2024, 5, 1, 12, id, 0, 0, time.UTC),
+			Actor:        "alice@example.com",
+			Action:       "update",
+			ResourceType: "flag",
+			ResourceID:   strconv.Itoa(id),
+		})
+	}
+}
+
+// recordingSink keeps the IDs of the entries it receives, failing the batch
+// starting with the entry failAt
+type recordingSink struct {
+	ids    []string
+	failAt string
+}
+
+func (s *recordingSink) Send(_ context.Context, entries []matrixflag.AuditLogEntry) error {
+	if entries[0].ID == s.failAt {
+		s.failAt = ""
+		return errors.New("collector unavailable")
+	}
+	for _, e := range entries {
+		s.ids = append(s.ids, e.ID)
+	}
+	return nil
+}
+
+func TestExportResumesAfterRestart(t *testing.T) {
+	srv := newAuditServer(t)
+	srv.record(5)
+	client := matrixflag.NewClient(srv.URL, "key", nil, matrixflag.WithRetries(0, 0, 0))
+	checkpoint := FileCheckpoint{Path: filepath.Join(t.TempDir(), "siem.checkpoint")}
+	sink := &recordingSink{failAt: "a3"}
+	ctx := context.Background()
+
+	delivered, err := New(client, sink, checkpoint, Options{BatchSize: 2}).Export(ctx)
+	assert.EqualError(t, err, "collector unavailable")
+	assert.Equal(t, 2, delivered)
+	id, err := checkpoint.Load(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "a2", id, "the checkpoint advances only past delivered batches")
+
+	// A new exporter sharing the checkpoint resumes with the failed batch
+	delivered, err = New(client, sink, checkpoint, Options{BatchSize: 2}).Export(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, delivered)
+	assert.Equal(t, []string{"a1", "a2", "a3", "a4", "a5"}, sink.ids)
+
+	srv.record(1)
+	delivered, err = New(client, sink, checkpoint, Options{BatchSize: 2}).Export(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	delivered, err = New(client, sink, checkpoint, Options{BatchSize: 2}).Export(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, delivered, "delivered entries are not sent again")
+	assert.Equal(t, []string{"a1", "a2", "a3", "a4", "a5", "a6"}, sink.ids)
+}
+
+func TestFileCheckpoint(t *testing.T) {
+	checkpoint := FileCheckpoint{Path: filepath.Join(t.TempDir(), "siem.checkpoint")}
+	ctx := context.Background()
+
+	id, err := checkpoint.Load(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, id, "nothing has been delivered without a checkpoint file")
+	require.NoError(t, checkpoint.Save(ctx, "a1"))
+	require.NoError(t, checkpoint.Save(ctx, "a2"))
+	id, err = checkpoint.Load(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "a2", id)
+
+	matches, err := filepath.Glob(checkpoint.Path + ".*")
+	require.NoError(t, err)
+	assert.Empty(t, matches, "temporary files are removed")
+}
+
+func TestCEF(t *testing.T) {
+	entry := matrixflag.AuditLogEntry{
+		ID:           "a1",
+		Time:         time.UnixMilli(1714564800000),
+		Actor:        "alice=admin",
+		Action:       "update",
+		ResourceType: "flag",
+		ResourceID:   "7",
+		ResourceName: "checkout|v2",
+		Environment:  "production",
+		SourceIP:     "10.0.0.1",
+	}
+	assert.Equal(t, "CEF:0|Matrix Flag|matrixflag|"+matrixflag.Version+"|flag.update|flag checkout\\|v2 update|3|"+
+		"rt=1714564800000 suser=alice\\=admin act=update cs1Label=resourceType cs1=flag cs2Label=resourceId cs2=7 "+
+		"cs3Label=environment cs3=production externalId=a1 cs4Label=resourceName cs4=checkout|v2 src=10.0.0.1", CEF(entry))
+}
+
+func TestSyslogSink(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	lines := make(chan string)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	sink := &SyslogSink{Network: "tcp", Address: ln.Addr().String(), Hostname: "flags-1"}
+	defer sink.Close()
+	entry := matrixflag.AuditLogEntry{ID: "a1", Time: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), Action: "create", ResourceType: "flag"}
+	require.NoError(t, sink.Send(context.Background(), []matrixflag.AuditLogEntry{entry}))
+	assert.Equal(t, "<109>1 2024-05-01T12:00:00Z flags-1 matrixflag - audit - "+CEF(entry), <-lines)
+}
+
+func TestHTTPSink(t *testing.T) {
+	var received []matrixflag.AuditLogEntry
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Splunk token", r.Header.Get("Authorization"))
+		json.NewDecoder(r.Body).Decode(&received)
+		if status != http.StatusOK {
+			http.Error(w, "over quota", status)
+		}
+	}))
+	defer srv.Close()
+	sink := &HTTPSink{URL: srv.URL, Headers: map[string]string{"Authorization": "Splunk token"}}
+	entries := []matrixflag.AuditLogEntry{{ID: "a1", Action: "create", Time: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}}
+
+	require.NoError(t, sink.Send(context.Background(), entries))
+	assert.Equal(t, entries, received)
+
+	status = http.StatusTooManyRequests
+	assert.EqualError(t, sink.Send(context.Background(), entries), "collector returned status 429: over quota\n")
+}
@@ -0,0 +1,99 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// SimulatedEvaluation is the result of evaluating a draft flag for one context
+type SimulatedEvaluation struct {
+	Context     EvaluationContext `json:"context"`
+	Value       any               `json:"value"`
+	Reason      string            `json:"reason,omitempty"`
+	MatchedRule string            `json:"matched_rule,omitempty"`
+}
+
+// SimulationResult reports how a draft flag would evaluate for a set of contexts
+type SimulationResult struct {
+	Evaluations []SimulatedEvaluation `json:"evaluations"`
+}
+
+// Distribution counts the contexts receiving each value, keyed by the value's printed form
+func (r *SimulationResult) Distribution() map[string]int {
+	counts := make(map[string]int)
+	for _, e := range r.Evaluations {
+		counts[fmt.Sprint(e.Value)]++
+	}
+	return counts
+}
+
+// DistributionEntry is the share of contexts receiving a value
+type DistributionEntry struct {
+	Value string
+	Count int
+	// Percentage of the simulated contexts, between 0 and 100
+	Percentage float64
+}
+
+// Summary returns the distribution of values ordered from the most to the least common
+func (r *SimulationResult) Summary() []DistributionEntry {
+	counts := r.Distribution()
+	entries := make([]DistributionEntry, 0, len(counts))
+	for value, count := range counts {
+		entries = append(entries, DistributionEntry{
+			Value:      value,
+			Count:      count,
+			Percentage: 100 * float64(count) / float64(len(r.Evaluations)),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Value < entries[j].Value
+	})
+	return entries
+}
+
+// Simulate evaluates a draft or hypothetical flag configuration against sample
+// contexts on the server without saving it, to preview the resulting
+// distribution before a change goes live.
+func (c *Client) Simulate(ctx context.Context, draft FeatureFlag, contexts []EvaluationContext, reqOpts ...RequestOption) (*SimulationResult, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if len(contexts) == 0 {
+		return nil, errors.New("invalid simulation: no contexts")
+	}
+	for i, evalCtx := range contexts {
+		if err := evalCtx.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid simulation context %d: %w", i, err)
+		}
+	}
+	if draft.TrafficAllocation != nil {
+		if err := draft.TrafficAllocation.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	draft.Name = c.qualifyName(draft.Name)
+
+	respBody, err := c.doRequest(ctx, request{
+		method: "POST",
+		path:   "/api/v1/feature-flags/simulate",
+		body: map[string]any{
+			"flag":     draft,
+			"contexts": contexts,
+		},
+		class: EndpointBulk,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result SimulationResult
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,109 @@
+package matrixflag
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSimulationServer serves the flags of a fakeServer and simulates drafts
+// by evaluating them locally, recording the names of the simulated drafts
+func newSimulationServer(t *testing.T, fake *fakeServer, drafts *[]string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/feature-flags/simulate" {
+			fake.Config.Handler.ServeHTTP(w, r)
+			return
+		}
+		var body struct {
+			Flag     FeatureFlag         `json:"flag"`
+			Contexts []EvaluationContext `json:"contexts"`
+		}
+		if !decodeBody(w, r, &body) {
+			return
+		}
+		*drafts = append(*drafts, body.Flag.Name)
+		evaluator := NewEvaluator(NewClient("http://localhost", "key", nil), EvaluatorOptions{Environment: body.Flag.Environment})
+		evaluator.SetRuleset(&Ruleset{Environment: body.Flag.Environment, Flags: []FeatureFlag{body.Flag}})
+		result := SimulationResult{Evaluations: []SimulatedEvaluation{}}
+		for _, evalCtx := range body.Contexts {
+			d := evaluator.Evaluate(body.Flag.Name, evalCtx, false)
+			result.Evaluations = append(result.Evaluations, SimulatedEvaluation{Context: evalCtx, Value: d.Value, Reason: string(d.Reason), MatchedRule: d.RuleID})
+		}
+		writeJSON(w, result)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSimulateDraft(t *testing.T) {
+	fake := newFakeServer(t)
+	live := fake.addFlag(FeatureFlag{Name: "checkout", Environment: "production", IsActive: false})
+	var drafts []string
+	client := NewClient(newSimulationServer(t, fake, &drafts).URL, "key", nil)
+	ctx := context.Background()
+
+	draft := live
+	draft.IsActive = true
+	draft.Rules = []FlagRule{{ID: "beta", Conditions: []TargetingCondition{{Attribute: "plan", Operator: OpEquals, Value: "beta"}}}}
+	draft.Rollout = &PercentageRollout{Percentage: 0}
+	contexts := make([]EvaluationContext, 0, 10)
+	for i := 0; i < 10; i++ {
+		plan := "free"
+		if i < 3 {
+			plan = "beta"
+		}
+		contexts = append(contexts, EvaluationContext{Key: fmt.Sprintf("user-%d", i), Attributes: map[string]any{"plan": plan}})
+	}
+
+	result, err := client.Simulate(ctx, draft, contexts)
+	require.NoError(t, err)
+	require.Len(t, result.Evaluations, 10)
+	assert.Equal(t, contexts[0], result.Evaluations[0].Context)
+	assert.Equal(t, true, result.Evaluations[0].Value)
+	assert.Equal(t, "beta", result.Evaluations[0].MatchedRule)
+	assert.Equal(t, false, result.Evaluations[9].Value)
+	assert.Empty(t, result.Evaluations[9].MatchedRule)
+
+	assert.Equal(t, map[string]int{"true": 3, "false": 7}, result.Distribution())
+	assert.Equal(t, []DistributionEntry{
+		{Value: "false", Count: 7, Percentage: 70},
+		{Value: "true", Count: 3, Percentage: 30},
+	}, result.Summary())
+
+	got, err := client.GetFeatureFlag(ctx, live.ID)
+	require.NoError(t, err)
+	assert.Equal(t, live, *got, "simulating a draft does not save it")
+	assert.Empty(t, fake.writes())
+}
+
+func TestSimulateNamespace(t *testing.T) {
+	fake := newFakeServer(t)
+	var drafts []string
+	client := NewClient(newSimulationServer(t, fake, &drafts).URL, "key", nil, WithNamespace("web"))
+
+	_, err := client.Simulate(context.Background(), FeatureFlag{Name: "checkout", IsActive: true}, []EvaluationContext{{Key: "user-1"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web.checkout"}, drafts, "the draft's name is qualified by the namespace")
+}
+
+func TestSimulateValidate(t *testing.T) {
+	var drafts []string
+	client := NewClient(newSimulationServer(t, newFakeServer(t), &drafts).URL, "key", nil)
+	ctx := context.Background()
+	draft := FeatureFlag{Name: "checkout", IsActive: true}
+
+	_, err := client.Simulate(ctx, draft, nil)
+	assert.EqualError(t, err, "invalid simulation: no contexts")
+	_, err = client.Simulate(ctx, draft, []EvaluationContext{{Key: "user-1"}, {}})
+	assert.ErrorIs(t, err, ErrInvalidContext)
+	assert.ErrorContains(t, err, "invalid simulation context 1")
+	draft.TrafficAllocation = &TrafficAllocation{Percentage: 150}
+	_, err = client.Simulate(ctx, draft, []EvaluationContext{{Key: "user-1"}})
+	assert.ErrorContains(t, err, "invalid traffic allocation 150")
+	assert.Empty(t, drafts, "invalid simulations are not sent")
+}
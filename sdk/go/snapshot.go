@@ -0,0 +1,82 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// LoadSnapshot swaps in the ruleset of the evaluator's SnapshotFile, the last
+// one synced before a restart. It returns an error wrapping os.ErrNotExist
+// when there is no snapshot yet.
+func (e *Evaluator) LoadSnapshot(ctx context.Context) error {
+	if e.opts.SnapshotFile == "" {
+		return fmt.Errorf("%w: no snapshot file is configured", ErrEvaluatorNotReady)
+	}
+	ruleset, err := readSnapshot(e.opts.SnapshotFile)
+	if err != nil {
+		return err
+	}
+	if ruleset.Environment != e.opts.Environment {
+		return fmt.Errorf("failed to load snapshot %s: it holds environment %s, not %s", e.opts.SnapshotFile, ruleset.Environment, e.opts.Environment)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.ruleset.Store(ruleset.index())
+	return nil
+}
+
+// SaveSnapshot writes the ruleset currently used for evaluations to the
+// evaluator's SnapshotFile, with the flag changes applied from the stream
+// since the last sync, such as before a shutdown
+func (e *Evaluator) SaveSnapshot() error {
+	if e.opts.SnapshotFile == "" {
+		return errors.New("failed to write snapshot: no snapshot file is configured")
+	}
+	ruleset := e.Ruleset()
+	if ruleset == nil {
+		return fmt.Errorf("%w: there is no ruleset to snapshot", ErrEvaluatorNotReady)
+	}
+	return writeSnapshot(e.opts.SnapshotFile, ruleset)
+}
+
+// readSnapshot reads a snapshot written by writeSnapshot. Unlike LoadOfflineFlags
+// it keeps flag fields unknown to this SDK, which a newer server may send.
+func readSnapshot(path string) (*Ruleset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	var f OfflineFlags
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot %s: %w", path, err)
+	}
+	if f.APIVersion != ManifestAPIVersionV1 || f.Kind != OfflineKind {
+		return nil, fmt.Errorf("failed to decode snapshot %s: not a %s offline flag file", path, ManifestAPIVersionV1)
+	}
+	return f.Ruleset(), nil
+}
+
+// writeSnapshot writes a ruleset to path as an offline flag file. The file is
+// replaced atomically, so a crash never leaves a partial snapshot behind.
+func writeSnapshot(path string, ruleset *Ruleset) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := NewOfflineFlags(ruleset).Write(tmp); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package matrixflag
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	source := writeOfflineFile(t, &Ruleset{
+		Environment: "production",
+		Flags:       []FeatureFlag{{ID: 1, Name: "checkout", Environment: "production", IsActive: true}},
+	})
+	snapshot := filepath.Join(t.TempDir(), "snapshot.json")
+	synced := NewEvaluator(NewClient("", "", &Config{OfflineFile: source}), EvaluatorOptions{Environment: "production", SnapshotFile: snapshot})
+	require.NoError(t, synced.Sync(context.Background()))
+
+	// The API is down after the restart
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	restarted := NewEvaluator(New("key", WithBaseURL(srv.URL), WithRetries(0, 0, 0)), EvaluatorOptions{Environment: "production", SnapshotFile: snapshot})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	restarted.Run(ctx)
+
+	require.True(t, restarted.Ready(), "the evaluator starts from the snapshot")
+	assert.True(t, restarted.EvaluateBool("checkout", NewContext("user-1"), false).Value)
+}
+
+func TestSnapshotKeepsUnknownFields(t *testing.T) {
+	snapshot := filepath.Join(t.TempDir(), "snapshot.json")
+	require.NoError(t, os.WriteFile(snapshot, []byte(`{
+		"apiVersion": "matrixflag.io/v1",
+		"kind": "OfflineFlags",
+		"environment": "production",
+		"flags": [{"id": 1, "name": "checkout", "is_active": true, "future_field": 1}]
+	}`), 0o600))
+	evaluator := NewEvaluator(New("key"), EvaluatorOptions{Environment: "production", SnapshotFile: snapshot})
+	require.NoError(t, evaluator.LoadSnapshot(context.Background()))
+	assert.True(t, evaluator.EvaluateBool("checkout", NewContext("user-1"), false).Value)
+}
+
+func TestLoadSnapshotErrors(t *testing.T) {
+	dir := t.TempDir()
+	evaluator := NewEvaluator(New("key"), EvaluatorOptions{Environment: "production", SnapshotFile: filepath.Join(dir, "missing.json")})
+	assert.ErrorIs(t, evaluator.LoadSnapshot(context.Background()), os.ErrNotExist)
+
+	staging := writeOfflineFile(t, &Ruleset{Environment: "staging"})
+	evaluator = NewEvaluator(New("key"), EvaluatorOptions{Environment: "production", SnapshotFile: staging})
+	assert.ErrorContains(t, evaluator.LoadSnapshot(context.Background()), "holds environment staging")
+	assert.False(t, evaluator.Ready())
+
+	evaluator = NewEvaluator(New("key"), EvaluatorOptions{Environment: "production"})
+	assert.ErrorIs(t, evaluator.LoadSnapshot(context.Background()), ErrEvaluatorNotReady)
+}
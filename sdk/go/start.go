@@ -0,0 +1,219 @@
+package matrixflag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// NotReadyBehavior decides what the evaluations of a started client serve
+// before it has loaded its first ruleset
+type NotReadyBehavior string
+
+// Behaviors before readiness
+const (
+	// NotReadyError fails evaluations with an error wrapping
+	// ErrEvaluatorNotReady, serving the default value with the NOT_READY error
+	// code, so callers see that the flags were not evaluated
+	NotReadyError NotReadyBehavior = "error"
+	// NotReadyDefaults serves the default value without an error; the detail
+	// still carries the NOT_READY error code
+	NotReadyDefaults NotReadyBehavior = "defaults"
+)
+
+// StartOptions configures the local evaluation of a client started with Start
+type StartOptions struct {
+	// PollInterval is the average time between ruleset polls, DefaultSyncInterval by default
+	PollInterval time.Duration
+	// Stream applies flag changes pushed by the server between polls, see Client.StreamFlags
+	Stream bool
+	// Store keeps the loaded ruleset, a new MemoryStore by default. A client
+	// started with a shared or persistent store is ready as soon as the store
+	// holds a ruleset.
+	Store FlagStore
+	// SnapshotFile is the path of a last-known-good snapshot, see
+	// EvaluatorOptions.SnapshotFile: every polled ruleset and, on Close, the
+	// latest one are written there, and Start loads it when the store is empty
+	SnapshotFile string
+	// NotReady decides what evaluations serve before the first ruleset is
+	// loaded, NotReadyError by default
+	NotReady NotReadyBehavior
+	// OnStatusChange is called when the state of the polling changes, see PollingOptions
+	OnStatusChange func(DataSourceStatus)
+}
+
+// WithStartOptions configures the local evaluation of the client once Start is called
+func WithStartOptions(opts StartOptions) ClientOption {
+	return func(c *Client) {
+		c.startOpts = opts
+	}
+}
+
+// localEvaluation is the local evaluation of a started client
+type localEvaluation struct {
+	evaluator *Evaluator
+	processor *PollingProcessor
+	notReady  NotReadyBehavior
+	// ready is closed once the evaluator holds a full ruleset
+	ready     chan struct{}
+	readyOnce sync.Once
+	// stop ends the polling and the stream
+	stop context.CancelFunc
+}
+
+// markReady records that the evaluator holds a full ruleset
+func (l *localEvaluation) markReady() {
+	l.readyOnce.Do(func() { close(l.ready) })
+}
+
+// Start switches the client to local evaluation: it polls the ruleset of the
+// configured environment in the background, and with StartOptions.Stream
+// applies the changes pushed in between, until ctx is canceled or the client
+// is closed. The flag
+// evaluations of the client, such as Evaluate, AllFlagsState and
+// DebugEvaluate, are then served from the loaded ruleset without a request
+// per call; before the first ruleset is loaded they serve their default value
+// as set by StartOptions.NotReady. Start returns right away, see WaitForReady.
+// It fails without an environment, in offline mode, when already started or
+// once closed.
+func (c *Client) Start(ctx context.Context, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if c.config.Environment == "" {
+		return errors.New("starting the client requires an environment")
+	}
+	if c.isOffline() {
+		return fmt.Errorf("%w: the client evaluates the offline flag file", ErrOffline)
+	}
+	if err := c.closedError(); err != nil {
+		return err
+	}
+	opts := c.startOpts
+	if opts.Store == nil {
+		opts.Store = NewMemoryStore()
+	}
+	if opts.NotReady == "" {
+		opts.NotReady = NotReadyError
+	}
+	ctx, stop := context.WithCancel(ctx)
+	local := &localEvaluation{
+		evaluator: NewEvaluator(c, EvaluatorOptions{
+			Environment:  c.config.Environment,
+			Store:        opts.Store,
+			SnapshotFile: opts.SnapshotFile,
+		}),
+		notReady: opts.NotReady,
+		ready:    make(chan struct{}),
+		stop:     stop,
+	}
+	local.processor = NewPollingProcessor(c, PollingOptions{
+		Environment: c.config.Environment,
+		Interval:    opts.PollInterval,
+		Store:       opts.Store,
+		OnUpdate: func(ruleset *Ruleset) {
+			local.evaluator.SetRuleset(ruleset)
+			local.markReady()
+			if opts.SnapshotFile != "" {
+				if err := writeSnapshot(opts.SnapshotFile, ruleset); err != nil {
+					c.Logger().Error("failed to write snapshot", slog.Any("error", err))
+				}
+			}
+			if err := local.evaluator.FlushDebugEvents(ctx); err != nil && ctx.Err() == nil {
+				c.Logger().Error("failed to send debug events", slog.Any("error", err))
+			}
+		},
+		OnStatusChange: opts.OnStatusChange,
+	})
+	if !c.local.CompareAndSwap(nil, local) {
+		stop()
+		return errors.New("the client is already started")
+	}
+
+	err := local.evaluator.Load(ctx)
+	if errors.Is(err, ErrEvaluatorNotReady) && opts.SnapshotFile != "" {
+		if err = local.evaluator.LoadSnapshot(ctx); errors.Is(err, os.ErrNotExist) {
+			err = fmt.Errorf("%w: there is no snapshot yet", ErrEvaluatorNotReady)
+		}
+	}
+	if err == nil {
+		local.markReady()
+	} else if !errors.Is(err, ErrEvaluatorNotReady) {
+		c.Logger().Error("failed to load ruleset", slog.Any("error", err))
+	}
+	go local.processor.Run(ctx)
+	if opts.Stream {
+		go local.evaluator.runStream(ctx)
+	}
+	return nil
+}
+
+// WaitForReady blocks until the client started with Start has loaded its
+// first ruleset, waiting at most timeout unless it is zero. It returns an error
+// wrapping ErrEvaluatorNotReady, and the error of the latest poll, if any, when
+// the wait ends first or the client was not started.
+func (c *Client) WaitForReady(ctx context.Context, timeout time.Duration, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	local := c.local.Load()
+	if local == nil {
+		return fmt.Errorf("%w: the client was not started", ErrEvaluatorNotReady)
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	select {
+	case <-local.ready:
+		return nil
+	case <-ctx.Done():
+		select {
+		case <-local.ready:
+			return nil
+		default:
+		}
+		return local.processor.notInitialized(ctx.Err())
+	case <-local.processor.stopped:
+		return local.processor.notInitialized(errors.New("the client stopped"))
+	}
+}
+
+// DataSourceStatus returns the status of the polling of a client started
+// with Start, DataSourceOff when it was not started
+func (c *Client) DataSourceStatus() DataSourceStatus {
+	local := c.local.Load()
+	if local == nil {
+		return DataSourceStatus{State: DataSourceOff}
+	}
+	return local.processor.Status()
+}
+
+// localRuleset returns the ruleset flags are evaluated from locally: that of
+// the offline flag file in offline mode, or the one loaded by Start. ok is
+// false when flags are evaluated by the server.
+func (c *Client) localRuleset() (ix *indexedRuleset, ok bool, err error) {
+	if c.isOffline() {
+		ix, err := c.offlineRuleset()
+		return ix, true, err
+	}
+	local := c.local.Load()
+	if local == nil {
+		return nil, false, nil
+	}
+	if ix := local.evaluator.ruleset.Load(); ix != nil {
+		return ix, true, nil
+	}
+	return nil, true, fmt.Errorf("%w: the client has not loaded the ruleset yet", ErrEvaluatorNotReady)
+}
+
+// evaluateStarted evaluates a flag from the ruleset loaded by Start, applying
+// the behavior before readiness
+func (c *Client) evaluateStarted(local *localEvaluation, flagKey string, evalCtx EvaluationContext, defaultValue any) EvaluationDetail[any] {
+	d, _ := local.evaluator.evaluate(flagKey, evalCtx, defaultValue)
+	if d.ErrorCode == ErrorNotReady && local.notReady == NotReadyDefaults {
+		d.Err = nil
+	}
+	return d
+}
@@ -0,0 +1,82 @@
+package matrixflag
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartEvaluatesLocally(t *testing.T) {
+	srv := newPollingServer(t, FeatureFlag{ID: 1, Name: "checkout", Environment: "production", IsActive: true})
+	client := NewClient(srv.URL, "key", nil, WithEnvironment("production"), WithRetries(0, 0, 0))
+	assert.Equal(t, DataSourceOff, client.DataSourceStatus().State)
+	assert.ErrorIs(t, client.WaitForReady(context.Background(), 0), ErrEvaluatorNotReady)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, client.Start(ctx))
+	assert.ErrorContains(t, client.Start(ctx), "already started")
+	require.NoError(t, client.WaitForReady(ctx, 5*time.Second))
+	assert.Equal(t, DataSourceValid, client.DataSourceStatus().State)
+
+	// the fake server has no evaluation endpoint, so these would fail remotely
+	d := client.Evaluate(ctx, "checkout", EvaluationContext{Key: "user-1"}, false)
+	require.NoError(t, d.Err)
+	assert.Equal(t, true, d.Value)
+	state, err := client.AllFlagsState(ctx, EvaluationContext{Key: "user-1"}, FlagsStateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, true, state["checkout"].Value)
+	trace, err := client.DebugEvaluate(ctx, "checkout", EvaluationContext{Key: "user-1"})
+	require.NoError(t, err)
+	assert.Equal(t, true, trace.Detail.Value)
+	assert.ErrorIs(t, client.Evaluate(ctx, "missing", EvaluationContext{Key: "user-1"}, false).Err, ErrFlagNotFound)
+}
+
+func TestStartNotReady(t *testing.T) {
+	srv := newPollingServer(t, FeatureFlag{ID: 1, Name: "checkout", Environment: "production", IsActive: true})
+	srv.setDown(true)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := NewClient(srv.URL, "key", nil, WithEnvironment("production"), WithRetries(0, 0, 0))
+	require.NoError(t, client.Start(ctx))
+	d := client.Evaluate(ctx, "checkout", EvaluationContext{Key: "user-1"}, false)
+	assert.ErrorIs(t, d.Err, ErrEvaluatorNotReady)
+	assert.Equal(t, ErrorNotReady, d.ErrorCode)
+	assert.Equal(t, false, d.Value)
+	err := client.WaitForReady(ctx, 10*time.Millisecond)
+	assert.ErrorIs(t, err, ErrEvaluatorNotReady)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "503")
+
+	client = NewClient(srv.URL, "key", nil, WithEnvironment("production"), WithRetries(0, 0, 0),
+		WithStartOptions(StartOptions{NotReady: NotReadyDefaults}))
+	require.NoError(t, client.Start(ctx))
+	d = client.Evaluate(ctx, "checkout", EvaluationContext{Key: "user-1"}, false)
+	assert.NoError(t, d.Err, "defaults are served without an error")
+	assert.Equal(t, ErrorNotReady, d.ErrorCode)
+	assert.Equal(t, false, d.Value)
+}
+
+func TestStartFromStore(t *testing.T) {
+	srv := newPollingServer(t, FeatureFlag{ID: 1, Name: "checkout", Environment: "production", IsActive: true})
+	srv.setDown(true)
+	store := NewMemoryStore()
+	require.NoError(t, store.Init(context.Background(), &Ruleset{Flags: []FeatureFlag{{ID: 1, Name: "checkout", IsActive: true}}}))
+	client := NewClient(srv.URL, "key", nil, WithEnvironment("production"), WithRetries(0, 0, 0),
+		WithStartOptions(StartOptions{Store: store}))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	require.NoError(t, client.Start(ctx))
+	require.NoError(t, client.WaitForReady(ctx, 0), "a stored ruleset makes the client ready")
+	assert.Equal(t, true, client.Evaluate(ctx, "checkout", EvaluationContext{Key: "user-1"}, false).Value)
+}
+
+func TestStartRequiresEnvironment(t *testing.T) {
+	client := NewClient("http://localhost", "key", nil)
+	assert.ErrorContains(t, client.Start(context.Background()), "requires an environment")
+}
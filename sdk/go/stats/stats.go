@@ -0,0 +1,242 @@
+// Package stats provides statistics helpers for reading experiment results
+// correctly without a data platform: a sample ratio mismatch check, an
+// always-valid sequential test, and minimum detectable effect and sample size
+// calculators.
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+// SRMThreshold is the p-value below which a sample ratio mismatch is reported
+const SRMThreshold = 0.001
+
+// Variant holds the observed statistics of one variant for a metric
+type Variant struct {
+	Name     string
+	Users    int
+	Mean     float64
+	Variance float64
+}
+
+// FromResults extracts the statistics of metric from experiment results. When
+// the server reports no variance the metric is treated as a conversion rate
+// and the Bernoulli variance Mean*(1-Mean) is used.
+func FromResults(results []matrixflag.ExperimentResult, metric string) ([]Variant, error) {
+	variants := make([]Variant, 0, len(results))
+	for _, r := range results {
+		m, ok := r.Metrics[metric]
+		if !ok {
+			return nil, fmt.Errorf("variant %s has no results for metric %s", r.VariantName, metric)
+		}
+		v := Variant{Name: r.VariantName, Users: r.TotalUsers, Mean: m["value"]}
+		if variance, ok := m["variance"]; ok {
+			v.Variance = variance
+		} else if sd, ok := m["std_dev"]; ok {
+			v.Variance = sd * sd
+		} else {
+			v.Variance = v.Mean * (1 - v.Mean)
+		}
+		variants = append(variants, v)
+	}
+	return variants, nil
+}
+
+// SRMResult is the outcome of a sample ratio mismatch check
+type SRMResult struct {
+	ChiSquare float64
+	PValue    float64
+	// Mismatch is true when the observed split is very unlikely under the expected weights,
+	// which usually means broken assignment or logging and invalidates the results
+	Mismatch bool
+}
+
+// SampleRatioMismatch runs a chi-square goodness of fit test of the observed
+// user counts against the expected variant weights. Weights need not sum to one.
+func SampleRatioMismatch(observed []int, weights []float64) (SRMResult, error) {
+	if len(observed) != len(weights) || len(observed) < 2 {
+		return SRMResult{}, errors.New("observed counts and weights must have the same length of at least 2")
+	}
+	total, weightSum := 0.0, 0.0
+	for i := range observed {
+		if observed[i] < 0 || weights[i] <= 0 {
+			return SRMResult{}, errors.New("counts must not be negative and weights must be positive")
+		}
+		total += float64(observed[i])
+		weightSum += weights[i]
+	}
+	if total == 0 {
+		return SRMResult{PValue: 1}, nil
+	}
+
+	chi := 0.0
+	for i := range observed {
+		expected := total * weights[i] / weightSum
+		d := float64(observed[i]) - expected
+		chi += d * d / expected
+	}
+	p := chiSquareSurvival(chi, float64(len(observed)-1))
+	return SRMResult{ChiSquare: chi, PValue: p, Mismatch: p < SRMThreshold}, nil
+}
+
+// SequentialTest is an always-valid test of the difference between a treatment
+// and a control mean (mixture sequential probability ratio test). Its p-value
+// may be checked after every update without inflating the false positive rate,
+// so an experiment can be stopped as soon as it is significant.
+type SequentialTest struct {
+	// Tau2 is the variance of the normal mixing distribution over effect sizes,
+	// roughly the square of the effect size considered plausible
+	Tau2 float64
+
+	pValue float64
+}
+
+// NewSequentialTest creates a sequential test with mixing variance tau2
+func NewSequentialTest(tau2 float64) *SequentialTest {
+	return &SequentialTest{Tau2: tau2, pValue: 1}
+}
+
+// SequentialResult is the state of a sequential test after an update
+type SequentialResult struct {
+	// Effect is the observed treatment minus control difference
+	Effect float64
+	// PValue is the always-valid p-value, non-increasing across updates
+	PValue float64
+	// CILow and CIHigh bound the always-valid confidence interval at the given alpha
+	CILow, CIHigh float64
+}
+
+// Update feeds the current cumulative statistics of control and treatment into the test
+func (t *SequentialTest) Update(control, treatment Variant, alpha float64) (SequentialResult, error) {
+	if control.Users == 0 || treatment.Users == 0 {
+		return SequentialResult{PValue: t.pValue}, nil
+	}
+	if t.Tau2 <= 0 || alpha <= 0 || alpha >= 1 {
+		return SequentialResult{}, errors.New("tau2 must be positive and alpha within (0, 1)")
+	}
+
+	v := control.Variance/float64(control.Users) + treatment.Variance/float64(treatment.Users)
+	d := treatment.Mean - control.Mean
+	if v <= 0 {
+		return SequentialResult{}, errors.New("variance must be positive")
+	}
+
+	// Likelihood ratio of the normal mixture against the null hypothesis of no effect
+	logLambda := 0.5*math.Log(v/(v+t.Tau2)) + t.Tau2*d*d/(2*v*(v+t.Tau2))
+	p := math.Min(1, math.Exp(-logLambda))
+	if p < t.pValue {
+		t.pValue = p
+	}
+
+	radius := math.Sqrt(v * (v + t.Tau2) / t.Tau2 * (math.Log((v+t.Tau2)/v) - 2*math.Log(alpha)))
+	return SequentialResult{
+		Effect: d,
+		PValue: t.pValue,
+		CILow:  d - radius,
+		CIHigh: d + radius,
+	}, nil
+}
+
+// MinimumDetectableEffect returns the smallest absolute difference in conversion
+// rate detectable with the given baseline rate, users per variant, two-sided
+// significance level alpha and power
+func MinimumDetectableEffect(baseline float64, usersPerVariant int, alpha, power float64) (float64, error) {
+	if err := checkDesign(baseline, alpha, power); err != nil {
+		return 0, err
+	}
+	if usersPerVariant <= 0 {
+		return 0, errors.New("users per variant must be positive")
+	}
+	z := normalQuantile(1-alpha/2) + normalQuantile(power)
+	return z * math.Sqrt(2*baseline*(1-baseline)/float64(usersPerVariant)), nil
+}
+
+// SampleSize returns the users needed per variant to detect an absolute
+// difference mde in conversion rate from the baseline rate
+func SampleSize(baseline, mde, alpha, power float64) (int, error) {
+	if err := checkDesign(baseline, alpha, power); err != nil {
+		return 0, err
+	}
+	if mde <= 0 {
+		return 0, errors.New("minimum detectable effect must be positive")
+	}
+	z := normalQuantile(1-alpha/2) + normalQuantile(power)
+	return int(math.Ceil(2 * baseline * (1 - baseline) * z * z / (mde * mde))), nil
+}
+
+// checkDesign validates experiment design parameters
+func checkDesign(baseline, alpha, power float64) error {
+	if baseline <= 0 || baseline >= 1 {
+		return errors.New("baseline rate must be within (0, 1)")
+	}
+	if alpha <= 0 || alpha >= 1 || power <= 0 || power >= 1 {
+		return errors.New("alpha and power must be within (0, 1)")
+	}
+	return nil
+}
+
+// normalQuantile returns the quantile function of the standard normal distribution
+func normalQuantile(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
+
+// chiSquareSurvival returns P(X > x) for a chi-square distribution with k degrees of freedom
+func chiSquareSurvival(x, k float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	return upperIncompleteGamma(k/2, x/2)
+}
+
+// upperIncompleteGamma returns the regularized upper incomplete gamma function Q(a, x)
+func upperIncompleteGamma(a, x float64) float64 {
+	const (
+		maxIter = 500
+		eps     = 1e-14
+		tiny    = 1e-300
+	)
+	lgamma, _ := math.Lgamma(a)
+	prefix := math.Exp(-x + a*math.Log(x) - lgamma)
+
+	if x < a+1 {
+		// Series expansion of the lower function P(a, x)
+		sum, term := 1/a, 1/a
+		for n := 1; n < maxIter; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*eps {
+				break
+			}
+		}
+		return math.Max(0, 1-sum*prefix)
+	}
+
+	// Continued fraction for Q(a, x), modified Lentz's method
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	h := d
+	for n := 1; n < maxIter; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < eps {
+			break
+		}
+	}
+	return prefix * h
+}
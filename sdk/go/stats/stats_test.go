@@ -0,0 +1,105 @@
+package stats
+
+import (
+	"math"
+	"testing"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleRatioMismatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		observed []int
+		weights  []float64
+		chi      float64
+		p        float64
+		mismatch bool
+	}{
+		{"even split", []int{5000, 5000}, []float64{1, 1}, 0, 1, false},
+		{"no users", []int{0, 0}, []float64{1, 1}, 0, 1, false},
+		{"skewed split", []int{5200, 4800}, []float64{1, 1}, 16, math.Erfc(math.Sqrt(8)), true},
+		{"expected skew", []int{7500, 2500}, []float64{3, 1}, 0, 1, false},
+		{"three variants", []int{1100, 1000, 900}, []float64{1, 1, 1}, 20, math.Exp(-10), true},
+		{"small deviation", []int{505, 495}, []float64{1, 1}, 0.1, math.Erfc(math.Sqrt(0.05)), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := SampleRatioMismatch(tt.observed, tt.weights)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.chi, r.ChiSquare, 1e-9)
+			assert.InEpsilon(t, tt.p, r.PValue, 1e-6)
+			assert.Equal(t, tt.mismatch, r.Mismatch)
+		})
+	}
+
+	for _, bad := range [][2]any{
+		{[]int{1}, []float64{1}},
+		{[]int{1, 2}, []float64{1}},
+		{[]int{-1, 2}, []float64{1, 1}},
+		{[]int{1, 2}, []float64{0, 1}},
+	} {
+		_, err := SampleRatioMismatch(bad[0].([]int), bad[1].([]float64))
+		assert.Error(t, err)
+	}
+}
+
+func TestSequentialTest(t *testing.T) {
+	test := NewSequentialTest(0.0001)
+	r, err := test.Update(Variant{}, Variant{}, 0.05)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, r.PValue, "no data gives no evidence")
+
+	last := 1.0
+	for _, users := range []int{1000, 5000, 20000, 50000} {
+		control := Variant{Users: users, Mean: 0.10, Variance: 0.09}
+		treatment := Variant{Users: users, Mean: 0.11, Variance: 0.0979}
+		r, err := test.Update(control, treatment, 0.05)
+		require.NoError(t, err)
+		assert.InDelta(t, 0.01, r.Effect, 1e-12)
+		assert.LessOrEqual(t, r.PValue, last, "the p-value never increases")
+		assert.Less(t, r.CILow, r.Effect)
+		assert.Greater(t, r.CIHigh, r.Effect)
+		last = r.PValue
+	}
+	assert.Less(t, last, 0.05, "a real effect becomes significant")
+
+	_, err = test.Update(Variant{Users: 1, Variance: 1}, Variant{Users: 1, Variance: 1}, 1.5)
+	assert.Error(t, err)
+}
+
+func TestDesign(t *testing.T) {
+	n, err := SampleSize(0.1, 0.01, 0.05, 0.8)
+	require.NoError(t, err)
+	assert.Equal(t, 14128, n)
+
+	mde, err := MinimumDetectableEffect(0.1, n, 0.05, 0.8)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.01, mde, 1e-5, "the sample size detects the effect it was computed for")
+
+	_, err = SampleSize(0, 0.01, 0.05, 0.8)
+	assert.Error(t, err)
+	_, err = SampleSize(0.1, 0, 0.05, 0.8)
+	assert.Error(t, err)
+	_, err = MinimumDetectableEffect(0.1, 0, 0.05, 0.8)
+	assert.Error(t, err)
+}
+
+func TestFromResults(t *testing.T) {
+	results := []matrixflag.ExperimentResult{
+		{VariantName: "control", TotalUsers: 100, Metrics: map[string]map[string]float64{"conversion": {"value": 0.2}}},
+		{VariantName: "treatment", TotalUsers: 120, Metrics: map[string]map[string]float64{"conversion": {"value": 0.25, "std_dev": 0.5}}},
+	}
+	variants, err := FromResults(results, "conversion")
+	require.NoError(t, err)
+	require.Len(t, variants, 2)
+	assert.InDelta(t, 0.16, variants[0].Variance, 1e-12, "conversion rates get the Bernoulli variance")
+	assert.InDelta(t, 0.25, variants[1].Variance, 1e-12, "the variance is the squared standard deviation")
+	assert.Equal(t, "treatment", variants[1].Name)
+	assert.Equal(t, 120, variants[1].Users)
+
+	_, err = FromResults(results, "revenue")
+	assert.Error(t, err)
+}
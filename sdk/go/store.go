@@ -0,0 +1,137 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// FlagStore persists the ruleset of an Evaluator, so evaluators can share flag
+// state and start from it without a cold fetch of the whole ruleset. The
+// in-memory store is the default; implementations backed by Redis or BoltDB
+// let several instances share one store and keep it across restarts.
+// Implementations must be safe for concurrent use; matrixflagtest.TestFlagStore
+// checks that an implementation behaves as described.
+type FlagStore interface {
+	// Init replaces the contents of the store with a full ruleset
+	Init(ctx context.Context, ruleset *Ruleset) error
+	// Get returns the flag with the given name, or an error wrapping ErrFlagNotFound
+	Get(ctx context.Context, name string) (*FeatureFlag, error)
+	// Upsert creates or replaces a flag, matched by name. It does nothing while
+	// the store has not been initialized, so All never returns a partial ruleset.
+	Upsert(ctx context.Context, flag FeatureFlag) error
+	// Delete removes the flag with the given name, if present
+	Delete(ctx context.Context, name string) error
+	// All returns the stored ruleset, or nil when the store has not been initialized
+	All(ctx context.Context) (*Ruleset, error)
+}
+
+// MemoryStore is a FlagStore keeping the ruleset in memory
+type MemoryStore struct {
+	mu      sync.RWMutex
+	ruleset *Ruleset
+}
+
+// NewMemoryStore creates an empty in-memory flag store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Init replaces the contents of the store with a copy of ruleset
+func (s *MemoryStore) Init(ctx context.Context, ruleset *Ruleset) error {
+	r := *ruleset
+	r.Flags = append([]FeatureFlag(nil), ruleset.Flags...)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ruleset = &r
+	return nil
+}
+
+// Get returns the flag with the given name
+func (s *MemoryStore) Get(ctx context.Context, name string) (*FeatureFlag, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.ruleset != nil {
+		for _, flag := range s.ruleset.Flags {
+			if flag.Name == name {
+				return &flag, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrFlagNotFound, name)
+}
+
+// Upsert creates or replaces a flag, matched by name. It does nothing while the store is empty.
+func (s *MemoryStore) Upsert(ctx context.Context, flag FeatureFlag) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ruleset != nil {
+		s.ruleset = s.ruleset.withFlag(flag)
+	}
+	return nil
+}
+
+// Delete removes the flag with the given name, if present
+func (s *MemoryStore) Delete(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ruleset != nil {
+		s.ruleset = s.ruleset.withoutFlag(name)
+	}
+	return nil
+}
+
+// All returns a copy of the stored ruleset, or nil when the store has not been initialized
+func (s *MemoryStore) All(ctx context.Context) (*Ruleset, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.ruleset == nil {
+		return nil, nil
+	}
+	r := *s.ruleset
+	r.Flags = append([]FeatureFlag(nil), s.ruleset.Flags...)
+	return &r, nil
+}
+
+// clone returns a deep copy of the ruleset
+func (r *Ruleset) clone() (*Ruleset, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy ruleset: %w", err)
+	}
+	var copied Ruleset
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil, fmt.Errorf("failed to copy ruleset: %w", err)
+	}
+	return &copied, nil
+}
+
+// withFlag returns a copy of the ruleset with flag added, or replacing the flag of the same name
+func (r *Ruleset) withFlag(flag FeatureFlag) *Ruleset {
+	updated := *r
+	updated.Flags = make([]FeatureFlag, 0, len(r.Flags)+1)
+	replaced := false
+	for _, f := range r.Flags {
+		if f.Name == flag.Name {
+			f, replaced = flag, true
+		}
+		updated.Flags = append(updated.Flags, f)
+	}
+	if !replaced {
+		updated.Flags = append(updated.Flags, flag)
+	}
+	return &updated
+}
+
+// withoutFlag returns a copy of the ruleset without the flag of the given name
+func (r *Ruleset) withoutFlag(name string) *Ruleset {
+	updated := *r
+	updated.Flags = make([]FeatureFlag, 0, len(r.Flags))
+	for _, f := range r.Flags {
+		if f.Name != name {
+			updated.Flags = append(updated.Flags, f)
+		}
+	}
+	return &updated
+}
@@ -0,0 +1,12 @@
+package matrixflag_test
+
+import (
+	"testing"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/matrixflag/sdk/matrixflagtest"
+)
+
+func TestMemoryStore(t *testing.T) {
+	matrixflagtest.TestFlagStore(t, func() matrixflag.FlagStore { return matrixflag.NewMemoryStore() })
+}
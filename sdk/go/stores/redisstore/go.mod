@@ -0,0 +1,16 @@
+module github.com/matrixflag/sdk/stores/redisstore
+
+go 1.21
+
+require (
+	github.com/google/uuid v1.4.0
+	github.com/matrixflag/sdk v0.0.0
+)
+
+require (
+	github.com/kr/text v0.2.0 // indirect
+	github.com/pelletier/go-toml/v2 v2.0.8 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/matrixflag/sdk => ../..
@@ -0,0 +1,213 @@
+// Package redisstore implements a Matrix Flag FlagStore on Redis, so
+// horizontally scaled services share one flag cache instead of each polling
+// the API. The store depends on a small Conn interface rather than on a Redis
+// client library; go-redis and other clients are adapted in a few lines.
+package redisstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	matrixflag "github.com/matrixflag/sdk"
+)
+
+// DefaultPrefix is the default prefix of the store's Redis key and channel
+const DefaultPrefix = "matrixflag:"
+
+// Conn is the set of Redis commands used by the store. With go-redis:
+//
+//	type conn struct{ *redis.Client }
+//
+//	func (c conn) Get(ctx context.Context, key string) ([]byte, error) {
+//		b, err := c.Client.Get(ctx, key).Bytes()
+//		if errors.Is(err, redis.Nil) {
+//			return nil, nil
+//		}
+//		return b, err
+//	}
+//
+//	func (c conn) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+//		return c.Client.Set(ctx, key, value, ttl).Err()
+//	}
+//
+//	func (c conn) Publish(ctx context.Context, channel string, message []byte) error {
+//		return c.Client.Publish(ctx, channel, message).Err()
+//	}
+type Conn interface {
+	// Get returns the value of key, or nil without an error when the key does not exist
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set sets the value of key, expiring after ttl unless ttl is zero
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Publish publishes a message on a pub/sub channel
+	Publish(ctx context.Context, channel string, message []byte) error
+}
+
+// Subscriber receives the messages of a pub/sub channel until ctx is canceled.
+// With go-redis, it forwards the payloads of client.Subscribe(ctx, channel).Channel().
+type Subscriber interface {
+	Subscribe(ctx context.Context, channel string) (<-chan []byte, error)
+}
+
+// Options configures a Store
+type Options struct {
+	// Prefix is prepended to the store's key and channel, DefaultPrefix by default.
+	// Stores of different environments must use different prefixes.
+	Prefix string
+	// TTL expires the stored ruleset unless it is written again in time, so a
+	// store that is no longer synced falls back to a cold fetch; zero keeps it
+	TTL time.Duration
+	// Invalidate publishes a message on every write, so other instances can reload with Listen
+	Invalidate bool
+}
+
+// Store is a FlagStore keeping the ruleset as one JSON value in Redis, so a
+// full sync replaces it atomically. Single flag writes read, modify and write
+// the ruleset; concurrent writers may race, and the next full sync resolves it.
+type Store struct {
+	conn Conn
+	opts Options
+	// id tells the store's own invalidation messages apart
+	id string
+}
+
+var _ matrixflag.FlagStore = (*Store)(nil)
+
+// New creates a Redis flag store
+func New(conn Conn, opts Options) *Store {
+	if opts.Prefix == "" {
+		opts.Prefix = DefaultPrefix
+	}
+	return &Store{conn: conn, opts: opts, id: uuid.NewString()}
+}
+
+// Key returns the Redis key holding the ruleset
+func (s *Store) Key() string {
+	return s.opts.Prefix + "ruleset"
+}
+
+// Channel returns the pub/sub channel of invalidation messages
+func (s *Store) Channel() string {
+	return s.opts.Prefix + "invalidate"
+}
+
+// Init implements matrixflag.FlagStore
+func (s *Store) Init(ctx context.Context, ruleset *matrixflag.Ruleset) error {
+	return s.write(ctx, ruleset)
+}
+
+// Get implements matrixflag.FlagStore
+func (s *Store) Get(ctx context.Context, name string) (*matrixflag.FeatureFlag, error) {
+	ruleset, err := s.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ruleset != nil {
+		for _, flag := range ruleset.Flags {
+			if flag.Name == name {
+				return &flag, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", matrixflag.ErrFlagNotFound, name)
+}
+
+// Upsert implements matrixflag.FlagStore. It does nothing while the store is empty.
+func (s *Store) Upsert(ctx context.Context, flag matrixflag.FeatureFlag) error {
+	return s.update(ctx, func(ruleset *matrixflag.Ruleset) {
+		for i := range ruleset.Flags {
+			if ruleset.Flags[i].Name == flag.Name {
+				ruleset.Flags[i] = flag
+				return
+			}
+		}
+		ruleset.Flags = append(ruleset.Flags, flag)
+	})
+}
+
+// Delete implements matrixflag.FlagStore
+func (s *Store) Delete(ctx context.Context, name string) error {
+	return s.update(ctx, func(ruleset *matrixflag.Ruleset) {
+		kept := ruleset.Flags[:0]
+		for _, flag := range ruleset.Flags {
+			if flag.Name != name {
+				kept = append(kept, flag)
+			}
+		}
+		ruleset.Flags = kept
+	})
+}
+
+// All implements matrixflag.FlagStore
+func (s *Store) All(ctx context.Context) (*matrixflag.Ruleset, error) {
+	value, err := s.conn.Get(ctx, s.Key())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", s.Key(), err)
+	}
+	if value == nil {
+		return nil, nil
+	}
+	var ruleset matrixflag.Ruleset
+	if err := json.Unmarshal(value, &ruleset); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", s.Key(), err)
+	}
+	return &ruleset, nil
+}
+
+// Listen calls fn whenever another store publishes a write on the store's
+// channel, until ctx is canceled. Evaluators that do not sync themselves use it
+// to reload the shared ruleset:
+//
+//	store.Listen(ctx, sub, func() { evaluator.Load(ctx) })
+func (s *Store) Listen(ctx context.Context, sub Subscriber, fn func()) error {
+	messages, err := sub.Subscribe(ctx, s.Channel())
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", s.Channel(), err)
+	}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				if !bytes.Equal(msg, []byte(s.id)) {
+					fn()
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// update applies fn to the stored ruleset and writes it back
+func (s *Store) update(ctx context.Context, fn func(*matrixflag.Ruleset)) error {
+	ruleset, err := s.All(ctx)
+	if err != nil || ruleset == nil {
+		return err
+	}
+	fn(ruleset)
+	return s.write(ctx, ruleset)
+}
+
+// write stores the ruleset and publishes an invalidation message when enabled
+func (s *Store) write(ctx context.Context, ruleset *matrixflag.Ruleset) error {
+	value, err := json.Marshal(ruleset)
+	if err != nil {
+		return fmt.Errorf("failed to encode ruleset: %w", err)
+	}
+	if err := s.conn.Set(ctx, s.Key(), value, s.opts.TTL); err != nil {
+		return fmt.Errorf("failed to write %s: %w", s.Key(), err)
+	}
+	if s.opts.Invalidate {
+		if err := s.conn.Publish(ctx, s.Channel(), []byte(s.id)); err != nil {
+			return fmt.Errorf("failed to publish to %s: %w", s.Channel(), err)
+		}
+	}
+	return nil
+}
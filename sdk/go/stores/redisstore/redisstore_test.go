@@ -0,0 +1,44 @@
+package redisstore
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	matrixflag "github.com/matrixflag/sdk"
+	"github.com/matrixflag/sdk/matrixflagtest"
+)
+
+// memConn is a Conn keeping values in memory
+type memConn struct {
+	mu        sync.Mutex
+	values    map[string][]byte
+	published []string
+}
+
+func (c *memConn) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[key], nil
+}
+
+func (c *memConn) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] = value
+	return nil
+}
+
+func (c *memConn) Publish(ctx context.Context, channel string, message []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.published = append(c.published, channel)
+	return nil
+}
+
+func TestStore(t *testing.T) {
+	matrixflagtest.TestFlagStore(t, func() matrixflag.FlagStore {
+		return New(&memConn{values: make(map[string][]byte)}, Options{Invalidate: true})
+	})
+}
@@ -0,0 +1,241 @@
+package matrixflag
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FlagToggled is the type of a stream event switching a flag's active state.
+// Watches report toggles as FlagUpdated changes, see FlagChange.Toggled.
+const FlagToggled FlagChangeType = "toggled"
+
+// FlagEvent is a flag change pushed by the server over a flag stream
+type FlagEvent struct {
+	// ID identifies the event; a reconnecting stream resumes after the last received ID
+	ID   string
+	Type FlagChangeType
+	// Flag is the flag after the change, or before it when it was deleted
+	Flag FeatureFlag
+}
+
+// StreamOptions configures the flag streams of a client
+type StreamOptions struct {
+	// Environment limits streams to one environment, the client's Environment by default
+	Environment string
+	// OnError is called when a stream breaks or carries an invalid event; the stream keeps running
+	OnError func(error)
+}
+
+// WithStreamOptions configures the flag streams opened by StreamFlags
+func WithStreamOptions(opts StreamOptions) ClientOption {
+	return func(c *Client) {
+		c.streamOpts = opts
+	}
+}
+
+// StreamFlags opens a Server-Sent Events connection to the API and sends every
+// flag created, updated, deleted or toggled on the returned channel until ctx is
+// canceled, when the channel is closed. An error opening the connection is
+// returned; afterwards a broken connection is reopened with the client's retry
+// delays, resuming after the last received event.
+func (c *Client) StreamFlags(ctx context.Context, reqOpts ...RequestOption) (<-chan FlagEvent, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	body, err := c.openStream(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	c.Metrics().StreamStatus(true)
+	events := make(chan FlagEvent)
+	go c.runStream(ctx, body, events)
+	return events, nil
+}
+
+// openStream connects to the flag stream, resuming after lastEventID when it is set
+func (c *Client) openStream(ctx context.Context, lastEventID string) (io.ReadCloser, error) {
+	req := request{
+		method:  "GET",
+		path:    "/api/v1/feature-flags/stream",
+		query:   map[string]string{},
+		headers: map[string]string{"Accept": "text/event-stream", "Cache-Control": "no-cache"},
+	}
+	if c.isOffline() {
+		return nil, c.offlineError(req)
+	}
+	if err := c.closedError(); err != nil {
+		return nil, err
+	}
+	env := c.streamOpts.Environment
+	if env == "" {
+		env = c.config.Environment
+	}
+	if env != "" {
+		req.query["environment"] = env
+	}
+	if lastEventID != "" {
+		req.headers["Last-Event-ID"] = lastEventID
+	}
+
+	// The stream stays open indefinitely, so it has no request timeout
+	httpReq, reqID, err := c.newHTTPRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.doer.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open flag stream %s: %w", reqID, err)
+	}
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		if id := resp.Header.Get(RequestIDHeader); id != "" {
+			reqID = id
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		return nil, responseError(resp.StatusCode, respBody, reqID)
+	}
+	return resp.Body, nil
+}
+
+// runStream reads events from body and reconnects when the stream breaks, until ctx is canceled
+func (c *Client) runStream(ctx context.Context, body io.ReadCloser, events chan<- FlagEvent) {
+	defer close(events)
+	defer c.Metrics().StreamStatus(false)
+	var lastEventID string
+	retryDelay := c.config.RetryDelay
+	for {
+		err := c.readStream(ctx, body, events, &lastEventID, &retryDelay)
+		body.Close()
+		if ctx.Err() != nil {
+			return
+		}
+		c.Metrics().StreamStatus(false)
+		if err == nil {
+			err = errors.New("flag stream closed by the server")
+		}
+		c.streamError(err)
+
+		// Reconnect with exponential backoff, never in a busy loop
+		delay := retryDelay
+		if delay <= 0 {
+			delay = time.Second
+		}
+		for {
+			if err := sleep(ctx, c.clock, delay); err != nil {
+				return
+			}
+			body, err = c.openStream(ctx, lastEventID)
+			if err == nil {
+				c.Logger().Info("flag stream reconnected", slog.String("last_event_id", lastEventID))
+				c.Metrics().StreamStatus(true)
+				break
+			}
+			if ctx.Err() != nil || errors.Is(err, ErrClientClosed) {
+				return
+			}
+			c.streamError(err)
+			delay *= 2
+			if delay > c.config.MaxRetryDelay {
+				delay = c.config.MaxRetryDelay
+			}
+		}
+	}
+}
+
+// readStream parses the event stream of body and sends its flag events until the stream ends
+func (c *Client) readStream(ctx context.Context, body io.Reader, events chan<- FlagEvent, lastEventID *string, retryDelay *time.Duration) error {
+	r := bufio.NewReader(body)
+	var eventType, id string
+	var data strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("failed to read flag stream: %w", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		// A blank line dispatches the event
+		if line == "" {
+			if id != "" {
+				*lastEventID = id
+			}
+			if data.Len() > 0 {
+				if event, ok := c.decodeStreamEvent(id, FlagChangeType(eventType), data.String()); ok {
+					select {
+					case events <- event:
+					case <-ctx.Done():
+						return nil
+					}
+				}
+			}
+			eventType, id = "", ""
+			data.Reset()
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			eventType = value
+		case "data":
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+		case "id":
+			id = value
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms >= 0 {
+				*retryDelay = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
+
+// decodeStreamEvent decodes the flag of a stream event. It reports false for
+// events that are not flag changes or concern flags of another namespace.
+func (c *Client) decodeStreamEvent(id string, eventType FlagChangeType, data string) (FlagEvent, bool) {
+	switch eventType {
+	case FlagCreated, FlagUpdated, FlagDeleted, FlagToggled:
+	default:
+		return FlagEvent{}, false
+	}
+
+	event := FlagEvent{ID: id, Type: eventType}
+	if err := json.Unmarshal([]byte(data), &event.Flag); err != nil {
+		c.streamError(fmt.Errorf("failed to unmarshal flag stream event %s: %w", id, err))
+		return FlagEvent{}, false
+	}
+	if err := c.checkUnknownFields(event.Flag); err != nil {
+		c.streamError(err)
+		return FlagEvent{}, false
+	}
+	if !c.unqualifyFlag(&event.Flag) {
+		return FlagEvent{}, false
+	}
+	return event, true
+}
+
+// streamError reports an error of a flag stream
+func (c *Client) streamError(err error) {
+	c.Logger().Warn("flag stream error", slog.Any("error", err))
+	if c.streamOpts.OnError != nil {
+		c.streamOpts.OnError(err)
+	}
+}
@@ -0,0 +1,148 @@
+package matrixflag
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The stress tests share one client between goroutines making every kind of
+// call, so `go test -race` catches unsynchronized state
+
+const (
+	stressGoroutines = 16
+	stressIterations = 25
+)
+
+// stress runs fn from stressGoroutines goroutines stressIterations times each
+func stress(fn func(g, i int)) {
+	var wg sync.WaitGroup
+	for g := 0; g < stressGoroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < stressIterations; i++ {
+				fn(g, i)
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestStressManagement(t *testing.T) {
+	fake := newFakeServer(t)
+	flag := fake.addFlag(FeatureFlag{Name: "checkout", Environment: "production"})
+	// report a rate limit on every response, so the client records it concurrently
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(RateLimitLimitHeader, "100000")
+		w.Header().Set(RateLimitRemainingHeader, "99999")
+		fake.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil,
+		WithEnvironment("production"),
+		WithRetries(0, 0, 0),
+		WithCircuitBreaker(BreakerOptions{}),
+		WithRateLimiter(100000, 1000),
+		WithResponseCache(CacheOptions{MaxEntries: 8}),
+		WithEvents(EventOptions{}),
+	)
+	ctx := context.Background()
+
+	stress(func(g, i int) {
+		var err error
+		switch i % 5 {
+		case 0:
+			_, err = client.ListFeatureFlags(ctx, FlagFilter{})
+		case 1:
+			_, err = client.GetFeatureFlag(ctx, flag.ID)
+		case 2:
+			_, err = client.ToggleFeatureFlag(ctx, flag.ID)
+		case 3:
+			_, err = client.CreateFeatureFlag(ctx, FeatureFlagCreate{Name: fmt.Sprintf("flag-%d-%d", g, i), Environment: "production"})
+		case 4:
+			err = client.Track(ctx, "checkout", EvaluationContext{Key: fmt.Sprintf("user-%d", g)}, 1, nil)
+			if err == nil {
+				err = client.FlushEvents(ctx)
+			}
+		}
+		assert.NoError(t, err)
+		client.RateLimit()
+		client.BreakerState()
+	})
+
+	limit, ok := client.RateLimit()
+	require.True(t, ok)
+	assert.Equal(t, 100000, limit.Limit)
+	assert.Equal(t, BreakerClosed, client.BreakerState())
+	assert.Len(t, fake.receivedEvents(), stressGoroutines*stressIterations/5)
+	require.NoError(t, client.Close())
+}
+
+func TestStressLocalEvaluation(t *testing.T) {
+	srv := newPollingServer(t, FeatureFlag{ID: 1, Name: "checkout", Environment: "production", IsActive: true})
+	client := NewClient(srv.URL, "key", nil,
+		WithEnvironment("production"),
+		WithRetries(0, 0, 0),
+		WithEvents(EventOptions{}),
+		WithStartOptions(StartOptions{PollInterval: time.Millisecond, Store: NewMemoryStore()}),
+	)
+	ctx := context.Background()
+	require.NoError(t, client.Start(ctx))
+	require.NoError(t, client.WaitForReady(ctx, 5*time.Second))
+
+	stress(func(g, i int) {
+		evalCtx := EvaluationContext{Key: fmt.Sprintf("user-%d-%d", g, i)}
+		switch i % 4 {
+		case 0:
+			d := client.Evaluate(ctx, "checkout", evalCtx, false)
+			assert.NoError(t, d.Err)
+			assert.Equal(t, true, d.Value)
+		case 1:
+			_, err := client.AllFlagsState(ctx, evalCtx, FlagsStateOptions{})
+			assert.NoError(t, err)
+		case 2:
+			_, err := client.DebugEvaluate(ctx, "checkout", evalCtx)
+			assert.NoError(t, err)
+		case 3:
+			assert.NoError(t, client.FlushEvents(ctx))
+		}
+		client.DataSourceStatus()
+	})
+
+	require.NoError(t, client.Close())
+	assert.Equal(t, DataSourceOff, client.DataSourceStatus().State)
+}
+
+func TestStressGetVariationRulesetChanges(t *testing.T) {
+	evaluator := NewEvaluator(NewClient("http://localhost", "key", nil), EvaluatorOptions{Environment: "production"})
+	with := &Ruleset{Environment: "production", Flags: []FeatureFlag{{ID: 1, Name: "checkout", IsActive: true,
+		Variations: []Variation{{Key: "control", Value: "A", Weight: 50}, {Key: "treatment", Value: "B", Weight: 50}}}}}
+	without := &Ruleset{Environment: "production"}
+	evaluator.SetRuleset(with)
+
+	stress(func(g, i int) {
+		// One goroutine keeps removing and restoring the flag while the others evaluate it
+		if g == 0 {
+			if i%2 == 0 {
+				evaluator.SetRuleset(without)
+			} else {
+				evaluator.SetRuleset(with)
+			}
+			return
+		}
+		v, err := evaluator.GetVariation("checkout", EvaluationContext{Key: fmt.Sprintf("user-%d-%d", g, i)})
+		if err != nil {
+			assert.True(t, IsNotFound(err), "a removed flag is not found: %v", err)
+			return
+		}
+		assert.Contains(t, []string{"control", "treatment"}, v.Key)
+	})
+}
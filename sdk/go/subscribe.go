@@ -0,0 +1,175 @@
+package matrixflag
+
+import (
+	"context"
+	"sync"
+)
+
+// FlagChangeFunc is called with a flag before and after a change. Old is the
+// zero FeatureFlag when the flag was created and new is the zero FeatureFlag
+// when it was deleted.
+type FlagChangeFunc func(old, new FeatureFlag)
+
+// Subscription is a handle to a flag change callback registered with
+// Subscribe, OnFlagChange or OnAnyChange
+type Subscription struct {
+	client *Client
+	key    string
+	id     uint64
+	once   sync.Once
+}
+
+// Unsubscribe stops calls of the subscription's callback. It is safe to call more than once.
+func (s *Subscription) Unsubscribe() {
+	s.once.Do(func() {
+		s.client.subs.remove(s.key, s.id)
+	})
+}
+
+// subscriptions holds the flag change callbacks of a client and the watch that drives them
+type subscriptions struct {
+	mu   sync.Mutex
+	next uint64
+	// handlers are keyed by flag name; the callbacks of OnAnyChange are under anyFlag
+	handlers map[string]map[uint64]FlagChangeFunc
+	cancel   context.CancelFunc
+	// stopped is set by Client.Close, after which no watch is started
+	stopped bool
+}
+
+// anyFlag is the handler key of callbacks subscribed to every flag. Flag names
+// cannot be empty, so it never clashes with one.
+const anyFlag = ""
+
+// WithSubscriptionOptions configures the watch driving subscription callbacks;
+// set Stream to drive them from the flag stream instead of polling. Its Keys
+// are ignored, as they follow the subscribed flags.
+func WithSubscriptionOptions(opts WatchOptions) ClientOption {
+	return func(c *Client) {
+		c.subscribeOpts = opts
+	}
+}
+
+// Subscribe calls fn for every change of the flag named key, in any environment,
+// until the returned subscription is unsubscribed. Changes are observed by a
+// watch that the client runs while it has subscriptions. Callbacks are called one
+// at a time from that watch's goroutine, and may subscribe or unsubscribe.
+func (c *Client) Subscribe(key string, fn FlagChangeFunc) *Subscription {
+	return c.subscribe(key, fn)
+}
+
+// OnFlagChange calls fn for every change of the flag named key, like Subscribe
+func (c *Client) OnFlagChange(key string, fn FlagChangeFunc) *Subscription {
+	return c.subscribe(key, fn)
+}
+
+// OnAnyChange calls fn for every change of any flag, until the returned
+// subscription is unsubscribed. It is driven by the same watch as Subscribe, so
+// applications can flush caches or reconnect on any toggle without their own
+// diffing loop.
+func (c *Client) OnAnyChange(fn FlagChangeFunc) *Subscription {
+	return c.subscribe(anyFlag, fn)
+}
+
+// subscribe registers a callback under key and starts the watch if it is not running
+func (c *Client) subscribe(key string, fn FlagChangeFunc) *Subscription {
+	s := &c.subs
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.handlers == nil {
+		s.handlers = make(map[string]map[uint64]FlagChangeFunc)
+	}
+	if s.handlers[key] == nil {
+		s.handlers[key] = make(map[uint64]FlagChangeFunc)
+	}
+	s.next++
+	s.handlers[key][s.next] = fn
+
+	if s.cancel == nil && !s.stopped {
+		var ctx context.Context
+		ctx, s.cancel = context.WithCancel(context.Background())
+		go c.runSubscriptions(ctx)
+	}
+	return &Subscription{client: c, key: key, id: s.next}
+}
+
+// remove unregisters a callback and stops the watch once no callback is left
+func (s *subscriptions) remove(key string, id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.handlers[key], id)
+	if len(s.handlers[key]) == 0 {
+		delete(s.handlers, key)
+	}
+	if len(s.handlers) == 0 && s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+}
+
+// stop stops the watch for good
+func (s *subscriptions) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopped = true
+	if s.cancel != nil {
+		s.cancel()
+		s.cancel = nil
+	}
+}
+
+// callbacks returns the callbacks subscribed to a flag
+func (s *subscriptions) callbacks(key string) []FlagChangeFunc {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fns := make([]FlagChangeFunc, 0, len(s.handlers[key]))
+	for _, fn := range s.handlers[key] {
+		fns = append(fns, fn)
+	}
+	return fns
+}
+
+// runSubscriptions watches flags and dispatches their changes until ctx is canceled
+func (c *Client) runSubscriptions(ctx context.Context) {
+	opts := c.subscribeOpts
+	opts.Keys = nil
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultWatchInterval
+	}
+
+	for {
+		changes, err := c.Watch(ctx, opts)
+		if err != nil {
+			if ctx.Err() == nil && opts.OnError != nil {
+				opts.OnError(err)
+			}
+			if sleep(ctx, c.clock, opts.Interval) != nil {
+				return
+			}
+			continue
+		}
+		for change := range changes {
+			var old, new FeatureFlag
+			if change.Old != nil {
+				old = *change.Old
+			}
+			if change.New != nil {
+				new = *change.New
+			}
+			fns := c.subs.callbacks(change.Flag().Name)
+			if change.Type == FlagUpdated && old.Name != new.Name {
+				// A renamed flag is reported to the subscribers of both names
+				fns = append(fns, c.subs.callbacks(old.Name)...)
+			}
+			fns = append(fns, c.subs.callbacks(anyFlag)...)
+			for _, fn := range fns {
+				fn(old, new)
+			}
+		}
+		return
+	}
+}
@@ -0,0 +1,40 @@
+package matrixflag
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriptionCallbacks(t *testing.T) {
+	srv := newFakeServer(t)
+	checkout := srv.addFlag(FeatureFlag{Name: "checkout", Environment: "production"})
+	search := srv.addFlag(FeatureFlag{Name: "search", Environment: "production"})
+	client := NewClient(srv.URL, "key", nil, WithSubscriptionOptions(WatchOptions{Interval: 10 * time.Millisecond}))
+
+	type call struct{ sub, flag string }
+	calls := make(chan call, 16)
+	one := client.OnFlagChange("checkout", func(old, new FeatureFlag) { calls <- call{"checkout", new.Name} })
+	defer one.Unsubscribe()
+	all := client.OnAnyChange(func(old, new FeatureFlag) { calls <- call{"any", new.Name} })
+	defer all.Unsubscribe()
+
+	// Let the watch fetch its initial state before changing flags
+	time.Sleep(50 * time.Millisecond)
+	search.IsActive = true
+	srv.publish(FlagUpdated, search)
+	checkout.IsActive = true
+	srv.publish(FlagUpdated, checkout)
+
+	var got []call
+	for len(got) < 3 {
+		select {
+		case c := <-calls:
+			got = append(got, c)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received %v, want 3 calls", got)
+		}
+	}
+	assert.ElementsMatch(t, []call{{"any", "search"}, {"checkout", "checkout"}, {"any", "checkout"}}, got)
+}
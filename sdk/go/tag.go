@@ -0,0 +1,75 @@
+package matrixflag
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// tagPattern matches valid tags, such as "team:payments" or "cleanup"
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9:._-]*$`)
+
+// maxTagLength is the longest tag accepted by the API
+const maxTagLength = 64
+
+// ValidateTag checks that a tag is lowercase letters, digits and the
+// characters ":", ".", "_" and "-", starting with a letter or digit
+func ValidateTag(tag string) error {
+	switch {
+	case tag == "":
+		return fmt.Errorf("tag is required")
+	case len(tag) > maxTagLength:
+		return fmt.Errorf("invalid tag %q: must be at most %d characters", tag, maxTagLength)
+	case !tagPattern.MatchString(tag):
+		return fmt.Errorf("invalid tag %q: must match %s", tag, tagPattern)
+	}
+	return nil
+}
+
+// validateTags checks the tags of a flag before they are sent
+func validateTags(tags []string) error {
+	for _, tag := range tags {
+		if err := ValidateTag(tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasTag reports whether the flag has a tag
+func (f *FeatureFlag) HasTag(tag string) bool {
+	for _, t := range f.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTag adds a tag to a feature flag, leaving its other tags unchanged; adding
+// a tag the flag already has is not an error
+func (c *Client) AddTag(ctx context.Context, id int, tag string, reqOpts ...RequestOption) (*FeatureFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := ValidateTag(tag); err != nil {
+		return nil, err
+	}
+	return c.flagRequest(ctx, request{
+		method: "POST",
+		path:   fmt.Sprintf("/api/v1/feature-flags/%d/tags", id),
+		body:   map[string]string{"tag": tag},
+	})
+}
+
+// RemoveTag removes a tag from a feature flag, leaving its other tags
+// unchanged; removing a tag the flag does not have is not an error
+func (c *Client) RemoveTag(ctx context.Context, id int, tag string, reqOpts ...RequestOption) (*FeatureFlag, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := ValidateTag(tag); err != nil {
+		return nil, err
+	}
+	return c.flagRequest(ctx, request{
+		method: "DELETE",
+		path:   fmt.Sprintf("/api/v1/feature-flags/%d/tags/%s", id, url.PathEscape(tag)),
+	})
+}
@@ -0,0 +1,56 @@
+package matrixflag
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTags(t *testing.T) {
+	var requests, bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath())
+		bodies = append(bodies, string(body))
+		tags := []string{"team:payments", "cleanup"}
+		if r.Method == http.MethodDelete {
+			tags = tags[:1]
+		}
+		writeJSON(w, FeatureFlag{ID: 7, Name: "checkout", Tags: tags})
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+
+	flag, err := client.AddTag(ctx, 7, "cleanup")
+	require.NoError(t, err)
+	assert.True(t, flag.HasTag("cleanup"))
+	flag, err = client.RemoveTag(ctx, 7, "team:payments")
+	require.NoError(t, err)
+	assert.False(t, flag.HasTag("cleanup"))
+
+	_, err = client.AddTag(ctx, 7, "Team Payments")
+	assert.ErrorContains(t, err, "invalid tag")
+	_, err = client.CreateFeatureFlag(ctx, FeatureFlagCreate{Name: "search", Tags: []string{""}})
+	assert.ErrorContains(t, err, "tag is required")
+
+	assert.Equal(t, []string{
+		"POST /api/v1/feature-flags/7/tags",
+		"DELETE /api/v1/feature-flags/7/tags/team:payments",
+	}, requests)
+	assert.JSONEq(t, `{"tag":"cleanup"}`, bodies[0])
+}
+
+func TestValidateTag(t *testing.T) {
+	for _, tag := range []string{"cleanup", "team:payments", "epic.q3-launch", "v2_beta"} {
+		assert.NoError(t, ValidateTag(tag), tag)
+	}
+	for _, tag := range []string{"", "Cleanup", "-cleanup", "two words", "a,b", string(make([]byte, 65))} {
+		assert.Error(t, ValidateTag(tag), tag)
+	}
+}
@@ -0,0 +1,242 @@
+package matrixflag
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// TargetingOperator compares a context attribute with the value of a condition
+type TargetingOperator string
+
+// Targeting operators
+const (
+	OpEquals      TargetingOperator = "equals"
+	OpNotEquals   TargetingOperator = "not_equals"
+	OpContains    TargetingOperator = "contains"
+	OpNotContains TargetingOperator = "not_contains"
+	OpGreaterThan TargetingOperator = "greater_than"
+	OpLessThan    TargetingOperator = "less_than"
+	// OpIn and OpNotIn take a list of values
+	OpIn    TargetingOperator = "in"
+	OpNotIn TargetingOperator = "not_in"
+	// OpBetween and OpNotBetween take an inclusive [low, high] pair
+	OpBetween    TargetingOperator = "between"
+	OpNotBetween TargetingOperator = "not_between"
+)
+
+// TargetingCondition matches contexts on one attribute
+type TargetingCondition struct {
+	// Attribute is the name of the attribute in the flattened context, see EvaluationContext.Flatten
+	Attribute   string            `json:"attribute"`
+	Operator    TargetingOperator `json:"operator"`
+	Value       any               `json:"value"`
+	Description string            `json:"description,omitempty"`
+}
+
+// FlagRule targets the contexts matching all of its conditions and belonging to
+// all of its segments. The first matching rule of a flag decides its value.
+type FlagRule struct {
+	ID         string               `json:"id"`
+	Conditions []TargetingCondition `json:"conditions,omitempty"`
+	// Segments are the names of segments the context must belong to
+	Segments []string `json:"segments,omitempty"`
+	// Variation is the key of the variation served by the rule; boolean flags serve true
+	Variation string `json:"variation,omitempty"`
+	// Rollout limits the rule to a percentage of the contexts it matches, bucketed
+	// on its own BucketBy attribute; the others fall through to the next rule
+	Rollout *PercentageRollout `json:"rollout,omitempty"`
+}
+
+// inRolloutFor reports whether a context matched by the rule is within the
+// rule's rollout, salted per rule of flagID unless the rollout sets a salt
+func (r FlagRule) inRolloutFor(flagID int, attributes map[string]any) bool {
+	if r.Rollout == nil {
+		return true
+	}
+	key, ok := BucketingKey(attributes, r.Rollout.BucketBy)
+	return ok && Bucket(key, r.rolloutSalt(flagID)) < r.Rollout.Percentage
+}
+
+// rolloutSalt returns the salt of the rule's rollout, derived from flagID and the rule ID unless the rollout sets one
+func (r FlagRule) rolloutSalt(flagID int) string {
+	if r.Rollout.Salt != "" {
+		return r.Rollout.Salt
+	}
+	return "rule." + strconv.Itoa(flagID) + "." + r.ID
+}
+
+// Matches reports whether a context, given as attributes, satisfies the
+// condition. Conditions on an attribute the context lacks never match.
+func (c TargetingCondition) Matches(attributes map[string]any) bool {
+	attr, ok := attributes[c.Attribute]
+	if !ok || attr == nil {
+		return false
+	}
+	switch c.Operator {
+	case OpEquals:
+		return valuesEqual(attr, c.Value)
+	case OpNotEquals:
+		return !valuesEqual(attr, c.Value)
+	case OpContains:
+		return containsValue(attr, c.Value)
+	case OpNotContains:
+		return !containsValue(attr, c.Value)
+	case OpGreaterThan:
+		cmp, ok := compareValues(attr, c.Value)
+		return ok && cmp > 0
+	case OpLessThan:
+		cmp, ok := compareValues(attr, c.Value)
+		return ok && cmp < 0
+	case OpIn:
+		return inValues(attr, c.Value)
+	case OpNotIn:
+		return !inValues(attr, c.Value)
+	case OpBetween:
+		return betweenValues(attr, c.Value)
+	case OpNotBetween:
+		return !betweenValues(attr, c.Value)
+	}
+	return false
+}
+
+// Validate checks that the condition has an attribute and a known operator
+func (c TargetingCondition) Validate() error {
+	if c.Attribute == "" {
+		return fmt.Errorf("invalid targeting condition: an attribute is required")
+	}
+	switch c.Operator {
+	case OpEquals, OpNotEquals, OpContains, OpNotContains, OpGreaterThan, OpLessThan:
+	case OpIn, OpNotIn:
+		if !isList(c.Value) {
+			return fmt.Errorf("invalid targeting condition on %s: %s takes a list of values", c.Attribute, c.Operator)
+		}
+	case OpBetween, OpNotBetween:
+		if v := reflect.ValueOf(c.Value); !isList(c.Value) || v.Len() != 2 {
+			return fmt.Errorf("invalid targeting condition on %s: %s takes a [low, high] pair", c.Attribute, c.Operator)
+		}
+	default:
+		return fmt.Errorf("invalid targeting condition on %s: unknown operator %q", c.Attribute, c.Operator)
+	}
+	return nil
+}
+
+// validateFlagRules checks rule conditions and that served variations exist.
+// Variations cannot be checked when none are given, as in an update leaving them unchanged.
+func validateFlagRules(rules []FlagRule, variations []Variation) error {
+	ids := make(map[string]bool, len(rules))
+	for i, rule := range rules {
+		if rule.ID == "" {
+			return fmt.Errorf("invalid flag rule %d: an ID is required", i)
+		}
+		if ids[rule.ID] {
+			return fmt.Errorf("invalid flag rule %d: ID %q appears more than once", i, rule.ID)
+		}
+		ids[rule.ID] = true
+		for _, cond := range rule.Conditions {
+			if err := cond.Validate(); err != nil {
+				return fmt.Errorf("invalid flag rule %s: %w", rule.ID, err)
+			}
+		}
+		if rule.Rollout != nil {
+			if err := rule.Rollout.Validate(); err != nil {
+				return fmt.Errorf("invalid flag rule %s: %w", rule.ID, err)
+			}
+		}
+		if rule.Variation != "" && len(variations) > 0 && !hasVariation(variations, rule.Variation) {
+			return fmt.Errorf("invalid flag rule %s: unknown variation %q", rule.ID, rule.Variation)
+		}
+	}
+	return nil
+}
+
+// matchesConditions reports whether attributes satisfy every condition
+func matchesConditions(conditions []TargetingCondition, attributes map[string]any) bool {
+	for _, cond := range conditions {
+		if !cond.Matches(attributes) {
+			return false
+		}
+	}
+	return true
+}
+
+// valuesEqual compares numbers by value and other values by their printed form
+func valuesEqual(a, b any) bool {
+	if x, ok := asFloat(a); ok {
+		y, ok := asFloat(b)
+		return ok && x == y
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// compareValues orders numbers by value and strings lexically
+func compareValues(a, b any) (int, bool) {
+	if x, ok := asFloat(a); ok {
+		y, ok := asFloat(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	}
+	x, ok := a.(string)
+	y, ok2 := b.(string)
+	if !ok || !ok2 {
+		return 0, false
+	}
+	return strings.Compare(x, y), true
+}
+
+// containsValue reports whether a string attribute contains a substring or a list attribute contains a value
+func containsValue(attr, value any) bool {
+	if s, ok := attr.(string); ok {
+		return strings.Contains(s, fmt.Sprint(value))
+	}
+	return inValues(value, attr)
+}
+
+// inValues reports whether value equals an element of list
+func inValues(value, list any) bool {
+	if !isList(list) {
+		return false
+	}
+	v := reflect.ValueOf(list)
+	for i := 0; i < v.Len(); i++ {
+		if valuesEqual(value, v.Index(i).Interface()) {
+			return true
+		}
+	}
+	return false
+}
+
+// betweenValues reports whether value lies within an inclusive [low, high] pair
+func betweenValues(value, bounds any) bool {
+	if !isList(bounds) {
+		return false
+	}
+	v := reflect.ValueOf(bounds)
+	if v.Len() != 2 {
+		return false
+	}
+	low, ok := compareValues(value, v.Index(0).Interface())
+	if !ok {
+		return false
+	}
+	high, ok := compareValues(value, v.Index(1).Interface())
+	return ok && low >= 0 && high <= 0
+}
+
+// isList reports whether v is a slice or array
+func isList(v any) bool {
+	if v == nil {
+		return false
+	}
+	kind := reflect.TypeOf(v).Kind()
+	return kind == reflect.Slice || kind == reflect.Array
+}
@@ -0,0 +1,44 @@
+package matrixflag
+
+import (
+	"context"
+	"time"
+)
+
+// EndpointClass groups API endpoints with similar latency for timeout configuration
+type EndpointClass string
+
+// Endpoint classes
+const (
+	// EndpointRead covers fast reads of single resources and lists
+	EndpointRead EndpointClass = "read"
+	// EndpointWrite covers creates, updates and deletes
+	EndpointWrite EndpointClass = "write"
+	// EndpointBulk covers operations changing many flags at once
+	EndpointBulk EndpointClass = "bulk"
+	// EndpointExport covers exports and reports over a whole environment or experiment
+	EndpointExport EndpointClass = "export"
+)
+
+// endpointClass returns the class of a request, derived from its method unless set explicitly
+func (r request) endpointClass() EndpointClass {
+	if r.class != "" {
+		return r.class
+	}
+	if r.method == "GET" {
+		return EndpointRead
+	}
+	return EndpointWrite
+}
+
+// timeout returns the timeout of a request attempt, that of the call's
+// options when set
+func (c *Client) timeout(ctx context.Context, req request) time.Duration {
+	if timeout := callOptions(ctx).timeout; timeout > 0 {
+		return timeout
+	}
+	if timeout, ok := c.config.Timeouts[req.endpointClass()]; ok {
+		return timeout
+	}
+	return c.config.Timeout
+}
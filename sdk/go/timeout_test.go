@@ -0,0 +1,86 @@
+package matrixflag
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSlowServer delays every request to handler by delay
+func newSlowServer(t *testing.T, handler http.Handler, delay time.Duration) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+			handler.ServeHTTP(w, r)
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestEndpointClassTimeouts(t *testing.T) {
+	fake := newFakeServer(t)
+	flag := fake.addFlag(FeatureFlag{Name: "checkout", Environment: "production"})
+	srv := newSlowServer(t, newGroupServer(t, fake).Config.Handler, 50*time.Millisecond)
+	ctx := context.Background()
+
+	calls := map[EndpointClass]func(c *Client) error{
+		EndpointRead: func(c *Client) error {
+			_, err := c.GetFeatureFlag(ctx, flag.ID)
+			return err
+		},
+		EndpointWrite: func(c *Client) error {
+			_, err := c.ToggleFeatureFlag(ctx, flag.ID)
+			return err
+		},
+		EndpointBulk: func(c *Client) error {
+			_, err := c.AtomicFlagOperation(ctx, []FlagOperation{{FlagID: flag.ID, Action: FlagToggle}})
+			return err
+		},
+		EndpointExport: func(c *Client) error {
+			_, err := c.ExportFlags(ctx, "production")
+			return err
+		},
+	}
+	for class := range calls {
+		t.Run(string(class), func(t *testing.T) {
+			client := NewClient(srv.URL, "key", &Config{
+				Timeout:  time.Second,
+				Timeouts: map[EndpointClass]time.Duration{class: 10 * time.Millisecond},
+			})
+			for other, call := range calls {
+				err := call(client)
+				if other == class {
+					assert.True(t, errors.Is(err, context.DeadlineExceeded), "the %s timeout fires: %v", class, err)
+				} else {
+					assert.NoError(t, err, "the %s timeout does not apply to %s endpoints", class, other)
+				}
+			}
+		})
+	}
+}
+
+func TestEndpointClassTimeoutOverride(t *testing.T) {
+	fake := newFakeServer(t)
+	flag := fake.addFlag(FeatureFlag{Name: "checkout"})
+	srv := newSlowServer(t, fake.Config.Handler, 50*time.Millisecond)
+	client := NewClient(srv.URL, "key", &Config{
+		Timeout:  10 * time.Millisecond,
+		Timeouts: map[EndpointClass]time.Duration{EndpointRead: time.Second},
+	})
+	ctx := context.Background()
+
+	_, err := client.GetFeatureFlag(ctx, flag.ID)
+	require.NoError(t, err, "the class timeout overrides Timeout")
+	_, err = client.ToggleFeatureFlag(ctx, flag.ID)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "classes without a timeout use Timeout")
+	_, err = client.GetFeatureFlag(ctx, flag.ID, WithRequestTimeout(10*time.Millisecond))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "the call's timeout overrides the class timeout")
+}
@@ -0,0 +1,225 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// TraceCheck is a kind of check made by a flag evaluation
+type TraceCheck string
+
+// Checks of a flag evaluation, in the order they are made
+const (
+	// CheckActive checks that the flag is active
+	CheckActive TraceCheck = "active"
+	// CheckPrerequisite checks that a prerequisite flag serves the required variation
+	CheckPrerequisite TraceCheck = "prerequisite"
+	// CheckHoldout is recorded when a holdout keeps the context out of the flag
+	CheckHoldout TraceCheck = "holdout"
+	// CheckLayer checks that the experiment layer of the flag assigns the context to it
+	CheckLayer TraceCheck = "layer"
+	// CheckTraffic checks that the context is within the flag's traffic allocation
+	CheckTraffic TraceCheck = "traffic"
+	// CheckRule checks that a targeting rule matches the context and, with a
+	// rollout, that the context is within it
+	CheckRule TraceCheck = "rule"
+	// CheckRollout checks that the context is within the flag's percentage rollout
+	CheckRollout TraceCheck = "rollout"
+	// CheckVariation buckets the context into one of the flag's weighted variations
+	CheckVariation TraceCheck = "variation"
+)
+
+// EvaluationTrace is the decision trace of a flag evaluation: every check it
+// made, in order, and the result. The last step decided the result.
+type EvaluationTrace struct {
+	Flag        string                `json:"flag"`
+	Environment string                `json:"environment"`
+	Context     EvaluationContext     `json:"context"`
+	Detail      EvaluationDetail[any] `json:"detail"`
+	Steps       []TraceStep           `json:"steps"`
+}
+
+// TraceStep is one check of a flag evaluation
+type TraceStep struct {
+	Check TraceCheck `json:"check"`
+	// Passed reports whether the evaluation went on past the check, or served
+	// the rule or variation it found
+	Passed bool `json:"passed"`
+	// RuleID and Matched are the rule of a CheckRule step and whether its
+	// conditions and segments matched, even when its rollout left the context out
+	RuleID  string `json:"rule_id,omitempty"`
+	Matched bool   `json:"matched,omitempty"`
+	// Prerequisite is the flag of a CheckPrerequisite step
+	Prerequisite string `json:"prerequisite,omitempty"`
+	// Variation is the variation served by the prerequisite of a
+	// CheckPrerequisite step, or picked by a CheckVariation step
+	Variation string `json:"variation,omitempty"`
+	// Holdout and Layer are the keys of the holdout or layer of the step
+	Holdout string `json:"holdout,omitempty"`
+	Layer   string `json:"layer,omitempty"`
+	// Bucketing is how the context was bucketed by a step checking a
+	// percentage or picking a variation
+	Bucketing *TraceBucketing `json:"bucketing,omitempty"`
+}
+
+// TraceBucketing is how a context was bucketed, see Bucket
+type TraceBucketing struct {
+	// BucketBy is the context attribute bucketed on and Key its value, empty
+	// when the context lacks the attribute
+	BucketBy string `json:"bucket_by"`
+	Key      string `json:"key,omitempty"`
+	// Bucket is the bucket of Key in [0, 100), inside Percentage when below it.
+	// Variations have no percentage; their weights split the buckets instead.
+	Bucket     float64 `json:"bucket"`
+	Percentage float64 `json:"percentage"`
+}
+
+// add records a step, doing nothing on a nil trace
+func (t *EvaluationTrace) add(step TraceStep) {
+	if t != nil {
+		t.Steps = append(t.Steps, step)
+	}
+}
+
+// traceBucketing returns how the attribute bucketBy of a context is bucketed with salt
+func traceBucketing(attributes map[string]any, bucketBy, salt string, percentage float64) *TraceBucketing {
+	if bucketBy == "" {
+		bucketBy = DefaultBucketBy
+	}
+	b := &TraceBucketing{BucketBy: bucketBy, Percentage: percentage}
+	if key, ok := BucketingKey(attributes, bucketBy); ok {
+		b.Key, b.Bucket = key, Bucket(key, salt)
+	}
+	return b
+}
+
+// String formats the trace for people, one line per step:
+//
+//	new-checkout in production: true (RULE_MATCH, rule beta-users)
+//	  active: passed
+//	  rule internal: not matched
+//	  rule beta-users: matched, key alice in bucket 12.06 of 25%
+func (t *EvaluationTrace) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s in %s: %v (%s", t.Flag, t.Environment, t.Detail.Value, t.Detail.Reason)
+	switch {
+	case t.Detail.RuleID != "":
+		fmt.Fprintf(&b, ", rule %s", t.Detail.RuleID)
+	case t.Detail.Err != nil:
+		fmt.Fprintf(&b, ", %v", t.Detail.Err)
+	case t.Detail.ErrorCode != "":
+		fmt.Fprintf(&b, ", %s", t.Detail.ErrorCode)
+	}
+	b.WriteString(")\n")
+	for _, step := range t.Steps {
+		b.WriteString("  " + step.String() + "\n")
+	}
+	return b.String()
+}
+
+// String formats the step for people, see EvaluationTrace.String
+func (s TraceStep) String() string {
+	name := string(s.Check)
+	for _, id := range []string{s.RuleID, s.Prerequisite, s.Holdout, s.Layer} {
+		if id != "" {
+			name += " " + id
+		}
+	}
+	result := "failed"
+	switch {
+	case s.Check == CheckRule && s.Passed:
+		result = "matched"
+	case s.Check == CheckRule && s.Matched:
+		result = "matched, outside its rollout"
+	case s.Check == CheckRule:
+		result = "not matched"
+	case s.Check == CheckHoldout:
+		result = "held out"
+	case s.Passed:
+		result = "passed"
+	}
+	if s.Variation != "" {
+		result += ", variation " + s.Variation
+	}
+	if bk := s.Bucketing; bk != nil {
+		switch {
+		case bk.Key == "":
+			result += ", no " + bk.BucketBy + " attribute to bucket on"
+		case s.Check == CheckVariation:
+			result += fmt.Sprintf(", %s %s in bucket %.2f", bk.BucketBy, bk.Key, bk.Bucket)
+		default:
+			result += fmt.Sprintf(", %s %s in bucket %.2f of %g%%", bk.BucketBy, bk.Key, bk.Bucket, bk.Percentage)
+		}
+	}
+	return name + ": " + result
+}
+
+// debugEvaluate evaluates a flag of the ruleset for a context, tracing its checks
+func (ix *indexedRuleset) debugEvaluate(flagKey string, evalCtx EvaluationContext) (*EvaluationTrace, error) {
+	flag, ok := ix.flags[flagKey]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFlagNotFound, flagKey)
+	}
+	t := &EvaluationTrace{Flag: flagKey, Environment: ix.Environment, Context: evalCtx}
+	t.Detail = ix.trace(flag, evalCtx, t)
+	return t, nil
+}
+
+// DebugEvaluate evaluates a flag on the server for an evaluation context and
+// returns the decision trace of the evaluation: the checks made, such as which
+// rules matched, and how the context was bucketed into rollouts and
+// variations, for support and troubleshooting. In offline mode, or once the
+// client is started, the flag is traced locally. Unlike Evaluate, the evaluation
+// is not traced, logged, counted in metrics or sent as an event.
+func (c *Client) DebugEvaluate(ctx context.Context, flagKey string, evalCtx EvaluationContext, reqOpts ...RequestOption) (*EvaluationTrace, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := evalCtx.Validate(); err != nil {
+		return nil, err
+	}
+	if ix, local, err := c.localRuleset(); local {
+		if err != nil {
+			return nil, err
+		}
+		return ix.debugEvaluate(flagKey, evalCtx)
+	}
+	respBody, err := c.doRequest(ctx, request{
+		method: "POST",
+		path:   "/api/v1/feature-flags/evaluate/debug",
+		body: map[string]any{
+			"flag":        c.qualifyName(flagKey),
+			"environment": c.config.Environment,
+			"context":     evalCtx,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var trace EvaluationTrace
+	if err := json.Unmarshal(respBody, &trace); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if trace.Detail.Reason == ReasonError {
+		if trace.Detail.ErrorCode == "" {
+			trace.Detail.ErrorCode = ErrorGeneral
+		}
+		trace.Detail.Err = fmt.Errorf("evaluation of flag %s failed: %s", flagKey, trace.Detail.ErrorCode)
+	}
+	return &trace, nil
+}
+
+// DebugEvaluate evaluates a flag locally for a context and returns the decision
+// trace of the evaluation, see Client.DebugEvaluate. The evaluation is not
+// recorded as a debug event.
+func (e *Evaluator) DebugEvaluate(flagKey string, evalCtx EvaluationContext) (*EvaluationTrace, error) {
+	ix := e.ruleset.Load()
+	if ix == nil {
+		return nil, ErrEvaluatorNotReady
+	}
+	if err := evalCtx.Validate(); err != nil {
+		return nil, err
+	}
+	return ix.debugEvaluate(flagKey, evalCtx)
+}
@@ -0,0 +1,173 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The steps rely on the buckets listed in TestBucketVectors
+func TestEvaluatorDebugEvaluate(t *testing.T) {
+	evaluator := NewEvaluator(NewClient("http://localhost", "key", nil), EvaluatorOptions{Environment: "production"})
+	user := EvaluationContext{Key: "user-2", Attributes: map[string]any{"country": "DE"}}
+
+	_, err := evaluator.DebugEvaluate("checkout", user)
+	assert.ErrorIs(t, err, ErrEvaluatorNotReady)
+
+	evaluator.SetRuleset(&Ruleset{Environment: "production", Flags: []FeatureFlag{{
+		ID: 1, Name: "checkout", IsActive: true,
+		Variations: []Variation{
+			{Key: "control", Value: "A", Weight: 25},
+			{Key: "treatment", Value: "B", Weight: 75},
+		},
+		Rules: []FlagRule{
+			{ID: "nl", Conditions: []TargetingCondition{{Attribute: "country", Operator: OpEquals, Value: "NL"}}},
+		},
+		Rollout: &PercentageRollout{Percentage: 50},
+	}}})
+	_, err = evaluator.DebugEvaluate("search", user)
+	assert.ErrorIs(t, err, ErrFlagNotFound)
+
+	trace, err := evaluator.DebugEvaluate("checkout", user)
+	require.NoError(t, err)
+	assert.Equal(t, "A", trace.Detail.Value)
+	assert.Equal(t, ReasonSplit, trace.Detail.Reason)
+	require.NotNil(t, trace.Detail.VariationIndex)
+	assert.Equal(t, 0, *trace.Detail.VariationIndex)
+	require.Len(t, trace.Steps, 4)
+	assert.Equal(t, TraceStep{Check: CheckActive, Passed: true}, trace.Steps[0])
+	assert.Equal(t, TraceStep{Check: CheckRule, RuleID: "nl"}, trace.Steps[1])
+	assert.Equal(t, CheckRollout, trace.Steps[2].Check)
+	assert.True(t, trace.Steps[2].Passed)
+	assert.InDelta(t, 39.19984656623321, trace.Steps[2].Bucketing.Bucket, 1e-9)
+	assert.Equal(t, CheckVariation, trace.Steps[3].Check)
+	assert.Equal(t, "control", trace.Steps[3].Variation)
+	assert.InDelta(t, 23.156904761325652, trace.Steps[3].Bucketing.Bucket, 1e-9)
+
+	assert.Equal(t, "checkout in production: A (SPLIT)\n"+
+		"  active: passed\n"+
+		"  rule nl: not matched\n"+
+		"  rollout: passed, key user-2 in bucket 39.20 of 50%\n"+
+		"  variation: passed, variation control, key user-2 in bucket 23.16\n", trace.String())
+
+	assert.Equal(t, trace.Detail, evaluator.Evaluate("checkout", user, nil), "tracing does not change the result")
+}
+
+func TestDebugEvaluateSteps(t *testing.T) {
+	prereq := FeatureFlag{ID: 2, Name: "payments", IsActive: true, Variations: []Variation{{Key: "on", Value: true, Weight: 1}}}
+	tests := []struct {
+		name    string
+		flag    FeatureFlag
+		ruleset Ruleset
+		evalCtx EvaluationContext
+		steps   []string
+	}{
+		{
+			name:  "inactive",
+			flag:  FeatureFlag{ID: 1},
+			steps: []string{"active: failed"},
+		},
+		{
+			name:    "failed prerequisite",
+			flag:    FeatureFlag{ID: 1, IsActive: true, Prerequisites: []Prerequisite{{Flag: "payments", Variation: "off"}}},
+			ruleset: Ruleset{Flags: []FeatureFlag{prereq}},
+			evalCtx: EvaluationContext{Key: "user-1"},
+			steps:   []string{"active: passed", "prerequisite payments: failed, variation on"},
+		},
+		{
+			name:    "holdout",
+			flag:    FeatureFlag{ID: 1, IsActive: true},
+			ruleset: Ruleset{Holdouts: []Holdout{{ID: 2, Key: "q3", Percentage: 50, FlagIDs: []int{1}}}},
+			evalCtx: EvaluationContext{Key: "user-3"},
+			steps:   []string{"active: passed", "holdout q3: held out"},
+		},
+		{
+			name:    "layer",
+			flag:    FeatureFlag{ID: 1, IsActive: true, LayerID: 7},
+			ruleset: Ruleset{Layers: []Layer{{ID: 7, Key: "checkout", Allocations: []LayerAllocation{{FlagID: 1, Start: 0, End: 50}}}}},
+			evalCtx: EvaluationContext{Key: "user-4"},
+			steps:   []string{"active: passed", "layer checkout: passed"},
+		},
+		{
+			name:    "traffic",
+			flag:    FeatureFlag{ID: 1, IsActive: true, TrafficAllocation: &TrafficAllocation{Percentage: 30}},
+			evalCtx: EvaluationContext{Key: "user-2"},
+			steps:   []string{"active: passed", "traffic: failed, key user-2 in bucket 39.47 of 30%"},
+		},
+		{
+			name: "rule outside its rollout",
+			flag: FeatureFlag{ID: 1, IsActive: true, Rules: []FlagRule{
+				{ID: "all", Rollout: &PercentageRollout{Percentage: 0}},
+				{ID: "fallback"},
+			}},
+			evalCtx: EvaluationContext{Key: "user-1"},
+			steps:   []string{"active: passed", "rule all: matched, outside its rollout, key user-1 in bucket", "rule fallback: matched"},
+		},
+		{
+			name:    "missing bucketing attribute",
+			flag:    FeatureFlag{ID: 1, IsActive: true, Rollout: &PercentageRollout{Percentage: 50, BucketBy: "org"}},
+			evalCtx: EvaluationContext{Key: "user-1"},
+			steps:   []string{"active: passed", "rollout: failed, no org attribute to bucket on"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ruleset := tt.ruleset
+			ruleset.Flags = append([]FeatureFlag{tt.flag}, ruleset.Flags...)
+			ruleset.Flags[0].Name = "checkout"
+			trace, err := ruleset.index().debugEvaluate("checkout", tt.evalCtx)
+			require.NoError(t, err)
+			require.Len(t, trace.Steps, len(tt.steps))
+			for i, step := range trace.Steps {
+				assert.Contains(t, step.String(), tt.steps[i])
+			}
+		})
+	}
+}
+
+func TestClientDebugEvaluate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/v1/feature-flags/evaluate/debug", r.URL.Path)
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "production", body["environment"])
+		w.Header().Set("Content-Type", "application/json")
+		if body["flag"] == "broken" {
+			w.Write([]byte(`{"flag": "broken", "environment": "production", "detail": {"value": null, "reason": "ERROR"}, "steps": []}`))
+			return
+		}
+		w.Write([]byte(`{
+			"flag": "checkout", "environment": "production", "context": {"key": "user-1"},
+			"detail": {"value": true, "reason": "RULE_MATCH", "rule_id": "beta", "variation": "on", "variation_index": 1},
+			"steps": [
+				{"check": "active", "passed": true},
+				{"check": "rule", "rule_id": "beta", "passed": true, "matched": true,
+				 "bucketing": {"bucket_by": "key", "key": "user-1", "bucket": 12.5, "percentage": 25}}
+			]
+		}`))
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil, WithEnvironment("production"))
+
+	trace, err := client.DebugEvaluate(context.Background(), "checkout", EvaluationContext{Key: "user-1"})
+	require.NoError(t, err)
+	assert.Equal(t, true, trace.Detail.Value)
+	assert.Equal(t, 1, *trace.Detail.VariationIndex)
+	assert.Equal(t, "checkout in production: true (RULE_MATCH, rule beta)\n"+
+		"  active: passed\n"+
+		"  rule beta: matched, key user-1 in bucket 12.50 of 25%\n", trace.String())
+
+	trace, err = client.DebugEvaluate(context.Background(), "broken", EvaluationContext{Key: "user-1"})
+	require.NoError(t, err)
+	assert.Equal(t, ErrorGeneral, trace.Detail.ErrorCode)
+	assert.Error(t, trace.Detail.Err)
+
+	_, err = client.DebugEvaluate(context.Background(), "checkout", EvaluationContext{})
+	assert.ErrorIs(t, err, ErrInvalidContext)
+}
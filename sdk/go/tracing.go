@@ -0,0 +1,42 @@
+package matrixflag
+
+import "context"
+
+// Tracer starts spans around API calls and flag evaluations, so flag lookups
+// appear in distributed traces. The otelflag package implements it with
+// OpenTelemetry. The returned context carries the span; the returned function
+// ends it.
+type Tracer interface {
+	// StartRequest starts the span of an API call, which covers all its retries.
+	// status is the final status code, 0 when no response was received.
+	StartRequest(ctx context.Context, method string, class EndpointClass, path string) (context.Context, func(status int, err error))
+	// StartEvaluation starts the span of a flag evaluation by a Client or Evaluator.
+	// Evaluator evaluations have no parent context.
+	StartEvaluation(ctx context.Context, flagKey string) (context.Context, func(EvaluationDetail[any]))
+}
+
+// NopTracer records no spans. Embed it to implement only part of Tracer.
+type NopTracer struct{}
+
+func (NopTracer) StartRequest(ctx context.Context, _ string, _ EndpointClass, _ string) (context.Context, func(int, error)) {
+	return ctx, func(int, error) {}
+}
+
+func (NopTracer) StartEvaluation(ctx context.Context, _ string) (context.Context, func(EvaluationDetail[any])) {
+	return ctx, func(EvaluationDetail[any]) {}
+}
+
+// WithTracer sets the tracer of the client
+func WithTracer(tracer Tracer) ClientOption {
+	return func(c *Client) {
+		c.tracer = tracer
+	}
+}
+
+// Tracer returns the tracer of the client, which records nothing unless set with WithTracer
+func (c *Client) Tracer() Tracer {
+	if c.tracer == nil {
+		return NopTracer{}
+	}
+	return c.tracer
+}
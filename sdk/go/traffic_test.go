@@ -0,0 +1,113 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInTraffic(t *testing.T) {
+	all := FeatureFlag{ID: 1}
+	none := FeatureFlag{ID: 1, TrafficAllocation: &TrafficAllocation{Percentage: 0}}
+	partial := FeatureFlag{ID: 1, TrafficAllocation: &TrafficAllocation{Percentage: 20}}
+	wider := FeatureFlag{ID: 1, TrafficAllocation: &TrafficAllocation{Percentage: 60}}
+	salted := FeatureFlag{ID: 2, TrafficAllocation: &TrafficAllocation{Percentage: 20, Salt: "traffic.1"}}
+
+	in := 0
+	for i := 0; i < 5000; i++ {
+		key := fmt.Sprintf("user-%d", i)
+		assert.True(t, all.InTraffic(key), "flags without an allocation admit all traffic")
+		assert.False(t, none.InTraffic(key))
+		assert.Equal(t, partial.InTraffic(key), salted.InTraffic(key), "the salt seeds the bucketing, not the flag ID")
+		if partial.InTraffic(key) {
+			assert.True(t, wider.InTraffic(key), "raising the allocation keeps admitted traffic")
+			in++
+		}
+	}
+	assert.InDelta(t, 1000, in, 150)
+}
+
+func TestInTrafficFor(t *testing.T) {
+	flag := FeatureFlag{ID: 1, TrafficAllocation: &TrafficAllocation{Percentage: 50, BucketBy: "org_id"}}
+	var orgIn, orgOut string
+	for i := 0; orgIn == "" || orgOut == ""; i++ {
+		org := fmt.Sprintf("org-%d", i)
+		if flag.InTraffic(org) {
+			orgIn = org
+		} else {
+			orgOut = org
+		}
+	}
+
+	for i := 0; i < 20; i++ {
+		user := fmt.Sprintf("user-%d", i)
+		assert.True(t, flag.InTrafficFor(map[string]any{"key": user, "org_id": orgIn}), "every user of an organization is admitted together")
+		assert.False(t, flag.InTrafficFor(map[string]any{"key": user, "org_id": orgOut}))
+	}
+	assert.False(t, flag.InTrafficFor(map[string]any{"key": "user-1"}), "contexts without the attribute are kept out")
+	assert.True(t, (&FeatureFlag{}).InTrafficFor(nil))
+}
+
+func TestTrafficAllocationEvaluation(t *testing.T) {
+	evaluator := NewEvaluator(NewClient("http://localhost", "key", nil), EvaluatorOptions{Environment: "production"})
+	evaluator.SetRuleset(&Ruleset{Environment: "production", Flags: []FeatureFlag{{
+		ID: 1, Name: "checkout", IsActive: true, TrafficAllocation: &TrafficAllocation{Percentage: 30},
+		Variations: []Variation{{Key: "control", Value: "a", Weight: 50}, {Key: "treatment", Value: "b", Weight: 50}},
+	}}})
+	flag := evaluator.Ruleset().Flags[0]
+
+	for i := 0; i < 200; i++ {
+		key := fmt.Sprintf("user-%d", i)
+		d := evaluator.Evaluate("checkout", EvaluationContext{Key: key}, "default")
+		if flag.InTraffic(key) {
+			assert.Equal(t, ReasonSplit, d.Reason)
+			assert.Contains(t, []string{"control", "treatment"}, d.Variation)
+		} else {
+			assert.Equal(t, ReasonExcluded, d.Reason, "traffic outside the allocation is not part of the experiment")
+			assert.Empty(t, d.Variation)
+		}
+	}
+}
+
+func TestTrafficAllocationValidate(t *testing.T) {
+	assert.NoError(t, TrafficAllocation{Percentage: 0}.Validate())
+	assert.NoError(t, TrafficAllocation{Percentage: 100}.Validate())
+	assert.EqualError(t, TrafficAllocation{Percentage: -1}.Validate(), "invalid traffic allocation -1: must be between 0 and 100")
+	assert.EqualError(t, TrafficAllocation{Percentage: 100.5}.Validate(), "invalid traffic allocation 100.5: must be between 0 and 100")
+}
+
+func TestUpdateTrafficAllocation(t *testing.T) {
+	allocation := TrafficAllocation{Percentage: 100}
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPut {
+			json.NewDecoder(r.Body).Decode(&allocation)
+		}
+		writeJSON(w, allocation)
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+
+	_, err := client.UpdateTrafficAllocation(ctx, 7, TrafficAllocation{Percentage: 120})
+	assert.ErrorContains(t, err, "invalid traffic allocation")
+	assert.Empty(t, requests, "invalid allocations are not sent")
+
+	updated, err := client.UpdateTrafficAllocation(ctx, 7, TrafficAllocation{Percentage: 20, BucketBy: "org_id"})
+	require.NoError(t, err)
+	assert.Equal(t, &TrafficAllocation{Percentage: 20, BucketBy: "org_id"}, updated)
+	got, err := client.GetTrafficAllocation(ctx, 7)
+	require.NoError(t, err)
+	assert.Equal(t, updated, got)
+	assert.Equal(t, []string{
+		"PUT /api/v1/feature-flags/7/traffic-allocation",
+		"GET /api/v1/feature-flags/7/traffic-allocation",
+	}, requests)
+}
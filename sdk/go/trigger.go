@@ -0,0 +1,154 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// TriggerAction is the change a trigger applies to its flag
+type TriggerAction string
+
+// Trigger actions
+const (
+	TriggerEnable  TriggerAction = "enable"
+	TriggerDisable TriggerAction = "disable"
+	TriggerToggle  TriggerAction = "toggle"
+)
+
+// Trigger represents a signed URL that changes a flag when it is requested.
+// URL is only returned when the trigger is created or its URL is reset.
+type Trigger struct {
+	ID              int           `json:"id"`
+	FlagID          int           `json:"flag_id"`
+	Action          TriggerAction `json:"action"`
+	Description     string        `json:"description,omitempty"`
+	Enabled         bool          `json:"enabled"`
+	URL             string        `json:"url,omitempty"`
+	TriggerCount    int           `json:"trigger_count"`
+	LastTriggeredAt *time.Time    `json:"last_triggered_at,omitempty"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+}
+
+// TriggerCreate represents the data needed to create a trigger
+type TriggerCreate struct {
+	Action      TriggerAction `json:"action"`
+	Description string        `json:"description,omitempty"`
+}
+
+// TriggerUpdate represents the data needed to update a trigger
+type TriggerUpdate struct {
+	Description string `json:"description,omitempty"`
+	Enabled     *bool  `json:"enabled,omitempty"`
+}
+
+// ListTriggers retrieves the triggers of a feature flag
+func (c *Client) ListTriggers(ctx context.Context, flagID int, reqOpts ...RequestOption) ([]Trigger, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	respBody, err := c.doRequest(ctx, request{
+		method: "GET",
+		path:   fmt.Sprintf("/api/v1/feature-flags/%d/triggers", flagID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var triggers []Trigger
+	if err := json.Unmarshal(respBody, &triggers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return triggers, nil
+}
+
+// CreateTrigger creates a trigger for a feature flag; the returned trigger holds its signed URL
+func (c *Client) CreateTrigger(ctx context.Context, flagID int, trigger TriggerCreate, reqOpts ...RequestOption) (*Trigger, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	switch trigger.Action {
+	case TriggerEnable, TriggerDisable, TriggerToggle:
+	default:
+		return nil, fmt.Errorf("invalid trigger action %q", trigger.Action)
+	}
+	return c.triggerRequest(ctx, request{
+		method: "POST",
+		path:   fmt.Sprintf("/api/v1/feature-flags/%d/triggers", flagID),
+		body:   trigger,
+	})
+}
+
+// GetTrigger retrieves a trigger by ID
+func (c *Client) GetTrigger(ctx context.Context, flagID, triggerID int, reqOpts ...RequestOption) (*Trigger, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.triggerRequest(ctx, request{
+		method: "GET",
+		path:   fmt.Sprintf("/api/v1/feature-flags/%d/triggers/%d", flagID, triggerID),
+	})
+}
+
+// UpdateTrigger updates a trigger
+func (c *Client) UpdateTrigger(ctx context.Context, flagID, triggerID int, trigger TriggerUpdate, reqOpts ...RequestOption) (*Trigger, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.triggerRequest(ctx, request{
+		method: "PUT",
+		path:   fmt.Sprintf("/api/v1/feature-flags/%d/triggers/%d", flagID, triggerID),
+		body:   trigger,
+	})
+}
+
+// ResetTriggerURL invalidates the URL of a trigger and returns the trigger with a newly signed URL
+func (c *Client) ResetTriggerURL(ctx context.Context, flagID, triggerID int, reqOpts ...RequestOption) (*Trigger, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.triggerRequest(ctx, request{
+		method: "POST",
+		path:   fmt.Sprintf("/api/v1/feature-flags/%d/triggers/%d/reset", flagID, triggerID),
+	})
+}
+
+// DeleteTrigger deletes a trigger
+func (c *Client) DeleteTrigger(ctx context.Context, flagID, triggerID int, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	_, err := c.doRequest(ctx, request{
+		method: "DELETE",
+		path:   fmt.Sprintf("/api/v1/feature-flags/%d/triggers/%d", flagID, triggerID),
+	})
+	return err
+}
+
+// triggerRequest performs a request returning a trigger
+func (c *Client) triggerRequest(ctx context.Context, req request) (*Trigger, error) {
+	respBody, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var trigger Trigger
+	if err := json.Unmarshal(respBody, &trigger); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return &trigger, nil
+}
+
+// FireTrigger requests a trigger URL. It needs no API credentials, the URL itself is signed.
+func FireTrigger(ctx context.Context, httpClient *http.Client, triggerURL string) error {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, triggerURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fire trigger: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("trigger rejected (status %d): %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
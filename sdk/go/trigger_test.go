@@ -0,0 +1,191 @@
+package matrixflag
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// triggerServer serves the triggers of the flags of a fakeServer and their
+// signed URLs, which change the flags when requested
+type triggerServer struct {
+	*httptest.Server
+	fake     *fakeServer
+	mu       sync.Mutex
+	triggers map[int]Trigger
+	// tokens maps the token of each valid trigger URL to its trigger ID
+	tokens map[string]int
+	nextID int
+}
+
+func newTriggerServer(t *testing.T, fake *fakeServer) *triggerServer {
+	s := &triggerServer{fake: fake, triggers: make(map[int]Trigger), tokens: make(map[string]int)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/hooks/"):
+			s.fire(w, strings.TrimPrefix(r.URL.Path, "/hooks/"))
+		case strings.Contains(r.URL.Path, "/triggers"):
+			s.handleTriggers(w, r)
+		default:
+			fake.Config.Handler.ServeHTTP(w, r)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// signURL returns a new signed URL for a trigger; s.mu must be held
+func (s *triggerServer) signURL(id int) string {
+	for token, tid := range s.tokens {
+		if tid == id {
+			delete(s.tokens, token)
+		}
+	}
+	s.nextID++
+	token := fmt.Sprintf("t%d-%d", id, s.nextID)
+	s.tokens[token] = id
+	return s.URL + "/hooks/" + token
+}
+
+func (s *triggerServer) handleTriggers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// /api/v1/feature-flags/{flag}/triggers[/{trigger}[/reset]]
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/feature-flags/"), "/")
+	flagID, _ := strconv.Atoi(parts[0])
+	if len(parts) == 2 {
+		switch r.Method {
+		case http.MethodGet:
+			triggers := []Trigger{}
+			for id := 1; id <= s.nextID; id++ {
+				if trigger, ok := s.triggers[id]; ok && trigger.FlagID == flagID {
+					triggers = append(triggers, trigger)
+				}
+			}
+			writeJSON(w, triggers)
+		case http.MethodPost:
+			var create TriggerCreate
+			if !decodeBody(w, r, &create) {
+				return
+			}
+			s.nextID++
+			trigger := Trigger{ID: s.nextID, FlagID: flagID, Action: create.Action, Description: create.Description, Enabled: true}
+			s.triggers[trigger.ID] = trigger
+			trigger.URL = s.signURL(trigger.ID)
+			writeJSON(w, trigger)
+		}
+		return
+	}
+	id, _ := strconv.Atoi(parts[2])
+	trigger, ok := s.triggers[id]
+	if !ok || trigger.FlagID != flagID {
+		http.Error(w, `{"message":"not found","code":"NOT_FOUND"}`, http.StatusNotFound)
+		return
+	}
+	switch {
+	case len(parts) == 4 && parts[3] == "reset":
+		trigger.URL = s.signURL(id)
+		writeJSON(w, trigger)
+		return
+	case r.Method == http.MethodPut:
+		if !patchJSON(w, r, &trigger) {
+			return
+		}
+		s.triggers[id] = trigger
+	case r.Method == http.MethodDelete:
+		delete(s.triggers, id)
+		s.signURL(id)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, trigger)
+}
+
+// fire applies the action of the trigger of a token to its flag
+func (s *triggerServer) fire(w http.ResponseWriter, token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	trigger, ok := s.triggers[s.tokens[token]]
+	if !ok || !trigger.Enabled {
+		http.Error(w, "unknown trigger", http.StatusNotFound)
+		return
+	}
+	s.fake.mu.Lock()
+	defer s.fake.mu.Unlock()
+	flag := s.fake.flags[trigger.FlagID]
+	switch trigger.Action {
+	case TriggerEnable:
+		flag.IsActive = true
+	case TriggerDisable:
+		flag.IsActive = false
+	case TriggerToggle:
+		flag.IsActive = !flag.IsActive
+	}
+	s.fake.flags[flag.ID] = flag
+	trigger.TriggerCount++
+	s.triggers[trigger.ID] = trigger
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestTriggers(t *testing.T) {
+	fake := newFakeServer(t)
+	flag := fake.addFlag(FeatureFlag{Name: "checkout", Environment: "production", IsActive: true})
+	srv := newTriggerServer(t, fake)
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+	isActive := func() bool {
+		f, err := client.GetFeatureFlag(ctx, flag.ID)
+		require.NoError(t, err)
+		return f.IsActive
+	}
+
+	_, err := client.CreateTrigger(ctx, flag.ID, TriggerCreate{Action: "explode"})
+	assert.EqualError(t, err, `invalid trigger action "explode"`)
+
+	kill, err := client.CreateTrigger(ctx, flag.ID, TriggerCreate{Action: TriggerDisable, Description: "PagerDuty"})
+	require.NoError(t, err)
+	require.NotEmpty(t, kill.URL, "the URL is returned on creation")
+	toggle, err := client.CreateTrigger(ctx, flag.ID, TriggerCreate{Action: TriggerToggle})
+	require.NoError(t, err)
+
+	// Firing needs no credentials
+	require.NoError(t, FireTrigger(ctx, nil, kill.URL))
+	assert.False(t, isActive())
+	require.NoError(t, FireTrigger(ctx, nil, kill.URL))
+	assert.False(t, isActive(), "disabling a disabled flag is a no-op")
+	require.NoError(t, FireTrigger(ctx, nil, toggle.URL))
+	assert.True(t, isActive())
+
+	got, err := client.GetTrigger(ctx, flag.ID, kill.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.TriggerCount)
+	assert.Empty(t, got.URL, "the URL is only returned on creation and reset")
+
+	reset, err := client.ResetTriggerURL(ctx, flag.ID, kill.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, kill.URL, reset.URL)
+	assert.ErrorContains(t, FireTrigger(ctx, nil, kill.URL), "trigger rejected (status 404)", "a reset URL stops working")
+	require.NoError(t, FireTrigger(ctx, nil, reset.URL))
+	assert.False(t, isActive())
+
+	disabled := false
+	updated, err := client.UpdateTrigger(ctx, flag.ID, toggle.ID, TriggerUpdate{Enabled: &disabled})
+	require.NoError(t, err)
+	assert.False(t, updated.Enabled)
+	assert.Error(t, FireTrigger(ctx, nil, toggle.URL), "disabled triggers do not fire")
+
+	require.NoError(t, client.DeleteTrigger(ctx, flag.ID, kill.ID))
+	triggers, err := client.ListTriggers(ctx, flag.ID)
+	require.NoError(t, err)
+	require.Len(t, triggers, 1)
+	assert.Equal(t, toggle.ID, triggers[0].ID)
+	assert.Error(t, FireTrigger(ctx, nil, reset.URL), "deleted triggers do not fire")
+}
@@ -0,0 +1,205 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Variation is one of the values a multivariate flag can serve
+type Variation struct {
+	// Key identifies the variation within its flag
+	Key  string `json:"key"`
+	Name string `json:"name,omitempty"`
+	// Value is the payload served with the variation: a string, number, bool or JSON value
+	Value any `json:"value"`
+	// Weight is the relative share of contexts served the variation
+	Weight float64 `json:"weight"`
+}
+
+// ValidateVariations checks that variations have unique keys and non-negative
+// weights, of which at least one is positive
+func ValidateVariations(variations []Variation) error {
+	if len(variations) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(variations))
+	total := 0.0
+	for i, v := range variations {
+		if v.Key == "" {
+			return fmt.Errorf("invalid variation %d: a key is required", i)
+		}
+		if seen[v.Key] {
+			return fmt.Errorf("invalid variation %d: key %q appears more than once", i, v.Key)
+		}
+		seen[v.Key] = true
+		if v.Weight < 0 {
+			return fmt.Errorf("invalid variation %s: weight %g must not be negative", v.Key, v.Weight)
+		}
+		total += v.Weight
+	}
+	if total <= 0 {
+		return errors.New("invalid variations: at least one weight must be positive")
+	}
+	return nil
+}
+
+// validateFlagVariations checks variations and that the off variation is one of them.
+// The off variation alone cannot be checked, as in an update leaving the variations unchanged.
+func validateFlagVariations(variations []Variation, offVariation string) error {
+	if err := ValidateVariations(variations); err != nil {
+		return err
+	}
+	if offVariation == "" || len(variations) == 0 || hasVariation(variations, offVariation) {
+		return nil
+	}
+	return fmt.Errorf("invalid off variation %q: not a variation of the flag", offVariation)
+}
+
+// hasVariation reports whether variations include the given key
+func hasVariation(variations []Variation, key string) bool {
+	for _, v := range variations {
+		if v.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Variation returns the variation of the flag with the given key
+func (f *FeatureFlag) Variation(key string) (*Variation, bool) {
+	for i := range f.Variations {
+		if f.Variations[i].Key == key {
+			return &f.Variations[i], true
+		}
+	}
+	return nil, false
+}
+
+// VariationFor returns the variation served to key by weight. Keys are bucketed
+// with a salt derived from the flag ID, so a key keeps its variation as long as
+// the weights do not change. It reports false when the flag has no variations.
+func (f *FeatureFlag) VariationFor(key string) (*Variation, bool) {
+	total := 0.0
+	for _, v := range f.Variations {
+		total += v.Weight
+	}
+	if total <= 0 {
+		return nil, false
+	}
+
+	b := Bucket(key, f.variationSalt()) / 100 * total
+	for i := range f.Variations {
+		b -= f.Variations[i].Weight
+		if b < 0 {
+			return &f.Variations[i], true
+		}
+	}
+	// Guard against rounding at the top of the range
+	for i := len(f.Variations) - 1; i >= 0; i-- {
+		if f.Variations[i].Weight > 0 {
+			return &f.Variations[i], true
+		}
+	}
+	return nil, false
+}
+
+// variationSalt returns the salt variations are bucketed with, derived from the flag ID
+func (f *FeatureFlag) variationSalt() string {
+	return "variation." + strconv.Itoa(f.ID)
+}
+
+// variationIndex returns the index of the variation with the given key
+func (f *FeatureFlag) variationIndex(key string) (int, bool) {
+	for i := range f.Variations {
+		if f.Variations[i].Key == key {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// bucketBy returns the context attribute a flag buckets on for its rollout,
+// traffic allocation and variations: the rollout's BucketBy, else the traffic
+// allocation's, else DefaultBucketBy. Resolving it once keeps a tenant bucketed
+// by organization in the same variation throughout.
+func (f *FeatureFlag) bucketBy() string {
+	if f.Rollout != nil && f.Rollout.BucketBy != "" {
+		return f.Rollout.BucketBy
+	}
+	if f.TrafficAllocation != nil && f.TrafficAllocation.BucketBy != "" {
+		return f.TrafficAllocation.BucketBy
+	}
+	return DefaultBucketBy
+}
+
+// validateBucketBy checks that a rollout and a traffic allocation set together bucket on the same attribute
+func validateBucketBy(rollout *PercentageRollout, traffic *TrafficAllocation) error {
+	if rollout == nil || traffic == nil || rollout.BucketBy == "" || traffic.BucketBy == "" {
+		return nil
+	}
+	if rollout.BucketBy != traffic.BucketBy {
+		return fmt.Errorf("invalid bucketing: rollout buckets by %s but traffic allocation by %s", rollout.BucketBy, traffic.BucketBy)
+	}
+	return nil
+}
+
+// GetVariation evaluates a multivariate flag on the server for a context and
+// returns the variation served, with its key and payload
+func (c *Client) GetVariation(ctx context.Context, flagKey string, evalCtx EvaluationContext, reqOpts ...RequestOption) (*Variation, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	d := c.Evaluate(ctx, flagKey, evalCtx, nil)
+	if d.Err != nil {
+		return nil, d.Err
+	}
+	if d.Variation == "" {
+		return nil, fmt.Errorf("flag %s served no variation", flagKey)
+	}
+	return &Variation{Key: d.Variation, Value: d.Value}, nil
+}
+
+// GetJSONVariation evaluates a flag with a JSON payload on the server for a
+// context and unmarshals the value served into target, a pointer to a
+// configuration struct for example, so flags can carry limits, URLs or
+// experiment parameters:
+//
+//	limits := RateLimits{PerMinute: 60}
+//	err := client.GetJSONVariation(ctx, "api-limits", user, &limits)
+//
+// Fields missing from the payload keep the values target held, and target is
+// left as is when the evaluation or the unmarshaling fails, so it can hold the
+// default configuration.
+func (c *Client) GetJSONVariation(ctx context.Context, flagKey string, evalCtx EvaluationContext, target any, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	d := c.Evaluate(ctx, flagKey, evalCtx, nil)
+	if d.Err != nil {
+		return d.Err
+	}
+	return decodeValue(flagKey, d.Value, target)
+}
+
+// decodeValue unmarshals the evaluated value of a flag into target, leaving it
+// as is on failure
+func decodeValue(flagKey string, value, target any) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("invalid target %T: must be a non-nil pointer", target)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value of flag %s: %w", flagKey, err)
+	}
+	// The payload is first unmarshaled into a fresh value, as a copy of target
+	// would share its maps and slices, so a failure leaves target unchanged.
+	// Only then is it merged into target.
+	if err := json.Unmarshal(data, reflect.New(rv.Elem().Type()).Interface()); err != nil {
+		return fmt.Errorf("failed to unmarshal value of flag %s: %w", flagKey, err)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("failed to unmarshal value of flag %s: %w", flagKey, err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package matrixflag
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testLimits struct {
+	PerMinute int      `json:"per_minute"`
+	Burst     int      `json:"burst"`
+	Endpoints []string `json:"endpoints"`
+}
+
+func TestEvaluatorGetJSONVariation(t *testing.T) {
+	evaluator := NewEvaluator(NewClient("http://localhost", "key", nil), EvaluatorOptions{Environment: "production"})
+	evaluator.SetRuleset(&Ruleset{Environment: "production", Flags: []FeatureFlag{
+		{ID: 1, Name: "api-limits", IsActive: true,
+			Variations: []Variation{{Key: "strict", Value: map[string]any{"per_minute": 10, "endpoints": []any{"/search"}}, Weight: 1}}},
+		{ID: 2, Name: "banner", IsActive: true,
+			Variations: []Variation{{Key: "sale", Value: "spring-sale", Weight: 1}}},
+	}})
+	user := EvaluationContext{Key: "user-1"}
+
+	limits := testLimits{PerMinute: 60, Burst: 5}
+	require.NoError(t, evaluator.GetJSONVariation("api-limits", user, &limits))
+	assert.Equal(t, testLimits{PerMinute: 10, Burst: 5, Endpoints: []string{"/search"}}, limits, "fields missing from the payload keep their value")
+
+	limits = testLimits{PerMinute: 60}
+	assert.ErrorContains(t, evaluator.GetJSONVariation("banner", user, &limits), "failed to unmarshal value of flag banner")
+	assert.Equal(t, testLimits{PerMinute: 60}, limits, "failures leave the target as is")
+	assert.ErrorIs(t, evaluator.GetJSONVariation("missing", user, &limits), ErrFlagNotFound)
+	assert.Equal(t, testLimits{PerMinute: 60}, limits)
+
+	var banner string
+	require.NoError(t, evaluator.GetJSONVariation("banner", user, &banner))
+	assert.Equal(t, "spring-sale", banner)
+	assert.ErrorContains(t, evaluator.GetJSONVariation("banner", user, banner), "must be a non-nil pointer")
+}
+
+func TestGetJSONVariationMapTarget(t *testing.T) {
+	evaluator := NewEvaluator(NewClient("http://localhost", "key", nil), EvaluatorOptions{Environment: "production"})
+	evaluator.SetRuleset(&Ruleset{Environment: "production", Flags: []FeatureFlag{
+		{ID: 1, Name: "quotas", IsActive: true,
+			Variations: []Variation{{Key: "pro", Value: map[string]any{"export": 100}, Weight: 1}}},
+		// search decodes before the invalid upload quota is reached
+		{ID: 2, Name: "broken-quotas", IsActive: true,
+			Variations: []Variation{{Key: "pro", Value: map[string]any{"search": 50, "upload": "unlimited"}, Weight: 1}}},
+	}})
+	user := EvaluationContext{Key: "user-1"}
+
+	quotas := map[string]int{"search": 10, "upload": 5}
+	assert.ErrorContains(t, evaluator.GetJSONVariation("broken-quotas", user, &quotas), "failed to unmarshal value of flag broken-quotas")
+	assert.Equal(t, map[string]int{"search": 10, "upload": 5}, quotas, "a payload failing partway leaves the map as is")
+
+	require.NoError(t, evaluator.GetJSONVariation("quotas", user, &quotas))
+	assert.Equal(t, map[string]int{"search": 10, "upload": 5, "export": 100}, quotas, "the payload is merged into the map")
+}
+
+func TestClientGetJSONVariation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/feature-flags/evaluate", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"value": {"per_minute": 120, "burst": 20}, "reason": "SPLIT", "variation": "relaxed"}`))
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil, WithEnvironment("production"))
+
+	var limits testLimits
+	require.NoError(t, client.GetJSONVariation(context.Background(), "api-limits", EvaluationContext{Key: "user-1"}, &limits))
+	assert.Equal(t, testLimits{PerMinute: 120, Burst: 20}, limits)
+}
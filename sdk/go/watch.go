@@ -0,0 +1,221 @@
+package matrixflag
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// DefaultWatchInterval is the default interval between flag polls of a watch
+const DefaultWatchInterval = 30 * time.Second
+
+// FlagChangeType is the kind of a flag change
+type FlagChangeType string
+
+// Flag change types
+const (
+	FlagCreated FlagChangeType = "created"
+	FlagUpdated FlagChangeType = "updated"
+	FlagDeleted FlagChangeType = "deleted"
+)
+
+// FlagChange describes a change to a flag observed by a watch
+type FlagChange struct {
+	Type FlagChangeType
+	// Old is the flag before the change, nil when it was created
+	Old *FeatureFlag
+	// New is the flag after the change, nil when it was deleted
+	New *FeatureFlag
+	// Time is when the change was observed
+	Time time.Time
+}
+
+// Flag returns the flag after the change, or before it when it was deleted
+func (c FlagChange) Flag() FeatureFlag {
+	if c.New != nil {
+		return *c.New
+	}
+	return *c.Old
+}
+
+// Toggled reports whether the change switched the flag's active state
+func (c FlagChange) Toggled() bool {
+	return c.Type == FlagUpdated && c.Old.IsActive != c.New.IsActive
+}
+
+// WatchOptions configures a watch
+type WatchOptions struct {
+	// Environment limits the watch to one environment; flags of all environments are watched when empty
+	Environment string
+	// Keys limits the watch to the flags with the given names; all flags are watched when empty
+	Keys []string
+	// Interval is the time between flag polls, DefaultWatchInterval by default
+	Interval time.Duration
+	// Stream observes changes on the client's flag stream instead of polling,
+	// so they are delivered as soon as the server pushes them. The watch falls
+	// back to polling when the stream cannot be opened.
+	Stream bool
+	// OnError is called when a poll fails or the stream cannot be opened; the
+	// watch keeps running. Errors of an open stream go to the OnError callback
+	// of the client's StreamOptions.
+	OnError func(error)
+}
+
+// Watch observes flags and sends every change on the returned channel until ctx
+// is canceled, when the channel is closed. The current state is fetched before
+// Watch returns, so changes are relative to it; an error fetching it is returned.
+func (c *Client) Watch(ctx context.Context, opts WatchOptions, reqOpts ...RequestOption) (<-chan FlagChange, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultWatchInterval
+	}
+	known, err := c.watchFetch(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Stream {
+		events, err := c.StreamFlags(ctx)
+		if err == nil {
+			return c.watchStream(ctx, opts, known, events), nil
+		}
+		if opts.OnError != nil {
+			opts.OnError(fmt.Errorf("failed to stream flag changes, polling instead: %w", err))
+		}
+	}
+	return c.watchPoll(ctx, opts, known), nil
+}
+
+// watchPoll polls the flags every interval and sends the changes relative to the known flags
+func (c *Client) watchPoll(ctx context.Context, opts WatchOptions, known []FeatureFlag) <-chan FlagChange {
+	changes := make(chan FlagChange)
+	go func() {
+		defer close(changes)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-c.clock.After(opts.Interval):
+			}
+
+			current, err := c.watchFetch(ctx, opts)
+			if err != nil {
+				if ctx.Err() == nil && opts.OnError != nil {
+					opts.OnError(err)
+				}
+				continue
+			}
+			for _, change := range DiffFlags(known, current, c.clock.Now()) {
+				select {
+				case <-ctx.Done():
+					return
+				case changes <- change:
+				}
+			}
+			known = current
+		}
+	}()
+	return changes
+}
+
+// watchStream turns the events of a flag stream into changes relative to the known flags
+func (c *Client) watchStream(ctx context.Context, opts WatchOptions, known []FeatureFlag, events <-chan FlagEvent) <-chan FlagChange {
+	byID := make(map[int]FeatureFlag, len(known))
+	for _, flag := range known {
+		byID[flag.ID] = flag
+	}
+	keys := make(map[string]bool, len(opts.Keys))
+	for _, key := range opts.Keys {
+		keys[key] = true
+	}
+
+	changes := make(chan FlagChange)
+	go func() {
+		defer close(changes)
+		for event := range events {
+			flag := event.Flag
+			if opts.Environment != "" && flag.Environment != "" && flag.Environment != opts.Environment {
+				continue
+			}
+			prev, ok := byID[flag.ID]
+			if len(keys) > 0 && !keys[flag.Name] && !(ok && keys[prev.Name]) {
+				continue
+			}
+
+			change := FlagChange{Time: c.clock.Now()}
+			switch {
+			case event.Type == FlagDeleted && !ok:
+				continue
+			case event.Type == FlagDeleted:
+				delete(byID, flag.ID)
+				change.Type, change.Old = FlagDeleted, &prev
+			case !ok:
+				byID[flag.ID] = flag
+				change.Type, change.New = FlagCreated, &flag
+			case reflect.DeepEqual(prev, flag):
+				continue
+			default:
+				byID[flag.ID] = flag
+				change.Type, change.Old, change.New = FlagUpdated, &prev, &flag
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case changes <- change:
+			}
+		}
+	}()
+	return changes
+}
+
+// watchFetch lists the flags observed by a watch
+func (c *Client) watchFetch(ctx context.Context, opts WatchOptions) ([]FeatureFlag, error) {
+	flags, err := c.ListFeatureFlags(ctx, FlagFilter{Environment: opts.Environment})
+	if err != nil || len(opts.Keys) == 0 {
+		return flags, err
+	}
+
+	keys := make(map[string]bool, len(opts.Keys))
+	for _, key := range opts.Keys {
+		keys[key] = true
+	}
+	watched := flags[:0]
+	for _, flag := range flags {
+		if keys[flag.Name] {
+			watched = append(watched, flag)
+		}
+	}
+	return watched, nil
+}
+
+// DiffFlags returns the changes between two snapshots of flags, matched by ID
+// and ordered by flag ID, observed at time t
+func DiffFlags(old, current []FeatureFlag, t time.Time) []FlagChange {
+	byID := make(map[int]*FeatureFlag, len(old))
+	for i := range old {
+		byID[old[i].ID] = &old[i]
+	}
+
+	var changes []FlagChange
+	for i := range current {
+		flag := &current[i]
+		prev, ok := byID[flag.ID]
+		switch {
+		case !ok:
+			changes = append(changes, FlagChange{Type: FlagCreated, New: flag, Time: t})
+		case !reflect.DeepEqual(*prev, *flag):
+			changes = append(changes, FlagChange{Type: FlagUpdated, Old: prev, New: flag, Time: t})
+		}
+		delete(byID, flag.ID)
+	}
+	for _, prev := range byID {
+		changes = append(changes, FlagChange{Type: FlagDeleted, Old: prev, Time: t})
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Flag().ID < changes[j].Flag().ID
+	})
+	return changes
+}
@@ -0,0 +1,75 @@
+package matrixflag
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nextChange receives a change from a watch, failing the test after a timeout
+func nextChange(t *testing.T, changes <-chan FlagChange) FlagChange {
+	t.Helper()
+	select {
+	case change := <-changes:
+		return change
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change received")
+		return FlagChange{}
+	}
+}
+
+func TestWatchStream(t *testing.T) {
+	srv := newFakeServer(t)
+	flag := srv.addFlag(FeatureFlag{Name: "checkout", Environment: "production", IsActive: false})
+	srv.addFlag(FeatureFlag{Name: "search", Environment: "production"})
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	// A long interval keeps the watch from polling, so changes can only come from the stream
+	client := NewClient(srv.URL, "key", nil)
+	changes, err := client.Watch(ctx, WatchOptions{Keys: []string{"checkout"}, Interval: time.Hour, Stream: true})
+	require.NoError(t, err)
+
+	search := FeatureFlag{ID: 2, Name: "search", Environment: "production", IsActive: true}
+	srv.publish(FlagToggled, search)
+	toggled := flag
+	toggled.IsActive = true
+	srv.publish(FlagToggled, toggled)
+
+	change := nextChange(t, changes)
+	assert.Equal(t, FlagUpdated, change.Type, "changes to other keys are skipped")
+	assert.True(t, change.Toggled())
+	assert.Equal(t, "checkout", change.Flag().Name)
+
+	srv.publish(FlagDeleted, toggled)
+	change = nextChange(t, changes)
+	assert.Equal(t, FlagDeleted, change.Type)
+	assert.True(t, change.Old.IsActive)
+}
+
+func TestWatchStreamFallsBackToPolling(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.noStream = true
+	flag := srv.addFlag(FeatureFlag{Name: "checkout", Environment: "production"})
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	var errs []error
+	client := NewClient(srv.URL, "key", nil)
+	changes, err := client.Watch(ctx, WatchOptions{
+		Interval: 10 * time.Millisecond,
+		Stream:   true,
+		OnError:  func(err error) { errs = append(errs, err) },
+	})
+	require.NoError(t, err)
+	require.Len(t, errs, 1, "the stream error is reported")
+	assert.ErrorContains(t, errs[0], "polling instead")
+
+	flag.IsActive = true
+	srv.publish(FlagUpdated, flag)
+	change := nextChange(t, changes)
+	assert.True(t, change.Toggled())
+}
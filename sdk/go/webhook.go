@@ -0,0 +1,177 @@
+package matrixflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// WebhookEventType is the type of a change delivered to webhooks
+type WebhookEventType string
+
+// Webhook event types
+const (
+	WebhookFlagCreated    WebhookEventType = "flag.created"
+	WebhookFlagUpdated    WebhookEventType = "flag.updated"
+	WebhookFlagToggled    WebhookEventType = "flag.toggled"
+	WebhookFlagDeleted    WebhookEventType = "flag.deleted"
+	WebhookSegmentCreated WebhookEventType = "segment.created"
+	WebhookSegmentUpdated WebhookEventType = "segment.updated"
+	WebhookSegmentDeleted WebhookEventType = "segment.deleted"
+)
+
+// webhookEventTypes are the known webhook event types
+var webhookEventTypes = map[WebhookEventType]bool{
+	WebhookFlagCreated:    true,
+	WebhookFlagUpdated:    true,
+	WebhookFlagToggled:    true,
+	WebhookFlagDeleted:    true,
+	WebhookSegmentCreated: true,
+	WebhookSegmentUpdated: true,
+	WebhookSegmentDeleted: true,
+}
+
+// Webhook delivers changes to a URL. Deliveries of a webhook with a secret
+// are signed with it, see WebhookHandler.
+type Webhook struct {
+	ID  int    `json:"id"`
+	URL string `json:"url"`
+	// Events filters the delivered changes; every change is delivered when it is empty
+	Events   []WebhookEventType `json:"events,omitempty"`
+	IsActive bool               `json:"is_active"`
+	// Signed reports whether the webhook has a secret; the secret itself is never returned
+	Signed bool `json:"signed"`
+}
+
+// WebhookCreate represents the data needed to create a webhook
+type WebhookCreate struct {
+	URL    string             `json:"url"`
+	Events []WebhookEventType `json:"events,omitempty"`
+	// Secret signs the deliveries, which are unsigned when it is empty
+	Secret string `json:"secret,omitempty"`
+}
+
+// WebhookUpdate represents the data needed to update a webhook
+type WebhookUpdate struct {
+	URL      string             `json:"url,omitempty"`
+	Events   []WebhookEventType `json:"events,omitempty"`
+	IsActive bool               `json:"is_active,omitempty"`
+	Secret   string             `json:"secret,omitempty"`
+	// Fields names fields sent even when empty, such as FieldEvents to deliver
+	// every change, FieldIsActive to pause the webhook or FieldSecret to stop
+	// signing deliveries
+	Fields FieldMask `json:"-"`
+}
+
+// ValidateWebhook checks the URL and event types of a webhook
+func ValidateWebhook(rawURL string, events []WebhookEventType) error {
+	if err := validateWebhookURL(rawURL); err != nil {
+		return err
+	}
+	return validateWebhookEvents(events)
+}
+
+// validateWebhookURL checks that a webhook URL is an absolute http or https URL
+func validateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	if (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid webhook URL %q: must be an absolute http or https URL", rawURL)
+	}
+	return nil
+}
+
+// validateWebhookEvents checks that webhook event types are known
+func validateWebhookEvents(events []WebhookEventType) error {
+	for _, event := range events {
+		if !webhookEventTypes[event] {
+			return fmt.Errorf("unknown webhook event type %q", event)
+		}
+	}
+	return nil
+}
+
+// ListWebhooks retrieves all webhooks
+func (c *Client) ListWebhooks(ctx context.Context, reqOpts ...RequestOption) ([]Webhook, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	respBody, err := c.doRequest(ctx, request{
+		method: "GET",
+		path:   "/api/v1/webhooks/",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var webhooks []Webhook
+	if err := json.Unmarshal(respBody, &webhooks); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return webhooks, nil
+}
+
+// GetWebhook retrieves a webhook by ID
+func (c *Client) GetWebhook(ctx context.Context, id int, reqOpts ...RequestOption) (*Webhook, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	return c.webhookRequest(ctx, request{
+		method: "GET",
+		path:   fmt.Sprintf("/api/v1/webhooks/%d", id),
+	})
+}
+
+// CreateWebhook creates a new webhook, active from the start
+func (c *Client) CreateWebhook(ctx context.Context, webhook WebhookCreate, reqOpts ...RequestOption) (*Webhook, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if err := ValidateWebhook(webhook.URL, webhook.Events); err != nil {
+		return nil, err
+	}
+	return c.webhookRequest(ctx, request{
+		method: "POST",
+		path:   "/api/v1/webhooks/",
+		body:   webhook,
+	})
+}
+
+// UpdateWebhook updates a webhook
+func (c *Client) UpdateWebhook(ctx context.Context, id int, webhook WebhookUpdate, reqOpts ...RequestOption) (*Webhook, error) {
+	ctx = withRequestOptions(ctx, reqOpts)
+	if webhook.URL != "" {
+		if err := validateWebhookURL(webhook.URL); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateWebhookEvents(webhook.Events); err != nil {
+		return nil, err
+	}
+	return c.webhookRequest(ctx, request{
+		method: "PUT",
+		path:   fmt.Sprintf("/api/v1/webhooks/%d", id),
+		body:   webhook,
+	})
+}
+
+// DeleteWebhook deletes a webhook
+func (c *Client) DeleteWebhook(ctx context.Context, id int, reqOpts ...RequestOption) error {
+	ctx = withRequestOptions(ctx, reqOpts)
+	_, err := c.doRequest(ctx, request{
+		method: "DELETE",
+		path:   fmt.Sprintf("/api/v1/webhooks/%d", id),
+	})
+	return err
+}
+
+// webhookRequest performs a request returning a webhook
+func (c *Client) webhookRequest(ctx context.Context, req request) (*Webhook, error) {
+	respBody, err := c.doRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var webhook Webhook
+	if err := json.Unmarshal(respBody, &webhook); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return &webhook, nil
+}
@@ -0,0 +1,163 @@
+package matrixflag
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Headers of signed webhook deliveries
+const (
+	// WebhookSignatureHeader holds "v1=" and the hex HMAC-SHA256 of the
+	// timestamp, a dot and the body, keyed with the webhook secret. While a
+	// secret is rotated it holds one signature per secret, separated by commas.
+	WebhookSignatureHeader = "X-MatrixFlag-Signature"
+	// WebhookTimestampHeader holds the Unix time the delivery was signed at
+	WebhookTimestampHeader = "X-MatrixFlag-Timestamp"
+)
+
+// WebhookTolerance is how far the timestamp of a delivery may be from the
+// receiver's clock; older deliveries are rejected as replays
+const WebhookTolerance = 5 * time.Minute
+
+// webhookDedupWindow is how long delivered event IDs are remembered
+const webhookDedupWindow = time.Hour
+
+// maxWebhookBody is the largest delivery body accepted
+const maxWebhookBody = 1 << 20
+
+// WebhookEvent is a change delivered to a webhook. Flag is set for flag events
+// and Segment for segment events, holding the object after the change, or
+// before it when it was deleted; Data holds the raw object for event types
+// this SDK version does not know.
+type WebhookEvent struct {
+	ID   string           `json:"id"`
+	Type WebhookEventType `json:"type"`
+	Time time.Time        `json:"created_at"`
+	// Actor is the user or API key making the change
+	Actor   string          `json:"actor,omitempty"`
+	Data    json.RawMessage `json:"data"`
+	Flag    *FeatureFlag    `json:"-"`
+	Segment *Segment        `json:"-"`
+}
+
+// SignWebhook returns the signature header value of a delivery, as sent by
+// the server, for testing receivers and for custom senders
+func SignWebhook(secret string, timestamp time.Time, body []byte) string {
+	return "v1=" + webhookMAC(secret, strconv.FormatInt(timestamp.Unix(), 10), body)
+}
+
+// webhookMAC returns the hex HMAC-SHA256 of a delivery
+func webhookMAC(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookHandler verifies and decodes webhook deliveries
+type webhookHandler struct {
+	secret string
+	handle func(WebhookEvent)
+	clock  Clock
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// WebhookHandler returns a handler receiving the deliveries of a webhook
+// signed with secret. It rejects deliveries with a missing or wrong
+// signature, or a timestamp more than WebhookTolerance away, with 401, and
+// malformed payloads with 400. Verified events are passed to handle before
+// the delivery is acknowledged; an event ID delivered again within an hour,
+// such as a retried delivery, is acknowledged without calling handle.
+func WebhookHandler(secret string, handle func(event WebhookEvent)) http.Handler {
+	return &webhookHandler{secret: secret, handle: handle, clock: SystemClock, seen: make(map[string]time.Time)}
+}
+
+func (h *webhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	if !h.verify(r.Header, body) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil || event.ID == "" || event.Type == "" {
+		http.Error(w, "invalid webhook payload", http.StatusBadRequest)
+		return
+	}
+	switch {
+	case strings.HasPrefix(string(event.Type), "flag."):
+		event.Flag = &FeatureFlag{}
+		err = json.Unmarshal(event.Data, event.Flag)
+	case strings.HasPrefix(string(event.Type), "segment."):
+		event.Segment = &Segment{}
+		err = json.Unmarshal(event.Data, event.Segment)
+	}
+	if err != nil {
+		http.Error(w, "invalid webhook payload", http.StatusBadRequest)
+		return
+	}
+
+	if h.firstDelivery(event.ID) {
+		h.handle(event)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// verify checks the timestamp and signature headers of a delivery
+func (h *webhookHandler) verify(header http.Header, body []byte) bool {
+	timestamp := header.Get(WebhookTimestampHeader)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	age := h.clock.Now().Sub(time.Unix(unix, 0))
+	if age > WebhookTolerance || age < -WebhookTolerance {
+		return false
+	}
+	expected := []byte(webhookMAC(h.secret, timestamp, body))
+	for _, signature := range strings.Split(header.Get(WebhookSignatureHeader), ",") {
+		mac, ok := strings.CutPrefix(strings.TrimSpace(signature), "v1=")
+		if ok && hmac.Equal([]byte(mac), expected) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstDelivery records an event ID, reporting whether it was not delivered
+// within the dedup window, and forgets the IDs older than it
+func (h *webhookHandler) firstDelivery(id string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := h.clock.Now()
+	for seenID, at := range h.seen {
+		if now.Sub(at) > webhookDedupWindow {
+			delete(h.seen, seenID)
+		}
+	}
+	if _, ok := h.seen[id]; ok {
+		return false
+	}
+	h.seen[id] = now
+	return true
+}
@@ -0,0 +1,67 @@
+package matrixflag
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deliver posts a webhook body signed at the given time with signature
+func deliver(h http.Handler, body string, signedAt time.Time, signature string) int {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(signedAt.Unix(), 10))
+	req.Header.Set(WebhookSignatureHeader, signature)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestWebhookHandler(t *testing.T) {
+	now := time.Now()
+	var events []WebhookEvent
+	h := WebhookHandler("s3cret", func(event WebhookEvent) { events = append(events, event) })
+	h.(*webhookHandler).clock = fixedClock{now}
+
+	body := `{"id":"evt-1","type":"flag.toggled","actor":"jane","created_at":"2024-05-01T12:00:00Z","data":{"id":7,"name":"checkout","environment":"production","is_active":true}}`
+	assert.Equal(t, http.StatusNoContent, deliver(h, body, now, SignWebhook("s3cret", now, []byte(body))))
+	require.Len(t, events, 1)
+	assert.Equal(t, WebhookFlagToggled, events[0].Type)
+	assert.Equal(t, "jane", events[0].Actor)
+	require.NotNil(t, events[0].Flag)
+	assert.True(t, events[0].Flag.IsActive)
+	assert.Nil(t, events[0].Segment)
+
+	assert.Equal(t, http.StatusNoContent, deliver(h, body, now, SignWebhook("s3cret", now, []byte(body))))
+	assert.Len(t, events, 1, "a retried delivery is acknowledged without handling it again")
+
+	segment := `{"id":"evt-2","type":"segment.deleted","data":{"name":"beta"}}`
+	rotating := SignWebhook("old", now, []byte(segment)) + "," + SignWebhook("s3cret", now, []byte(segment))
+	assert.Equal(t, http.StatusNoContent, deliver(h, segment, now, rotating), "one of several signatures matches")
+	require.Len(t, events, 2)
+	assert.Equal(t, "beta", events[1].Segment.Name)
+}
+
+func TestWebhookHandlerRejects(t *testing.T) {
+	now := time.Now()
+	h := WebhookHandler("s3cret", func(event WebhookEvent) { t.Errorf("unexpected event %s", event.ID) })
+	h.(*webhookHandler).clock = fixedClock{now}
+	body := `{"id":"evt-1","type":"flag.created","data":{}}`
+
+	assert.Equal(t, http.StatusUnauthorized, deliver(h, body, now, SignWebhook("other", now, []byte(body))))
+	assert.Equal(t, http.StatusUnauthorized, deliver(h, body, now, ""))
+	assert.Equal(t, http.StatusUnauthorized, deliver(h, body+" ", now, SignWebhook("s3cret", now, []byte(body))), "the body is signed")
+	old := now.Add(-WebhookTolerance - time.Second)
+	assert.Equal(t, http.StatusUnauthorized, deliver(h, body, old, SignWebhook("s3cret", old, []byte(body))), "old deliveries are replays")
+	invalid := `{"type":"flag.created"}`
+	assert.Equal(t, http.StatusBadRequest, deliver(h, invalid, now, SignWebhook("s3cret", now, []byte(invalid))))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhooks", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
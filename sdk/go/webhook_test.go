@@ -0,0 +1,68 @@
+package matrixflag
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhooks(t *testing.T) {
+	var requests, bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		bodies = append(bodies, string(body))
+		switch {
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/api/v1/webhooks/" && r.Method == http.MethodGet:
+			writeJSON(w, []Webhook{
+				{ID: 1, URL: "https://example.com/hook?a=b", IsActive: true},
+				{ID: 2, URL: "https://other.example.com/hook", IsActive: true},
+			})
+		default:
+			writeJSON(w, Webhook{ID: 1, URL: "https://example.com/hook?a=b", IsActive: true, Signed: true})
+		}
+	}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "key", nil)
+	ctx := context.Background()
+
+	webhook, err := client.CreateWebhook(ctx, WebhookCreate{
+		URL:    "https://example.com/hook?a=b",
+		Events: []WebhookEventType{WebhookFlagToggled},
+		Secret: "s3cret",
+	})
+	require.NoError(t, err)
+	assert.True(t, webhook.Signed)
+	_, err = client.GetWebhook(ctx, 1)
+	require.NoError(t, err)
+	_, err = client.UpdateWebhook(ctx, 1, WebhookUpdate{Fields: []string{FieldEvents, FieldIsActive}})
+	require.NoError(t, err)
+	require.NoError(t, client.DeleteWebhook(ctx, 1))
+	assert.JSONEq(t, `{"url":"https://example.com/hook?a=b","events":["flag.toggled"],"secret":"s3cret"}`, bodies[0])
+	assert.JSONEq(t, `{"events":[],"is_active":false}`, bodies[2])
+
+	requests = nil
+	require.NoError(t, client.AddWebhook(ctx, "https://example.com/hook?a=b"))
+	require.NoError(t, client.RemoveWebhook(ctx, "https://example.com/hook?a=b"))
+	assert.Equal(t, []string{
+		"POST /api/v1/webhooks/",
+		"GET /api/v1/webhooks/",
+		"DELETE /api/v1/webhooks/1",
+	}, requests, "the URL is sent in the body, not the path")
+}
+
+func TestValidateWebhook(t *testing.T) {
+	assert.NoError(t, ValidateWebhook("https://example.com/hook", []WebhookEventType{WebhookFlagCreated, WebhookSegmentDeleted}))
+	assert.Error(t, ValidateWebhook("example.com/hook", nil))
+	assert.Error(t, ValidateWebhook("ftp://example.com/hook", nil))
+	assert.ErrorContains(t, ValidateWebhook("https://example.com/hook", []WebhookEventType{"flag.renamed"}), "flag.renamed")
+	_, err := NewClient("http://localhost", "key", nil).UpdateWebhook(context.Background(), 1, WebhookUpdate{URL: "/hook"})
+	assert.Error(t, err)
+}